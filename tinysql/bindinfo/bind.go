@@ -0,0 +1,92 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+// HintsSet contains all the hints of a query.
+type HintsSet struct {
+	// tableHints are the optimizer hints, the slice offset is the traversal
+	// order of the SelectStmts in the ast.
+	tableHints [][]*ast.TableOptimizerHint
+	// indexHints are the index hints, the slice offset is the traversal
+	// order of the TableNames in the ast.
+	indexHints [][]*ast.IndexHint
+}
+
+type hintCollector struct {
+	hintsSet *HintsSet
+}
+
+// Enter implements Visitor interface.
+func (c *hintCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.SelectStmt:
+		c.hintsSet.tableHints = append(c.hintsSet.tableHints, node.TableHints)
+	case *ast.TableName:
+		c.hintsSet.indexHints = append(c.hintsSet.indexHints, node.IndexHints)
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (c *hintCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// CollectHint collects the hints of the statement.
+func CollectHint(stmt ast.StmtNode) *HintsSet {
+	collector := &hintCollector{hintsSet: &HintsSet{}}
+	stmt.Accept(collector)
+	return collector.hintsSet
+}
+
+type hintBinder struct {
+	hintsSet  *HintsSet
+	selectIdx int
+	tableIdx  int
+}
+
+// Enter implements Visitor interface.
+func (b *hintBinder) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.SelectStmt:
+		node.TableHints = b.hintsSet.tableHints[b.selectIdx]
+		b.selectIdx++
+	case *ast.TableName:
+		node.IndexHints = b.hintsSet.indexHints[b.tableIdx]
+		b.tableIdx++
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (b *hintBinder) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// BindHint replaces the hints of the statement with the hints in the
+// HintsSet. The statement must have the same structure as the one the
+// HintsSet is collected from, otherwise it's not changed and false is
+// returned.
+func BindHint(stmt ast.StmtNode, hintsSet *HintsSet) bool {
+	origin := CollectHint(stmt)
+	if len(origin.tableHints) != len(hintsSet.tableHints) || len(origin.indexHints) != len(hintsSet.indexHints) {
+		return false
+	}
+	stmt.Accept(&hintBinder{hintsSet: hintsSet})
+	return true
+}
@@ -0,0 +1,200 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo_test
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testSuite{})
+
+type testSuite struct {
+	store kv.Storage
+	dom   *domain.Domain
+}
+
+func (s *testSuite) SetUpSuite(c *C) {
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	s.store = store
+	bindinfo.Lease = 0
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testSuite) TearDownSuite(c *C) {
+	s.dom.Close()
+	s.store.Close()
+}
+
+// checkBindings checks the bindings except the create and update time.
+func checkBindings(c *C, tk *testkit.TestKit, sql string, expected ...[]interface{}) {
+	rows := tk.MustQuery(sql).Rows()
+	c.Assert(rows, HasLen, len(expected))
+	for i, row := range rows {
+		c.Assert(row[:4], DeepEquals, expected[i])
+	}
+}
+
+func (s *testSuite) TestGlobalBinding(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1(a int, b int, index idx_a(a))")
+
+	tableScan := testkit.Rows(
+		"TableReader_7 3333.33 root data:Selection_6",
+		"└─Selection_6 3333.33 cop gt(test.t1.a, 10)",
+		"  └─TableScan_5 10000.00 cop table:t1, range:[-inf,+inf], keep order:false, stats:pseudo",
+	)
+	indexLookUp := testkit.Rows(
+		"IndexLookUp_7 3333.33 root ",
+		"├─IndexScan_5 3333.33 cop table:t1, index:a, range:(10,+inf], keep order:false, stats:pseudo",
+		"└─TableScan_6 3333.33 cop table:t1, keep order:false, stats:pseudo",
+	)
+	tk.MustQuery("explain select * from t1 where a > 10").Check(tableScan)
+
+	tk.MustExec("create global binding for select * from t1 where a > 1 using select * from t1 use index(idx_a) where a > 1")
+	tk.MustQuery("explain select * from t1 where a > 10").Check(indexLookUp)
+	checkBindings(c, tk, "show global bindings",
+		[]interface{}{"select * from t1 where a > ?", "select * from t1 use index(idx_a) where a > 1", "test", "using"})
+	tk.MustQuery("show session bindings").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from mysql.bind_info where status = 'using'").Check(testkit.Rows("1"))
+
+	// A new handle loads the bindings from the storage.
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+	handle := bindinfo.NewBindHandle(se)
+	c.Assert(handle.Update(true), IsNil)
+	c.Assert(handle.Size(), Equals, 1)
+	record := handle.GetBindRecord("select * from t1 where a > ?", "test")
+	c.Assert(record, NotNil)
+	c.Assert(record.BindSQL, Equals, "select * from t1 use index(idx_a) where a > 1")
+
+	tk.MustExec("drop global binding for select * from t1 where a > 100")
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	tk.MustQuery("explain select * from t1 where a > 10").Check(tableScan)
+	tk.MustQuery("select status from mysql.bind_info").Check(testkit.Rows("deleted"))
+
+	// The deleted binding is removed when the handle updates incrementally.
+	c.Assert(handle.Update(false), IsNil)
+	c.Assert(handle.Size(), Equals, 0)
+}
+
+func (s *testSuite) TestSessionBinding(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t2, t3")
+	tk.MustExec("create table t2(a int, b int, index idx_a(a))")
+	tk.MustExec("create table t3(a int, b int, index idx_a(a))")
+
+	rows := tk.MustQuery("explain select * from t2, t3 where t2.a = t3.a").Rows()
+	c.Assert(rows[0][0], Matches, "HashLeftJoin.*")
+	tk.MustExec("create session binding for select * from t2, t3 where t2.a = t3.a using select /*+ MERGE_JOIN(t2) */ * from t2, t3 where t2.a = t3.a")
+	checkBindings(c, tk, "show session bindings",
+		[]interface{}{"select * from t2 , t3 where t2 . a = t3 . a", "select /*+ MERGE_JOIN(t2) */ * from t2, t3 where t2.a = t3.a", "test", "using"})
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	rows = tk.MustQuery("explain select * from t2, t3 where t2.a = t3.a").Rows()
+	c.Assert(rows[0][0], Matches, "MergeJoin.*")
+
+	// The session binding is invisible to the other sessions.
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustQuery("show session bindings").Check(testkit.Rows())
+
+	// The session binding takes precedence over the global one.
+	tk.MustExec("create global binding for select * from t2 where a > 1 using select * from t2 use index(idx_a) where a > 1")
+	tk.MustExec("create session binding for select * from t2 where a > 1 using select * from t2 ignore index(idx_a) where a > 1")
+	rows = tk.MustQuery("explain select * from t2 where a > 10").Rows()
+	c.Assert(rows[0][0], Matches, "TableReader.*")
+	rows = tk1.MustQuery("explain select * from t2 where a > 10").Rows()
+	c.Assert(rows[0][0], Matches, "IndexLookUp.*")
+
+	tk.MustExec("drop session binding for select * from t2 where a > 1")
+	rows = tk.MustQuery("explain select * from t2 where a > 10").Rows()
+	c.Assert(rows[0][0], Matches, "IndexLookUp.*")
+	tk.MustExec("drop global binding for select * from t2 where a > 1")
+	tk.MustExec("drop session binding for select * from t2, t3 where t2.a = t3.a")
+	tk.MustQuery("show session bindings").Check(testkit.Rows())
+}
+
+func (s *testSuite) TestBindingMismatch(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t4")
+	tk.MustExec("create table t4(a int, b int, index idx_a(a))")
+
+	_, err := tk.Exec("create global binding for select * from t4 where a > 1 using select * from t4 use index(idx_a) where b > 1")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, "hinted sql and original sql don't match.*")
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+}
+
+func (s *testSuite) TestPreparedStmtBinding(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t5")
+	tk.MustExec("create table t5(a int, b int, index idx_a(a))")
+	tk.MustExec("set @@tidb_enable_prepared_plan_cache = 1")
+	tk.MustExec("set @@optimizer_trace = 'enabled=on,one_line=on'")
+	vars := tk.Se.GetSessionVars()
+	finalPlan := func() string {
+		rows := tk.MustQuery("select trace from information_schema.optimizer_trace").Rows()
+		c.Assert(rows, HasLen, 1)
+		return rows[0][0].(string)
+	}
+
+	tk.MustExec("prepare stmt from 'select * from t5 where a > ?'")
+	tk.MustExec("set @a = 10")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	c.Assert(finalPlan(), Matches, `.*"final_plan":"TableReader.*`)
+
+	// The plan cached before the binding is created is not used.
+	tk.MustExec("create global binding for select * from t5 where a > 1 using select * from t5 use index(idx_a) where a > 1")
+	hits := vars.PlanCacheHits
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	c.Assert(vars.PlanCacheHits, Equals, hits)
+	c.Assert(finalPlan(), Matches, `.*"final_plan":"IndexLookUp.*`)
+
+	// The hints of the binding are not left in the prepared statement.
+	tk.MustExec("drop global binding for select * from t5 where a > 1")
+	tk.MustExec("set @@tidb_enable_prepared_plan_cache = 0")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	c.Assert(finalPlan(), Matches, `.*"final_plan":"TableReader.*`)
+}
+
+func (s *testSuite) TestBootstrapWithoutBindInfoTable(c *C) {
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	defer store.Close()
+	dom, err := session.BootstrapSession(store)
+	c.Assert(err, IsNil)
+	defer dom.Close()
+
+	// The stores bootstrapped before the sql bindings are supported don't
+	// have the bind_info table.
+	tk := testkit.NewTestKit(c, store)
+	tk.MustExec("drop table mysql.bind_info")
+	_, err = session.BootstrapSession(store)
+	c.Assert(err, IsNil)
+	tk.MustQuery("select count(*) from mysql.bind_info").Check(testkit.Rows("0"))
+}
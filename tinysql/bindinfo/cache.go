@@ -0,0 +1,127 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"sort"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/types"
+)
+
+const (
+	// Using is the bind info's in use status.
+	Using = "using"
+	// deleted is the bind info's deleted status.
+	deleted = "deleted"
+)
+
+// BindRecord represents a sql bind record.
+type BindRecord struct {
+	// OriginalSQL is the original statement normalized by
+	// parser.NormalizeForBinding.
+	OriginalSQL string
+	// BindSQL is the statement with hints that the original statement is
+	// bound to.
+	BindSQL string
+	Db      string
+	// Status represents the status of the binding. It can only be one of the following values:
+	// 1. deleted: BindRecord is deleted, can not be used anymore.
+	// 2. using: BindRecord is in the normal active mode.
+	Status     string
+	CreateTime types.Time
+	UpdateTime types.Time
+
+	hintsSet *HintsSet
+}
+
+// HintsSet returns the hints of BindSQL.
+func (r *BindRecord) HintsSet() *HintsSet {
+	return r.hintsSet
+}
+
+// collectHints parses BindSQL and collects its hints.
+func (r *BindRecord) collectHints(p *parser.Parser) error {
+	stmt, err := p.ParseOneStmt(r.BindSQL, "", "")
+	if err != nil {
+		return err
+	}
+	r.hintsSet = CollectHint(stmt)
+	return nil
+}
+
+// bindCache caches the bind records, the key is the normalized original sql.
+// The records of a key have different default databases.
+type bindCache map[string][]*BindRecord
+
+func (c bindCache) getBindRecord(normdOrigSQL, db string) *BindRecord {
+	for _, record := range c[normdOrigSQL] {
+		if record.Db == db {
+			return record
+		}
+	}
+	return nil
+}
+
+func (c bindCache) setBindRecord(record *BindRecord) {
+	records := c[record.OriginalSQL]
+	for i, old := range records {
+		if old.Db == record.Db {
+			records[i] = record
+			return
+		}
+	}
+	c[record.OriginalSQL] = append(records, record)
+}
+
+func (c bindCache) removeBindRecord(normdOrigSQL, db string) {
+	records := c[normdOrigSQL]
+	for i, old := range records {
+		if old.Db == db {
+			records = append(records[:i], records[i+1:]...)
+			break
+		}
+	}
+	if len(records) == 0 {
+		delete(c, normdOrigSQL)
+		return
+	}
+	c[normdOrigSQL] = records
+}
+
+// copy returns a copy of the cache, the slices of the records are copied so
+// that changing the copy does not affect the readers of the original cache.
+func (c bindCache) copy() bindCache {
+	newCache := make(bindCache, len(c))
+	for sql, records := range c {
+		newCache[sql] = append([]*BindRecord(nil), records...)
+	}
+	return newCache
+}
+
+// allBindRecords returns all the records in the cache ordered by the
+// original sql and the default database.
+func (c bindCache) allBindRecords() []*BindRecord {
+	records := make([]*BindRecord, 0, len(c))
+	for _, rs := range c {
+		records = append(records, rs...)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].OriginalSQL != records[j].OriginalSQL {
+			return records[i].OriginalSQL < records[j].OriginalSQL
+		}
+		return records[i].Db < records[j].Db
+	})
+	return records
+}
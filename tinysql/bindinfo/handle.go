@@ -0,0 +1,247 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// Lease influences the duration of loading bind info.
+var Lease = 3 * time.Second
+
+// BindHandle is used to handle all global sql bind operations.
+type BindHandle struct {
+	sctx struct {
+		sync.Mutex
+		sessionctx.Context
+	}
+
+	// bindInfo caches the sql bind info from storage.
+	//
+	// The Mutex protects that there is only one goroutine changes the content
+	// of atomic.Value, the readers load the cache without acquiring the lock.
+	bindInfo struct {
+		sync.Mutex
+		atomic.Value
+		parser         *parser.Parser
+		lastUpdateTime types.Time
+	}
+}
+
+// NewBindHandle creates a new BindHandle.
+func NewBindHandle(ctx sessionctx.Context) *BindHandle {
+	handle := &BindHandle{}
+	handle.sctx.Context = ctx
+	handle.bindInfo.Value.Store(make(bindCache))
+	handle.bindInfo.parser = parser.New()
+	handle.bindInfo.lastUpdateTime = types.ZeroTimestamp
+	return handle
+}
+
+// Update updates the global sql bind cache.
+func (h *BindHandle) Update(fullLoad bool) (err error) {
+	h.bindInfo.Lock()
+	lastUpdateTime := h.bindInfo.lastUpdateTime
+	h.bindInfo.Unlock()
+
+	sql := "select original_sql, bind_sql, default_db, status, create_time, update_time from mysql.bind_info"
+	if !fullLoad {
+		sql += " where update_time > \"" + lastUpdateTime.String() + "\""
+	}
+	// We need to apply the updates by order, wrong apply order of same original sql may cause inconsistent state.
+	sql += " order by update_time"
+
+	rows, _, err := h.sctx.Context.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(sql)
+	if err != nil {
+		return err
+	}
+
+	// Make sure there is only one goroutine writes the cache.
+	h.bindInfo.Lock()
+	newCache := h.bindInfo.Value.Load().(bindCache).copy()
+	defer func() {
+		h.bindInfo.lastUpdateTime = lastUpdateTime
+		h.bindInfo.Value.Store(newCache)
+		h.bindInfo.Unlock()
+	}()
+
+	for _, row := range rows {
+		record := newBindRecord(row)
+		if record.UpdateTime.Compare(lastUpdateTime) > 0 {
+			lastUpdateTime = record.UpdateTime
+		}
+		if record.Status != Using {
+			newCache.removeBindRecord(record.OriginalSQL, record.Db)
+			continue
+		}
+		if err := record.collectHints(h.bindInfo.parser); err != nil {
+			logutil.BgLogger().Error("parse bind sql failed", zap.String("bindSQL", record.BindSQL), zap.Error(err))
+			continue
+		}
+		newCache.setBindRecord(record)
+	}
+	return nil
+}
+
+// AddBindRecord adds a BindRecord to the storage and the cache. The old
+// binding of the same original sql and default database is replaced.
+func (h *BindHandle) AddBindRecord(record *BindRecord) (err error) {
+	h.bindInfo.Lock()
+	err = record.collectHints(h.bindInfo.parser)
+	h.bindInfo.Unlock()
+	if err != nil {
+		return err
+	}
+
+	h.sctx.Lock()
+	defer h.sctx.Unlock()
+	exec := h.sctx.Context.(sqlexec.SQLExecutor)
+	ctx := context.TODO()
+	_, err = exec.Execute(ctx, "BEGIN")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+		if err != nil {
+			return
+		}
+		h.bindInfo.Lock()
+		newCache := h.bindInfo.Value.Load().(bindCache).copy()
+		newCache.setBindRecord(record)
+		h.bindInfo.Value.Store(newCache)
+		h.bindInfo.Unlock()
+	}()
+
+	txn, err := h.sctx.Context.Txn(true)
+	if err != nil {
+		return err
+	}
+	now := types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(txn.StartTS())), mysql.TypeTimestamp, 3)
+	record.CreateTime = now
+	record.UpdateTime = now
+	record.Status = Using
+
+	sqls := []string{
+		fmt.Sprintf("DELETE FROM mysql.bind_info WHERE original_sql = %s AND default_db = %s",
+			quote(record.OriginalSQL), quote(record.Db)),
+		fmt.Sprintf("INSERT INTO mysql.bind_info VALUES (%s, %s, %s, %s, %s, %s)",
+			quote(record.OriginalSQL), quote(record.BindSQL), quote(record.Db), quote(record.Status),
+			quote(record.CreateTime.String()), quote(record.UpdateTime.String())),
+	}
+	for _, sql := range sqls {
+		if _, err = exec.Execute(ctx, sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropBindRecord marks the BindRecord of the original sql and default
+// database as deleted in the storage, and removes it from the cache. The
+// record is kept in the storage so that the other servers can notice the
+// change when they update their caches.
+func (h *BindHandle) DropBindRecord(record *BindRecord) (err error) {
+	h.sctx.Lock()
+	defer h.sctx.Unlock()
+	exec := h.sctx.Context.(sqlexec.SQLExecutor)
+	ctx := context.TODO()
+	_, err = exec.Execute(ctx, "BEGIN")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+		if err != nil {
+			return
+		}
+		h.bindInfo.Lock()
+		newCache := h.bindInfo.Value.Load().(bindCache).copy()
+		newCache.removeBindRecord(record.OriginalSQL, record.Db)
+		h.bindInfo.Value.Store(newCache)
+		h.bindInfo.Unlock()
+	}()
+
+	txn, err := h.sctx.Context.Txn(true)
+	if err != nil {
+		return err
+	}
+	now := types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(txn.StartTS())), mysql.TypeTimestamp, 3)
+	sql := fmt.Sprintf("UPDATE mysql.bind_info SET status = %s, update_time = %s WHERE original_sql = %s AND default_db = %s",
+		quote(deleted), quote(now.String()), quote(record.OriginalSQL), quote(record.Db))
+	_, err = exec.Execute(ctx, sql)
+	return err
+}
+
+// GetBindRecord returns the BindRecord of the (normdOrigSQL, db) if it exists.
+func (h *BindHandle) GetBindRecord(normdOrigSQL, db string) *BindRecord {
+	return h.bindInfo.Value.Load().(bindCache).getBindRecord(normdOrigSQL, db)
+}
+
+// GetAllBindRecord returns all the bind records in the cache.
+func (h *BindHandle) GetAllBindRecord() []*BindRecord {
+	return h.bindInfo.Value.Load().(bindCache).allBindRecords()
+}
+
+// Size returns the number of the bind records in the cache.
+func (h *BindHandle) Size() int {
+	size := 0
+	for _, records := range h.bindInfo.Value.Load().(bindCache) {
+		size += len(records)
+	}
+	return size
+}
+
+func newBindRecord(row chunk.Row) *BindRecord {
+	return &BindRecord{
+		OriginalSQL: row.GetString(0),
+		BindSQL:     row.GetString(1),
+		Db:          row.GetString(2),
+		Status:      row.GetString(3),
+		CreateTime:  row.GetTime(4),
+		UpdateTime:  row.GetTime(5),
+	}
+}
+
+func finishTransaction(ctx context.Context, exec sqlexec.SQLExecutor, err error) error {
+	if err == nil {
+		_, err = exec.Execute(ctx, "COMMIT")
+	} else {
+		_, err1 := exec.Execute(ctx, "ROLLBACK")
+		terror.Log(errors.Trace(err1))
+	}
+	return errors.Trace(err)
+}
+
+// quote quotes the string as a sql string literal.
+func quote(s string) string {
+	return "'" + strings.Replace(strings.Replace(s, `\`, `\\`, -1), `'`, `\'`, -1) + "'"
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// SessionHandle is used to handle all session sql bind operations.
+type SessionHandle struct {
+	ch     bindCache
+	parser *parser.Parser
+}
+
+// NewSessionBindHandle creates a new SessionBindHandle.
+func NewSessionBindHandle(parser *parser.Parser) *SessionHandle {
+	return &SessionHandle{
+		ch:     make(bindCache),
+		parser: parser,
+	}
+}
+
+// AddBindRecord adds a BindRecord to the cache.
+func (h *SessionHandle) AddBindRecord(record *BindRecord) error {
+	if err := record.collectHints(h.parser); err != nil {
+		return err
+	}
+	now := types.NewTime(types.FromGoTime(time.Now()), mysql.TypeTimestamp, 3)
+	record.CreateTime = now
+	record.UpdateTime = now
+	record.Status = Using
+	h.ch.setBindRecord(record)
+	return nil
+}
+
+// DropBindRecord drops a BindRecord in the cache.
+func (h *SessionHandle) DropBindRecord(record *BindRecord) {
+	h.ch.removeBindRecord(record.OriginalSQL, record.Db)
+}
+
+// GetBindRecord return the BindRecord of the (normdOrigSQL,db) if BindRecord exist.
+func (h *SessionHandle) GetBindRecord(normdOrigSQL, db string) *BindRecord {
+	return h.ch.getBindRecord(normdOrigSQL, db)
+}
+
+// GetAllBindRecord return all session bind info.
+func (h *SessionHandle) GetAllBindRecord() []*BindRecord {
+	return h.ch.allBindRecords()
+}
+
+// sessionBindInfoKeyType is a dummy type to avoid naming collision in context.
+type sessionBindInfoKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k sessionBindInfoKeyType) String() string {
+	return "session_bindinfo"
+}
+
+// SessionBindInfoKeyType is a variable key for store session bind info.
+const SessionBindInfoKeyType sessionBindInfoKeyType = 0
@@ -50,6 +50,8 @@ type Config struct {
 	Store            string `toml:"store" json:"store"`
 	Path             string `toml:"path" json:"path"`
 	Lease            string `toml:"lease" json:"lease"`
+	TempStoragePath  string `toml:"tmp-storage-path" json:"tmp-storage-path"`
+	CostProfileDir   string `toml:"cost-profile-dir" json:"cost-profile-dir"`
 	Log              Log    `toml:"log" json:"log"`
 	Status           Status `toml:"status" json:"status"`
 }
@@ -92,6 +94,8 @@ var defaultConf = Config{
 	Store:            "mocktikv",
 	Path:             "/tmp/tinysql",
 	Lease:            "45s",
+	TempStoragePath:  "/tmp/tinysql-tmp-storage",
+	CostProfileDir:   "",
 	Log: Log{
 		Level: "info",
 		File:  logutil.NewFileLogConfig(logutil.DefaultLogMaxSize),
@@ -15,6 +15,7 @@ package distsql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
@@ -45,6 +46,21 @@ func Select(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request, fie
 	}, nil
 }
 
+// SelectWithRuntimeStats sends a DAG request, returns SelectResult.
+// The difference from Select is that SelectWithRuntimeStats will set copPlanIDs into selectResult,
+// which can help selectResult to collect runtime stats.
+func SelectWithRuntimeStats(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request,
+	fieldTypes []*types.FieldType, copPlanIDs []fmt.Stringer, rootPlanID fmt.Stringer) (SelectResult, error) {
+	sr, err := Select(ctx, sctx, kvReq, fieldTypes)
+	if err == nil {
+		if selectResult, ok := sr.(*selectResult); ok {
+			selectResult.copPlanIDs = copPlanIDs
+			selectResult.rootPlanID = rootPlanID
+		}
+	}
+	return sr, err
+}
+
 // Analyze do a analyze request.
 func Analyze(ctx context.Context, client kv.Client, kvReq *kv.Request, vars *kv.Variables) (SelectResult, error) {
 	resp := client.Send(ctx, kvReq, vars)
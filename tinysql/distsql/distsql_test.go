@@ -178,3 +178,6 @@ func (r *mockResultSubset) MemSize() int64 { return int64(cap(r.data)) }
 
 // RespTime implements kv.ResultSubset interface.
 func (r *mockResultSubset) RespTime() time.Duration { return 0 }
+
+// CalleeAddress implements kv.ResultSubset interface.
+func (r *mockResultSubset) CalleeAddress() string { return "" }
@@ -15,6 +15,7 @@ package distsql
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -24,7 +25,9 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tipb/go-tipb"
+	"go.uber.org/zap"
 )
 
 var _ SelectResult = (*selectResult)(nil)
@@ -55,6 +58,11 @@ type selectResult struct {
 
 	fetchDuration    time.Duration
 	durationReported bool
+
+	// copPlanIDs contains all copTasks' planIDs,
+	// which help to collect copTasks' runtime stats.
+	copPlanIDs []fmt.Stringer
+	rootPlanID fmt.Stringer
 }
 
 func (r *selectResult) fetchResp(ctx context.Context) error {
@@ -91,6 +99,7 @@ func (r *selectResult) fetchResp(ctx context.Context) error {
 		for _, warning := range r.selectResp.Warnings {
 			sc.AppendWarning(terror.ClassTiKV.New(terror.ErrCode(warning.Code), warning.Msg))
 		}
+		r.updateCopRuntimeStats(resultSubset.CalleeAddress(), resultSubset.RespTime())
 		r.partialCount++
 		if len(r.selectResp.Chunks) != 0 {
 			break
@@ -99,6 +108,27 @@ func (r *selectResult) fetchResp(ctx context.Context) error {
 	return nil
 }
 
+func (r *selectResult) updateCopRuntimeStats(callee string, respTime time.Duration) {
+	runtimeStatsColl := r.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl
+	if runtimeStatsColl == nil || r.rootPlanID == nil {
+		return
+	}
+	runtimeStatsColl.RecordOneReaderStats(r.rootPlanID.String(), respTime)
+	if len(r.selectResp.GetExecutionSummaries()) != len(r.copPlanIDs) {
+		logutil.BgLogger().Error("invalid cop task execution summaries length",
+			zap.Int("expected", len(r.copPlanIDs)),
+			zap.Int("received", len(r.selectResp.GetExecutionSummaries())))
+		return
+	}
+	for i, detail := range r.selectResp.GetExecutionSummaries() {
+		if detail != nil && detail.TimeProcessedNs != nil &&
+			detail.NumProducedRows != nil && detail.NumIterations != nil {
+			planID := r.copPlanIDs[i].String()
+			runtimeStatsColl.RecordOneCopTask(planID, callee, detail)
+		}
+	}
+}
+
 func (r *selectResult) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
 	if r.selectResp == nil || r.respChkIdx == len(r.selectResp.Chunks) {
@@ -24,6 +24,7 @@ import (
 	"github.com/ngaut/pools"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
@@ -50,6 +51,7 @@ type Domain struct {
 	infoHandle      *infoschema.Handle
 	statsHandle     unsafe.Pointer
 	statsLease      time.Duration
+	bindHandle      *bindinfo.BindHandle
 	ddl             ddl.DDL
 	m               sync.Mutex
 	SchemaValidator SchemaValidator
@@ -665,6 +667,43 @@ func (do *Domain) loadStatsWorker() {
 	}
 }
 
+// BindHandle returns domain's bindHandle.
+func (do *Domain) BindHandle() *bindinfo.BindHandle {
+	return do.bindHandle
+}
+
+// LoadBindInfoLoop creates a goroutine loads BindInfo in a loop, it should
+// be called only once in BootstrapSession.
+func (do *Domain) LoadBindInfoLoop(ctx sessionctx.Context) error {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	do.bindHandle = bindinfo.NewBindHandle(ctx)
+	err := do.bindHandle.Update(true)
+	if err != nil || bindinfo.Lease == 0 {
+		return err
+	}
+	do.wg.Add(1)
+	go do.loadBindInfoWorker()
+	return nil
+}
+
+func (do *Domain) loadBindInfoWorker() {
+	defer recoverInDomain("loadBindInfoWorker", false)
+	defer do.wg.Done()
+	loadTicker := time.NewTicker(bindinfo.Lease)
+	defer loadTicker.Stop()
+	for {
+		select {
+		case <-loadTicker.C:
+			err := do.bindHandle.Update(false)
+			if err != nil {
+				logutil.BgLogger().Error("update bindinfo failed", zap.Error(err))
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
+
 func recoverInDomain(funcName string, quit bool) {
 	r := recover()
 	if r == nil {
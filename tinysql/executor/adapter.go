@@ -221,13 +221,22 @@ func (a *ExecStmt) HandleNoDelay(ctx context.Context, e Executor) (bool, sqlexec
 // The first return value stands for if it handle the executor.
 func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor) (bool, sqlexec.RecordSet, error) {
 	toCheck := e
+	isExplainAnalyze := false
+	if explain, ok := e.(*ExplainExec); ok {
+		if analyze := explain.getAnalyzeExecToExecutedNoDelay(); analyze != nil {
+			toCheck = analyze
+			isExplainAnalyze = true
+		}
+	}
 
 	// If the executor doesn't return any result to the client, we execute it without delay.
 	if toCheck.Schema().Len() == 0 {
 		// Hint: step I.4.3
 		// YOUR CODE HERE (lab4)
-		r, err := a.handleNoDelayExecutor(ctx, e)
-		return true, r, err
+		// The DML of EXPLAIN ANALYZE is executed here in the statement, the
+		// explain result is still returned to the client.
+		r, err := a.handleNoDelayExecutor(ctx, toCheck)
+		return !isExplainAnalyze || err != nil, r, err
 	}
 
 	return false, nil, nil
@@ -243,7 +252,7 @@ func (a *ExecStmt) handleNoDelayExecutor(ctx context.Context, e Executor) (sqlex
 	// YOUR CODE HERE (lab4)
 	chk := newFirstChunk(e)
 	for {
-		err = e.Next(ctx, chk)
+		err = Next(ctx, e, chk)
 		if err != nil {
 			return nil, err
 		}
@@ -270,6 +279,16 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 		return nil, errors.Trace(b.err)
 	}
 
+	// ExecuteExec is not a real Executor, we only use it to build another Executor from a prepared statement.
+	if executorExec, ok := e.(*ExecuteExec); ok {
+		err := executorExec.Build(b)
+		if err != nil {
+			return nil, err
+		}
+		a.OutputNames = executorExec.outputNames
+		a.Plan = executorExec.plan
+		e = executorExec.stmtExec
+	}
 	return e, nil
 }
 
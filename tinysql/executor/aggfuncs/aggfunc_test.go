@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	_ "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
@@ -154,12 +155,25 @@ func getDataGenFunc(ft *types.FieldType) func(i int) types.Datum {
 		return func(i int) types.Datum { return types.NewFloat32Datum(float32(i)) }
 	case mysql.TypeDouble:
 		return func(i int) types.Datum { return types.NewFloat64Datum(float64(i)) }
+	case mysql.TypeNewDecimal:
+		return func(i int) types.Datum { return types.NewDecimalDatum(types.NewDecFromInt(int64(i))) }
 	case mysql.TypeString:
 		return func(i int) types.Datum { return types.NewStringDatum(fmt.Sprintf("%d", i)) }
+	case mysql.TypeDate:
+		return func(i int) types.Datum { return types.NewTimeDatum(newDate(i)) }
+	case mysql.TypeDuration:
+		return func(i int) types.Datum { return types.NewDurationDatum(types.Duration{Duration: time.Duration(i)}) }
+	case mysql.TypeJSON:
+		return func(i int) types.Datum { return types.NewDatum(json.CreateBinary(int64(i))) }
 	}
 	return nil
 }
 
+// newDate returns the date which is i days after 2020-01-01.
+func newDate(i int) types.Time {
+	return types.NewTime(types.FromDate(2020, 1, 1+i, 0, 0, 0, 0), mysql.TypeDate, 0)
+}
+
 func (s *testSuite) testAggFunc(c *C, p aggTest) {
 	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{p.dataType}, p.numRows)
 	for i := 0; i < p.numRows; i++ {
@@ -27,6 +27,9 @@ var (
 	_ AggFunc = (*countPartial)(nil)
 	_ AggFunc = (*countOriginal4Int)(nil)
 	_ AggFunc = (*countOriginal4Real)(nil)
+	_ AggFunc = (*countOriginal4Decimal)(nil)
+	_ AggFunc = (*countOriginal4Time)(nil)
+	_ AggFunc = (*countOriginal4Duration)(nil)
 	_ AggFunc = (*countOriginal4String)(nil)
 
 	// All the AggFunc implementations for "FIRSTROW" are listed here.
@@ -34,24 +37,38 @@ var (
 	_ AggFunc = (*firstRow4String)(nil)
 	_ AggFunc = (*firstRow4Float32)(nil)
 	_ AggFunc = (*firstRow4Float64)(nil)
+	_ AggFunc = (*firstRow4Decimal)(nil)
+	_ AggFunc = (*firstRow4Time)(nil)
+	_ AggFunc = (*firstRow4Duration)(nil)
 
 	// All the AggFunc implementations for "MAX"/"MIN" are listed here.
 	_ AggFunc = (*maxMin4Int)(nil)
 	_ AggFunc = (*maxMin4Uint)(nil)
 	_ AggFunc = (*maxMin4Float32)(nil)
 	_ AggFunc = (*maxMin4Float64)(nil)
+	_ AggFunc = (*maxMin4Decimal)(nil)
+	_ AggFunc = (*maxMin4Time)(nil)
+	_ AggFunc = (*maxMin4Duration)(nil)
 	_ AggFunc = (*maxMin4String)(nil)
 
 	// All the AggFunc implementations for "AVG" are listed here.
-	_ AggFunc = (*avgOriginal4Int64)(nil)
-	_ AggFunc = (*avgPartial4Int64)(nil)
+	_ AggFunc = (*avgOriginal4Decimal)(nil)
+	_ AggFunc = (*avgPartial4Decimal)(nil)
 
 	_ AggFunc = (*avgOriginal4Float64)(nil)
 	_ AggFunc = (*avgPartial4Float64)(nil)
 
 	// All the AggFunc implementations for "SUM" are listed here.
-	_ AggFunc = (*sum4Int64)(nil)
+	_ AggFunc = (*sum4Decimal)(nil)
 	_ AggFunc = (*sum4Float64)(nil)
+
+	// All the AggFunc implementations for window functions are listed here.
+	_ AggFunc = (*rowNumber)(nil)
+	_ AggFunc = (*rank)(nil)
+	_ AggFunc = (*firstValue)(nil)
+	_ AggFunc = (*lastValue)(nil)
+	_ AggFunc = (*lead)(nil)
+	_ AggFunc = (*lag)(nil)
 )
 
 // PartialResult represents data structure to store the partial result for the
@@ -14,6 +14,7 @@
 package aggfuncs
 
 import (
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -41,6 +42,28 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 	return nil
 }
 
+// BuildWindowFunctions builds specific window function according to function description and order by columns.
+func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.AggFuncDesc, ordinal int, orderByCols []*expression.Column) AggFunc {
+	switch windowFuncDesc.Name {
+	case ast.WindowFuncRank:
+		return buildRank(ordinal, orderByCols, false)
+	case ast.WindowFuncDenseRank:
+		return buildRank(ordinal, orderByCols, true)
+	case ast.WindowFuncRowNumber:
+		return buildRowNumber(windowFuncDesc, ordinal)
+	case ast.WindowFuncFirstValue:
+		return buildFirstValue(windowFuncDesc, ordinal)
+	case ast.WindowFuncLastValue:
+		return buildLastValue(windowFuncDesc, ordinal)
+	case ast.WindowFuncLead:
+		return buildLead(ctx, windowFuncDesc, ordinal)
+	case ast.WindowFuncLag:
+		return buildLag(ctx, windowFuncDesc, ordinal)
+	default:
+		return Build(ctx, windowFuncDesc, ordinal)
+	}
+}
+
 // buildCount builds the AggFunc implementation for function "COUNT".
 func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
@@ -55,8 +78,16 @@ func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 			return &countOriginal4Int{baseCount{base}}
 		case types.ETReal:
 			return &countOriginal4Real{baseCount{base}}
+		case types.ETDecimal:
+			return &countOriginal4Decimal{baseCount{base}}
+		case types.ETDatetime, types.ETTimestamp:
+			return &countOriginal4Time{baseCount{base}}
+		case types.ETDuration:
+			return &countOriginal4Duration{baseCount{base}}
 		case types.ETString:
 			return &countOriginal4String{baseCount{base}}
+		case types.ETJson:
+			return &countOriginal4JSON{baseCount{base}}
 		}
 	case aggregation.Partial2Mode, aggregation.FinalMode:
 		return &countPartial{baseCount{base}}
@@ -74,8 +105,8 @@ func buildSum(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		},
 	}
 	switch aggFuncDesc.RetTp.EvalType() {
-	case types.ETInt:
-		return &sum4Int64{base}
+	case types.ETDecimal:
+		return &sum4Decimal{base}
 	default:
 		return &sum4Float64{base}
 	}
@@ -92,8 +123,8 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	// partial results.
 	case aggregation.CompleteMode, aggregation.Partial1Mode:
 		switch aggFuncDesc.RetTp.EvalType() {
-		case types.ETInt:
-			return &avgOriginal4Int64{baseAvgInt64{base}}
+		case types.ETDecimal:
+			return &avgOriginal4Decimal{baseAvgDecimal{base}}
 		default:
 			return &avgOriginal4Float64{baseAvgFloat64{base}}
 		}
@@ -102,8 +133,8 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	// functions and update their partial results.
 	case aggregation.Partial2Mode, aggregation.FinalMode:
 		switch aggFuncDesc.RetTp.Tp {
-		case mysql.TypeLonglong:
-			return &avgPartial4Int64{baseAvgInt64{base}}
+		case mysql.TypeNewDecimal:
+			return &avgPartial4Decimal{baseAvgDecimal{base}}
 		case mysql.TypeDouble:
 			return &avgPartial4Float64{baseAvgFloat64{base}}
 		}
@@ -132,8 +163,16 @@ func buildFirstRow(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		case mysql.TypeDouble:
 			return &firstRow4Float64{base}
 		}
+	case types.ETDecimal:
+		return &firstRow4Decimal{base}
+	case types.ETDatetime, types.ETTimestamp:
+		return &firstRow4Time{base}
+	case types.ETDuration:
+		return &firstRow4Duration{base}
 	case types.ETString:
 		return &firstRow4String{base}
+	case types.ETJson:
+		return &firstRow4JSON{base}
 	}
 	return nil
 }
@@ -165,8 +204,77 @@ func buildMaxMin(aggFuncDesc *aggregation.AggFuncDesc, ordinal int, isMax bool)
 		case mysql.TypeDouble:
 			return &maxMin4Float64{base}
 		}
+	case types.ETDecimal:
+		return &maxMin4Decimal{base}
+	case types.ETDatetime, types.ETTimestamp:
+		return &maxMin4Time{base}
+	case types.ETDuration:
+		return &maxMin4Duration{base}
 	case types.ETString:
 		return &maxMin4String{base}
+	case types.ETJson:
+		return &maxMin4JSON{base}
 	}
 	return nil
 }
+
+func buildRowNumber(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &rowNumber{base}
+}
+
+func buildRank(ordinal int, orderByCols []*expression.Column, isDense bool) AggFunc {
+	base := baseAggFunc{
+		ordinal: ordinal,
+	}
+	return &rank{baseAggFunc: base, isDense: isDense, rowComparer: buildRowComparer(orderByCols)}
+}
+
+func buildFirstValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &firstValue{baseAggFunc: base, tp: aggFuncDesc.RetTp}
+}
+
+func buildLastValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &lastValue{baseAggFunc: base, tp: aggFuncDesc.RetTp}
+}
+
+func buildLeadLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) baseLeadLag {
+	offset := uint64(1)
+	if len(aggFuncDesc.Args) >= 2 {
+		offset, _, _ = expression.GetUint64FromConstant(aggFuncDesc.Args[1])
+	}
+	args := make([]expression.Expression, 0, len(aggFuncDesc.Args))
+	args = append(args, aggFuncDesc.Args...)
+	var defaultExpr expression.Expression
+	defaultExpr = expression.Null
+	if len(args) == 3 {
+		// The return type merges the types of the value and the default value,
+		// so both of them are casted to the return type before evaluating.
+		args[0] = expression.BuildCastFunction(ctx, args[0], aggFuncDesc.RetTp)
+		defaultExpr = expression.BuildCastFunction(ctx, args[2], aggFuncDesc.RetTp)
+	}
+	base := baseAggFunc{
+		args:    args,
+		ordinal: ordinal,
+	}
+	return baseLeadLag{baseAggFunc: base, offset: offset, defaultExpr: defaultExpr, valueEvaluator: buildValueEvaluator(aggFuncDesc.RetTp)}
+}
+
+func buildLead(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	return &lead{buildLeadLag(ctx, aggFuncDesc, ordinal)}
+}
+
+func buildLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	return &lag{buildLeadLag(ctx, aggFuncDesc, ordinal)}
+}
@@ -14,54 +14,74 @@
 package aggfuncs
 
 import (
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
 // All the following avg function implementations return the decimal result,
-// which store the partial results in "partialResult4AvgInt64".
+// which store the partial results in "partialResult4AvgDecimal".
 //
 // "baseAvgDecimal" is wrapped by:
-// - "avgOriginal4Int64"
-// - "avgPartial4Int64"
-type baseAvgInt64 struct {
+// - "avgOriginal4Decimal"
+// - "avgPartial4Decimal"
+type baseAvgDecimal struct {
 	baseAggFunc
 }
 
-type partialResult4AvgInt64 struct {
-	sum   int64
+type partialResult4AvgDecimal struct {
+	sum   types.MyDecimal
 	count int64
 }
 
-func (e *baseAvgInt64) AllocPartialResult() PartialResult {
-	return PartialResult(&partialResult4AvgInt64{})
+func (e *baseAvgDecimal) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4AvgDecimal{})
 }
 
-func (e *baseAvgInt64) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4AvgInt64)(pr)
-	p.sum = 0
+func (e *baseAvgDecimal) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4AvgDecimal)(pr)
+	p.sum = *types.NewDecFromInt(0)
 	p.count = int64(0)
 }
 
-func (e *baseAvgInt64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4AvgInt64)(pr)
+func (e *baseAvgDecimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4AvgDecimal)(pr)
 	if p.count == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	chk.AppendInt64(e.ordinal, p.sum/p.count)
+	decimalCount := types.NewDecFromInt(p.count)
+	finalResult := new(types.MyDecimal)
+	err := types.DecimalDiv(&p.sum, decimalCount, finalResult, types.DivFracIncr)
+	if err != nil {
+		return err
+	}
+	// Make the decimal be the result of type inferring.
+	frac := e.args[0].GetType().Decimal
+	if len(e.args) == 2 {
+		frac = e.args[1].GetType().Decimal
+	}
+	if frac == -1 {
+		frac = mysql.MaxDecimalScale
+	}
+	err = finalResult.Round(finalResult, mathutil.Min(frac, mysql.MaxDecimalScale), types.ModeHalfEven)
+	if err != nil {
+		return err
+	}
+	chk.AppendMyDecimal(e.ordinal, finalResult)
 	return nil
 }
 
-type avgOriginal4Int64 struct {
-	baseAvgInt64
+type avgOriginal4Decimal struct {
+	baseAvgDecimal
 }
 
-func (e *avgOriginal4Int64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
-	p := (*partialResult4AvgInt64)(pr)
+func (e *avgOriginal4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgDecimal)(pr)
 	for _, row := range rowsInGroup {
-		input, isNull, err := e.args[0].EvalInt(sctx, row)
+		input, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -69,24 +89,26 @@ func (e *avgOriginal4Int64) UpdatePartialResult(sctx sessionctx.Context, rowsInG
 			continue
 		}
 
-		newSum, err := types.AddInt64(p.sum, input)
+		newSum := new(types.MyDecimal)
+		err = types.DecimalAdd(&p.sum, input, newSum)
 		if err != nil {
 			return err
 		}
-		p.sum = newSum
+		p.sum = *newSum
 		p.count++
 	}
 	return nil
 }
 
-type avgPartial4Int64 struct {
-	baseAvgInt64
+type avgPartial4Decimal struct {
+	baseAvgDecimal
 }
 
-func (e *avgPartial4Int64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
-	p := (*partialResult4AvgInt64)(pr)
+func (e *avgPartial4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgDecimal)(pr)
+	newSum := new(types.MyDecimal)
 	for _, row := range rowsInGroup {
-		inputSum, isNull, err := e.args[1].EvalInt(sctx, row)
+		inputSum, isNull, err := e.args[1].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -102,26 +124,27 @@ func (e *avgPartial4Int64) UpdatePartialResult(sctx sessionctx.Context, rowsInGr
 			continue
 		}
 
-		newSum, err := types.AddInt64(p.sum, inputSum)
+		err = types.DecimalAdd(&p.sum, inputSum, newSum)
 		if err != nil {
 			return err
 		}
-		p.sum = newSum
+		p.sum = *newSum
 		p.count += inputCount
 	}
 	return nil
 }
 
-func (e *avgPartial4Int64) MergePartialResult(sctx sessionctx.Context, src PartialResult, dst PartialResult) error {
-	p1, p2 := (*partialResult4AvgInt64)(src), (*partialResult4AvgInt64)(dst)
+func (e *avgPartial4Decimal) MergePartialResult(sctx sessionctx.Context, src PartialResult, dst PartialResult) error {
+	p1, p2 := (*partialResult4AvgDecimal)(src), (*partialResult4AvgDecimal)(dst)
 	if p1.count == 0 {
 		return nil
 	}
-	newSum, err := types.AddInt64(p1.sum, p2.sum)
+	newSum := new(types.MyDecimal)
+	err := types.DecimalAdd(&p1.sum, &p2.sum, newSum)
 	if err != nil {
 		return err
 	}
-	p2.sum = newSum
+	p2.sum = *newSum
 	p2.count += p1.count
 	return nil
 }
@@ -17,11 +17,12 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
 )
 
 func (s *testSuite) TestMergePartialResult4Avg(c *C) {
 	tests := []aggTest{
-		buildAggTester(ast.AggFuncAvg, mysql.TypeLonglong, 5, 2.0, 3.0, 2),
+		buildAggTester(ast.AggFuncAvg, mysql.TypeNewDecimal, 5, types.NewDecFromFloatForTest(2.0), types.NewDecFromFloatForTest(3.0), types.NewDecFromFloatForTest(2.375)),
 		buildAggTester(ast.AggFuncAvg, mysql.TypeDouble, 5, 2.0, 3.0, 2.375),
 	}
 	for _, test := range tests {
@@ -31,7 +32,7 @@ func (s *testSuite) TestMergePartialResult4Avg(c *C) {
 
 func (s *testSuite) TestAvg(c *C) {
 	tests := []aggTest{
-		buildAggTester(ast.AggFuncAvg, mysql.TypeLonglong, 5, nil, 2.0),
+		buildAggTester(ast.AggFuncAvg, mysql.TypeNewDecimal, 5, nil, types.NewDecFromFloatForTest(2.0)),
 		buildAggTester(ast.AggFuncAvg, mysql.TypeDouble, 5, nil, 2.0),
 	}
 	for _, test := range tests {
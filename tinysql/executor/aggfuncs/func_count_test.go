@@ -30,6 +30,7 @@ func (s *testSuite) TestCount(c *C) {
 		buildAggTester(ast.AggFuncCount, mysql.TypeFloat, 5, 0, 5),
 		buildAggTester(ast.AggFuncCount, mysql.TypeDouble, 5, 0, 5),
 		buildAggTester(ast.AggFuncCount, mysql.TypeString, 5, 0, 5),
+		buildAggTester(ast.AggFuncCount, mysql.TypeJSON, 5, 0, 5),
 	}
 	for _, test := range tests {
 		s.testAggFunc(c, test)
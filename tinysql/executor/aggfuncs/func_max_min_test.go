@@ -14,10 +14,13 @@
 package aggfuncs_test
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 )
 
 func (s *testSuite) TestMergePartialResult4MaxMin(c *C) {
@@ -28,13 +31,21 @@ func (s *testSuite) TestMergePartialResult4MaxMin(c *C) {
 		buildAggTesterWithFieldType(ast.AggFuncMax, unsignedType, 5, 4, 4, 4),
 		buildAggTester(ast.AggFuncMax, mysql.TypeFloat, 5, 4.0, 4.0, 4.0),
 		buildAggTester(ast.AggFuncMax, mysql.TypeDouble, 5, 4.0, 4.0, 4.0),
+		buildAggTester(ast.AggFuncMax, mysql.TypeNewDecimal, 5, types.NewDecFromInt(4), types.NewDecFromInt(4), types.NewDecFromInt(4)),
 		buildAggTester(ast.AggFuncMax, mysql.TypeString, 5, "4", "4", "4"),
+		buildAggTester(ast.AggFuncMax, mysql.TypeDate, 5, newDate(4), newDate(4), newDate(4)),
+		buildAggTester(ast.AggFuncMax, mysql.TypeDuration, 5, types.Duration{Duration: time.Duration(4)}, types.Duration{Duration: time.Duration(4)}, types.Duration{Duration: time.Duration(4)}),
+		buildAggTester(ast.AggFuncMax, mysql.TypeJSON, 5, json.CreateBinary(int64(4)), json.CreateBinary(int64(4)), json.CreateBinary(int64(4))),
 
 		buildAggTester(ast.AggFuncMin, mysql.TypeLonglong, 5, 0, 2, 0),
 		buildAggTesterWithFieldType(ast.AggFuncMin, unsignedType, 5, 0, 2, 0),
 		buildAggTester(ast.AggFuncMin, mysql.TypeFloat, 5, 0.0, 2.0, 0.0),
 		buildAggTester(ast.AggFuncMin, mysql.TypeDouble, 5, 0.0, 2.0, 0.0),
+		buildAggTester(ast.AggFuncMin, mysql.TypeNewDecimal, 5, types.NewDecFromInt(0), types.NewDecFromInt(2), types.NewDecFromInt(0)),
 		buildAggTester(ast.AggFuncMin, mysql.TypeString, 5, "0", "2", "0"),
+		buildAggTester(ast.AggFuncMin, mysql.TypeDate, 5, newDate(0), newDate(2), newDate(0)),
+		buildAggTester(ast.AggFuncMin, mysql.TypeDuration, 5, types.Duration{Duration: time.Duration(0)}, types.Duration{Duration: time.Duration(2)}, types.Duration{Duration: time.Duration(0)}),
+		buildAggTester(ast.AggFuncMin, mysql.TypeJSON, 5, json.CreateBinary(int64(0)), json.CreateBinary(int64(2)), json.CreateBinary(int64(0))),
 	}
 	for _, test := range tests {
 		s.testMergePartialResult(c, test)
@@ -49,13 +60,21 @@ func (s *testSuite) TestMaxMin(c *C) {
 		buildAggTesterWithFieldType(ast.AggFuncMax, unsignedType, 5, nil, 4),
 		buildAggTester(ast.AggFuncMax, mysql.TypeFloat, 5, nil, 4.0),
 		buildAggTester(ast.AggFuncMax, mysql.TypeDouble, 5, nil, 4.0),
+		buildAggTester(ast.AggFuncMax, mysql.TypeNewDecimal, 5, nil, types.NewDecFromInt(4)),
 		buildAggTester(ast.AggFuncMax, mysql.TypeString, 5, nil, "4", "4"),
+		buildAggTester(ast.AggFuncMax, mysql.TypeDate, 5, nil, newDate(4)),
+		buildAggTester(ast.AggFuncMax, mysql.TypeDuration, 5, nil, types.Duration{Duration: time.Duration(4)}),
+		buildAggTester(ast.AggFuncMax, mysql.TypeJSON, 5, nil, json.CreateBinary(int64(4))),
 
 		buildAggTester(ast.AggFuncMin, mysql.TypeLonglong, 5, nil, 0),
 		buildAggTesterWithFieldType(ast.AggFuncMin, unsignedType, 5, nil, 0),
 		buildAggTester(ast.AggFuncMin, mysql.TypeFloat, 5, nil, 0.0),
 		buildAggTester(ast.AggFuncMin, mysql.TypeDouble, 5, nil, 0.0),
+		buildAggTester(ast.AggFuncMin, mysql.TypeNewDecimal, 5, nil, types.NewDecFromInt(0)),
 		buildAggTester(ast.AggFuncMin, mysql.TypeString, 5, nil, "0"),
+		buildAggTester(ast.AggFuncMin, mysql.TypeDate, 5, nil, newDate(0)),
+		buildAggTester(ast.AggFuncMin, mysql.TypeDuration, 5, nil, types.Duration{Duration: time.Duration(0)}),
+		buildAggTester(ast.AggFuncMin, mysql.TypeJSON, 5, nil, json.CreateBinary(int64(0))),
 	}
 	for _, test := range tests {
 		s.testAggFunc(c, test)
@@ -24,8 +24,8 @@ type partialResult4SumFloat64 struct {
 	isNull bool
 }
 
-type partialResult4Int64 struct {
-	val    int64
+type partialResult4SumDecimal struct {
+	val    types.MyDecimal
 	isNull bool
 }
 
@@ -89,35 +89,35 @@ func (e *sum4Float64) MergePartialResult(sctx sessionctx.Context, src, dst Parti
 	return nil
 }
 
-type sum4Int64 struct {
+type sum4Decimal struct {
 	baseSumAggFunc
 }
 
-func (e *sum4Int64) AllocPartialResult() PartialResult {
-	p := new(partialResult4Int64)
+func (e *sum4Decimal) AllocPartialResult() PartialResult {
+	p := new(partialResult4SumDecimal)
 	p.isNull = true
 	return PartialResult(p)
 }
 
-func (e *sum4Int64) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4Int64)(pr)
+func (e *sum4Decimal) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4SumDecimal)(pr)
 	p.isNull = true
 }
 
-func (e *sum4Int64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4Int64)(pr)
+func (e *sum4Decimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4SumDecimal)(pr)
 	if p.isNull {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	chk.AppendInt64(e.ordinal, p.val)
+	chk.AppendMyDecimal(e.ordinal, &p.val)
 	return nil
 }
 
-func (e *sum4Int64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
-	p := (*partialResult4Int64)(pr)
+func (e *sum4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumDecimal)(pr)
 	for _, row := range rowsInGroup {
-		input, isNull, err := e.args[0].EvalInt(sctx, row)
+		input, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -125,30 +125,36 @@ func (e *sum4Int64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []c
 			continue
 		}
 		if p.isNull {
-			p.val = input
+			p.val = *input
 			p.isNull = false
 			continue
 		}
 
-		newSum, err := types.AddInt64(p.val, input)
+		newSum := new(types.MyDecimal)
+		err = types.DecimalAdd(&p.val, input, newSum)
 		if err != nil {
 			return err
 		}
-		p.val = newSum
+		p.val = *newSum
 	}
 	return nil
 }
 
-func (e *sum4Int64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
-	p1, p2 := (*partialResult4Int64)(src), (*partialResult4Int64)(dst)
+func (e *sum4Decimal) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4SumDecimal)(src), (*partialResult4SumDecimal)(dst)
 	if p1.isNull {
 		return nil
 	}
-	newSum, err := types.AddInt64(p1.val, p2.val)
+	if p2.isNull {
+		*p2 = *p1
+		return nil
+	}
+	newSum := new(types.MyDecimal)
+	err := types.DecimalAdd(&p1.val, &p2.val, newSum)
 	if err != nil {
 		return err
 	}
-	p2.val = newSum
+	p2.val = *newSum
 	p2.isNull = false
 	return nil
 }
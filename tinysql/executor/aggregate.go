@@ -16,18 +16,22 @@ package executor
 import (
 	"context"
 	"sync"
+	"unsafe"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/set"
 	"github.com/spaolacci/murmur3"
 	"go.uber.org/zap"
@@ -41,17 +45,30 @@ type baseHashAggWorker struct {
 	finishCh     <-chan struct{}
 	aggFuncs     []aggfuncs.AggFunc
 	maxChunkSize int
+	memTracker   *memory.Tracker
 }
 
-func newBaseHashAggWorker(ctx sessionctx.Context, finishCh <-chan struct{}, aggFuncs []aggfuncs.AggFunc, maxChunkSize int) baseHashAggWorker {
+func newBaseHashAggWorker(ctx sessionctx.Context, finishCh <-chan struct{}, aggFuncs []aggfuncs.AggFunc, maxChunkSize int, memTracker *memory.Tracker) baseHashAggWorker {
 	return baseHashAggWorker{
 		ctx:          ctx,
 		finishCh:     finishCh,
 		aggFuncs:     aggFuncs,
 		maxChunkSize: maxChunkSize,
+		memTracker:   memTracker,
 	}
 }
 
+const (
+	// groupEntryMemUsage is the estimated memory usage of a group in the
+	// partial result map besides the group key, it includes the key string
+	// header, the slice of the partial results and the overhead of the map.
+	groupEntryMemUsage = int64(unsafe.Sizeof("")+unsafe.Sizeof([]aggfuncs.PartialResult{})) * 3 / 2
+	// partialResultMemUsage is the estimated memory usage of a partial result,
+	// the exact size of the partial results of the aggregate functions is not
+	// tracked.
+	partialResultMemUsage = 32
+)
+
 // HashAggPartialWorker indicates the partial workers of parallel hash agg execution,
 // the number of the worker can be set by `tidb_hashagg_partial_concurrency`.
 type HashAggPartialWorker struct {
@@ -64,6 +81,9 @@ type HashAggPartialWorker struct {
 	partialResultsMap aggPartialResultMapper
 	groupByItems      []expression.Expression
 	groupKey          [][]byte
+	// spillTriggered points to HashAggExec.spillTriggered, the partial results
+	// are kept for spillExec instead of being shuffled when it is true.
+	spillTriggered *bool
 	// chk stores the input data from child,
 	// and is reused by childExec and partial worker.
 	chk *chunk.Chunk
@@ -153,6 +173,31 @@ type HashAggExec struct {
 	isChildReturnEmpty bool
 	prepared           bool
 	executed           bool
+
+	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *memory.Tracker // track disk usage.
+
+	// spillTriggered is set by the data fetcher when spillAction fires during
+	// the parallel execution, the parallel workers stop and the aggregation
+	// switches to spillExec.
+	spillTriggered bool
+	// spillMode is true after switching to spillExec. The aggregation is
+	// executed by the main thread instead of the parallel workers in this
+	// mode, see spillExec for details.
+	spillMode      bool
+	spillAction    *spillDiskAction
+	spillPending   []aggSpilledInput
+	spillResults   aggPartialResultMapper
+	spillGroupKeys []string
+	spillCursor    int
+	spillGroupKey  [][]byte
+}
+
+// aggSpilledInput is a partition of the input rows spilled to disk, the rows
+// are aggregated in the depth-th round.
+type aggSpilledInput struct {
+	rows  *chunk.ListInDisk
+	depth int
 }
 
 // HashAggInput indicates the input of hash agg exec.
@@ -185,6 +230,9 @@ func (d *HashAggIntermData) getPartialResultBatch(sc *stmtctx.StatementContext,
 
 // Close implements the Executor Close interface.
 func (e *HashAggExec) Close() error {
+	if e.spillMode {
+		return e.closeSpill()
+	}
 	// `Close` may be called after `Open` without calling `Next` in test.
 	if !e.prepared {
 		close(e.inputCh)
@@ -208,6 +256,16 @@ func (e *HashAggExec) Close() error {
 	for range e.finalOutputCh {
 	}
 	e.executed = false
+	if e.spillAction != nil {
+		e.spillAction.setSpillable(false)
+		e.spillAction = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.Detach()
+	}
+	if e.diskTracker != nil {
+		e.diskTracker.Detach()
+	}
 
 	return e.baseExecutor.Close()
 }
@@ -218,8 +276,14 @@ func (e *HashAggExec) Open(ctx context.Context) error {
 		return err
 	}
 	e.prepared = false
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
 
+	e.spillMode, e.spillTriggered = false, false
 	e.initForParallelExec(e.ctx)
+	if e.ctx.GetSessionVars().OOMAction == variable.OOMActionSpill {
+		e.initSpillAction()
+	}
 	return nil
 }
 
@@ -247,13 +311,14 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	// Init partial workers.
 	for i := 0; i < partialConcurrency; i++ {
 		w := HashAggPartialWorker{
-			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize),
+			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, e.memTracker),
 			inputCh:           e.partialInputChs[i],
 			outputChs:         e.partialOutputChs,
 			giveBackCh:        e.inputCh,
 			globalOutputCh:    e.finalOutputCh,
 			partialResultsMap: make(aggPartialResultMapper, 128),
 			groupByItems:      e.GroupByItems,
+			spillTriggered:    &e.spillTriggered,
 			chk:               newFirstChunk(e.children[0]),
 			groupKey:          make([][]byte, 0, 16),
 		}
@@ -268,7 +333,7 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	// Init final workers.
 	for i := 0; i < finalConcurrency; i++ {
 		e.finalWorkers[i] = HashAggFinalWorker{
-			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize),
+			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, e.memTracker),
 			partialResultMap:    make(aggPartialResultMapper, 128),
 			groupSet:            set.NewStringSet(),
 			inputCh:             e.partialOutputChs[i],
@@ -311,7 +376,7 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 		if r := recover(); r != nil {
 			recoveryHashAgg(w.globalOutputCh, r)
 		}
-		if needShuffle {
+		if needShuffle && !*w.spillTriggered {
 			w.shuffleIntermData(sc, finalConcurrency)
 		}
 		waitGroup.Done()
@@ -414,6 +479,7 @@ func getGroupKey(ctx sessionctx.Context, input *chunk.Chunk, groupKey [][]byte,
 func (w baseHashAggWorker) getPartialResult(sc *stmtctx.StatementContext, groupKey [][]byte, mapper aggPartialResultMapper) [][]aggfuncs.PartialResult {
 	n := len(groupKey)
 	partialResults := make([][]aggfuncs.PartialResult, n)
+	var memDelta int64
 	for i := 0; i < n; i++ {
 		var ok bool
 		if partialResults[i], ok = mapper[string(groupKey[i])]; ok {
@@ -423,7 +489,9 @@ func (w baseHashAggWorker) getPartialResult(sc *stmtctx.StatementContext, groupK
 			partialResults[i] = append(partialResults[i], af.AllocPartialResult())
 		}
 		mapper[string(groupKey[i])] = partialResults[i]
+		memDelta += int64(len(groupKey[i])) + groupEntryMemUsage + partialResultMemUsage*int64(len(w.aggFuncs))
 	}
+	w.memTracker.Consume(memDelta)
 	return partialResults
 }
 
@@ -533,6 +601,9 @@ func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGro
 // Next implements the Executor Next interface.
 func (e *HashAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
+	if e.spillMode {
+		return e.spillExec(ctx, req)
+	}
 	return e.parallelExec(ctx, req)
 }
 
@@ -552,6 +623,12 @@ func (e *HashAggExec) fetchChildData(ctx context.Context) {
 		}
 	}()
 	for {
+		if e.spillAction != nil && e.spillAction.shouldSpill() {
+			// The rest of the input is aggregated by spillExec, see
+			// switchToSpillExec.
+			e.spillTriggered = true
+			return
+		}
 		select {
 		case <-e.finishCh:
 			return
@@ -620,6 +697,15 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 	for {
 		result, ok := <-e.finalOutputCh
 		if !ok {
+			if e.spillTriggered {
+				if err := e.switchToSpillExec(ctx); err != nil {
+					return err
+				}
+				return e.spillExec(ctx, chk)
+			}
+			if e.spillAction != nil {
+				e.spillAction.setSpillable(false)
+			}
 			e.executed = true
 			if e.isChildReturnEmpty && e.defaultVal != nil {
 				chk.Append(e.defaultVal, 0, 1)
@@ -638,3 +724,434 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 		}
 	}
 }
+
+// initSpillAction registers a spillDiskAction when the OOM action is SPILL,
+// the parallel execution switches to spillExec once it fires.
+func (e *HashAggExec) initSpillAction() {
+	memTracker := e.ctx.GetSessionVars().StmtCtx.MemTracker
+	if memTracker == nil {
+		return
+	}
+	e.diskTracker = memory.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.spillAction = &spillDiskAction{spillable: 1}
+	memTracker.FallbackOldAndSetNewAction(e.spillAction)
+}
+
+// switchToSpillExec is called after the parallel workers exit because the
+// memory quota is exceeded. The partial results kept by the partial workers
+// are merged into the groups of the first round of spillExec, and the rest of
+// the input from the child executor is aggregated in that round.
+func (e *HashAggExec) switchToSpillExec(ctx context.Context) error {
+	e.spillMode = true
+	e.spillResults = make(aggPartialResultMapper)
+	e.spillGroupKeys = e.spillGroupKeys[:0]
+	e.spillCursor = 0
+	var memDelta int64
+	for i := range e.partialWorkers {
+		for groupKey, partialResults := range e.partialWorkers[i].partialResultsMap {
+			dst, ok := e.spillResults[groupKey]
+			if !ok {
+				e.spillResults[groupKey] = partialResults
+				e.spillGroupKeys = append(e.spillGroupKeys, groupKey)
+				continue
+			}
+			for j, af := range e.FinalAggFuncs {
+				if err := af.MergePartialResult(e.ctx, partialResults[j], dst[j]); err != nil {
+					return err
+				}
+			}
+			memDelta -= int64(len(groupKey)) + groupEntryMemUsage + partialResultMemUsage*int64(len(e.PartialAggFuncs))
+		}
+		e.partialWorkers[i].partialResultsMap = nil
+	}
+	e.memTracker.Consume(memDelta)
+	if len(e.spillGroupKeys) > 0 {
+		e.isChildReturnEmpty = false
+	}
+	return e.execSpillRound(ctx, nil, 0)
+}
+
+// spillExec executes the aggregation in rounds after the memory quota is
+// exceeded. In every round, the partial results of the groups are kept in
+// memory until the memory quota is exceeded, after that the input rows of the
+// new groups are split into partitions on disk by the hash values of the group
+// keys. The rows of the groups in memory are still aggregated. After the final
+// results of a round are returned, every partition on disk is aggregated in a
+// new round, which may split it again.
+func (e *HashAggExec) spillExec(ctx context.Context, chk *chunk.Chunk) error {
+	for !e.executed && !chk.IsFull() {
+		if e.spillCursor < len(e.spillGroupKeys) {
+			if err := e.appendSpillResult(chk); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(e.spillPending) == 0 {
+			e.executed = true
+			if e.isChildReturnEmpty && e.defaultVal != nil {
+				chk.Append(e.defaultVal, 0, 1)
+			}
+			break
+		}
+		input := e.spillPending[len(e.spillPending)-1]
+		e.spillPending = e.spillPending[:len(e.spillPending)-1]
+		e.resetSpillRound()
+		err := e.execSpillRound(ctx, input.rows, input.depth)
+		if closeErr := input.rows.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetSpillRound releases the groups of the last round before aggregating a
+// partition on disk.
+func (e *HashAggExec) resetSpillRound() {
+	e.spillResults = make(aggPartialResultMapper)
+	e.spillGroupKeys = e.spillGroupKeys[:0]
+	e.spillCursor = 0
+	e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	e.spillAction.reset()
+}
+
+// execSpillRound aggregates the rows from the child executor if input is nil,
+// otherwise it aggregates the rows of a partition on disk.
+func (e *HashAggExec) execSpillRound(ctx context.Context, input *chunk.ListInDisk, depth int) (err error) {
+	// A partition still exceeding the quota after maxSpillDepth rounds of
+	// partitioning is aggregated in memory.
+	e.spillAction.setSpillable(true)
+	defer e.spillAction.setSpillable(false)
+
+	var partitions *partitionsInDisk
+	defer func() {
+		if err != nil && partitions != nil {
+			terror.Log(partitions.close())
+		}
+	}()
+	chk := newFirstChunk(e.children[0])
+	for chkIdx := 0; ; chkIdx++ {
+		if input == nil {
+			err = Next(ctx, e.children[0], chk)
+		} else if chkIdx < input.NumChunks() {
+			chk, err = input.GetChunk(chkIdx)
+		} else {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		e.isChildReturnEmpty = false
+
+		e.spillGroupKey, err = getGroupKey(e.ctx, chk, e.spillGroupKey, e.GroupByItems)
+		if err != nil {
+			return err
+		}
+		spill := e.spillAction.shouldSpill() && depth < maxSpillDepth
+		var memDelta int64
+		rows := make([]chunk.Row, 1)
+		for i := 0; i < chk.NumRows(); i++ {
+			groupKey := e.spillGroupKey[i]
+			partialResults, ok := e.spillResults[string(groupKey)]
+			if !ok && spill {
+				if partitions == nil {
+					partitions = newPartitionsInDisk(retTypes(e.children[0]), e.maxChunkSize, e.diskTracker)
+				}
+				if err = partitions.appendRow(spillPartitionIdx(murmur3.Sum64(groupKey), depth), chk.GetRow(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			if !ok {
+				for _, af := range e.PartialAggFuncs {
+					partialResults = append(partialResults, af.AllocPartialResult())
+				}
+				e.spillResults[string(groupKey)] = partialResults
+				e.spillGroupKeys = append(e.spillGroupKeys, string(groupKey))
+				memDelta += int64(len(groupKey)) + groupEntryMemUsage + partialResultMemUsage*int64(len(e.PartialAggFuncs))
+			}
+			rows[0] = chk.GetRow(i)
+			for j, af := range e.PartialAggFuncs {
+				if err = af.UpdatePartialResult(e.ctx, rows, partialResults[j]); err != nil {
+					return err
+				}
+			}
+		}
+		e.memTracker.Consume(memDelta)
+	}
+	if partitions == nil {
+		return nil
+	}
+	if err = partitions.finish(); err != nil {
+		return err
+	}
+	for i := spillPartitionNum - 1; i >= 0; i-- {
+		if rows := partitions.getPartition(i); rows != nil {
+			e.spillPending = append(e.spillPending, aggSpilledInput{rows: rows, depth: depth + 1})
+		}
+	}
+	return nil
+}
+
+// appendSpillResult merges the partial results of the next group and appends
+// the final results to chk.
+func (e *HashAggExec) appendSpillResult(chk *chunk.Chunk) error {
+	partialResults := e.spillResults[e.spillGroupKeys[e.spillCursor]]
+	e.spillCursor++
+	for j, af := range e.FinalAggFuncs {
+		finalResult := af.AllocPartialResult()
+		if err := af.MergePartialResult(e.ctx, partialResults[j], finalResult); err != nil {
+			return err
+		}
+		if err := af.AppendFinalResult2Chunk(e.ctx, finalResult, chk); err != nil {
+			return err
+		}
+	}
+	if len(e.FinalAggFuncs) == 0 {
+		chk.SetNumVirtualRows(chk.NumRows() + 1)
+	}
+	return nil
+}
+
+func (e *HashAggExec) closeSpill() error {
+	var err error
+	for _, input := range e.spillPending {
+		if err1 := input.rows.Close(); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+	e.spillPending = nil
+	e.spillResults, e.spillGroupKeys = nil, nil
+	if e.spillAction != nil {
+		e.spillAction.setSpillable(false)
+		e.spillAction = nil
+	}
+	e.executed = false
+	e.memTracker.Detach()
+	e.diskTracker.Detach()
+	if err1 := e.baseExecutor.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// StreamAggExec deals with all the aggregate functions.
+// It assumes all the input data is sorted by group by key.
+// When Next() is called, it will return a result for the same group.
+type StreamAggExec struct {
+	baseExecutor
+
+	executed bool
+	// isChildReturnEmpty indicates whether the child executor only returns an empty input.
+	isChildReturnEmpty bool
+	defaultVal         *chunk.Chunk
+	groupChecker       *groupChecker
+	inputIter          *chunk.Iterator4Chunk
+	inputRow           chunk.Row
+	aggFuncs           []aggfuncs.AggFunc
+	partialResults     []aggfuncs.PartialResult
+	groupRows          []chunk.Row
+	childResult        *chunk.Chunk
+}
+
+// Open implements the Executor Open interface.
+func (e *StreamAggExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.executed = false
+	e.isChildReturnEmpty = true
+	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
+	e.inputRow = e.inputIter.End()
+
+	e.partialResults = make([]aggfuncs.PartialResult, 0, len(e.aggFuncs))
+	for _, aggFunc := range e.aggFuncs {
+		e.partialResults = append(e.partialResults, aggFunc.AllocPartialResult())
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *StreamAggExec) Close() error {
+	e.childResult = nil
+	e.groupRows = nil
+	e.groupChecker.reset()
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *StreamAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !e.executed && !req.IsFull() {
+		err := e.consumeOneGroup(ctx, req)
+		if err != nil {
+			e.executed = true
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *StreamAggExec) consumeOneGroup(ctx context.Context, chk *chunk.Chunk) error {
+	for !e.executed {
+		if err := e.fetchChildIfNecessary(ctx, chk); err != nil {
+			return err
+		}
+		for ; e.inputRow != e.inputIter.End(); e.inputRow = e.inputIter.Next() {
+			meetNewGroup, err := e.groupChecker.meetNewGroup(e.inputRow)
+			if err != nil {
+				return err
+			}
+			if meetNewGroup {
+				err := e.consumeGroupRows()
+				if err != nil {
+					return err
+				}
+				err = e.appendResult2Chunk(chk)
+				if err != nil {
+					return err
+				}
+			}
+			e.groupRows = append(e.groupRows, e.inputRow)
+			if meetNewGroup {
+				e.inputRow = e.inputIter.Next()
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// consumeGroupRows updates the partial results with the buffered rows of the
+// current group. It must be called before the child chunk is reused.
+func (e *StreamAggExec) consumeGroupRows() error {
+	if len(e.groupRows) == 0 {
+		return nil
+	}
+
+	for i, aggFunc := range e.aggFuncs {
+		err := aggFunc.UpdatePartialResult(e.ctx, e.groupRows, e.partialResults[i])
+		if err != nil {
+			return err
+		}
+	}
+	e.groupRows = e.groupRows[:0]
+	return nil
+}
+
+func (e *StreamAggExec) fetchChildIfNecessary(ctx context.Context, chk *chunk.Chunk) (err error) {
+	if e.inputRow != e.inputIter.End() {
+		return nil
+	}
+
+	// Before fetching a new batch of input, we should consume the last group.
+	err = e.consumeGroupRows()
+	if err != nil {
+		return err
+	}
+
+	err = Next(ctx, e.children[0], e.childResult)
+	if err != nil {
+		return err
+	}
+
+	// No more data.
+	if e.childResult.NumRows() == 0 {
+		if !e.isChildReturnEmpty {
+			err = e.appendResult2Chunk(chk)
+		} else if e.defaultVal != nil {
+			chk.Append(e.defaultVal, 0, 1)
+		}
+		e.executed = true
+		return err
+	}
+	// Reach here, "e.childResult.NumRows() > 0" is guaranteed.
+	e.isChildReturnEmpty = false
+	e.inputRow = e.inputIter.Begin()
+	return nil
+}
+
+// appendResult2Chunk appends result of all the aggregation functions to the
+// result chunk, and reset the evaluation context for each aggregation.
+func (e *StreamAggExec) appendResult2Chunk(chk *chunk.Chunk) error {
+	for i, aggFunc := range e.aggFuncs {
+		err := aggFunc.AppendFinalResult2Chunk(e.ctx, e.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+		aggFunc.ResetPartialResult(e.partialResults[i])
+	}
+	if len(e.aggFuncs) == 0 {
+		chk.SetNumVirtualRows(chk.NumRows() + 1)
+	}
+	return nil
+}
+
+// groupChecker checks whether a row belongs to a different group from the
+// previous rows, the input rows must be sorted by the group by items.
+type groupChecker struct {
+	StmtCtx      *stmtctx.StatementContext
+	GroupByItems []expression.Expression
+	curGroupKey  []types.Datum
+	tmpGroupKey  []types.Datum
+}
+
+func newGroupChecker(stmtCtx *stmtctx.StatementContext, items []expression.Expression) *groupChecker {
+	return &groupChecker{
+		StmtCtx:      stmtCtx,
+		GroupByItems: items,
+	}
+}
+
+// meetNewGroup returns a value that represents if the new group is different from last group.
+// TODO: Since all the group by items are only a column reference, guaranteed by building projection below aggregation, we can directly compare data in a chunk.
+func (e *groupChecker) meetNewGroup(row chunk.Row) (bool, error) {
+	if len(e.GroupByItems) == 0 {
+		return false, nil
+	}
+	e.tmpGroupKey = e.tmpGroupKey[:0]
+	matched, firstGroup := true, false
+	if len(e.curGroupKey) == 0 {
+		matched, firstGroup = false, true
+	}
+	for i, item := range e.GroupByItems {
+		v, err := item.Eval(row)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			c, err := v.CompareDatum(e.StmtCtx, &e.curGroupKey[i])
+			if err != nil {
+				return false, err
+			}
+			matched = c == 0
+		}
+		e.tmpGroupKey = append(e.tmpGroupKey, v)
+	}
+	if matched {
+		return false, nil
+	}
+	e.curGroupKey = e.curGroupKey[:0]
+	for _, v := range e.tmpGroupKey {
+		// The datum may refer to the memory of the input chunk, which will be
+		// reused by the next fetch, so it must be copied.
+		e.curGroupKey = append(e.curGroupKey, *((&v).Copy()))
+	}
+	return !firstGroup, nil
+}
+
+func (e *groupChecker) reset() {
+	if e.curGroupKey != nil {
+		e.curGroupKey = e.curGroupKey[:0]
+	}
+	if e.tmpGroupKey != nil {
+		e.tmpGroupKey = e.tmpGroupKey[:0]
+	}
+}
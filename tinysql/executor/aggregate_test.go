@@ -14,6 +14,9 @@
 package executor_test
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testutil"
@@ -68,6 +71,47 @@ func (s *testSuiteAgg) TestAggPushDown(c *C) {
 	tk.MustQuery("select count(a) from t where b>0 group by a, b order by a limit 1;").Check(testkit.Rows("3"))
 }
 
+func checkStreamAggAndRun(tk *testkit.TestKit, c *C, sql string) *testkit.Result {
+	result := tk.MustQuery("explain " + sql)
+	resultStr := fmt.Sprintf("%v", result.Rows())
+	if !strings.Contains(resultStr, "StreamAgg") {
+		c.Errorf("Expected StreamAgg in plan of %s, obtained:\n%s", sql, resultStr)
+	}
+	return tk.MustQuery(sql)
+}
+
+func (s *testSuiteAgg) TestStreamAgg(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, a int, b varchar(10), index idx_a(a), index idx_b(b))")
+	// The cost of the parallel hash aggregation is amortized by its concurrency,
+	// make the two aggregations comparable on the ordered input.
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 1")
+	tk.MustExec("set @@tidb_hashagg_final_concurrency = 1")
+	// test for empty table
+	checkStreamAggAndRun(tk, c, "select a, count(*) from t where a > 0 group by a").Check(testkit.Rows())
+	checkStreamAggAndRun(tk, c, "select count(a), sum(a) from t where a > 0").Check(testkit.Rows("0 <nil>"))
+
+	tk.MustExec("insert into t values(1, 1, 'x'), (2, 2, 'y'), (3, 1, 'x'), (4, null, null), (5, 3, 'z'), (6, 3, 'x')")
+	checkStreamAggAndRun(tk, c, "select a, count(*), sum(id) from t use index(idx_a) where a > 0 group by a").Check(testkit.Rows(
+		"1 2 4", "2 1 2", "3 2 11"))
+	checkStreamAggAndRun(tk, c, "select b, count(*) from t use index(idx_b) where b >= 'x' group by b").Check(testkit.Rows(
+		"x 3", "y 1", "z 1"))
+	checkStreamAggAndRun(tk, c, "select count(a), sum(a) from t where a > 0").Check(testkit.Rows("5 10"))
+	checkStreamAggAndRun(tk, c, "select a, count(*) from t where a > 0 group by a order by a desc").Check(testkit.Rows(
+		"3 2", "2 1", "1 2"))
+
+	// test for groups across several chunks
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	for i := 0; i < 300; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d, '%d')", i+10, i/7+10, i%13))
+	}
+	checkStreamAggAndRun(tk, c, "select count(*), sum(a), sum(c) from (select a, count(*) c from t where a >= 10 group by a) tmp").Check(testkit.Rows(
+		"43 1333 300"))
+	checkStreamAggAndRun(tk, c, "select a, count(*) from t where a > 50 group by a").Check(testkit.Rows(
+		"51 7", "52 6"))
+}
+
 func (s *testSuiteAgg) TestAggEliminator(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 
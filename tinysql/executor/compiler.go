@@ -15,8 +15,11 @@ package executor
 
 import (
 	"context"
+	"strings"
 
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -34,6 +37,7 @@ func (c *Compiler) Compile(ctx context.Context, stmtNode ast.StmtNode) (*ExecStm
 	if err := plannercore.Preprocess(c.Ctx, stmtNode, infoSchema); err != nil {
 		return nil, err
 	}
+	addHintForSelect(c.Ctx, stmtNode)
 
 	finalPlan, names, err := planner.Optimize(ctx, c.Ctx, stmtNode, infoSchema)
 	if err != nil {
@@ -48,3 +52,36 @@ func (c *Compiler) Compile(ctx context.Context, stmtNode ast.StmtNode) (*ExecStm
 		OutputNames: names,
 	}, nil
 }
+
+// addHintForSelect replaces the hints of the select statement with the ones
+// of the matching sql binding. The session bindings take precedence over the
+// global ones.
+func addHintForSelect(sctx sessionctx.Context, stmtNode ast.StmtNode) {
+	var selStmt *ast.SelectStmt
+	var normdOrigSQL string
+	switch x := stmtNode.(type) {
+	case *ast.SelectStmt:
+		selStmt = x
+		normdOrigSQL = parser.NormalizeForBinding(x.Text())
+	case *ast.ExplainStmt:
+		sel, ok := x.Stmt.(*ast.SelectStmt)
+		if !ok {
+			return
+		}
+		selStmt = sel
+		normdOrigSQL = parser.NormalizeForBinding(x.Text())
+		idx := strings.Index(normdOrigSQL, "select")
+		if idx < 0 {
+			return
+		}
+		normdOrigSQL = normdOrigSQL[idx:]
+	default:
+		return
+	}
+
+	record := plannercore.GetBindRecord(sctx, normdOrigSQL)
+	if record == nil {
+		return
+	}
+	bindinfo.BindHint(selStmt, record.HintsSet())
+}
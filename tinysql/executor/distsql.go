@@ -15,6 +15,7 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"go.uber.org/zap"
 	"math"
 	"runtime"
@@ -114,6 +115,16 @@ func handleIsExtra(col *expression.Column) bool {
 	return false
 }
 
+// getPhysicalPlanIDs returns the explain IDs of the plans pushed down to the
+// coprocessor, they are used to collect the runtime stats of the cop tasks.
+func getPhysicalPlanIDs(plans []plannercore.PhysicalPlan) []fmt.Stringer {
+	planIDs := make([]fmt.Stringer, 0, len(plans))
+	for _, p := range plans {
+		planIDs = append(planIDs, p.ExplainID())
+	}
+	return planIDs
+}
+
 func splitRanges(ranges []*ranger.Range, keepOrder bool, desc bool) ([]*ranger.Range, []*ranger.Range) {
 	if len(ranges) == 0 || ranges[0].LowVal[0].Kind() == types.KindInt64 {
 		return ranges, nil
@@ -189,6 +200,8 @@ type IndexReaderExecutor struct {
 	idxCols []*expression.Column
 	colLens []int
 	plans   []plannercore.PhysicalPlan
+	// corColInFilter tells whether there's correlated column in filter.
+	corColInFilter bool
 }
 
 // Close clears all resources hold by current object.
@@ -207,6 +220,12 @@ func (e *IndexReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 // Open implements the Executor Open interface.
 func (e *IndexReaderExecutor) Open(ctx context.Context) error {
 	var err error
+	if e.corColInFilter {
+		e.dagPB.Executors, err = constructDistExec(e.ctx, e.plans)
+		if err != nil {
+			return err
+		}
+	}
 	kvRanges, err := distsql.IndexRangesToKVRanges(e.ctx.GetSessionVars().StmtCtx, e.physicalTableID, e.index.ID, e.ranges)
 	if err != nil {
 		return err
@@ -229,7 +248,7 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 	if err != nil {
 		return err
 	}
-	e.result, err = distsql.Select(ctx, e.ctx, kvReq, retTypes(e))
+	e.result, err = distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, retTypes(e), getPhysicalPlanIDs(e.plans), e.id)
 	return err
 }
 
@@ -266,11 +285,26 @@ type IndexLookUpExecutor struct {
 	tblPlans []plannercore.PhysicalPlan
 	idxCols  []*expression.Column
 	colLens  []int
+	// corColInIdxSide and corColInTblSide tell whether there's correlated column in the filters of each side.
+	corColInIdxSide bool
+	corColInTblSide bool
 }
 
 // Open implements the Executor Open interface.
 func (e *IndexLookUpExecutor) Open(ctx context.Context) error {
 	var err error
+	if e.corColInIdxSide {
+		e.dagPB.Executors, err = constructDistExec(e.ctx, e.idxPlans)
+		if err != nil {
+			return err
+		}
+	}
+	if e.corColInTblSide {
+		e.tableRequest.Executors, err = constructDistExec(e.ctx, e.tblPlans)
+		if err != nil {
+			return err
+		}
+	}
 	e.kvRanges, err = distsql.IndexRangesToKVRanges(e.ctx.GetSessionVars().StmtCtx, getPhysicalTableID(e.table), e.index.ID, e.ranges)
 	if err != nil {
 		return err
@@ -311,7 +345,7 @@ func (e *IndexLookUpExecutor) startIndexWorker(ctx context.Context, kvRanges []k
 		return err
 	}
 	tps := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
-	result, err := distsql.Select(ctx, e.ctx, kvReq, tps)
+	result, err := distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, tps, getPhysicalPlanIDs(e.idxPlans), e.id)
 	if err != nil {
 		return err
 	}
@@ -41,6 +41,8 @@ var (
 	ErrWrongObject                 = terror.ClassExecutor.New(mysql.ErrWrongObject, mysql.MySQLErrName[mysql.ErrWrongObject])
 	ErrRoleNotGranted              = terror.ClassPrivilege.New(mysql.ErrRoleNotGranted, mysql.MySQLErrName[mysql.ErrRoleNotGranted])
 	ErrQueryInterrupted            = terror.ClassExecutor.New(mysql.ErrQueryInterrupted, mysql.MySQLErrName[mysql.ErrQueryInterrupted])
+	ErrWarnTooFewRecords           = terror.ClassExecutor.New(mysql.ErrWarnTooFewRecords, mysql.MySQLErrName[mysql.ErrWarnTooFewRecords])
+	ErrWarnTooManyRecords          = terror.ClassExecutor.New(mysql.ErrWarnTooManyRecords, mysql.MySQLErrName[mysql.ErrWarnTooManyRecords])
 )
 
 func init() {
@@ -68,6 +70,8 @@ func init() {
 		mysql.ErrRoleNotGranted:              mysql.ErrRoleNotGranted,
 		mysql.ErrQueryInterrupted:            mysql.ErrQueryInterrupted,
 		mysql.ErrWrongValueCountOnRow:        mysql.ErrWrongValueCountOnRow,
+		mysql.ErrWarnTooFewRecords:           mysql.ErrWarnTooFewRecords,
+		mysql.ErrWarnTooManyRecords:          mysql.ErrWarnTooManyRecords,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExecutor] = tableMySQLErrCodes
 }
@@ -31,12 +31,15 @@ import (
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/mocktikv"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"math"
+	"math/rand"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -469,6 +472,131 @@ func (s *testSuiteP1) TestSelectLimit(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testSuiteP1) TestUnion(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+
+	testSQL := `drop table if exists union_test; create table union_test(id int);`
+	tk.MustExec(testSQL)
+
+	testSQL = `drop table if exists union_test;`
+	tk.MustExec(testSQL)
+	testSQL = `create table union_test(id int);`
+	tk.MustExec(testSQL)
+	testSQL = `insert union_test values (1),(2)`
+	tk.MustExec(testSQL)
+
+	testSQL = `select * from (select id from union_test union select id from union_test) t order by id;`
+	r := tk.MustQuery(testSQL)
+	r.Check(testkit.Rows("1", "2"))
+
+	r = tk.MustQuery("select 1 union all select 1")
+	r.Check(testkit.Rows("1", "1"))
+
+	r = tk.MustQuery("select 1 union all select 1 union select 1")
+	r.Check(testkit.Rows("1"))
+
+	r = tk.MustQuery("select 1 as a union (select 2) order by a limit 1")
+	r.Check(testkit.Rows("1"))
+
+	r = tk.MustQuery("select 1 as a union (select 2) order by a limit 1, 1")
+	r.Check(testkit.Rows("2"))
+
+	r = tk.MustQuery("select id from union_test union all (select 1) order by id desc")
+	r.Check(testkit.Rows("2", "1", "1"))
+
+	r = tk.MustQuery("select id as a from union_test union (select 1) order by a desc")
+	r.Check(testkit.Rows("2", "1"))
+
+	r = tk.MustQuery(`select null as a union (select "abc") order by a`)
+	r.Check(testkit.Rows("<nil>", "abc"))
+
+	r = tk.MustQuery(`select "abc" as a union (select 1) order by a`)
+	r.Check(testkit.Rows("1", "abc"))
+
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (c int, d int)")
+	tk.MustExec("insert t1 values (NULL, 1)")
+	tk.MustExec("insert t1 values (1, 1)")
+	tk.MustExec("insert t1 values (1, 2)")
+	tk.MustExec("drop table if exists t2")
+	tk.MustExec("create table t2 (c int, d int)")
+	tk.MustExec("insert t2 values (1, 3)")
+	tk.MustExec("insert t2 values (1, 1)")
+	tk.MustExec("drop table if exists t3")
+	tk.MustExec("create table t3 (c int, d int)")
+	tk.MustExec("insert t3 values (3, 2)")
+	tk.MustExec("insert t3 values (4, 3)")
+	r = tk.MustQuery(`select sum(c1), c2 from (select c c1, d c2 from t1 union all select d c1, c c2 from t2 union all select c c1, d c2 from t3) x group by c2 order by c2`)
+	r.Check(testkit.Rows("5 1", "4 2", "4 3"))
+
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1 (a int primary key)")
+	tk.MustExec("create table t2 (a int primary key)")
+	tk.MustExec("create table t3 (a int primary key)")
+	tk.MustExec("insert t1 values (7), (8)")
+	tk.MustExec("insert t2 values (1), (9)")
+	tk.MustExec("insert t3 values (2), (3)")
+	r = tk.MustQuery("select * from t1 union all select * from t2 union all (select * from t3) order by a limit 2")
+	r.Check(testkit.Rows("1", "2"))
+
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert t1 values (2), (1)")
+	tk.MustExec("insert t2 values (3), (4)")
+	r = tk.MustQuery("select * from t1 union all (select * from t2) order by a limit 1")
+	r.Check(testkit.Rows("1"))
+	r = tk.MustQuery("select (select * from t1 where a != t.a union all (select * from t2 where a != t.a) order by a limit 1) from t1 t")
+	r.Check(testkit.Rows("1", "2"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int unsigned primary key auto_increment, c1 int, c2 int, index c1_c2 (c1, c2))")
+	tk.MustExec("insert into t values (1, 1, 1)")
+	tk.MustExec("insert into t values (2, 1, 2)")
+	tk.MustExec("insert into t values (3, 2, 3)")
+	r = tk.MustQuery("select * from (select * from t where t.c1 = 1 union select * from t where t.id = 1) s order by s.id")
+	r.Check(testkit.Rows("1 1 1", "2 1 2"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (f1 varchar(20))")
+	tk.MustExec("insert into t values ('ab')")
+	r = tk.MustQuery("select 1 union select f1 from t")
+	r.Sort().Check(testkit.Rows("1", "ab"))
+
+	err := tk.ExecToErr("select 1 from t order by 1 union select 1")
+	c.Assert(err, NotNil)
+	err = tk.ExecToErr("select 1 from t limit 1 union select 1")
+	c.Assert(err, NotNil)
+	tk.MustQuery("(select 1 from t limit 1) union (select 2)").Sort().Check(testkit.Rows("1", "2"))
+	err = tk.ExecToErr("select 1, 2 union select 1")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuiteP1) TestExceptAndIntersect(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("create table t3 (a int, b int)")
+	tk.MustExec("insert t1 values (1, 1), (1, 1), (2, 2), (3, 3), (null, null)")
+	tk.MustExec("insert t2 values (1, 1), (3, null), (null, null)")
+	tk.MustExec("insert t3 values (2, 2), (4, 4)")
+
+	tk.MustQuery("select * from t1 except select * from t2").Sort().Check(testkit.Rows("2 2", "3 3"))
+	tk.MustQuery("select * from t1 intersect select * from t2").Sort().Check(testkit.Rows("1 1", "<nil> <nil>"))
+	tk.MustQuery("select * from t1 intersect select * from t3").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select * from t1 except select * from t2 except select * from t3").Check(testkit.Rows("3 3"))
+	// INTERSECT has higher precedence than UNION and EXCEPT.
+	tk.MustQuery("select * from t3 union select * from t1 intersect select * from t2").Sort().Check(testkit.Rows("1 1", "2 2", "4 4", "<nil> <nil>"))
+	tk.MustQuery("select * from t1 except select * from t2 union select * from t3").Sort().Check(testkit.Rows("2 2", "3 3", "4 4"))
+	tk.MustQuery("select a from t1 except select a from t2 order by a").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t1 intersect select b from t2").Sort().Check(testkit.Rows("1", "<nil>"))
+	err := tk.ExecToErr("select a, b from t1 except select a from t2")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuiteP1) TestSelectOrderBy(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -777,6 +905,89 @@ func (s *testSuite8) TestIndexScan(c *C) {
 	result.Check(testkit.Rows())
 }
 
+func (s *testSuiteP1) TestDecimal(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b decimal(10, 2), index idx(b))")
+	tk.MustExec("insert into t values (1, 1.25), (1, 2.5), (2, 3.75), (2, null), (3, -0.1)")
+	tk.MustQuery("select sum(b), avg(b), max(b), min(b), count(b) from t").Check(testkit.Rows("7.40 1.850000 3.75 -0.10 4"))
+	tk.MustQuery("select a, sum(b), avg(b) from t group by a order by a").Check(testkit.Rows("1 3.75 1.875000", "2 3.75 3.750000", "3 -0.10 -0.100000"))
+	tk.MustQuery("select sum(a), avg(a) from t").Check(testkit.Rows("9 1.8000"))
+	tk.MustQuery("select b from t where b > 1.3 order by b").Check(testkit.Rows("2.50", "3.75"))
+	tk.MustQuery("select b * 2, b + 0.001, b - a from t where b is not null order by b").Check(testkit.Rows("-0.20 -0.099 -3.10", "2.50 1.251 0.25", "5.00 2.501 1.50", "7.50 3.751 1.75"))
+}
+
+func (s *testSuiteP1) TestTemporalTypes(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, d date, dt datetime(3), ts timestamp, tm time(2), index idx_dt(dt), index idx_ts(ts))")
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustExec("insert into t values (1, '2020-01-01', '2020-01-01 10:00:00.1234', '2020-01-01 10:00:00', '-12:30:00.456')")
+	tk.MustExec("insert into t values (2, '2020-02-29', '2020-03-01 00:00:00', '2020-03-01 00:00:00', '838:59:59')")
+	tk.MustExec("insert into t values (3, null, null, null, null)")
+	tk.MustQuery("select d, dt, ts, tm from t order by id").Check(testkit.Rows(
+		"2020-01-01 2020-01-01 10:00:00.123 2020-01-01 10:00:00 -12:30:00.46",
+		"2020-02-29 2020-03-01 00:00:00.000 2020-03-01 00:00:00 838:59:59.00",
+		"<nil> <nil> <nil> <nil>"))
+
+	// TIMESTAMP values are stored in UTC and converted to the session time zone.
+	tk.MustExec("set @@time_zone = '+08:00'")
+	tk.MustQuery("select ts, dt from t where id = 1").Check(testkit.Rows("2020-01-01 18:00:00 2020-01-01 10:00:00.123"))
+	tk.MustQuery("select id from t where ts = '2020-03-01 08:00:00'").Check(testkit.Rows("2"))
+	tk.MustQuery("select id from t use index(idx_ts) where ts > '2020-01-01 18:00:00'").Check(testkit.Rows("2"))
+	tk.MustQuery("select id from t use index(idx_dt) where dt >= '2020-01-01 10:00:00.123' and dt < '2020-03-01' order by id").Check(testkit.Rows("1"))
+	tk.MustExec("set @@time_zone = 'UTC'")
+	tk.MustQuery("select ts from t where id = 1").Check(testkit.Rows("2020-01-01 10:00:00"))
+
+	tk.MustQuery("select date_add(d, interval 1 month), date_sub(dt, interval 1 day), date_add(d, interval '1:30' hour_minute) from t order by id").Check(testkit.Rows(
+		"2020-02-01 2019-12-31 10:00:00.123 2020-01-01 01:30:00",
+		"2020-03-29 2020-02-29 00:00:00.000 2020-02-29 01:30:00",
+		"<nil> <nil> <nil>"))
+	tk.MustQuery("select date_format(dt, '%Y/%m/%d %H:%i:%s.%f'), extract(year_month from d), extract(hour from tm) from t where id = 1").Check(testkit.Rows("2020/01/01 10:00:00.123000 202001 -12"))
+	tk.MustQuery("select unix_timestamp(ts), year(d), month(d), dayofmonth(d), datediff(dt, d) from t where id = 2").Check(testkit.Rows("1583020800 2020 2 29 1"))
+	tk.MustQuery("select max(dt), min(ts), max(tm), count(d) from t").Check(testkit.Rows("2020-03-01 00:00:00.000 2020-01-01 10:00:00 838:59:59.00 2"))
+	tk.MustQuery("select unix_timestamp(now()) - unix_timestamp(now()) < 1, now() = current_timestamp()").Check(testkit.Rows("1 1"))
+}
+
+func (s *testSuiteP1) TestJSON(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, j json)")
+	tk.MustExec(`insert into t values (1, '{"a": 1, "b": {"c": "x"}, "d": [1, 2]}'), (2, '[1, "2", null]'), (3, '"str"'), (4, null)`)
+	tk.MustQuery("select id, j from t order by id").Check(testkit.Rows(
+		`1 {"a": 1, "b": {"c": "x"}, "d": [1, 2]}`, `2 [1, "2", null]`, `3 "str"`, "4 <nil>"))
+	_, err := tk.Exec(`insert into t values (5, '{"a": 1')`)
+	c.Assert(err, NotNil)
+
+	tk.MustQuery(`select j->'$.b.c', j->>'$.b.c', json_extract(j, '$.d[1]', '$.a') from t where id = 1`).Check(testkit.Rows(`"x" x [2, 1]`))
+	tk.MustQuery(`select id, j->'$[1]' from t where j->'$[0]' = 1 order by id`).Check(testkit.Rows(`2 "2"`))
+	tk.MustQuery(`select id from t where j->'$.a' = 1`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select id from t where j->>'$.b.c' = 'x'`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select json_unquote(j) from t where id = 3`).Check(testkit.Rows("str"))
+	tk.MustQuery(`select id from t where json_contains(j, '1') order by id`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select id from t where json_contains(j, '2', '$.d')`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select json_set(j, '$.a', 2, '$.e', 'y') from t where id = 1`).Check(testkit.Rows(`{"a": 2, "b": {"c": "x"}, "d": [1, 2], "e": "y"}`))
+	tk.MustQuery(`select json_object('id', id, 'j', j) from t where id = 3`).Check(testkit.Rows(`{"id": 3, "j": "str"}`))
+	tk.MustQuery(`select json_array(id, j->'$.a', null) from t where id = 1`).Check(testkit.Rows(`[1, 1, null]`))
+	tk.MustQuery(`select count(j), max(j->'$.a') from t`).Check(testkit.Rows("3 1"))
+
+	// A string compared with JSON is taken as a JSON string, and JSON values of
+	// different types are compared by the precedence of the types.
+	tk.MustQuery(`select id from t where j > 'abc' order by id`).Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery(`select id from t where j = 'str'`).Check(testkit.Rows("3"))
+	tk.MustQuery(`select id from t where j = '"str"'`).Check(testkit.Rows())
+	tk.MustQuery(`select id from t where j->'$.a' in (1, 2)`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select id from t where j is null`).Check(testkit.Rows("4"))
+
+	tk.MustExec(`update t set j = json_set(j, '$.a', 3) where id = 1`)
+	tk.MustQuery(`select j->'$.a' from t where id = 1`).Check(testkit.Rows("3"))
+	_, err = tk.Exec("create table t1 (j json, index idx(j))")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuiteP1) TestIndexReverseOrder(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -1247,3 +1458,136 @@ func (s *testSuite8) TearDownTest(c *C) {
 		tk.MustExec(fmt.Sprintf("drop table %v", tableName))
 	}
 }
+
+func (s *testSuite5) TestOOMAction(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	queries := []string{
+		"select * from t order by b",
+		"select a, count(*) from t group by a",
+		"select /*+ HASH_JOIN(t, t1) */ t.a from t join t1 on t.a = t1.a",
+	}
+
+	// The queries only log a warning when the action is LOG.
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	tk.MustExec("set @@tidb_mem_oom_action = 'log'")
+	tk.MustQuery("select @@tidb_mem_oom_action").Check(testkit.Rows("LOG"))
+	for _, sql := range queries {
+		c.Assert(tk.MustQuery(sql).Rows(), HasLen, 3)
+	}
+
+	// The queries are canceled when the action is CANCEL.
+	tk.MustExec("set @@tidb_mem_oom_action = 'cancel'")
+	for _, sql := range queries {
+		err := tk.QueryToErr(sql)
+		c.Assert(err, NotNil, Commentf("sql: %s", sql))
+		c.Assert(strings.Contains(err.Error(), memory.PanicMemoryExceed), IsTrue, Commentf("sql: %s, err: %v", sql, err))
+	}
+
+	// The MEMORY_QUOTA hint overrides the session quota.
+	tk.MustQuery("select /*+ MEMORY_QUOTA(1 MB) */ * from t order by b").Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+	for _, sql := range queries {
+		c.Assert(tk.MustQuery(sql).Rows(), HasLen, 3)
+	}
+	c.Assert(tk.Se.GetSessionVars().MemTracker.BytesConsumed(), Equals, int64(0))
+
+	_, err := tk.Exec("set @@tidb_mem_oom_action = 'unknown'")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("set @@tidb_mem_quota_query = 'abc'")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite5) TestSortSpillDisk(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(20), c double)")
+	rows := 1000
+	values := make([]string, 0, rows)
+	for i := 0; i < rows; i++ {
+		if i%17 == 0 {
+			values = append(values, fmt.Sprintf("(null, 'v%d', %d)", i, i))
+			continue
+		}
+		values = append(values, fmt.Sprintf("(%d, 'v%d', %d.5)", rand.Intn(200), rand.Intn(rows), i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ", "))
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	queries := []string{
+		"select * from t order by a, c",
+		"select * from t order by a desc, b, c",
+		"select b, c from t order by b, c desc",
+	}
+	for _, sql := range queries {
+		expected := tk.MustQuery(sql).Rows()
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Equals, int64(0))
+
+		tk.MustExec("set @@tidb_mem_quota_query = 1")
+		tk.MustExec("set @@tidb_mem_oom_action = 'spill'")
+		tk.MustQuery(sql).Check(expected)
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed() > 0, IsTrue, Commentf("sql: %s", sql))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+
+		tk.MustExec("set @@tidb_mem_quota_query = default")
+		tk.MustExec("set @@tidb_mem_oom_action = default")
+	}
+}
+
+func (s *testSuite5) TestHashJoinAndAggSpillDisk(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int, b varchar(20), c double)")
+	tk.MustExec("create table t1 (a int, b varchar(20), c double)")
+	for _, tbl := range []string{"t", "t1"} {
+		rows := 500 + rand.Intn(500)
+		values := make([]string, 0, rows)
+		for i := 0; i < rows; i++ {
+			if i%23 == 0 {
+				values = append(values, fmt.Sprintf("(null, null, %d)", i))
+				continue
+			}
+			values = append(values, fmt.Sprintf("(%d, 'v%d', %d.5)", rand.Intn(300), rand.Intn(50), rand.Intn(100)))
+		}
+		tk.MustExec(fmt.Sprintf("insert into %s values %s", tbl, strings.Join(values, ", ")))
+	}
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	queries := []string{
+		"select /*+ HASH_JOIN(t, t1) */ t.a, t.c, t1.c from t join t1 on t.a = t1.a",
+		"select /*+ HASH_JOIN(t, t1) */ t.a, t.c, t1.c from t join t1 on t.a = t1.a and t.b = t1.b and t.c < t1.c",
+		"select /*+ HASH_JOIN(t, t1) */ t.a, t.c, t1.c from t left join t1 on t.a = t1.a",
+		"select /*+ HASH_JOIN(t, t1) */ t.a, t.c, t1.c from t right join t1 on t.b = t1.b and t.a > t1.a",
+		"select /*+ HASH_JOIN(t, t1) */ t.a, t.c, t1.c from t join t1 on t.a <=> t1.a",
+		"select * from t where t.a in (select a from t1 where t1.c > 50)",
+		"select * from t where not exists (select * from t1 where t1.a = t.a)",
+		"select /*+ HASH_AGG() */ count(*), sum(c), avg(c), min(b), max(c) from t",
+		"select /*+ HASH_AGG() */ a, count(*), sum(c), avg(c), min(b), max(b) from t group by a",
+		"select /*+ HASH_AGG() */ a, b, count(c), sum(a), avg(c) from t group by a, b",
+		"select /*+ HASH_AGG() */ distinct a, b from t",
+		"select /*+ HASH_AGG() */ count(*), sum(c) from t where a > 1000",
+	}
+	for _, sql := range queries {
+		expected := tk.MustQuery(sql).Sort().Rows()
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Equals, int64(0))
+
+		tk.MustExec("set @@tidb_mem_quota_query = 1")
+		tk.MustExec("set @@tidb_mem_oom_action = 'spill'")
+		tk.MustQuery(sql).Sort().Check(expected)
+		if len(expected) > 1 {
+			c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed() > 0, IsTrue, Commentf("sql: %s", sql))
+		}
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+
+		tk.MustExec("set @@tidb_mem_quota_query = default")
+		tk.MustExec("set @@tidb_mem_oom_action = default")
+	}
+}
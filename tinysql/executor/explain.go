@@ -25,19 +25,28 @@ import (
 type ExplainExec struct {
 	baseExecutor
 
-	explain *core.Explain
-	rows    [][]string
-	cursor  int
+	explain     *core.Explain
+	analyzeExec Executor
+	executed    bool
+	rows        [][]string
+	cursor      int
 }
 
 // Open implements the Executor Open interface.
 func (e *ExplainExec) Open(ctx context.Context) error {
+	if e.analyzeExec != nil {
+		return e.analyzeExec.Open(ctx)
+	}
 	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *ExplainExec) Close() error {
 	e.rows = nil
+	if e.analyzeExec != nil && !e.executed {
+		// The analyzeExec is opened but not executed, it is closed here.
+		return e.analyzeExec.Close()
+	}
 	return nil
 }
 
@@ -67,8 +76,42 @@ func (e *ExplainExec) Next(ctx context.Context, req *chunk.Chunk) error {
 }
 
 func (e *ExplainExec) generateExplainInfo(ctx context.Context) ([][]string, error) {
+	if e.analyzeExec != nil && !e.executed {
+		e.executed = true
+		if err := e.executeAnalyzeExec(ctx); err != nil {
+			return nil, err
+		}
+	}
 	if err := e.explain.RenderResult(); err != nil {
 		return nil, err
 	}
 	return e.explain.Rows, nil
 }
+
+// executeAnalyzeExec runs the statement of EXPLAIN ANALYZE to the end, the
+// runtime stats of its executors are collected in the statement context.
+func (e *ExplainExec) executeAnalyzeExec(ctx context.Context) (err error) {
+	defer func() {
+		closeErr := e.analyzeExec.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	chk := newFirstChunk(e.analyzeExec)
+	for {
+		err = Next(ctx, e.analyzeExec, chk)
+		if err != nil || chk.NumRows() == 0 {
+			return err
+		}
+	}
+}
+
+// getAnalyzeExecToExecutedNoDelay returns the DML of EXPLAIN ANALYZE, which
+// should be executed in the statement like the other DMLs.
+func (e *ExplainExec) getAnalyzeExecToExecutedNoDelay() Executor {
+	if e.analyzeExec != nil && !e.executed && e.analyzeExec.Schema().Len() == 0 {
+		e.executed = true
+		return e.analyzeExec
+	}
+	return nil
+}
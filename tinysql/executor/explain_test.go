@@ -0,0 +1,74 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite) TestExplainAnalyze(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, index idx(b))")
+	tk.MustExec("insert into t values(1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+
+	rows := tk.MustQuery("explain analyze select sum(c) from t where c > 1").Rows()
+	c.Assert(len(rows), Greater, 0)
+	for _, row := range rows {
+		c.Assert(row, HasLen, 6)
+		id, task, execInfo := row[0].(string), row[2].(string), row[4].(string)
+		c.Assert(strings.Contains(execInfo, "time:"), IsTrue, Commentf("%v", row))
+		c.Assert(strings.Contains(execInfo, "loops:"), IsTrue, Commentf("%v", row))
+		switch {
+		case task == "cop":
+			c.Assert(row[5], Equals, "N/A")
+			if strings.Contains(id, "Selection") {
+				c.Assert(strings.Contains(execInfo, "rows:3"), IsTrue, Commentf("%v", row))
+			}
+		case strings.Contains(id, "TableReader"):
+			c.Assert(strings.Contains(execInfo, "cop_task: {num: 1"), IsTrue, Commentf("%v", row))
+		}
+		if task == "root" {
+			c.Assert(row[5], Not(Equals), "N/A")
+		}
+	}
+	c.Assert(rows[0][4], Matches, "time:.*, loops:2, rows:1")
+
+	// The statement is executed.
+	tk.MustQuery("explain analyze insert into t values(5, 5, 5)")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+
+	// The operators which are not executed have empty runtime stats.
+	rows = tk.MustQuery("explain analyze select * from t t1 join t t2 on t1.a = t2.b where t1.c > 10").Rows()
+	for _, row := range rows {
+		c.Assert(row, HasLen, 6)
+	}
+
+	// The runtime stats are collected per statement.
+	rows = tk.MustQuery("explain analyze select * from t use index(idx) where b > 2").Rows()
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "IndexLookUp") {
+			c.Assert(row[4], Matches, "time:.*, loops:2, rows:3, cop_task: {num: 1, time: .*}")
+		}
+	}
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.RuntimeStatsColl, NotNil)
+	tk.MustQuery("select * from t where a = 1")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.RuntimeStatsColl, IsNil)
+	tk.MustQuery("explain select * from t").Check(testkit.Rows(
+		"TableReader_5 10000.00 root data:TableScan_4",
+		"└─TableScan_4 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo"))
+}
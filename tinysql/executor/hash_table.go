@@ -14,8 +14,10 @@
 package executor
 
 import (
+	"fmt"
 	"hash"
 	"hash/fnv"
+	"unsafe"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/sessionctx"
@@ -23,6 +25,8 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/stringutil"
 )
 
 const (
@@ -50,6 +54,8 @@ type hashContext struct {
 	buf       []byte
 	hashVals  []hash.Hash64
 	hasNull   []bool
+	// isNullEQ marks the key columns compared by null-eq, NULL values of them are treated as equal.
+	isNullEQ []bool
 }
 
 func (hc *hashContext) initHash(rows int) {
@@ -72,15 +78,20 @@ func (hc *hashContext) initHash(rows int) {
 }
 
 // hashRowContainer handles the rows and the hash map of a table.
-// TODO: support spilling out to disk when memory is limited.
+// HashJoinExec spills the rows to disk in partitions when memory is limited,
+// and builds a hashRowContainer for each of the partitions.
 type hashRowContainer struct {
 	records   *chunk.List
 	hashTable *rowHashMap
 
 	sc   *stmtctx.StatementContext
 	hCtx *hashContext
+
+	memTracker *memory.Tracker
 }
 
+var hashRowContainerLabel fmt.Stringer = stringutil.StringerStr("hashRowContainer")
+
 func newHashRowContainer(sctx sessionctx.Context, estCount int, hCtx *hashContext, initList *chunk.List) *hashRowContainer {
 	maxChunkSize := sctx.GetSessionVars().MaxChunkSize
 	// The estCount from cost model is not quite accurate and we need
@@ -100,10 +111,19 @@ func newHashRowContainer(sctx sessionctx.Context, estCount int, hCtx *hashContex
 
 		sc:   sctx.GetSessionVars().StmtCtx,
 		hCtx: hCtx,
+
+		memTracker: memory.NewTracker(hashRowContainerLabel, -1),
 	}
+	initList.GetMemTracker().AttachTo(c.memTracker)
+	c.memTracker.Consume(c.hashTable.memUsage())
 	return c
 }
 
+// GetMemTracker returns the memory tracker of the rows and the hash map.
+func (c *hashRowContainer) GetMemTracker() *memory.Tracker {
+	return c.memTracker
+}
+
 // GetMatchedRows get matched rows from probeRow. It can be called
 // in multiple goroutines while each goroutine should keep its own
 // h and buf.
@@ -146,13 +166,15 @@ func (c *hashRowContainer) matchJoinKey(buildRow, probeRow chunk.Row, probeHCtx
 func (c *hashRowContainer) PutChunk(chk *chunk.Chunk) error {
 	chkIdx := uint32(c.records.NumChunks())
 	numRows := chk.NumRows()
+	oldMemUsage := c.hashTable.memUsage()
 
 	c.records.Add(chk)
 	c.hCtx.initHash(numRows)
 
 	hCtx := c.hCtx
-	for _, colIdx := range c.hCtx.keyColIdx {
-		err := codec.HashChunkColumns(c.sc, hCtx.hashVals, chk, hCtx.allTypes[colIdx], colIdx, hCtx.buf, hCtx.hasNull)
+	for keyIdx, colIdx := range c.hCtx.keyColIdx {
+		ignoreNull := len(hCtx.isNullEQ) > keyIdx && hCtx.isNullEQ[keyIdx]
+		err := codec.HashChunkSelected(c.sc, hCtx.hashVals, chk, hCtx.allTypes[colIdx], colIdx, hCtx.buf, hCtx.hasNull, nil, ignoreNull)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -165,6 +187,7 @@ func (c *hashRowContainer) PutChunk(chk *chunk.Chunk) error {
 		rowPtr := chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(i)}
 		c.hashTable.Put(key, rowPtr)
 	}
+	c.memTracker.Consume(c.hashTable.memUsage() - oldMemUsage)
 	return nil
 }
 
@@ -183,12 +206,23 @@ type entry struct {
 	next entryAddr
 }
 
+const (
+	// entrySize is the memory size of an entry.
+	entrySize = int64(unsafe.Sizeof(entry{}))
+	// hashTableKeySize is the estimated memory size of a key in the hash
+	// table, including the key, the entryAddr and the overhead of the map.
+	hashTableKeySize = int64(unsafe.Sizeof(uint64(0))+unsafe.Sizeof(entryAddr{})) * 3 / 2
+)
+
 type entryStore struct {
 	slices [][]entry
+	// memUsage is the memory size of the allocated slices.
+	memUsage int64
 }
 
 func (es *entryStore) init() {
 	es.slices = [][]entry{make([]entry, 0, initialEntrySliceLen)}
+	es.memUsage = entrySize * initialEntrySliceLen
 	// Reserve the first empty entry, so entryAddr{} can represent nullEntryAddr.
 	reserved := es.put(entry{})
 	if reserved != nullEntryAddr {
@@ -206,6 +240,7 @@ func (es *entryStore) put(e entry) entryAddr {
 		}
 		slice = make([]entry, 0, size)
 		es.slices = append(es.slices, slice)
+		es.memUsage += entrySize * int64(size)
 		sliceIdx++
 	}
 	addr := entryAddr{sliceIdx: sliceIdx, offset: uint32(len(slice))}
@@ -232,6 +267,7 @@ type rowHashMap struct {
 	entryStore entryStore
 	hashTable  map[uint64]entryAddr
 	length     int
+	estCount   int
 }
 
 // newRowHashMap creates a new rowHashMap. estCount means the estimated size of the hashMap.
@@ -240,6 +276,7 @@ func newRowHashMap(estCount int) *rowHashMap {
 	m := new(rowHashMap)
 	m.hashTable = make(map[uint64]entryAddr, estCount)
 	m.entryStore.init()
+	m.estCount = estCount
 	return m
 }
 
@@ -271,6 +308,16 @@ func (m *rowHashMap) Get(hashKey uint64) (rowPtrs []chunk.RowPtr) {
 	return
 }
 
+// memUsage returns the estimated memory usage of the rowHashMap, the space
+// reserved for the estimated count of keys is counted in advance.
+func (m *rowHashMap) memUsage() int64 {
+	numKeys := len(m.hashTable)
+	if numKeys < m.estCount {
+		numKeys = m.estCount
+	}
+	return m.entryStore.memUsage + hashTableKeySize*int64(numKeys)
+}
+
 // Len returns the number of rowPtrs in the rowHashMap, the number of keys may be less than Len
 // if the same key is put more than once.
 func (m *rowHashMap) Len() int { return m.length }
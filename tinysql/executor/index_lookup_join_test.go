@@ -0,0 +1,78 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func checkIndexJoinAndRun(tk *testkit.TestKit, c *C, sql string) *testkit.Result {
+	result := tk.MustQuery("explain " + sql)
+	resultStr := fmt.Sprintf("%v", result.Rows())
+	if !strings.Contains(resultStr, "IndexJoin") {
+		c.Errorf("Expected IndexJoin in plan of %s, obtained:\n%s", sql, resultStr)
+	}
+	return tk.MustQuery(sql)
+}
+
+func (s *testSuiteJoin1) TestIndexLookupJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, s")
+	tk.MustExec("create table t(id int primary key, a int, b int)")
+	tk.MustExec("create table s(a int primary key, b int, c int, index idx_b(b), index idx_bc(b, c))")
+	tk.MustExec("insert into t values(1, 1, 1), (2, 2, 2), (3, 3, 3), (4, null, null), (5, 1, 5)")
+	tk.MustExec("insert into s values(1, 1, 1), (2, 2, 2), (4, 1, 3), (5, 5, 5)")
+
+	// The inner side is read by the integer handle.
+	checkIndexJoinAndRun(tk, c, "select * from t join s on t.a = s.a where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1 1 1 1", "2 2 2 2 2 2", "5 1 5 1 1 1"))
+	checkIndexJoinAndRun(tk, c, "select * from t left join s on t.a = s.a where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1 1 1 1", "2 2 2 2 2 2", "3 3 3 <nil> <nil> <nil>", "4 <nil> <nil> <nil> <nil> <nil>", "5 1 5 1 1 1"))
+	checkIndexJoinAndRun(tk, c, "select * from t where exists (select 1 from s where s.a = t.a) and t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1", "2 2 2", "5 1 5"))
+	// The inner side is read by an index.
+	checkIndexJoinAndRun(tk, c, "select * from t join s on t.b = s.b where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1 1 1 1", "1 1 1 4 1 3", "2 2 2 2 2 2", "5 1 5 5 5 5"))
+	checkIndexJoinAndRun(tk, c, "select t.a, s.c from t join s on t.b = s.b where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1", "1 3", "1 5", "2 2"))
+
+	// The inner side is merged with the dirty rows of the transaction.
+	tk.MustExec("begin")
+	tk.MustExec("insert into s values(3, 3, 3)")
+	tk.MustExec("delete from s where a = 2")
+	checkIndexJoinAndRun(tk, c, "select * from t left join s on t.a = s.a where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1 1 1 1", "2 2 2 <nil> <nil> <nil>", "3 3 3 3 3 3", "4 <nil> <nil> <nil> <nil> <nil>", "5 1 5 1 1 1"))
+	checkIndexJoinAndRun(tk, c, "select * from t join s on t.b = s.b where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1 1 1 1", "1 1 1 4 1 3", "3 3 3 3 3 3", "5 1 5 5 5 5"))
+	checkIndexJoinAndRun(tk, c, "select * from t left join s on t.b = s.b and s.c > 1 where t.id < 10").Sort().Check(testkit.Rows(
+		"1 1 1 4 1 3", "2 2 2 <nil> <nil> <nil>", "3 3 3 3 3 3", "4 <nil> <nil> <nil> <nil> <nil>", "5 1 5 5 5 5"))
+	tk.MustExec("rollback")
+
+	// The outer side is read in several batches.
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(id int primary key, a int)")
+	tk.MustExec("create table t2(a int primary key, b int, index idx(b))")
+	for i := 0; i < 300; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values(%d, %d)", i, i%50))
+		tk.MustExec(fmt.Sprintf("insert into t2 values(%d, %d)", i, i%7))
+	}
+	tk.MustExec("set @@tidb_index_lookup_join_concurrency = 2")
+	checkIndexJoinAndRun(tk, c, "select count(*), sum(t2.b) from t1 join t2 on t1.a = t2.a where t1.id < 1000").Check(testkit.Rows("300 882"))
+	checkIndexJoinAndRun(tk, c, "select count(*), sum(t2.a) from t1 join t2 on t1.a = t2.b where t1.id < 1000").Check(testkit.Rows("1800 269100"))
+}
@@ -16,8 +16,12 @@ package executor
 import (
 	"context"
 
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
@@ -27,6 +31,13 @@ import (
 // InsertExec represents an insert executor.
 type InsertExec struct {
 	*InsertValues
+	OnDuplicate []*expression.Assignment
+	// evalBuffer4Dup is the buffer to evaluate the assignments of
+	// ON DUPLICATE KEY UPDATE, it holds the duplicated row in the table.
+	evalBuffer4Dup chunk.MutRow
+	// curInsertVals holds the to-be-inserted row, VALUES() functions in
+	// ON DUPLICATE KEY UPDATE read values from it.
+	curInsertVals chunk.MutRow
 
 	Priority mysql.PriorityEnum
 }
@@ -40,6 +51,16 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	}
 	sessVars.GetWriteStmtBufs().BufStore = kv.NewBufferStore(txn, kv.TempTxnMemBufCap)
 	sessVars.StmtCtx.AddRecordRows(uint64(len(rows)))
+	// If `ON DUPLICATE KEY UPDATE` is specified, the duplicated rows are
+	// updated instead of inserted. Otherwise with the IGNORE keyword, the rows
+	// with duplicate keys are discarded and appended as warnings.
+	// The keys of the rows are prefetched by BatchGet in both cases.
+	if len(e.OnDuplicate) > 0 {
+		return e.batchUpdateDupRows(ctx, rows)
+	}
+	if sessVars.StmtCtx.DupKeyAsWarning {
+		return e.batchCheckAndInsert(ctx, rows, e.addRecord)
+	}
 	for _, row := range rows {
 		logutil.BgLogger().Debug("row", zap.Int("col", len(row)))
 		var err error
@@ -53,6 +74,123 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	return nil
 }
 
+// batchUpdateDupRows updates multi-rows in batch if they are duplicate with rows in table.
+func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.Datum) error {
+	// Get keys need to be checked.
+	toBeCheckedRows, err := getKeysNeedCheck(ctx, e.ctx, e.Table, newRows)
+	if err != nil {
+		return err
+	}
+
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+
+	// Use BatchGet to fill cache.
+	// It's an optimization and could be removed without affecting correctness.
+	if err = prefetchDataCache(ctx, txn, toBeCheckedRows); err != nil {
+		return err
+	}
+
+	for i, r := range toBeCheckedRows {
+		if r.handleKey != nil {
+			handle, err := tablecodec.DecodeRowKey(r.handleKey.newKV.key)
+			if err != nil {
+				return err
+			}
+
+			err = e.updateDupRow(ctx, txn, r, handle, e.OnDuplicate)
+			if err == nil {
+				continue
+			}
+			if !kv.IsErrNotFound(err) {
+				return err
+			}
+		}
+
+		for _, uk := range r.uniqueKeys {
+			val, err := txn.Get(ctx, uk.newKV.key)
+			if err != nil {
+				if kv.IsErrNotFound(err) {
+					continue
+				}
+				return err
+			}
+			handle, err := tables.DecodeHandle(val)
+			if err != nil {
+				return err
+			}
+
+			err = e.updateDupRow(ctx, txn, r, handle, e.OnDuplicate)
+			if err != nil {
+				return err
+			}
+
+			newRows[i] = nil
+			break
+		}
+
+		// If the row has no duplicate keys, it is inserted. The row is written
+		// to the membuffer of the transaction, so the txn.Get above finds it
+		// when a later row of the same statement has the same keys.
+		if newRows[i] != nil {
+			_, err := e.addRecord(ctx, newRows[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// updateDupRow updates a duplicate row to a new row.
+func (e *InsertExec) updateDupRow(ctx context.Context, txn kv.Transaction, row toBeCheckedRow, handle int64, onDuplicate []*expression.Assignment) error {
+	oldRow, err := getOldRow(ctx, e.ctx, txn, row.t, handle)
+	if err != nil {
+		return err
+	}
+
+	err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, onDuplicate)
+	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
+		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return nil
+	}
+	return err
+}
+
+// doDupRowUpdate updates the duplicate row.
+func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle int64, oldRow []types.Datum, newRow []types.Datum,
+	cols []*expression.Assignment) error {
+	assignFlag := make([]bool, len(e.Table.WritableCols()))
+	// See http://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
+	e.curInsertVals.SetDatums(newRow...)
+	e.ctx.GetSessionVars().CurrInsertValues = e.curInsertVals.ToRow()
+
+	// The assignments are evaluated on the old row, a later assignment sees
+	// the values set by the former ones.
+	e.evalBuffer4Dup.SetDatums(oldRow...)
+	row4Update := append(make([]types.Datum, 0, len(oldRow)), oldRow...)
+
+	// Update old row when the key is duplicated.
+	writableCols := e.Table.WritableCols()
+	for _, col := range cols {
+		val, err1 := col.Expr.Eval(e.evalBuffer4Dup.ToRow())
+		if err1 != nil {
+			return err1
+		}
+		row4Update[col.Col.Index], err1 = table.CastValue(e.ctx, val, writableCols[col.Col.Index].ToInfo())
+		if err1 != nil {
+			return err1
+		}
+		e.evalBuffer4Dup.SetDatum(col.Col.Index, row4Update[col.Col.Index])
+		assignFlag[col.Col.Index] = true
+	}
+
+	_, _, _, err := updateRecord(ctx, e.ctx, handle, oldRow, row4Update, assignFlag, e.Table, true)
+	return err
+}
+
 // Next implements the Executor Next interface.
 func (e *InsertExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
@@ -80,6 +218,7 @@ func (e *InsertExec) Close() error {
 
 // Open implements the Executor Open interface.
 func (e *InsertExec) Open(ctx context.Context) error {
+	e.initEvalBuffer4Dup()
 	if e.SelectExec != nil {
 		var err error
 		// Hint: step II.2
@@ -93,6 +232,18 @@ func (e *InsertExec) Open(ctx context.Context) error {
 	return nil
 }
 
+func (e *InsertExec) initEvalBuffer4Dup() {
+	if len(e.OnDuplicate) == 0 {
+		return
+	}
+	fieldTps := make([]*types.FieldType, 0, len(e.Table.WritableCols()))
+	for _, col := range e.Table.WritableCols() {
+		fieldTps = append(fieldTps, &col.FieldType)
+	}
+	e.evalBuffer4Dup = chunk.MutRowFromTypes(fieldTps)
+	e.curInsertVals = chunk.MutRowFromTypes(fieldTps)
+}
+
 func (e *InsertExec) insertRows(ctx context.Context) error {
 	// Use the common insertRows function from insert_common.go
 	return insertRows(ctx, e)
@@ -657,6 +657,65 @@ func getAutoRecordID(d types.Datum, target *types.FieldType, isInsert bool) (int
 	return recordID, nil
 }
 
+// batchCheckAndInsert checks rows with duplicate errors.
+// All duplicate rows will be ignored and appended as duplicate warnings.
+func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.Datum, addRecord func(ctx context.Context, row []types.Datum) (int64, error)) error {
+	// All the rows will be checked, so it is safe to set BatchCheck = true.
+	e.ctx.GetSessionVars().StmtCtx.BatchCheck = true
+
+	// Get keys need to be checked.
+	toBeCheckedRows, err := getKeysNeedCheck(ctx, e.ctx, e.Table, rows)
+	if err != nil {
+		return err
+	}
+
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+
+	// Fill cache using BatchGet, the following Get requests don't need to visit TiKV.
+	if _, err = prefetchUniqueIndices(ctx, txn, toBeCheckedRows); err != nil {
+		return err
+	}
+
+	// Append warnings and insert the rows without duplicate errors.
+	for i, r := range toBeCheckedRows {
+		if r.handleKey != nil {
+			_, err := txn.Get(ctx, r.handleKey.newKV.key)
+			if err == nil {
+				e.ctx.GetSessionVars().StmtCtx.AppendWarning(r.handleKey.dupErr)
+				continue
+			}
+			if !kv.IsErrNotFound(err) {
+				return err
+			}
+		}
+		skip := false
+		for _, uk := range r.uniqueKeys {
+			_, err := txn.Get(ctx, uk.newKV.key)
+			if err == nil {
+				e.ctx.GetSessionVars().StmtCtx.AppendWarning(uk.dupErr)
+				skip = true
+				break
+			}
+			if !kv.IsErrNotFound(err) {
+				return err
+			}
+		}
+		// The row without duplicate keys is added to the transaction, so the
+		// following rows of the statement are checked against it as well.
+		if !skip {
+			e.ctx.GetSessionVars().StmtCtx.AddCopiedRows(1)
+			_, err = addRecord(ctx, rows[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) (int64, error) {
 	txn, err := e.ctx.Txn(true)
 	if err != nil {
@@ -668,10 +727,6 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) (int64,
 	var recordID int64
 	// Hint: step II.5
 	// YOUR CODE HERE (lab4)
-	row, err = e.getRow(ctx, row)
-	if err != nil {
-		return 0, err
-	}
 	recordID, err = e.Table.AddRecord(e.ctx, row)
 	txn.DelOption(kv.PresumeKeyNotExists)
 	if err != nil {
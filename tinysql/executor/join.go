@@ -19,13 +19,19 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/memory"
 )
 
-var _ Executor = &HashJoinExec{}
+var (
+	_ Executor = &HashJoinExec{}
+	_ Executor = &NestedLoopApplyExec{}
+)
 
 // HashJoinExec implements the hash join algorithm.
 type HashJoinExec struct {
@@ -37,6 +43,8 @@ type HashJoinExec struct {
 	outerSideFilter   expression.CNFExprs
 	outerKeys         []*expression.Column
 	innerKeys         []*expression.Column
+	// isNullEQ is used for cases like Except statement where null key should be matched with null key.
+	isNullEQ []bool
 
 	// concurrency is the number of partition, build and join workers.
 	concurrency  uint
@@ -56,7 +64,17 @@ type HashJoinExec struct {
 	joinChkResourceCh  []chan *chunk.Chunk
 	joinResultCh       chan *hashjoinWorkerResult
 
-	prepared bool
+	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *memory.Tracker // track disk usage.
+	prepared    bool
+
+	// spillAction is registered on the statement memory tracker when the OOM
+	// action is SPILL. Once the build side doesn't fit in memory, both sides
+	// are split into partitions on disk by the hash values of the join keys,
+	// and the partitions are joined pair by pair.
+	spillAction     *spillDiskAction
+	buildPartitions *partitionsInDisk
+	probePartitions *partitionsInDisk
 }
 
 // outerChkResource stores the result of the join outer side fetch worker,
@@ -102,7 +120,29 @@ func (e *HashJoinExec) Close() error {
 		e.outerChkResourceCh = nil
 		e.joinChkResourceCh = nil
 	}
-	err := e.baseExecutor.Close()
+	if e.spillAction != nil {
+		e.spillAction.setSpillable(false)
+		e.spillAction = nil
+	}
+	var err error
+	for _, partitions := range []*partitionsInDisk{e.buildPartitions, e.probePartitions} {
+		if partitions == nil {
+			continue
+		}
+		if err1 := partitions.close(); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+	e.buildPartitions, e.probePartitions = nil, nil
+	if e.memTracker != nil {
+		e.memTracker.Detach()
+	}
+	if e.diskTracker != nil {
+		e.diskTracker.Detach()
+	}
+	if err1 := e.baseExecutor.Close(); err == nil {
+		err = err1
+	}
 	return err
 }
 
@@ -113,6 +153,10 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 	}
 
 	e.prepared = false
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = memory.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
 	e.closeCh = make(chan struct{})
 	e.joinWorkerWaitGroup = sync.WaitGroup{}
 	return nil
@@ -128,7 +172,11 @@ func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		if err != nil {
 			return err
 		}
-		e.fetchAndProbeHashTable(ctx)
+		if e.buildPartitions != nil {
+			e.fetchAndProbeSpilledPartitions(ctx)
+		} else {
+			e.fetchAndProbeHashTable(ctx)
+		}
 		e.prepared = true
 	}
 	req.Reset()
@@ -146,18 +194,17 @@ func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 }
 
 func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) error {
-	buildKeyColIdx := make([]int, len(e.innerKeys))
-	for i := range e.innerKeys {
-		buildKeyColIdx[i] = e.innerKeys[i].Index
-	}
-	allTypes := e.innerSideExec.base().retFieldTypes
-	hCtx := &hashContext{
-		allTypes:  allTypes,
-		keyColIdx: buildKeyColIdx,
+	sessVars := e.ctx.GetSessionVars()
+	if sessVars.OOMAction == variable.OOMActionSpill && sessVars.StmtCtx.MemTracker != nil {
+		e.spillAction = &spillDiskAction{spillable: 1}
+		sessVars.StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+		// The probe phase of the in-memory hash join is not able to spill.
+		defer e.spillAction.setSpillable(false)
 	}
-	initList := chunk.NewList(allTypes, e.initCap, e.maxChunkSize)
+	hCtx := e.newBuildSideHashContext()
+	initList := chunk.NewList(hCtx.allTypes, e.initCap, e.maxChunkSize)
 	e.rowContainer = newHashRowContainer(e.ctx, int(e.innerSideEstCount), hCtx, initList)
-
+	e.rowContainer.GetMemTracker().AttachTo(e.memTracker)
 	for {
 		chk := chunk.NewChunkWithCapacity(e.innerSideExec.base().retFieldTypes, e.ctx.GetSessionVars().MaxChunkSize)
 		err := Next(ctx, e.innerSideExec, chk)
@@ -165,13 +212,86 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) error {
 			return err
 		}
 		if chk.NumRows() == 0 {
-			return nil
+			break
+		}
+		if e.buildPartitions != nil {
+			if err = e.partitionChunk(e.buildPartitions, chk, hCtx, 0, true); err != nil {
+				return err
+			}
+			continue
 		}
 		err = e.rowContainer.PutChunk(chk)
 		if err != nil {
 			return err
 		}
+		if e.spillAction != nil && e.spillAction.shouldSpill() {
+			if err = e.spillBuildSide(hCtx); err != nil {
+				return err
+			}
+		}
+	}
+	if e.buildPartitions != nil {
+		return e.buildPartitions.finish()
+	}
+	return nil
+}
+
+func (e *HashJoinExec) newBuildSideHashContext() *hashContext {
+	buildKeyColIdx := make([]int, len(e.innerKeys))
+	for i := range e.innerKeys {
+		buildKeyColIdx[i] = e.innerKeys[i].Index
+	}
+	return &hashContext{
+		allTypes:  e.innerSideExec.base().retFieldTypes,
+		keyColIdx: buildKeyColIdx,
+		isNullEQ:  e.isNullEQ,
+	}
+}
+
+// spillBuildSide moves the build side rows in memory to the partitions on
+// disk, the following rows are written to the partitions directly.
+func (e *HashJoinExec) spillBuildSide(hCtx *hashContext) error {
+	e.buildPartitions = newPartitionsInDisk(hCtx.allTypes, e.maxChunkSize, e.diskTracker)
+	records := e.rowContainer.records
+	for i := 0; i < records.NumChunks(); i++ {
+		if err := e.partitionChunk(e.buildPartitions, records.GetChunk(i), hCtx, 0, true); err != nil {
+			return err
+		}
+	}
+	e.rowContainer.GetMemTracker().Detach()
+	e.rowContainer = nil
+	e.spillAction.reset()
+	return nil
+}
+
+// partitionChunk splits the rows of chk into partitions by the hash values of
+// the join keys in the depth-th round of partitioning. The build side rows
+// with NULL keys never match, so they are dropped, the probe side ones are
+// kept in the first partition to be processed as unmatched rows.
+func (e *HashJoinExec) partitionChunk(partitions *partitionsInDisk, chk *chunk.Chunk, hCtx *hashContext, depth int, isBuildSide bool) error {
+	numRows := chk.NumRows()
+	hCtx.initHash(numRows)
+	for keyIdx, colIdx := range hCtx.keyColIdx {
+		ignoreNull := len(hCtx.isNullEQ) > keyIdx && hCtx.isNullEQ[keyIdx]
+		err := codec.HashChunkSelected(e.ctx.GetSessionVars().StmtCtx, hCtx.hashVals, chk, hCtx.allTypes[colIdx], colIdx, hCtx.buf, hCtx.hasNull, nil, ignoreNull)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for i := 0; i < numRows; i++ {
+		partIdx := 0
+		if hCtx.hasNull[i] {
+			if isBuildSide {
+				continue
+			}
+		} else {
+			partIdx = spillPartitionIdx(hCtx.hashVals[i].Sum64(), depth)
+		}
+		if err := partitions.appendRow(partIdx, chk.GetRow(i)); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func (e *HashJoinExec) initializeForOuter() {
@@ -257,6 +377,167 @@ func (e *HashJoinExec) fetchAndProbeHashTable(ctx context.Context) {
 	go util.WithRecovery(e.waitJoinWorkersAndCloseResultChan, nil)
 }
 
+// fetchAndProbeSpilledPartitions splits the probe side rows into partitions in
+// the same way as the build side, and joins the partitions pair by pair in a
+// single join worker.
+func (e *HashJoinExec) fetchAndProbeSpilledPartitions(ctx context.Context) {
+	e.joinChkResourceCh = []chan *chunk.Chunk{make(chan *chunk.Chunk, 1)}
+	e.joinChkResourceCh[0] <- newFirstChunk(e)
+	e.joinResultCh = make(chan *hashjoinWorkerResult, 2)
+
+	e.joinWorkerWaitGroup.Add(1)
+	go util.WithRecovery(func() { e.runSpilledJoinWorker(ctx) }, e.handleJoinWorkerPanic)
+	go util.WithRecovery(e.waitJoinWorkersAndCloseResultChan, nil)
+}
+
+func (e *HashJoinExec) runSpilledJoinWorker(ctx context.Context) {
+	ok, joinResult := e.getNewJoinResult(0)
+	if !ok {
+		return
+	}
+	outerKeyColIdx := make([]int, len(e.outerKeys))
+	for i := range e.outerKeys {
+		outerKeyColIdx[i] = e.outerKeys[i].Index
+	}
+	hCtx := &hashContext{
+		allTypes:  retTypes(e.outerSideExec),
+		keyColIdx: outerKeyColIdx,
+		isNullEQ:  e.isNullEQ,
+	}
+	e.probePartitions = newPartitionsInDisk(hCtx.allTypes, e.maxChunkSize, e.diskTracker)
+	chk := newFirstChunk(e.outerSideExec)
+	for {
+		select {
+		case <-e.closeCh:
+			return
+		default:
+		}
+		err := Next(ctx, e.outerSideExec, chk)
+		if err == nil && chk.NumRows() == 0 {
+			err = e.probePartitions.finish()
+			if err == nil {
+				break
+			}
+		}
+		if err == nil {
+			err = e.partitionChunk(e.probePartitions, chk, hCtx, 0, false)
+		}
+		if err != nil {
+			joinResult.err = err
+			e.joinResultCh <- joinResult
+			return
+		}
+	}
+	for i := 0; ok && i < spillPartitionNum; i++ {
+		ok, joinResult = e.joinPartition(e.buildPartitions.getPartition(i), e.probePartitions.getPartition(i), hCtx, 1, joinResult)
+	}
+	if joinResult == nil {
+		return
+	} else if joinResult.err != nil || (joinResult.chk != nil && joinResult.chk.NumRows() > 0) {
+		e.joinResultCh <- joinResult
+	}
+}
+
+// joinPartition builds the hash table of a build side partition and probes it
+// with the probe side partition. If the build side partition doesn't fit in
+// memory, both partitions are split again in the depth-th round.
+func (e *HashJoinExec) joinPartition(buildSide, probeSide *chunk.ListInDisk, hCtx *hashContext, depth int,
+	joinResult *hashjoinWorkerResult) (bool, *hashjoinWorkerResult) {
+	if probeSide == nil {
+		// All the output rows are generated from the probe side rows.
+		return true, joinResult
+	}
+	// A partition still exceeding the quota after maxSpillDepth rounds of
+	// partitioning mostly consists of very few keys, it is built in memory.
+	e.spillAction.reset()
+	e.spillAction.setSpillable(true)
+	buildHCtx := e.newBuildSideHashContext()
+	e.rowContainer = newHashRowContainer(e.ctx, 0, buildHCtx, chunk.NewList(buildHCtx.allTypes, e.initCap, e.maxChunkSize))
+	e.rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	defer e.rowContainer.GetMemTracker().Detach()
+	for i := 0; buildSide != nil && i < buildSide.NumChunks(); i++ {
+		chk, err := buildSide.GetChunk(i)
+		if err == nil {
+			err = e.rowContainer.PutChunk(chk)
+		}
+		if err != nil {
+			e.spillAction.setSpillable(false)
+			joinResult.err = err
+			return false, joinResult
+		}
+		if e.spillAction.shouldSpill() && depth < maxSpillDepth {
+			e.spillAction.setSpillable(false)
+			e.rowContainer.GetMemTracker().Detach()
+			return e.repartitionAndJoin(buildSide, probeSide, buildHCtx, hCtx, depth, joinResult)
+		}
+	}
+	e.spillAction.setSpillable(false)
+
+	selected := make([]bool, 0, chunk.InitialCapacity)
+	for i := 0; i < probeSide.NumChunks(); i++ {
+		select {
+		case <-e.closeCh:
+			return false, joinResult
+		default:
+		}
+		chk, err := probeSide.GetChunk(i)
+		if err != nil {
+			joinResult.err = err
+			return false, joinResult
+		}
+		var ok bool
+		ok, joinResult = e.join2Chunk(0, chk, hCtx, joinResult, selected)
+		if !ok {
+			return false, joinResult
+		}
+	}
+	return true, joinResult
+}
+
+// repartitionAndJoin splits a pair of partitions into smaller ones by the
+// hash values of the join keys and joins them pair by pair.
+func (e *HashJoinExec) repartitionAndJoin(buildSide, probeSide *chunk.ListInDisk, buildHCtx, probeHCtx *hashContext, depth int,
+	joinResult *hashjoinWorkerResult) (ok bool, _ *hashjoinWorkerResult) {
+	buildPartitions := newPartitionsInDisk(buildHCtx.allTypes, e.maxChunkSize, e.diskTracker)
+	probePartitions := newPartitionsInDisk(probeHCtx.allTypes, e.maxChunkSize, e.diskTracker)
+	defer func() {
+		for _, partitions := range []*partitionsInDisk{buildPartitions, probePartitions} {
+			if err := partitions.close(); err != nil && joinResult.err == nil {
+				joinResult.err, ok = err, false
+			}
+		}
+	}()
+	for _, side := range []struct {
+		list       *chunk.ListInDisk
+		partitions *partitionsInDisk
+		hCtx       *hashContext
+		isBuild    bool
+	}{
+		{buildSide, buildPartitions, buildHCtx, true},
+		{probeSide, probePartitions, probeHCtx, false},
+	} {
+		for i := 0; i < side.list.NumChunks(); i++ {
+			chk, err := side.list.GetChunk(i)
+			if err == nil {
+				err = e.partitionChunk(side.partitions, chk, side.hCtx, depth, side.isBuild)
+			}
+			if err != nil {
+				joinResult.err = err
+				return false, joinResult
+			}
+		}
+		if err := side.partitions.finish(); err != nil {
+			joinResult.err = err
+			return false, joinResult
+		}
+	}
+	ok = true
+	for i := 0; ok && i < spillPartitionNum; i++ {
+		ok, joinResult = e.joinPartition(buildPartitions.getPartition(i), probePartitions.getPartition(i), probeHCtx, depth+1, joinResult)
+	}
+	return ok, joinResult
+}
+
 func (e *HashJoinExec) runJoinWorker(workerID uint, outerKeyColIdx []int) {
 	var (
 		outerSideResult *chunk.Chunk
@@ -274,6 +555,7 @@ func (e *HashJoinExec) runJoinWorker(workerID uint, outerKeyColIdx []int) {
 	hCtx := &hashContext{
 		allTypes:  retTypes(e.outerSideExec),
 		keyColIdx: outerKeyColIdx,
+		isNullEQ:  e.isNullEQ,
 	}
 	for ok := true; ok; {
 		select {
@@ -342,18 +624,19 @@ func (e *HashJoinExec) joinMatchedOuterSideRow2Chunk(workerID uint, outerKey uin
 		return false, joinResult
 	}
 	if len(buildSideRows) == 0 {
-		e.joiners[workerID].onMissMatch(outerSideRow, joinResult.chk)
+		e.joiners[workerID].onMissMatch(false, outerSideRow, joinResult.chk)
 		return true, joinResult
 	}
 	iter := chunk.NewIterator4Slice(buildSideRows)
-	hasMatch := false
+	hasMatch, hasNull := false, false
 	for iter.Begin(); iter.Current() != iter.End(); {
-		matched, _, err := e.joiners[workerID].tryToMatchInners(outerSideRow, iter, joinResult.chk)
+		matched, isNull, err := e.joiners[workerID].tryToMatchInners(outerSideRow, iter, joinResult.chk)
 		if err != nil {
 			joinResult.err = err
 			return false, joinResult
 		}
 		hasMatch = hasMatch || matched
+		hasNull = hasNull || isNull
 
 		if joinResult.chk.IsFull() {
 			e.joinResultCh <- joinResult
@@ -364,7 +647,7 @@ func (e *HashJoinExec) joinMatchedOuterSideRow2Chunk(workerID uint, outerKey uin
 		}
 	}
 	if !hasMatch {
-		e.joiners[workerID].onMissMatch(outerSideRow, joinResult.chk)
+		e.joiners[workerID].onMissMatch(hasNull, outerSideRow, joinResult.chk)
 	}
 	return true, joinResult
 }
@@ -379,8 +662,9 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 	}
 
 	hCtx.initHash(outerSideChk.NumRows())
-	for _, i := range hCtx.keyColIdx {
-		err = codec.HashChunkSelected(e.rowContainer.sc, hCtx.hashVals, outerSideChk, hCtx.allTypes[i], i, hCtx.buf, hCtx.hasNull, selected)
+	for keyIdx, i := range hCtx.keyColIdx {
+		ignoreNull := len(hCtx.isNullEQ) > keyIdx && hCtx.isNullEQ[keyIdx]
+		err = codec.HashChunkSelected(e.rowContainer.sc, hCtx.hashVals, outerSideChk, hCtx.allTypes[i], i, hCtx.buf, hCtx.hasNull, selected, ignoreNull)
 		if err != nil {
 			joinResult.err = err
 			return false, joinResult
@@ -389,7 +673,7 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 
 	for i := range selected {
 		if !selected[i] || hCtx.hasNull[i] { // process unmatched outer side rows
-			e.joiners[workerID].onMissMatch(outerSideChk.GetRow(i), joinResult.chk)
+			e.joiners[workerID].onMissMatch(false, outerSideChk.GetRow(i), joinResult.chk)
 		} else { // process matched outer side rows
 			outerKey, outerRow := hCtx.hashVals[i].Sum64(), outerSideChk.GetRow(i)
 			ok, joinResult = e.joinMatchedOuterSideRow2Chunk(workerID, outerKey, outerRow, hCtx, joinResult)
@@ -407,3 +691,150 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 	}
 	return true, joinResult
 }
+
+// NestedLoopApplyExec is the executor for apply.
+type NestedLoopApplyExec struct {
+	baseExecutor
+
+	innerExec   Executor
+	outerExec   Executor
+	innerFilter expression.CNFExprs
+	outerFilter expression.CNFExprs
+	outer       bool
+
+	joiner joiner
+
+	outerSchema []*expression.CorrelatedColumn
+
+	outerChunk       *chunk.Chunk
+	outerChunkCursor int
+	outerSelected    []bool
+	innerList        *chunk.List
+	innerChunk       *chunk.Chunk
+	innerSelected    []bool
+	innerIter        chunk.Iterator
+	outerRow         *chunk.Row
+	hasMatch         bool
+	hasNull          bool
+}
+
+// Close implements the Executor interface.
+func (e *NestedLoopApplyExec) Close() error {
+	e.innerList = nil
+	return e.outerExec.Close()
+}
+
+// Open implements the Executor interface.
+func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
+	err := e.outerExec.Open(ctx)
+	if err != nil {
+		return err
+	}
+	e.outerChunk = newFirstChunk(e.outerExec)
+	e.outerChunkCursor = 0
+	e.innerChunk = newFirstChunk(e.innerExec)
+	e.innerList = chunk.NewList(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
+	e.innerIter = nil
+	e.outerRow = nil
+	return nil
+}
+
+// fetchSelectedOuterRow fetches the next outer row which passes the outer filter.
+// The unmatched outer rows are handled by the joiner directly for outer joins.
+func (e *NestedLoopApplyExec) fetchSelectedOuterRow(ctx context.Context, chk *chunk.Chunk) (*chunk.Row, error) {
+	outerIter := chunk.NewIterator4Chunk(e.outerChunk)
+	for {
+		if e.outerChunkCursor >= e.outerChunk.NumRows() {
+			err := Next(ctx, e.outerExec, e.outerChunk)
+			if err != nil {
+				return nil, err
+			}
+			if e.outerChunk.NumRows() == 0 {
+				return nil, nil
+			}
+			e.outerSelected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, outerIter, e.outerSelected)
+			if err != nil {
+				return nil, err
+			}
+			e.outerChunkCursor = 0
+		}
+		outerRow := e.outerChunk.GetRow(e.outerChunkCursor)
+		selected := e.outerSelected[e.outerChunkCursor]
+		e.outerChunkCursor++
+		if selected {
+			return &outerRow, nil
+		} else if e.outer {
+			e.joiner.onMissMatch(false, outerRow, chk)
+			if chk.IsFull() {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// fetchAllInners reads all data from the inner table and stores them in a List.
+func (e *NestedLoopApplyExec) fetchAllInners(ctx context.Context) error {
+	err := e.innerExec.Open(ctx)
+	defer terror.Call(e.innerExec.Close)
+	if err != nil {
+		return err
+	}
+	e.innerList.Reset()
+	innerIter := chunk.NewIterator4Chunk(e.innerChunk)
+	for {
+		err := Next(ctx, e.innerExec, e.innerChunk)
+		if err != nil {
+			return err
+		}
+		if e.innerChunk.NumRows() == 0 {
+			return nil
+		}
+
+		e.innerSelected, err = expression.VectorizedFilter(e.ctx, e.innerFilter, innerIter, e.innerSelected)
+		if err != nil {
+			return err
+		}
+		for row := innerIter.Begin(); row != innerIter.End(); row = innerIter.Next() {
+			if e.innerSelected[row.Idx()] {
+				e.innerList.AppendRow(row)
+			}
+		}
+	}
+}
+
+// Next implements the Executor interface.
+func (e *NestedLoopApplyExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
+	req.Reset()
+	for {
+		if e.innerIter == nil || e.innerIter.Current() == e.innerIter.End() {
+			if e.outerRow != nil && !e.hasMatch {
+				e.joiner.onMissMatch(e.hasNull, *e.outerRow, req)
+			}
+			e.outerRow, err = e.fetchSelectedOuterRow(ctx, req)
+			if e.outerRow == nil || err != nil {
+				return err
+			}
+			e.hasMatch = false
+			e.hasNull = false
+
+			// Bind the outer row to the correlated columns before rebuilding the inner side.
+			for _, col := range e.outerSchema {
+				*col.Data = e.outerRow.GetDatum(col.Index, col.RetType)
+			}
+			err = e.fetchAllInners(ctx)
+			if err != nil {
+				return err
+			}
+			e.innerIter = chunk.NewIterator4List(e.innerList)
+			e.innerIter.Begin()
+		}
+
+		matched, isNull, err := e.joiner.tryToMatchInners(*e.outerRow, e.innerIter, req)
+		e.hasMatch = e.hasMatch || matched
+		e.hasNull = e.hasNull || isNull
+
+		if err != nil || req.IsFull() {
+			return err
+		}
+	}
+}
@@ -412,3 +412,140 @@ func (s *testSuiteJoin1) TestInjectProjOnTopN(c *C) {
 		"2",
 	))
 }
+
+func (s *testSuiteJoin1) TestSubquerySameTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert t values (1), (2)")
+	result := tk.MustQuery("select a from t where exists(select 1 from t as x where x.a < t.a)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select a from t where not exists(select 1 from t as x where x.a < t.a)")
+	result.Check(testkit.Rows("1"))
+}
+
+func (s *testSuiteJoin1) TestSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (c int, d int)")
+	tk.MustExec("insert t values (1, 1)")
+	tk.MustExec("insert t values (2, 2)")
+	tk.MustExec("insert t values (3, 4)")
+	tk.MustExec("commit")
+
+	result := tk.MustQuery("select * from t where exists(select * from t k where t.c = k.c having sum(c) = 1)")
+	result.Check(testkit.Rows("1 1"))
+	result = tk.MustQuery("select * from t where exists(select k.c, k.d from t k, t p where t.c = k.d)")
+	result.Check(testkit.Rows("1 1", "2 2"))
+	result = tk.MustQuery("select 1 = (select count(*) from t where t.c = k.d) from t k")
+	result.Check(testkit.Rows("1", "1", "0"))
+	result = tk.MustQuery("select 1 = (select count(*) from t where exists( select * from t m where t.c = k.d)) from t k")
+	result.Sort().Check(testkit.Rows("0", "1", "1"))
+	result = tk.MustQuery("select t.c = any (select count(*) from t) from t")
+	result.Sort().Check(testkit.Rows("0", "0", "1"))
+	result = tk.MustQuery("select * from t where (t.c, 6) = any (select count(*), sum(t.c) from t)")
+	result.Check(testkit.Rows("3 4"))
+	result = tk.MustQuery("select t.c from t where (t.c) < all (select count(*) from t)")
+	result.Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) = any (select * from t)")
+	result.Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) != all (select * from t)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select (select count(*) from t where t.c = k.d) from t k")
+	result.Sort().Check(testkit.Rows("0", "1", "1"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) in (select * from t)")
+	result.Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) not in (select * from t)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select * from t A inner join t B on A.c = B.c and A.c > 100")
+	result.Check(testkit.Rows())
+	// = all empty set is true
+	result = tk.MustQuery("select t.c from t where (t.c, t.d) != all (select * from t where d > 1000)")
+	result.Check(testkit.Rows("1", "2", "3"))
+	result = tk.MustQuery("select t.c from t where (t.c) < any (select c from t where d > 1000)")
+	result.Check(testkit.Rows())
+	tk.MustExec("insert t values (NULL, NULL)")
+	result = tk.MustQuery("select (t.c) < any (select c from t) from t")
+	result.Sort().Check(testkit.Rows("1", "1", "<nil>", "<nil>"))
+	result = tk.MustQuery("select (10) > all (select c from t) from t")
+	result.Check(testkit.Rows("<nil>", "<nil>", "<nil>", "<nil>"))
+	result = tk.MustQuery("select (c) > all (select c from t) from t")
+	result.Check(testkit.Rows("0", "0", "0", "<nil>"))
+
+	tk.MustExec("drop table if exists a")
+	tk.MustExec("create table a (c int, d int)")
+	tk.MustExec("insert a values (1, 2)")
+	tk.MustExec("drop table if exists b")
+	tk.MustExec("create table b (c int, d int)")
+	tk.MustExec("insert b values (2, 1)")
+
+	result = tk.MustQuery("select * from a b where c = (select d from b a where a.c = 2 and b.c = 1)")
+	result.Check(testkit.Rows("1 2"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(c int)")
+	tk.MustExec("insert t values(10), (8), (7), (9), (11)")
+	result = tk.MustQuery("select * from t where 9 in (select c from t s where s.c < t.c limit 3)")
+	result.Check(testkit.Rows("10"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, v int)")
+	tk.MustExec("insert into t values(1, 1), (2, 2), (3, 3)")
+	result = tk.MustQuery("select * from t where v=(select min(t1.v) from t t1, t t2, t t3 where t1.id=t2.id and t2.id=t3.id and t1.id=t.id)")
+	result.Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	result = tk.MustQuery("select exists (select t.id from t where s.id < 2 and t.id = s.id) from t s")
+	result.Sort().Check(testkit.Rows("0", "0", "1"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(c int)")
+	result = tk.MustQuery("select exists(select count(*) from t)")
+	result.Check(testkit.Rows("1"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, v int)")
+	tk.MustExec("insert into t values(1, 1), (2, 2), (3, 3)")
+	result = tk.MustQuery("select (select t.id from t where s.id = t.id) from t s")
+	result.Sort().Check(testkit.Rows("1", "2", "3"))
+	_, err := tk.Exec("select (select id from t) from t s")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select (select id from t where id = 2), (select v from t where id = 9)").Check(testkit.Rows("2 <nil>"))
+	tk.MustQuery("select * from t where id > (select avg(v) from t)").Check(testkit.Rows("3 3"))
+	tk.MustQuery("select * from t where exists (select * from t where v > 2)").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustQuery("select * from t where not exists (select * from t where v > 3)").Check(testkit.Rows("1 1", "2 2", "3 3"))
+}
+
+func (s *testSuiteJoin1) TestInSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert t values (1, 1), (2, 1)")
+	result := tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b from t as m2)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select m1.a from t as m1 where (3, m1.b) not in (select * from t as m2)")
+	result.Sort().Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b+1 from t as m2)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a in (select m2.b from t as m2 where m1.a = m2.a)")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select m1.a from t as m1 where m1.a not in (select m2.b from t as m2)")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select m1.a in (select m2.b from t as m2) from t as m1")
+	result.Sort().Check(testkit.Rows("0", "1"))
+
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert into t1 values (1), (2)")
+	tk.MustExec("insert into t2 values (1), (null)")
+	// `not in` with a null in the subquery never returns true.
+	result = tk.MustQuery("select * from t1 where a not in (select a from t2)")
+	result.Check(testkit.Rows())
+	result = tk.MustQuery("select a, a in (select a from t2), a not in (select a from t2) from t1")
+	result.Sort().Check(testkit.Rows("1 1 0", "2 <nil> <nil>"))
+	result = tk.MustQuery("select a, a in (select a from t2 where t2.a is not null) from t1")
+	result.Sort().Check(testkit.Rows("1 1", "2 0"))
+}
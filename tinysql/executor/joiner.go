@@ -25,6 +25,10 @@ var (
 	_ joiner = &leftOuterJoiner{}
 	_ joiner = &rightOuterJoiner{}
 	_ joiner = &innerJoiner{}
+	_ joiner = &semiJoiner{}
+	_ joiner = &antiSemiJoiner{}
+	_ joiner = &leftOuterSemiJoiner{}
+	_ joiner = &antiLeftOuterSemiJoiner{}
 )
 
 // joiner is used to generate join results according to the join type.
@@ -74,8 +78,20 @@ type joiner interface {
 	//      and appends it to the result buffer.
 	//   2. 'RightOuterJoin': concats the unmatched outer row with a row of NULLs
 	//      and appends it to the result buffer.
-	//   3. 'InnerJoin': ignores the unmatched outer row.
-	onMissMatch(outer chunk.Row, chk *chunk.Chunk)
+	//   3. 'SemiJoin': ignores the unmatched outer row.
+	//   4. 'AntiSemiJoin': appends the unmatched outer row to the result buffer.
+	//   5. 'LeftOuterSemiJoin': concats the unmatched outer row with 0 and
+	//      appends it to the result buffer.
+	//   6. 'AntiLeftOuterSemiJoin': concats the unmatched outer row with 1 and
+	//      appends it to the result buffer.
+	//   7. 'InnerJoin': ignores the unmatched outer row.
+	//
+	// Note that, for LeftOuterSemiJoin, AntiSemiJoin and AntiLeftOuterSemiJoin,
+	// we need to know the reason of outer row being treated as unmatched:
+	// whether the join condition returns false, or returns null, because
+	// it decides if this outer row should be outputted, hence we have a `hasNull`
+	// parameter passed to `onMissMatch`.
+	onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk)
 
 	// Clone deep copies a joiner.
 	Clone() joiner
@@ -93,6 +109,7 @@ func newJoiner(ctx sessionctx.Context, joinType plannercore.JoinType,
 	colTypes := make([]*types.FieldType, 0, len(lhsColTypes)+len(rhsColTypes))
 	colTypes = append(colTypes, lhsColTypes...)
 	colTypes = append(colTypes, rhsColTypes...)
+	shallowRowType := colTypes
 	base.selected = make([]bool, 0, chunk.InitialCapacity)
 	base.isNull = make([]bool, 0, chunk.InitialCapacity)
 	if joinType == plannercore.LeftOuterJoin || joinType == plannercore.RightOuterJoin {
@@ -103,6 +120,18 @@ func newJoiner(ctx sessionctx.Context, joinType plannercore.JoinType,
 		base.initDefaultInner(innerColTypes, defaultInner)
 	}
 	switch joinType {
+	case plannercore.SemiJoin:
+		base.shallowRow = chunk.MutRowFromTypes(shallowRowType)
+		return &semiJoiner{base}
+	case plannercore.AntiSemiJoin:
+		base.shallowRow = chunk.MutRowFromTypes(shallowRowType)
+		return &antiSemiJoiner{base}
+	case plannercore.LeftOuterSemiJoin:
+		base.shallowRow = chunk.MutRowFromTypes(shallowRowType)
+		return &leftOuterSemiJoiner{base}
+	case plannercore.AntiLeftOuterSemiJoin:
+		base.shallowRow = chunk.MutRowFromTypes(shallowRowType)
+		return &antiLeftOuterSemiJoiner{base}
 	case plannercore.LeftOuterJoin:
 		base.chk = chunk.NewChunkWithCapacity(colTypes, ctx.GetSessionVars().MaxChunkSize)
 		return &leftOuterJoiner{base}
@@ -142,6 +171,15 @@ func (j *baseJoiner) initDefaultInner(innerTypes []*types.FieldType, defaultInne
 	j.defaultInner = mutableRow.ToRow()
 }
 
+// makeShallowJoinRow shallow copies `inner` and `outer` into `shallowRow`.
+func (j *baseJoiner) makeShallowJoinRow(isRightJoin bool, inner, outer chunk.Row) {
+	if !isRightJoin {
+		inner, outer = outer, inner
+	}
+	j.shallowRow.ShallowCopyPartialRow(0, inner)
+	j.shallowRow.ShallowCopyPartialRow(inner.Len(), outer)
+}
+
 func (j *baseJoiner) makeJoinRowToChunk(chk *chunk.Chunk, lhs, rhs chunk.Row) {
 	// Call AppendRow() first to increment the virtual rows.
 	// Fix: https://github.com/pingcap/tidb/issues/5771
@@ -217,6 +255,287 @@ func (j *baseJoiner) Clone() baseJoiner {
 	return base
 }
 
+type semiJoiner struct {
+	baseJoiner
+}
+
+// tryToMatchInners implements joiner interface.
+func (j *semiJoiner) tryToMatchInners(outer chunk.Row, inners chunk.Iterator, chk *chunk.Chunk) (matched bool, hasNull bool, err error) {
+	if inners.Len() == 0 {
+		return false, false, nil
+	}
+
+	if len(j.conditions) == 0 {
+		chk.AppendPartialRow(0, outer)
+		inners.ReachEnd()
+		return true, false, nil
+	}
+
+	for inner := inners.Current(); inner != inners.End(); inner = inners.Next() {
+		j.makeShallowJoinRow(j.outerIsRight, inner, outer)
+
+		// For SemiJoin, we can safely treat null result of join conditions as false,
+		// so we ignore the nullness returned by EvalBool here.
+		matched, _, err = expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+		if err != nil {
+			return false, false, err
+		}
+		if matched {
+			chk.AppendPartialRow(0, outer)
+			inners.ReachEnd()
+			return true, false, nil
+		}
+	}
+	return false, false, nil
+}
+
+func (j *semiJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.Row, chk *chunk.Chunk, outerRowStatus []outerRowStatusFlag) (_ []outerRowStatusFlag, err error) {
+	outerRowStatus = outerRowStatus[:0]
+	outer, numToAppend := outers.Current(), chk.RequiredRows()-chk.NumRows()
+	for ; outer != outers.End() && numToAppend > 0; outer, numToAppend = outers.Next(), numToAppend-1 {
+		matched := true
+		if len(j.conditions) > 0 {
+			j.makeShallowJoinRow(j.outerIsRight, inner, outer)
+			matched, _, err = expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+			if err != nil {
+				return nil, err
+			}
+		}
+		if matched {
+			outerRowStatus = append(outerRowStatus, outerRowMatched)
+			chk.AppendPartialRow(0, outer)
+		} else {
+			outerRowStatus = append(outerRowStatus, outerRowUnmatched)
+		}
+	}
+	return outerRowStatus, nil
+}
+
+func (j *semiJoiner) onMissMatch(_ bool, outer chunk.Row, chk *chunk.Chunk) {
+}
+
+// Clone implements joiner interface.
+func (j *semiJoiner) Clone() joiner {
+	return &semiJoiner{baseJoiner: j.baseJoiner.Clone()}
+}
+
+type antiSemiJoiner struct {
+	baseJoiner
+}
+
+// tryToMatchInners implements joiner interface.
+func (j *antiSemiJoiner) tryToMatchInners(outer chunk.Row, inners chunk.Iterator, chk *chunk.Chunk) (matched bool, hasNull bool, err error) {
+	if inners.Len() == 0 {
+		return false, false, nil
+	}
+
+	if len(j.conditions) == 0 {
+		inners.ReachEnd()
+		return true, false, nil
+	}
+
+	for inner := inners.Current(); inner != inners.End(); inner = inners.Next() {
+		j.makeShallowJoinRow(j.outerIsRight, inner, outer)
+
+		matched, isNull, err := expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+		if err != nil {
+			return false, false, err
+		}
+		if matched {
+			inners.ReachEnd()
+			return true, false, nil
+		}
+		hasNull = hasNull || isNull
+	}
+	return false, hasNull, nil
+}
+
+func (j *antiSemiJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.Row, chk *chunk.Chunk, outerRowStatus []outerRowStatusFlag) (_ []outerRowStatusFlag, err error) {
+	outerRowStatus = outerRowStatus[:0]
+	outer, numToAppend := outers.Current(), chk.RequiredRows()-chk.NumRows()
+	for ; outer != outers.End() && numToAppend > 0; outer, numToAppend = outers.Next(), numToAppend-1 {
+		matched, isNull := true, false
+		if len(j.conditions) > 0 {
+			j.makeShallowJoinRow(j.outerIsRight, inner, outer)
+			matched, isNull, err = expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+			if err != nil {
+				return nil, err
+			}
+		}
+		if matched {
+			outerRowStatus = append(outerRowStatus, outerRowMatched)
+		} else if isNull {
+			outerRowStatus = append(outerRowStatus, outerRowHasNull)
+		} else {
+			outerRowStatus = append(outerRowStatus, outerRowUnmatched)
+		}
+	}
+	return outerRowStatus, nil
+}
+
+func (j *antiSemiJoiner) onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk) {
+	if !hasNull {
+		chk.AppendRow(outer)
+	}
+}
+
+// Clone implements joiner interface.
+func (j *antiSemiJoiner) Clone() joiner {
+	return &antiSemiJoiner{baseJoiner: j.baseJoiner.Clone()}
+}
+
+type leftOuterSemiJoiner struct {
+	baseJoiner
+}
+
+// tryToMatchInners implements joiner interface.
+func (j *leftOuterSemiJoiner) tryToMatchInners(outer chunk.Row, inners chunk.Iterator, chk *chunk.Chunk) (matched bool, hasNull bool, err error) {
+	if inners.Len() == 0 {
+		return false, false, nil
+	}
+
+	if len(j.conditions) == 0 {
+		j.onMatch(outer, chk)
+		inners.ReachEnd()
+		return true, false, nil
+	}
+
+	for inner := inners.Current(); inner != inners.End(); inner = inners.Next() {
+		j.makeShallowJoinRow(false, inner, outer)
+
+		matched, isNull, err := expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+		if err != nil {
+			return false, false, err
+		}
+		if matched {
+			j.onMatch(outer, chk)
+			inners.ReachEnd()
+			return true, false, nil
+		}
+		hasNull = hasNull || isNull
+	}
+	return false, hasNull, nil
+}
+
+func (j *leftOuterSemiJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.Row, chk *chunk.Chunk, outerRowStatus []outerRowStatusFlag) (_ []outerRowStatusFlag, err error) {
+	outerRowStatus = outerRowStatus[:0]
+	outer, numToAppend := outers.Current(), chk.RequiredRows()-chk.NumRows()
+	for ; outer != outers.End() && numToAppend > 0; outer, numToAppend = outers.Next(), numToAppend-1 {
+		matched, isNull := true, false
+		if len(j.conditions) > 0 {
+			j.makeShallowJoinRow(false, inner, outer)
+			matched, isNull, err = expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+			if err != nil {
+				return nil, err
+			}
+		}
+		if matched {
+			outerRowStatus = append(outerRowStatus, outerRowMatched)
+			j.onMatch(outer, chk)
+		} else if isNull {
+			outerRowStatus = append(outerRowStatus, outerRowHasNull)
+		} else {
+			outerRowStatus = append(outerRowStatus, outerRowUnmatched)
+		}
+	}
+	return outerRowStatus, nil
+}
+
+func (j *leftOuterSemiJoiner) onMatch(outer chunk.Row, chk *chunk.Chunk) {
+	chk.AppendPartialRow(0, outer)
+	chk.AppendInt64(outer.Len(), 1)
+}
+
+func (j *leftOuterSemiJoiner) onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk) {
+	chk.AppendPartialRow(0, outer)
+	if hasNull {
+		chk.AppendNull(outer.Len())
+	} else {
+		chk.AppendInt64(outer.Len(), 0)
+	}
+}
+
+// Clone implements joiner interface.
+func (j *leftOuterSemiJoiner) Clone() joiner {
+	return &leftOuterSemiJoiner{baseJoiner: j.baseJoiner.Clone()}
+}
+
+type antiLeftOuterSemiJoiner struct {
+	baseJoiner
+}
+
+// tryToMatchInners implements joiner interface.
+func (j *antiLeftOuterSemiJoiner) tryToMatchInners(outer chunk.Row, inners chunk.Iterator, chk *chunk.Chunk) (matched bool, hasNull bool, err error) {
+	if inners.Len() == 0 {
+		return false, false, nil
+	}
+
+	if len(j.conditions) == 0 {
+		j.onMatch(outer, chk)
+		inners.ReachEnd()
+		return true, false, nil
+	}
+
+	for inner := inners.Current(); inner != inners.End(); inner = inners.Next() {
+		j.makeShallowJoinRow(false, inner, outer)
+
+		matched, isNull, err := expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+		if err != nil {
+			return false, false, err
+		}
+		if matched {
+			j.onMatch(outer, chk)
+			inners.ReachEnd()
+			return true, false, nil
+		}
+		hasNull = hasNull || isNull
+	}
+	return false, hasNull, nil
+}
+
+func (j *antiLeftOuterSemiJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.Row, chk *chunk.Chunk, outerRowStatus []outerRowStatusFlag) (_ []outerRowStatusFlag, err error) {
+	outerRowStatus = outerRowStatus[:0]
+	outer, numToAppend := outers.Current(), chk.RequiredRows()-chk.NumRows()
+	for ; outer != outers.End() && numToAppend > 0; outer, numToAppend = outers.Next(), numToAppend-1 {
+		matched, isNull := true, false
+		if len(j.conditions) > 0 {
+			j.makeShallowJoinRow(false, inner, outer)
+			matched, isNull, err = expression.EvalBool(j.ctx, j.conditions, j.shallowRow.ToRow())
+			if err != nil {
+				return nil, err
+			}
+		}
+		if matched {
+			outerRowStatus = append(outerRowStatus, outerRowMatched)
+			j.onMatch(outer, chk)
+		} else if isNull {
+			outerRowStatus = append(outerRowStatus, outerRowHasNull)
+		} else {
+			outerRowStatus = append(outerRowStatus, outerRowUnmatched)
+		}
+	}
+	return outerRowStatus, nil
+}
+
+func (j *antiLeftOuterSemiJoiner) onMatch(outer chunk.Row, chk *chunk.Chunk) {
+	chk.AppendPartialRow(0, outer)
+	chk.AppendInt64(outer.Len(), 0)
+}
+
+func (j *antiLeftOuterSemiJoiner) onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk) {
+	chk.AppendPartialRow(0, outer)
+	if hasNull {
+		chk.AppendNull(outer.Len())
+	} else {
+		chk.AppendInt64(outer.Len(), 1)
+	}
+}
+
+// Clone implements joiner interface.
+func (j *antiLeftOuterSemiJoiner) Clone() joiner {
+	return &antiLeftOuterSemiJoiner{baseJoiner: j.baseJoiner.Clone()}
+}
+
 type leftOuterJoiner struct {
 	baseJoiner
 }
@@ -271,7 +590,7 @@ func (j *leftOuterJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.Ro
 	return j.filterAndCheckOuterRowStatus(chkForJoin, chk, inner.Len(), outerRowStatus)
 }
 
-func (j *leftOuterJoiner) onMissMatch(outer chunk.Row, chk *chunk.Chunk) {
+func (j *leftOuterJoiner) onMissMatch(_ bool, outer chunk.Row, chk *chunk.Chunk) {
 	chk.AppendPartialRow(0, outer)
 	chk.AppendPartialRow(outer.Len(), j.defaultInner)
 }
@@ -334,7 +653,7 @@ func (j *rightOuterJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.R
 	return j.filterAndCheckOuterRowStatus(chkForJoin, chk, inner.Len(), outerRowStatus)
 }
 
-func (j *rightOuterJoiner) onMissMatch(outer chunk.Row, chk *chunk.Chunk) {
+func (j *rightOuterJoiner) onMissMatch(_ bool, outer chunk.Row, chk *chunk.Chunk) {
 	chk.AppendPartialRow(0, j.defaultInner)
 	chk.AppendPartialRow(j.defaultInner.Len(), outer)
 }
@@ -402,7 +721,7 @@ func (j *innerJoiner) tryToMatchOuters(outers chunk.Iterator, inner chunk.Row, c
 	return j.filterAndCheckOuterRowStatus(chkForJoin, chk, inner.Len(), outerRowStatus)
 }
 
-func (j *innerJoiner) onMissMatch(outer chunk.Row, chk *chunk.Chunk) {
+func (j *innerJoiner) onMissMatch(_ bool, outer chunk.Row, chk *chunk.Chunk) {
 }
 
 func (j *innerJoiner) Clone() joiner {
@@ -0,0 +1,369 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var (
+	_ Executor = &LoadDataExec{}
+)
+
+// LoadDataExec represents a load data executor. It doesn't read the file by
+// itself, it saves the LoadDataInfo in the session, then the server requests
+// the file from the client and inserts the content by the LoadDataInfo.
+type LoadDataExec struct {
+	baseExecutor
+
+	IsLocal      bool
+	loadDataInfo *LoadDataInfo
+}
+
+// Next implements the Executor Next interface.
+func (e *LoadDataExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	// The file on the server is read without the FILE privilege check,
+	// so only the file sent by the client is supported.
+	if !e.IsLocal {
+		return errors.New("Load Data: don't support load data without local field")
+	}
+	if len(e.loadDataInfo.FieldsInfo.Terminated) == 0 {
+		return errors.New("Load Data: don't support load data terminated is nil")
+	}
+	if len(e.loadDataInfo.LinesInfo.Terminated) == 0 {
+		return errors.New("Load Data: don't support load data lines terminated is nil")
+	}
+	if len(e.loadDataInfo.Path) == 0 {
+		return errors.New("Load Data: infile path is empty")
+	}
+
+	sctx := e.loadDataInfo.Ctx
+	if val := sctx.Value(LoadDataVarKey); val != nil {
+		sctx.SetValue(LoadDataVarKey, nil)
+		return errors.New("Load Data: previous load data option isn't closed normal")
+	}
+	sctx.SetValue(LoadDataVarKey, e.loadDataInfo)
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *LoadDataExec) Close() error {
+	return nil
+}
+
+// Open implements the Executor Open interface.
+func (e *LoadDataExec) Open(ctx context.Context) error {
+	e.loadDataInfo.initEvalBuffer()
+	return nil
+}
+
+// LoadDataInfo saves the information of loading data operation.
+type LoadDataInfo struct {
+	*InsertValues
+
+	Path        string
+	OnDuplicate ast.OnDuplicateKeyHandlingType
+	FieldsInfo  *ast.FieldsClause
+	LinesInfo   *ast.LinesClause
+	IgnoreLines uint64
+	Ctx         sessionctx.Context
+
+	// maxRowsInBatch is the max number of rows inserted in a transaction,
+	// 0 means all the rows are inserted in one transaction.
+	maxRowsInBatch uint64
+	rows           [][]types.Datum
+}
+
+// SetMaxRowsInBatch sets the max number of rows to insert in a batch.
+func (e *LoadDataInfo) SetMaxRowsInBatch(limit uint64) {
+	e.maxRowsInBatch = limit
+	e.rows = make([][]types.Datum, 0, limit)
+}
+
+// field is a parsed field of a line.
+type field struct {
+	str    []byte
+	isNull bool
+}
+
+// InsertData parses the lines in prevData and curData into rows of the current
+// batch. prevData is the data returned by the last call, which doesn't contain
+// a complete line, an empty curData means the end of the file, so the last
+// line doesn't need a terminator. It returns the data not parsed yet, and
+// whether the batch is full, in which case the caller should insert the batch
+// by CheckAndInsertOneBatch and call InsertData with the returned data again.
+func (e *LoadDataInfo) InsertData(ctx context.Context, prevData, curData []byte) ([]byte, bool, error) {
+	isEOF := len(curData) == 0
+	data := prevData
+	if !isEOF {
+		data = append(prevData, curData...)
+	}
+	for len(data) > 0 {
+		if e.isBatchFull() {
+			return data, true, nil
+		}
+		fields, n, ok := e.readLine(data, isEOF)
+		if !ok {
+			break
+		}
+		data = data[n:]
+		// The line without the starting prefix is skipped.
+		if fields == nil {
+			continue
+		}
+		if e.IgnoreLines > 0 {
+			e.IgnoreLines--
+			continue
+		}
+		if row := e.colsToRow(ctx, fields); row != nil {
+			e.rows = append(e.rows, row)
+		}
+	}
+	return data, e.isBatchFull(), nil
+}
+
+func (e *LoadDataInfo) isBatchFull() bool {
+	return e.maxRowsInBatch > 0 && uint64(len(e.rows)) >= e.maxRowsInBatch
+}
+
+// CheckAndInsertOneBatch inserts the rows of the current batch into the table.
+// The rows with duplicate keys replace the old rows if REPLACE is specified,
+// otherwise they are skipped with warnings.
+func (e *LoadDataInfo) CheckAndInsertOneBatch(ctx context.Context) error {
+	if len(e.rows) == 0 {
+		return nil
+	}
+	rows := e.rows
+	e.rows = make([][]types.Datum, 0, cap(rows))
+	sc := e.ctx.GetSessionVars().StmtCtx
+	if e.OnDuplicate == ast.OnDuplicateKeyHandlingReplace {
+		replaceExec := &ReplaceExec{InsertValues: e.InsertValues}
+		if err := replaceExec.exec(ctx, rows); err != nil {
+			return err
+		}
+		sc.AddCopiedRows(uint64(len(rows)))
+		return nil
+	}
+	// The client keeps sending the file after an error occurs, so LOAD DATA
+	// LOCAL skips the rows with duplicate keys as if IGNORE is specified.
+	sc.AddRecordRows(uint64(len(rows)))
+	return e.batchCheckAndInsert(ctx, rows, e.addRecord)
+}
+
+// Message returns the info message of the LOAD DATA statement.
+func (e *LoadDataInfo) Message() string {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	numRecords := sc.RecordRows()
+	numSkipped := numRecords - sc.CopiedRows()
+	return fmt.Sprintf(mysql.MySQLErrName[mysql.ErrLoadInfo], numRecords, 0, numSkipped, sc.WarningCount())
+}
+
+// colsToRow converts the fields of a line to a row of the table. The columns
+// without fields are filled with the default values, the extra fields are
+// discarded. A nil row is returned if the fields can't be converted.
+func (e *LoadDataInfo) colsToRow(ctx context.Context, fields []field) []types.Datum {
+	e.rowCount++
+	sc := e.ctx.GetSessionVars().StmtCtx
+	if len(fields) < len(e.insertColumns) {
+		sc.AppendWarning(ErrWarnTooFewRecords.GenWithStackByArgs(e.rowCount))
+	} else if len(fields) > len(e.insertColumns) {
+		sc.AppendWarning(ErrWarnTooManyRecords.GenWithStackByArgs(e.rowCount))
+		fields = fields[:len(e.insertColumns)]
+	}
+	vals := make([]types.Datum, len(fields))
+	for i, f := range fields {
+		if f.isNull {
+			vals[i].SetNull()
+		} else {
+			vals[i].SetString(string(f.str))
+		}
+	}
+	row, err := e.getRow(ctx, vals)
+	if err != nil {
+		sc.AddRecordRows(1)
+		sc.AppendWarning(err)
+		return nil
+	}
+	return row
+}
+
+// readLine parses the first line of data into fields. It returns the fields,
+// the length of the line including the terminator, and false if the line is
+// incomplete and more data is needed. The fields are nil if the line doesn't
+// contain the starting prefix and should be skipped.
+func (e *LoadDataInfo) readLine(data []byte, isEOF bool) ([]field, int, bool) {
+	lineTerm := []byte(e.LinesInfo.Terminated)
+	pos := 0
+	if starting := []byte(e.LinesInfo.Starting); len(starting) > 0 {
+		idx := bytes.Index(data, starting)
+		end := bytes.Index(data, lineTerm)
+		if idx < 0 || (end >= 0 && end < idx) {
+			switch {
+			case end >= 0:
+				return nil, end + len(lineTerm), true
+			case isEOF:
+				return nil, len(data), true
+			default:
+				return nil, 0, false
+			}
+		}
+		pos = idx + len(starting)
+	}
+
+	var fields []field
+	for {
+		f, n, lineEnd, ok := e.readField(data[pos:], isEOF)
+		if !ok {
+			return nil, 0, false
+		}
+		fields = append(fields, f)
+		pos += n
+		if lineEnd {
+			return fields, pos, true
+		}
+	}
+}
+
+// readField parses a field at the beginning of data. It returns the field,
+// the length of the field including the terminator, whether the field is the
+// last one of the line, and false if more data is needed.
+func (e *LoadDataInfo) readField(data []byte, isEOF bool) (field, int, bool, bool) {
+	fieldTerm, lineTerm := []byte(e.FieldsInfo.Terminated), []byte(e.LinesInfo.Terminated)
+	enclosed, escaped := e.FieldsInfo.Enclosed, e.FieldsInfo.Escaped
+
+	var (
+		f        field
+		pos      int
+		inQuotes bool
+		quoted   bool
+	)
+	if enclosed != 0 && len(data) > 0 && data[0] == enclosed {
+		inQuotes, quoted = true, true
+		pos++
+	}
+	finish := func(termLen int, lineEnd bool) (field, int, bool, bool) {
+		raw := data[:pos]
+		if !quoted {
+			// The unquoted \N and the unquoted word NULL if the fields can be
+			// enclosed mean NULL values.
+			if escaped != 0 && len(raw) == 2 && raw[0] == escaped && raw[1] == 'N' {
+				f.isNull = true
+			} else if enclosed != 0 && string(raw) == "NULL" {
+				f.isNull = true
+			}
+		}
+		return f, pos + termLen, lineEnd, true
+	}
+	for {
+		if pos >= len(data) {
+			if !isEOF {
+				return f, 0, false, false
+			}
+			return finish(0, true)
+		}
+		c := data[pos]
+		if escaped != 0 && c == escaped {
+			if pos+1 >= len(data) {
+				if !isEOF {
+					return f, 0, false, false
+				}
+				f.str = append(f.str, c)
+				pos++
+				continue
+			}
+			f.str = append(f.str, unescapeChar(data[pos+1]))
+			pos += 2
+			continue
+		}
+		if inQuotes {
+			if c == enclosed {
+				// A doubled enclosed character means the character itself.
+				if pos+1 < len(data) && data[pos+1] == enclosed {
+					f.str = append(f.str, c)
+					pos += 2
+					continue
+				}
+				if pos+1 >= len(data) && !isEOF {
+					return f, 0, false, false
+				}
+				inQuotes = false
+				pos++
+				continue
+			}
+			f.str = append(f.str, c)
+			pos++
+			continue
+		}
+		rest := data[pos:]
+		if !isEOF && (isPartialPrefix(rest, lineTerm) || isPartialPrefix(rest, fieldTerm)) {
+			return f, 0, false, false
+		}
+		if bytes.HasPrefix(rest, lineTerm) {
+			return finish(len(lineTerm), true)
+		}
+		if bytes.HasPrefix(rest, fieldTerm) {
+			return finish(len(fieldTerm), false)
+		}
+		f.str = append(f.str, c)
+		pos++
+	}
+}
+
+// isPartialPrefix checks whether data is a proper prefix of term, in which
+// case more data is needed to tell whether data starts with term.
+func isPartialPrefix(data, term []byte) bool {
+	return len(data) < len(term) && bytes.HasPrefix(term, data)
+}
+
+// unescapeChar returns the character represented by the escape sequence
+// consisting of the escape character and c.
+// See https://dev.mysql.com/doc/refman/5.7/en/load-data.html
+func unescapeChar(c byte) byte {
+	switch c {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	}
+	return c
+}
+
+// loadDataVarKeyType is a dummy type to avoid naming collision in context.
+type loadDataVarKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k loadDataVarKeyType) String() string {
+	return "load_data_var"
+}
+
+// LoadDataVarKey is a variable key for load data.
+const LoadDataVarKey loadDataVarKeyType = 0
@@ -1,7 +1,18 @@
 package executor
 
 import (
+	"context"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/stringutil"
 )
 
 var _ = Suite(&pkgTestSuite{})
@@ -38,3 +49,55 @@ func (s *pkgTestSuite) TestMoveInfoSchemaToFront(c *C) {
 		}
 	}
 }
+
+func (s *pkgTestSuite) TestHashAggSpillOnlyWhenExceedingQuota(c *C) {
+	ctx := mock.NewContext()
+	sessionVars := ctx.GetSessionVars()
+	sessionVars.InitChunkSize = 32
+	sessionVars.MaxChunkSize = 32
+	sessionVars.OOMAction = variable.OOMActionSpill
+	cols := []*expression.Column{
+		{Index: 0, RetType: types.NewFieldType(mysql.TypeDouble)},
+		{Index: 1, RetType: types.NewFieldType(mysql.TypeLonglong)},
+	}
+	aggFunc, err := aggregation.NewAggFuncDesc(ctx, ast.AggFuncCount, []expression.Expression{cols[0]})
+	c.Assert(err, IsNil)
+	dataSource := buildMockDataSource(mockDataSourceParameters{
+		schema: expression.NewSchema(cols...),
+		genDataFunc: func(row int, typ *types.FieldType) interface{} {
+			if typ.Tp == mysql.TypeDouble {
+				return float64(row)
+			}
+			return int64(row % 500)
+		},
+		rows: 2000,
+		ctx:  ctx,
+	})
+
+	for _, quota := range []int64{-1, 1} {
+		sessionVars.StmtCtx.MemTracker = memory.NewTracker(stringutil.StringerStr("testHashAgg"), quota)
+		sessionVars.StmtCtx.DiskTracker = memory.NewTracker(stringutil.StringerStr("testHashAgg"), -1)
+		dataSource.prepareChunks()
+		aggExec := buildHashAggExecutor(ctx, dataSource, expression.NewSchema(cols[:1]...), []*aggregation.AggFuncDesc{aggFunc}, []expression.Expression{cols[1]})
+		c.Assert(aggExec.Open(context.Background()), IsNil)
+		chk := newFirstChunk(aggExec)
+		var groups, count int64
+		for {
+			c.Assert(aggExec.Next(context.Background(), chk), IsNil)
+			if chk.NumRows() == 0 {
+				break
+			}
+			groups += int64(chk.NumRows())
+			for i := 0; i < chk.NumRows(); i++ {
+				count += chk.GetRow(i).GetInt64(0)
+			}
+		}
+		c.Assert(groups, Equals, int64(500))
+		c.Assert(count, Equals, int64(2000))
+		hashAgg := aggExec.(*HashAggExec)
+		c.Assert(hashAgg.spillMode, Equals, quota > 0)
+		c.Assert(sessionVars.StmtCtx.DiskTracker.MaxConsumed() > 0, Equals, quota > 0)
+		c.Assert(aggExec.Close(), IsNil)
+		c.Assert(sessionVars.StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite1) TestPrepared(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists prepare_test")
+	tk.MustExec("create table prepare_test (id int primary key, c1 int, c2 varchar(20))")
+	tk.MustExec("insert prepare_test values (1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c')")
+
+	tk.MustExec(`prepare stmt_test_1 from 'select id from prepare_test where id > ?'`)
+	tk.MustExec(`set @a = 1`)
+	tk.MustQuery(`execute stmt_test_1 using @a`).Check(testkit.Rows("2", "3"))
+	tk.MustExec(`set @a = 2`)
+	tk.MustQuery(`execute stmt_test_1 using @a`).Check(testkit.Rows("3"))
+
+	// The parameters are ordered by their position in the SQL text.
+	tk.MustExec(`prepare stmt_test_2 from 'select c2 from prepare_test where c1 > ? and id < ?'`)
+	tk.MustExec(`set @a = 1, @b = 3`)
+	tk.MustQuery(`execute stmt_test_2 using @a, @b`).Check(testkit.Rows("b"))
+
+	// Prepare from a user variable.
+	tk.MustExec(`set @sql = 'select c2 from prepare_test where id = ?'`)
+	tk.MustExec(`prepare stmt_test_3 from @sql`)
+	tk.MustExec(`set @a = 3`)
+	tk.MustQuery(`execute stmt_test_3 using @a`).Check(testkit.Rows("c"))
+
+	// Parameter marker in LIMIT clause.
+	tk.MustExec(`prepare stmt_test_4 from 'select id from prepare_test order by id limit ?'`)
+	tk.MustExec(`set @a = 2`)
+	tk.MustQuery(`execute stmt_test_4 using @a`).Check(testkit.Rows("1", "2"))
+
+	// DML statements.
+	tk.MustExec(`prepare stmt_test_5 from 'update prepare_test set c2 = ? where id = ?'`)
+	tk.MustExec(`set @a = 'x', @b = 1`)
+	tk.MustExec(`execute stmt_test_5 using @a, @b`)
+	tk.MustQuery(`select c2 from prepare_test where id = 1`).Check(testkit.Rows("x"))
+
+	// Wrong parameter count.
+	_, err := tk.Exec(`execute stmt_test_1`)
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrWrongParamCount), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`execute stmt_test_1 using @a, @b`)
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrWrongParamCount), IsTrue, Commentf("err %v", err))
+
+	// Re-preparing with the same name replaces the old statement.
+	tk.MustExec(`prepare stmt_test_1 from 'select c1 from prepare_test where id = ?'`)
+	tk.MustExec(`set @a = 2`)
+	tk.MustQuery(`execute stmt_test_1 using @a`).Check(testkit.Rows("2"))
+
+	tk.MustExec(`deallocate prepare stmt_test_1`)
+	_, err = tk.Exec(`execute stmt_test_1 using @a`)
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`deallocate prepare stmt_test_1`)
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrStmtNotFound), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`drop prepare stmt_test_2`)
+
+	_, err = tk.Exec(`prepare stmt_test_6 from 'select 1; select 2'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPrepareMulti), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`prepare stmt_test_6 from 'create table t (a int)'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPrepareDDL), IsTrue, Commentf("err %v", err))
+
+	// Parameter markers are only allowed in prepared statements.
+	_, err = tk.Exec(`select ?`)
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite1) TestPreparedPlanCache(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, c int, key idx_b(b))")
+	tk.MustExec("insert t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+	checkCounters := func(hits, misses string) {
+		tk.MustQuery("select variable_value from information_schema.session_status " +
+			"where variable_name in ('Plan_cache_hits', 'Plan_cache_misses') order by variable_name").Check(testkit.Rows(hits, misses))
+	}
+	checkCounters("0", "0")
+
+	// The ranges of the cached plans are rebuilt for the new parameters.
+	tk.MustExec(`prepare stmt1 from 'select a from t where a > ? and a < ?'`)
+	tk.MustExec(`set @a = 1, @b = 4`)
+	tk.MustQuery(`execute stmt1 using @a, @b`).Check(testkit.Rows("2", "3"))
+	tk.MustExec(`set @a = 2, @b = 5`)
+	tk.MustQuery(`execute stmt1 using @a, @b`).Check(testkit.Rows("3", "4"))
+	checkCounters("1", "1")
+
+	tk.MustExec(`prepare stmt2 from 'select b, c from t use index(idx_b) where b >= ?'`)
+	tk.MustExec(`set @a = 3`)
+	tk.MustQuery(`execute stmt2 using @a`).Sort().Check(testkit.Rows("3 3", "4 4"))
+	tk.MustExec(`set @a = 2`)
+	tk.MustQuery(`execute stmt2 using @a`).Sort().Check(testkit.Rows("2 2", "3 3", "4 4"))
+	checkCounters("2", "2")
+
+	// The statements with the same text share the cached plan.
+	tk.MustExec(`prepare stmt3 from 'SELECT a FROM t WHERE a > ?  AND a < ?'`)
+	tk.MustExec(`set @a = 0, @b = 2`)
+	tk.MustQuery(`execute stmt3 using @a, @b`).Check(testkit.Rows("1"))
+	checkCounters("3", "2")
+
+	// The user variables are strings, the integer parameters of the binary
+	// protocol can't reuse the plan.
+	stmtID, _, _, err := tk.Se.PrepareStmt("select a from t where a > ? and a < ?")
+	c.Assert(err, IsNil)
+	rs, err := tk.Se.ExecutePreparedStmt(context.Background(), stmtID, types.MakeDatums(1, 3))
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("execute with 1, 3")).Check(testkit.Rows("2"))
+	rs, err = tk.Se.ExecutePreparedStmt(context.Background(), stmtID, types.MakeDatums(0, 2))
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("execute with 0, 2")).Check(testkit.Rows("1"))
+	checkCounters("4", "3")
+
+	// DML statements.
+	tk.MustExec(`prepare stmt4 from 'update t set c = ? where a = ?'`)
+	tk.MustExec(`set @a = 10, @b = 1`)
+	tk.MustExec(`execute stmt4 using @a, @b`)
+	tk.MustExec(`set @a = 20, @b = 2`)
+	tk.MustExec(`execute stmt4 using @a, @b`)
+	tk.MustExec(`prepare stmt5 from 'insert into t values (?, ?, ?)'`)
+	tk.MustExec(`set @a = 5, @b = 5, @c = 5`)
+	tk.MustExec(`execute stmt5 using @a, @b, @c`)
+	tk.MustExec(`set @a = 6, @b = 6, @c = 6`)
+	tk.MustExec(`execute stmt5 using @a, @b, @c`)
+	tk.MustQuery(`select * from t`).Check(testkit.Rows("1 1 10", "2 2 20", "3 3 3", "4 4 4", "5 5 5", "6 6 6"))
+	checkCounters("6", "5")
+
+	// The plan is not cached when it depends on the values of the parameters.
+	stmtID, _, _, err = tk.Se.PrepareStmt("select a from t where a = ? and a = ?")
+	c.Assert(err, IsNil)
+	rs, err = tk.Se.ExecutePreparedStmt(context.Background(), stmtID, types.MakeDatums(1, 2))
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("execute with 1, 2")).Check(testkit.Rows())
+	rs, err = tk.Se.ExecutePreparedStmt(context.Background(), stmtID, types.MakeDatums(1, 1))
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("execute with 1, 1")).Check(testkit.Rows("1"))
+	checkCounters("6", "7")
+
+	// The uncacheable statements are not counted.
+	tk.MustExec(`prepare stmt7 from 'select a from t where a > ? order by a limit ?'`)
+	tk.MustExec(`set @a = 1, @b = 2`)
+	tk.MustQuery(`execute stmt7 using @a, @b`).Check(testkit.Rows("2", "3"))
+	tk.MustExec(`prepare stmt8 from 'select a from t where a > ? and a < unix_timestamp()'`)
+	tk.MustQuery(`execute stmt8 using @a`).Check(testkit.Rows("2", "3", "4", "5", "6"))
+	checkCounters("6", "7")
+
+	// The cached plans are invalidated by the schema changes and the system variables.
+	tk.MustExec(`set @a = 1, @b = 4`)
+	tk.MustExec(`alter table t add column d int`)
+	tk.MustQuery(`execute stmt1 using @a, @b`).Check(testkit.Rows("2", "3"))
+	checkCounters("6", "8")
+	tk.MustExec(`set @@tidb_opt_agg_push_down = 1`)
+	tk.MustQuery(`execute stmt1 using @a, @b`).Check(testkit.Rows("2", "3"))
+	tk.MustQuery(`execute stmt1 using @a, @b`).Check(testkit.Rows("2", "3"))
+	checkCounters("7", "9")
+
+	tk.MustExec(`set @@tidb_enable_prepared_plan_cache = 0`)
+	tk.MustQuery(`execute stmt1 using @a, @b`).Check(testkit.Rows("2", "3"))
+	checkCounters("7", "9")
+	_, err = tk.Exec(`set @@tidb_prepared_plan_cache_size = 0`)
+	c.Assert(err, NotNil)
+}
@@ -321,6 +321,11 @@ func (f *projectionInputFetcher) run(ctx context.Context) {
 	defer func() {
 		if r := recover(); r != nil {
 			recoveryProjection(output, r)
+			// The output has not been dispatched to the main thread yet, send
+			// it so that the error is not lost.
+			if output != nil {
+				f.globalOutputCh <- output
+			}
 		}
 		close(f.globalOutputCh)
 		f.proj.wg.Done()
@@ -20,8 +20,10 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"strings"
 	"sync/atomic"
@@ -30,6 +32,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
@@ -208,3 +211,66 @@ func (s *seqTestSuite) TestMaxDeltaSchemaCount(c *C) {
 	c.Assert(variable.GetMaxDeltaSchemaCount(), Equals, int64(2048))
 	tk.MustQuery("select @@global.tidb_max_delta_schema_count").Check(testkit.Rows("2048"))
 }
+
+func (s *seqTestSuite) TestSetCostProfile(c *C) {
+	dir, err := ioutil.TempDir("", "cost_profile")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	profile := variable.NewCostProfile()
+	profile.SeekFactor = 12.5
+	profile.MemoryFactor = 0.002
+	c.Assert(profile.Save(filepath.Join(dir, "profile.json")), IsNil)
+	c.Assert(variable.NewCostProfile().Save(filepath.Join(dir, "default.json")), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{"tidb_opt_scan_factor": "a"}`), 0644), IsNil)
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	gvc := domain.GetDomain(tk.Se).GetGlobalVarsCache()
+	gvc.Disable()
+
+	// Loading cost profiles is disabled if the cost-profile-dir is not configured.
+	_, err = tk.Exec("set @@global.tidb_opt_cost_profile = 'profile.json'")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select @@global.tidb_opt_cost_profile").Check(testkit.Rows(""))
+
+	originCfg := config.GetGlobalConfig()
+	newCfg := *originCfg
+	newCfg.CostProfileDir = dir
+	config.StoreGlobalConfig(&newCfg)
+	defer config.StoreGlobalConfig(originCfg)
+
+	tk.MustExec("set @@global.tidb_opt_cost_profile = 'profile.json'")
+	tk.MustQuery("select @@global.tidb_opt_cost_profile").Check(testkit.Rows("profile.json"))
+	tk.MustQuery("select @@global.tidb_opt_seek_factor, @@global.tidb_opt_memory_factor, @@global.tidb_opt_cpu_factor").
+		Check(testkit.Rows("12.5 0.002 3"))
+	// The current session is not affected, the new sessions use the factors in the profile.
+	c.Assert(tk.Se.GetSessionVars().SeekFactor, Equals, variable.DefOptSeekFactor)
+	tk.Se = nil
+	tk.MustExec("use test")
+	c.Assert(tk.Se.GetSessionVars().SeekFactor, Equals, 12.5)
+	c.Assert(tk.Se.GetSessionVars().MemoryFactor, Equals, 0.002)
+
+	// Only the files in the cost-profile-dir can be loaded, and the reason of
+	// a failed load is not returned to the client.
+	for _, name := range []string{
+		filepath.Join(dir, "default.json"),
+		"../default.json",
+		".hidden.json",
+		"nonexistent.json",
+		"invalid.json",
+	} {
+		_, err = tk.Exec(fmt.Sprintf("set @@global.tidb_opt_cost_profile = '%s'", name))
+		c.Assert(terror.ErrorEqual(err, variable.ErrWrongValueForVar), IsTrue, Commentf("%s: %v", name, err))
+	}
+	_, err = tk.Exec("set @@tidb_opt_cost_profile = 'profile.json'")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select @@global.tidb_opt_cost_profile, @@global.tidb_opt_seek_factor").Check(testkit.Rows("profile.json 12.5"))
+
+	tk.MustExec("set @@global.tidb_opt_cost_profile = 'default.json'")
+	tk.MustQuery("select @@global.tidb_opt_seek_factor, @@global.tidb_opt_memory_factor").Check(testkit.Rows("20 0.001"))
+	tk.MustExec("set @@global.tidb_opt_cost_profile = ''")
+	tk.MustQuery("select @@global.tidb_opt_cost_profile, @@global.tidb_opt_seek_factor").Check(testkit.Rows(" 20"))
+	tk.Se = nil
+	tk.MustExec("use test")
+	c.Assert(tk.Se.GetSessionVars().SeekFactor, Equals, variable.DefOptSeekFactor)
+}
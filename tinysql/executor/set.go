@@ -113,6 +113,24 @@ func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) e
 		if err != nil {
 			return err
 		}
+		if name == variable.OptimizerTrace {
+			// The flags not given keep their current global values.
+			current, err := variable.GetGlobalSystemVar(sessionVars, name)
+			if err != nil {
+				return err
+			}
+			merged, err := variable.MergeOptimizerTrace(current, valStr)
+			if err != nil {
+				return variable.ErrWrongValueForVar.GenWithStackByArgs(name, valStr)
+			}
+			valStr = merged
+		}
+		if name == variable.TiDBOptCostProfile && valStr != "" {
+			err = e.loadCostProfile(valStr)
+			if err != nil {
+				return err
+			}
+		}
 		err = sessionVars.GlobalVarsAccessor.SetGlobalSysVar(name, valStr)
 		if err != nil {
 			return err
@@ -152,6 +170,31 @@ func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) e
 	return nil
 }
 
+// loadCostProfile sets the global cost factors to the values in the cost
+// profile named name in the cost-profile-dir, they take effect in the new
+// sessions. The reason of a failed load is only logged, so the files on the
+// server are not exposed to the client.
+func (e *SetExecutor) loadCostProfile(name string) error {
+	sessionVars := e.ctx.GetSessionVars()
+	path, err := variable.CostProfilePath(name)
+	if err != nil {
+		return err
+	}
+	profile, err := variable.LoadCostProfile(path)
+	if err != nil {
+		logutil.BgLogger().Warn("load cost profile failed", zap.Uint64("conn", sessionVars.ConnectionID), zap.String("path", path), zap.Error(err))
+		return variable.ErrWrongValueForVar.GenWithStackByArgs(variable.TiDBOptCostProfile, name)
+	}
+	for factor, value := range profile.SysVars() {
+		err = sessionVars.GlobalVarsAccessor.SetGlobalSysVar(factor, value)
+		if err != nil {
+			return err
+		}
+	}
+	logutil.BgLogger().Info("load cost profile", zap.Uint64("conn", sessionVars.ConnectionID), zap.String("path", path))
+	return nil
+}
+
 func (e *SetExecutor) getVarValue(v *expression.VarAssignment, sysVar *variable.SysVar) (value types.Datum, err error) {
 	if v.IsDefault {
 		// To set a SESSION variable to the GLOBAL value or a GLOBAL value
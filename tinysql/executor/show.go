@@ -23,6 +23,8 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/ast"
@@ -103,10 +105,31 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowTables()
 	case ast.ShowVariables:
 		return e.fetchShowVariables()
+	case ast.ShowBindings:
+		return e.fetchShowBind()
 	case ast.ShowWarnings:
 		return e.fetchShowWarnings(false)
 	case ast.ShowErrors:
 		return e.fetchShowWarnings(true)
+	case ast.ShowProcessList:
+		return e.fetchShowProcessList()
+	}
+	return nil
+}
+
+func (e *ShowExec) fetchShowProcessList() error {
+	sm := e.ctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	pl := sm.ShowProcessList()
+	ids := make([]uint64, 0, len(pl))
+	for id := range pl {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		e.appendRow(pl[id].ToRowForShow(e.Full))
 	}
 	return nil
 }
@@ -204,6 +227,27 @@ func (e *ShowExec) fetchShowVariables() (err error) {
 	return nil
 }
 
+func (e *ShowExec) fetchShowBind() error {
+	var records []*bindinfo.BindRecord
+	if !e.GlobalScope {
+		handle := e.ctx.Value(bindinfo.SessionBindInfoKeyType).(*bindinfo.SessionHandle)
+		records = handle.GetAllBindRecord()
+	} else {
+		records = domain.GetDomain(e.ctx).BindHandle().GetAllBindRecord()
+	}
+	for _, record := range records {
+		e.appendRow([]interface{}{
+			record.OriginalSQL,
+			record.BindSQL,
+			record.Db,
+			record.Status,
+			record.CreateTime,
+			record.UpdateTime,
+		})
+	}
+	return nil
+}
+
 func getDefaultCollate(charsetName string) string {
 	for _, c := range charset.GetSupportedCharsets() {
 		if strings.EqualFold(c.Name, charsetName) {
@@ -476,6 +520,8 @@ func (e *ShowExec) appendRow(row []interface{}) {
 			e.result.AppendString(i, x)
 		case []byte:
 			e.result.AppendBytes(i, x)
+		case types.Time:
+			e.result.AppendTime(i, x)
 		default:
 			e.result.AppendNull(i)
 		}
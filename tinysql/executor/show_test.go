@@ -14,7 +14,12 @@
 package executor_test
 
 import (
+	"strings"
+	"time"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testutil"
 )
@@ -79,3 +84,29 @@ func (s *testSuite5) TestShowEscape(c *C) {
 	tk.MustExec("drop table \"t`abl\"\"e\"")
 	tk.MustExec("set sql_mode=@old_sql_mode")
 }
+
+type mockSessionManager struct {
+	processInfoMap map[uint64]*util.ProcessInfo
+}
+
+func (sm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
+	return sm.processInfoMap
+}
+
+func (s *testSuite5) TestShowProcessList(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("show processlist").Check(testkit.Rows())
+
+	longSQL := "select " + strings.Repeat("1 + ", 40) + "1"
+	sm := &mockSessionManager{map[uint64]*util.ProcessInfo{
+		2: {ID: 2, User: "root", Host: "127.0.0.1", Command: mysql.ComSleep, Time: time.Now()},
+		1: {ID: 1, User: "root", Host: "127.0.0.1", DB: "test", Command: mysql.ComQuery, Time: time.Now(), Info: longSQL},
+	}}
+	tk.Se.SetSessionManager(sm)
+	tk.MustQuery("show processlist").Check(testkit.Rows(
+		"1 root 127.0.0.1 test Query 0 0 "+longSQL[:100],
+		"2 root 127.0.0.1 <nil> Sleep 0 0 <nil>"))
+	tk.MustQuery("show full processlist").Check(testkit.Rows(
+		"1 root 127.0.0.1 test Query 0 0 "+longSQL,
+		"2 root 127.0.0.1 <nil> Sleep 0 0 <nil>"))
+}
@@ -16,13 +16,23 @@ package executor
 import (
 	"container/heap"
 	"context"
+	"fmt"
 	"sort"
+	"unsafe"
 
 	"github.com/pingcap/tidb/expression"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/stringutil"
 )
 
+var rowChunksLabel fmt.Stringer = stringutil.StringerStr("rowChunks")
+
+// rowPtrSize is the memory size of a chunk.RowPtr.
+const rowPtrSize = int64(unsafe.Sizeof(chunk.RowPtr{}))
+
 // SortExec represents sorting executor.
 type SortExec struct {
 	baseExecutor
@@ -40,17 +50,55 @@ type SortExec struct {
 	rowChunks *chunk.List
 	// rowPointer store the chunk index and row index for each row.
 	rowPtrs []chunk.RowPtr
+
+	memTracker  *memory.Tracker
+	diskTracker *memory.Tracker
+
+	// spillAction is registered on the statement memory tracker when the
+	// OOM action is SPILL, it asks the executor to spill the rows in memory.
+	spillAction *spillDiskAction
+	// partitionList is the sorted runs spilled to disk.
+	partitionList []*chunk.ListInDisk
+	// multiWayMerge merges the sorted runs in partitionList.
+	multiWayMerge *multiWayMerge
 }
 
 // Close implements the Executor Close interface.
 func (e *SortExec) Close() error {
-	return e.children[0].Close()
+	if e.spillAction != nil {
+		e.spillAction.setSpillable(false)
+		e.spillAction = nil
+	}
+	// Keep closing the other partitions and the child on error, so that no
+	// temporary file or child executor is leaked.
+	var firstErr error
+	for _, partition := range e.partitionList {
+		if err := partition.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	e.partitionList = nil
+	e.multiWayMerge = nil
+	if e.memTracker != nil {
+		e.memTracker.Detach()
+	}
+	if e.diskTracker != nil {
+		e.diskTracker.Detach()
+	}
+	if err := e.children[0].Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
 }
 
 // Open implements the Executor Open interface.
 func (e *SortExec) Open(ctx context.Context) error {
 	e.fetched = false
 	e.Idx = 0
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = memory.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
 	return e.children[0].Open(ctx)
 }
 
@@ -58,16 +106,26 @@ func (e *SortExec) Open(ctx context.Context) error {
 func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if !e.fetched {
+		e.initCompareFuncs()
+		e.buildKeyColumns()
 		err := e.fetchRowChunks(ctx)
 		if err != nil {
 			return err
 		}
-		e.initPointers()
-		e.initCompareFuncs()
-		e.buildKeyColumns()
-		sort.Slice(e.rowPtrs, e.keyColumnsLess)
+		if len(e.partitionList) > 0 {
+			err = e.initMultiWayMerge()
+			if err != nil {
+				return err
+			}
+		} else {
+			e.initPointers()
+			sort.Slice(e.rowPtrs, e.keyColumnsLess)
+		}
 		e.fetched = true
 	}
+	if e.multiWayMerge != nil {
+		return e.multiWayMerge.next(req)
+	}
 	for !req.IsFull() && e.Idx < len(e.rowPtrs) {
 		rowPtr := e.rowPtrs[e.Idx]
 		req.AppendRow(e.rowChunks.GetRow(rowPtr))
@@ -76,9 +134,20 @@ func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return nil
 }
 
+func (e *SortExec) newRowChunks() {
+	e.rowChunks = chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	e.rowChunks.GetMemTracker().AttachTo(e.memTracker)
+	e.rowChunks.GetMemTracker().SetLabel(rowChunksLabel)
+}
+
 func (e *SortExec) fetchRowChunks(ctx context.Context) error {
-	fields := retTypes(e)
-	e.rowChunks = chunk.NewList(fields, e.initCap, e.maxChunkSize)
+	e.newRowChunks()
+	sessVars := e.ctx.GetSessionVars()
+	if sessVars.OOMAction == variable.OOMActionSpill && sessVars.StmtCtx.MemTracker != nil {
+		e.spillAction = &spillDiskAction{spillable: 1}
+		sessVars.StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+		defer e.spillAction.setSpillable(false)
+	}
 	for {
 		chk := newFirstChunk(e.children[0])
 		err := Next(ctx, e.children[0], chk)
@@ -90,6 +159,130 @@ func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 			break
 		}
 		e.rowChunks.Add(chk)
+		if e.spillAction != nil && e.spillAction.shouldSpill() {
+			if err = e.spillToDisk(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(e.partitionList) > 0 && e.rowChunks.Len() > 0 {
+		// The rest rows are spilled as the last run, so all the runs can be
+		// merged in the same way.
+		return e.spillToDisk()
+	}
+	return nil
+}
+
+// spillToDisk sorts the rows in memory and writes them to disk as a sorted
+// run, the memory of the rows is released after that.
+func (e *SortExec) spillToDisk() error {
+	defer e.spillAction.reset()
+	if e.rowChunks.Len() == 0 {
+		return nil
+	}
+	e.initPointers()
+	sort.Slice(e.rowPtrs, e.keyColumnsLess)
+	partition := chunk.NewListInDisk(retTypes(e))
+	partition.GetDiskTracker().AttachTo(e.diskTracker)
+	e.partitionList = append(e.partitionList, partition)
+	chk := chunk.NewChunkWithCapacity(retTypes(e), e.maxChunkSize)
+	for _, rowPtr := range e.rowPtrs {
+		chk.AppendRow(e.rowChunks.GetRow(rowPtr))
+		if chk.NumRows() >= e.maxChunkSize {
+			if err := partition.Add(chk); err != nil {
+				return err
+			}
+			chk.Reset()
+		}
+	}
+	if chk.NumRows() > 0 {
+		if err := partition.Add(chk); err != nil {
+			return err
+		}
+	}
+	e.memTracker.Consume(-rowPtrSize * int64(cap(e.rowPtrs)))
+	e.rowPtrs = nil
+	e.rowChunks.GetMemTracker().Detach()
+	e.newRowChunks()
+	return nil
+}
+
+func (e *SortExec) initMultiWayMerge() error {
+	e.multiWayMerge = &multiWayMerge{
+		lessRowFunction: e.lessRow,
+		partitionList:   e.partitionList,
+		elements:        make([]partitionPointer, 0, len(e.partitionList)),
+	}
+	for i, partition := range e.partitionList {
+		chk, err := partition.GetChunk(0)
+		if err != nil {
+			return err
+		}
+		e.multiWayMerge.elements = append(e.multiWayMerge.elements, partitionPointer{chk: chk, partitionID: i})
+	}
+	heap.Init(e.multiWayMerge)
+	return nil
+}
+
+// partitionPointer points to the current row of a sorted run.
+type partitionPointer struct {
+	chk         *chunk.Chunk
+	chkIdx      int
+	rowIdx      int
+	partitionID int
+}
+
+// multiWayMerge implements heap.Interface, it merges the sorted runs spilled
+// to disk, only one chunk of each run is kept in memory.
+type multiWayMerge struct {
+	lessRowFunction func(rowI, rowJ chunk.Row) bool
+	partitionList   []*chunk.ListInDisk
+	elements        []partitionPointer
+}
+
+func (h *multiWayMerge) Less(i, j int) bool {
+	rowI := h.elements[i].chk.GetRow(h.elements[i].rowIdx)
+	rowJ := h.elements[j].chk.GetRow(h.elements[j].rowIdx)
+	return h.lessRowFunction(rowI, rowJ)
+}
+
+func (h *multiWayMerge) Len() int {
+	return len(h.elements)
+}
+
+func (h *multiWayMerge) Push(x interface{}) {
+	// Should never be called.
+}
+
+func (h *multiWayMerge) Pop() interface{} {
+	h.elements = h.elements[:len(h.elements)-1]
+	return nil
+}
+
+func (h *multiWayMerge) Swap(i, j int) {
+	h.elements[i], h.elements[j] = h.elements[j], h.elements[i]
+}
+
+// next fills req with the smallest rows of the sorted runs.
+func (h *multiWayMerge) next(req *chunk.Chunk) error {
+	for !req.IsFull() && h.Len() > 0 {
+		top := &h.elements[0]
+		req.AppendRow(top.chk.GetRow(top.rowIdx))
+		top.rowIdx++
+		if top.rowIdx >= top.chk.NumRows() {
+			partition := h.partitionList[top.partitionID]
+			top.chkIdx++
+			if top.chkIdx >= partition.NumChunks() {
+				heap.Remove(h, 0)
+				continue
+			}
+			chk, err := partition.GetChunk(top.chkIdx)
+			if err != nil {
+				return err
+			}
+			top.chk, top.rowIdx = chk, 0
+		}
+		heap.Fix(h, 0)
 	}
 	return nil
 }
@@ -102,6 +295,7 @@ func (e *SortExec) initPointers() {
 			e.rowPtrs = append(e.rowPtrs, chunk.RowPtr{ChkIdx: uint32(chkIdx), RowIdx: uint32(rowIdx)})
 		}
 	}
+	e.memTracker.Consume(rowPtrSize * int64(cap(e.rowPtrs)))
 }
 
 func (e *SortExec) initCompareFuncs() {
@@ -234,7 +428,7 @@ func (e *TopNExec) Next(ctx context.Context, req *chunk.Chunk) error {
 
 func (e *TopNExec) loadChunksUntilTotalLimit(ctx context.Context) error {
 	e.chkHeap = &topNChunkHeap{e}
-	e.rowChunks = chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	e.newRowChunks()
 	for uint64(e.rowChunks.Len()) < e.totalLimit {
 		srcChk := newFirstChunk(e.children[0])
 		// adjust required rows by total limit
@@ -312,7 +506,11 @@ func (e *TopNExec) doCompaction() error {
 		newRowPtr := newRowChunks.AppendRow(e.rowChunks.GetRow(rowPtr))
 		newRowPtrs = append(newRowPtrs, newRowPtr)
 	}
+	newRowChunks.GetMemTracker().SetLabel(rowChunksLabel)
+	e.memTracker.ReplaceChild(e.rowChunks.GetMemTracker(), newRowChunks.GetMemTracker())
 	e.rowChunks = newRowChunks
+
+	e.memTracker.Consume(rowPtrSize * int64(cap(newRowPtrs)-cap(e.rowPtrs)))
 	e.rowPtrs = newRowPtrs
 	return nil
 }
@@ -0,0 +1,165 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+const (
+	// spillPartitionBits is the number of the radix bits of the hash value
+	// used to choose the partition of a row in every round of partitioning.
+	spillPartitionBits = 4
+	// spillPartitionNum is the number of the partitions spilled to disk in
+	// every round of partitioning.
+	spillPartitionNum = 1 << spillPartitionBits
+	// maxSpillDepth is the max rounds of partitioning. A partition which still
+	// exceeds the memory quota after maxSpillDepth rounds is processed in
+	// memory, it mostly consists of very few keys.
+	maxSpillDepth = 4
+)
+
+// spillPartitionIdx returns the partition of a row in the depth-th round of
+// partitioning, different radix bits of the hash value are used in each
+// round, so a partition can be split again.
+func spillPartitionIdx(hashVal uint64, depth int) int {
+	return int((hashVal >> (uint(depth) * spillPartitionBits)) & (spillPartitionNum - 1))
+}
+
+// spillDiskAction asks an executor to spill its data to disk when the memory
+// usage of the statement exceeds the quota. It falls back to the old action
+// when the executor is not able to spill.
+type spillDiskAction struct {
+	spillable int32 // 1 when the executor is able to spill, accessed atomically.
+	needSpill int32 // 1 when the executor needs to spill, accessed atomically.
+	fallback  memory.ActionOnExceed
+}
+
+// Action implements memory.ActionOnExceed Action interface.
+func (a *spillDiskAction) Action(t *memory.Tracker) {
+	if atomic.LoadInt32(&a.spillable) == 1 {
+		atomic.StoreInt32(&a.needSpill, 1)
+		return
+	}
+	if a.fallback != nil {
+		a.fallback.Action(t)
+	}
+}
+
+// SetFallback implements memory.ActionOnExceed SetFallback interface.
+func (a *spillDiskAction) SetFallback(fallback memory.ActionOnExceed) {
+	a.fallback = fallback
+}
+
+func (a *spillDiskAction) setSpillable(spillable bool) {
+	if spillable {
+		atomic.StoreInt32(&a.spillable, 1)
+	} else {
+		atomic.StoreInt32(&a.spillable, 0)
+	}
+}
+
+func (a *spillDiskAction) shouldSpill() bool {
+	return atomic.LoadInt32(&a.needSpill) == 1
+}
+
+func (a *spillDiskAction) reset() {
+	atomic.StoreInt32(&a.needSpill, 0)
+}
+
+// partitionsInDisk splits rows into spillPartitionNum partitions stored in
+// disk, the rows of a partition are buffered in a chunk before written.
+type partitionsInDisk struct {
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+	diskTracker  *memory.Tracker
+
+	partitions []*chunk.ListInDisk
+	bufs       []*chunk.Chunk
+}
+
+func newPartitionsInDisk(fieldTypes []*types.FieldType, maxChunkSize int, diskTracker *memory.Tracker) *partitionsInDisk {
+	return &partitionsInDisk{
+		fieldTypes:   fieldTypes,
+		maxChunkSize: maxChunkSize,
+		diskTracker:  diskTracker,
+		partitions:   make([]*chunk.ListInDisk, spillPartitionNum),
+		bufs:         make([]*chunk.Chunk, spillPartitionNum),
+	}
+}
+
+// appendRow appends a row to the partition specified by partIdx.
+func (p *partitionsInDisk) appendRow(partIdx int, row chunk.Row) error {
+	if p.bufs[partIdx] == nil {
+		p.bufs[partIdx] = chunk.NewChunkWithCapacity(p.fieldTypes, p.maxChunkSize)
+	}
+	buf := p.bufs[partIdx]
+	buf.AppendRow(row)
+	if buf.NumRows() < p.maxChunkSize {
+		return nil
+	}
+	return p.flush(partIdx)
+}
+
+func (p *partitionsInDisk) flush(partIdx int) error {
+	buf := p.bufs[partIdx]
+	if buf == nil || buf.NumRows() == 0 {
+		return nil
+	}
+	if p.partitions[partIdx] == nil {
+		p.partitions[partIdx] = chunk.NewListInDisk(p.fieldTypes)
+		p.partitions[partIdx].GetDiskTracker().AttachTo(p.diskTracker)
+	}
+	if err := p.partitions[partIdx].Add(buf); err != nil {
+		return err
+	}
+	buf.Reset()
+	return nil
+}
+
+// finish writes all the buffered rows to disk, it must be called before
+// reading the partitions.
+func (p *partitionsInDisk) finish() error {
+	for i := range p.bufs {
+		if err := p.flush(i); err != nil {
+			return err
+		}
+	}
+	p.bufs = nil
+	return nil
+}
+
+// getPartition returns the partition specified by partIdx, nil is returned if
+// the partition is empty.
+func (p *partitionsInDisk) getPartition(partIdx int) *chunk.ListInDisk {
+	return p.partitions[partIdx]
+}
+
+// close removes the temporary files of the partitions.
+func (p *partitionsInDisk) close() (err error) {
+	for i, partition := range p.partitions {
+		if partition == nil {
+			continue
+		}
+		if err1 := partition.Close(); err1 != nil && err == nil {
+			err = err1
+		}
+		p.partitions[i] = nil
+	}
+	return err
+}
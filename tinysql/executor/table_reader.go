@@ -46,6 +46,8 @@ type TableReaderExecutor struct {
 	// for unsigned int.
 	resultHandler *tableResultHandler
 	plans         []plannercore.PhysicalPlan
+	// corColInFilter tells whether there's correlated column in filter.
+	corColInFilter bool
 
 	keepOrder bool
 	desc      bool
@@ -53,6 +55,13 @@ type TableReaderExecutor struct {
 
 // Open initialzes necessary variables for using this executor.
 func (e *TableReaderExecutor) Open(ctx context.Context) error {
+	var err error
+	if e.corColInFilter {
+		e.dagPB.Executors, err = constructDistExec(e.ctx, e.plans)
+		if err != nil {
+			return err
+		}
+	}
 	e.resultHandler = &tableResultHandler{}
 	firstPartRanges, secondPartRanges := splitRanges(e.ranges, e.keepOrder, e.desc)
 	firstResult, err := e.buildResp(ctx, firstPartRanges)
@@ -102,7 +111,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 		return nil, err
 	}
 	e.kvRanges = append(e.kvRanges, kvReq.KeyRanges...)
-	return distsql.Select(ctx, e.ctx, kvReq, retTypes(e))
+	return distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, retTypes(e), getPhysicalPlanIDs(e.plans), e.id)
 }
 
 type tableResultHandler struct {
@@ -0,0 +1,372 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// WindowExec is the executor for window functions.
+// The rows from the child are sorted by the partition by and order by items,
+// so the executor reads a whole partition at a time, and then computes the
+// window function results for every row in the partition.
+type WindowExec struct {
+	baseExecutor
+
+	processor windowProcessor
+
+	// childColIdx is the column index in the child of the output columns
+	// except the window function results.
+	childColIdx []int
+	// partitionColIdx is the column index of the partition by items.
+	partitionColIdx []int
+	// partitionCmpFuncs is used to compare each partition by item.
+	partitionCmpFuncs []chunk.CompareFunc
+
+	// childResult stores the child chunk which is being consumed.
+	childResult *chunk.Chunk
+	// childCursor is the index of the next row in childResult to be consumed.
+	childCursor int
+	// executed indicates the child executor is drained.
+	executed bool
+	// partitionRows stores the rows of the current partition.
+	partitionRows []chunk.Row
+	// outputCursor is the index of the next row in partitionRows to be output.
+	outputCursor int
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	e.childResult = nil
+	e.childCursor = 0
+	e.executed = false
+	e.partitionRows = e.partitionRows[:0]
+	e.outputCursor = 0
+	return e.baseExecutor.Open(ctx)
+}
+
+// Close implements the Executor Close interface.
+func (e *WindowExec) Close() error {
+	e.childResult = nil
+	e.partitionRows = nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *WindowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if e.outputCursor == len(e.partitionRows) {
+			err := e.fetchPartition(ctx)
+			if err != nil {
+				return err
+			}
+			if len(e.partitionRows) == 0 {
+				return nil
+			}
+		}
+		err := e.appendResult2Chunk(req)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchPartition reads all the rows of the next partition from the child,
+// and lets the window processor consume them.
+func (e *WindowExec) fetchPartition(ctx context.Context) error {
+	e.processor.resetPartialResult()
+	e.partitionRows = e.partitionRows[:0]
+	e.outputCursor = 0
+	for {
+		if e.childResult == nil || e.childCursor == e.childResult.NumRows() {
+			if e.executed {
+				break
+			}
+			// The rows of a partition may come from several chunks, so we
+			// always use a new chunk to keep the fetched rows valid.
+			e.childResult = newFirstChunk(e.children[0])
+			err := Next(ctx, e.children[0], e.childResult)
+			if err != nil {
+				return err
+			}
+			e.childCursor = 0
+			if e.childResult.NumRows() == 0 {
+				e.executed = true
+				break
+			}
+		}
+		row := e.childResult.GetRow(e.childCursor)
+		if len(e.partitionRows) > 0 && !e.samePartition(e.partitionRows[0], row) {
+			break
+		}
+		e.partitionRows = append(e.partitionRows, row)
+		e.childCursor++
+	}
+	if len(e.partitionRows) == 0 {
+		return nil
+	}
+	return e.processor.consumePartition(e.ctx, e.partitionRows)
+}
+
+func (e *WindowExec) samePartition(lhs, rhs chunk.Row) bool {
+	for i, colIdx := range e.partitionColIdx {
+		if e.partitionCmpFuncs[i](lhs, colIdx, rhs, colIdx) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *WindowExec) appendResult2Chunk(chk *chunk.Chunk) error {
+	for ; e.outputCursor < len(e.partitionRows) && !chk.IsFull(); e.outputCursor++ {
+		chk.AppendPartialRowByColIdxs(0, e.partitionRows[e.outputCursor], e.childColIdx)
+		err := e.processor.appendResult2Chunk(e.ctx, e.partitionRows, e.outputCursor, chk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// windowProcessor is the interface for processing different kinds of windows.
+type windowProcessor interface {
+	// consumePartition is called with all the rows of a partition before
+	// any result of the partition is appended.
+	consumePartition(ctx sessionctx.Context, rows []chunk.Row) error
+	// appendResult2Chunk appends the window function results of rows[idx] to chk.
+	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error
+	// resetPartialResult resets the partial results to the original state.
+	resetPartialResult()
+}
+
+type baseWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+}
+
+func (p *baseWindowProcessor) resetPartialResult() {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+	}
+}
+
+// appendFrameResult2Chunk computes the window function results on the rows of the frame.
+func (p *baseWindowProcessor) appendFrameResult2Chunk(ctx sessionctx.Context, frame []chunk.Row, chk *chunk.Chunk) error {
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+		if len(frame) > 0 {
+			err := windowFunc.UpdatePartialResult(ctx, frame, p.partialResults[i])
+			if err != nil {
+				return err
+			}
+		}
+		err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggWindowProcessor is the processor for windows without frame,
+// the window functions operate on the entire partition.
+type aggWindowProcessor struct {
+	baseWindowProcessor
+}
+
+func (p *aggWindowProcessor) consumePartition(ctx sessionctx.Context, rows []chunk.Row) error {
+	for i, windowFunc := range p.windowFuncs {
+		err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error {
+	for i, windowFunc := range p.windowFuncs {
+		err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowFrameWindowProcessor is the processor for windows with ROWS frame.
+type rowFrameWindowProcessor struct {
+	baseWindowProcessor
+	start *plannercore.FrameBound
+	end   *plannercore.FrameBound
+}
+
+func (p *rowFrameWindowProcessor) getStartOffset(curRowIdx, numRows uint64) uint64 {
+	if p.start.UnBounded {
+		return 0
+	}
+	switch p.start.Type {
+	case ast.Preceding:
+		if curRowIdx >= p.start.Num {
+			return curRowIdx - p.start.Num
+		}
+		return 0
+	case ast.Following:
+		offset := curRowIdx + p.start.Num
+		if offset >= numRows {
+			return numRows
+		}
+		return offset
+	case ast.CurrentRow:
+		return curRowIdx
+	}
+	// It will never reach here.
+	return 0
+}
+
+func (p *rowFrameWindowProcessor) getEndOffset(curRowIdx, numRows uint64) uint64 {
+	if p.end.UnBounded {
+		return numRows
+	}
+	switch p.end.Type {
+	case ast.Preceding:
+		if curRowIdx >= p.end.Num {
+			return curRowIdx - p.end.Num + 1
+		}
+		return 0
+	case ast.Following:
+		offset := curRowIdx + p.end.Num
+		if offset >= numRows {
+			return numRows
+		}
+		return offset + 1
+	case ast.CurrentRow:
+		return curRowIdx + 1
+	}
+	// It will never reach here.
+	return 0
+}
+
+func (p *rowFrameWindowProcessor) consumePartition(ctx sessionctx.Context, rows []chunk.Row) error {
+	return nil
+}
+
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error {
+	numRows := uint64(len(rows))
+	start := p.getStartOffset(uint64(idx), numRows)
+	end := p.getEndOffset(uint64(idx), numRows)
+	if start >= end {
+		return p.appendFrameResult2Chunk(ctx, nil, chk)
+	}
+	return p.appendFrameResult2Chunk(ctx, rows[start:end], chk)
+}
+
+// rangeFrameWindowProcessor is the processor for windows with RANGE frame.
+type rangeFrameWindowProcessor struct {
+	baseWindowProcessor
+	start       *plannercore.FrameBound
+	end         *plannercore.FrameBound
+	orderByCols []*expression.Column
+	// expectedCmpResult is used to decide if one value is included in the frame.
+	expectedCmpResult int64
+
+	// The frame of the next row never starts or ends before the frame of the
+	// current row, since the rows are sorted by the ORDER BY column.
+	lastStartOffset int
+	lastEndOffset   int
+}
+
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row, curRowIdx int) (int, error) {
+	if p.start.UnBounded {
+		return 0, nil
+	}
+	for ; p.lastStartOffset < len(rows); p.lastStartOffset++ {
+		var res int64
+		var err error
+		for i := range p.orderByCols {
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[curRowIdx])
+			if err != nil {
+				return 0, err
+			}
+			if res != 0 {
+				break
+			}
+		}
+		// For asc, break when the current value is greater or equal to the calculated result;
+		// For desc, break when the current value is less or equal to the calculated result.
+		if (p.expectedCmpResult == -1 && res >= 0) || (p.expectedCmpResult == 1 && res <= 0) {
+			break
+		}
+	}
+	return p.lastStartOffset, nil
+}
+
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row, curRowIdx int) (int, error) {
+	if p.end.UnBounded {
+		return len(rows), nil
+	}
+	for ; p.lastEndOffset < len(rows); p.lastEndOffset++ {
+		var res int64
+		var err error
+		for i := range p.orderByCols {
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[curRowIdx], rows[p.lastEndOffset])
+			if err != nil {
+				return 0, err
+			}
+			if res != 0 {
+				break
+			}
+		}
+		// For asc, break when the calculated result is less than the current value.
+		// For desc, break when the calculated result is greater than the current value.
+		if (p.expectedCmpResult == -1 && res < 0) || (p.expectedCmpResult == 1 && res > 0) {
+			break
+		}
+	}
+	return p.lastEndOffset, nil
+}
+
+func (p *rangeFrameWindowProcessor) consumePartition(ctx sessionctx.Context, rows []chunk.Row) error {
+	return nil
+}
+
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, idx int, chk *chunk.Chunk) error {
+	start, err := p.getStartOffset(ctx, rows, idx)
+	if err != nil {
+		return err
+	}
+	end, err := p.getEndOffset(ctx, rows, idx)
+	if err != nil {
+		return err
+	}
+	if start >= end {
+		return p.appendFrameResult2Chunk(ctx, nil, chk)
+	}
+	return p.appendFrameResult2Chunk(ctx, rows[start:end], chk)
+}
+
+func (p *rangeFrameWindowProcessor) resetPartialResult() {
+	p.baseWindowProcessor.resetPartialResult()
+	p.lastStartOffset = 0
+	p.lastEndOffset = 0
+}
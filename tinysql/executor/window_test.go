@@ -0,0 +1,78 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuiteP1) TestWindowFunctions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(10))")
+	tk.MustExec("insert into t values (1, 1, 'x'), (1, 2, 'y'), (1, 2, 'z'), (2, 3, 'w'), (2, 5, 'v'), (3, null, 'u')")
+
+	result := tk.MustQuery("select a, b, row_number() over(partition by a order by b) from t")
+	result.Check(testkit.Rows("1 1 1", "1 2 2", "1 2 3", "2 3 1", "2 5 2", "3 <nil> 1"))
+	result = tk.MustQuery("select a, b, rank() over(order by b), dense_rank() over(order by b) from t")
+	result.Check(testkit.Rows("3 <nil> 1 1", "1 1 2 2", "1 2 3 3", "1 2 3 3", "2 3 5 4", "2 5 6 5"))
+	result = tk.MustQuery("select a, b, sum(b) over(partition by a) from t")
+	result.Check(testkit.Rows("1 1 5", "1 2 5", "1 2 5", "2 3 8", "2 5 8", "3 <nil> <nil>"))
+	result = tk.MustQuery("select a, b, sum(b) over(partition by a order by b) from t")
+	result.Check(testkit.Rows("1 1 1", "1 2 5", "1 2 5", "2 3 3", "2 5 8", "3 <nil> <nil>"))
+	result = tk.MustQuery("select a, b, sum(b) over(order by b rows between 1 preceding and 1 following) from t")
+	result.Check(testkit.Rows("3 <nil> 1", "1 1 3", "1 2 5", "1 2 7", "2 3 10", "2 5 8"))
+	result = tk.MustQuery("select a, b, sum(b) over(order by b range between 1 preceding and 1 following) from t")
+	result.Check(testkit.Rows("3 <nil> <nil>", "1 1 5", "1 2 8", "1 2 8", "2 3 7", "2 5 5"))
+	result = tk.MustQuery("select a, b, count(*) over(order by b desc range between 2 preceding and current row) from t")
+	result.Check(testkit.Rows("2 5 1", "2 3 2", "1 2 3", "1 2 3", "1 1 4", "3 <nil> 1"))
+	result = tk.MustQuery("select a, b, lead(b) over(order by a, b), lag(b, 2, -1) over(order by a, b) from t")
+	result.Check(testkit.Rows("1 1 2 -1", "1 2 2 -1", "1 2 3 1", "2 3 5 2", "2 5 <nil> 2", "3 <nil> <nil> 3"))
+	result = tk.MustQuery("select a, b, first_value(c) over(partition by a order by b), " +
+		"last_value(c) over(partition by a order by b rows between unbounded preceding and unbounded following) from t")
+	result.Check(testkit.Rows("1 1 x z", "1 2 x z", "1 2 x z", "2 3 w v", "2 5 w v", "3 <nil> u u"))
+
+	// Window functions are evaluated after aggregation.
+	result = tk.MustQuery("select a, count(*), sum(count(*)) over() from t group by a order by a")
+	result.Check(testkit.Rows("1 3 6", "2 2 6", "3 1 6"))
+	// Window functions in ORDER BY clause.
+	result = tk.MustQuery("select a, b from t order by row_number() over(order by b desc)")
+	result.Check(testkit.Rows("2 5", "2 3", "1 2", "1 2", "1 1", "3 <nil>"))
+	// Expressions in the window specification.
+	result = tk.MustQuery("select a, b + 1, avg(b) over(partition by a + 1) from t where a > 1")
+	result.Check(testkit.Rows("2 4 4.0000", "2 6 4.0000", "3 <nil> <nil>"))
+	// Top N rows of each group.
+	result = tk.MustQuery("select a, c from (select a, c, row_number() over(partition by a order by b desc, c) as r from t) tt where r <= 1")
+	result.Check(testkit.Rows("1 y", "2 v", "3 u"))
+
+	tk.MustQuery("select row_number() over(rows between 1 preceding and 1 following) from t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 3599 Window function 'row_number' ignores the frame clause of window '<unnamed window>' and aggregates over the whole partition"))
+
+	_, err := tk.Exec("select a from t having row_number() over() > 1")
+	c.Assert(err.Error(), Equals, "[planner:3593]You cannot use the window function 'row_number' in this context.'")
+	_, err = tk.Exec("select a from t where row_number() over() > 1")
+	c.Assert(err.Error(), Equals, "[planner:3593]You cannot use the window function 'row_number' in this context.'")
+	_, err = tk.Exec("select sum(a) over(rows between unbounded following and current row) from t")
+	c.Assert(err.Error(), Equals, "[planner:3584]Window '<unnamed window>': frame start cannot be UNBOUNDED FOLLOWING.")
+	_, err = tk.Exec("select sum(a) over(rows between current row and unbounded preceding) from t")
+	c.Assert(err.Error(), Equals, "[planner:3585]Window '<unnamed window>': frame end cannot be UNBOUNDED PRECEDING.")
+	_, err = tk.Exec("select sum(a) over(rows between 1 following and current row) from t")
+	c.Assert(err.Error(), Equals, "[planner:3586]Window '<unnamed window>': frame start or end is negative, NULL or of non-integral type")
+	_, err = tk.Exec("select sum(a) over(range between 1 preceding and current row) from t")
+	c.Assert(err.Error(), Equals, "[planner:3587]Window '<unnamed window>' with RANGE N PRECEDING/FOLLOWING frame requires exactly one ORDER BY expression, of numeric or temporal type")
+	_, err = tk.Exec("select sum(a) over(order by c range between 1 preceding and current row) from t")
+	c.Assert(err.Error(), Equals, "[planner:3587]Window '<unnamed window>' with RANGE N PRECEDING/FOLLOWING frame requires exactly one ORDER BY expression, of numeric or temporal type")
+}
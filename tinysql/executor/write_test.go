@@ -19,11 +19,13 @@ import (
 	"fmt"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testutil"
 )
 
 type testBypassSuite struct{}
@@ -314,6 +316,98 @@ func (s *testSuite4) TestInsertSetWithDefault(c *C) {
 	tk.MustQuery("select * from t1;").Check(testkit.Rows("30 20"))
 }
 
+func (s *testSuite4) TestInsertIgnore(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int, unique key uk(v))")
+	tk.MustExec("insert into t values (1, 10), (2, 20)")
+
+	_, err := tk.Exec("insert into t values (1, 30)")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("insert ignore into t values (1, 30), (3, 20), (4, 40)")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(1))
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|",
+		"Warning|1062|Duplicate entry '1' for key 'PRIMARY'",
+		"Warning|1062|Duplicate entry '20' for key 'uk'"))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "2 20", "4 40"))
+
+	// Duplicate rows inside the statement are ignored as well.
+	tk.MustExec("insert ignore into t values (5, 50), (5, 51), (6, 50)")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(1))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(2))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "2 20", "4 40", "5 50"))
+
+	tk.MustExec("drop table if exists s")
+	tk.MustExec("create table s (id int, v int)")
+	tk.MustExec("insert into s values (2, 70), (7, 70), (8, 80)")
+	tk.MustExec("insert ignore into t select * from s")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(2))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "2 20", "4 40", "5 50", "7 70", "8 80"))
+
+	// The rows inserted in the same transaction are checked.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (9, 90)")
+	tk.MustExec("insert ignore into t values (9, 91), (10, 90), (11, 110)")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(1))
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t where id > 8 order by id").Check(testkit.Rows("9 90", "11 110"))
+}
+
+func (s *testSuite4) TestInsertOnDuplicateKeyUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int, cnt int default 0, unique key uk(v))")
+	tk.MustExec("insert into t (id, v) values (1, 10), (2, 20)")
+
+	// Update the row duplicated on the primary key.
+	tk.MustExec("insert into t (id, v) values (1, 11) on duplicate key update cnt = cnt + 1")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(2))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10 1", "2 20 0"))
+
+	// Update the row duplicated on the unique key, VALUES() refers to the to-be-inserted row.
+	tk.MustExec("insert into t (id, v) values (3, 20) on duplicate key update cnt = cnt + values(id)")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(2))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10 1", "2 20 3"))
+
+	// An unchanged row is not counted, a new row is counted as 1.
+	tk.MustExec("insert into t (id, v) values (1, 10), (4, 40) on duplicate key update v = values(v)")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(1))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10 1", "2 20 3", "4 40 0"))
+
+	// Later assignments see the values set by the former ones.
+	tk.MustExec("insert into t (id, v) values (4, 0) on duplicate key update v = v + 1, cnt = v")
+	tk.MustQuery("select * from t where id = 4").Check(testkit.Rows("4 41 41"))
+
+	// The handle is changed by the update.
+	tk.MustExec("insert into t (id, v) values (2, 0) on duplicate key update id = 5")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(2))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10 1", "4 41 41", "5 20 3"))
+
+	// Duplicate rows inside the statement update the row inserted by the former one.
+	tk.MustExec("insert into t (id, v) values (6, 60), (6, 61) on duplicate key update cnt = cnt + 1")
+	tk.MustQuery("select * from t where id = 6").Check(testkit.Rows("6 60 1"))
+
+	tk.MustExec("drop table if exists s")
+	tk.MustExec("create table s (id int, v int)")
+	tk.MustExec("insert into s values (1, 100), (7, 70)")
+	tk.MustExec("insert into t (id, v) select * from s on duplicate key update v = values(v)")
+	c.Assert(int64(tk.Se.AffectedRows()), Equals, int64(3))
+	tk.MustQuery("select id, v from t order by id").Check(testkit.Rows("1 100", "4 41", "5 20", "6 60", "7 70"))
+
+	// The update conflicts with another row.
+	_, err := tk.Exec("insert into t (id, v) values (1, 0) on duplicate key update v = 41")
+	c.Assert(err, NotNil)
+	tk.MustExec("insert ignore into t (id, v) values (1, 0) on duplicate key update v = 41")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
+	tk.MustQuery("select v from t where id = 1").Check(testkit.Rows("100"))
+
+	_, err = tk.Exec("insert into t (id, v) values (1, 0) on duplicate key update xxx = 1")
+	c.Assert(err, NotNil)
+}
+
 func (s *testSuite4) TestReplace(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -459,6 +553,317 @@ func (s *testSuite4) TestReplace(c *C) {
 	tk.MustQuery("select * from t1;").Check(testkit.Rows("1 20 30", "2 30 20", "50 20 30"))
 }
 
+type testCase struct {
+	data     []byte
+	expected []string
+}
+
+// checkLoadData runs the statement prepared by tk, then feeds every test case
+// to the LoadDataInfo in a transaction and checks the rows of table t.
+func checkLoadData(c *C, tk *testkit.TestKit, sql string, cases []testCase) {
+	ctx := context.Background()
+	for _, tc := range cases {
+		tk.MustExec("delete from t")
+		tk.MustExec(sql)
+		ld, ok := tk.Se.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+		c.Assert(ok, IsTrue)
+		tk.Se.SetValue(executor.LoadDataVarKey, nil)
+		c.Assert(tk.Se.NewTxn(ctx), IsNil)
+		rest, reachLimit, err := ld.InsertData(ctx, nil, tc.data)
+		c.Assert(err, IsNil)
+		c.Assert(reachLimit, IsFalse)
+		_, _, err = ld.InsertData(ctx, rest, nil)
+		c.Assert(err, IsNil)
+		c.Assert(ld.CheckAndInsertOneBatch(ctx), IsNil)
+		c.Assert(tk.Se.StmtCommit(), IsNil)
+		c.Assert(tk.Se.CommitTxn(ctx), IsNil)
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows(tc.expected...))
+	}
+}
+
+func (s *testSuite4) TestLoadData(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c1 varchar(20), c2 int default 7)")
+
+	_, err := tk.Exec("load data infile '/tmp/nonexistence.csv' into table t")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("load data local infile '/tmp/nonexistence.csv' into table nonexistence")
+	c.Assert(err, NotNil)
+
+	sql := "load data local infile '/tmp/nonexistence.csv' into table t"
+	checkLoadData(c, tk, sql, []testCase{
+		{[]byte("1\ta\t1\n2\tb\t2\n"), []string{"1 a 1", "2 b 2"}},
+		// The last line doesn't need a terminator.
+		{[]byte("1\ta\t1\n2\tb\t2"), []string{"1 a 1", "2 b 2"}},
+		// The missing columns take the default values, the extra fields are discarded.
+		{[]byte("1\ta\n2\tb\t2\tc\n"), []string{"1 a 7", "2 b 2"}},
+		{[]byte("1\t\\N\t\\N\n2\tNULL\t2\n"), []string{"1 <nil> <nil>", "2 NULL 2"}},
+		{[]byte("1\ta\\tb\\\\c\t1\n"), []string{"1 a\tb\\c 1"}},
+	})
+	tk.MustExec("load data local infile '/tmp/nonexistence.csv' into table t")
+	ld := tk.Se.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	tk.Se.SetValue(executor.LoadDataVarKey, nil)
+	c.Assert(tk.Se.NewTxn(context.Background()), IsNil)
+	_, _, err = ld.InsertData(context.Background(), []byte("3\tc\n4\td\t4\te\n"), nil)
+	c.Assert(err, IsNil)
+	c.Assert(ld.CheckAndInsertOneBatch(context.Background()), IsNil)
+	c.Assert(ld.Message(), Equals, "Records: 2  Deleted: 0  Skipped: 0  Warnings: 2")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|",
+		"Warning|1261|Row 1 doesn't contain data for all columns",
+		"Warning|1262|Row 2 was truncated; it contained more data than there were input columns"))
+	c.Assert(tk.Se.StmtCommit(), IsNil)
+	c.Assert(tk.Se.CommitTxn(context.Background()), IsNil)
+
+	sql = `load data local infile '/tmp/nonexistence.csv' into table t fields terminated by ',' enclosed by '"' lines terminated by '\r\n'`
+	checkLoadData(c, tk, sql, []testCase{
+		{[]byte("1,\"a,b\",1\r\n2,\"c\"\"d\",2\r\n"), []string{"1 a,b 1", "2 c\"d 2"}},
+		{[]byte("1,\"a\r\nb\",1\r\n"), []string{"1 a\r\nb 1"}},
+		// The unquoted word NULL means NULL if the fields can be enclosed.
+		{[]byte("1,NULL,1\r\n2,\"NULL\",2\r\n"), []string{"1 <nil> 1", "2 NULL 2"}},
+	})
+
+	sql = "load data local infile '/tmp/nonexistence.csv' into table t fields terminated by '||' lines starting by 'xxx' terminated by '|!' ignore 1 lines"
+	checkLoadData(c, tk, sql, []testCase{
+		{[]byte("xxx0||h||0|!xxx1||a||1|!2||b||2|!yyyxxx3||c|!"), []string{"1 a 1", "3 c 7"}},
+	})
+
+	sql = "load data local infile '/tmp/nonexistence.csv' into table t (c1, id)"
+	checkLoadData(c, tk, sql, []testCase{
+		{[]byte("a\t1\nb\t2\n"), []string{"1 a 7", "2 b 7"}},
+	})
+}
+
+func (s *testSuite4) TestLoadDataDuplicate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int, unique key uk(v))")
+	ctx := context.Background()
+
+	load := func(sql string, data string) *executor.LoadDataInfo {
+		tk.MustExec(sql)
+		ld := tk.Se.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+		tk.Se.SetValue(executor.LoadDataVarKey, nil)
+		c.Assert(tk.Se.NewTxn(ctx), IsNil)
+		_, _, err := ld.InsertData(ctx, []byte(data), nil)
+		c.Assert(err, IsNil)
+		c.Assert(ld.CheckAndInsertOneBatch(ctx), IsNil)
+		c.Assert(tk.Se.StmtCommit(), IsNil)
+		c.Assert(tk.Se.CommitTxn(ctx), IsNil)
+		return ld
+	}
+
+	tk.MustExec("insert into t values (1, 10), (2, 20)")
+	// The duplicate rows are skipped by default.
+	ld := load("load data local infile '/tmp/nonexistence.csv' into table t", "1\t30\n3\t20\n4\t40\n4\t41\n")
+	c.Assert(ld.Message(), Equals, "Records: 4  Deleted: 0  Skipped: 3  Warnings: 3")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "2 20", "4 40"))
+	load("load data local infile '/tmp/nonexistence.csv' ignore into table t", "2\t50\n5\t50\n")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "2 20", "4 40", "5 50"))
+
+	ld = load("load data local infile '/tmp/nonexistence.csv' replace into table t", "1\t11\n6\t20\n7\t70\n")
+	c.Assert(ld.Message(), Equals, "Records: 3  Deleted: 0  Skipped: 0  Warnings: 0")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 11", "4 40", "5 50", "6 20", "7 70"))
+}
+
+func (s *testSuite4) TestLoadDataBatch(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+	ctx := context.Background()
+
+	tk.MustExec("load data local infile '/tmp/nonexistence.csv' into table t fields terminated by ','")
+	ld := tk.Se.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	tk.Se.SetValue(executor.LoadDataVarKey, nil)
+	ld.SetMaxRowsInBatch(2)
+	c.Assert(tk.Se.NewTxn(ctx), IsNil)
+
+	// The incomplete line is returned and parsed with the next packet.
+	rest, reachLimit, err := ld.InsertData(ctx, nil, []byte("1,1\n2,"))
+	c.Assert(err, IsNil)
+	c.Assert(reachLimit, IsFalse)
+	c.Assert(string(rest), Equals, "2,")
+	rest, reachLimit, err = ld.InsertData(ctx, rest, []byte("2\n3,3\n4"))
+	c.Assert(err, IsNil)
+	c.Assert(reachLimit, IsTrue)
+	c.Assert(string(rest), Equals, "3,3\n4")
+	c.Assert(ld.CheckAndInsertOneBatch(ctx), IsNil)
+	c.Assert(tk.Se.StmtCommit(), IsNil)
+	c.Assert(tk.Se.CommitTxn(ctx), IsNil)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2"))
+
+	c.Assert(tk.Se.NewTxn(ctx), IsNil)
+	rest, reachLimit, err = ld.InsertData(ctx, nil, rest)
+	c.Assert(err, IsNil)
+	c.Assert(reachLimit, IsFalse)
+	c.Assert(string(rest), Equals, "4")
+	rest, reachLimit, err = ld.InsertData(ctx, rest, []byte(",4"))
+	c.Assert(err, IsNil)
+	c.Assert(reachLimit, IsFalse)
+	c.Assert(string(rest), Equals, "4,4")
+	// The last line is parsed at the end of the file.
+	rest, reachLimit, err = ld.InsertData(ctx, rest, nil)
+	c.Assert(err, IsNil)
+	c.Assert(reachLimit, IsTrue)
+	c.Assert(rest, HasLen, 0)
+	c.Assert(ld.CheckAndInsertOneBatch(ctx), IsNil)
+	c.Assert(tk.Se.StmtCommit(), IsNil)
+	c.Assert(tk.Se.CommitTxn(ctx), IsNil)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2", "3 3", "4 4"))
+}
+
+func (s *testSuite4) TestUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	s.fillData(tk, "update_test")
+
+	updateStr := `UPDATE update_test SET name = "abc" where id > 0;`
+	tk.MustExec(updateStr)
+	tk.CheckExecResult(2, 0)
+
+	// select data
+	tk.MustExec("begin")
+	r := tk.MustQuery(`SELECT * from update_test limit 2;`)
+	r.Check(testkit.Rows("1 abc", "2 abc"))
+	tk.MustExec("commit")
+
+	tk.MustExec(`UPDATE update_test SET name = "foo"`)
+	tk.CheckExecResult(2, 0)
+
+	// table option is auto-increment
+	tk.MustExec("begin")
+	tk.MustExec("drop table if exists update_test;")
+	tk.MustExec("commit")
+	tk.MustExec("begin")
+	tk.MustExec("create table update_test(id int not null auto_increment, name varchar(255), primary key(id))")
+	tk.MustExec("insert into update_test values (null, 'aa')")
+	tk.MustExec("update update_test set id = 8 where name = 'aa'")
+	tk.CheckExecResult(1, 0)
+	tk.MustExec("insert into update_test values (null, 'bb')")
+	tk.MustExec("commit")
+	tk.MustExec("begin")
+	r = tk.MustQuery("select * from update_test;")
+	r.Check(testkit.Rows("8 aa", "9 bb"))
+	tk.MustExec("commit")
+
+	tk.MustExec("begin")
+	tk.MustExec("drop table if exists update_test;")
+	tk.MustExec("commit")
+	tk.MustExec("begin")
+	tk.MustExec("create table update_test(id int not null auto_increment, name varchar(255), index(id))")
+	tk.MustExec("insert into update_test values (null, 'aa')")
+	_, err := tk.Exec("update update_test set id = null where name = 'aa'")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), DeepEquals, "[table:1048]Column 'id' cannot be null")
+	tk.MustExec("rollback")
+
+	tk.MustExec("drop table update_test")
+
+	// unchanged rows are matched but not affected
+	tk.MustExec("create table update_test(a int, b int)")
+	tk.MustExec("insert into update_test values (1, 1), (2, 2)")
+	tk.MustExec("update update_test set b = 1")
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("1 1", "2 1"))
+	tk.MustExec("update update_test set b = b + a where a > 1")
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("1 1", "2 3"))
+	tk.MustExec("update update_test set b = default")
+	tk.MustQuery("select * from update_test").Check(testkit.Rows("1 <nil>", "2 <nil>"))
+	tk.MustExec("drop table update_test")
+
+	// unknown columns and subquery tables can not be updated
+	tk.MustExec("create table update_test(a int)")
+	_, err = tk.Exec("update update_test set c = 1")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("update (select a from update_test) t set t.a = 1")
+	c.Assert(err, NotNil)
+	tk.MustExec("drop table update_test")
+}
+
+func (s *testSuite4) TestUpdateWithOrderAndLimit(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("update t set b = 10 order by a desc limit 2")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2", "3 10", "4 10"))
+
+	// update the primary key in descending order to avoid conflicts
+	tk.MustExec("update t set a = a + 1 order by a desc")
+	tk.CheckExecResult(4, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 1", "3 2", "4 10", "5 10"))
+
+	tk.MustExec("update t set b = 0 where a > 2 limit 1")
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 1", "3 0", "4 10", "5 10"))
+}
+
+func (s *testSuite4) TestUpdateDuplicateKey(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, unique key(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	// primary key conflict
+	_, err := tk.Exec("update t set a = 2 where a = 1")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '2' for key 'PRIMARY'")
+	// unique key conflict
+	_, err = tk.Exec("update t set b = 2 where a = 1")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '2' for key 'b'")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2"))
+
+	// update ignore turns the duplicate errors into warnings
+	tk.MustExec("update ignore t set a = 2 where a = 1")
+	tk.CheckExecResult(0, 0)
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1062 Duplicate entry '2' for key 'PRIMARY'"))
+	tk.MustExec("update ignore t set b = b + 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1062 Duplicate entry '2' for key 'b'"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 3"))
+
+	// change the unique key and the primary key together
+	tk.MustExec("update t set a = 3, b = 4 where a = 2")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "3 4"))
+	tk.MustQuery("select a from t where b = 4").Check(testkit.Rows("3"))
+}
+
+func (s *testSuite4) TestMultiUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (id int primary key, v int)")
+	tk.MustExec("create table t2 (id int primary key, v int)")
+	tk.MustExec("insert into t1 values (1, 10), (2, 20), (3, 30)")
+	tk.MustExec("insert into t2 values (1, 100), (2, 200), (4, 400)")
+
+	tk.MustExec("update t1, t2 set t1.v = t2.v where t1.id = t2.id")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 100", "2 200", "3 30"))
+
+	tk.MustExec("update t1, t2 set t1.v = 0, t2.v = 0 where t1.id = t2.id and t1.id = 1")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 0", "2 200", "3 30"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 0", "2 200", "4 400"))
+
+	// the inner side of an outer join may be NULL and must not be updated
+	tk.MustExec("update t1 left join t2 on t1.id = t2.id set t1.v = 1, t2.v = 1")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 1", "2 1", "3 1"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1 1", "2 1", "4 400"))
+
+	// a row matched several times is only updated once
+	tk.MustExec("update t1 a, t2 b set a.v = a.v + 1")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 2", "2 2", "3 2"))
+
+	tk.MustExec("update t1 as a join t2 as b on a.id = b.id set a.v = b.v + a.v where b.id = 2")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 2", "2 3", "3 2"))
+}
+
 func (s *testSuite) TestDelete(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	s.fillData(tk, "delete_test")
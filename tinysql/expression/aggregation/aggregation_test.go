@@ -76,17 +76,18 @@ func (s *testAggFuncSuit) TestAvg(c *C) {
 		c.Assert(err, IsNil)
 	}
 	result = avgFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(67))
+	needed := types.NewDecFromStringForTest("67.000000000000000000000000000000")
+	c.Assert(result.GetMysqlDecimal().Compare(needed) == 0, IsTrue)
 	err = avgFunc.Update(evalCtx, s.ctx.GetSessionVars().StmtCtx, s.nullRow)
 	c.Assert(err, IsNil)
 	result = avgFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(67))
+	c.Assert(result.GetMysqlDecimal().Compare(needed) == 0, IsTrue)
 }
 
 func (s *testAggFuncSuit) TestAvgFinalMode(c *C) {
 	rows := make([][]types.Datum, 0, 100)
 	for i := 1; i <= 100; i++ {
-		rows = append(rows, types.MakeDatums(i, int64(i*i)))
+		rows = append(rows, types.MakeDatums(i, types.NewDecFromInt(int64(i*i))))
 	}
 	ctx := mock.NewContext()
 	cntCol := &expression.Column{
@@ -95,10 +96,13 @@ func (s *testAggFuncSuit) TestAvgFinalMode(c *C) {
 	}
 	sumCol := &expression.Column{
 		Index:   1,
-		RetType: types.NewFieldType(mysql.TypeLonglong),
+		RetType: types.NewFieldType(mysql.TypeNewDecimal),
 	}
 	aggFunc, err := NewAggFuncDesc(s.ctx, ast.AggFuncAvg, []expression.Expression{cntCol, sumCol})
 	c.Assert(err, IsNil)
+	// The args of a final mode aggregation are the partial results, which
+	// are never wrapped with cast.
+	aggFunc.Args = []expression.Expression{cntCol, sumCol}
 	aggFunc.Mode = FinalMode
 	avgFunc := aggFunc.GetAggFunc(ctx)
 	evalCtx := avgFunc.CreateContext(s.ctx.GetSessionVars().StmtCtx)
@@ -108,7 +112,8 @@ func (s *testAggFuncSuit) TestAvgFinalMode(c *C) {
 		c.Assert(err, IsNil)
 	}
 	result := avgFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(67))
+	needed := types.NewDecFromStringForTest("67.000000000000000000000000000000")
+	c.Assert(result.GetMysqlDecimal().Compare(needed) == 0, IsTrue)
 }
 
 func (s *testAggFuncSuit) TestSum(c *C) {
@@ -130,13 +135,14 @@ func (s *testAggFuncSuit) TestSum(c *C) {
 		c.Assert(err, IsNil)
 	}
 	result = sumFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(338350))
+	needed := types.NewDecFromStringForTest("338350")
+	c.Assert(result.GetMysqlDecimal().Compare(needed) == 0, IsTrue)
 	err = sumFunc.Update(evalCtx, s.ctx.GetSessionVars().StmtCtx, s.nullRow)
 	c.Assert(err, IsNil)
 	result = sumFunc.GetResult(evalCtx)
-	c.Assert(result.GetInt64(), Equals, int64(338350))
+	c.Assert(result.GetMysqlDecimal().Compare(needed) == 0, IsTrue)
 	partialResult := sumFunc.GetPartialResult(evalCtx)
-	c.Assert(partialResult[0].GetInt64(), Equals, int64(338350))
+	c.Assert(partialResult[0].GetMysqlDecimal().Compare(needed) == 0, IsTrue)
 }
 
 func (s *testAggFuncSuit) TestCount(c *C) {
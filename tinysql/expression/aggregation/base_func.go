@@ -15,10 +15,9 @@ package aggregation
 
 import (
 	"bytes"
-	"fmt"
-	log "github.com/sirupsen/logrus"
 	"strings"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
@@ -40,18 +39,7 @@ type baseFuncDesc struct {
 func newBaseFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression) (baseFuncDesc, error) {
 	b := baseFuncDesc{Name: strings.ToLower(name), Args: args}
 	err := b.typeInfer(ctx)
-	if err != nil {
-		return b, err
-	}
-	if _, ok := noNeedCastAggFuncs[name]; ok {
-		return b, nil
-	}
-	for _, arg := range args {
-		if arg.GetType().EvalType() != b.RetTp.EvalType() {
-			log.Warn(fmt.Sprintf("unmatched arg tp %v with return tp %v", arg.GetType().EvalType(), b.RetTp.EvalType()))
-		}
-	}
-	return b, nil
+	return b, err
 }
 
 func (a *baseFuncDesc) equal(ctx sessionctx.Context, other *baseFuncDesc) bool {
@@ -100,8 +88,13 @@ func (a *baseFuncDesc) typeInfer(ctx sessionctx.Context) error {
 		a.typeInfer4Sum(ctx)
 	case ast.AggFuncAvg:
 		a.typeInfer4Avg(ctx)
-	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
+	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow,
+		ast.WindowFuncFirstValue, ast.WindowFuncLastValue:
 		a.typeInfer4MaxMin(ctx)
+	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		a.typeInfer4NumberFuncs()
+	case ast.WindowFuncLead, ast.WindowFuncLag:
+		a.typeInfer4LeadLag(ctx)
 	default:
 		return errors.Errorf("unsupported agg function: %s", a.Name)
 	}
@@ -119,7 +112,14 @@ func (a *baseFuncDesc) typeInfer4Count(ctx sessionctx.Context) {
 func (a *baseFuncDesc) typeInfer4Sum(ctx sessionctx.Context) {
 	switch a.Args[0].GetType().Tp {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
-		a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, 0
+	case mysql.TypeNewDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, a.Args[0].GetType().Decimal
+		if a.RetTp.Decimal < 0 || a.RetTp.Decimal > mysql.MaxDecimalScale {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		}
 	case mysql.TypeDouble, mysql.TypeFloat:
 		a.RetTp = types.NewFieldType(mysql.TypeDouble)
 		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, a.Args[0].GetType().Decimal
@@ -134,8 +134,14 @@ func (a *baseFuncDesc) typeInfer4Sum(ctx sessionctx.Context) {
 // Because child returns integer or decimal type.
 func (a *baseFuncDesc) typeInfer4Avg(ctx sessionctx.Context) {
 	switch a.Args[0].GetType().Tp {
-	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
-		a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeNewDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		if a.Args[0].GetType().Decimal < 0 {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		} else {
+			a.RetTp.Decimal = mathutil.Min(a.Args[0].GetType().Decimal+types.DivFracIncr, mysql.MaxDecimalScale)
+		}
+		a.RetTp.Flen = mysql.MaxDecimalWidth
 	case mysql.TypeDouble, mysql.TypeFloat:
 		a.RetTp = types.NewFieldType(mysql.TypeDouble)
 		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, a.Args[0].GetType().Decimal
@@ -148,7 +154,9 @@ func (a *baseFuncDesc) typeInfer4Avg(ctx sessionctx.Context) {
 
 func (a *baseFuncDesc) typeInfer4MaxMin(ctx sessionctx.Context) {
 	a.RetTp = a.Args[0].GetType()
-	if (a.Name == ast.AggFuncMax || a.Name == ast.AggFuncMin) && a.RetTp.Tp != mysql.TypeBit {
+	// Except for firstrow, these functions may return NULL even if the argument is not nullable,
+	// e.g. max on an empty set or lead beyond the partition.
+	if a.Name != ast.AggFuncFirstRow && a.RetTp.Tp != mysql.TypeBit {
 		a.RetTp = a.Args[0].GetType().Clone()
 		a.RetTp.Flag &^= mysql.NotNullFlag
 	}
@@ -157,6 +165,21 @@ func (a *baseFuncDesc) typeInfer4MaxMin(ctx sessionctx.Context) {
 	}
 }
 
+func (a *baseFuncDesc) typeInfer4NumberFuncs() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+func (a *baseFuncDesc) typeInfer4LeadLag(ctx sessionctx.Context) {
+	if len(a.Args) <= 2 {
+		a.typeInfer4MaxMin(ctx)
+	} else {
+		// Merge the type of first and third argument.
+		a.RetTp = expression.InferType4ControlFuncs(a.Args[0].GetType(), a.Args[2].GetType())
+	}
+}
+
 // GetDefaultValue gets the default value when the function's input is null.
 // According to MySQL, default values of the function are listed as follows:
 // e.g.
@@ -191,4 +214,51 @@ var noNeedCastAggFuncs = map[string]struct{}{
 	ast.AggFuncMax:      {},
 	ast.AggFuncMin:      {},
 	ast.AggFuncFirstRow: {},
+
+	ast.WindowFuncFirstValue: {},
+	ast.WindowFuncLastValue:  {},
+	ast.WindowFuncLead:       {},
+	ast.WindowFuncLag:        {},
+}
+
+// WrapCastForAggArgs wraps the args of an aggregate function with a cast function.
+func (a *baseFuncDesc) WrapCastForAggArgs(ctx sessionctx.Context) {
+	if len(a.Args) == 0 {
+		return
+	}
+	if _, ok := noNeedCastAggFuncs[a.Name]; ok {
+		return
+	}
+	var castFunc func(ctx sessionctx.Context, expr expression.Expression) expression.Expression
+	switch retTp := a.RetTp; retTp.EvalType() {
+	case types.ETInt:
+		castFunc = expression.WrapWithCastAsInt
+	case types.ETReal:
+		castFunc = expression.WrapWithCastAsReal
+	case types.ETDecimal:
+		castFunc = expression.WrapWithCastAsDecimal
+	case types.ETString:
+		castFunc = expression.WrapWithCastAsString
+	default:
+		panic("should never happen in baseFuncDesc.WrapCastForAggArgs")
+	}
+	for i := range a.Args {
+		a.Args[i] = castFunc(ctx, a.Args[i])
+		if a.Name != ast.AggFuncAvg && a.Name != ast.AggFuncSum {
+			continue
+		}
+		// After wrapping cast on the argument, flen etc. may not be the same
+		// as the type of the aggregation function. Set the type of the
+		// argument exactly as the type of the aggregation function. The
+		// RetType of a Column refers to the infoschema, so it is replaced
+		// with a new one instead of being modified in place.
+		if col, ok := a.Args[i].(*expression.Column); ok {
+			col.RetType = types.NewFieldType(col.RetType.Tp)
+		}
+		// originTp is used when the Tp of the column is TypeFloat while
+		// the type of the aggregation function is TypeDouble.
+		originTp := a.Args[i].GetType().Tp
+		*(a.Args[i].GetType()) = *(a.RetTp)
+		a.Args[i].GetType().Tp = originTp
+	}
 }
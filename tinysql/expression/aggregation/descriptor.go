@@ -34,6 +34,7 @@ func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expre
 	if err != nil {
 		return nil, err
 	}
+	b.WrapCastForAggArgs(ctx)
 	return &AggFuncDesc{baseFuncDesc: b}, nil
 }
 
@@ -49,6 +50,30 @@ func (a *AggFuncDesc) Clone() *AggFuncDesc {
 	return &clone
 }
 
+// ToCopPartial returns the aggregate function computed by the coprocessor in
+// the partial phase. SUM and AVG over integers return DECIMAL, which the
+// coprocessor can't evaluate, so their partial results are computed as BIGINT
+// over the original argument, the final phase converts them to DECIMAL.
+func (a *AggFuncDesc) ToCopPartial() *AggFuncDesc {
+	if (a.Name != ast.AggFuncSum && a.Name != ast.AggFuncAvg) || a.Mode != CompleteMode {
+		return a
+	}
+	cast, ok := a.Args[0].(*expression.ScalarFunction)
+	if !ok || cast.FuncName.L != ast.Cast || a.RetTp.EvalType() != types.ETDecimal {
+		return a
+	}
+	arg := cast.GetArgs()[0]
+	if arg.GetType().EvalType() != types.ETInt || mysql.HasUnsignedFlag(arg.GetType().Flag) {
+		return a
+	}
+	partial := a.Clone()
+	partial.Args = []expression.Expression{arg}
+	partial.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	partial.RetTp.Flen, partial.RetTp.Decimal = mysql.MaxIntWidth, 0
+	types.SetBinChsClnFlag(partial.RetTp)
+	return partial
+}
+
 // Split splits `a` into two aggregate descriptors for partial phase and
 // final phase individually.
 // This function is only used when executing aggregate function parallelly.
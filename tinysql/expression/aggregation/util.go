@@ -62,10 +62,15 @@ func calculateSum(sc *stmtctx.StatementContext, sum, v types.Datum) (data types.
 	switch v.Kind() {
 	case types.KindNull:
 	case types.KindInt64, types.KindUint64:
-		d, err := v.ToInt64(sc)
+		// Only the partial SUM and AVG over integers pushed down to the
+		// coprocessor get integer values, see AggFuncDesc.ToCopPartial.
+		var d int64
+		d, err = v.ToInt64(sc)
 		if err == nil {
 			data = types.NewIntDatum(d)
 		}
+	case types.KindMysqlDecimal:
+		data = *v.Copy()
 	default:
 		var f float64
 		f, err = v.ToFloat64(sc)
@@ -83,7 +88,7 @@ func calculateSum(sc *stmtctx.StatementContext, sum, v types.Datum) (data types.
 	switch sum.Kind() {
 	case types.KindNull:
 		return data, nil
-	case types.KindFloat64, types.KindInt64:
+	case types.KindFloat64, types.KindInt64, types.KindMysqlDecimal:
 		return types.ComputePlus(sum, data)
 	default:
 		return data, errors.Errorf("invalid value %v for aggregate", sum.Kind())
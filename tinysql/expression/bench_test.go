@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
 )
@@ -73,8 +74,36 @@ func (g *defaultGener) gen() interface{} {
 			return -rand.Float64() * 1000000
 		}
 		return rand.Float64() * 1000000
+	case types.ETDecimal:
+		d := new(types.MyDecimal)
+		var f float64
+		if rand.Float64() < 0.5 {
+			f = rand.Float64() * 100000
+		} else {
+			f = -rand.Float64() * 100000
+		}
+		if err := d.FromFloat64(f); err != nil {
+			panic(err)
+		}
+		return d
+	case types.ETDatetime, types.ETTimestamp:
+		gt := getRandomTime()
+		t := types.NewTime(gt, convertETType(g.eType), 0)
+		return t
+	case types.ETDuration:
+		d := types.Duration{
+			// use rand.Int31() to make it not overflow when AddDuration
+			Duration: time.Duration(rand.Int31()),
+		}
+		return d
 	case types.ETString:
 		return randString()
+	case types.ETJson:
+		m := make(map[string]interface{}, 5)
+		for j := 0; j < 5; j++ {
+			m[randString()] = rand.Int63n(100)
+		}
+		return json.CreateBinary(m)
 	}
 	return nil
 }
@@ -110,6 +139,30 @@ func (g *rangeRealGener) gen() interface{} {
 	return rand.Float64()*(g.end-g.begin) + g.begin
 }
 
+// rangeDecimalGener is used to generate decimal items in [begin, end].
+type rangeDecimalGener struct {
+	begin float64
+	end   float64
+
+	nullRation float64
+}
+
+func (g *rangeDecimalGener) gen() interface{} {
+	if rand.Float64() < g.nullRation {
+		return nil
+	}
+	if g.end < g.begin {
+		g.begin = -100000
+		g.end = 100000
+	}
+	d := new(types.MyDecimal)
+	f := rand.Float64()*(g.end-g.begin) + g.begin
+	if err := d.FromFloat64(f); err != nil {
+		panic(err)
+	}
+	return d
+}
+
 // rangeInt64Gener is used to generate int64 items in [begin, end).
 type rangeInt64Gener struct {
 	begin int
@@ -206,12 +259,25 @@ func fillColumnWithGener(eType types.EvalType, chk *chunk.Chunk, colIdx int, gen
 			col.AppendInt64(v.(int64))
 		case types.ETReal:
 			col.AppendFloat64(v.(float64))
+		case types.ETDecimal:
+			col.AppendMyDecimal(v.(*types.MyDecimal))
+		case types.ETDatetime, types.ETTimestamp:
+			col.AppendTime(v.(types.Time))
+		case types.ETDuration:
+			col.AppendDuration(v.(types.Duration))
 		case types.ETString:
 			col.AppendString(v.(string))
+		case types.ETJson:
+			col.AppendJSON(v.(json.BinaryJSON))
 		}
 	}
 }
 
+func getRandomTime() types.CoreTime {
+	return types.FromDate(rand.Intn(2200), rand.Intn(10)+1, rand.Intn(20)+1,
+		rand.Intn(12), rand.Intn(60), rand.Intn(60), rand.Intn(1000000))
+}
+
 func randString() string {
 	n := 10 + rand.Intn(10)
 	buf := make([]byte, n)
@@ -234,8 +300,16 @@ func eType2FieldType(eType types.EvalType) *types.FieldType {
 		return types.NewFieldType(mysql.TypeLonglong)
 	case types.ETReal:
 		return types.NewFieldType(mysql.TypeDouble)
+	case types.ETDecimal:
+		return types.NewFieldType(mysql.TypeNewDecimal)
+	case types.ETDatetime, types.ETTimestamp:
+		return types.NewFieldType(mysql.TypeDatetime)
+	case types.ETDuration:
+		return types.NewFieldType(mysql.TypeDuration)
 	case types.ETString:
 		return types.NewFieldType(mysql.TypeVarString)
+	case types.ETJson:
+		return types.NewFieldType(mysql.TypeJSON)
 	default:
 		panic(fmt.Sprintf("EvalType=%v is not supported.", eType))
 	}
@@ -305,6 +379,27 @@ func testVectorizedEvalOneVec(c *C, vecExprCases vecExprBenchCases) {
 						c.Assert(c1.GetFloat64(i), Equals, c2.GetFloat64(i), commentf(i))
 					}
 				}
+			case types.ETDecimal:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(c1.GetDecimal(i).Compare(c2.GetDecimal(i)), Equals, 0, commentf(i))
+					}
+				}
+			case types.ETDatetime, types.ETTimestamp:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(c1.GetTime(i).Compare(c2.GetTime(i)), Equals, 0, commentf(i))
+					}
+				}
+			case types.ETDuration:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(c1.GetDuration(i, 0), Equals, c2.GetDuration(i, 0), commentf(i))
+					}
+				}
 			case types.ETString:
 				for i := 0; i < input.NumRows(); i++ {
 					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
@@ -312,6 +407,13 @@ func testVectorizedEvalOneVec(c *C, vecExprCases vecExprBenchCases) {
 						c.Assert(c1.GetString(i), Equals, c2.GetString(i), commentf(i))
 					}
 				}
+			case types.ETJson:
+				for i := 0; i < input.NumRows(); i++ {
+					c.Assert(c1.IsNull(i), Equals, c2.IsNull(i), commentf(i))
+					if !c1.IsNull(i) {
+						c.Assert(json.CompareBinary(c1.GetJSON(i), c2.GetJSON(i)), Equals, 0, commentf(i))
+					}
+				}
 			}
 		}
 	}
@@ -379,7 +481,26 @@ func genVecBuiltinFuncBenchCase(ctx sessionctx.Context, funcName string, testCas
 		input.SetNumVirtualRows(testCase.chunkSize)
 	}
 
-	baseFunc, err := funcs[funcName].getFunction(ctx, cols)
+	var err error
+	if funcName == ast.Cast {
+		var fc functionClass
+		tp := eType2FieldType(testCase.retEvalType)
+		switch testCase.retEvalType {
+		case types.ETInt:
+			fc = &castAsIntFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		case types.ETReal:
+			fc = &castAsRealFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		case types.ETString:
+			fc = &castAsStringFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		case types.ETJson:
+			fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		default:
+			panic(fmt.Sprintf("EvalType=%v is not supported.", testCase.retEvalType))
+		}
+		baseFunc, err = fc.getFunction(ctx, cols)
+	} else {
+		baseFunc, err = funcs[funcName].getFunction(ctx, cols)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -473,6 +594,54 @@ func testVectorizedBuiltinFunc(c *C, vecExprCases vecExprBenchCases) {
 					}
 					i++
 				}
+			case types.ETDecimal:
+				err := baseFunc.vecEvalDecimal(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				d64s := output.Decimals()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalDecimal(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Compare(&d64s[i]), Equals, 0, commentf(i))
+					}
+					i++
+				}
+			case types.ETDatetime, types.ETTimestamp:
+				err := baseFunc.vecEvalTime(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				t64s := output.Times()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalTime(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Compare(t64s[i]), Equals, 0, commentf(i))
+					}
+					i++
+				}
+			case types.ETDuration:
+				err := baseFunc.vecEvalDuration(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				d64s := output.GoDurations()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalDuration(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(val.Duration, Equals, d64s[i], commentf(i))
+					}
+					i++
+				}
 			case types.ETString:
 				err := baseFunc.vecEvalString(input, output)
 				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
@@ -488,6 +657,21 @@ func testVectorizedBuiltinFunc(c *C, vecExprCases vecExprBenchCases) {
 					}
 					i++
 				}
+			case types.ETJson:
+				err := baseFunc.vecEvalJSON(input, output)
+				c.Assert(err, IsNil, Commentf("func: %v, case: %+v", baseFuncName, testCase))
+				// do not forget to call ResizeXXX/ReserveXXX
+				c.Assert(getColumnLen(output, testCase.retEvalType), Equals, input.NumRows())
+				vecWarnCnt = ctx.GetSessionVars().StmtCtx.WarningCount()
+				for row := it.Begin(); row != it.End(); row = it.Next() {
+					val, isNull, err := baseFunc.evalJSON(row)
+					c.Assert(err, IsNil, commentf(i))
+					c.Assert(isNull, Equals, output.IsNull(i), commentf(i))
+					if !isNull {
+						c.Assert(json.CompareBinary(val, output.GetJSON(i)), Equals, 0, commentf(i))
+					}
+					i++
+				}
 			default:
 				c.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 			}
@@ -543,12 +727,36 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							b.Fatal(err)
 						}
 					}
+				case types.ETDecimal:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalDecimal(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
+				case types.ETDatetime, types.ETTimestamp:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalTime(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
+				case types.ETDuration:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalDuration(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
 				case types.ETString:
 					for i := 0; i < b.N; i++ {
 						if err := baseFunc.vecEvalString(input, output); err != nil {
 							b.Fatal(err)
 						}
 					}
+				case types.ETJson:
+					for i := 0; i < b.N; i++ {
+						if err := baseFunc.vecEvalJSON(input, output); err != nil {
+							b.Fatal(err)
+						}
+					}
 				default:
 					b.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 				}
@@ -587,6 +795,51 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							}
 						}
 					}
+				case types.ETDecimal:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalDecimal(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendMyDecimal(v)
+							}
+						}
+					}
+				case types.ETDatetime, types.ETTimestamp:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalTime(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendTime(v)
+							}
+						}
+					}
+				case types.ETDuration:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalDuration(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendDuration(v)
+							}
+						}
+					}
 				case types.ETString:
 					for i := 0; i < b.N; i++ {
 						output.Reset(testCase.retEvalType)
@@ -602,6 +855,21 @@ func benchmarkVectorizedBuiltinFunc(b *testing.B, vecExprCases vecExprBenchCases
 							}
 						}
 					}
+				case types.ETJson:
+					for i := 0; i < b.N; i++ {
+						output.Reset(testCase.retEvalType)
+						for row := it.Begin(); row != it.End(); row = it.Next() {
+							v, isNull, err := baseFunc.evalJSON(row)
+							if err != nil {
+								b.Fatal(err)
+							}
+							if isNull {
+								output.AppendNull()
+							} else {
+								output.AppendJSON(v)
+							}
+						}
+					}
 				default:
 					b.Fatal(fmt.Sprintf("evalType=%v is not supported", testCase.retEvalType))
 				}
@@ -674,7 +942,7 @@ func generateRandomSel() []int {
 
 func (s *testEvaluatorSuite) TestVecEvalBool(c *C) {
 	ctx := mock.NewContext()
-	eTypes := []types.EvalType{types.ETReal, types.ETString}
+	eTypes := []types.EvalType{types.ETReal, types.ETDecimal, types.ETString}
 	for numCols := 1; numCols <= 5; numCols++ {
 		for round := 0; round < 16; round++ {
 			exprs, input := genVecEvalBool(numCols, nil, eTypes)
@@ -14,9 +14,12 @@
 package expression
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testutil"
@@ -291,3 +294,139 @@ func (s *testEvaluatorSuite) TestArithmeticMultiply(c *C) {
 		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect))
 	}
 }
+
+func (s *testEvaluatorSuite) TestArithmeticIntDivide(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+		err    error
+	}{
+		{
+			args:   []interface{}{int64(13), int64(11)},
+			expect: int64(1),
+		},
+		{
+			args:   []interface{}{int64(-13), int64(11)},
+			expect: int64(-1),
+		},
+		{
+			args:   []interface{}{uint64(13), int64(-11)},
+			expect: nil,
+			err:    types.ErrOverflow,
+		},
+		{
+			args:   []interface{}{int64(-22), uint64(11)},
+			expect: nil,
+			err:    types.ErrOverflow,
+		},
+		{
+			args:   []interface{}{uint64(13), uint64(11)},
+			expect: uint64(1),
+		},
+		{
+			args:   []interface{}{int64(13), int64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{float64(13.5), float64(3)},
+			expect: int64(4),
+		},
+		{
+			args:   []interface{}{float64(-13.5), float64(3)},
+			expect: int64(-4),
+		},
+		{
+			args:   []interface{}{float64(13.5), float64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{float64(1e30), float64(1)},
+			expect: nil,
+			err:    types.ErrOverflow,
+		},
+		{
+			args:   []interface{}{nil, int64(1)},
+			expect: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		sig, err := funcs[ast.IntDiv].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tc.args...)))
+		c.Assert(err, IsNil)
+		c.Assert(sig, NotNil)
+		val, err := evalBuiltinFunc(sig, chunk.Row{})
+		if tc.err != nil {
+			c.Assert(tc.err.(*terror.Error).Equal(err), IsTrue, Commentf("%v", tc.args))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect), Commentf("%v", tc.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestArithmeticMod(c *C) {
+	testCases := []struct {
+		args   []interface{}
+		expect interface{}
+	}{
+		{
+			args:   []interface{}{int64(13), int64(11)},
+			expect: int64(2),
+		},
+		{
+			args:   []interface{}{int64(-13), int64(11)},
+			expect: int64(-2),
+		},
+		{
+			args:   []interface{}{int64(13), int64(-11)},
+			expect: int64(2),
+		},
+		{
+			args:   []interface{}{uint64(13), int64(-11)},
+			expect: uint64(2),
+		},
+		{
+			args:   []interface{}{int64(-13), uint64(11)},
+			expect: int64(-2),
+		},
+		{
+			args:   []interface{}{uint64(math.MaxUint64), uint64(10)},
+			expect: uint64(5),
+		},
+		{
+			args:   []interface{}{int64(13), int64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{float64(13.5), float64(3)},
+			expect: float64(1.5),
+		},
+		{
+			args:   []interface{}{float64(-13.5), float64(3)},
+			expect: float64(-1.5),
+		},
+		{
+			args:   []interface{}{float64(13.5), float64(0)},
+			expect: nil,
+		},
+		{
+			args:   []interface{}{nil, int64(1)},
+			expect: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		sig, err := funcs[ast.Mod].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tc.args...)))
+		c.Assert(err, IsNil)
+		c.Assert(sig, NotNil)
+		val, err := evalBuiltinFunc(sig, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(val, testutil.DatumEquals, types.NewDatum(tc.expect), Commentf("%v", tc.args))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.Mod, s.primitiveValsToConstants([]interface{}{types.NewDecFromStringForTest("13.55"), int64(3)})...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlDecimal().String(), Equals, "1.55")
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestCastFunctions(c *C) {
+	intTp := types.NewFieldType(mysql.TypeLonglong)
+	realTp := types.NewFieldType(mysql.TypeDouble)
+	strTp := types.NewFieldType(mysql.TypeVarString)
+	strTp.Flen = types.UnspecifiedLength
+	cases := []struct {
+		arg    interface{}
+		tp     *types.FieldType
+		expect interface{}
+	}{
+		{int64(1), intTp, int64(1)},
+		{int64(-1), realTp, float64(-1)},
+		{int64(-12), strTp, "-12"},
+		{float64(1.4), intTp, int64(1)},
+		{float64(1.5), intTp, int64(2)},
+		{float64(1.5), realTp, float64(1.5)},
+		{float64(1.25), strTp, "1.25"},
+		{"123", intTp, int64(123)},
+		{"-1.5", realTp, float64(-1.5)},
+		{"abc", strTp, "abc"},
+		{nil, intTp, nil},
+	}
+	for _, t := range cases {
+		arg := s.primitiveValsToConstants([]interface{}{t.arg})[0]
+		f := BuildCastFunction(s.ctx, arg, t.tp)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.expect == nil {
+			c.Assert(d.IsNull(), IsTrue)
+			continue
+		}
+		c.Assert(d.GetValue(), Equals, t.expect)
+	}
+}
+
+func (s *testEvaluatorSuite) TestCastFunction4Union(c *C) {
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	tp.Flag |= mysql.UnsignedFlag
+	col := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	f := BuildCastFunction4Union(s.ctx, col, tp)
+	c.Assert(s.ctx.Value(inUnionCastContext), IsNil)
+
+	chk := chunk.NewChunkWithCapacity([]*types.FieldType{col.RetType}, 1)
+	chk.AppendInt64(0, -1)
+	res, isNull, err := f.EvalInt(s.ctx, chk.GetRow(0))
+	c.Assert(err, IsNil)
+	c.Assert(isNull, IsFalse)
+	c.Assert(res, Equals, int64(0))
+
+	f = BuildCastFunction(s.ctx, col, tp)
+	res, _, err = f.EvalInt(s.ctx, chk.GetRow(0))
+	c.Assert(err, IsNil)
+	c.Assert(res, Equals, int64(-1))
+}
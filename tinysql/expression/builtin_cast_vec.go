@@ -0,0 +1,303 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinCastIntAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastIntAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	nums := buf.Int64s()
+	isBool := mysql.HasIsBooleanFlag(b.args[0].GetType().Flag)
+	isUnsigned := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if isBool {
+			result.AppendJSON(json.CreateBinary(nums[i] != 0))
+		} else if isUnsigned {
+			result.AppendJSON(json.CreateBinary(uint64(nums[i])))
+		} else {
+			result.AppendJSON(json.CreateBinary(nums[i]))
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastRealAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastRealAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	f64s := buf.Float64s()
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(json.CreateBinary(f64s[i]))
+	}
+	return nil
+}
+
+func (b *builtinCastDecimalAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastDecimalAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	decs := buf.Decimals()
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		f64, err := decs[i].ToFloat64()
+		if err != nil {
+			return err
+		}
+		result.AppendJSON(json.CreateBinary(f64))
+	}
+	return nil
+}
+
+func (b *builtinCastStringAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastStringAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	parse := mysql.HasParseToJSONFlag(b.tp.Flag)
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if !parse {
+			result.AppendJSON(json.CreateBinary(buf.GetString(i)))
+			continue
+		}
+		res, err := json.ParseBinaryFromString(buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendJSON(res)
+	}
+	return nil
+}
+
+func (b *builtinCastTimeAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastTimeAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	times := buf.Times()
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		t := times[i]
+		if t.Type() == mysql.TypeDatetime || t.Type() == mysql.TypeTimestamp {
+			t.SetFsp(types.MaxFsp)
+		}
+		result.AppendJSON(json.CreateBinary(t.String()))
+	}
+	return nil
+}
+
+func (b *builtinCastDurationAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastDurationAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDuration, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDuration(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(json.CreateBinary(buf.GetDuration(i, int(types.MaxFsp)).String()))
+	}
+	return nil
+}
+
+func (b *builtinCastJSONAsJSONSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastJSONAsJSONSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalJSON(b.ctx, input, result)
+}
+
+func (b *builtinCastJSONAsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastJSONAsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	sc := b.ctx.GetSessionVars().StmtCtx
+	unsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i], err = types.ConvertJSONToInt(sc, buf.GetJSON(i), unsigned)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastJSONAsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastJSONAsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	sc := b.ctx.GetSessionVars().StmtCtx
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		f64s[i], err = types.ConvertJSONToFloat(sc, buf.GetJSON(i))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastJSONAsStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCastJSONAsStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETJson, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(buf.GetJSON(i).String())
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinCastCases = map[string][]vecExprBenchCase{
+	ast.Cast: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETInt}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETReal}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETDecimal}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETDatetime}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETDuration}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson}, geners: []dataGenerator{&jsonScalarGener{}}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETJson}, geners: []dataGenerator{&jsonScalarGener{}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETJson}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinCastEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinCastCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinCastFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinCastCases)
+}
+
+func BenchmarkVectorizedBuiltinCastEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinCastCases)
+}
+
+func BenchmarkVectorizedBuiltinCastFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinCastCases)
+}
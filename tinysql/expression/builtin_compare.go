@@ -16,406 +16,1804 @@ package expression
 import (
 	"math"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
 var (
+	_ functionClass = &coalesceFunctionClass{}
+	_ functionClass = &greatestFunctionClass{}
+	_ functionClass = &leastFunctionClass{}
 	_ functionClass = &compareFunctionClass{}
 )
 
 var (
+	_ builtinFunc = &builtinCoalesceIntSig{}
+	_ builtinFunc = &builtinCoalesceRealSig{}
+	_ builtinFunc = &builtinCoalesceDecimalSig{}
+	_ builtinFunc = &builtinCoalesceStringSig{}
+	_ builtinFunc = &builtinCoalesceTimeSig{}
+	_ builtinFunc = &builtinCoalesceDurationSig{}
+	_ builtinFunc = &builtinCoalesceJSONSig{}
+
+	_ builtinFunc = &builtinGreatestIntSig{}
+	_ builtinFunc = &builtinGreatestRealSig{}
+	_ builtinFunc = &builtinGreatestDecimalSig{}
+	_ builtinFunc = &builtinGreatestStringSig{}
+	_ builtinFunc = &builtinGreatestTimeSig{}
+
+	_ builtinFunc = &builtinLeastIntSig{}
+	_ builtinFunc = &builtinLeastRealSig{}
+	_ builtinFunc = &builtinLeastDecimalSig{}
+	_ builtinFunc = &builtinLeastStringSig{}
+	_ builtinFunc = &builtinLeastTimeSig{}
+
 	_ builtinFunc = &builtinLTIntSig{}
 	_ builtinFunc = &builtinLTRealSig{}
+	_ builtinFunc = &builtinLTDecimalSig{}
 	_ builtinFunc = &builtinLTStringSig{}
+	_ builtinFunc = &builtinLTTimeSig{}
+	_ builtinFunc = &builtinLTDurationSig{}
+	_ builtinFunc = &builtinLTJSONSig{}
 
 	_ builtinFunc = &builtinLEIntSig{}
 	_ builtinFunc = &builtinLERealSig{}
+	_ builtinFunc = &builtinLEDecimalSig{}
 	_ builtinFunc = &builtinLEStringSig{}
+	_ builtinFunc = &builtinLETimeSig{}
+	_ builtinFunc = &builtinLEDurationSig{}
+	_ builtinFunc = &builtinLEJSONSig{}
 
 	_ builtinFunc = &builtinGTIntSig{}
 	_ builtinFunc = &builtinGTRealSig{}
+	_ builtinFunc = &builtinGTDecimalSig{}
 	_ builtinFunc = &builtinGTStringSig{}
+	_ builtinFunc = &builtinGTTimeSig{}
+	_ builtinFunc = &builtinGTDurationSig{}
+	_ builtinFunc = &builtinGTJSONSig{}
 
 	_ builtinFunc = &builtinGEIntSig{}
 	_ builtinFunc = &builtinGERealSig{}
+	_ builtinFunc = &builtinGEDecimalSig{}
 	_ builtinFunc = &builtinGEStringSig{}
+	_ builtinFunc = &builtinGETimeSig{}
+	_ builtinFunc = &builtinGEDurationSig{}
+	_ builtinFunc = &builtinGEJSONSig{}
 
 	_ builtinFunc = &builtinNEIntSig{}
 	_ builtinFunc = &builtinNERealSig{}
+	_ builtinFunc = &builtinNEDecimalSig{}
 	_ builtinFunc = &builtinNEStringSig{}
+	_ builtinFunc = &builtinNETimeSig{}
+	_ builtinFunc = &builtinNEDurationSig{}
+	_ builtinFunc = &builtinNEJSONSig{}
 )
 
-type compareFunctionClass struct {
+// coalesceFunctionClass returns the first non-NULL value in the list,
+// or NULL if there are no non-NULL values.
+type coalesceFunctionClass struct {
 	baseFunctionClass
+}
 
-	op opcode.Op
+func (c *coalesceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	fieldTps := make([]*types.FieldType, 0, len(args))
+	for _, arg := range args {
+		fieldTps = append(fieldTps, arg.GetType())
+	}
+
+	// Use the aggregated field type as retType.
+	resultFieldType := types.AggFieldType(fieldTps)
+	resultEvalType := types.AggregateEvalType(fieldTps, &resultFieldType.Flag)
+	retEvalTp := resultFieldType.EvalType()
+
+	fieldEvalTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		fieldEvalTps = append(fieldEvalTps, retEvalTp)
+	}
+
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retEvalTp, fieldEvalTps...)
+
+	bf.tp.Flag |= resultFieldType.Flag
+	resultFieldType.Flen, resultFieldType.Decimal = 0, types.UnspecifiedLength
+
+	// Set retType to BINARY(0) if all arguments are of type NULL.
+	if resultFieldType.Tp == mysql.TypeNull {
+		types.SetBinChsClnFlag(bf.tp)
+	} else {
+		maxIntLen := 0
+		maxFlen := 0
+
+		// Find the max length of field in `maxFlen`,
+		// and max integer-part length in `maxIntLen`.
+		for _, argTp := range fieldTps {
+			if argTp.Decimal > resultFieldType.Decimal {
+				resultFieldType.Decimal = argTp.Decimal
+			}
+			argIntLen := argTp.Flen
+			if argTp.Decimal > 0 {
+				argIntLen -= argTp.Decimal + 1
+			}
+
+			// Reduce the sign bit if it is a signed integer/decimal
+			if !mysql.HasUnsignedFlag(argTp.Flag) {
+				argIntLen--
+			}
+			if argIntLen > maxIntLen {
+				maxIntLen = argIntLen
+			}
+			if argTp.Flen > maxFlen || argTp.Flen == types.UnspecifiedLength {
+				maxFlen = argTp.Flen
+			}
+		}
+		// For integer, field length = maxIntLen + (1/0 for sign bit)
+		// For decimal, field length = maxIntLen + maxDecimal + (1/0 for sign bit)
+		if resultEvalType == types.ETInt || resultEvalType == types.ETDecimal {
+			resultFieldType.Flen = maxIntLen + resultFieldType.Decimal
+			if resultFieldType.Decimal > 0 {
+				resultFieldType.Flen++
+			}
+			if !mysql.HasUnsignedFlag(resultFieldType.Flag) {
+				resultFieldType.Flen++
+			}
+			bf.tp = resultFieldType
+		} else {
+			bf.tp.Flen = maxFlen
+			bf.tp.Decimal = resultFieldType.Decimal
+		}
+		// Set the field length to maxFlen for other types.
+		if bf.tp.Flen > mysql.MaxDecimalWidth {
+			bf.tp.Flen = mysql.MaxDecimalWidth
+		}
+		if retEvalTp == types.ETString && types.IsBinaryStr(resultFieldType) {
+			types.SetBinChsClnFlag(bf.tp)
+		}
+	}
+
+	switch retEvalTp {
+	case types.ETInt:
+		sig = &builtinCoalesceIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceInt)
+	case types.ETReal:
+		sig = &builtinCoalesceRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceReal)
+	case types.ETDecimal:
+		sig = &builtinCoalesceDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceDecimal)
+	case types.ETString:
+		sig = &builtinCoalesceStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCoalesceTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceTime)
+	case types.ETDuration:
+		sig = &builtinCoalesceDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceDuration)
+	case types.ETJson:
+		sig = &builtinCoalesceJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CoalesceJson)
+	}
+
+	return sig, nil
 }
 
-// getBaseCmpType gets the EvalType that the two args will be treated as when comparing.
-func getBaseCmpType(lhs, rhs types.EvalType, lft, rft *types.FieldType) types.EvalType {
-	if lft.Tp == mysql.TypeUnspecified || rft.Tp == mysql.TypeUnspecified {
-		if lft.Tp == rft.Tp {
-			return types.ETString
+// builtinCoalesceIntSig is builtin function coalesce signature which return type int.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceIntSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalInt(b.ctx, row)
+		if err != nil || !isNull {
+			break
 		}
-		if lft.Tp == mysql.TypeUnspecified {
-			lhs = rhs
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceRealSig is builtin function coalesce signature which return type real.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceRealSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalReal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceDecimalSig is builtin function coalesce signature which return type decimal.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDecimal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceStringSig is builtin function coalesce signature which return type string.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceStringSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalString(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceTimeSig is builtin function coalesce signature which return type time.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceTimeSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalTime(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceDurationSig is builtin function coalesce signature which return type duration.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDurationSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDurationSig) evalDuration(row chunk.Row) (res types.Duration, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDuration(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// builtinCoalesceJSONSig is builtin function coalesce signature which return type json.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_coalesce
+type builtinCoalesceJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceJSONSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalJSON(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+// getCmpTp4MinMax gets compare type for GREATEST and LEAST.
+func getCmpTp4MinMax(args []Expression) (argTp types.EvalType) {
+	datetimeFound, allUnsigned, allSigned := false, true, true
+	cmpEvalType := args[0].GetType().EvalType()
+	for i, arg := range args {
+		ft := arg.GetType()
+		if i > 0 {
+			cmpEvalType = getBaseCmpType(cmpEvalType, ft.EvalType(), args[i-1].GetType(), ft)
+		}
+		if types.IsTypeTime(ft.Tp) {
+			datetimeFound = true
+		}
+		if mysql.HasUnsignedFlag(ft.Flag) {
+			allSigned = false
 		} else {
-			rhs = lhs
+			allUnsigned = false
 		}
 	}
-	if lhs.IsStringKind() && rhs.IsStringKind() {
-		return types.ETString
-	} else if (lhs == types.ETInt || lft.Hybrid()) && (rhs == types.ETInt || rft.Hybrid()) {
-		return types.ETInt
+	switch {
+	case cmpEvalType == types.ETString && datetimeFound:
+		// Strings are compared with the temporal values as datetime.
+		return types.ETDatetime
+	case cmpEvalType == types.ETInt && !allSigned && !allUnsigned:
+		// A signed integer and an unsigned integer are compared as decimal.
+		return types.ETDecimal
 	}
-	return types.ETReal
+	return cmpEvalType
 }
 
-// GetAccurateCmpType uses a more complex logic to decide the EvalType of the two args when compare with each other than
-// getBaseCmpType does.
-func GetAccurateCmpType(lhs, rhs Expression) types.EvalType {
-	lhsFieldType, rhsFieldType := lhs.GetType(), rhs.GetType()
-	lhsEvalType, rhsEvalType := lhsFieldType.EvalType(), rhsFieldType.EvalType()
-	cmpType := getBaseCmpType(lhsEvalType, rhsEvalType, lhsFieldType, rhsFieldType)
-	return cmpType
+// newBaseBuiltinFunc4MinMax creates the baseBuiltinFunc for GREATEST and
+// LEAST, all the arguments are compared as the type of the return value.
+func newBaseBuiltinFunc4MinMax(ctx sessionctx.Context, args []Expression) baseBuiltinFunc {
+	tp := getCmpTp4MinMax(args)
+	argTps := make([]types.EvalType, len(args))
+	for i := range args {
+		argTps[i] = tp
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, tp, argTps...)
+	switch tp {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(args[0].GetType().Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+	case types.ETString:
+		for _, arg := range args {
+			if types.IsBinaryStr(arg.GetType()) {
+				types.SetBinChsClnFlag(bf.tp)
+				break
+			}
+		}
+	case types.ETDatetime:
+		bf.tp.Decimal = 0
+		for _, arg := range args {
+			if arg.GetType().Decimal > bf.tp.Decimal && types.IsTypeTime(arg.GetType().Tp) {
+				bf.tp.Decimal = arg.GetType().Decimal
+			}
+		}
+	}
+	for _, arg := range args {
+		bf.tp.Flen = mathutil.Max(bf.tp.Flen, arg.GetType().Flen)
+		if tp != types.ETDatetime && arg.GetType().Decimal > bf.tp.Decimal {
+			bf.tp.Decimal = arg.GetType().Decimal
+		}
+	}
+	if tp == types.ETInt {
+		bf.tp.Decimal = 0
+	}
+	return bf
 }
 
-// GetCmpFunction get the compare function according to two arguments.
-func GetCmpFunction(lhs, rhs Expression) CompareFunc {
-	switch GetAccurateCmpType(lhs, rhs) {
+type greatestFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *greatestFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc4MinMax(ctx, args)
+	switch bf.tp.EvalType() {
 	case types.ETInt:
-		return CompareInt
+		sig = &builtinGreatestIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestInt)
 	case types.ETReal:
-		return CompareReal
+		sig = &builtinGreatestRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestReal)
+	case types.ETDecimal:
+		sig = &builtinGreatestDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestDecimal)
 	case types.ETString:
-		return CompareString
+		sig = &builtinGreatestStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinGreatestTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestTime)
 	}
-	return nil
+	return sig, nil
 }
 
-// getFunction sets compare built-in function signatures for various types.
-func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, rawArgs []Expression) (sig builtinFunc, err error) {
-	if err = c.verifyArgs(rawArgs); err != nil {
-		return nil, err
+type builtinGreatestIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestIntSig) Clone() builtinFunc {
+	newSig := &builtinGreatestIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinGreatestIntSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
 	}
-	cmpType := GetAccurateCmpType(rawArgs[0], rawArgs[1])
-	sig, err = c.generateCmpSigs(ctx, rawArgs, cmpType)
-	return sig, err
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if isUnsigned && uint64(v) > uint64(res) || !isUnsigned && v > res {
+			res = v
+		}
+	}
+	return res, false, nil
 }
 
-// generateCmpSigs generates compare function signatures.
-func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Expression, tp types.EvalType) (sig builtinFunc, err error) {
-	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, tp, tp)
-	bf.tp.Flen = 1
-	switch tp {
-	case types.ETInt:
-		switch c.op {
-		case opcode.LT:
-			sig = &builtinLTIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LTInt)
-		case opcode.LE:
-			sig = &builtinLEIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LEInt)
-		case opcode.GT:
-			sig = &builtinGTIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GTInt)
-		case opcode.EQ:
-			sig = &builtinEQIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_EQInt)
-		case opcode.GE:
-			sig = &builtinGEIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GEInt)
-		case opcode.NE:
-			sig = &builtinNEIntSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_NEInt)
+type builtinGreatestRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestRealSig) Clone() builtinFunc {
+	newSig := &builtinGreatestRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinGreatestRealSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
 		}
-	case types.ETReal:
-		switch c.op {
-		case opcode.LT:
-			sig = &builtinLTRealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LTReal)
-		case opcode.LE:
-			sig = &builtinLERealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LEReal)
-		case opcode.GT:
-			sig = &builtinGTRealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GTReal)
-		case opcode.GE:
-			sig = &builtinGERealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GEReal)
-		case opcode.EQ:
-			sig = &builtinEQRealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_EQReal)
-		case opcode.NE:
-			sig = &builtinNERealSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_NEReal)
+		if v > res {
+			res = v
 		}
-	case types.ETString:
-		switch c.op {
-		case opcode.LT:
-			sig = &builtinLTStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LTString)
-		case opcode.LE:
-			sig = &builtinLEStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_LEString)
-		case opcode.GT:
-			sig = &builtinGTStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GTString)
-		case opcode.GE:
-			sig = &builtinGEStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_GEString)
-		case opcode.EQ:
-			sig = &builtinEQStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_EQString)
-		case opcode.NE:
-			sig = &builtinNEStringSig{bf}
-			sig.setPbCode(tipb.ScalarFuncSig_NEString)
+	}
+	return res, false, nil
+}
+
+type builtinGreatestDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGreatestDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinGreatestDecimalSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if v.Compare(res) > 0 {
+			res = v
 		}
 	}
-	return
+	return res, false, nil
+}
+
+type builtinGreatestStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestStringSig) Clone() builtinFunc {
+	newSig := &builtinGreatestStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinGreatestStringSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if types.CompareString(v, res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinGreatestTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestTimeSig) Clone() builtinFunc {
+	newSig := &builtinGreatestTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinGreatestTimeSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+func (b *builtinGreatestTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if v.Compare(res) > 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type leastFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leastFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFunc4MinMax(ctx, args)
+	switch bf.tp.EvalType() {
+	case types.ETInt:
+		sig = &builtinLeastIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastInt)
+	case types.ETReal:
+		sig = &builtinLeastRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastReal)
+	case types.ETDecimal:
+		sig = &builtinLeastDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastDecimal)
+	case types.ETString:
+		sig = &builtinLeastStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinLeastTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastTime)
+	}
+	return sig, nil
+}
+
+type builtinLeastIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastIntSig) Clone() builtinFunc {
+	newSig := &builtinLeastIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinLeastIntSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if isUnsigned && uint64(v) < uint64(res) || !isUnsigned && v < res {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastRealSig) Clone() builtinFunc {
+	newSig := &builtinLeastRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLeastRealSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if v < res {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLeastDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinLeastDecimalSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if v.Compare(res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastStringSig) Clone() builtinFunc {
+	newSig := &builtinLeastStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLeastStringSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if types.CompareString(v, res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type builtinLeastTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastTimeSig) Clone() builtinFunc {
+	newSig := &builtinLeastTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinLeastTimeSig.
+// See http://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+func (b *builtinLeastTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	for i := 1; i < len(b.args); i++ {
+		v, isNull, err := b.args[i].EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if v.Compare(res) < 0 {
+			res = v
+		}
+	}
+	return res, false, nil
+}
+
+type compareFunctionClass struct {
+	baseFunctionClass
+
+	op opcode.Op
+}
+
+// getBaseCmpType gets the EvalType that the two args will be treated as when comparing.
+func getBaseCmpType(lhs, rhs types.EvalType, lft, rft *types.FieldType) types.EvalType {
+	if lft.Tp == mysql.TypeUnspecified || rft.Tp == mysql.TypeUnspecified {
+		if lft.Tp == rft.Tp {
+			return types.ETString
+		}
+		if lft.Tp == mysql.TypeUnspecified {
+			lhs = rhs
+		} else {
+			rhs = lhs
+		}
+	}
+	if lhs.IsStringKind() && rhs.IsStringKind() {
+		return types.ETString
+	} else if (lhs == types.ETInt || lft.Hybrid()) && (rhs == types.ETInt || rft.Hybrid()) {
+		return types.ETInt
+	} else if ((lhs == types.ETInt || lft.Hybrid()) || lhs == types.ETDecimal) &&
+		((rhs == types.ETInt || rft.Hybrid()) || rhs == types.ETDecimal) {
+		return types.ETDecimal
+	}
+	return types.ETReal
+}
+
+// GetAccurateCmpType uses a more complex logic to decide the EvalType of the two args when compare with each other than
+// getBaseCmpType does.
+func GetAccurateCmpType(lhs, rhs Expression) types.EvalType {
+	lhsFieldType, rhsFieldType := lhs.GetType(), rhs.GetType()
+	lhsEvalType, rhsEvalType := lhsFieldType.EvalType(), rhsFieldType.EvalType()
+	cmpType := getBaseCmpType(lhsEvalType, rhsEvalType, lhsFieldType, rhsFieldType)
+	if lhsEvalType == types.ETJson || rhsEvalType == types.ETJson {
+		// json <cmp> any
+		// compare as json, the other side is converted to a json scalar
+		cmpType = types.ETJson
+	} else if cmpType == types.ETString && (types.IsTypeTime(lhsFieldType.Tp) || types.IsTypeTime(rhsFieldType.Tp)) {
+		// date[time] <cmp> date[time]
+		// string <cmp> date[time]
+		// compare as time
+		if lhsFieldType.Tp == rhsFieldType.Tp {
+			cmpType = lhsFieldType.EvalType()
+		} else {
+			cmpType = types.ETDatetime
+		}
+	} else if lhsFieldType.Tp == mysql.TypeDuration && rhsFieldType.Tp == mysql.TypeDuration {
+		// duration <cmp> duration
+		// compare as duration
+		cmpType = types.ETDuration
+	} else if cmpType == types.ETReal || cmpType == types.ETString {
+		_, isLHSConst := lhs.(*Constant)
+		_, isRHSConst := rhs.(*Constant)
+		if isTemporalColumn(lhs) && isRHSConst || isTemporalColumn(rhs) && isLHSConst {
+			// <temporal column> <cmp> <non-temporal constant>
+			// or
+			// <non-temporal constant> <cmp> <temporal column>
+			//
+			// Convert the constant to temporal type.
+			col, isLHSColumn := lhs.(*Column)
+			if !isLHSColumn {
+				col = rhs.(*Column)
+			}
+			if col.GetType().Tp == mysql.TypeDuration {
+				cmpType = types.ETDuration
+			} else {
+				cmpType = types.ETDatetime
+			}
+		}
+	}
+	return cmpType
+}
+
+// isTemporalColumn checks if a expression is a temporal column,
+// temporal column indicates time column or duration column.
+func isTemporalColumn(expr Expression) bool {
+	ft := expr.GetType()
+	if _, isCol := expr.(*Column); !isCol {
+		return false
+	}
+	if !types.IsTypeTime(ft.Tp) && ft.Tp != mysql.TypeDuration {
+		return false
+	}
+	return true
+}
+
+// GetCmpFunction get the compare function according to two arguments.
+func GetCmpFunction(lhs, rhs Expression) CompareFunc {
+	switch GetAccurateCmpType(lhs, rhs) {
+	case types.ETInt:
+		return CompareInt
+	case types.ETReal:
+		return CompareReal
+	case types.ETDecimal:
+		return CompareDecimal
+	case types.ETString:
+		return CompareString
+	case types.ETDatetime, types.ETTimestamp:
+		return CompareDatetime
+	case types.ETDuration:
+		return CompareDuration
+	case types.ETJson:
+		return CompareJSON
+	}
+	return nil
+}
+
+// getFunction sets compare built-in function signatures for various types.
+func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, rawArgs []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(rawArgs); err != nil {
+		return nil, err
+	}
+	args := c.refineArgs(ctx, rawArgs)
+	cmpType := GetAccurateCmpType(args[0], args[1])
+	sig, err = c.generateCmpSigs(ctx, args, cmpType)
+	return sig, err
+}
+
+// symmetricOp maps a compare operator to the one used when the two arguments are swapped.
+var symmetricOp = map[opcode.Op]opcode.Op{
+	opcode.LT:     opcode.GT,
+	opcode.GE:     opcode.LE,
+	opcode.GT:     opcode.LT,
+	opcode.LE:     opcode.GE,
+	opcode.EQ:     opcode.EQ,
+	opcode.NE:     opcode.NE,
+	opcode.NullEQ: opcode.NullEQ,
+}
+
+// RefineComparedConstant changes a non-integer constant argument to its ceiling or floor result by the given op.
+// isExceptional indicates whether the 'int column [cmp] const' is always true or always false. In that case the
+// returned constant is either the clipped bound of the int type (for LT/LE/GT/GE overflow) or the input constant
+// (for EQ/NullEQ that can never hold).
+func RefineComparedConstant(ctx sessionctx.Context, targetFieldType types.FieldType, con *Constant, op opcode.Op) (_ *Constant, isExceptional bool) {
+	dt, err := con.Eval(chunk.Row{})
+	if err != nil {
+		return con, false
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	if targetFieldType.Tp == mysql.TypeBit {
+		targetFieldType = *types.NewFieldType(mysql.TypeLonglong)
+	}
+	intDatum, err := dt.ConvertTo(sc, &targetFieldType)
+	if err != nil {
+		if terror.ErrorEqual(err, types.ErrOverflow) {
+			return &Constant{Value: intDatum, RetType: &targetFieldType}, true
+		}
+		return con, false
+	}
+	c, err := intDatum.CompareDatum(sc, &con.Value)
+	if err != nil {
+		return con, false
+	}
+	if c == 0 {
+		return &Constant{Value: intDatum, RetType: &targetFieldType}, false
+	}
+	switch op {
+	case opcode.LT, opcode.GE:
+		return tryToConvertConstantInt(ctx, &targetFieldType, dt, true)
+	case opcode.LE, opcode.GT:
+		return tryToConvertConstantInt(ctx, &targetFieldType, dt, false)
+	case opcode.NullEQ, opcode.EQ:
+		switch con.GetType().EvalType() {
+		// An integer value equal or NULL-safe equal to a float value which contains
+		// non-zero decimal digits is definitely false.
+		case types.ETReal, types.ETDecimal:
+			return con, true
+		case types.ETString:
+			// Try to convert the string constant to double. If the double result equals
+			// the int result, return the int result; otherwise the comparison is always false.
+			doubleDatum, err := dt.ConvertTo(sc, types.NewFieldType(mysql.TypeDouble))
+			if err != nil {
+				return con, false
+			}
+			if c, err = doubleDatum.CompareDatum(sc, &intDatum); err != nil {
+				return con, false
+			}
+			if c != 0 {
+				return con, true
+			}
+			return &Constant{Value: intDatum, RetType: &targetFieldType}, false
+		}
+	}
+	return con, false
+}
+
+// tryToConvertConstantInt converts the ceiling (or floor) of a non-integer datum to an int constant.
+// isExceptional is true when the result overflows the target int type.
+func tryToConvertConstantInt(ctx sessionctx.Context, targetFieldType *types.FieldType, dt types.Datum, ceil bool) (_ *Constant, isExceptional bool) {
+	sc := ctx.GetSessionVars().StmtCtx
+	var rounded types.Datum
+	if dt.Kind() == types.KindMysqlDecimal {
+		dec, to := dt.GetMysqlDecimal(), new(types.MyDecimal)
+		if err := dec.Round(to, 0, types.ModeTruncate); err != nil {
+			return &Constant{Value: dt, RetType: types.NewFieldType(mysql.TypeNewDecimal)}, false
+		}
+		if to.Compare(dec) != 0 && ceil != dec.IsNegative() {
+			one := types.NewDecFromInt(1)
+			res := new(types.MyDecimal)
+			var err error
+			if ceil {
+				err = types.DecimalAdd(to, one, res)
+			} else {
+				err = types.DecimalSub(to, one, res)
+			}
+			if err == nil {
+				to = res
+			}
+		}
+		rounded = types.NewDecimalDatum(to)
+	} else {
+		f, err := dt.ToFloat64(sc)
+		if err != nil {
+			return &Constant{Value: dt, RetType: types.NewFieldType(mysql.TypeDouble)}, false
+		}
+		if ceil {
+			f = math.Ceil(f)
+		} else {
+			f = math.Floor(f)
+		}
+		rounded = types.NewFloat64Datum(f)
+	}
+	intDatum, err := rounded.ConvertTo(sc, targetFieldType)
+	if err != nil {
+		if terror.ErrorEqual(err, types.ErrOverflow) {
+			return &Constant{Value: intDatum, RetType: targetFieldType}, true
+		}
+		return &Constant{Value: dt, RetType: types.NewFieldType(mysql.TypeDouble)}, false
+	}
+	return &Constant{Value: intDatum, RetType: targetFieldType}, false
+}
+
+// refineArgs rewrites the arguments if the compare expression is `int column <cmp> non-int constant` or
+// `non-int constant <cmp> int column`. E.g., `a < 1.1` will be rewritten to `a < 2`.
+func (c *compareFunctionClass) refineArgs(ctx sessionctx.Context, args []Expression) []Expression {
+	arg0Type, arg1Type := args[0].GetType(), args[1].GetType()
+	arg0IsInt := arg0Type.EvalType() == types.ETInt
+	arg1IsInt := arg1Type.EvalType() == types.ETInt
+	arg0, arg0IsCon := args[0].(*Constant)
+	arg1, arg1IsCon := args[1].(*Constant)
+	isExceptional, finalArg0, finalArg1 := false, args[0], args[1]
+	isPositiveInfinite, isNegativeInfinite := false, false
+	if MaybeOverOptimized4PlanCache(ctx, args) {
+		// The refined constant depends on the value of the parameter, the
+		// arguments are kept as they are so that the plan can be reused.
+		return args
+	}
+	// int non-constant [cmp] non-int constant
+	if arg0IsInt && !arg0IsCon && !arg1IsInt && arg1IsCon {
+		arg1, isExceptional = RefineComparedConstant(ctx, *arg0Type, arg1, c.op)
+		finalArg1 = arg1
+		if isExceptional && arg1.GetType().EvalType() == types.ETInt {
+			// Judge it is inf or -inf
+			// For int:
+			//			inf:  01111111 & 1 == 1
+			//		   -inf:  10000000 & 1 == 0
+			// For uint:
+			//			inf:  11111111 & 1 == 1
+			//		   -inf:  00000000 & 1 == 0
+			if arg1.Value.GetInt64()&1 == 1 {
+				isPositiveInfinite = true
+			} else {
+				isNegativeInfinite = true
+			}
+		}
+	}
+	// non-int constant [cmp] int non-constant
+	if arg1IsInt && !arg1IsCon && !arg0IsInt && arg0IsCon {
+		arg0, isExceptional = RefineComparedConstant(ctx, *arg1Type, arg0, symmetricOp[c.op])
+		finalArg0 = arg0
+		if isExceptional && arg0.GetType().EvalType() == types.ETInt {
+			if arg0.Value.GetInt64()&1 == 1 {
+				isNegativeInfinite = true
+			} else {
+				isPositiveInfinite = true
+			}
+		}
+	}
+
+	if isExceptional && (c.op == opcode.EQ || c.op == opcode.NullEQ) {
+		// This will always be false.
+		return []Expression{Zero.Clone(), One.Clone()}
+	}
+	if isPositiveInfinite {
+		// If the op is opcode.LT, opcode.LE, this will always be true.
+		// If the op is opcode.GT, opcode.GE, this will always be false.
+		return []Expression{Zero.Clone(), One.Clone()}
+	}
+	if isNegativeInfinite {
+		// If the op is opcode.GT, opcode.GE, this will always be true.
+		// If the op is opcode.LT, opcode.LE, this will always be false.
+		return []Expression{One.Clone(), Zero.Clone()}
+	}
+	return []Expression{finalArg0, finalArg1}
+}
+
+// generateCmpSigs generates compare function signatures.
+func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Expression, tp types.EvalType) (sig builtinFunc, err error) {
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, tp, tp)
+	bf.tp.Flen = 1
+	switch tp {
+	case types.ETInt:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTInt)
+		case opcode.LE:
+			sig = &builtinLEIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEInt)
+		case opcode.GT:
+			sig = &builtinGTIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTInt)
+		case opcode.EQ:
+			sig = &builtinEQIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQInt)
+		case opcode.GE:
+			sig = &builtinGEIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEInt)
+		case opcode.NE:
+			sig = &builtinNEIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEInt)
+		case opcode.NullEQ:
+			sig = &builtinNullEQIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQInt)
+		}
+	case types.ETReal:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTReal)
+		case opcode.LE:
+			sig = &builtinLERealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEReal)
+		case opcode.GT:
+			sig = &builtinGTRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTReal)
+		case opcode.GE:
+			sig = &builtinGERealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEReal)
+		case opcode.EQ:
+			sig = &builtinEQRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQReal)
+		case opcode.NE:
+			sig = &builtinNERealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEReal)
+		case opcode.NullEQ:
+			sig = &builtinNullEQRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQReal)
+		}
+	case types.ETDecimal:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTDecimal)
+		case opcode.LE:
+			sig = &builtinLEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEDecimal)
+		case opcode.GT:
+			sig = &builtinGTDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTDecimal)
+		case opcode.GE:
+			sig = &builtinGEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEDecimal)
+		case opcode.EQ:
+			sig = &builtinEQDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQDecimal)
+		case opcode.NE:
+			sig = &builtinNEDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEDecimal)
+		case opcode.NullEQ:
+			sig = &builtinNullEQDecimalSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQDecimal)
+		}
+	case types.ETString:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTString)
+		case opcode.LE:
+			sig = &builtinLEStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEString)
+		case opcode.GT:
+			sig = &builtinGTStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTString)
+		case opcode.GE:
+			sig = &builtinGEStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEString)
+		case opcode.EQ:
+			sig = &builtinEQStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQString)
+		case opcode.NE:
+			sig = &builtinNEStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEString)
+		case opcode.NullEQ:
+			sig = &builtinNullEQStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQString)
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTTime)
+		case opcode.LE:
+			sig = &builtinLETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LETime)
+		case opcode.GT:
+			sig = &builtinGTTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTTime)
+		case opcode.GE:
+			sig = &builtinGETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GETime)
+		case opcode.EQ:
+			sig = &builtinEQTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQTime)
+		case opcode.NE:
+			sig = &builtinNETimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NETime)
+		case opcode.NullEQ:
+			sig = &builtinNullEQTimeSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQTime)
+		}
+	case types.ETDuration:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTDuration)
+		case opcode.LE:
+			sig = &builtinLEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEDuration)
+		case opcode.GT:
+			sig = &builtinGTDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTDuration)
+		case opcode.GE:
+			sig = &builtinGEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEDuration)
+		case opcode.EQ:
+			sig = &builtinEQDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQDuration)
+		case opcode.NE:
+			sig = &builtinNEDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEDuration)
+		case opcode.NullEQ:
+			sig = &builtinNullEQDurationSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQDuration)
+		}
+	case types.ETJson:
+		switch c.op {
+		case opcode.LT:
+			sig = &builtinLTJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LTJson)
+		case opcode.LE:
+			sig = &builtinLEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_LEJson)
+		case opcode.GT:
+			sig = &builtinGTJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GTJson)
+		case opcode.GE:
+			sig = &builtinGEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_GEJson)
+		case opcode.EQ:
+			sig = &builtinEQJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_EQJson)
+		case opcode.NE:
+			sig = &builtinNEJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NEJson)
+		case opcode.NullEQ:
+			sig = &builtinNullEQJSONSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQJson)
+		}
+	}
+	return
+}
+
+type builtinLTIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTIntSig) Clone() builtinFunc {
+	newSig := &builtinLTIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTRealSig) Clone() builtinFunc {
+	newSig := &builtinLTRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLTDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTTimeSig) Clone() builtinFunc {
+	newSig := &builtinLTTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTDurationSig) Clone() builtinFunc {
+	newSig := &builtinLTDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTStringSig) Clone() builtinFunc {
+	newSig := &builtinLTStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLTJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTJSONSig) Clone() builtinFunc {
+	newSig := &builtinLTJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLTJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLT(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEIntSig) Clone() builtinFunc {
+	newSig := &builtinLEIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLERealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLERealSig) Clone() builtinFunc {
+	newSig := &builtinLERealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinLEDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLETimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLETimeSig) Clone() builtinFunc {
+	newSig := &builtinLETimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEDurationSig) Clone() builtinFunc {
+	newSig := &builtinLEDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEStringSig) Clone() builtinFunc {
+	newSig := &builtinLEStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinLEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLEJSONSig) Clone() builtinFunc {
+	newSig := &builtinLEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfLE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTIntSig) Clone() builtinFunc {
+	newSig := &builtinGTIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTRealSig) Clone() builtinFunc {
+	newSig := &builtinGTRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGTDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTTimeSig) Clone() builtinFunc {
+	newSig := &builtinGTTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTDurationSig) Clone() builtinFunc {
+	newSig := &builtinGTDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTStringSig) Clone() builtinFunc {
+	newSig := &builtinGTStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGTJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGTJSONSig) Clone() builtinFunc {
+	newSig := &builtinGTJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGTJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGT(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGEIntSig) Clone() builtinFunc {
+	newSig := &builtinGEIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGERealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGERealSig) Clone() builtinFunc {
+	newSig := &builtinGERealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
 }
 
-type builtinLTIntSig struct {
+func (b *builtinGERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinGEDecimalSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTIntSig) Clone() builtinFunc {
-	newSig := &builtinLTIntSig{}
+func (b *builtinGEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinGEDecimalSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinGEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLTRealSig struct {
+type builtinGETimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTRealSig) Clone() builtinFunc {
-	newSig := &builtinLTRealSig{}
+func (b *builtinGETimeSig) Clone() builtinFunc {
+	newSig := &builtinGETimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinGETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLTStringSig struct {
+type builtinGEDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLTStringSig) Clone() builtinFunc {
-	newSig := &builtinLTStringSig{}
+func (b *builtinGEDurationSig) Clone() builtinFunc {
+	newSig := &builtinGEDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinGEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLEIntSig struct {
+type builtinGEStringSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLEIntSig) Clone() builtinFunc {
-	newSig := &builtinLEIntSig{}
+func (b *builtinGEStringSig) Clone() builtinFunc {
+	newSig := &builtinGEStringSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinGEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLERealSig struct {
+type builtinGEJSONSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLERealSig) Clone() builtinFunc {
-	newSig := &builtinLERealSig{}
+func (b *builtinGEJSONSig) Clone() builtinFunc {
+	newSig := &builtinGEJSONSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinGEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfGE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinLEStringSig struct {
+type builtinEQIntSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinLEStringSig) Clone() builtinFunc {
-	newSig := &builtinLEStringSig{}
+func (b *builtinEQIntSig) Clone() builtinFunc {
+	newSig := &builtinEQIntSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinLEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGTIntSig struct {
+type builtinEQRealSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGTIntSig) Clone() builtinFunc {
-	newSig := &builtinGTIntSig{}
+func (b *builtinEQRealSig) Clone() builtinFunc {
+	newSig := &builtinEQRealSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGTIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGTRealSig struct {
+type builtinEQDecimalSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGTRealSig) Clone() builtinFunc {
-	newSig := &builtinGTRealSig{}
+func (b *builtinEQDecimalSig) Clone() builtinFunc {
+	newSig := &builtinEQDecimalSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGTRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGTStringSig struct {
+type builtinEQTimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGTStringSig) Clone() builtinFunc {
-	newSig := &builtinGTStringSig{}
+func (b *builtinEQTimeSig) Clone() builtinFunc {
+	newSig := &builtinEQTimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGEIntSig struct {
+type builtinEQDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGEIntSig) Clone() builtinFunc {
-	newSig := &builtinGEIntSig{}
+func (b *builtinEQDurationSig) Clone() builtinFunc {
+	newSig := &builtinEQDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGERealSig struct {
+type builtinEQStringSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGERealSig) Clone() builtinFunc {
-	newSig := &builtinGERealSig{}
+func (b *builtinEQStringSig) Clone() builtinFunc {
+	newSig := &builtinEQStringSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinGEStringSig struct {
+type builtinEQJSONSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGEStringSig) Clone() builtinFunc {
-	newSig := &builtinGEStringSig{}
+func (b *builtinEQJSONSig) Clone() builtinFunc {
+	newSig := &builtinEQJSONSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinEQJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfEQ(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinEQIntSig struct {
+type builtinNEIntSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinEQIntSig) Clone() builtinFunc {
-	newSig := &builtinEQIntSig{}
+func (b *builtinNEIntSig) Clone() builtinFunc {
+	newSig := &builtinNEIntSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinEQRealSig struct {
+type builtinNERealSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinEQRealSig) Clone() builtinFunc {
-	newSig := &builtinEQRealSig{}
+func (b *builtinNERealSig) Clone() builtinFunc {
+	newSig := &builtinNERealSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinEQStringSig struct {
+type builtinNEDecimalSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinEQStringSig) Clone() builtinFunc {
-	newSig := &builtinEQStringSig{}
+func (b *builtinNEDecimalSig) Clone() builtinFunc {
+	newSig := &builtinNEDecimalSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinNEIntSig struct {
+type builtinNETimeSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinNEIntSig) Clone() builtinFunc {
-	newSig := &builtinNEIntSig{}
+func (b *builtinNETimeSig) Clone() builtinFunc {
+	newSig := &builtinNETimeSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinNEIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfNE(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNETimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
 }
 
-type builtinNERealSig struct {
+type builtinNEDurationSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinNERealSig) Clone() builtinFunc {
-	newSig := &builtinNERealSig{}
+func (b *builtinNEDurationSig) Clone() builtinFunc {
+	newSig := &builtinNEDurationSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinNERealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfNE(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+func (b *builtinNEDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
 }
 
 type builtinNEStringSig struct {
@@ -432,6 +1830,20 @@ func (b *builtinNEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err
 	return resOfNE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
 }
 
+type builtinNEJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNEJSONSig) Clone() builtinFunc {
+	newSig := &builtinNEJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNEJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNE(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 func resOfLT(val int64, isNull bool, err error) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
@@ -480,6 +1892,104 @@ func resOfGE(val int64, isNull bool, err error) (int64, bool, error) {
 	return val, false, nil
 }
 
+type builtinNullEQIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQIntSig) Clone() builtinFunc {
+	newSig := &builtinNullEQIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQRealSig) Clone() builtinFunc {
+	newSig := &builtinNullEQRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQDecimalSig) Clone() builtinFunc {
+	newSig := &builtinNullEQDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQDecimalSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareDecimal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQTimeSig) Clone() builtinFunc {
+	newSig := &builtinNullEQTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQTimeSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareDatetime(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQDurationSig) Clone() builtinFunc {
+	newSig := &builtinNullEQDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQDurationSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareDuration(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQStringSig) Clone() builtinFunc {
+	newSig := &builtinNullEQStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQJSONSig) Clone() builtinFunc {
+	newSig := &builtinNullEQJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQJSONSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareJSON(b.ctx, b.args[0], b.args[1], row, row))
+}
+
 func resOfEQ(val int64, isNull bool, err error) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
@@ -492,6 +2002,19 @@ func resOfEQ(val int64, isNull bool, err error) (int64, bool, error) {
 	return val, false, nil
 }
 
+// resOfNullEQ treats two NULL values as equal and never returns NULL.
+func resOfNullEQ(val int64, isNull bool, err error) (int64, bool, error) {
+	if err != nil {
+		return 0, true, err
+	}
+	if val == 0 {
+		val = 1
+	} else {
+		val = 0
+	}
+	return val, false, nil
+}
+
 func resOfNE(val int64, isNull bool, err error) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
@@ -596,3 +2119,75 @@ func CompareReal(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhs
 	}
 	return int64(types.CompareFloat64(arg0, arg1)), false, nil
 }
+
+// CompareDecimal compares two decimals.
+func CompareDecimal(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalDecimal(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalDecimal(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareDatetime compares two datetime or timestamps.
+func CompareDatetime(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalTime(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalTime(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareDuration compares two durations.
+func CompareDuration(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalDuration(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalDuration(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(arg0.Compare(arg1)), false, nil
+}
+
+// CompareJSON compares two JSONs.
+func CompareJSON(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := lhsArg.EvalJSON(sctx, lhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	arg1, isNull1, err := rhsArg.EvalJSON(sctx, rhsRow)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if isNull0 || isNull1 {
+		return compareNull(isNull0, isNull1), true, nil
+	}
+	return int64(json.CompareBinary(arg0, arg1)), false, nil
+}
@@ -17,6 +17,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -34,6 +35,8 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		{stringVal, stringVal, ast.LT, mysql.TypeVarString, 0},
 		{realVal, realVal, ast.LT, mysql.TypeDouble, 0},
 		{uintVal, uintVal, ast.EQ, mysql.TypeLonglong, 1},
+		{intVal, intVal, ast.NullEQ, mysql.TypeLonglong, 1},
+		{stringVal, "124", ast.NullEQ, mysql.TypeVarString, 0},
 	}
 
 	for _, t := range tests {
@@ -48,3 +51,77 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		c.Assert(res, Equals, t.expected)
 	}
 }
+
+func (s *testEvaluatorSuite) TestCoalesce(c *C) {
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		isNil    bool
+	}{
+		{[]interface{}{nil}, nil, true},
+		{[]interface{}{nil, nil}, nil, true},
+		{[]interface{}{nil, nil, nil}, nil, true},
+		{[]interface{}{nil, 1}, int64(1), false},
+		{[]interface{}{nil, 1.1}, float64(1.1), false},
+		{[]interface{}{1, 1.1}, float64(1), false},
+		{[]interface{}{nil, types.NewDecFromFloatForTest(123.456)}, types.NewDecFromFloatForTest(123.456), false},
+		{[]interface{}{1, types.NewDecFromFloatForTest(123.456)}, types.NewDecFromInt(1), false},
+		{[]interface{}{nil, "abc"}, "abc", false},
+		{[]interface{}{nil, 1, "abc"}, "1", false},
+	}
+
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Coalesce, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			if d.Kind() == types.KindMysqlDecimal {
+				c.Assert(d.GetMysqlDecimal().Compare(t.expected.(*types.MyDecimal)), Equals, 0)
+			} else {
+				c.Assert(d.GetValue(), Equals, t.expected)
+			}
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestGreatestLeast(c *C) {
+	cases := []struct {
+		args             []interface{}
+		expectedGreatest interface{}
+		expectedLeast    interface{}
+		isNil            bool
+	}{
+		{[]interface{}{1, 2, 3}, int64(3), int64(1), false},
+		{[]interface{}{1.5, 2.5, -3.5}, float64(2.5), float64(-3.5), false},
+		{[]interface{}{"123", "45", 12}, float64(123), float64(12), false},
+		{[]interface{}{"abc", "ABC", "abd"}, "abd", "ABC", false},
+		{[]interface{}{1, nil, 3}, nil, nil, true},
+		{[]interface{}{nil, nil}, nil, nil, true},
+	}
+
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Greatest, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetValue(), DeepEquals, t.expectedGreatest, Commentf("%v", t.args))
+		}
+
+		f, err = newFunctionForTest(s.ctx, ast.Least, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetValue(), DeepEquals, t.expectedLeast, Commentf("%v", t.args))
+		}
+	}
+}
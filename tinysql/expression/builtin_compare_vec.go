@@ -211,6 +211,360 @@ func (b *builtinLTIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) e
 	return nil
 }
 
+func (b *builtinGreatestIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	res := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if isUnsigned && uint64(v[i]) > uint64(res[i]) || !isUnsigned && v[i] > res[i] {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	res := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if v[i] > res[i] {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+	res := result.Decimals()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalDecimal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Decimals()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if v[i].Compare(&res[i]) > 0 {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestTimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestTimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, result); err != nil {
+		return err
+	}
+	res := result.Times()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalTime(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Times()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if v[i].Compare(res[i]) > 0 {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinGreatestStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGreatestStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := 0; j < len(b.args); j++ {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull := false
+		for j := 0; j < len(b.args); j++ {
+			if bufs[j].IsNull(i) {
+				isNull = true
+				break
+			}
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		res := bufs[0].GetString(i)
+		for j := 1; j < len(b.args); j++ {
+			v := bufs[j].GetString(i)
+			if types.CompareString(v, res) > 0 {
+				res = v
+			}
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinLeastIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	res := result.Int64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalInt(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Int64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if isUnsigned && uint64(v[i]) < uint64(res[i]) || !isUnsigned && v[i] < res[i] {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	res := result.Float64s()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalReal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if v[i] < res[i] {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+	res := result.Decimals()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalDecimal(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Decimals()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if v[i].Compare(&res[i]) < 0 {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastTimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastTimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, result); err != nil {
+		return err
+	}
+	res := result.Times()
+	for j := 1; j < len(b.args); j++ {
+		if err := b.args[j].VecEvalTime(b.ctx, input, buf); err != nil {
+			return err
+		}
+		result.MergeNulls(buf)
+		v := buf.Times()
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) {
+				continue
+			}
+			if v[i].Compare(res[i]) < 0 {
+				res[i] = v[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builtinLeastStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeastStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j := 0; j < len(b.args); j++ {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := b.args[j].VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		isNull := false
+		for j := 0; j < len(b.args); j++ {
+			if bufs[j].IsNull(i) {
+				isNull = true
+				break
+			}
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		res := bufs[0].GetString(i)
+		for j := 1; j < len(b.args); j++ {
+			v := bufs[j].GetString(i)
+			if types.CompareString(v, res) < 0 {
+				res = v
+			}
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
 func vecResOfLT(res []int64) {
 	n := len(res)
 	for i := 0; i < n; i++ {
@@ -19,22 +19,39 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
 var (
+	_ functionClass = &caseWhenFunctionClass{}
 	_ functionClass = &ifFunctionClass{}
 	_ functionClass = &ifNullFunctionClass{}
 )
 
 var (
+	_ builtinFunc = &builtinCaseWhenIntSig{}
+	_ builtinFunc = &builtinCaseWhenRealSig{}
+	_ builtinFunc = &builtinCaseWhenDecimalSig{}
+	_ builtinFunc = &builtinCaseWhenStringSig{}
+	_ builtinFunc = &builtinCaseWhenTimeSig{}
+	_ builtinFunc = &builtinCaseWhenDurationSig{}
+	_ builtinFunc = &builtinCaseWhenJSONSig{}
 	_ builtinFunc = &builtinIfNullIntSig{}
 	_ builtinFunc = &builtinIfNullRealSig{}
+	_ builtinFunc = &builtinIfNullDecimalSig{}
 	_ builtinFunc = &builtinIfNullStringSig{}
+	_ builtinFunc = &builtinIfNullTimeSig{}
+	_ builtinFunc = &builtinIfNullDurationSig{}
+	_ builtinFunc = &builtinIfNullJSONSig{}
 	_ builtinFunc = &builtinIfIntSig{}
 	_ builtinFunc = &builtinIfRealSig{}
+	_ builtinFunc = &builtinIfDecimalSig{}
 	_ builtinFunc = &builtinIfStringSig{}
+	_ builtinFunc = &builtinIfTimeSig{}
+	_ builtinFunc = &builtinIfDurationSig{}
+	_ builtinFunc = &builtinIfJSONSig{}
 )
 
 // InferType4ControlFuncs infer result type for builtin IF, IFNULL, NULLIF, LEAD and LAG.
@@ -111,6 +128,339 @@ func InferType4ControlFuncs(lhs, rhs *types.FieldType) *types.FieldType {
 	return resultFieldType
 }
 
+type caseWhenFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *caseWhenFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	l := len(args)
+	// Fill in each 'THEN' clause parameter type.
+	fieldTps := make([]*types.FieldType, 0, (l+1)/2)
+	for i := 1; i < l; i += 2 {
+		fieldTps = append(fieldTps, args[i].GetType())
+	}
+	if l%2 == 1 {
+		fieldTps = append(fieldTps, args[l-1].GetType())
+	}
+	decimal, flen, isBinaryStr, isBinaryFlag := fieldTps[0].Decimal, 0, false, false
+	for _, tp := range fieldTps {
+		decimal = mathutil.Max(decimal, tp.Decimal)
+		flen = mathutil.Max(flen, tp.Flen)
+		isBinaryStr = isBinaryStr || types.IsBinaryStr(tp)
+		isBinaryFlag = isBinaryFlag || !types.IsNonBinaryStr(tp)
+	}
+
+	fieldTp := types.AggFieldType(fieldTps)
+	// Here we turn off NotNullFlag. Because if all when-clauses are false,
+	// the result of case-when expr is NULL.
+	fieldTp.Flag &^= mysql.NotNullFlag
+	tp := fieldTp.EvalType()
+	if tp == types.ETInt {
+		decimal = 0
+	}
+	fieldTp.Decimal, fieldTp.Flen = decimal, flen
+	if fieldTp.EvalType().IsStringKind() && !isBinaryStr {
+		fieldTp.Charset, fieldTp.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+	}
+	if isBinaryFlag {
+		fieldTp.Flag |= mysql.BinaryFlag
+	}
+	// Set retType to BINARY(0) if all arguments are of type NULL.
+	if fieldTp.Tp == mysql.TypeNull {
+		fieldTp.Flen, fieldTp.Decimal = 0, types.UnspecifiedLength
+		types.SetBinChsClnFlag(fieldTp)
+	}
+	argTps := make([]types.EvalType, 0, l)
+	for i := 0; i < l-1; i += 2 {
+		argTps = append(argTps, types.ETInt, tp)
+	}
+	if l%2 == 1 {
+		argTps = append(argTps, tp)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, tp, argTps...)
+	bf.tp = fieldTp
+
+	switch tp {
+	case types.ETInt:
+		sig = &builtinCaseWhenIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenInt)
+	case types.ETReal:
+		sig = &builtinCaseWhenRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenReal)
+	case types.ETDecimal:
+		sig = &builtinCaseWhenDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenDecimal)
+	case types.ETString:
+		sig = &builtinCaseWhenStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinCaseWhenTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenTime)
+	case types.ETDuration:
+		sig = &builtinCaseWhenDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenDuration)
+	case types.ETJson:
+		sig = &builtinCaseWhenJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CaseWhenJson)
+	}
+	return sig, nil
+}
+
+type builtinCaseWhenIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenIntSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCaseWhenIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenIntSig) evalInt(row chunk.Row) (ret int64, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalInt(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalInt(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenRealSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinCaseWhenRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenRealSig) evalReal(row chunk.Row) (ret float64, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalReal(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalReal(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinCaseWhenDecimalSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenDecimalSig) evalDecimal(row chunk.Row) (ret *types.MyDecimal, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalDecimal(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalDecimal(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenStringSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinCaseWhenStringSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenStringSig) evalString(row chunk.Row) (ret string, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalString(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalString(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenTimeSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinCaseWhenTimeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenTimeSig) evalTime(row chunk.Row) (ret types.Time, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalTime(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalTime(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDurationSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals a builtinCaseWhenDurationSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenDurationSig) evalDuration(row chunk.Row) (ret types.Duration, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalDuration(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalDuration(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
+type builtinCaseWhenJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenJSONSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinCaseWhenJSONSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/control-flow-functions.html#operator_case
+func (b *builtinCaseWhenJSONSig) evalJSON(row chunk.Row) (ret json.BinaryJSON, isNull bool, err error) {
+	var condition int64
+	args, l := b.getArgs(), len(b.getArgs())
+	for i := 0; i < l-1; i += 2 {
+		condition, isNull, err = args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return ret, isNull, err
+		}
+		if isNull || condition == 0 {
+			continue
+		}
+		ret, isNull, err = args[i+1].EvalJSON(b.ctx, row)
+		return ret, isNull, err
+	}
+	// when clause(condition, result) -> args[i], args[i+1]; (i >= 0 && i+1 < l-1)
+	// else clause -> args[l-1]
+	// If case clause has else clause, l%2 == 1.
+	if l%2 == 1 {
+		ret, isNull, err = args[l-1].EvalJSON(b.ctx, row)
+		return ret, isNull, err
+	}
+	return ret, true, nil
+}
+
 type ifFunctionClass struct {
 	baseFunctionClass
 }
@@ -132,9 +482,21 @@ func (c *ifFunctionClass) getFunction(ctx sessionctx.Context, args []Expression)
 	case types.ETReal:
 		sig = &builtinIfRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfReal)
+	case types.ETDecimal:
+		sig = &builtinIfDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfDecimal)
 	case types.ETString:
 		sig = &builtinIfStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinIfTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfTime)
+	case types.ETDuration:
+		sig = &builtinIfDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfDuration)
+	case types.ETJson:
+		sig = &builtinIfJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfJson)
 	}
 	return sig, nil
 }
@@ -185,6 +547,75 @@ func (b *builtinIfRealSig) evalReal(row chunk.Row) (ret float64, isNull bool, er
 	return arg2, isNull2, err
 }
 
+type builtinIfDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfDecimalSig) Clone() builtinFunc {
+	newSig := &builtinIfDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfDecimalSig) evalDecimal(row chunk.Row) (ret *types.MyDecimal, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return nil, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalDecimal(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalDecimal(b.ctx, row)
+	return arg2, isNull2, err
+}
+
+type builtinIfTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfTimeSig) Clone() builtinFunc {
+	newSig := &builtinIfTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfTimeSig) evalTime(row chunk.Row) (ret types.Time, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalTime(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalTime(b.ctx, row)
+	return arg2, isNull2, err
+}
+
+type builtinIfDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfDurationSig) Clone() builtinFunc {
+	newSig := &builtinIfDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfDurationSig) evalDuration(row chunk.Row) (ret types.Duration, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalDuration(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalDuration(b.ctx, row)
+	return arg2, isNull2, err
+}
+
 type builtinIfStringSig struct {
 	baseBuiltinFunc
 }
@@ -208,6 +639,29 @@ func (b *builtinIfStringSig) evalString(row chunk.Row) (ret string, isNull bool,
 	return arg2, isNull2, err
 }
 
+type builtinIfJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfJSONSig) Clone() builtinFunc {
+	newSig := &builtinIfJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfJSONSig) evalJSON(row chunk.Row) (ret json.BinaryJSON, isNull bool, err error) {
+	arg0, isNull0, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return ret, true, err
+	}
+	arg1, isNull1, err := b.args[1].EvalJSON(b.ctx, row)
+	if (!isNull0 && arg0 != 0) || err != nil {
+		return arg1, isNull1, err
+	}
+	arg2, isNull2, err := b.args[2].EvalJSON(b.ctx, row)
+	return arg2, isNull2, err
+}
+
 type ifNullFunctionClass struct {
 	baseFunctionClass
 }
@@ -234,9 +688,21 @@ func (c *ifNullFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	case types.ETReal:
 		sig = &builtinIfNullRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfNullReal)
+	case types.ETDecimal:
+		sig = &builtinIfNullDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullDecimal)
 	case types.ETString:
 		sig = &builtinIfNullStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_IfNullString)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinIfNullTimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullTime)
+	case types.ETDuration:
+		sig = &builtinIfNullDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullDuration)
+	case types.ETJson:
+		sig = &builtinIfNullJSONSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_IfNullJson)
 	}
 	return sig, nil
 }
@@ -279,6 +745,63 @@ func (b *builtinIfNullRealSig) evalReal(row chunk.Row) (float64, bool, error) {
 	return arg1, isNull || err != nil, err
 }
 
+type builtinIfNullDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullDecimalSig) Clone() builtinFunc {
+	newSig := &builtinIfNullDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	arg0, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalDecimal(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
+type builtinIfNullTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullTimeSig) Clone() builtinFunc {
+	newSig := &builtinIfNullTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullTimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	arg0, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
+type builtinIfNullDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullDurationSig) Clone() builtinFunc {
+	newSig := &builtinIfNullDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullDurationSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	arg0, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalDuration(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
+
 type builtinIfNullStringSig struct {
 	baseBuiltinFunc
 }
@@ -297,3 +820,22 @@ func (b *builtinIfNullStringSig) evalString(row chunk.Row) (string, bool, error)
 	arg1, isNull, err := b.args[1].EvalString(b.ctx, row)
 	return arg1, isNull || err != nil, err
 }
+
+type builtinIfNullJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIfNullJSONSig) Clone() builtinFunc {
+	newSig := &builtinIfNullJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIfNullJSONSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	arg0, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if !isNull || err != nil {
+		return arg0, err != nil, err
+	}
+	arg1, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	return arg1, isNull || err != nil, err
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestCaseWhen(c *C) {
+	tbl := []struct {
+		Arg []interface{}
+		Ret interface{}
+	}{
+		{[]interface{}{true, 1, true, 2, 3}, 1},
+		{[]interface{}{false, 1, true, 2, 3}, 2},
+		{[]interface{}{nil, 1, true, 2, 3}, 2},
+		{[]interface{}{false, 1, false, 2, 3}, 3},
+		{[]interface{}{nil, 1, nil, 2, 3}, 3},
+		{[]interface{}{false, 1, nil, 2, 3}, 3},
+		{[]interface{}{nil, 1, false, 2, 3}, 3},
+		{[]interface{}{1, 1, 0, 2, 3}, 1},
+		{[]interface{}{0, 1, 1, 2, 3}, 2},
+		{[]interface{}{false, 1, false, 2}, nil},
+		{[]interface{}{true, 1.5, false, 2}, 1.5},
+		{[]interface{}{false, 1, false, "abc"}, nil},
+		{[]interface{}{false, 1, true, "abc"}, "abc"},
+		{[]interface{}{types.NewDecFromInt(1), 2, 3}, 2},
+	}
+	fc := funcs[ast.Case]
+	for _, t := range tbl {
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(t.Arg...)))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.Ret), Commentf("%v", t.Arg))
+	}
+}
@@ -0,0 +1,492 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &jsonExtractFunctionClass{}
+	_ functionClass = &jsonUnquoteFunctionClass{}
+	_ functionClass = &jsonSetFunctionClass{}
+	_ functionClass = &jsonInsertFunctionClass{}
+	_ functionClass = &jsonReplaceFunctionClass{}
+	_ functionClass = &jsonObjectFunctionClass{}
+	_ functionClass = &jsonArrayFunctionClass{}
+	_ functionClass = &jsonContainsFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinJSONExtractSig{}
+	_ builtinFunc = &builtinJSONUnquoteSig{}
+	_ builtinFunc = &builtinJSONSetSig{}
+	_ builtinFunc = &builtinJSONInsertSig{}
+	_ builtinFunc = &builtinJSONReplaceSig{}
+	_ builtinFunc = &builtinJSONObjectSig{}
+	_ builtinFunc = &builtinJSONArraySig{}
+	_ builtinFunc = &builtinJSONContainsSig{}
+)
+
+// wrapWithParseAsJSON wraps a string argument which stands for a JSON document
+// with a cast that parses it. Other strings passed to JSON functions, such as
+// the values of JSON_OBJECT, are taken as JSON string scalars instead.
+func wrapWithParseAsJSON(ctx sessionctx.Context, arg Expression) Expression {
+	if arg.GetType().EvalType() != types.ETString {
+		return arg
+	}
+	tp := &types.FieldType{
+		Tp:      mysql.TypeJSON,
+		Flen:    mysql.MaxBlobWidth,
+		Decimal: 0,
+		Charset: mysql.DefaultCharset,
+		Collate: mysql.DefaultCollationName,
+		Flag:    mysql.BinaryFlag | mysql.ParseToJSONFlag,
+	}
+	return BuildCastFunction(ctx, arg, tp)
+}
+
+// verifyJSONDocArgs checks the arguments at the given positions are strings or
+// JSON values, which are the only types MySQL accepts as a JSON document.
+func verifyJSONDocArgs(funcName string, args []Expression, pos ...int) error {
+	for _, i := range pos {
+		if i >= len(args) {
+			continue
+		}
+		if evalType := args[i].GetType().EvalType(); evalType != types.ETString && evalType != types.ETJson {
+			return errInvalidTypeForJSON.GenWithStackByArgs(i+1, funcName)
+		}
+	}
+	return nil
+}
+
+// evalJSONPathExprs evaluates and parses the path arguments starting at
+// args[begin] every step arguments. It returns isNull when any path is NULL.
+func evalJSONPathExprs(ctx sessionctx.Context, args []Expression, row chunk.Row, begin, step int) (pathExprs []json.PathExpression, isNull bool, err error) {
+	pathExprs = make([]json.PathExpression, 0, (len(args)-begin+step-1)/step)
+	for i := begin; i < len(args); i += step {
+		s, isNull, err := args[i].EvalString(ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		pathExpr, err := json.ParseJSONPathExpr(s)
+		if err != nil {
+			return nil, true, err
+		}
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs, false, nil
+}
+
+// evalJSONOrNull evaluates arg as JSON, a NULL value becomes the JSON null literal.
+func evalJSONOrNull(ctx sessionctx.Context, arg Expression, row chunk.Row) (json.BinaryJSON, error) {
+	val, isNull, err := arg.EvalJSON(ctx, row)
+	if err != nil {
+		return val, err
+	}
+	if isNull {
+		return json.CreateBinary(nil), nil
+	}
+	return val, nil
+}
+
+type jsonExtractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonExtractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if err := verifyJSONDocArgs(c.funcName, args, 0); err != nil {
+		return nil, err
+	}
+	args[0] = wrapWithParseAsJSON(ctx, args[0])
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONExtractSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonExtractSig)
+	return sig, nil
+}
+
+type builtinJSONExtractSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONExtractSig) Clone() builtinFunc {
+	newSig := &builtinJSONExtractSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONExtractSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+func (b *builtinJSONExtractSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	pathExprs, isNull, err := evalJSONPathExprs(b.ctx, b.args, row, 1, 1)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	var found bool
+	if res, found = res.Extract(pathExprs); !found {
+		return res, true, nil
+	}
+	return res, false, nil
+}
+
+type jsonUnquoteFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonUnquoteFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = mysql.MaxFieldVarCharLength
+	sig := &builtinJSONUnquoteSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonUnquoteSig)
+	return sig, nil
+}
+
+type builtinJSONUnquoteSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONUnquoteSig) Clone() builtinFunc {
+	newSig := &builtinJSONUnquoteSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinJSONUnquoteSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+func (b *builtinJSONUnquoteSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	str, err = json.UnquoteString(str)
+	if err != nil {
+		return "", false, err
+	}
+	return str, false, nil
+}
+
+// getJSONModifyFunction builds the signature of JSON_SET, JSON_INSERT and
+// JSON_REPLACE, which all take a document followed by path-value pairs.
+func getJSONModifyFunction(ctx sessionctx.Context, c *baseFunctionClass, args []Expression) (baseBuiltinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return baseBuiltinFunc{}, err
+	}
+	if len(args)&1 != 1 {
+		return baseBuiltinFunc{}, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	if err := verifyJSONDocArgs(c.funcName, args, 0); err != nil {
+		return baseBuiltinFunc{}, err
+	}
+	args[0] = wrapWithParseAsJSON(ctx, args[0])
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for i := 1; i < len(args)-1; i += 2 {
+		argTps = append(argTps, types.ETString, types.ETJson)
+	}
+	return newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...), nil
+}
+
+// evalJSONModify evaluates JSON_SET, JSON_INSERT and JSON_REPLACE. A NULL
+// document or path yields NULL, a NULL value is stored as the JSON null literal.
+func evalJSONModify(ctx sessionctx.Context, args []Expression, row chunk.Row, mt json.ModifyType) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = args[0].EvalJSON(ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	pathExprs, isNull, err := evalJSONPathExprs(ctx, args, row, 1, 2)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	values := make([]json.BinaryJSON, 0, len(pathExprs))
+	for i := 2; i < len(args); i += 2 {
+		value, err := evalJSONOrNull(ctx, args[i], row)
+		if err != nil {
+			return res, true, err
+		}
+		values = append(values, value)
+	}
+	res, err = res.Modify(pathExprs, values, mt)
+	if err != nil {
+		return res, true, err
+	}
+	return res, false, nil
+}
+
+type jsonSetFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonSetFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := getJSONModifyFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONSetSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonSetSig)
+	return sig, nil
+}
+
+type builtinJSONSetSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONSetSig) Clone() builtinFunc {
+	newSig := &builtinJSONSetSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONSetSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-set
+func (b *builtinJSONSetSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	return evalJSONModify(b.ctx, b.args, row, json.ModifySet)
+}
+
+type jsonInsertFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonInsertFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := getJSONModifyFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONInsertSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonInsertSig)
+	return sig, nil
+}
+
+type builtinJSONInsertSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONInsertSig) Clone() builtinFunc {
+	newSig := &builtinJSONInsertSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONInsertSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-insert
+func (b *builtinJSONInsertSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	return evalJSONModify(b.ctx, b.args, row, json.ModifyInsert)
+}
+
+type jsonReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := getJSONModifyFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONReplaceSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonReplaceSig)
+	return sig, nil
+}
+
+type builtinJSONReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONReplaceSig) Clone() builtinFunc {
+	newSig := &builtinJSONReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-replace
+func (b *builtinJSONReplaceSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	return evalJSONModify(b.ctx, b.args, row, json.ModifyReplace)
+}
+
+type jsonObjectFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonObjectFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)&1 != 0 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := 0; i < len(args)-1; i += 2 {
+		argTps = append(argTps, types.ETString, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONObjectSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonObjectSig)
+	return sig, nil
+}
+
+type builtinJSONObjectSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONObjectSig) Clone() builtinFunc {
+	newSig := &builtinJSONObjectSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONObjectSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-object
+func (b *builtinJSONObjectSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	jsons := make(map[string]interface{}, len(b.args)>>1)
+	for i := 0; i < len(b.args); i += 2 {
+		key, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if err != nil {
+			return res, true, err
+		}
+		if isNull {
+			return res, true, json.ErrJSONDocumentNULLKey
+		}
+		value, err := evalJSONOrNull(b.ctx, b.args[i+1], row)
+		if err != nil {
+			return res, true, err
+		}
+		jsons[key] = value
+	}
+	return json.CreateBinary(jsons), false, nil
+}
+
+type jsonArrayFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONArraySig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonArraySig)
+	return sig, nil
+}
+
+type builtinJSONArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONArraySig) Clone() builtinFunc {
+	newSig := &builtinJSONArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals a builtinJSONArraySig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-array
+func (b *builtinJSONArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	jsons := make([]interface{}, 0, len(b.args))
+	for _, arg := range b.args {
+		value, err := evalJSONOrNull(b.ctx, arg, row)
+		if err != nil {
+			return res, true, err
+		}
+		jsons = append(jsons, value)
+	}
+	return json.CreateBinary(jsons), false, nil
+}
+
+type jsonContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if err := verifyJSONDocArgs(c.funcName, args, 0, 1); err != nil {
+		return nil, err
+	}
+	args[0] = wrapWithParseAsJSON(ctx, args[0])
+	args[1] = wrapWithParseAsJSON(ctx, args[1])
+	argTps := []types.EvalType{types.ETJson, types.ETJson}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinJSONContainsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonContainsSig)
+	return sig, nil
+}
+
+type builtinJSONContainsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONContainsSig) Clone() builtinFunc {
+	newSig := &builtinJSONContainsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinJSONContainsSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains
+func (b *builtinJSONContainsSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	obj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	target, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if len(b.args) == 3 {
+		pathExprs, isNull, err := evalJSONPathExprs(b.ctx, b.args, row, 2, 1)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if pathExprs[0].ContainsAnyAsterisk() {
+			return res, true, json.ErrInvalidJSONPathWildcard
+		}
+		var exists bool
+		if obj, exists = obj.Extract(pathExprs); !exists {
+			return res, true, nil
+		}
+	}
+	if json.ContainsBinary(obj, target) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestJSONExtract(c *C) {
+	jstr := `{"a": [{"aa": [{"aaa": 1}]}], "aaa": 2}`
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{nil, nil}, nil, true},
+		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$.aaa`}, `[1, 2]`, true},
+		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$InvalidPath`}, nil, false},
+		{[]interface{}{jstr, `$.a`}, `[{"aa": [{"aaa": 1}]}]`, true},
+		{[]interface{}{jstr, `$.b`}, nil, true},
+		{[]interface{}{jstr, nil}, nil, true},
+		{[]interface{}{`[1, 2, 3]`, `$[1]`}, `2`, true},
+		{[]interface{}{`{"a": 1`, `$.a`}, nil, false},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONExtract, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.success {
+			c.Assert(err, IsNil)
+			switch x := t.expected.(type) {
+			case string:
+				j1, err := json.ParseBinaryFromString(x)
+				c.Assert(err, IsNil)
+				cmp := json.CompareBinary(j1, d.GetMysqlJSON())
+				c.Assert(cmp, Equals, 0)
+			case nil:
+				c.Assert(d.IsNull(), IsTrue)
+			}
+		} else {
+			c.Assert(err, NotNil)
+		}
+	}
+
+	_, err := funcs[ast.JSONExtract].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{1, `$`}))
+	c.Assert(err, NotNil)
+}
+
+func (s *testEvaluatorSuite) TestJSONUnquote(c *C) {
+	cases := []struct {
+		arg      interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{``, ``},
+		{`""`, ``},
+		{`''`, `''`},
+		{`"a"`, `a`},
+		{`3`, `3`},
+		{`{"a": "b"}`, `{"a": "b"}`},
+		{`"啊"`, `啊`},
+		{`"\t\n"`, "\t\n"},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONUnquote, s.primitiveValsToConstants([]interface{}{t.arg})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetString(), Equals, t.expected)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONModify(c *C) {
+	cases := []struct {
+		fn       string
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{ast.JSONSet, []interface{}{nil, `$`, 3}, nil, true},
+		{ast.JSONSet, []interface{}{`{}`, nil, 3}, nil, true},
+		{ast.JSONSet, []interface{}{`{}`, `$.a`, nil}, `{"a": null}`, true},
+		{ast.JSONSet, []interface{}{`{}`, `$.a`, 3}, `{"a": 3}`, true},
+		{ast.JSONSet, []interface{}{`{}`, `$.a`, "b"}, `{"a": "b"}`, true},
+		{ast.JSONSet, []interface{}{`{"a": 1}`, `$.a`, 2, `$.b`, 3}, `{"a": 2, "b": 3}`, true},
+		{ast.JSONSet, []interface{}{`[1, 2]`, `$[5]`, 3}, `[1, 2, 3]`, true},
+		{ast.JSONSet, []interface{}{`{}`, `$.*`, 3}, nil, false},
+		{ast.JSONSet, []interface{}{`{}`, `$InvalidPath`, 3}, nil, false},
+		{ast.JSONInsert, []interface{}{`{"a": 1}`, `$.a`, 2, `$.b`, 3}, `{"a": 1, "b": 3}`, true},
+		{ast.JSONReplace, []interface{}{`{"a": 1}`, `$.a`, 2, `$.b`, 3}, `{"a": 2}`, true},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.success {
+			c.Assert(err, IsNil)
+			switch x := t.expected.(type) {
+			case string:
+				j1, err := json.ParseBinaryFromString(x)
+				c.Assert(err, IsNil)
+				cmp := json.CompareBinary(j1, d.GetMysqlJSON())
+				c.Assert(cmp, Equals, 0, Commentf("got %v expect %v", d.GetMysqlJSON(), j1))
+			case nil:
+				c.Assert(d.IsNull(), IsTrue)
+			}
+		} else {
+			c.Assert(err, NotNil)
+		}
+	}
+
+	_, err := funcs[ast.JSONSet].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{`{}`, `$.a`, 1, `$.b`}))
+	c.Assert(err, NotNil)
+}
+
+func (s *testEvaluatorSuite) TestJSONObjectAndArray(c *C) {
+	cases := []struct {
+		fn       string
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{ast.JSONObject, []interface{}{}, `{}`, true},
+		{ast.JSONObject, []interface{}{"a", 1, "b", "x", "c", nil}, `{"a": 1, "b": "x", "c": null}`, true},
+		{ast.JSONObject, []interface{}{"a", `{"b": 1}`}, `{"a": "{\"b\": 1}"}`, true},
+		{ast.JSONObject, []interface{}{nil, 1}, nil, false},
+		{ast.JSONArray, []interface{}{}, `[]`, true},
+		{ast.JSONArray, []interface{}{1, "a", nil, 2.5}, `[1, "a", null, 2.5]`, true},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.fn, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.success {
+			c.Assert(err, IsNil)
+			j1, err := json.ParseBinaryFromString(t.expected.(string))
+			c.Assert(err, IsNil)
+			cmp := json.CompareBinary(j1, d.GetMysqlJSON())
+			c.Assert(cmp, Equals, 0, Commentf("got %v expect %v", d.GetMysqlJSON(), j1))
+		} else {
+			c.Assert(err, NotNil)
+		}
+	}
+
+	_, err := funcs[ast.JSONObject].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{"a"}))
+	c.Assert(err, NotNil)
+}
+
+func (s *testEvaluatorSuite) TestJSONContains(c *C) {
+	jstr := `{"a": [1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}], "b": true, "c": ["d"], "\"e": false}`
+	cases := []struct {
+		args     []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{jstr, `1`, `$.c`}, 0, true},
+		{[]interface{}{jstr, `"d"`, `$.c`}, 1, true},
+		{[]interface{}{jstr, `{"aa": "bb"}`, `$.a`}, 1, true},
+		{[]interface{}{jstr, `[{"aa": "bb"}, 1]`, `$.a`}, 1, true},
+		{[]interface{}{jstr, `4`, `$.a`}, 1, true},
+		{[]interface{}{jstr, `true`, `$.b`}, 1, true},
+		{[]interface{}{jstr, `{"b": true}`}, 1, true},
+		{[]interface{}{jstr, `{"b": false}`}, 0, true},
+		{[]interface{}{jstr, `1`, `$.x`}, nil, true},
+		{[]interface{}{nil, `1`}, nil, true},
+		{[]interface{}{jstr, nil}, nil, true},
+		{[]interface{}{jstr, `1`, nil}, nil, true},
+		{[]interface{}{jstr, `1`, `$.*`}, nil, false},
+		{[]interface{}{jstr, `1`, `$InvalidPath`}, nil, false},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.JSONContains, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.success {
+			c.Assert(err, IsNil)
+			if t.expected == nil {
+				c.Assert(d.IsNull(), IsTrue)
+			} else {
+				c.Assert(d.GetInt64(), Equals, int64(t.expected.(int)))
+			}
+		} else {
+			c.Assert(err, NotNil)
+		}
+	}
+
+	_, err := funcs[ast.JSONContains].getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{jstr, 1}))
+	c.Assert(err, NotNil)
+	c.Assert(errInvalidTypeForJSON.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONCompare(c *C) {
+	cases := []struct {
+		lhs      interface{}
+		rhs      interface{}
+		expected int64
+	}{
+		{`{"a": 1}`, `{"a": 1}`, 1},
+		{`[1, 2]`, `[1, 2]`, 1},
+		{`[1, 2]`, `[2, 1]`, 0},
+		{`1`, `1.0`, 1},
+		{`"a"`, `"a"`, 1},
+		{`true`, `1`, 0},
+	}
+	for _, t := range cases {
+		lhs, err := newFunctionForTest(s.ctx, ast.JSONExtract, s.primitiveValsToConstants([]interface{}{t.lhs, `$`})...)
+		c.Assert(err, IsNil)
+		rhs, err := newFunctionForTest(s.ctx, ast.JSONExtract, s.primitiveValsToConstants([]interface{}{t.rhs, `$`})...)
+		c.Assert(err, IsNil)
+		f, err := newFunctionForTest(s.ctx, ast.EQ, lhs, rhs)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.Kind(), Equals, types.KindInt64)
+		c.Assert(d.GetInt64(), Equals, t.expected)
+	}
+}
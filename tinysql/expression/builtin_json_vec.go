@@ -0,0 +1,296 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalJSONFuncArgs evaluates every argument of a JSON function into a
+// buffer of the argument's own evaluation type. The caller should put the
+// buffers back by putJSONFuncArgBufs.
+func (b *baseBuiltinFunc) vecEvalJSONFuncArgs(input *chunk.Chunk) ([]*chunk.Column, error) {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	for _, arg := range b.args {
+		buf, err := b.bufAllocator.get(arg.GetType().EvalType(), n)
+		if err != nil {
+			b.putJSONFuncArgBufs(bufs)
+			return nil, err
+		}
+		bufs = append(bufs, buf)
+		if err := VecEval(b.ctx, arg, input, buf); err != nil {
+			b.putJSONFuncArgBufs(bufs)
+			return nil, err
+		}
+	}
+	return bufs, nil
+}
+
+func (b *baseBuiltinFunc) putJSONFuncArgBufs(bufs []*chunk.Column) {
+	for _, buf := range bufs {
+		b.bufAllocator.put(buf)
+	}
+}
+
+// parseJSONPathBufs parses the path strings of the i-th row in
+// bufs[begin], bufs[begin+step], ... It returns isNull when any path is NULL.
+func parseJSONPathBufs(bufs []*chunk.Column, i, begin, step int) (pathExprs []json.PathExpression, isNull bool, err error) {
+	pathExprs = make([]json.PathExpression, 0, (len(bufs)-begin+step-1)/step)
+	for j := begin; j < len(bufs); j += step {
+		if bufs[j].IsNull(i) {
+			return nil, true, nil
+		}
+		pathExpr, err := json.ParseJSONPathExpr(bufs[j].GetString(i))
+		if err != nil {
+			return nil, true, err
+		}
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs, false, nil
+}
+
+// getJSONOrNull gets the i-th JSON value of buf, a NULL value becomes the
+// JSON null literal.
+func getJSONOrNull(buf *chunk.Column, i int) json.BinaryJSON {
+	if buf.IsNull(i) {
+		return json.CreateBinary(nil)
+	}
+	return buf.GetJSON(i)
+}
+
+func (b *builtinJSONExtractSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONExtractSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalJSONFuncArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putJSONFuncArgBufs(bufs)
+
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		pathExprs, isNull, err := parseJSONPathBufs(bufs, i, 1, 1)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		res, found := bufs[0].GetJSON(i).Extract(pathExprs)
+		if !found {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(res)
+	}
+	return nil
+}
+
+func (b *builtinJSONUnquoteSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONUnquoteSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, err := json.UnquoteString(buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+// vecEvalJSONModify is the vectorized version of evalJSONModify.
+func (b *baseBuiltinFunc) vecEvalJSONModify(input *chunk.Chunk, result *chunk.Column, mt json.ModifyType) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalJSONFuncArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putJSONFuncArgBufs(bufs)
+
+	result.ReserveJSON(n)
+	values := make([]json.BinaryJSON, 0, len(bufs)/2)
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		pathExprs, isNull, err := parseJSONPathBufs(bufs, i, 1, 2)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		values = values[:0]
+		for j := 2; j < len(bufs); j += 2 {
+			values = append(values, getJSONOrNull(bufs[j], i))
+		}
+		res, err := bufs[0].GetJSON(i).Modify(pathExprs, values, mt)
+		if err != nil {
+			return err
+		}
+		result.AppendJSON(res)
+	}
+	return nil
+}
+
+func (b *builtinJSONSetSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONSetSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalJSONModify(input, result, json.ModifySet)
+}
+
+func (b *builtinJSONInsertSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONInsertSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalJSONModify(input, result, json.ModifyInsert)
+}
+
+func (b *builtinJSONReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONReplaceSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalJSONModify(input, result, json.ModifyReplace)
+}
+
+func (b *builtinJSONObjectSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONObjectSig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalJSONFuncArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putJSONFuncArgBufs(bufs)
+
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		jsons := make(map[string]interface{}, len(bufs)>>1)
+		for j := 0; j < len(bufs); j += 2 {
+			if bufs[j].IsNull(i) {
+				return json.ErrJSONDocumentNULLKey
+			}
+			jsons[bufs[j].GetString(i)] = getJSONOrNull(bufs[j+1], i)
+		}
+		result.AppendJSON(json.CreateBinary(jsons))
+	}
+	return nil
+}
+
+func (b *builtinJSONArraySig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONArraySig) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalJSONFuncArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putJSONFuncArgBufs(bufs)
+
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		jsons := make([]interface{}, 0, len(bufs))
+		for _, buf := range bufs {
+			jsons = append(jsons, getJSONOrNull(buf, i))
+		}
+		result.AppendJSON(json.CreateBinary(jsons))
+	}
+	return nil
+}
+
+func (b *builtinJSONContainsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONContainsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalJSONFuncArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putJSONFuncArgBufs(bufs)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs[0], bufs[1])
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		obj, target := bufs[0].GetJSON(i), bufs[1].GetJSON(i)
+		if len(bufs) == 3 {
+			pathExprs, isNull, err := parseJSONPathBufs(bufs, i, 2, 1)
+			if err != nil {
+				return err
+			}
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
+			if pathExprs[0].ContainsAnyAsterisk() {
+				return json.ErrInvalidJSONPathWildcard
+			}
+			var exists bool
+			if obj, exists = obj.Extract(pathExprs); !exists {
+				result.SetNull(i, true)
+				continue
+			}
+		}
+		if json.ContainsBinary(obj, target) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
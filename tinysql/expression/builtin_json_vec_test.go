@@ -0,0 +1,114 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+)
+
+// jsonObjectGener generates small JSON objects over a fixed set of keys, so
+// that the paths in the test cases have a chance to hit.
+type jsonObjectGener struct {
+	nullRation float64
+}
+
+func (g *jsonObjectGener) gen() interface{} {
+	if rand.Float64() < g.nullRation {
+		return nil
+	}
+	m := make(map[string]interface{}, 3)
+	for _, key := range []string{"a", "b", "c"} {
+		if rand.Intn(2) == 0 {
+			m[key] = rand.Int63n(5)
+		}
+	}
+	return json.CreateBinary(m)
+}
+
+// jsonScalarGener generates JSON numbers and booleans, which can be converted
+// to numbers without any warning.
+type jsonScalarGener struct{}
+
+func (g *jsonScalarGener) gen() interface{} {
+	switch rand.Intn(4) {
+	case 0:
+		return json.CreateBinary(rand.Int63n(1000) - 500)
+	case 1:
+		return json.CreateBinary(rand.Float64() * 1000)
+	case 2:
+		return json.CreateBinary(rand.Intn(2) == 0)
+	default:
+		return nil
+	}
+}
+
+var jsonPathGener = &selectStringGener{candidates: []string{"$", "$.a", "$.b", "$.c", "$.d"}}
+
+var jsonTextGener = &selectStringGener{candidates: []string{`{"a": 1}`, `{"a": [1, 2], "b": "x"}`, `[1, {"b": 2}]`, `"a"`, `3`}}
+
+var vecBuiltinJSONCases = map[string][]vecExprBenchCase{
+	ast.JSONExtract: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString}, geners: []dataGenerator{&jsonObjectGener{0.2}, jsonPathGener}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETString}, geners: []dataGenerator{&jsonObjectGener{0.2}, jsonPathGener, jsonPathGener}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{jsonTextGener, jsonPathGener}},
+	},
+	ast.JSONUnquote: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&selectStringGener{candidates: []string{`"a"`, `"啊"`, `b`, `"\t"`, `[1]`}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETJson}, geners: []dataGenerator{&jsonObjectGener{0.2}}},
+	},
+	ast.JSONSet: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETInt}, geners: []dataGenerator{&jsonObjectGener{0.2}, jsonPathGener, nil}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETJson, types.ETString, types.ETString}, geners: []dataGenerator{&jsonObjectGener{0.2}, jsonPathGener, &jsonObjectGener{0.2}, jsonPathGener, nil}},
+	},
+	ast.JSONInsert: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETInt}, geners: []dataGenerator{&jsonObjectGener{0.2}, jsonPathGener, nil}},
+	},
+	ast.JSONReplace: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETInt}, geners: []dataGenerator{&jsonObjectGener{0.2}, jsonPathGener, nil}},
+	},
+	ast.JSONObject: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString, types.ETJson}, geners: []dataGenerator{&randLenStrGener{1, 5}, nil, &randLenStrGener{1, 5}, &jsonObjectGener{0.2}}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETString, types.ETReal, types.ETString, types.ETString}, geners: []dataGenerator{&randLenStrGener{1, 5}, nil, &randLenStrGener{1, 5}, nil}},
+	},
+	ast.JSONArray: {
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{}},
+		{retEvalType: types.ETJson, childrenTypes: []types.EvalType{types.ETInt, types.ETString, types.ETJson}, geners: []dataGenerator{nil, nil, &jsonObjectGener{0.2}}},
+	},
+	ast.JSONContains: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson}, geners: []dataGenerator{&jsonObjectGener{0.2}, &jsonObjectGener{0.2}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETString, types.ETString}, geners: []dataGenerator{&jsonObjectGener{0.2}, &selectStringGener{candidates: []string{`1`, `2`, `{"a": 1}`}}, jsonPathGener}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinJSONEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinJSONCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinJSONFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinJSONCases)
+}
+
+func BenchmarkVectorizedBuiltinJSONEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinJSONCases)
+}
+
+func BenchmarkVectorizedBuiltinJSONFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinJSONCases)
+}
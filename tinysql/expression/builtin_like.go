@@ -0,0 +1,283 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &likeFunctionClass{}
+	_ functionClass = &regexpFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinLikeSig{}
+	_ builtinFunc = &builtinRegexpSig{}
+	_ builtinFunc = &builtinRegexpUTF8Sig{}
+)
+
+// PatternMatchCollation decides how the string and the pattern of LIKE and
+// REGEXP are matched according to their collations. If binary is true, the
+// bytes of the strings are matched. Otherwise the characters are matched, and
+// they are compared case-insensitively if ci is true.
+func PatternMatchCollation(str, pattern Expression) (binary, ci bool) {
+	if types.IsBinaryStr(str.GetType()) || types.IsBinaryStr(pattern.GetType()) {
+		return true, false
+	}
+	// A column has a stronger coercibility than a constant, so its collation
+	// is used when they differ.
+	collate := str.GetType().Collate
+	if _, ok := str.(*Constant); ok {
+		collate = pattern.GetType().Collate
+	}
+	if collate == charset.CollationBin {
+		return true, false
+	}
+	return false, strings.HasSuffix(collate, "_ci")
+}
+
+// likePattern is a compiled LIKE pattern.
+type likePattern struct {
+	binary bool
+	ci     bool
+
+	byteChars []byte
+	runeChars []rune
+	patTypes  []byte
+}
+
+func newLikePattern(binary, ci bool, pattern string, escape int64) *likePattern {
+	p := &likePattern{binary: binary, ci: ci}
+	if binary {
+		p.byteChars, p.patTypes = stringutil.CompilePattern(pattern, byte(escape))
+	} else {
+		p.runeChars, p.patTypes = stringutil.CompilePatternRunes(pattern, rune(escape))
+	}
+	return p
+}
+
+func (p *likePattern) doMatch(str string) bool {
+	if p.binary {
+		return stringutil.DoMatch(str, p.byteChars, p.patTypes)
+	}
+	return stringutil.DoMatchRunes(str, p.runeChars, p.patTypes, p.ci)
+}
+
+type likeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *likeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString, types.ETInt}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTp...)
+	bf.tp.Flen = 1
+	sig := newBuiltinLikeSig(bf)
+	sig.setPbCode(tipb.ScalarFuncSig_LikeSig)
+	return sig, nil
+}
+
+// newBuiltinLikeSig creates a builtinLikeSig, the pattern is compiled here
+// when both the pattern and the escape are constants.
+func newBuiltinLikeSig(bf baseBuiltinFunc) *builtinLikeSig {
+	sig := &builtinLikeSig{baseBuiltinFunc: bf}
+	sig.binary, sig.ci = PatternMatchCollation(bf.args[0], bf.args[1])
+	patCon, ok1 := bf.args[1].(*Constant)
+	escCon, ok2 := bf.args[2].(*Constant)
+	if !ok1 || !ok2 || !patCon.ConstItem() || !escCon.ConstItem() || patCon.Value.IsNull() || escCon.Value.IsNull() {
+		return sig
+	}
+	pattern, isNull, err := patCon.EvalString(bf.ctx, chunk.Row{})
+	if isNull || err != nil {
+		return sig
+	}
+	escape, isNull, err := escCon.EvalInt(bf.ctx, chunk.Row{})
+	if isNull || err != nil {
+		return sig
+	}
+	sig.pattern = newLikePattern(sig.binary, sig.ci, pattern, escape)
+	return sig
+}
+
+type builtinLikeSig struct {
+	baseBuiltinFunc
+
+	binary bool
+	ci     bool
+	// pattern is the memorized pattern when the pattern and the escape are
+	// constants, so that it is not compiled for every row.
+	pattern *likePattern
+}
+
+func (b *builtinLikeSig) Clone() builtinFunc {
+	newSig := &builtinLikeSig{binary: b.binary, ci: b.ci, pattern: b.pattern}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinLikeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-comparison-functions.html#operator_like
+func (b *builtinLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	valStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	pattern := b.pattern
+	if pattern == nil {
+		patternStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		escape, isNull, err := b.args[2].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		pattern = newLikePattern(b.binary, b.ci, patternStr, escape)
+	}
+	return boolToInt64(pattern.doMatch(valStr)), false, nil
+}
+
+type regexpFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETString)
+	bf.tp.Flen = 1
+	binary, ci := PatternMatchCollation(args[0], args[1])
+	var sig builtinFunc
+	if binary {
+		sig = newBuiltinRegexpSig(bf)
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSig)
+	} else {
+		sig = newBuiltinRegexpUTF8Sig(bf, ci)
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpUTF8Sig)
+	}
+	return sig, nil
+}
+
+// baseBuiltinRegexpSig holds the memorized regular expression of a constant
+// pattern, so that it is not compiled for every row.
+type baseBuiltinRegexpSig struct {
+	baseBuiltinFunc
+
+	// compile compiles the pattern, it respects the case sensitivity of the
+	// signature.
+	compile func(pattern string) (*regexp.Regexp, error)
+	re      *regexp.Regexp
+	// reErr is the error of compiling the constant pattern, it is reported
+	// when the function is evaluated.
+	reErr error
+}
+
+func (b *baseBuiltinRegexpSig) memorizePattern() {
+	con, ok := b.args[1].(*Constant)
+	if !ok || !con.ConstItem() {
+		return
+	}
+	pattern, isNull, err := con.EvalString(b.ctx, chunk.Row{})
+	if isNull || err != nil {
+		return
+	}
+	b.re, b.reErr = b.getRegexp(pattern)
+}
+
+func (b *baseBuiltinRegexpSig) cloneFrom(from *baseBuiltinRegexpSig) {
+	b.baseBuiltinFunc.cloneFrom(&from.baseBuiltinFunc)
+	b.compile = from.compile
+	b.re = from.re
+	b.reErr = from.reErr
+}
+
+func (b *baseBuiltinRegexpSig) getRegexp(pattern string) (*regexp.Regexp, error) {
+	if b.re != nil || b.reErr != nil {
+		return b.re, b.reErr
+	}
+	re, err := b.compile(pattern)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+func (b *baseBuiltinRegexpSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	re, err := b.getRegexp(pat)
+	if err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(re.MatchString(expr)), false, nil
+}
+
+func newBuiltinRegexpSig(bf baseBuiltinFunc) *builtinRegexpSig {
+	sig := &builtinRegexpSig{baseBuiltinRegexpSig{baseBuiltinFunc: bf, compile: regexp.Compile}}
+	sig.memorizePattern()
+	return sig
+}
+
+// builtinRegexpSig matches the bytes of binary strings case-sensitively.
+type builtinRegexpSig struct {
+	baseBuiltinRegexpSig
+}
+
+func (b *builtinRegexpSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSig{}
+	newSig.baseBuiltinRegexpSig.cloneFrom(&b.baseBuiltinRegexpSig)
+	return newSig
+}
+
+func newBuiltinRegexpUTF8Sig(bf baseBuiltinFunc, ci bool) *builtinRegexpUTF8Sig {
+	compile := regexp.Compile
+	if ci {
+		compile = func(pattern string) (*regexp.Regexp, error) {
+			return regexp.Compile("(?i)" + pattern)
+		}
+	}
+	sig := &builtinRegexpUTF8Sig{baseBuiltinRegexpSig{baseBuiltinFunc: bf, compile: compile}}
+	sig.memorizePattern()
+	return sig
+}
+
+// builtinRegexpUTF8Sig matches the characters of non-binary strings, they are
+// compared case-insensitively under a case-insensitive collation.
+type builtinRegexpUTF8Sig struct {
+	baseBuiltinRegexpSig
+}
+
+func (b *builtinRegexpUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRegexpUTF8Sig{}
+	newSig.baseBuiltinRegexpSig.cloneFrom(&b.baseBuiltinRegexpSig)
+	return newSig
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestLike(c *C) {
+	tests := []struct {
+		input   interface{}
+		pattern string
+		escape  int
+		match   interface{}
+	}{
+		{"a", "", '\\', 0},
+		{"a", "a", '\\', 1},
+		{"a", "b", '\\', 0},
+		{"aA", "Aa", '\\', 0},
+		{`aAb`, `Aa%`, '\\', 0},
+		{"aAb", "aA_", '\\', 1},
+		{"abc", "a%c", '\\', 1},
+		{"a%c", `a\%c`, '\\', 1},
+		{"abc", `a\%c`, '\\', 0},
+		{"a_c", "a|_c", '|', 1},
+		{"abc", "a|_c", '|', 0},
+		{`a\c`, `a\c`, '|', 1},
+		{"中文", "中_", '\\', 1},
+		{"中文", "_", '\\', 0},
+		{[]byte("中文"), "中___", '\\', 1},
+		{123, "1%", '\\', 1},
+		{nil, "a", '\\', nil},
+	}
+	for _, tt := range tests {
+		fc := funcs[ast.Like]
+		f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{tt.input, tt.pattern, tt.escape}))
+		c.Assert(err, IsNil)
+		r, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(r, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v like %v", tt.input, tt.pattern))
+	}
+}
+
+func (s *testEvaluatorSuite) TestLikeCaseInsensitive(c *C) {
+	ciString := func(str string) *Constant {
+		ft := types.NewFieldType(mysql.TypeVarString)
+		ft.Charset, ft.Collate = mysql.UTF8MB4Charset, "utf8mb4_general_ci"
+		return &Constant{Value: types.NewStringDatum(str), RetType: ft}
+	}
+	tests := []struct {
+		input   string
+		pattern string
+		match   int64
+	}{
+		{"aAb", "Aa%", 1},
+		{"aAb", "AAB", 1},
+		{"ÀB", "àb", 1},
+		{"aAb", "Ab_", 0},
+	}
+	for _, tt := range tests {
+		args := []Expression{ciString(tt.input), ciString(tt.pattern), &Constant{Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}}
+		f, err := funcs[ast.Like].getFunction(s.ctx, args)
+		c.Assert(err, IsNil)
+		r, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(r.GetInt64(), Equals, tt.match, Commentf("%v like %v", tt.input, tt.pattern))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexp(c *C) {
+	tests := []struct {
+		pattern string
+		input   interface{}
+		match   int64
+		err     error
+	}{
+		{"^$", "a", 0, nil},
+		{"a", "a", 1, nil},
+		{"a", "b", 0, nil},
+		{"aA", "aA", 1, nil},
+		{".", "a", 1, nil},
+		{"^.$", "ab", 0, nil},
+		{"..", "b", 0, nil},
+		{".ab", "aab", 1, nil},
+		{".*", "abcd", 1, nil},
+		{"^.$", "中", 1, nil},
+		{"AB", []byte("ab"), 0, nil},
+		{"ab", []byte("ab"), 1, nil},
+		{"(", "", 0, ErrRegexp},
+		{"(*", "", 0, ErrRegexp},
+		{"[a", "", 0, ErrRegexp},
+		{"\\", "", 0, ErrRegexp},
+	}
+	for _, tt := range tests {
+		fc := funcs[ast.Regexp]
+		f, err := fc.getFunction(s.ctx, s.primitiveValsToConstants([]interface{}{tt.input, tt.pattern}))
+		c.Assert(err, IsNil)
+		match, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err == nil {
+			c.Assert(err, IsNil)
+			c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+		} else {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinLikeCases = map[string][]vecExprBenchCase{
+	ast.Like: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: []string{"abc", "aBc", "a_c", "a%c", "中文"}},
+				&selectStringGener{candidates: []string{"a%", "_b%", "%c", "a\\_c", "a|%c", "中_"}},
+				&rangeInt64Gener{'\\', '\\' + 1},
+			}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"abc", "aBc", "a_c", "a%c", "中文"}}},
+			constants: []*Constant{nil,
+				{Value: types.NewStringDatum("a_c%"), RetType: types.NewFieldType(mysql.TypeVarString)},
+				{Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: []string{"abc", "aBc", "中文"}},
+				&selectStringGener{candidates: []string{"a%", "_b%", "中_", "中___"}},
+				&rangeInt64Gener{'\\', '\\' + 1},
+			}},
+	},
+	ast.Regexp: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: []string{"abc", "aBc", "123", "中文"}},
+				&selectStringGener{candidates: []string{"^a", "c$", "[0-9]+", "^.{2}$"}},
+			}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{&selectStringGener{candidates: []string{"abc", "aBc", "123", "中文"}}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("^a.c$"), RetType: types.NewFieldType(mysql.TypeVarString)}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: []string{"abc", "aBc", "中文"}},
+				&selectStringGener{candidates: []string{"^.{2}$", "^.{6}$", "B"}},
+			}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinLikeCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinLikeCases)
+}
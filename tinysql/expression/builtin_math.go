@@ -0,0 +1,1305 @@
+// Copyright 2013 The ql Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSES/QL-LICENSE file.
+
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	utilMath "github.com/pingcap/tidb/util/math"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &absFunctionClass{}
+	_ functionClass = &roundFunctionClass{}
+	_ functionClass = &ceilFunctionClass{}
+	_ functionClass = &floorFunctionClass{}
+	_ functionClass = &logFunctionClass{}
+	_ functionClass = &randFunctionClass{}
+	_ functionClass = &powFunctionClass{}
+	_ functionClass = &sqrtFunctionClass{}
+	_ functionClass = &expFunctionClass{}
+	_ functionClass = &crc32FunctionClass{}
+	_ functionClass = &truncateFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinAbsRealSig{}
+	_ builtinFunc = &builtinAbsIntSig{}
+	_ builtinFunc = &builtinAbsUIntSig{}
+	_ builtinFunc = &builtinAbsDecSig{}
+	_ builtinFunc = &builtinRoundRealSig{}
+	_ builtinFunc = &builtinRoundIntSig{}
+	_ builtinFunc = &builtinRoundDecSig{}
+	_ builtinFunc = &builtinRoundWithFracRealSig{}
+	_ builtinFunc = &builtinRoundWithFracIntSig{}
+	_ builtinFunc = &builtinRoundWithFracDecSig{}
+	_ builtinFunc = &builtinCeilRealSig{}
+	_ builtinFunc = &builtinCeilIntToDecSig{}
+	_ builtinFunc = &builtinCeilIntToIntSig{}
+	_ builtinFunc = &builtinCeilDecToIntSig{}
+	_ builtinFunc = &builtinCeilDecToDecSig{}
+	_ builtinFunc = &builtinFloorRealSig{}
+	_ builtinFunc = &builtinFloorIntToDecSig{}
+	_ builtinFunc = &builtinFloorIntToIntSig{}
+	_ builtinFunc = &builtinFloorDecToIntSig{}
+	_ builtinFunc = &builtinFloorDecToDecSig{}
+	_ builtinFunc = &builtinLog1ArgSig{}
+	_ builtinFunc = &builtinLog2ArgsSig{}
+	_ builtinFunc = &builtinRandSig{}
+	_ builtinFunc = &builtinRandWithSeedFirstGenSig{}
+	_ builtinFunc = &builtinPowSig{}
+	_ builtinFunc = &builtinSqrtSig{}
+	_ builtinFunc = &builtinExpSig{}
+	_ builtinFunc = &builtinCRC32Sig{}
+	_ builtinFunc = &builtinTruncateIntSig{}
+	_ builtinFunc = &builtinTruncateUintSig{}
+	_ builtinFunc = &builtinTruncateRealSig{}
+	_ builtinFunc = &builtinTruncateDecimalSig{}
+)
+
+type absFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *absFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	argFieldTp := args[0].GetType()
+	argTp := argFieldTp.EvalType()
+	if argTp != types.ETInt && argTp != types.ETDecimal {
+		argTp = types.ETReal
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTp)
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	if argTp == types.ETReal {
+		bf.tp.Flen, bf.tp.Decimal = mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeDouble)
+	} else {
+		bf.tp.Flen = argFieldTp.Flen
+		bf.tp.Decimal = argFieldTp.Decimal
+	}
+	var sig builtinFunc
+	switch argTp {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+			sig = &builtinAbsUIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_AbsUInt)
+		} else {
+			sig = &builtinAbsIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_AbsInt)
+		}
+	case types.ETDecimal:
+		sig = &builtinAbsDecSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_AbsDecimal)
+	default:
+		sig = &builtinAbsRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_AbsReal)
+	}
+	return sig, nil
+}
+
+type builtinAbsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsRealSig) Clone() builtinFunc {
+	newSig := &builtinAbsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Abs(val), false, nil
+}
+
+type builtinAbsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsIntSig) Clone() builtinFunc {
+	newSig := &builtinAbsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if val >= 0 {
+		return val, false, nil
+	}
+	if val == math.MinInt64 {
+		return 0, true, types.ErrOverflow.GenWithStackByArgs("BIGINT", fmt.Sprintf("abs(%d)", val))
+	}
+	return -val, false, nil
+}
+
+type builtinAbsUIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsUIntSig) Clone() builtinFunc {
+	newSig := &builtinAbsUIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsUIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinAbsDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsDecSig) Clone() builtinFunc {
+	newSig := &builtinAbsDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	if !val.IsNegative() {
+		*to = *val
+	} else {
+		if err = types.DecimalSub(new(types.MyDecimal), val, to); err != nil {
+			return nil, true, err
+		}
+	}
+	return to, false, nil
+}
+
+// getEvalTp4RoundAndTruncate returns the evaluation type of ROUND and TRUNCATE,
+// values other than integers and decimals are rounded as doubles.
+func getEvalTp4RoundAndTruncate(arg Expression) types.EvalType {
+	argTp := arg.GetType().EvalType()
+	if argTp != types.ETInt && argTp != types.ETDecimal {
+		argTp = types.ETReal
+	}
+	return argTp
+}
+
+// calculateDecimal4RoundAndTruncate calculates the decimal of the result of
+// ROUND and TRUNCATE, it is known only when the fraction argument is a constant.
+func calculateDecimal4RoundAndTruncate(ctx sessionctx.Context, args []Expression, retType types.EvalType) int {
+	if retType == types.ETInt || len(args) <= 1 {
+		return 0
+	}
+	secondConst, secondIsConst := args[1].(*Constant)
+	if !secondIsConst {
+		return args[0].GetType().Decimal
+	}
+	if MaybeOverOptimized4PlanCache(ctx, []Expression{secondConst}) {
+		ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+	}
+	argDec, isNull, err := secondConst.EvalInt(ctx, chunk.Row{})
+	if err != nil || isNull || argDec < 0 {
+		return 0
+	}
+	if argDec > mysql.MaxDecimalScale {
+		return mysql.MaxDecimalScale
+	}
+	return int(argDec)
+}
+
+type roundFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *roundFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := getEvalTp4RoundAndTruncate(args[0])
+	argTps := []types.EvalType{argTp}
+	if len(args) > 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTps...)
+	argFieldTp := args[0].GetType()
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+
+	bf.tp.Flen = argFieldTp.Flen
+	bf.tp.Decimal = calculateDecimal4RoundAndTruncate(ctx, args, argTp)
+
+	var sig builtinFunc
+	if len(args) > 1 {
+		switch argTp {
+		case types.ETInt:
+			sig = &builtinRoundWithFracIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracInt)
+		case types.ETDecimal:
+			sig = &builtinRoundWithFracDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracDec)
+		default:
+			sig = &builtinRoundWithFracRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracReal)
+		}
+	} else {
+		switch argTp {
+		case types.ETInt:
+			sig = &builtinRoundIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundInt)
+		case types.ETDecimal:
+			sig = &builtinRoundDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundDec)
+		default:
+			sig = &builtinRoundRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_RoundReal)
+		}
+	}
+	return sig, nil
+}
+
+type builtinRoundRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.Round(val, 0), false, nil
+}
+
+type builtinRoundIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinRoundDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundDecSig) Clone() builtinFunc {
+	newSig := &builtinRoundDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ROUND(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	if err = val.Round(to, 0, types.ModeHalfEven); err != nil {
+		return nil, true, err
+	}
+	return to, false, nil
+}
+
+type builtinRoundWithFracRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(value, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.Round(val, int(frac)), false, nil
+}
+
+type builtinRoundWithFracIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(value, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(types.Round(float64(val), int(frac))), false, nil
+}
+
+type builtinRoundWithFracDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracDecSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ROUND(value, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	frac, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	if err = val.Round(to, mathutil.Min(int(frac), b.tp.Decimal), types.ModeHalfEven); err != nil {
+		return nil, true, err
+	}
+	return to, false, nil
+}
+
+// getEvalTp4FloorAndCeil gets the types.EvalType of FLOOR and CEIL.
+func getEvalTp4FloorAndCeil(arg Expression) (retTp, argTp types.EvalType) {
+	fieldTp := arg.GetType()
+	retTp, argTp = types.ETInt, fieldTp.EvalType()
+	switch argTp {
+	case types.ETInt:
+		if fieldTp.Tp == mysql.TypeLonglong {
+			retTp = types.ETDecimal
+		}
+	case types.ETDecimal:
+		if fieldTp.Flen-fieldTp.Decimal > mysql.MaxIntWidth-2 { // len(math.MaxInt64) - 1
+			retTp = types.ETDecimal
+		}
+	default:
+		retTp, argTp = types.ETReal, types.ETReal
+	}
+	return retTp, argTp
+}
+
+// setFlag4FloorAndCeil sets return flag of FLOOR and CEIL.
+func setFlag4FloorAndCeil(tp *types.FieldType, arg Expression) {
+	fieldTp := arg.GetType()
+	if (fieldTp.Tp == mysql.TypeLong || fieldTp.Tp == mysql.TypeNewDecimal) && mysql.HasUnsignedFlag(fieldTp.Flag) {
+		tp.Flag |= mysql.UnsignedFlag
+	}
+}
+
+type ceilFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *ceilFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	retTp, argTp := getEvalTp4FloorAndCeil(args[0])
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retTp, argTp)
+	setFlag4FloorAndCeil(bf.tp, args[0])
+	bf.tp.Flen, bf.tp.Decimal = args[0].GetType().Flen, 0
+
+	switch argTp {
+	case types.ETInt:
+		if retTp == types.ETInt {
+			sig = &builtinCeilIntToIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_CeilIntToInt)
+		} else {
+			sig = &builtinCeilIntToDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_CeilIntToDec)
+		}
+	case types.ETDecimal:
+		if retTp == types.ETInt {
+			sig = &builtinCeilDecToIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_CeilDecToInt)
+		} else {
+			sig = &builtinCeilDecToDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_CeilDecToDec)
+		}
+	default:
+		sig = &builtinCeilRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_CeilReal)
+	}
+	return sig, nil
+}
+
+type builtinCeilRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilRealSig) Clone() builtinFunc {
+	newSig := &builtinCeilRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinCeilRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Ceil(val), false, nil
+}
+
+type builtinCeilIntToIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilIntToIntSig) Clone() builtinFunc {
+	newSig := &builtinCeilIntToIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCeilIntToIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilIntToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinCeilIntToDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilIntToDecSig) Clone() builtinFunc {
+	newSig := &builtinCeilIntToDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinCeilIntToDecSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilIntToDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return intToDecimal(val, mysql.HasUnsignedFlag(b.args[0].GetType().Flag)), false, nil
+}
+
+// intToDecimal converts an integer to a decimal according to its signedness.
+func intToDecimal(val int64, unsigned bool) *types.MyDecimal {
+	if unsigned || val >= 0 {
+		return types.NewDecFromUint(uint64(val))
+	}
+	return types.NewDecFromInt(val)
+}
+
+type builtinCeilDecToIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilDecToIntSig) Clone() builtinFunc {
+	newSig := &builtinCeilDecToIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinCeilDecToIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilDecToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, err := ceilDecimalToInt(val)
+	return res, err != nil, err
+}
+
+// ceilDecimalToInt converts a decimal to the smallest integer not less than it.
+func ceilDecimalToInt(val *types.MyDecimal) (int64, error) {
+	// err here will only be ErrOverFlow(will never happen) or ErrTruncate(can be ignored).
+	res, err := val.ToInt()
+	if err == types.ErrTruncated {
+		err = nil
+		if !val.IsNegative() {
+			res = res + 1
+		}
+	}
+	return res, err
+}
+
+type builtinCeilDecToDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCeilDecToDecSig) Clone() builtinFunc {
+	newSig := &builtinCeilDecToDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinCeilDecToDecSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ceil
+func (b *builtinCeilDecToDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	res := new(types.MyDecimal)
+	err = ceilOrFloorDecimal(val, res, true)
+	return res, err != nil, err
+}
+
+// ceilOrFloorDecimal stores the CEIL or FLOOR of val into res.
+func ceilOrFloorDecimal(val, res *types.MyDecimal, isCeil bool) error {
+	if err := val.Round(res, 0, types.ModeTruncate); err != nil {
+		return err
+	}
+	// Truncating already rounds towards zero, so only the values on the other
+	// side of zero need adjustment when they have a fraction.
+	if val.IsNegative() == isCeil || res.Compare(val) == 0 {
+		return nil
+	}
+	delta := int64(1)
+	if !isCeil {
+		delta = -1
+	}
+	return types.DecimalAdd(res, types.NewDecFromInt(delta), res)
+}
+
+type floorFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *floorFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	retTp, argTp := getEvalTp4FloorAndCeil(args[0])
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retTp, argTp)
+	setFlag4FloorAndCeil(bf.tp, args[0])
+	bf.tp.Flen, bf.tp.Decimal = args[0].GetType().Flen, 0
+	switch argTp {
+	case types.ETInt:
+		if retTp == types.ETInt {
+			sig = &builtinFloorIntToIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_FloorIntToInt)
+		} else {
+			sig = &builtinFloorIntToDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_FloorIntToDec)
+		}
+	case types.ETDecimal:
+		if retTp == types.ETInt {
+			sig = &builtinFloorDecToIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_FloorDecToInt)
+		} else {
+			sig = &builtinFloorDecToDecSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_FloorDecToDec)
+		}
+	default:
+		sig = &builtinFloorRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FloorReal)
+	}
+	return sig, nil
+}
+
+type builtinFloorRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorRealSig) Clone() builtinFunc {
+	newSig := &builtinFloorRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinFloorRealSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Floor(val), false, nil
+}
+
+type builtinFloorIntToIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorIntToIntSig) Clone() builtinFunc {
+	newSig := &builtinFloorIntToIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinFloorIntToIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorIntToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinFloorIntToDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorIntToDecSig) Clone() builtinFunc {
+	newSig := &builtinFloorIntToDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinFloorIntToDecSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorIntToDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return intToDecimal(val, mysql.HasUnsignedFlag(b.args[0].GetType().Flag)), false, nil
+}
+
+type builtinFloorDecToIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorDecToIntSig) Clone() builtinFunc {
+	newSig := &builtinFloorDecToIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinFloorDecToIntSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorDecToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, err := floorDecimalToInt(val)
+	return res, err != nil, err
+}
+
+// floorDecimalToInt converts a decimal to the largest integer not greater than it.
+func floorDecimalToInt(val *types.MyDecimal) (int64, error) {
+	// err here will only be ErrOverFlow(will never happen) or ErrTruncate(can be ignored).
+	res, err := val.ToInt()
+	if err == types.ErrTruncated {
+		err = nil
+		if val.IsNegative() {
+			res--
+		}
+	}
+	return res, err
+}
+
+type builtinFloorDecToDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorDecToDecSig) Clone() builtinFunc {
+	newSig := &builtinFloorDecToDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a builtinFloorDecToDecSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorDecToDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	res := new(types.MyDecimal)
+	err = ceilOrFloorDecimal(val, res, false)
+	return res, err != nil, err
+}
+
+type logFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *logFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	var (
+		sig     builtinFunc
+		bf      baseBuiltinFunc
+		argsLen = len(args)
+	)
+
+	if argsLen == 1 {
+		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
+	} else {
+		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+	}
+
+	if argsLen == 1 {
+		sig = &builtinLog1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Log1Arg)
+	} else {
+		sig = &builtinLog2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Log2Args)
+	}
+
+	return sig, nil
+}
+
+type builtinLog1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog1ArgSig) Clone() builtinFunc {
+	newSig := &builtinLog1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog1ArgSig, corresponding to log(x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
+func (b *builtinLog1ArgSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if val <= 0 {
+		return 0, true, nil
+	}
+	return math.Log(val), false, nil
+}
+
+type builtinLog2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLog2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog2ArgsSig, corresponding to log(b, x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
+func (b *builtinLog2ArgsSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val1, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	val2, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	if val1 <= 0 || val1 == 1 || val2 <= 0 {
+		return 0, true, nil
+	}
+
+	return math.Log(val2) / math.Log(val1), false, nil
+}
+
+type randFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *randFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	var argTps []types.EvalType
+	if len(args) > 0 {
+		argTps = []types.EvalType{types.ETInt}
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argTps...)
+	bf.tp.Flen, bf.tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	if len(args) == 0 {
+		sig = &builtinRandSig{bf, &sync.Mutex{}, utilMath.NewWithTime()}
+		sig.setPbCode(tipb.ScalarFuncSig_Rand)
+	} else if _, isConstant := args[0].(*Constant); isConstant {
+		// According to MySQL manual:
+		// If an integer argument N is specified, it is used as the seed value:
+		// With a constant initializer argument, the seed is initialized once
+		// when the statement is prepared, prior to execution.
+		if MaybeOverOptimized4PlanCache(ctx, args) {
+			ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+		}
+		seed, isNull, err := args[0].EvalInt(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		if isNull {
+			seed = 0
+		}
+		sig = &builtinRandSig{bf, &sync.Mutex{}, utilMath.NewWithSeed(seed)}
+		sig.setPbCode(tipb.ScalarFuncSig_Rand)
+	} else {
+		sig = &builtinRandWithSeedFirstGenSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RandWithSeedFirstGen)
+	}
+	return sig, nil
+}
+
+type builtinRandSig struct {
+	baseBuiltinFunc
+	mu       *sync.Mutex
+	mysqlRng *utilMath.MysqlRng
+}
+
+func (b *builtinRandSig) Clone() builtinFunc {
+	newSig := &builtinRandSig{mysqlRng: b.mysqlRng, mu: b.mu}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals RAND().
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_rand
+func (b *builtinRandSig) evalReal(row chunk.Row) (float64, bool, error) {
+	b.mu.Lock()
+	res := b.mysqlRng.Gen()
+	b.mu.Unlock()
+	return res, false, nil
+}
+
+type builtinRandWithSeedFirstGenSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRandWithSeedFirstGenSig) Clone() builtinFunc {
+	newSig := &builtinRandWithSeedFirstGenSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals RAND(N), the seed is not a constant so every row generates
+// the first value of its own sequence.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_rand
+func (b *builtinRandWithSeedFirstGenSig) evalReal(row chunk.Row) (float64, bool, error) {
+	seed, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	// b.args[0] is promised to be a non-constant(such as a column name) in
+	// builtinRandWithSeedFirstGenSig, the seed is initialized with the value for each
+	// invocation of RAND().
+	if isNull {
+		seed = 0
+	}
+	return utilMath.NewWithSeed(seed).Gen(), false, nil
+}
+
+type powFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *powFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+	sig := &builtinPowSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Pow)
+	return sig, nil
+}
+
+type builtinPowSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinPowSig) Clone() builtinFunc {
+	newSig := &builtinPowSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals POW(x, y).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_pow
+func (b *builtinPowSig) evalReal(row chunk.Row) (float64, bool, error) {
+	x, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	y, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	power, err := pow(x, y)
+	return power, err != nil, err
+}
+
+func pow(x, y float64) (float64, error) {
+	power := math.Pow(x, y)
+	if math.IsInf(power, -1) || math.IsInf(power, 1) || math.IsNaN(power) {
+		return 0, types.ErrOverflow.GenWithStackByArgs("DOUBLE", fmt.Sprintf("pow(%s, %s)", strconv.FormatFloat(x, 'f', -1, 64), strconv.FormatFloat(y, 'f', -1, 64)))
+	}
+	return power, nil
+}
+
+type sqrtFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sqrtFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
+	sig := &builtinSqrtSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Sqrt)
+	return sig, nil
+}
+
+type builtinSqrtSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSqrtSig) Clone() builtinFunc {
+	newSig := &builtinSqrtSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a SQRT(x).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_sqrt
+func (b *builtinSqrtSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if val < 0 {
+		return 0, true, nil
+	}
+	return math.Sqrt(val), false, nil
+}
+
+type expFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *expFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
+	sig := &builtinExpSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Exp)
+	return sig, nil
+}
+
+type builtinExpSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExpSig) Clone() builtinFunc {
+	newSig := &builtinExpSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinExpSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_exp
+func (b *builtinExpSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	exp, err := exp(val)
+	return exp, err != nil, err
+}
+
+func exp(val float64) (float64, error) {
+	exp := math.Exp(val)
+	if math.IsInf(exp, 0) || math.IsNaN(exp) {
+		return 0, types.ErrOverflow.GenWithStackByArgs("DOUBLE", fmt.Sprintf("exp(%s)", strconv.FormatFloat(val, 'f', -1, 64)))
+	}
+	return exp, nil
+}
+
+type crc32FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *crc32FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString)
+	bf.tp.Flen = 10
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinCRC32Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_CRC32)
+	return sig, nil
+}
+
+type builtinCRC32Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCRC32Sig) Clone() builtinFunc {
+	newSig := &builtinCRC32Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a CRC32(expr).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_crc32
+func (b *builtinCRC32Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	x, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	r := crc32.ChecksumIEEE([]byte(x))
+	return int64(r), false, nil
+}
+
+type truncateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *truncateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	argTp := getEvalTp4RoundAndTruncate(args[0])
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTp, types.ETInt)
+
+	bf.tp.Decimal = calculateDecimal4RoundAndTruncate(ctx, args, argTp)
+	bf.tp.Flen = args[0].GetType().Flen - args[0].GetType().Decimal + bf.tp.Decimal
+	bf.tp.Flag |= args[0].GetType().Flag
+
+	var sig builtinFunc
+	switch argTp {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(args[0].GetType().Flag) {
+			sig = &builtinTruncateUintSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_TruncateUint)
+		} else {
+			sig = &builtinTruncateIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_TruncateInt)
+		}
+	case types.ETDecimal:
+		sig = &builtinTruncateDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateDecimal)
+	default:
+		sig = &builtinTruncateRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateReal)
+	}
+
+	return sig, nil
+}
+
+type builtinTruncateDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateDecimalSig) Clone() builtinFunc {
+	newSig := &builtinTruncateDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	x, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+
+	result := new(types.MyDecimal)
+	if err := x.Round(result, mathutil.Min(int(d), b.tp.Decimal), types.ModeTruncate); err != nil {
+		return nil, true, err
+	}
+	return result, false, nil
+}
+
+type builtinTruncateRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateRealSig) Clone() builtinFunc {
+	newSig := &builtinTruncateRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	x, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+
+	return types.Truncate(x, int(d)), false, nil
+}
+
+type builtinTruncateIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateIntSig) Clone() builtinFunc {
+	newSig := &builtinTruncateIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	x, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		return x, false, nil
+	}
+	return truncateInt(x, d), false, nil
+}
+
+// truncateInt truncates x to d digits before the decimal point when d is negative.
+func truncateInt(x, d int64) int64 {
+	if d >= 0 {
+		return x
+	}
+	shift := int64(1)
+	for i := int64(0); i < -d; i++ {
+		if shift > math.MaxInt64/10 {
+			// The shift is larger than any int64 value.
+			return 0
+		}
+		shift *= 10
+	}
+	return x / shift * shift
+}
+
+type builtinTruncateUintSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateUintSig) Clone() builtinFunc {
+	newSig := &builtinTruncateUintSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a TRUNCATE(X,D).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateUintSig) evalInt(row chunk.Row) (int64, bool, error) {
+	x, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		return x, false, nil
+	}
+	return truncateUint(x, d), false, nil
+}
+
+// truncateUint is like truncateInt but treats x as an unsigned integer.
+func truncateUint(x, d int64) int64 {
+	if d >= 0 {
+		return x
+	}
+	shift := uint64(1)
+	for i := int64(0); i < -d; i++ {
+		if shift > math.MaxUint64/10 {
+			// The shift is larger than any uint64 value.
+			return 0
+		}
+		shift *= 10
+	}
+	return int64(uint64(x) / shift * shift)
+}
@@ -0,0 +1,184 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+// mathFuncTestCase is a test case of the math functions, err is true if the
+// evaluation is expected to fail.
+type mathFuncTestCase struct {
+	args   []interface{}
+	expect interface{}
+	err    bool
+}
+
+func (s *testEvaluatorSuite) checkMathFunc(c *C, funcName string, cases []mathFuncTestCase) {
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.err {
+			c.Assert(err, NotNil, Commentf("%s%v", funcName, t.args))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("%s%v", funcName, t.args))
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect), Commentf("%s%v", funcName, t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestAbs(c *C) {
+	s.checkMathFunc(c, ast.Abs, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{int64(-1)}, int64(1), false},
+		{[]interface{}{uint64(math.MaxUint64)}, uint64(math.MaxUint64), false},
+		{[]interface{}{-1.23}, 1.23, false},
+		{[]interface{}{types.NewDecFromStringForTest("-1.23")}, types.NewDecFromStringForTest("1.23"), false},
+		{[]interface{}{"-1.5"}, 1.5, false},
+		{[]interface{}{int64(math.MinInt64)}, nil, true},
+	})
+}
+
+func (s *testEvaluatorSuite) TestCeilAndFloor(c *C) {
+	s.checkMathFunc(c, ast.Ceil, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{int64(-3)}, types.NewDecFromInt(-3), false},
+		{[]interface{}{uint64(math.MaxUint64)}, types.NewDecFromUint(math.MaxUint64), false},
+		{[]interface{}{1.2}, float64(2), false},
+		{[]interface{}{-1.2}, float64(-1), false},
+		{[]interface{}{types.NewDecFromStringForTest("1.2")}, types.NewDecFromInt(2), false},
+		{[]interface{}{types.NewDecFromStringForTest("-1.2")}, types.NewDecFromInt(-1), false},
+		{[]interface{}{types.NewDecFromStringForTest("3")}, types.NewDecFromInt(3), false},
+	})
+	s.checkMathFunc(c, ast.Floor, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{int64(-3)}, types.NewDecFromInt(-3), false},
+		{[]interface{}{1.2}, float64(1), false},
+		{[]interface{}{-1.2}, float64(-2), false},
+		{[]interface{}{types.NewDecFromStringForTest("1.2")}, types.NewDecFromInt(1), false},
+		{[]interface{}{types.NewDecFromStringForTest("-1.2")}, types.NewDecFromInt(-2), false},
+		{[]interface{}{types.NewDecFromStringForTest("-3")}, types.NewDecFromInt(-3), false},
+	})
+
+	for _, t := range []struct {
+		arg   *types.MyDecimal
+		ceil  int64
+		floor int64
+	}{
+		{types.NewDecFromStringForTest("1.5"), 2, 1},
+		{types.NewDecFromStringForTest("-1.5"), -1, -2},
+		{types.NewDecFromStringForTest("7"), 7, 7},
+	} {
+		res, err := ceilDecimalToInt(t.arg)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, t.ceil)
+		res, err = floorDecimalToInt(t.arg)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, t.floor)
+	}
+}
+
+func (s *testEvaluatorSuite) TestRound(c *C) {
+	s.checkMathFunc(c, ast.Round, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{int64(-3)}, int64(-3), false},
+		{[]interface{}{1.5}, float64(2), false},
+		{[]interface{}{-1.5}, float64(-2), false},
+		{[]interface{}{types.NewDecFromStringForTest("1.5")}, types.NewDecFromInt(2), false},
+		{[]interface{}{1.298, int64(1)}, 1.3, false},
+		{[]interface{}{1.298, nil}, nil, false},
+		{[]interface{}{int64(23298), int64(-2)}, int64(23300), false},
+		{[]interface{}{types.NewDecFromStringForTest("1.298"), int64(2)}, types.NewDecFromStringForTest("1.30"), false},
+		{[]interface{}{types.NewDecFromStringForTest("-1.298"), int64(0)}, types.NewDecFromInt(-1), false},
+	})
+}
+
+func (s *testEvaluatorSuite) TestTruncate(c *C) {
+	s.checkMathFunc(c, ast.Truncate, []mathFuncTestCase{
+		{[]interface{}{nil, int64(1)}, nil, false},
+		{[]interface{}{1.223, nil}, nil, false},
+		{[]interface{}{1.223, int64(1)}, 1.2, false},
+		{[]interface{}{-1.999, int64(0)}, float64(-1), false},
+		{[]interface{}{int64(122), int64(-2)}, int64(100), false},
+		{[]interface{}{int64(-122), int64(-2)}, int64(-100), false},
+		{[]interface{}{int64(122), int64(2)}, int64(122), false},
+		{[]interface{}{int64(math.MaxInt64), int64(-19)}, int64(0), false},
+		{[]interface{}{uint64(math.MaxUint64), int64(-19)}, uint64(10000000000000000000), false},
+		{[]interface{}{uint64(math.MaxUint64), int64(-20)}, uint64(0), false},
+		{[]interface{}{types.NewDecFromStringForTest("1.223"), int64(1)}, types.NewDecFromStringForTest("1.2"), false},
+		{[]interface{}{types.NewDecFromStringForTest("-1.999"), int64(2)}, types.NewDecFromStringForTest("-1.99"), false},
+	})
+}
+
+func (s *testEvaluatorSuite) TestPowSqrtExpLog(c *C) {
+	s.checkMathFunc(c, ast.Pow, []mathFuncTestCase{
+		{[]interface{}{nil, 1}, nil, false},
+		{[]interface{}{2, 10}, float64(1024), false},
+		{[]interface{}{"2", -1}, 0.5, false},
+		{[]interface{}{10, 1000}, nil, true},
+	})
+	s.checkMathFunc(c, ast.Sqrt, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{int64(16)}, float64(4), false},
+		{[]interface{}{-1.0}, nil, false},
+	})
+	s.checkMathFunc(c, ast.Exp, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{int64(0)}, float64(1), false},
+		{[]interface{}{int64(1000)}, nil, true},
+	})
+	s.checkMathFunc(c, ast.Log, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{math.E}, float64(1), false},
+		{[]interface{}{int64(0)}, nil, false},
+		{[]interface{}{int64(-1)}, nil, false},
+		{[]interface{}{int64(2), int64(8)}, float64(3), false},
+		{[]interface{}{int64(1), int64(8)}, nil, false},
+		{[]interface{}{int64(2), int64(0)}, nil, false},
+		{[]interface{}{nil, int64(8)}, nil, false},
+	})
+}
+
+func (s *testEvaluatorSuite) TestRandAndCRC32(c *C) {
+	s.checkMathFunc(c, ast.Rand, []mathFuncTestCase{
+		{[]interface{}{int64(0)}, 0.15522042769493574, false},
+		{[]interface{}{nil}, 0.15522042769493574, false},
+	})
+	f, err := newFunctionForTest(s.ctx, ast.Rand)
+	c.Assert(err, IsNil)
+	for i := 0; i < 10; i++ {
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetFloat64() >= 0 && d.GetFloat64() < 1, IsTrue)
+	}
+
+	s.checkMathFunc(c, ast.CRC32, []mathFuncTestCase{
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{""}, uint64(0), false},
+		{[]interface{}{"MySQL"}, uint64(3259397556), false},
+		{[]interface{}{int64(-1)}, uint64(808273962), false},
+	})
+	f, err = newFunctionForTest(s.ctx, ast.CRC32, s.primitiveValsToConstants([]interface{}{"a"})...)
+	c.Assert(err, IsNil)
+	c.Assert(mysql.HasUnsignedFlag(f.GetType().Flag), IsTrue)
+	c.Assert(f.GetType().Flen, Equals, 10)
+}
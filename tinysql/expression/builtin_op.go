@@ -31,17 +31,35 @@ var (
 	_ functionClass = &unaryMinusFunctionClass{}
 	_ functionClass = &isNullFunctionClass{}
 	_ functionClass = &unaryNotFunctionClass{}
+	_ functionClass = &bitAndFunctionClass{}
+	_ functionClass = &bitOrFunctionClass{}
+	_ functionClass = &bitXorFunctionClass{}
+	_ functionClass = &bitNegFunctionClass{}
+	_ functionClass = &leftShiftFunctionClass{}
+	_ functionClass = &rightShiftFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinLogicAndSig{}
 	_ builtinFunc = &builtinLogicOrSig{}
 	_ builtinFunc = &builtinUnaryMinusIntSig{}
+	_ builtinFunc = &builtinUnaryMinusDecimalSig{}
 	_ builtinFunc = &builtinIntIsNullSig{}
 	_ builtinFunc = &builtinRealIsNullSig{}
+	_ builtinFunc = &builtinDecimalIsNullSig{}
 	_ builtinFunc = &builtinStringIsNullSig{}
+	_ builtinFunc = &builtinTimeIsNullSig{}
+	_ builtinFunc = &builtinDurationIsNullSig{}
+	_ builtinFunc = &builtinJSONIsNullSig{}
 	_ builtinFunc = &builtinUnaryNotRealSig{}
+	_ builtinFunc = &builtinUnaryNotDecimalSig{}
 	_ builtinFunc = &builtinUnaryNotIntSig{}
+	_ builtinFunc = &builtinBitAndSig{}
+	_ builtinFunc = &builtinBitOrSig{}
+	_ builtinFunc = &builtinBitXorSig{}
+	_ builtinFunc = &builtinBitNegSig{}
+	_ builtinFunc = &builtinLeftShiftSig{}
+	_ builtinFunc = &builtinRightShiftSig{}
 )
 
 type logicAndFunctionClass struct {
@@ -144,7 +162,9 @@ func (c *unaryNotFunctionClass) getFunction(ctx sessionctx.Context, args []Expre
 	}
 
 	argTp := args[0].GetType().EvalType()
-	if argTp == types.ETString {
+	if argTp == types.ETTimestamp || argTp == types.ETDatetime || argTp == types.ETDuration {
+		argTp = types.ETInt
+	} else if argTp == types.ETString {
 		argTp = types.ETReal
 	}
 
@@ -156,6 +176,9 @@ func (c *unaryNotFunctionClass) getFunction(ctx sessionctx.Context, args []Expre
 	case types.ETReal:
 		sig = &builtinUnaryNotRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_UnaryNotReal)
+	case types.ETDecimal:
+		sig = &builtinUnaryNotDecimalSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnaryNotDecimal)
 	case types.ETInt:
 		sig = &builtinUnaryNotIntSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_UnaryNotInt)
@@ -186,6 +209,27 @@ func (b *builtinUnaryNotRealSig) evalInt(row chunk.Row) (int64, bool, error) {
 	return 0, false, nil
 }
 
+type builtinUnaryNotDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryNotDecimalSig) Clone() builtinFunc {
+	newSig := &builtinUnaryNotDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryNotDecimalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if arg.IsZero() {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
 type builtinUnaryNotIntSig struct {
 	baseBuiltinFunc
 }
@@ -215,11 +259,26 @@ type unaryMinusFunctionClass struct {
 // typerInfer will infers the return type as types.ETDecimal, not types.ETInt.
 func (c *unaryMinusFunctionClass) typeInfer(argExpr Expression) (types.EvalType, bool) {
 	tp := argExpr.GetType().EvalType()
-	if tp != types.ETInt {
+	if tp != types.ETInt && tp != types.ETDecimal {
 		tp = types.ETReal
 	}
 
-	return tp, false
+	overflow := false
+	if arg, ok := argExpr.(*Constant); ok && tp == types.ETInt && c.handleIntOverflow(arg) {
+		tp = types.ETDecimal
+		overflow = true
+	}
+	return tp, overflow
+}
+
+// handleIntOverflow reports whether negating the int constant overflows BIGINT.
+func (c *unaryMinusFunctionClass) handleIntOverflow(arg *Constant) (overflow bool) {
+	if mysql.HasUnsignedFlag(arg.GetType().Flag) {
+		// -math.MinInt64 is 9223372036854775808, so negating anything larger overflows.
+		return arg.Value.GetUint64() > uint64(-math.MinInt64)
+	}
+	// The absolute value of math.MinInt64 is larger than math.MaxInt64.
+	return arg.Value.GetInt64() == math.MinInt64
 }
 
 func (c *unaryMinusFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
@@ -229,26 +288,45 @@ func (c *unaryMinusFunctionClass) getFunction(ctx sessionctx.Context, args []Exp
 
 	argExpr, argExprTp := args[0], args[0].GetType()
 	_, intOverflow := c.typeInfer(argExpr)
+	if argExprTp.EvalType() == types.ETInt && MaybeOverOptimized4PlanCache(ctx, args) {
+		// The return type depends on whether the parameter overflows.
+		ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+	}
 
 	var bf baseBuiltinFunc
 	switch argExprTp.EvalType() {
 	case types.ETInt:
 		if intOverflow {
-			panic("overflows int")
+			bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+			sig = &builtinUnaryMinusDecimalSig{bf, true}
+			sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusDecimal)
 		} else {
 			bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt)
 			sig = &builtinUnaryMinusIntSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusInt)
 		}
 		bf.tp.Decimal = 0
+	case types.ETDecimal:
+		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+		bf.tp.Decimal = argExprTp.Decimal
+		sig = &builtinUnaryMinusDecimalSig{bf, false}
+		sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusDecimal)
 	case types.ETReal:
 		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
 		sig = &builtinUnaryMinusRealSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusReal)
 	default:
-		bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
-		sig = &builtinUnaryMinusRealSig{bf}
-		sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusReal)
+		tp := argExpr.GetType().Tp
+		if types.IsTypeTemporal(tp) {
+			bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+			bf.tp.Decimal = argExprTp.Decimal
+			sig = &builtinUnaryMinusDecimalSig{bf, false}
+			sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusDecimal)
+		} else {
+			bf = newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
+			sig = &builtinUnaryMinusRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_UnaryMinusReal)
+		}
 	}
 	bf.tp.Flen = argExprTp.Flen + 1
 	return sig, err
@@ -284,6 +362,26 @@ func (b *builtinUnaryMinusIntSig) evalInt(row chunk.Row) (res int64, isNull bool
 	return -val, false, nil
 }
 
+type builtinUnaryMinusDecimalSig struct {
+	baseBuiltinFunc
+
+	constantArgOverflow bool
+}
+
+func (b *builtinUnaryMinusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinUnaryMinusDecimalSig{constantArgOverflow: b.constantArgOverflow}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryMinusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	dec, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if err != nil || isNull {
+		return dec, isNull, err
+	}
+	return types.DecimalNeg(dec), false, nil
+}
+
 type builtinUnaryMinusRealSig struct {
 	baseBuiltinFunc
 }
@@ -318,9 +416,21 @@ func (c *isNullFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	case types.ETReal:
 		sig = &builtinRealIsNullSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_RealIsNull)
+	case types.ETDecimal:
+		sig = &builtinDecimalIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_DecimalIsNull)
 	case types.ETString:
 		sig = &builtinStringIsNullSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_StringIsNull)
+	case types.ETDatetime, types.ETTimestamp:
+		sig = &builtinTimeIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TimeIsNull)
+	case types.ETDuration:
+		sig = &builtinDurationIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_DurationIsNull)
+	case types.ETJson:
+		sig = &builtinJSONIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_JsonIsNull)
 	default:
 		panic("unexpected types.EvalType")
 	}
@@ -367,6 +477,66 @@ func (b *builtinRealIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
 	return evalIsNull(isNull, err)
 }
 
+type builtinDecimalIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDecimalIsNullSig) Clone() builtinFunc {
+	newSig := &builtinDecimalIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinDecimalIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
+type builtinTimeIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTimeIsNullSig) Clone() builtinFunc {
+	newSig := &builtinTimeIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinTimeIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
+type builtinDurationIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDurationIsNullSig) Clone() builtinFunc {
+	newSig := &builtinDurationIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinDurationIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
+type builtinJSONIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONIsNullSig) Clone() builtinFunc {
+	newSig := &builtinJSONIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
+
 type builtinStringIsNullSig struct {
 	baseBuiltinFunc
 }
@@ -381,3 +551,225 @@ func (b *builtinStringIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
 	_, isNull, err := b.args[0].EvalString(b.ctx, row)
 	return evalIsNull(isNull, err)
 }
+
+type bitAndFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitAndFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	sig := &builtinBitAndSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_BitAndSig)
+	sig.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinBitAndSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitAndSig) Clone() builtinFunc {
+	newSig := &builtinBitAndSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitAndSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	arg1, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return arg0 & arg1, false, nil
+}
+
+type bitOrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitOrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	sig := &builtinBitOrSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_BitOrSig)
+	sig.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinBitOrSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitOrSig) Clone() builtinFunc {
+	newSig := &builtinBitOrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitOrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	arg1, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return arg0 | arg1, false, nil
+}
+
+type bitXorFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitXorFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	sig := &builtinBitXorSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_BitXorSig)
+	sig.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinBitXorSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitXorSig) Clone() builtinFunc {
+	newSig := &builtinBitXorSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitXorSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	arg1, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return arg0 ^ arg1, false, nil
+}
+
+type leftShiftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leftShiftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	sig := &builtinLeftShiftSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LeftShift)
+	sig.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinLeftShiftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftShiftSig) Clone() builtinFunc {
+	newSig := &builtinLeftShiftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinLeftShiftSig, the shift is done on the unsigned value
+// of the operands.
+func (b *builtinLeftShiftSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	arg1, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(uint64(arg0) << uint64(arg1)), false, nil
+}
+
+type rightShiftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rightShiftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	sig := &builtinRightShiftSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RightShift)
+	sig.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinRightShiftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightShiftSig) Clone() builtinFunc {
+	newSig := &builtinRightShiftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinRightShiftSig, the shift is done on the unsigned value
+// of the operands.
+func (b *builtinRightShiftSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	arg1, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(uint64(arg0) >> uint64(arg1)), false, nil
+}
+
+type bitNegFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitNegFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt)
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinBitNegSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_BitNegSig)
+	return sig, nil
+}
+
+type builtinBitNegSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitNegSig) Clone() builtinFunc {
+	newSig := &builtinBitNegSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitNegSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return ^arg, false, nil
+}
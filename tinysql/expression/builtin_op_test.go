@@ -0,0 +1,64 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestBitOperators(c *C) {
+	tests := []struct {
+		funcName string
+		args     []interface{}
+		expected interface{}
+	}{
+		{ast.And, []interface{}{123, 321}, uint64(65)},
+		{ast.And, []interface{}{-123, 321}, uint64(257)},
+		{ast.And, []interface{}{nil, 1}, nil},
+		{ast.Or, []interface{}{123, 321}, uint64(379)},
+		{ast.Or, []interface{}{-123, 321}, uint64(18446744073709551557)},
+		{ast.Or, []interface{}{nil, 1}, nil},
+		{ast.Xor, []interface{}{123, 321}, uint64(314)},
+		{ast.Xor, []interface{}{-123, 321}, uint64(18446744073709551300)},
+		{ast.Xor, []interface{}{nil, 1}, nil},
+		{ast.LeftShift, []interface{}{123, 2}, uint64(492)},
+		{ast.LeftShift, []interface{}{-123, 2}, uint64(18446744073709551124)},
+		{ast.LeftShift, []interface{}{1, 64}, uint64(0)},
+		{ast.LeftShift, []interface{}{nil, 1}, nil},
+		{ast.RightShift, []interface{}{123, 2}, uint64(30)},
+		{ast.RightShift, []interface{}{-123, 2}, uint64(4611686018427387873)},
+		{ast.RightShift, []interface{}{1, 64}, uint64(0)},
+		{ast.RightShift, []interface{}{nil, 1}, nil},
+		{ast.BitNeg, []interface{}{123}, uint64(18446744073709551492)},
+		{ast.BitNeg, []interface{}{-123}, uint64(122)},
+		{ast.BitNeg, []interface{}{uint64(math.MaxUint64)}, uint64(0)},
+		{ast.BitNeg, []interface{}{nil}, nil},
+	}
+
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, t.funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		c.Assert(mysql.HasUnsignedFlag(f.GetType().Flag), IsTrue)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected), Commentf("%s%v", t.funcName, t.args))
+	}
+}
@@ -85,6 +85,26 @@ func (b *builtinUnaryMinusRealSig) vecEvalReal(input *chunk.Chunk, result *chunk
 	return nil
 }
 
+func (b *builtinUnaryMinusDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnaryMinusDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, result); err != nil {
+		return err
+	}
+
+	n := input.NumRows()
+	decs := result.Decimals()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		decs[i] = *types.DecimalNeg(&decs[i])
+	}
+	return nil
+}
+
 func (b *builtinIntIsNullSig) vectorized() bool {
 	return true
 }
@@ -134,6 +154,150 @@ func (b *builtinRealIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colu
 	return nil
 }
 
+func (b *builtinDecimalIsNullSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDecimalIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	numRows := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(numRows, false)
+	i64s := result.Int64s()
+	for i := 0; i < numRows; i++ {
+		if buf.IsNull(i) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinTimeIsNullSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTimeIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	numRows := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDatetime, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(numRows, false)
+	i64s := result.Int64s()
+	for i := 0; i < numRows; i++ {
+		if buf.IsNull(i) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinDurationIsNullSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDurationIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	numRows := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDuration, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	if err := b.args[0].VecEvalDuration(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(numRows, false)
+	i64s := result.Int64s()
+	for i := 0; i < numRows; i++ {
+		if buf.IsNull(i) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinJSONIsNullSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	numRows := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETJson, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	if err := b.args[0].VecEvalJSON(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(numRows, false)
+	i64s := result.Int64s()
+	for i := 0; i < numRows; i++ {
+		if buf.IsNull(i) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinUnaryNotDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnaryNotDecimalSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETDecimal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+	decs := buf.Decimals()
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if decs[i].IsZero() {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
 func (b *builtinUnaryNotRealSig) vectorized() bool {
 	return true
 }
@@ -265,3 +429,149 @@ func (b *builtinUnaryNotIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Col
 	}
 	return nil
 }
+
+func (b *builtinBitAndSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitAndSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	numRows := input.NumRows()
+	arg1, err := b.bufAllocator.get(types.ETInt, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(arg1)
+	if err := b.args[1].VecEvalInt(b.ctx, input, arg1); err != nil {
+		return err
+	}
+	arg0s := result.Int64s()
+	arg1s := arg1.Int64s()
+	for i := 0; i < numRows; i++ {
+		arg0s[i] &= arg1s[i]
+	}
+	result.MergeNulls(arg1)
+	return nil
+}
+
+func (b *builtinBitOrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitOrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	numRows := input.NumRows()
+	arg1, err := b.bufAllocator.get(types.ETInt, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(arg1)
+	if err := b.args[1].VecEvalInt(b.ctx, input, arg1); err != nil {
+		return err
+	}
+	arg0s := result.Int64s()
+	arg1s := arg1.Int64s()
+	for i := 0; i < numRows; i++ {
+		arg0s[i] |= arg1s[i]
+	}
+	result.MergeNulls(arg1)
+	return nil
+}
+
+func (b *builtinBitXorSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitXorSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	numRows := input.NumRows()
+	arg1, err := b.bufAllocator.get(types.ETInt, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(arg1)
+	if err := b.args[1].VecEvalInt(b.ctx, input, arg1); err != nil {
+		return err
+	}
+	arg0s := result.Int64s()
+	arg1s := arg1.Int64s()
+	for i := 0; i < numRows; i++ {
+		arg0s[i] ^= arg1s[i]
+	}
+	result.MergeNulls(arg1)
+	return nil
+}
+
+func (b *builtinLeftShiftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftShiftSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	numRows := input.NumRows()
+	arg1, err := b.bufAllocator.get(types.ETInt, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(arg1)
+	if err := b.args[1].VecEvalInt(b.ctx, input, arg1); err != nil {
+		return err
+	}
+	arg0s := result.Int64s()
+	arg1s := arg1.Int64s()
+	for i := 0; i < numRows; i++ {
+		arg0s[i] = int64(uint64(arg0s[i]) << uint64(arg1s[i]))
+	}
+	result.MergeNulls(arg1)
+	return nil
+}
+
+func (b *builtinRightShiftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightShiftSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	numRows := input.NumRows()
+	arg1, err := b.bufAllocator.get(types.ETInt, numRows)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(arg1)
+	if err := b.args[1].VecEvalInt(b.ctx, input, arg1); err != nil {
+		return err
+	}
+	arg0s := result.Int64s()
+	arg1s := arg1.Int64s()
+	for i := 0; i < numRows; i++ {
+		arg0s[i] = int64(uint64(arg0s[i]) >> uint64(arg1s[i]))
+	}
+	result.MergeNulls(arg1)
+	return nil
+}
+
+func (b *builtinBitNegSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitNegSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	args := result.Int64s()
+	for i := 0; i < n; i++ {
+		args[i] = ^args[i]
+	}
+	return nil
+}
@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -45,6 +47,18 @@ func (g inGener) gen() interface{} {
 			return -float64(randNum)
 		}
 		return float64(randNum)
+	case types.ETDecimal:
+		d := new(types.MyDecimal)
+		f := float64(randNum)
+		if rand.Float64() < 0.5 {
+			f = -f
+		}
+		d.FromFloat64(f)
+		return d
+	case types.ETDatetime, types.ETTimestamp:
+		return types.NewTime(types.FromDate(2019, 11, 2, 22, 00, int(randNum), rand.Intn(1000000)), mysql.TypeDatetime, 6)
+	case types.ETDuration:
+		return types.Duration{Duration: time.Duration(randNum)}
 	case types.ETString:
 		return fmt.Sprint(randNum)
 	}
@@ -101,6 +115,54 @@ var vecBuiltinOtherGeneratedCases = map[string][]vecExprBenchCase{
 				inGener{defaultGener{eType: types.ETReal, nullRation: 0.2}},
 			},
 		},
+		// builtinInDecimalSig
+		{
+			retEvalType: types.ETInt,
+			childrenTypes: []types.EvalType{
+				types.ETDecimal,
+				types.ETDecimal,
+				types.ETDecimal,
+				types.ETDecimal,
+			},
+			geners: []dataGenerator{
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDecimal, nullRation: 0.2}},
+			},
+		},
+		// builtinInTimeSig
+		{
+			retEvalType: types.ETInt,
+			childrenTypes: []types.EvalType{
+				types.ETDatetime,
+				types.ETDatetime,
+				types.ETDatetime,
+				types.ETDatetime,
+			},
+			geners: []dataGenerator{
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDatetime, nullRation: 0.2}},
+			},
+		},
+		// builtinInDurationSig
+		{
+			retEvalType: types.ETInt,
+			childrenTypes: []types.EvalType{
+				types.ETDuration,
+				types.ETDuration,
+				types.ETDuration,
+				types.ETDuration,
+			},
+			geners: []dataGenerator{
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+				inGener{defaultGener{eType: types.ETDuration, nullRation: 0.2}},
+			},
+		},
 	},
 }
 
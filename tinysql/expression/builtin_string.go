@@ -18,8 +18,14 @@
 package expression
 
 import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
@@ -28,11 +34,55 @@ import (
 var (
 	_ functionClass = &lengthFunctionClass{}
 	_ functionClass = &strcmpFunctionClass{}
+	_ functionClass = &concatFunctionClass{}
+	_ functionClass = &concatWSFunctionClass{}
+	_ functionClass = &substringFunctionClass{}
+	_ functionClass = &locateFunctionClass{}
+	_ functionClass = &replaceFunctionClass{}
+	_ functionClass = &trimFunctionClass{}
+	_ functionClass = &lTrimFunctionClass{}
+	_ functionClass = &rTrimFunctionClass{}
+	_ functionClass = &upperFunctionClass{}
+	_ functionClass = &lowerFunctionClass{}
+	_ functionClass = &lpadFunctionClass{}
+	_ functionClass = &rpadFunctionClass{}
+	_ functionClass = &leftFunctionClass{}
+	_ functionClass = &rightFunctionClass{}
+	_ functionClass = &reverseFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinLengthSig{}
 	_ builtinFunc = &builtinStrcmpSig{}
+	_ builtinFunc = &builtinConcatSig{}
+	_ builtinFunc = &builtinConcatWSSig{}
+	_ builtinFunc = &builtinSubstring2ArgsSig{}
+	_ builtinFunc = &builtinSubstring2ArgsUTF8Sig{}
+	_ builtinFunc = &builtinSubstring3ArgsSig{}
+	_ builtinFunc = &builtinSubstring3ArgsUTF8Sig{}
+	_ builtinFunc = &builtinLocate2ArgsSig{}
+	_ builtinFunc = &builtinLocate2ArgsUTF8Sig{}
+	_ builtinFunc = &builtinLocate3ArgsSig{}
+	_ builtinFunc = &builtinLocate3ArgsUTF8Sig{}
+	_ builtinFunc = &builtinReplaceSig{}
+	_ builtinFunc = &builtinTrim1ArgSig{}
+	_ builtinFunc = &builtinTrim2ArgsSig{}
+	_ builtinFunc = &builtinTrim3ArgsSig{}
+	_ builtinFunc = &builtinLTrimSig{}
+	_ builtinFunc = &builtinRTrimSig{}
+	_ builtinFunc = &builtinUpperSig{}
+	_ builtinFunc = &builtinUpperUTF8Sig{}
+	_ builtinFunc = &builtinLowerSig{}
+	_ builtinFunc = &builtinLpadSig{}
+	_ builtinFunc = &builtinLpadUTF8Sig{}
+	_ builtinFunc = &builtinRpadSig{}
+	_ builtinFunc = &builtinRpadUTF8Sig{}
+	_ builtinFunc = &builtinLeftSig{}
+	_ builtinFunc = &builtinLeftUTF8Sig{}
+	_ builtinFunc = &builtinRightSig{}
+	_ builtinFunc = &builtinRightUTF8Sig{}
+	_ builtinFunc = &builtinReverseSig{}
+	_ builtinFunc = &builtinReverseUTF8Sig{}
 )
 
 // SetBinFlagOrBinStr sets resTp to binary string if argTp is a binary string,
@@ -126,3 +176,1349 @@ func (b *builtinStrcmpSig) evalInt(row chunk.Row) (int64, bool, error) {
 	res := types.CompareString(left, right)
 	return int64(res), false, nil
 }
+
+// getMaxAllowedPacket gets the max_allowed_packet of the session, functions
+// which may generate long strings check their results against it.
+func getMaxAllowedPacket(ctx sessionctx.Context) (uint64, error) {
+	valStr, _ := ctx.GetSessionVars().GetSystemVar(variable.MaxAllowedPacket)
+	return strconv.ParseUint(valStr, 10, 64)
+}
+
+type concatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for i := range args {
+		argType := args[i].GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		if argType.Flen < 0 {
+			bf.tp.Flen = mysql.MaxBlobWidth
+			break
+		}
+		bf.tp.Flen += argType.Flen
+	}
+	if bf.tp.Flen >= mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinConcatSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_Concat)
+	return sig, nil
+}
+
+type builtinConcatSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinConcatSig) Clone() builtinFunc {
+	newSig := &builtinConcatSig{maxAllowedPacket: b.maxAllowedPacket}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinConcatSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) evalString(row chunk.Row) (d string, isNull bool, err error) {
+	var s []byte
+	for _, a := range b.getArgs() {
+		d, isNull, err = a.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return d, isNull, err
+		}
+		if uint64(len(s)+len(d)) > b.maxAllowedPacket {
+			return "", true, handleAllowedPacketOverflowed(b.ctx, "concat", b.maxAllowedPacket)
+		}
+		s = append(s, d...)
+	}
+	return string(s), false, nil
+}
+
+type concatWSFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatWSFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for i := range args {
+		argType := args[i].GetType()
+		SetBinFlagOrBinStr(argType, bf.tp)
+		// Skip the separator.
+		if i == 0 || bf.tp.Flen >= mysql.MaxBlobWidth {
+			continue
+		}
+		if argType.Flen < 0 {
+			bf.tp.Flen = mysql.MaxBlobWidth
+			continue
+		}
+		bf.tp.Flen += argType.Flen
+	}
+	// Add the length of the separators.
+	bf.tp.Flen += (len(args) - 2) * args[0].GetType().Flen
+	if bf.tp.Flen >= mysql.MaxBlobWidth || args[0].GetType().Flen < 0 {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinConcatWSSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_ConcatWS)
+	return sig, nil
+}
+
+type builtinConcatWSSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinConcatWSSig) Clone() builtinFunc {
+	newSig := &builtinConcatWSSig{maxAllowedPacket: b.maxAllowedPacket}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinConcatWSSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat-ws
+func (b *builtinConcatWSSig) evalString(row chunk.Row) (string, bool, error) {
+	sep, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	strs := make([]string, 0, len(b.args)-1)
+	targetLength := 0
+	for _, arg := range b.args[1:] {
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if err != nil {
+			return "", true, err
+		}
+		// NULL values are skipped.
+		if isNull {
+			continue
+		}
+		targetLength += len(val)
+		if len(strs) > 0 {
+			targetLength += len(sep)
+		}
+		if uint64(targetLength) > b.maxAllowedPacket {
+			return "", true, handleAllowedPacketOverflowed(b.ctx, "concat_ws", b.maxAllowedPacket)
+		}
+		strs = append(strs, val)
+	}
+	return strings.Join(strs, sep), false, nil
+}
+
+type substringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *substringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+
+	var sig builtinFunc
+	switch {
+	case len(args) == 3 && types.IsBinaryStr(argType):
+		sig = &builtinSubstring3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring3Args)
+	case len(args) == 3:
+		sig = &builtinSubstring3ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring3ArgsUTF8)
+	case types.IsBinaryStr(argType):
+		sig = &builtinSubstring2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring2Args)
+	default:
+		sig = &builtinSubstring2ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Substring2ArgsUTF8)
+	}
+	return sig, nil
+}
+
+// substringBounds converts the 1-based position and the length of SUBSTRING
+// to the bounds of a string whose length is strLen. A negative position
+// counts from the end of the string, and a nil length means to the end.
+func substringBounds(strLen, pos int64, length *int64) (begin, end int64) {
+	if pos < 0 {
+		pos += strLen
+	} else {
+		pos--
+	}
+	if pos > strLen || pos < 0 {
+		pos = strLen
+	}
+	end = strLen
+	if length != nil {
+		if *length < 0 {
+			end = pos
+		} else if *length < strLen-pos {
+			end = pos + *length
+		}
+	}
+	return pos, end
+}
+
+type builtinSubstring2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinSubstring2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos), SUBSTR(str FROM pos), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring2ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	begin, end := substringBounds(int64(len(str)), pos, nil)
+	return str[begin:end], false, nil
+}
+
+type builtinSubstring2ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinSubstring2ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos), SUBSTR(str FROM pos), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring2ArgsUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	begin, end := substringBounds(int64(len(runes)), pos, nil)
+	return string(runes[begin:end]), false, nil
+}
+
+type builtinSubstring3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinSubstring3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos,len), SUBSTR(str FROM pos FOR len), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring3ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	begin, end := substringBounds(int64(len(str)), pos, &length)
+	return str[begin:end], false, nil
+}
+
+type builtinSubstring3ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinSubstring3ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTR(str,pos,len), SUBSTR(str FROM pos FOR len), SUBSTR() is a synonym for SUBSTRING().
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substr
+func (b *builtinSubstring3ArgsUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	begin, end := substringBounds(int64(len(runes)), pos, &length)
+	return string(runes[begin:end]), false, nil
+}
+
+type locateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *locateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	hasStartPos, argTps := len(args) == 3, []types.EvalType{types.ETString, types.ETString}
+	if hasStartPos {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	// LOCATE is multibyte safe, and is case-sensitive only if at least one
+	// argument is a binary string or the collation is case-sensitive.
+	binary, _ := PatternMatchCollation(args[0], args[1])
+	var sig builtinFunc
+	switch {
+	case hasStartPos && binary:
+		sig = &builtinLocate3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate3Args)
+	case hasStartPos:
+		sig = &builtinLocate3ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate3ArgsUTF8)
+	case binary:
+		sig = &builtinLocate2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate2Args)
+	default:
+		sig = &builtinLocate2ArgsUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Locate2ArgsUTF8)
+	}
+	return sig, nil
+}
+
+// locateBytes returns the 1-based byte position of the first occurrence of
+// subStr in str starting at the 0-based byte position pos, or 0 if it is not
+// found.
+func locateBytes(subStr, str string, pos int64) int64 {
+	if pos < 0 || pos > int64(len(str))-int64(len(subStr)) {
+		return 0
+	}
+	if idx := strings.Index(str[pos:], subStr); idx != -1 {
+		return pos + int64(idx) + 1
+	}
+	return 0
+}
+
+// locateRunes is like locateBytes, but the positions are counted in
+// characters, and the characters are compared case-insensitively if ci is
+// true.
+func locateRunes(subStr, str string, pos int64, ci bool) int64 {
+	if ci {
+		subStr, str = strings.ToLower(subStr), strings.ToLower(str)
+	}
+	strLen, subStrLen := int64(utf8.RuneCountInString(str)), int64(utf8.RuneCountInString(subStr))
+	if pos < 0 || pos > strLen-subStrLen {
+		return 0
+	}
+	slice := string([]rune(str)[pos:])
+	if idx := strings.Index(slice, subStr); idx != -1 {
+		return pos + int64(utf8.RuneCountInString(slice[:idx])) + 1
+	}
+	return 0
+}
+
+type builtinLocate2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLocate2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate2ArgsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return locateBytes(subStr, str, 0), false, nil
+}
+
+type builtinLocate2ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLocate2ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate2ArgsUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	_, ci := PatternMatchCollation(b.args[0], b.args[1])
+	return locateRunes(subStr, str, 0, ci), false, nil
+}
+
+type builtinLocate3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLocate3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str,pos), case-sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate3ArgsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pos, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	// Transfer the argument which starts from 1 to real index which starts from 0.
+	return locateBytes(subStr, str, pos-1), false, nil
+}
+
+type builtinLocate3ArgsUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLocate3ArgsUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LOCATE(substr,str,pos).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_locate
+func (b *builtinLocate3ArgsUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	subStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pos, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	_, ci := PatternMatchCollation(b.args[0], b.args[1])
+	// Transfer the argument which starts from 1 to real index which starts from 0.
+	return locateRunes(subStr, str, pos-1, ci), false, nil
+}
+
+type replaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *replaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = c.fixLength(args)
+	for _, a := range args {
+		SetBinFlagOrBinStr(a.GetType(), bf.tp)
+	}
+	sig := &builtinReplaceSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Replace)
+	return sig, nil
+}
+
+// fixLength calculates the Flen of the return type.
+func (c *replaceFunctionClass) fixLength(args []Expression) int {
+	charLen := args[0].GetType().Flen
+	oldStrLen := args[1].GetType().Flen
+	diff := args[2].GetType().Flen - oldStrLen
+	if diff > 0 && oldStrLen > 0 {
+		charLen += (charLen / oldStrLen) * diff
+	}
+	if charLen < 0 || charLen > mysql.MaxBlobWidth {
+		charLen = mysql.MaxBlobWidth
+	}
+	return charLen
+}
+
+type builtinReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReplaceSig) Clone() builtinFunc {
+	newSig := &builtinReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	oldStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	newStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if oldStr == "" {
+		return str, false, nil
+	}
+	return strings.Replace(str, oldStr, newStr, -1), false, nil
+}
+
+const spaceChars = " "
+
+type trimFunctionClass struct {
+	baseFunctionClass
+}
+
+// getFunction sets trim built-in function signature.
+// The syntax of trim in mysql is 'TRIM([{BOTH | LEADING | TRAILING} [remstr] FROM] str), TRIM([remstr FROM] str)',
+// but we wil convert it into trim(str), trim(str, remstr) and trim(str, remstr, direction) in AST.
+func (c *trimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps[:len(args)]...)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+
+	var sig builtinFunc
+	switch len(args) {
+	case 1:
+		sig = &builtinTrim1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim1Arg)
+	case 2:
+		sig = &builtinTrim2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim2Args)
+	default:
+		sig = &builtinTrim3ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Trim3Args)
+	}
+	return sig, nil
+}
+
+// trimLeft removes all the leading occurrences of remstr from str.
+func trimLeft(str, remstr string) string {
+	if remstr == "" {
+		return str
+	}
+	for strings.HasPrefix(str, remstr) {
+		str = str[len(remstr):]
+	}
+	return str
+}
+
+// trimRight removes all the trailing occurrences of remstr from str.
+func trimRight(str, remstr string) string {
+	if remstr == "" {
+		return str
+	}
+	for strings.HasSuffix(str, remstr) {
+		str = str[:len(str)-len(remstr)]
+	}
+	return str
+}
+
+// trimByDirection trims remstr from str in the direction, a NULL remstr
+// trims spaces.
+func trimByDirection(str, remstr string, isRemStrNull bool, direction ast.TrimDirectionType) string {
+	switch direction {
+	case ast.TrimLeading:
+		if isRemStrNull {
+			return strings.TrimLeft(str, spaceChars)
+		}
+		return trimLeft(str, remstr)
+	case ast.TrimTrailing:
+		if isRemStrNull {
+			return strings.TrimRight(str, spaceChars)
+		}
+		return trimRight(str, remstr)
+	default:
+		if isRemStrNull {
+			return strings.Trim(str, spaceChars)
+		}
+		return trimRight(trimLeft(str, remstr), remstr)
+	}
+}
+
+type builtinTrim1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim1ArgSig) Clone() builtinFunc {
+	newSig := &builtinTrim1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim1ArgSig, corresponding to trim(str)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim1ArgSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return strings.Trim(str, spaceChars), false, nil
+}
+
+type builtinTrim2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinTrim2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim2ArgsSig, corresponding to trim(str, remstr)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim2ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	remstr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return trimRight(trimLeft(str, remstr), remstr), false, nil
+}
+
+type builtinTrim3ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTrim3ArgsSig) Clone() builtinFunc {
+	newSig := &builtinTrim3ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTrim3ArgsSig, corresponding to trim(str, remstr, direction)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_trim
+func (b *builtinTrim3ArgsSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	remstr, isRemStrNull, err := b.args[1].EvalString(b.ctx, row)
+	if err != nil {
+		return "", true, err
+	}
+	direction, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return trimByDirection(str, remstr, isRemStrNull, ast.TrimDirectionType(direction)), false, nil
+}
+
+type lTrimFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lTrimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinLTrimSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LTrim)
+	return sig, nil
+}
+
+type builtinLTrimSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLTrimSig) Clone() builtinFunc {
+	newSig := &builtinLTrimSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLTrimSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_ltrim
+func (b *builtinLTrimSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return strings.TrimLeft(str, spaceChars), false, nil
+}
+
+type rTrimFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rTrimFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinRTrimSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RTrim)
+	return sig, nil
+}
+
+type builtinRTrimSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRTrimSig) Clone() builtinFunc {
+	newSig := &builtinRTrimSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinRTrimSig
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rtrim
+func (b *builtinRTrimSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return strings.TrimRight(str, spaceChars), false, nil
+}
+
+type upperFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *upperFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	var sig builtinFunc
+	if types.IsBinaryStr(argTp) {
+		sig = &builtinUpperSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Upper)
+	} else {
+		sig = &builtinUpperUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UpperUTF8)
+	}
+	return sig, nil
+}
+
+type builtinUpperSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUpperSig) Clone() builtinFunc {
+	newSig := &builtinUpperSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUpperSig, binary strings are not converted.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func (b *builtinUpperSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.args[0].EvalString(b.ctx, row)
+}
+
+type builtinUpperUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUpperUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinUpperUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUpperUTF8Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_upper
+func (b *builtinUpperUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return strings.ToUpper(str), false, nil
+}
+
+type lowerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lowerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	sig := &builtinLowerSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Lower)
+	return sig, nil
+}
+
+type builtinLowerSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLowerSig) Clone() builtinFunc {
+	newSig := &builtinLowerSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinLowerSig, binary strings are not converted.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lower
+func (b *builtinLowerSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return str, false, nil
+	}
+	return strings.ToLower(str), false, nil
+}
+
+type lpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType()) {
+		sig := &builtinLpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Lpad)
+		return sig, nil
+	}
+	if bf.tp.Flen *= mysql.MaxBytesOfCharacter; bf.tp.Flen > mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	sig := &builtinLpadUTF8Sig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_LpadUTF8)
+	return sig, nil
+}
+
+// getFlen4LpadAndRpad gets the `flen` for the function `lpad` and `rpad`.
+func getFlen4LpadAndRpad(ctx sessionctx.Context, arg Expression) int {
+	if constant, ok := arg.(*Constant); ok {
+		if MaybeOverOptimized4PlanCache(ctx, []Expression{constant}) {
+			ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+		}
+		length, isNull, err := constant.EvalInt(ctx, chunk.Row{})
+		if isNull || err != nil || length < 0 || length > mysql.MaxBlobWidth {
+			return mysql.MaxBlobWidth
+		}
+		return int(length)
+	}
+	return mysql.MaxBlobWidth
+}
+
+// padBytes pads str with padStr to targetLength bytes, on the left if left is
+// true, otherwise on the right. It returns isNull when the result is NULL.
+func padBytes(str, padStr string, targetLength int64, left bool) (string, bool) {
+	if targetLength < 0 || targetLength > int64(len(str)) && len(padStr) == 0 {
+		return "", true
+	}
+	if tailLen := int(targetLength) - len(str); tailLen > 0 {
+		pad := strings.Repeat(padStr, tailLen/len(padStr)+1)[:tailLen]
+		if left {
+			return pad + str, false
+		}
+		return str + pad, false
+	}
+	return str[:targetLength], false
+}
+
+// padRunes is like padBytes, but the lengths are counted in characters.
+func padRunes(str, padStr string, targetLength int64, left bool) (string, bool) {
+	runeLength, padLength := utf8.RuneCountInString(str), utf8.RuneCountInString(padStr)
+	if targetLength < 0 || targetLength > int64(runeLength) && padLength == 0 {
+		return "", true
+	}
+	if tailLen := int(targetLength) - runeLength; tailLen > 0 {
+		pad := string([]rune(strings.Repeat(padStr, tailLen/padLength+1))[:tailLen])
+		if left {
+			return pad + str, false
+		}
+		return str + pad, false
+	}
+	return string([]rune(str)[:targetLength]), false
+}
+
+type builtinLpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadSig) Clone() builtinFunc {
+	newSig := &builtinLpadSig{maxAllowedPacket: b.maxAllowedPacket}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if length > 0 && uint64(length) > b.maxAllowedPacket {
+		return "", true, handleAllowedPacketOverflowed(b.ctx, "lpad", b.maxAllowedPacket)
+	}
+	padStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	result, isNull := padBytes(str, padStr, length, true)
+	return result, isNull, nil
+}
+
+type builtinLpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLpadUTF8Sig{maxAllowedPacket: b.maxAllowedPacket}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if length > 0 && uint64(length)*mysql.MaxBytesOfCharacter > b.maxAllowedPacket {
+		return "", true, handleAllowedPacketOverflowed(b.ctx, "lpad", b.maxAllowedPacket)
+	}
+	padStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	result, isNull := padRunes(str, padStr, length, true)
+	return result, isNull, nil
+}
+
+type rpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType()) {
+		sig := &builtinRpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Rpad)
+		return sig, nil
+	}
+	if bf.tp.Flen *= mysql.MaxBytesOfCharacter; bf.tp.Flen > mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	sig := &builtinRpadUTF8Sig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_RpadUTF8)
+	return sig, nil
+}
+
+type builtinRpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadSig) Clone() builtinFunc {
+	newSig := &builtinRpadSig{maxAllowedPacket: b.maxAllowedPacket}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if length > 0 && uint64(length) > b.maxAllowedPacket {
+		return "", true, handleAllowedPacketOverflowed(b.ctx, "rpad", b.maxAllowedPacket)
+	}
+	padStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	result, isNull := padBytes(str, padStr, length, false)
+	return result, isNull, nil
+}
+
+type builtinRpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRpadUTF8Sig{maxAllowedPacket: b.maxAllowedPacket}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RPAD(str,len,padstr).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if length > 0 && uint64(length)*mysql.MaxBytesOfCharacter > b.maxAllowedPacket {
+		return "", true, handleAllowedPacketOverflowed(b.ctx, "rpad", b.maxAllowedPacket)
+	}
+	padStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	result, isNull := padRunes(str, padStr, length, false)
+	return result, isNull, nil
+}
+
+type leftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if types.IsBinaryStr(argType) {
+		sig := &builtinLeftSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Left)
+		return sig, nil
+	}
+	sig := &builtinLeftUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LeftUTF8)
+	return sig, nil
+}
+
+// clampLength limits length into [0, strLen].
+func clampLength(length int64, strLen int) int {
+	if length > int64(strLen) {
+		return strLen
+	} else if length < 0 {
+		return 0
+	}
+	return int(length)
+}
+
+type builtinLeftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftSig) Clone() builtinFunc {
+	newSig := &builtinLeftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LEFT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
+func (b *builtinLeftSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return str[:clampLength(length, len(str))], false, nil
+}
+
+type builtinLeftUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLeftUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LEFT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_left
+func (b *builtinLeftUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	return string(runes[:clampLength(length, len(runes))]), false, nil
+}
+
+type rightFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rightFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	if types.IsBinaryStr(argType) {
+		sig := &builtinRightSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Right)
+		return sig, nil
+	}
+	sig := &builtinRightUTF8Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_RightUTF8)
+	return sig, nil
+}
+
+type builtinRightSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightSig) Clone() builtinFunc {
+	newSig := &builtinRightSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RIGHT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func (b *builtinRightSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return str[len(str)-clampLength(length, len(str)):], false, nil
+}
+
+type builtinRightUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRightUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals RIGHT(str,len).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_right
+func (b *builtinRightUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	runes := []rune(str)
+	return string(runes[len(runes)-clampLength(length, len(runes)):]), false, nil
+}
+
+type reverseFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *reverseFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	SetBinFlagOrBinStr(argTp, bf.tp)
+	var sig builtinFunc
+	if types.IsBinaryStr(argTp) {
+		sig = &builtinReverseSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Reverse)
+	} else {
+		sig = &builtinReverseUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ReverseUTF8)
+	}
+	return sig, nil
+}
+
+func reverseBytes(origin []byte) []byte {
+	for i, length := 0, len(origin); i < length/2; i++ {
+		origin[i], origin[length-i-1] = origin[length-i-1], origin[i]
+	}
+	return origin
+}
+
+func reverseRunes(origin []rune) []rune {
+	for i, length := 0, len(origin); i < length/2; i++ {
+		origin[i], origin[length-i-1] = origin[length-i-1], origin[i]
+	}
+	return origin
+}
+
+type builtinReverseSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReverseSig) Clone() builtinFunc {
+	newSig := &builtinReverseSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a REVERSE(str).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func (b *builtinReverseSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return string(reverseBytes([]byte(str))), false, nil
+}
+
+type builtinReverseUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReverseUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinReverseUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a REVERSE(str).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_reverse
+func (b *builtinReverseUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return string(reverseRunes([]rune(str))), false, nil
+}
@@ -14,9 +14,12 @@
 package expression
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -98,3 +101,236 @@ func (s *testEvaluatorSuite) TestStrcmp(c *C) {
 		}
 	}
 }
+
+// evalStringFuncForTest evaluates the function with the arguments, the result
+// is nil if it is NULL.
+func (s *testEvaluatorSuite) evalStringFuncForTest(c *C, funcName string, args ...interface{}) interface{} {
+	f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants(args)...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	if d.IsNull() {
+		return nil
+	}
+	return d.GetValue()
+}
+
+func (s *testEvaluatorSuite) TestConcat(c *C) {
+	cases := []struct {
+		args []interface{}
+		res  interface{}
+	}{
+		{[]interface{}{nil}, nil},
+		{[]interface{}{"a", "b", 1, 2, 1.1, 1.2}, "ab121.11.2"},
+		{[]interface{}{"a", "b", nil}, nil},
+		{[]interface{}{"中文", []byte("abc")}, "中文abc"},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Concat, t.args...), DeepEquals, t.res, Commentf("%v", t.args))
+	}
+
+	_, err := funcs[ast.Concat].getFunction(s.ctx, nil)
+	c.Assert(err, NotNil)
+}
+
+func (s *testEvaluatorSuite) TestConcatWS(c *C) {
+	cases := []struct {
+		args []interface{}
+		res  interface{}
+	}{
+		{[]interface{}{nil, "a", "b"}, nil},
+		{[]interface{}{",", "a", "b"}, "a,b"},
+		{[]interface{}{",", "a", nil, "b"}, "a,b"},
+		{[]interface{}{",", nil, nil}, ""},
+		{[]interface{}{"--", 1, 2.5, "c"}, "1--2.5--c"},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.ConcatWS, t.args...), DeepEquals, t.res, Commentf("%v", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestConcatOverflow(c *C) {
+	c.Assert(s.ctx.GetSessionVars().SetSystemVar(variable.MaxAllowedPacket, "4"), IsNil)
+	defer func() {
+		c.Assert(s.ctx.GetSessionVars().SetSystemVar(variable.MaxAllowedPacket, "67108864"), IsNil)
+	}()
+	warnCnt := s.ctx.GetSessionVars().StmtCtx.WarningCount()
+	c.Assert(s.evalStringFuncForTest(c, ast.Concat, "abc", "de"), IsNil)
+	c.Assert(s.evalStringFuncForTest(c, ast.ConcatWS, ",", "ab", "cd"), IsNil)
+	c.Assert(s.evalStringFuncForTest(c, ast.Lpad, "a", 5, "x"), IsNil)
+	c.Assert(s.evalStringFuncForTest(c, ast.Rpad, []byte("a"), 2, "x"), Equals, "ax")
+	c.Assert(s.ctx.GetSessionVars().StmtCtx.WarningCount(), Equals, warnCnt+3)
+}
+
+func (s *testEvaluatorSuite) TestSubstring(c *C) {
+	cases := []struct {
+		args []interface{}
+		res  interface{}
+	}{
+		{[]interface{}{"Quadratically", 5}, "ratically"},
+		{[]interface{}{"Sakila", -3}, "ila"},
+		{[]interface{}{"Sakila", 0}, ""},
+		{[]interface{}{"Sakila", 7}, ""},
+		{[]interface{}{"Quadratically", 5, 6}, "ratica"},
+		{[]interface{}{"Sakila", -5, 3}, "aki"},
+		{[]interface{}{"Sakila", 2, -1}, ""},
+		{[]interface{}{"Sakila", 3, int64(math.MaxInt64)}, "kila"},
+		{[]interface{}{"中文字符串", 2, 2}, "文字"},
+		{[]interface{}{[]byte("中文"), 4}, "文"},
+		{[]interface{}{nil, 1}, nil},
+		{[]interface{}{"Sakila", nil}, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Substring, t.args...), DeepEquals, t.res, Commentf("%v", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestLocate(c *C) {
+	cases := []struct {
+		args []interface{}
+		res  interface{}
+	}{
+		{[]interface{}{"bar", "foobarbar"}, int64(4)},
+		{[]interface{}{"xbar", "foobar"}, int64(0)},
+		{[]interface{}{"", "foobar"}, int64(1)},
+		{[]interface{}{"bar", "foobarbar", 5}, int64(7)},
+		{[]interface{}{"bar", "foobarbar", 0}, int64(0)},
+		{[]interface{}{"bar", "foobarbar", 10}, int64(0)},
+		{[]interface{}{"BaR", "foobarbar"}, int64(0)},
+		{[]interface{}{"字符", "中文字符串"}, int64(3)},
+		{[]interface{}{[]byte("字符"), "中文字符串"}, int64(7)},
+		{[]interface{}{nil, "foobar"}, nil},
+		{[]interface{}{"bar", "foobar", nil}, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Locate, t.args...), DeepEquals, t.res, Commentf("%v", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestReplace(c *C) {
+	cases := []struct {
+		args []interface{}
+		res  interface{}
+	}{
+		{[]interface{}{"www.mysql.com", "mysql", "pingcap"}, "www.pingcap.com"},
+		{[]interface{}{"www.mysql.com", "w", 1}, "111.mysql.com"},
+		{[]interface{}{"abc", "", "x"}, "abc"},
+		{[]interface{}{"中文", "文", "国"}, "中国"},
+		{[]interface{}{nil, "a", "b"}, nil},
+		{[]interface{}{"abc", nil, "b"}, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Replace, t.args...), DeepEquals, t.res, Commentf("%v", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestTrim(c *C) {
+	cases := []struct {
+		args []interface{}
+		res  interface{}
+	}{
+		{[]interface{}{"   bar   "}, "bar"},
+		{[]interface{}{"xxxbarxxx", "x"}, "bar"},
+		{[]interface{}{"xyxybarxyxy", "xy"}, "bar"},
+		{[]interface{}{"xxxbarxxx", "x", int(ast.TrimLeading)}, "barxxx"},
+		{[]interface{}{"barxxyz", "xyz", int(ast.TrimTrailing)}, "barx"},
+		{[]interface{}{"xxxbarxxx", "x", int(ast.TrimBoth)}, "bar"},
+		{[]interface{}{"   bar   ", nil, int(ast.TrimLeading)}, "bar   "},
+		{[]interface{}{"   bar   ", nil, int(ast.TrimBothDefault)}, "bar"},
+		{[]interface{}{"bar", ""}, "bar"},
+		{[]interface{}{nil}, nil},
+		{[]interface{}{"bar", nil}, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Trim, t.args...), DeepEquals, t.res, Commentf("%v", t.args))
+	}
+
+	c.Assert(s.evalStringFuncForTest(c, ast.LTrim, "  bar  "), Equals, "bar  ")
+	c.Assert(s.evalStringFuncForTest(c, ast.RTrim, "  bar  "), Equals, "  bar")
+	c.Assert(s.evalStringFuncForTest(c, ast.LTrim, nil), IsNil)
+	c.Assert(s.evalStringFuncForTest(c, ast.RTrim, nil), IsNil)
+}
+
+func (s *testEvaluatorSuite) TestUpperAndLower(c *C) {
+	cases := []struct {
+		arg   interface{}
+		upper interface{}
+		lower interface{}
+	}{
+		{"aBc", "ABC", "abc"},
+		{"àÉ", "ÀÉ", "àé"},
+		{[]byte("aBc"), "aBc", "aBc"},
+		{1, "1", "1"},
+		{nil, nil, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Upper, t.arg), DeepEquals, t.upper, Commentf("%v", t.arg))
+		c.Assert(s.evalStringFuncForTest(c, ast.Ucase, t.arg), DeepEquals, t.upper, Commentf("%v", t.arg))
+		c.Assert(s.evalStringFuncForTest(c, ast.Lower, t.arg), DeepEquals, t.lower, Commentf("%v", t.arg))
+		c.Assert(s.evalStringFuncForTest(c, ast.Lcase, t.arg), DeepEquals, t.lower, Commentf("%v", t.arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestLpadAndRpad(c *C) {
+	cases := []struct {
+		str    interface{}
+		length interface{}
+		padStr interface{}
+		lpad   interface{}
+		rpad   interface{}
+	}{
+		{"hi", 5, "?", "???hi", "hi???"},
+		{"hi", 1, "?", "h", "h"},
+		{"hi", 6, "ab", "ababhi", "hiabab"},
+		{"hi", 3, "", nil, nil},
+		{"hi", 2, "", "hi", "hi"},
+		{"hi", -1, "?", nil, nil},
+		{"中文", 4, "字", "字字中文", "中文字字"},
+		{[]byte("中文"), 3, "?", "中", "中"},
+		{nil, 5, "?", nil, nil},
+		{"hi", nil, "?", nil, nil},
+		{"hi", 5, nil, nil, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Lpad, t.str, t.length, t.padStr), DeepEquals, t.lpad, Commentf("%v", t))
+		c.Assert(s.evalStringFuncForTest(c, ast.Rpad, t.str, t.length, t.padStr), DeepEquals, t.rpad, Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestLeftAndRight(c *C) {
+	cases := []struct {
+		str    interface{}
+		length interface{}
+		left   interface{}
+		right  interface{}
+	}{
+		{"abcde", 3, "abc", "cde"},
+		{"abcde", 0, "", ""},
+		{"abcde", -1, "", ""},
+		{"abcde", 10, "abcde", "abcde"},
+		{"中文字符串", 2, "中文", "符串"},
+		{[]byte("中文"), 3, "中", "文"},
+		{nil, 1, nil, nil},
+		{"abcde", nil, nil, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Left, t.str, t.length), DeepEquals, t.left, Commentf("%v", t))
+		c.Assert(s.evalStringFuncForTest(c, ast.Right, t.str, t.length), DeepEquals, t.right, Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestReverse(c *C) {
+	cases := []struct {
+		arg interface{}
+		res interface{}
+	}{
+		{"abc", "cba"},
+		{"中文", "文中"},
+		{[]byte("ab"), "ba"},
+		{"", ""},
+		{nil, nil},
+	}
+	for _, t := range cases {
+		c.Assert(s.evalStringFuncForTest(c, ast.Reverse, t.arg), DeepEquals, t.res, Commentf("%v", t.arg))
+	}
+}
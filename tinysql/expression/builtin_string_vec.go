@@ -14,6 +14,10 @@
 package expression
 
 import (
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -109,3 +113,725 @@ func (b *builtinLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 	}
 	return nil
 }
+
+func (b *builtinConcatSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a CONCAT(str1,str2,...)
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	strs := make([][]byte, n)
+	isNulls := make([]bool, n)
+	for _, arg := range b.args {
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if isNulls[i] {
+				continue
+			}
+			if buf.IsNull(i) {
+				isNulls[i] = true
+				continue
+			}
+			str := buf.GetBytes(i)
+			if uint64(len(strs[i])+len(str)) > b.maxAllowedPacket {
+				if err := handleAllowedPacketOverflowed(b.ctx, "concat", b.maxAllowedPacket); err != nil {
+					return err
+				}
+				isNulls[i] = true
+				continue
+			}
+			strs[i] = append(strs[i], str...)
+		}
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isNulls[i] {
+			result.AppendNull()
+		} else {
+			result.AppendBytes(strs[i])
+		}
+	}
+	return nil
+}
+
+func (b *builtinConcatWSSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a CONCAT_WS(separator,str1,str2,...).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat-ws
+func (b *builtinConcatWSSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j, arg := range b.args {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	strs := make([]string, 0, len(b.args)-1)
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		sep := bufs[0].GetString(i)
+		strs = strs[:0]
+		targetLength, overflowed := 0, false
+		for _, buf := range bufs[1:] {
+			// NULL values are skipped.
+			if buf.IsNull(i) {
+				continue
+			}
+			str := buf.GetString(i)
+			targetLength += len(str)
+			if len(strs) > 0 {
+				targetLength += len(sep)
+			}
+			if uint64(targetLength) > b.maxAllowedPacket {
+				overflowed = true
+				break
+			}
+			strs = append(strs, str)
+		}
+		if overflowed {
+			if err := handleAllowedPacketOverflowed(b.ctx, "concat_ws", b.maxAllowedPacket); err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(strings.Join(strs, sep))
+	}
+	return nil
+}
+
+// vecEvalStringIntArgs evaluates the first string argument and the following
+// integer arguments of a string function. The caller should put the returned
+// buffers back to the allocator.
+func (b *baseBuiltinFunc) vecEvalStringIntArgs(input *chunk.Chunk) (strBuf *chunk.Column, intBufs []*chunk.Column, err error) {
+	n := input.NumRows()
+	strBuf, err = b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		b.bufAllocator.put(strBuf)
+		return nil, nil, err
+	}
+	for _, arg := range b.args[1:] {
+		buf, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			b.putStringIntArgBufs(strBuf, intBufs)
+			return nil, nil, err
+		}
+		intBufs = append(intBufs, buf)
+		if err := arg.VecEvalInt(b.ctx, input, buf); err != nil {
+			b.putStringIntArgBufs(strBuf, intBufs)
+			return nil, nil, err
+		}
+	}
+	return strBuf, intBufs, nil
+}
+
+func (b *baseBuiltinFunc) putStringIntArgBufs(strBuf *chunk.Column, intBufs []*chunk.Column) {
+	b.bufAllocator.put(strBuf)
+	for _, buf := range intBufs {
+		b.bufAllocator.put(buf)
+	}
+}
+
+func (b *builtinSubstring2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring2ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	strBuf, intBufs, err := b.vecEvalStringIntArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putStringIntArgBufs(strBuf, intBufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	poses := intBufs[0].Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || intBufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str := strBuf.GetString(i)
+		begin, end := substringBounds(int64(len(str)), poses[i], nil)
+		result.AppendString(str[begin:end])
+	}
+	return nil
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring2ArgsUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	strBuf, intBufs, err := b.vecEvalStringIntArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putStringIntArgBufs(strBuf, intBufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	poses := intBufs[0].Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || intBufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		runes := []rune(strBuf.GetString(i))
+		begin, end := substringBounds(int64(len(runes)), poses[i], nil)
+		result.AppendString(string(runes[begin:end]))
+	}
+	return nil
+}
+
+func (b *builtinSubstring3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring3ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	strBuf, intBufs, err := b.vecEvalStringIntArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putStringIntArgBufs(strBuf, intBufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	poses, lengths := intBufs[0].Int64s(), intBufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || intBufs[0].IsNull(i) || intBufs[1].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str := strBuf.GetString(i)
+		begin, end := substringBounds(int64(len(str)), poses[i], &lengths[i])
+		result.AppendString(str[begin:end])
+	}
+	return nil
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstring3ArgsUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	strBuf, intBufs, err := b.vecEvalStringIntArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putStringIntArgBufs(strBuf, intBufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	poses, lengths := intBufs[0].Int64s(), intBufs[1].Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || intBufs[0].IsNull(i) || intBufs[1].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		runes := []rune(strBuf.GetString(i))
+		begin, end := substringBounds(int64(len(runes)), poses[i], &lengths[i])
+		result.AppendString(string(runes[begin:end]))
+	}
+	return nil
+}
+
+// vecEvalLocate is the vectorized version of LOCATE, locate finds the 1-based
+// position of the sub string from the 0-based start position.
+func (b *baseBuiltinFunc) vecEvalLocate(input *chunk.Chunk, result *chunk.Column, locate func(subStr, str string, pos int64) int64) error {
+	n := input.NumRows()
+	subStrBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(subStrBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, subStrBuf); err != nil {
+		return err
+	}
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(subStrBuf, strBuf)
+	var poses []int64
+	if len(b.args) == 3 {
+		posBuf, err := b.bufAllocator.get(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(posBuf)
+		if err := b.args[2].VecEvalInt(b.ctx, input, posBuf); err != nil {
+			return err
+		}
+		result.MergeNulls(posBuf)
+		poses = posBuf.Int64s()
+	}
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		var pos int64
+		if poses != nil {
+			// Transfer the argument which starts from 1 to real index which starts from 0.
+			pos = poses[i] - 1
+		}
+		i64s[i] = locate(subStrBuf.GetString(i), strBuf.GetString(i), pos)
+	}
+	return nil
+}
+
+func (b *builtinLocate2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate2ArgsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalLocate(input, result, locateBytes)
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate2ArgsUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	_, ci := PatternMatchCollation(b.args[0], b.args[1])
+	return b.vecEvalLocate(input, result, func(subStr, str string, pos int64) int64 {
+		return locateRunes(subStr, str, pos, ci)
+	})
+}
+
+func (b *builtinLocate3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate3ArgsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalLocate(input, result, locateBytes)
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLocate3ArgsUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	_, ci := PatternMatchCollation(b.args[0], b.args[1])
+	return b.vecEvalLocate(input, result, func(subStr, str string, pos int64) int64 {
+		return locateRunes(subStr, str, pos, ci)
+	})
+}
+
+func (b *builtinReplaceSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals a builtinReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for j, arg := range b.args {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[j] = buf
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) || bufs[1].IsNull(i) || bufs[2].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, oldStr, newStr := bufs[0].GetString(i), bufs[1].GetString(i), bufs[2].GetString(i)
+		if oldStr == "" {
+			result.AppendString(str)
+			continue
+		}
+		result.AppendString(strings.Replace(str, oldStr, newStr, -1))
+	}
+	return nil
+}
+
+// vecEvalStringMap applies f to every non-NULL value of the first string
+// argument.
+func (b *baseBuiltinFunc) vecEvalStringMap(input *chunk.Chunk, result *chunk.Column, f func(string) string) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(f(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinTrim1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim1ArgSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalStringMap(input, result, func(str string) string {
+		return strings.Trim(str, spaceChars)
+	})
+}
+
+func (b *builtinTrim2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim2ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	remBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(remBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, remBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || remBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		remstr := remBuf.GetString(i)
+		result.AppendString(trimRight(trimLeft(strBuf.GetString(i), remstr), remstr))
+	}
+	return nil
+}
+
+func (b *builtinTrim3ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTrim3ArgsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	remBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(remBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, remBuf); err != nil {
+		return err
+	}
+	dirBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(dirBuf)
+	if err := b.args[2].VecEvalInt(b.ctx, input, dirBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	directions := dirBuf.Int64s()
+	for i := 0; i < n; i++ {
+		// A NULL remstr means to trim spaces.
+		if strBuf.IsNull(i) || dirBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		isRemStrNull := remBuf.IsNull(i)
+		var remstr string
+		if !isRemStrNull {
+			remstr = remBuf.GetString(i)
+		}
+		result.AppendString(trimByDirection(strBuf.GetString(i), remstr, isRemStrNull, ast.TrimDirectionType(directions[i])))
+	}
+	return nil
+}
+
+func (b *builtinLTrimSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLTrimSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalStringMap(input, result, func(str string) string {
+		return strings.TrimLeft(str, spaceChars)
+	})
+}
+
+func (b *builtinRTrimSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRTrimSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalStringMap(input, result, func(str string) string {
+		return strings.TrimRight(str, spaceChars)
+	})
+}
+
+func (b *builtinUpperSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUpperSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalString(b.ctx, input, result)
+}
+
+func (b *builtinUpperUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUpperUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalStringMap(input, result, strings.ToUpper)
+}
+
+func (b *builtinLowerSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLowerSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	if types.IsBinaryStr(b.args[0].GetType()) {
+		return b.args[0].VecEvalString(b.ctx, input, result)
+	}
+	return b.vecEvalStringMap(input, result, strings.ToLower)
+}
+
+// vecEvalPad is the vectorized version of LPAD and RPAD. pad pads the string
+// to the target length and returns isNull if the result is NULL, and
+// maxCharBytes is the max number of bytes of a character in the result.
+func (b *baseBuiltinFunc) vecEvalPad(input *chunk.Chunk, result *chunk.Column, funcName string, maxAllowedPacket, maxCharBytes uint64,
+	pad func(str, padStr string, targetLength int64) (string, bool)) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+	padBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(padBuf)
+	if err := b.args[2].VecEvalString(b.ctx, input, padBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	lengths := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if lengths[i] > 0 && uint64(lengths[i])*maxCharBytes > maxAllowedPacket {
+			if err := handleAllowedPacketOverflowed(b.ctx, funcName, maxAllowedPacket); err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		if padBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, isNull := pad(strBuf.GetString(i), padBuf.GetString(i), lengths[i])
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinLpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalPad(input, result, "lpad", b.maxAllowedPacket, 1, func(str, padStr string, targetLength int64) (string, bool) {
+		return padBytes(str, padStr, targetLength, true)
+	})
+}
+
+func (b *builtinLpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalPad(input, result, "lpad", b.maxAllowedPacket, mysql.MaxBytesOfCharacter, func(str, padStr string, targetLength int64) (string, bool) {
+		return padRunes(str, padStr, targetLength, true)
+	})
+}
+
+func (b *builtinRpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalPad(input, result, "rpad", b.maxAllowedPacket, 1, func(str, padStr string, targetLength int64) (string, bool) {
+		return padBytes(str, padStr, targetLength, false)
+	})
+}
+
+func (b *builtinRpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalPad(input, result, "rpad", b.maxAllowedPacket, mysql.MaxBytesOfCharacter, func(str, padStr string, targetLength int64) (string, bool) {
+		return padRunes(str, padStr, targetLength, false)
+	})
+}
+
+// vecEvalLeftOrRight is the vectorized version of LEFT and RIGHT, sub gets the
+// sub string of the length.
+func (b *baseBuiltinFunc) vecEvalLeftOrRight(input *chunk.Chunk, result *chunk.Column, sub func(str string, length int64) string) error {
+	strBuf, intBufs, err := b.vecEvalStringIntArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putStringIntArgBufs(strBuf, intBufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	lengths := intBufs[0].Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || intBufs[0].IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(sub(strBuf.GetString(i), lengths[i]))
+	}
+	return nil
+}
+
+func (b *builtinLeftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalLeftOrRight(input, result, func(str string, length int64) string {
+		return str[:clampLength(length, len(str))]
+	})
+}
+
+func (b *builtinLeftUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalLeftOrRight(input, result, func(str string, length int64) string {
+		runes := []rune(str)
+		return string(runes[:clampLength(length, len(runes))])
+	})
+}
+
+func (b *builtinRightSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalLeftOrRight(input, result, func(str string, length int64) string {
+		return str[len(str)-clampLength(length, len(str)):]
+	})
+}
+
+func (b *builtinRightUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalLeftOrRight(input, result, func(str string, length int64) string {
+		runes := []rune(str)
+		return string(runes[len(runes)-clampLength(length, len(runes)):])
+	})
+}
+
+func (b *builtinReverseSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReverseSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalStringMap(input, result, func(str string) string {
+		return string(reverseBytes([]byte(str)))
+	})
+}
+
+func (b *builtinReverseUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReverseUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalStringMap(input, result, func(str string) string {
+		return string(reverseRunes([]rune(str)))
+	})
+}
@@ -18,9 +18,14 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 )
 
+var binaryStrFieldType = &types.FieldType{Tp: mysql.TypeVarString, Flen: types.UnspecifiedLength, Flag: mysql.BinaryFlag,
+	Charset: charset.CharsetBin, Collate: charset.CollationBin}
+
 var vecBuiltinStringCases = map[string][]vecExprBenchCase{
 	ast.Length: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&defaultGener{0.2, types.ETString}}},
@@ -44,6 +49,107 @@ var vecBuiltinStringCases = map[string][]vecExprBenchCase{
 			},
 		}},
 	},
+	ast.Concat: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType, binaryStrFieldType}},
+	},
+	ast.ConcatWS: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{",", "--", ""}}}},
+	},
+	ast.Substring: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-25, 25}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			geners: []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-25, 25}, &rangeInt64Gener{-25, 25}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"中文字符串", "αβγ", "abc"}}, &rangeInt64Gener{-6, 6}, &rangeInt64Gener{-1, 6}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType},
+			geners:             []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-25, 25}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType},
+			geners:             []dataGenerator{&randLenStrGener{0, 20}, &rangeInt64Gener{-25, 25}, &rangeInt64Gener{-25, 25}}},
+	},
+	ast.Locate: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"a", "bc", "", "中"}}, &selectStringGener{candidates: []string{"abcabc", "中文abc", "xyz"}}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"a", "bc", "", "中"}}, &selectStringGener{candidates: []string{"abcabc", "中文abc", "xyz"}}, &rangeInt64Gener{-2, 8}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType, binaryStrFieldType},
+			geners:             []dataGenerator{&selectStringGener{candidates: []string{"a", "bc", "", "中"}}, &selectStringGener{candidates: []string{"abcabc", "中文abc", "xyz"}}}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType, binaryStrFieldType},
+			geners:             []dataGenerator{&selectStringGener{candidates: []string{"a", "bc", "", "中"}}, &selectStringGener{candidates: []string{"abcabc", "中文abc", "xyz"}}, &rangeInt64Gener{-2, 8}}},
+	},
+	ast.Replace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"aaa", "abcabc", ""}}, &selectStringGener{candidates: []string{"a", "bc", ""}}, &randLenStrGener{0, 5}}},
+	},
+	ast.Trim: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"  abc  ", "abc", "   ", ""}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"xxabcxx", "xabcxxx", "xx", ""}}, &selectStringGener{candidates: []string{"x", "xx", ""}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"xxabcxx", "  abc  ", ""}}, &selectStringGener{candidates: []string{"x", "xx", ""}}, &rangeInt64Gener{0, 4}}},
+	},
+	ast.LTrim: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"  abc  ", "abc", "   ", ""}}}},
+	},
+	ast.RTrim: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"  abc  ", "abc", "   ", ""}}}},
+	},
+	ast.Upper: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType}},
+	},
+	ast.Lower: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType}},
+	},
+	ast.Lpad: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			geners: []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 20}, &selectStringGener{candidates: []string{"x", "中文", ""}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType, nil, binaryStrFieldType},
+			geners:             []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 20}, &selectStringGener{candidates: []string{"x", "中文", ""}}}},
+	},
+	ast.Rpad: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			geners: []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 20}, &selectStringGener{candidates: []string{"x", "中文", ""}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType, nil, binaryStrFieldType},
+			geners:             []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-5, 20}, &selectStringGener{candidates: []string{"x", "中文", ""}}}},
+	},
+	ast.Left: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"中文字符串", "abc", ""}}, &rangeInt64Gener{-2, 10}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType},
+			geners:             []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-2, 15}}},
+	},
+	ast.Right: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"中文字符串", "abc", ""}}, &rangeInt64Gener{-2, 10}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType},
+			geners:             []dataGenerator{&randLenStrGener{0, 10}, &rangeInt64Gener{-2, 15}}},
+	},
+	ast.Reverse: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{&selectStringGener{candidates: []string{"中文字符串", "abc", ""}}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			childrenFieldTypes: []*types.FieldType{binaryStrFieldType}},
+	},
 }
 
 func (s *testEvaluatorSuite) TestVectorizedBuiltinStringEvalOneVec(c *C) {
@@ -0,0 +1,1311 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &nowFunctionClass{}
+	_ functionClass = &sysDateFunctionClass{}
+	_ functionClass = &currentDateFunctionClass{}
+	_ functionClass = &currentTimeFunctionClass{}
+	_ functionClass = &utcDateFunctionClass{}
+	_ functionClass = &utcTimeFunctionClass{}
+	_ functionClass = &utcTimestampFunctionClass{}
+	_ functionClass = &dateArithFunctionClass{}
+	_ functionClass = &dateFormatFunctionClass{}
+	_ functionClass = &unixTimestampFunctionClass{}
+	_ functionClass = &fromUnixTimeFunctionClass{}
+	_ functionClass = &extractFunctionClass{}
+	_ functionClass = &dateFunctionClass{}
+	_ functionClass = &yearFunctionClass{}
+	_ functionClass = &monthFunctionClass{}
+	_ functionClass = &dayOfMonthFunctionClass{}
+	_ functionClass = &hourFunctionClass{}
+	_ functionClass = &minuteFunctionClass{}
+	_ functionClass = &secondFunctionClass{}
+	_ functionClass = &microSecondFunctionClass{}
+	_ functionClass = &dateDiffFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinNowSig{}
+	_ builtinFunc = &builtinSysDateSig{}
+	_ builtinFunc = &builtinCurrentDateSig{}
+	_ builtinFunc = &builtinCurrentTimeSig{}
+	_ builtinFunc = &builtinUTCDateSig{}
+	_ builtinFunc = &builtinUTCTimeSig{}
+	_ builtinFunc = &builtinUTCTimestampSig{}
+	_ builtinFunc = &builtinDateArithDatetimeSig{}
+	_ builtinFunc = &builtinDateArithDurationSig{}
+	_ builtinFunc = &builtinDateFormatSig{}
+	_ builtinFunc = &builtinUnixTimestampCurrentSig{}
+	_ builtinFunc = &builtinUnixTimestampIntSig{}
+	_ builtinFunc = &builtinUnixTimestampDecSig{}
+	_ builtinFunc = &builtinFromUnixTime1ArgSig{}
+	_ builtinFunc = &builtinFromUnixTime2ArgSig{}
+	_ builtinFunc = &builtinExtractDatetimeSig{}
+	_ builtinFunc = &builtinExtractDurationSig{}
+	_ builtinFunc = &builtinDateSig{}
+	_ builtinFunc = &builtinYearSig{}
+	_ builtinFunc = &builtinMonthSig{}
+	_ builtinFunc = &builtinDayOfMonthSig{}
+	_ builtinFunc = &builtinHourSig{}
+	_ builtinFunc = &builtinMinuteSig{}
+	_ builtinFunc = &builtinSecondSig{}
+	_ builtinFunc = &builtinMicroSecondSig{}
+	_ builtinFunc = &builtinDateDiffSig{}
+)
+
+// handleInvalidTimeError reports error or warning depend on the context.
+func handleInvalidTimeError(ctx sessionctx.Context, err error) error {
+	if err == nil || !(types.ErrWrongValue.Equal(err) ||
+		types.ErrTruncatedWrongVal.Equal(err) || types.ErrInvalidWeekModeFormat.Equal(err) ||
+		types.ErrInvalidTimeFormat.Equal(err)) {
+		return err
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	err = sc.HandleTruncate(err)
+	if ctx.GetSessionVars().StrictSQLMode && (sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt) {
+		return err
+	}
+	return nil
+}
+
+// getFspFromArgs returns the fractional seconds precision given by the optional
+// argument of the current time functions, such as NOW(3) or CURTIME(6).
+func getFspFromArgs(ctx sessionctx.Context, funcName string, args []Expression) (int8, error) {
+	if len(args) == 0 {
+		return types.DefaultFsp, nil
+	}
+	fsp, isNull, err := args[0].EvalInt(ctx, chunk.Row{})
+	if err != nil {
+		return 0, err
+	}
+	if isNull || fsp < 0 {
+		return 0, ErrIncorrectType.GenWithStackByArgs("fsp", funcName)
+	}
+	if fsp > int64(types.MaxFsp) {
+		return 0, errTooBigPrecision.GenWithStackByArgs(fsp, funcName, types.MaxFsp)
+	}
+	return int8(fsp), nil
+}
+
+// setTimeFieldLen sets the display length of a DATETIME or TIME result for the given fsp.
+func setTimeFieldLen(tp *types.FieldType, fsp int8) {
+	tp.Decimal = int(fsp)
+	if tp.Tp == mysql.TypeDuration {
+		tp.Flen = mysql.MaxDurationWidthNoFsp
+	} else {
+		tp.Flen = mysql.MaxDatetimeWidthNoFsp
+	}
+	if fsp > 0 {
+		tp.Flen += 1 + int(fsp)
+	}
+}
+
+// convertTimeToMysqlTime converts a go time to a DATETIME value with the given fsp.
+func convertTimeToMysqlTime(ctx sessionctx.Context, t time.Time, fsp int8) (types.Time, error) {
+	tr := types.NewTime(types.FromGoTime(t), mysql.TypeDatetime, types.MaxFsp)
+	return tr.RoundFrac(ctx.GetSessionVars().StmtCtx, fsp)
+}
+
+// convertTimeToDuration converts the clock part of a go time to a TIME value with the given fsp.
+func convertTimeToDuration(ctx sessionctx.Context, t time.Time, fsp int8) (types.Duration, error) {
+	return types.ParseDuration(ctx.GetSessionVars().StmtCtx, t.Format("15:04:05.000000"), fsp)
+}
+
+// convertTimeToDate returns the date part of a go time as a DATE value.
+func convertTimeToDate(t time.Time) types.Time {
+	year, month, day := t.Date()
+	return types.NewTime(types.FromDate(year, int(month), day, 0, 0, 0, 0), mysql.TypeDate, 0)
+}
+
+type nowFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *nowFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspFromArgs(ctx, c.funcName, args)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	if len(args) == 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, argTps...)
+	setTimeFieldLen(bf.tp, fsp)
+	sig := &builtinNowSig{bf}
+	if len(args) == 1 {
+		sig.setPbCode(tipb.ScalarFuncSig_NowWithArg)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_NowWithoutArg)
+	}
+	return sig, nil
+}
+
+type builtinNowSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNowSig) Clone() builtinFunc {
+	newSig := &builtinNowSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals NOW(), CURRENT_TIMESTAMP(), LOCALTIME() and LOCALTIMESTAMP().
+// The value is fixed when the statement starts to execute, and is returned in the session time zone.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
+func (b *builtinNowSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	vars := b.ctx.GetSessionVars()
+	nowTs := vars.StmtCtx.GetNowTsCached().In(vars.Location())
+	t, err := convertTimeToMysqlTime(b.ctx, nowTs, int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return t, false, nil
+}
+
+type sysDateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sysDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspFromArgs(ctx, c.funcName, args)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	if len(args) == 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, argTps...)
+	setTimeFieldLen(bf.tp, fsp)
+	sig := &builtinSysDateSig{bf}
+	if len(args) == 1 {
+		sig.setPbCode(tipb.ScalarFuncSig_SysDateWithFsp)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_SysDateWithoutFsp)
+	}
+	return sig, nil
+}
+
+type builtinSysDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSysDateSig) Clone() builtinFunc {
+	newSig := &builtinSysDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals SYSDATE(), unlike NOW() it returns the time at which it executes.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_sysdate
+func (b *builtinSysDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	t, err := convertTimeToMysqlTime(b.ctx, time.Now().In(b.ctx.GetSessionVars().Location()), int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return t, false, nil
+}
+
+type currentDateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+	bf.tp.Tp, bf.tp.Flen, bf.tp.Decimal = mysql.TypeDate, mysql.MaxDateWidth, 0
+	sig := &builtinCurrentDateSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_CurrentDate)
+	return sig, nil
+}
+
+type builtinCurrentDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentDateSig) Clone() builtinFunc {
+	newSig := &builtinCurrentDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals CURDATE().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curdate
+func (b *builtinCurrentDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	vars := b.ctx.GetSessionVars()
+	return convertTimeToDate(vars.StmtCtx.GetNowTsCached().In(vars.Location())), false, nil
+}
+
+type currentTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspFromArgs(ctx, c.funcName, args)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	if len(args) == 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, argTps...)
+	setTimeFieldLen(bf.tp, fsp)
+	sig := &builtinCurrentTimeSig{bf}
+	if len(args) == 1 {
+		sig.setPbCode(tipb.ScalarFuncSig_CurrentTime1Arg)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_CurrentTime0Arg)
+	}
+	return sig, nil
+}
+
+type builtinCurrentTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentTimeSig) Clone() builtinFunc {
+	newSig := &builtinCurrentTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals CURTIME().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curtime
+func (b *builtinCurrentTimeSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	vars := b.ctx.GetSessionVars()
+	res, err := convertTimeToDuration(b.ctx, vars.StmtCtx.GetNowTsCached().In(vars.Location()), int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroDuration, true, err
+	}
+	return res, false, nil
+}
+
+type utcDateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime)
+	bf.tp.Tp, bf.tp.Flen, bf.tp.Decimal = mysql.TypeDate, mysql.MaxDateWidth, 0
+	sig := &builtinUTCDateSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UTCDate)
+	return sig, nil
+}
+
+type builtinUTCDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCDateSig) Clone() builtinFunc {
+	newSig := &builtinUTCDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals UTC_DATE, UTC_DATE().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-date
+func (b *builtinUTCDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return convertTimeToDate(b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().UTC()), false, nil
+}
+
+type utcTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspFromArgs(ctx, c.funcName, args)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	if len(args) == 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, argTps...)
+	setTimeFieldLen(bf.tp, fsp)
+	sig := &builtinUTCTimeSig{bf}
+	if len(args) == 1 {
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTimeWithArg)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTimeWithoutArg)
+	}
+	return sig, nil
+}
+
+type builtinUTCTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTimeSig) Clone() builtinFunc {
+	newSig := &builtinUTCTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals UTC_TIME, UTC_TIME([fsp]).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-time
+func (b *builtinUTCTimeSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	res, err := convertTimeToDuration(b.ctx, b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().UTC(), int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroDuration, true, err
+	}
+	return res, false, nil
+}
+
+type utcTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *utcTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getFspFromArgs(ctx, c.funcName, args)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	if len(args) == 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, argTps...)
+	setTimeFieldLen(bf.tp, fsp)
+	sig := &builtinUTCTimestampSig{bf}
+	if len(args) == 1 {
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTimestampWithArg)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_UTCTimestampWithoutArg)
+	}
+	return sig, nil
+}
+
+type builtinUTCTimestampSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUTCTimestampSig) Clone() builtinFunc {
+	newSig := &builtinUTCTimestampSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals UTC_TIMESTAMP, UTC_TIMESTAMP([fsp]).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_utc-timestamp
+func (b *builtinUTCTimestampSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	t, err := convertTimeToMysqlTime(b.ctx, b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().UTC(), int8(b.tp.Decimal))
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return t, false, nil
+}
+
+// dateArithFunctionClass is the function class of DATE_ADD, DATE_SUB, ADDDATE and SUBDATE.
+// The arguments are the date, the interval value and the interval unit.
+type dateArithFunctionClass struct {
+	baseFunctionClass
+
+	isSub bool
+}
+
+func (c *dateArithFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	unit, _, err := args[2].EvalString(ctx, chunk.Row{})
+	if err != nil {
+		return nil, err
+	}
+	dateTp := args[0].GetType()
+	if dateTp.Tp == mysql.TypeDuration && types.IsClockUnit(unit) {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDuration, types.ETDuration, types.ETString, types.ETString)
+		sig := &builtinDateArithDurationSig{bf, c.isSub}
+		if c.isSub {
+			sig.setPbCode(tipb.ScalarFuncSig_SubDateDurationString)
+		} else {
+			sig.setPbCode(tipb.ScalarFuncSig_AddDateDurationString)
+		}
+		return sig, nil
+	}
+	// Strings are parsed by the function itself to keep the fsp they carry.
+	dateEvalTp := types.ETDatetime
+	if dateTp.EvalType() == types.ETString {
+		dateEvalTp = types.ETString
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, dateEvalTp, types.ETString, types.ETString)
+	if dateTp.Tp == mysql.TypeDate && !types.IsClockUnit(unit) {
+		bf.tp.Tp, bf.tp.Flen, bf.tp.Decimal = mysql.TypeDate, mysql.MaxDateWidth, 0
+	}
+	sig := &builtinDateArithDatetimeSig{bf, c.isSub}
+	if c.isSub {
+		sig.setPbCode(tipb.ScalarFuncSig_SubDateDatetimeString)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_AddDateDatetimeString)
+	}
+	return sig, nil
+}
+
+// evalInterval evaluates the interval value and the unit of a date arithmetic function,
+// and returns the years, months, days and nanoseconds to be added.
+func evalInterval(ctx sessionctx.Context, args []Expression, row chunk.Row, isSub bool) (y, m, d, n int64, isNull bool, err error) {
+	interval, isNull, err := args[1].EvalString(ctx, row)
+	if isNull || err != nil {
+		return 0, 0, 0, 0, true, err
+	}
+	unit, isNull, err := args[2].EvalString(ctx, row)
+	if isNull || err != nil {
+		return 0, 0, 0, 0, true, err
+	}
+	y, m, d, n, err = types.ParseDurationValue(unit, interval)
+	if err != nil {
+		return 0, 0, 0, 0, true, handleInvalidTimeError(ctx, err)
+	}
+	if isSub {
+		y, m, d, n = -y, -m, -d, -n
+	}
+	return y, m, d, n, false, nil
+}
+
+type builtinDateArithDatetimeSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinDateArithDatetimeSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinDateArithDatetimeSig) evalDate(row chunk.Row) (types.Time, bool, error) {
+	if b.args[0].GetType().EvalType() != types.ETString {
+		return b.args[0].EvalTime(b.ctx, row)
+	}
+	dateStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	date, err := types.ParseTime(b.ctx.GetSessionVars().StmtCtx, dateStr, mysql.TypeDatetime, types.GetFsp(dateStr))
+	return date, err != nil, err
+}
+
+// evalTime evals DATE_ADD(date,INTERVAL expr unit) and DATE_SUB(date,INTERVAL expr unit).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithDatetimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	date, isNull, err := b.evalDate(row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	y, m, d, n, isNull, err := evalInterval(b.ctx, b.args, row, b.isSub)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	goTime, err := date.GoTime(time.UTC)
+	if err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	goTime = types.AddDate(y, m, d, goTime.Add(time.Duration(n)))
+	if goTime.Year() < 0 || goTime.Year() > 9999 {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(types.ErrDatetimeFunctionOverflow.GenWithStackByArgs("datetime"))
+		return types.ZeroTime, true, nil
+	}
+	fsp := date.Fsp()
+	if n%int64(time.Second) != 0 {
+		fsp = types.MaxFsp
+	}
+	return types.NewTime(types.FromGoTime(goTime), b.tp.Tp, fsp), false, nil
+}
+
+type builtinDateArithDurationSig struct {
+	baseBuiltinFunc
+
+	isSub bool
+}
+
+func (b *builtinDateArithDurationSig) Clone() builtinFunc {
+	newSig := &builtinDateArithDurationSig{isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDuration evals DATE_ADD(time,INTERVAL expr unit) and DATE_SUB(time,INTERVAL expr unit) for TIME values.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinDateArithDurationSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	dur, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroDuration, true, err
+	}
+	_, _, d, n, isNull, err := evalInterval(b.ctx, b.args, row, b.isSub)
+	if isNull || err != nil {
+		return types.ZeroDuration, true, err
+	}
+	delta := time.Duration(n) + time.Duration(d)*24*time.Hour
+	fsp := dur.Fsp
+	if delta%time.Second != 0 {
+		fsp = types.MaxFsp
+	}
+	res, err := dur.Add(types.Duration{Duration: delta, Fsp: fsp})
+	if err != nil {
+		return types.ZeroDuration, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res.Fsp = fsp
+	return res, false, nil
+}
+
+type dateFormatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateFormatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDatetime, types.ETString)
+	// worst case: formatMask=%r%r%r...%r, each %r takes 11 characters
+	bf.tp.Flen = (args[1].GetType().Flen + 1) / 2 * 11
+	sig := &builtinDateFormatSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DateFormatSig)
+	return sig, nil
+}
+
+type builtinDateFormatSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateFormatSig) Clone() builtinFunc {
+	newSig := &builtinDateFormatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinDateFormatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (b *builtinDateFormatSig) evalString(row chunk.Row) (string, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, handleInvalidTimeError(b.ctx, err)
+	}
+	formatMask, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if t.InvalidZero() {
+		return "", true, handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String()))
+	}
+	res, err := t.DateFormat(formatMask)
+	return res, isNull, err
+}
+
+type unixTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+// getUnixTimestampFsp returns the fsp of the result of UNIX_TIMESTAMP(arg),
+// which is decided by the fractional seconds the argument may carry.
+func getUnixTimestampFsp(arg Expression) int8 {
+	argTp := arg.GetType()
+	switch argTp.EvalType() {
+	case types.ETInt:
+		return 0
+	case types.ETString:
+		if c, ok := arg.(*Constant); ok && c.Value.Kind() == types.KindString {
+			return types.GetFsp(c.Value.GetString())
+		}
+		return types.MaxFsp
+	}
+	if argTp.Decimal < 0 || argTp.Decimal > int(types.MaxFsp) {
+		return types.MaxFsp
+	}
+	return int8(argTp.Decimal)
+}
+
+func (c *unixTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+		bf.tp.Flen = 11
+		sig := &builtinUnixTimestampCurrentSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampCurrent)
+		return sig, nil
+	}
+	fsp := getUnixTimestampFsp(args[0])
+	if fsp == 0 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+		bf.tp.Flen = 11
+		sig := &builtinUnixTimestampIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampInt)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDatetime)
+	bf.tp.Flen, bf.tp.Decimal = 12+int(fsp), int(fsp)
+	sig := &builtinUnixTimestampDecSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_UnixTimestampDec)
+	return sig, nil
+}
+
+// goTimeToMysqlUnixTimestamp converts a go time into the seconds since '1970-01-01 00:00:00' UTC,
+// the value out of the range of a 32-bit timestamp is converted to 0.
+func goTimeToMysqlUnixTimestamp(t time.Time, decimal int) (*types.MyDecimal, error) {
+	nanoSeconds := t.UnixNano()
+	if nanoSeconds < 0 || (nanoSeconds/1e3) >= (math.MaxInt32+1)*1e6 {
+		return new(types.MyDecimal), nil
+	}
+	dec := new(types.MyDecimal)
+	// Don't use float here to prevent precision loss.
+	dec.FromInt(nanoSeconds)
+	if err := dec.Shift(-9); err != nil {
+		return nil, err
+	}
+	// MySQL truncates the result instead of rounding it.
+	err := dec.Round(dec, decimal, types.ModeTruncate)
+	return dec, err
+}
+
+// evalUnixTimestamp evaluates the timestamp of a DATETIME value in the session time zone.
+func evalUnixTimestamp(ctx sessionctx.Context, arg Expression, row chunk.Row, decimal int) (*types.MyDecimal, bool, error) {
+	val, isNull, err := arg.EvalTime(ctx, row)
+	if err != nil && terror.ErrorEqual(types.ErrWrongValue.GenWithStackByArgs(types.TimeStr, val), err) {
+		// Return 0 for invalid date time.
+		return new(types.MyDecimal), false, nil
+	}
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	t, err := val.GoTime(ctx.GetSessionVars().Location())
+	if err != nil {
+		return new(types.MyDecimal), false, nil
+	}
+	dec, err := goTimeToMysqlUnixTimestamp(t, decimal)
+	if err != nil {
+		return nil, true, err
+	}
+	return dec, false, nil
+}
+
+type builtinUnixTimestampCurrentSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampCurrentSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampCurrentSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a UNIX_TIMESTAMP().
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampCurrentSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix(), false, nil
+}
+
+type builtinUnixTimestampIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampIntSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a UNIX_TIMESTAMP(time).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dec, isNull, err := evalUnixTimestamp(b.ctx, b.args[0], row, 0)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	intVal, err := dec.ToInt()
+	return intVal, false, err
+}
+
+type builtinUnixTimestampDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampDecSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals a UNIX_TIMESTAMP(time) whose argument has fractional seconds.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	return evalUnixTimestamp(b.ctx, b.args[0], row, b.tp.Decimal)
+}
+
+type fromUnixTimeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fromUnixTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp := types.MaxFsp
+	argTp := args[0].GetType()
+	if argTp.EvalType() == types.ETInt {
+		fsp = 0
+	} else if argTp.EvalType() != types.ETString && argTp.Decimal >= 0 && argTp.Decimal < int(types.MaxFsp) {
+		fsp = int8(argTp.Decimal)
+	}
+	if len(args) == 1 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDecimal)
+		setTimeFieldLen(bf.tp, fsp)
+		sig := &builtinFromUnixTime1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_FromUnixTime1Arg)
+		return sig, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDecimal, types.ETString)
+	bf.tp.Flen = (args[1].GetType().Flen + 1) / 2 * 11
+	sig := &builtinFromUnixTime2ArgSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_FromUnixTime2Arg)
+	return sig, nil
+}
+
+// evalFromUnixTime converts the seconds since '1970-01-01 00:00:00' UTC to a DATETIME value
+// in the session time zone.
+func evalFromUnixTime(ctx sessionctx.Context, fsp int8, arg Expression, row chunk.Row) (types.Time, bool, error) {
+	unixTimeStamp, isNull, err := arg.EvalDecimal(ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	// 0 <= unixTimeStamp <= INT32_MAX
+	if unixTimeStamp.IsNegative() {
+		return types.ZeroTime, true, nil
+	}
+	integralPart, err := unixTimeStamp.ToInt()
+	if err != nil && !terror.ErrorEqual(err, types.ErrTruncated) {
+		return types.ZeroTime, true, err
+	}
+	if integralPart > int64(math.MaxInt32) {
+		return types.ZeroTime, true, nil
+	}
+	// Split the integral part and fractional part of a decimal timestamp.
+	// e.g. for timestamp 12345.678,
+	// first get the integral part 12345,
+	// then (12345.678 - 12345) * (10^9) to get the decimal part and convert it to nanosecond precision.
+	fracDecimal := new(types.MyDecimal)
+	if err = types.DecimalSub(unixTimeStamp, new(types.MyDecimal).FromInt(integralPart), fracDecimal); err != nil {
+		return types.ZeroTime, true, err
+	}
+	nanoDecimal := new(types.MyDecimal)
+	if err = types.DecimalMul(fracDecimal, new(types.MyDecimal).FromInt(int64(time.Second)), nanoDecimal); err != nil {
+		return types.ZeroTime, true, err
+	}
+	fractionalPart, err := nanoDecimal.ToInt()
+	if err != nil && !terror.ErrorEqual(err, types.ErrTruncated) {
+		return types.ZeroTime, true, err
+	}
+	tm := time.Unix(integralPart, fractionalPart).In(ctx.GetSessionVars().Location())
+	t, err := convertTimeToMysqlTime(ctx, tm, fsp)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return t, false, nil
+}
+
+type builtinFromUnixTime1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFromUnixTime1ArgSig) Clone() builtinFunc {
+	newSig := &builtinFromUnixTime1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals a builtinFromUnixTime1ArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func (b *builtinFromUnixTime1ArgSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return evalFromUnixTime(b.ctx, int8(b.tp.Decimal), b.args[0], row)
+}
+
+type builtinFromUnixTime2ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFromUnixTime2ArgSig) Clone() builtinFunc {
+	newSig := &builtinFromUnixTime2ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinFromUnixTime2ArgSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_from-unixtime
+func (b *builtinFromUnixTime2ArgSig) evalString(row chunk.Row) (string, bool, error) {
+	format, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	t, isNull, err := evalFromUnixTime(b.ctx, types.MaxFsp, b.args[0], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, err := t.DateFormat(format)
+	return res, err != nil, err
+}
+
+type extractFunctionClass struct {
+	baseFunctionClass
+}
+
+// isDatetimeUnit returns true if the unit of EXTRACT needs the date part of its argument.
+func isDatetimeUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "DAY", "WEEK", "MONTH", "QUARTER", "YEAR",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR", "YEAR_MONTH":
+		return true
+	}
+	return false
+}
+
+func (c *extractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	unit, _, err := args[0].EvalString(ctx, chunk.Row{})
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if isDatetimeUnit(unit) {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETDatetime)
+		sig = &builtinExtractDatetimeSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ExtractDatetime)
+	} else {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETDuration)
+		sig = &builtinExtractDurationSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_ExtractDuration)
+	}
+	return sig, nil
+}
+
+type builtinExtractDatetimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExtractDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinExtractDatetimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinExtractDatetimeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func (b *builtinExtractDatetimeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	dt, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	res, err := types.ExtractDatetimeNum(&dt, unit)
+	return res, err != nil, err
+}
+
+type builtinExtractDurationSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinExtractDurationSig) Clone() builtinFunc {
+	newSig := &builtinExtractDurationSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinExtractDurationSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_extract
+func (b *builtinExtractDurationSig) evalInt(row chunk.Row) (int64, bool, error) {
+	unit, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	dur, isNull, err := b.args[1].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := types.ExtractDurationNum(&dur, unit)
+	return res, err != nil, err
+}
+
+type dateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDatetime)
+	bf.tp.Tp, bf.tp.Flen, bf.tp.Decimal = mysql.TypeDate, mysql.MaxDateWidth, 0
+	sig := &builtinDateSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Date)
+	return sig, nil
+}
+
+type builtinDateSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateSig) Clone() builtinFunc {
+	newSig := &builtinDateSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals DATE(expr).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date
+func (b *builtinDateSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	expr, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if expr.IsZero() {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, expr.String()))
+	}
+	return types.NewTime(types.FromDate(expr.Year(), expr.Month(), expr.Day(), 0, 0, 0, 0), mysql.TypeDate, 0), false, nil
+}
+
+type yearFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *yearFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+	bf.tp.Flen = 4
+	sig := &builtinYearSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Year)
+	return sig, nil
+}
+
+type builtinYearSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinYearSig) Clone() builtinFunc {
+	newSig := &builtinYearSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals YEAR(date).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_year
+func (b *builtinYearSig) evalInt(row chunk.Row) (int64, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return int64(date.Year()), false, nil
+}
+
+type monthFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *monthFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+	bf.tp.Flen = 2
+	sig := &builtinMonthSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Month)
+	return sig, nil
+}
+
+type builtinMonthSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMonthSig) Clone() builtinFunc {
+	newSig := &builtinMonthSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals MONTH(date).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_month
+func (b *builtinMonthSig) evalInt(row chunk.Row) (int64, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return int64(date.Month()), false, nil
+}
+
+type dayOfMonthFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dayOfMonthFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime)
+	bf.tp.Flen = 2
+	sig := &builtinDayOfMonthSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DayOfMonth)
+	return sig, nil
+}
+
+type builtinDayOfMonthSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDayOfMonthSig) Clone() builtinFunc {
+	newSig := &builtinDayOfMonthSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals DAYOFMONTH(date).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_dayofmonth
+func (b *builtinDayOfMonthSig) evalInt(row chunk.Row) (int64, bool, error) {
+	date, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	return int64(date.Day()), false, nil
+}
+
+type hourFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *hourFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDuration)
+	bf.tp.Flen, bf.tp.Decimal = 3, 0
+	sig := &builtinHourSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Hour)
+	return sig, nil
+}
+
+type builtinHourSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinHourSig) Clone() builtinFunc {
+	newSig := &builtinHourSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals HOUR(time).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_hour
+func (b *builtinHourSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dur, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(dur.Hour()), false, nil
+}
+
+type minuteFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *minuteFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDuration)
+	bf.tp.Flen, bf.tp.Decimal = 2, 0
+	sig := &builtinMinuteSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Minute)
+	return sig, nil
+}
+
+type builtinMinuteSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMinuteSig) Clone() builtinFunc {
+	newSig := &builtinMinuteSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals MINUTE(time).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_minute
+func (b *builtinMinuteSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dur, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(dur.Minute()), false, nil
+}
+
+type secondFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *secondFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDuration)
+	bf.tp.Flen, bf.tp.Decimal = 2, 0
+	sig := &builtinSecondSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Second)
+	return sig, nil
+}
+
+type builtinSecondSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSecondSig) Clone() builtinFunc {
+	newSig := &builtinSecondSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals SECOND(time).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_second
+func (b *builtinSecondSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dur, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(dur.Second()), false, nil
+}
+
+type microSecondFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *microSecondFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDuration)
+	bf.tp.Flen, bf.tp.Decimal = 6, 0
+	sig := &builtinMicroSecondSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_MicroSecond)
+	return sig, nil
+}
+
+type builtinMicroSecondSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMicroSecondSig) Clone() builtinFunc {
+	newSig := &builtinMicroSecondSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals MICROSECOND(expr).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_microsecond
+func (b *builtinMicroSecondSig) evalInt(row chunk.Row) (int64, bool, error) {
+	dur, isNull, err := b.args[0].EvalDuration(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(dur.MicroSecond()), false, nil
+}
+
+type dateDiffFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateDiffFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime, types.ETDatetime)
+	sig := &builtinDateDiffSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_DateDiff)
+	return sig, nil
+}
+
+type builtinDateDiffSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateDiffSig) Clone() builtinFunc {
+	newSig := &builtinDateDiffSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinDateDiffSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+func (b *builtinDateDiffSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lhs, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	rhs, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, handleInvalidTimeError(b.ctx, err)
+	}
+	if invalidLHS, invalidRHS := lhs.InvalidZero(), rhs.InvalidZero(); invalidLHS || invalidRHS {
+		if invalidLHS {
+			err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, lhs.String()))
+		}
+		if err == nil && invalidRHS {
+			err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, rhs.String()))
+		}
+		return 0, true, err
+	}
+	return int64(types.DateDiff(lhs.CoreTime(), rhs.CoreTime())), false, nil
+}
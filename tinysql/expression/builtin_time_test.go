@@ -0,0 +1,283 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) evalTimeFunc(c *C, funcName string, args ...interface{}) types.Datum {
+	f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants(args)...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	return d
+}
+
+func (s *testEvaluatorSuite) TestDateArith(c *C) {
+	cases := []struct {
+		fn       string
+		date     interface{}
+		interval interface{}
+		unit     string
+		expected interface{}
+	}{
+		{ast.DateAdd, "2011-11-11", 1, "DAY", "2011-11-12 00:00:00"},
+		{ast.DateAdd, "2011-11-11 10:10:10", "1:1", "HOUR_MINUTE", "2011-11-11 11:11:10"},
+		{ast.DateAdd, "2018-01-31", 1, "MONTH", "2018-02-28 00:00:00"},
+		{ast.DateAdd, "2020-02-29", 1, "YEAR", "2021-02-28 00:00:00"},
+		{ast.DateAdd, "2011-11-11 10:10:10", "1.5", "SECOND", "2011-11-11 10:10:11.500000"},
+		{ast.DateAdd, "2011-12-31 23:59:59", "1 1:1:1", "DAY_SECOND", "2012-01-02 01:01:00"},
+		{ast.DateSub, "2011-11-11", 1, "DAY", "2011-11-10 00:00:00"},
+		{ast.DateSub, "2011-11-11 10:10:10", "-1", "HOUR", "2011-11-11 11:10:10"},
+		{ast.SubDate, "2011-03-01", 1, "QUARTER", "2010-12-01 00:00:00"},
+		{ast.AddDate, "2011-11-11", 2, "WEEK", "2011-11-25 00:00:00"},
+		{ast.DateAdd, "9999-12-31", 1, "DAY", nil},
+		{ast.DateAdd, "2011-11-11", "abc", "HOUR_MINUTE", nil},
+		{ast.DateAdd, nil, 1, "DAY", nil},
+		{ast.DateAdd, "2011-11-11", nil, "DAY", nil},
+	}
+	for _, t := range cases {
+		d := s.evalTimeFunc(c, t.fn, t.date, t.interval, t.unit)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue, Commentf("%v", t))
+			continue
+		}
+		c.Assert(d.GetMysqlTime().String(), Equals, t.expected, Commentf("%v", t))
+	}
+
+	// DATE values stay DATE unless the unit has a clock part.
+	date := types.NewTime(types.FromDate(2011, 11, 11, 0, 0, 0, 0), mysql.TypeDate, 0)
+	args := []Expression{
+		&Constant{Value: types.NewTimeDatum(date), RetType: types.NewFieldType(mysql.TypeDate)},
+		&Constant{Value: types.NewIntDatum(1), RetType: types.NewFieldType(mysql.TypeLonglong)},
+		&Constant{Value: types.NewStringDatum("DAY"), RetType: types.NewFieldType(mysql.TypeVarString)},
+	}
+	f, err := newFunctionForTest(s.ctx, ast.DateAdd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(f.GetType().Tp, Equals, mysql.TypeDate)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlTime().String(), Equals, "2011-11-12")
+
+	args[2] = &Constant{Value: types.NewStringDatum("HOUR"), RetType: types.NewFieldType(mysql.TypeVarString)}
+	f, err = newFunctionForTest(s.ctx, ast.DateAdd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(f.GetType().Tp, Equals, mysql.TypeDatetime)
+	d, err = f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlTime().String(), Equals, "2011-11-11 01:00:00")
+
+	// TIME values stay TIME for the clock units.
+	dur := types.Duration{Duration: 10 * time.Hour, Fsp: 0}
+	args[0] = &Constant{Value: types.NewDurationDatum(dur), RetType: types.NewFieldType(mysql.TypeDuration)}
+	f, err = newFunctionForTest(s.ctx, ast.DateSub, args...)
+	c.Assert(err, IsNil)
+	c.Assert(f.GetType().Tp, Equals, mysql.TypeDuration)
+	d, err = f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlDuration().String(), Equals, "09:00:00")
+}
+
+func (s *testEvaluatorSuite) TestDateFormat(c *C) {
+	cases := []struct {
+		date     interface{}
+		format   interface{}
+		expected interface{}
+	}{
+		{"2010-01-07 23:12:34.12345", "%b %M %m %c %D %d %e %j %k %h %i %p %r %T %s %f %% %U %u %V %v %a %W %w %X %x %Y %y",
+			"Jan January 01 1 7th 07 7 007 23 11 12 PM 11:12:34 PM 23:12:34 34 123450 % 01 01 01 01 Thu Thursday 4 2010 2010 2010 10"},
+		{"2012-12-21", "%Y-%m-%d %H:%i:%s", "2012-12-21 00:00:00"},
+		{"0000-00-00 00:00:00", "%Y", nil},
+		{nil, "%Y", nil},
+		{"2012-12-21", nil, nil},
+	}
+	for _, t := range cases {
+		d := s.evalTimeFunc(c, ast.DateFormat, t.date, t.format)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue, Commentf("%v", t))
+			continue
+		}
+		c.Assert(d.GetString(), Equals, t.expected, Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestExtract(c *C) {
+	cases := []struct {
+		unit     string
+		date     interface{}
+		expected interface{}
+	}{
+		{"MICROSECOND", "2011-11-11 10:10:10.123456", int64(123456)},
+		{"SECOND", "2011-11-11 10:10:10.123456", int64(10)},
+		{"MINUTE", "2011-11-11 10:10:10.123456", int64(10)},
+		{"HOUR", "2011-11-11 10:10:10.123456", int64(10)},
+		{"DAY", "2011-11-11 10:10:10.123456", int64(11)},
+		{"WEEK", "2011-11-11 10:10:10.123456", int64(45)},
+		{"MONTH", "2011-11-11 10:10:10.123456", int64(11)},
+		{"QUARTER", "2011-11-11 10:10:10.123456", int64(4)},
+		{"YEAR", "2011-11-11 10:10:10.123456", int64(2011)},
+		{"SECOND_MICROSECOND", "2011-11-11 10:10:10.123456", int64(10123456)},
+		{"HOUR_MINUTE", "2011-11-11 10:10:10.123456", int64(1010)},
+		{"DAY_HOUR", "2011-11-11 10:10:10.123456", int64(1110)},
+		{"YEAR_MONTH", "2011-11-11 10:10:10.123456", int64(201111)},
+		{"DAY", nil, nil},
+	}
+	for _, t := range cases {
+		d := s.evalTimeFunc(c, ast.Extract, t.unit, t.date)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue, Commentf("%v", t))
+			continue
+		}
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestDateParts(c *C) {
+	cases := []struct {
+		fn       string
+		arg      interface{}
+		expected interface{}
+	}{
+		{ast.Year, "2011-11-11 10:10:10.123456", int64(2011)},
+		{ast.Month, "2011-11-11 10:10:10.123456", int64(11)},
+		{ast.Day, "2011-11-11 10:10:10.123456", int64(11)},
+		{ast.DayOfMonth, "2011-11-09", int64(9)},
+		{ast.Hour, "2011-11-11 10:10:10.123456", int64(10)},
+		{ast.Hour, "838:59:59", int64(838)},
+		{ast.Minute, "2011-11-11 10:11:12.123456", int64(11)},
+		{ast.Second, "2011-11-11 10:11:12.123456", int64(12)},
+		{ast.Microsecond, "2011-11-11 10:11:12.123456", int64(123456)},
+		{ast.Year, "0000-00-00", int64(0)},
+		{ast.Year, nil, nil},
+		{ast.Hour, nil, nil},
+	}
+	for _, t := range cases {
+		d := s.evalTimeFunc(c, t.fn, t.arg)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue, Commentf("%v", t))
+			continue
+		}
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("%v", t))
+	}
+
+	d := s.evalTimeFunc(c, ast.Date, "2011-11-11 10:10:10.123456")
+	c.Assert(d.GetMysqlTime().String(), Equals, "2011-11-11")
+	d = s.evalTimeFunc(c, ast.Date, "0000-00-00 00:00:00")
+	c.Assert(d.IsNull(), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestDateDiff(c *C) {
+	cases := []struct {
+		t1       interface{}
+		t2       interface{}
+		expected interface{}
+	}{
+		{"2004-05-21", "2004:01:02", int64(140)},
+		{"2004-04-21", "2000:01:02", int64(1571)},
+		{"2008-12-31 23:59:59.000001", "2008-12-30 01:01:01.000002", int64(1)},
+		{"1010-11-30 23:59:59", "2010-12-31", int64(-365274)},
+		{"1010-11-30", "2210-11-01", int64(-438262)},
+		{"0000-00-00", "2010-12-31", nil},
+		{nil, "2010-12-31", nil},
+	}
+	for _, t := range cases {
+		d := s.evalTimeFunc(c, ast.DateDiff, t.t1, t.t2)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue, Commentf("%v", t))
+			continue
+		}
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("%v", t))
+	}
+}
+
+func (s *testEvaluatorSuite) TestUnixTimestampAndFromUnixTime(c *C) {
+	vars := s.ctx.GetSessionVars()
+	originTZ := vars.TimeZone
+	defer func() {
+		vars.TimeZone = originTZ
+	}()
+
+	vars.TimeZone = time.UTC
+	d := s.evalTimeFunc(c, ast.UnixTimestamp, "1970-01-01 00:00:01")
+	c.Assert(d.GetInt64(), Equals, int64(1))
+	d = s.evalTimeFunc(c, ast.UnixTimestamp, "2015-11-13 10:20:19.012")
+	c.Assert(d.GetMysqlDecimal().String(), Equals, "1447410019.012")
+	d = s.evalTimeFunc(c, ast.UnixTimestamp, "1969-12-31 23:59:59")
+	c.Assert(d.GetInt64(), Equals, int64(0))
+	d = s.evalTimeFunc(c, ast.UnixTimestamp, nil)
+	c.Assert(d.IsNull(), IsTrue)
+	d = s.evalTimeFunc(c, ast.FromUnixTime, 1447410019)
+	c.Assert(d.GetMysqlTime().String(), Equals, "2015-11-13 10:20:19")
+	d = s.evalTimeFunc(c, ast.FromUnixTime, 1447410019, "%Y %D %M %H:%i:%s")
+	c.Assert(d.GetString(), Equals, "2015 13th November 10:20:19")
+	d = s.evalTimeFunc(c, ast.FromUnixTime, -1)
+	c.Assert(d.IsNull(), IsTrue)
+
+	// The same timestamp is shown in the session time zone.
+	vars.TimeZone = time.FixedZone("", 8*3600)
+	d = s.evalTimeFunc(c, ast.UnixTimestamp, "1970-01-01 08:00:01")
+	c.Assert(d.GetInt64(), Equals, int64(1))
+	d = s.evalTimeFunc(c, ast.FromUnixTime, 1447410019)
+	c.Assert(d.GetMysqlTime().String(), Equals, "2015-11-13 18:20:19")
+}
+
+func (s *testEvaluatorSuite) TestNow(c *C) {
+	vars := s.ctx.GetSessionVars()
+	originTZ := vars.TimeZone
+	defer func() {
+		vars.TimeZone = originTZ
+		vars.StmtCtx.ResetNowTs()
+	}()
+
+	vars.StmtCtx.ResetNowTs()
+	vars.TimeZone = time.UTC
+	now := vars.StmtCtx.GetNowTsCached().UTC()
+
+	d := s.evalTimeFunc(c, ast.Now)
+	t := d.GetMysqlTime()
+	c.Assert(t.Fsp(), Equals, int8(0))
+	c.Assert(t.Year(), Equals, now.Year())
+	c.Assert(t.Hour(), Equals, now.Hour())
+	d = s.evalTimeFunc(c, ast.UTCTimestamp)
+	c.Assert(d.GetMysqlTime().Compare(t), Equals, 0)
+
+	d = s.evalTimeFunc(c, ast.Now, 6)
+	c.Assert(d.GetMysqlTime().Fsp(), Equals, int8(6))
+	c.Assert(d.GetMysqlTime().Microsecond(), Equals, now.Add(500*time.Nanosecond).Nanosecond()/1000)
+	d = s.evalTimeFunc(c, ast.UnixTimestamp)
+	c.Assert(d.GetInt64(), Equals, now.Unix())
+
+	d = s.evalTimeFunc(c, ast.Curdate)
+	c.Assert(d.GetMysqlTime().Type(), Equals, mysql.TypeDate)
+	c.Assert(d.GetMysqlTime().Day(), Equals, now.Day())
+	d = s.evalTimeFunc(c, ast.Curtime)
+	c.Assert(d.GetMysqlDuration().Hour(), Equals, now.Hour())
+
+	// NOW() follows the session time zone while UTC_TIMESTAMP() does not.
+	vars.TimeZone = time.FixedZone("", 3*3600)
+	d = s.evalTimeFunc(c, ast.Now)
+	c.Assert(d.GetMysqlTime().Hour(), Equals, now.Add(3*time.Hour).Hour())
+	d = s.evalTimeFunc(c, ast.UTCTime)
+	c.Assert(d.GetMysqlDuration().Hour(), Equals, now.Hour())
+
+	_, err := newFunctionForTest(s.ctx, ast.Now, s.primitiveValsToConstants([]interface{}{7})...)
+	c.Assert(err, ErrorMatches, ".*Too big precision 7 specified for column 'now'.*")
+}
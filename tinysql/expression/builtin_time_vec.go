@@ -0,0 +1,339 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalTimeArg evaluates the first argument of b as times and returns the buffer,
+// the caller should put the buffer back to the allocator.
+func (b *baseBuiltinFunc) vecEvalTimeArg(input *chunk.Chunk) (*chunk.Column, error) {
+	buf, err := b.bufAllocator.get(types.ETDatetime, input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		b.bufAllocator.put(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// vecEvalDurationArg evaluates the first argument of b as durations and returns the buffer,
+// the caller should put the buffer back to the allocator.
+func (b *baseBuiltinFunc) vecEvalDurationArg(input *chunk.Chunk) (*chunk.Column, error) {
+	buf, err := b.bufAllocator.get(types.ETDuration, input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := b.args[0].VecEvalDuration(b.ctx, input, buf); err != nil {
+		b.bufAllocator.put(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (b *builtinYearSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinYearSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalTimeArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(ds[i].Year())
+	}
+	return nil
+}
+
+func (b *builtinMonthSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalTimeArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(ds[i].Month())
+	}
+	return nil
+}
+
+func (b *builtinDayOfMonthSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDayOfMonthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalTimeArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(ds[i].Day())
+	}
+	return nil
+}
+
+func (b *builtinHourSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinHourSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalDurationArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.GoDurations()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(types.Duration{Duration: ds[i]}.Hour())
+	}
+	return nil
+}
+
+func (b *builtinMinuteSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMinuteSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalDurationArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.GoDurations()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(types.Duration{Duration: ds[i]}.Minute())
+	}
+	return nil
+}
+
+func (b *builtinSecondSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSecondSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalDurationArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.GoDurations()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(types.Duration{Duration: ds[i]}.Second())
+	}
+	return nil
+}
+
+func (b *builtinMicroSecondSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMicroSecondSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.vecEvalDurationArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	ds := buf.GoDurations()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(types.Duration{Duration: ds[i]}.MicroSecond())
+	}
+	return nil
+}
+
+func (b *builtinDateSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	if err := b.args[0].VecEvalTime(b.ctx, input, result); err != nil {
+		return err
+	}
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if times[i].IsZero() {
+			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, times[i].String())); err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = types.NewTime(types.FromDate(times[i].Year(), times[i].Month(), times[i].Day(), 0, 0, 0, 0), mysql.TypeDate, 0)
+	}
+	return nil
+}
+
+func (b *builtinDateDiffSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	lhs, err := b.vecEvalTimeArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lhs)
+	rhs, err := b.bufAllocator.get(types.ETDatetime, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(rhs)
+	if err := b.args[1].VecEvalTime(b.ctx, input, rhs); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(lhs, rhs)
+	i64s := result.Int64s()
+	args0, args1 := lhs.Times(), rhs.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if invalidArg0, invalidArg1 := args0[i].InvalidZero(), args1[i].InvalidZero(); invalidArg0 || invalidArg1 {
+			if invalidArg0 {
+				err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, args0[i].String()))
+			}
+			if err == nil && invalidArg1 {
+				err = handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, args1[i].String()))
+			}
+			if err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = int64(types.DateDiff(args0[i].CoreTime(), args1[i].CoreTime()))
+	}
+	return nil
+}
+
+func (b *builtinDateFormatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateFormatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	dateBuf, err := b.vecEvalTimeArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(dateBuf)
+	formatBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(formatBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, formatBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	times := dateBuf.Times()
+	for i := 0; i < n; i++ {
+		if dateBuf.IsNull(i) || formatBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		t := times[i]
+		if t.InvalidZero() {
+			if err := handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, t.String())); err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		res, err := t.DateFormat(formatBuf.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
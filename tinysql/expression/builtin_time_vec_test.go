@@ -0,0 +1,73 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinTimeCases = map[string][]vecExprBenchCase{
+	ast.Year: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.Month: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.DayOfMonth: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.Hour: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDuration}},
+	},
+	ast.Minute: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDuration}},
+	},
+	ast.Second: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDuration}},
+	},
+	ast.Microsecond: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDuration}},
+	},
+	ast.Date: {
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDatetime}},
+	},
+	ast.DateDiff: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETDatetime, types.ETDatetime}},
+	},
+	ast.DateFormat: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETDatetime, types.ETString},
+			geners: []dataGenerator{nil, &selectStringGener{candidates: []string{"%Y-%m-%d %H:%i:%s", "%b %D %W %j %f", "%r %T %% %U %u"}}},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinTimeEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinTimeCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinTimeFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinTimeCases)
+}
+
+func BenchmarkVectorizedBuiltinTimeEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinTimeCases)
+}
+
+func BenchmarkVectorizedBuiltinTimeFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinTimeCases)
+}
@@ -247,6 +247,14 @@ func convertETType(eType types.EvalType) (mysqlType byte) {
 		mysqlType = mysql.TypeLonglong
 	case types.ETReal:
 		mysqlType = mysql.TypeDouble
+	case types.ETDecimal:
+		mysqlType = mysql.TypeNewDecimal
+	case types.ETDatetime:
+		mysqlType = mysql.TypeDatetime
+	case types.ETTimestamp:
+		mysqlType = mysql.TypeTimestamp
+	case types.ETDuration:
+		mysqlType = mysql.TypeDuration
 	case types.ETString:
 		mysqlType = mysql.TypeVarString
 	}
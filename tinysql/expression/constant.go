@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 )
@@ -47,13 +48,37 @@ var (
 
 // Constant stands for a constant value.
 type Constant struct {
-	Value    types.Datum
-	RetType  *types.FieldType
-	hashcode []byte
+	Value   types.Datum
+	RetType *types.FieldType
+	// DeferredExpr holds the folded function whose arguments contain mutable
+	// constants, it is evaluated again when the cached plan is reused.
+	DeferredExpr Expression
+	// ParamMarker holds the parameter of the prepared statement referenced by
+	// the constant in a cacheable plan.
+	ParamMarker *ParamMarker
+	hashcode    []byte
+}
+
+// ParamMarker indicates a parameter provided by EXECUTE or COM_STMT_EXECUTE.
+type ParamMarker struct {
+	ctx   sessionctx.Context
+	order int
+}
+
+// GetUserVar returns the value of the parameter in the current execution.
+func (d *ParamMarker) GetUserVar() types.Datum {
+	sessionVars := d.ctx.GetSessionVars()
+	return sessionVars.PreparedParams[d.order]
 }
 
 // String implements fmt.Stringer interface.
 func (c *Constant) String() string {
+	if c.ParamMarker != nil {
+		dt := c.ParamMarker.GetUserVar()
+		return fmt.Sprintf("%v", dt.GetValue())
+	} else if c.DeferredExpr != nil {
+		return c.DeferredExpr.String()
+	}
 	return fmt.Sprintf("%v", c.Value.GetValue())
 }
 
@@ -87,53 +112,179 @@ func (c *Constant) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return genVecFromConstExpr(ctx, c, types.ETString, input, result)
 }
 
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETDecimal, input, result)
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETTimestamp, input, result)
+}
+
+// VecEvalDuration evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDuration(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETDuration, input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETJson, input, result)
+}
+
+// getLazyDatum returns the value of a mutable constant, which is only known
+// when the plan is executed.
+func (c *Constant) getLazyDatum(row chunk.Row) (dt types.Datum, isLazy bool, err error) {
+	if p := c.ParamMarker; p != nil {
+		return p.GetUserVar(), true, nil
+	} else if c.DeferredExpr != nil {
+		dt, err = c.DeferredExpr.Eval(row)
+		return dt, true, err
+	}
+	return types.Datum{}, false, nil
+}
+
+// getDatum returns the current value of the constant.
+func (c *Constant) getDatum(row chunk.Row) (types.Datum, error) {
+	dt, lazy, err := c.getLazyDatum(row)
+	if !lazy {
+		return c.Value, nil
+	}
+	return dt, err
+}
+
 // Eval implements Expression interface.
-func (c *Constant) Eval(_ chunk.Row) (types.Datum, error) {
-	return c.Value, nil
+func (c *Constant) Eval(row chunk.Row) (types.Datum, error) {
+	return c.getDatum(row)
 }
 
 // EvalInt returns int representation of Constant.
-func (c *Constant) EvalInt(ctx sessionctx.Context, _ chunk.Row) (int64, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+func (c *Constant) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return 0, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return 0, true, nil
 	}
-	if c.GetType().Hybrid() || c.Value.Kind() == types.KindString {
-		res, err := c.Value.ToInt64(ctx.GetSessionVars().StmtCtx)
+	if c.GetType().Hybrid() || dt.Kind() == types.KindString {
+		res, err := dt.ToInt64(ctx.GetSessionVars().StmtCtx)
 		return res, err != nil, err
 	}
-	return c.Value.GetInt64(), false, nil
+	return dt.GetInt64(), false, nil
 }
 
 // EvalReal returns real representation of Constant.
-func (c *Constant) EvalReal(ctx sessionctx.Context, _ chunk.Row) (float64, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+func (c *Constant) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return 0, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return 0, true, nil
 	}
-	if c.GetType().Hybrid() || c.Value.Kind() == types.KindString {
-		res, err := c.Value.ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if c.GetType().Hybrid() || dt.Kind() == types.KindString {
+		res, err := dt.ToFloat64(ctx.GetSessionVars().StmtCtx)
 		return res, err != nil, err
 	}
-	return c.Value.GetFloat64(), false, nil
+	return dt.GetFloat64(), false, nil
 }
 
 // EvalString returns string representation of Constant.
-func (c *Constant) EvalString(ctx sessionctx.Context, _ chunk.Row) (string, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+func (c *Constant) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return "", true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return "", true, nil
 	}
-	res, err := c.Value.ToString()
+	res, err := dt.ToString()
+	return res, err != nil, err
+}
+
+// EvalDecimal returns decimal representation of Constant.
+func (c *Constant) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return nil, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return nil, true, nil
+	}
+	res, err := dt.ToDecimal(ctx.GetSessionVars().StmtCtx)
 	return res, err != nil, err
 }
 
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Constant.
+func (c *Constant) EvalTime(ctx sessionctx.Context, row chunk.Row) (val types.Time, isNull bool, err error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return types.ZeroTime, true, nil
+	}
+	if dt.Kind() == types.KindMysqlTime {
+		return dt.GetMysqlTime(), false, nil
+	}
+	d, err := dt.ConvertTo(ctx.GetSessionVars().StmtCtx, c.RetType)
+	if err != nil {
+		return types.ZeroTime, true, err
+	}
+	return d.GetMysqlTime(), false, nil
+}
+
+// EvalDuration returns Duration representation of Constant.
+func (c *Constant) EvalDuration(ctx sessionctx.Context, row chunk.Row) (val types.Duration, isNull bool, err error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return types.Duration{}, true, nil
+	}
+	if dt.Kind() == types.KindMysqlDuration {
+		return dt.GetMysqlDuration(), false, nil
+	}
+	d, err := dt.ConvertTo(ctx.GetSessionVars().StmtCtx, c.RetType)
+	if err != nil {
+		return types.Duration{}, true, err
+	}
+	return d.GetMysqlDuration(), false, nil
+}
+
+// EvalJSON returns JSON representation of Constant.
+func (c *Constant) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	dt, err := c.getDatum(row)
+	if err != nil {
+		return json.BinaryJSON{}, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return json.BinaryJSON{}, true, nil
+	}
+	val, err := dt.ConvertTo(ctx.GetSessionVars().StmtCtx, types.NewFieldType(mysql.TypeJSON))
+	if err != nil {
+		return json.BinaryJSON{}, true, err
+	}
+	return val.GetMysqlJSON(), false, nil
+}
+
 // Equal implements Expression interface.
 func (c *Constant) Equal(ctx sessionctx.Context, b Expression) bool {
 	y, ok := b.(*Constant)
 	if !ok {
 		return false
 	}
-	_, err1 := y.Eval(chunk.Row{})
-	_, err2 := c.Eval(chunk.Row{})
-	if err1 != nil || err2 != nil {
+	if !c.ConstItem() || !y.ConstItem() {
+		// The values of the mutable constants may differ when the plan is
+		// reused, so they are equal only if they refer to the same thing.
+		if c.ParamMarker != nil && y.ParamMarker != nil {
+			return c.ParamMarker.order == y.ParamMarker.order
+		}
+		if c.DeferredExpr != nil && y.DeferredExpr != nil {
+			return c.DeferredExpr.Equal(ctx, y.DeferredExpr)
+		}
 		return false
 	}
 	con, err := c.Value.CompareDatum(ctx.GetSessionVars().StmtCtx, &y.Value)
@@ -149,8 +300,10 @@ func (c *Constant) IsCorrelated() bool {
 }
 
 // ConstItem implements Expression interface.
+// The value of a constant which refers to a parameter of a cached plan may
+// change when the plan is reused, so it is not a constant item.
 func (c *Constant) ConstItem() bool {
-	return true
+	return c.DeferredExpr == nil && c.ParamMarker == nil
 }
 
 // Decorrelate implements Expression interface.
@@ -163,11 +316,17 @@ func (c *Constant) HashCode(sc *stmtctx.StatementContext) []byte {
 	if len(c.hashcode) > 0 {
 		return c.hashcode
 	}
-	_, err := c.Eval(chunk.Row{})
-	if err != nil {
-		terror.Log(err)
+	if c.ParamMarker != nil {
+		c.hashcode = append(c.hashcode, parameterFlag)
+		c.hashcode = codec.EncodeInt(c.hashcode, int64(c.ParamMarker.order))
+		return c.hashcode
+	}
+	if c.DeferredExpr != nil {
+		c.hashcode = c.DeferredExpr.HashCode(sc)
+		return c.hashcode
 	}
 	c.hashcode = append(c.hashcode, constantFlag)
+	var err error
 	c.hashcode, err = codec.EncodeValue(sc, c.hashcode, c.Value)
 	if err != nil {
 		terror.Log(err)
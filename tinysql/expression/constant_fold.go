@@ -14,6 +14,7 @@
 package expression
 
 import (
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -36,9 +37,11 @@ func foldConstant(expr Expression) Expression {
 		argIsConst := make([]bool, len(args))
 		hasNullArg := false
 		allConstArg := true
+		isDeferredConst := false
 		for i := 0; i < len(args); i++ {
 			switch x := args[i].(type) {
 			case *Constant:
+				isDeferredConst = isDeferredConst || !x.ConstItem()
 				argIsConst[i] = true
 				hasNullArg = hasNullArg || x.Value.IsNull()
 			default:
@@ -46,9 +49,14 @@ func foldConstant(expr Expression) Expression {
 			}
 		}
 		if !allConstArg {
-			if !hasNullArg || !sc.InNullRejectCheck {
+			if !hasNullArg || !sc.InNullRejectCheck || x.FuncName.L == ast.NullEQ {
 				return expr
 			}
+			if isDeferredConst {
+				// Whether the expression is null rejected depends on the value
+				// of the parameters, so the plan can not be reused.
+				sc.SkipPlanCache = true
+			}
 			constArgs := make([]Expression, len(args))
 			for i, arg := range args {
 				if argIsConst[i] {
@@ -78,6 +86,9 @@ func foldConstant(expr Expression) Expression {
 			logutil.BgLogger().Debug("fold expression to constant", zap.String("expression", x.ExplainInfo()), zap.Error(err))
 			return expr
 		}
+		if isDeferredConst {
+			return &Constant{Value: value, RetType: x.RetType, DeferredExpr: x}
+		}
 		return &Constant{Value: value, RetType: x.RetType}
 	}
 	return expr
@@ -52,11 +52,21 @@ func (s *basePropConstSolver) insertCol(col *Column) {
 // a = 1 and a = 2, we set the second return value to false.
 func (s *basePropConstSolver) tryToUpdateEQList(col *Column, con *Constant) (bool, bool) {
 	if con.Value.IsNull() {
+		if MaybeOverOptimized4PlanCache(s.ctx, []Expression{con}) {
+			s.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+		}
 		return false, true
 	}
 	id := s.getColID(col)
 	oldCon := s.eqList[id]
 	if oldCon != nil {
+		if MaybeOverOptimized4PlanCache(s.ctx, []Expression{oldCon, con}) {
+			// Whether the two conditions conflict depends on the values of the
+			// parameters, so compare the current values instead.
+			s.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+			cmp, err := oldCon.Value.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &con.Value)
+			return false, err != nil || cmp != 0
+		}
 		return false, !oldCon.Equal(s.ctx, con)
 	}
 	s.eqList[id] = con
@@ -246,6 +256,9 @@ func (s *propConstSolver) pickNewEQConds(visited []bool) (retMapper map[int]*Con
 		var ok bool
 		if col == nil {
 			if con, ok = cond.(*Constant); ok {
+				if MaybeOverOptimized4PlanCache(s.ctx, []Expression{con}) {
+					s.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+				}
 				value, _, err := EvalBool(s.ctx, []Expression{con}, chunk.Row{})
 				if err != nil {
 					terror.Log(err)
@@ -304,6 +317,10 @@ type propOuterJoinConstSolver struct {
 	filterConds []Expression
 	outerSchema *Schema
 	innerSchema *Schema
+	// nullSensitive indicates if this outer join is null sensitive, if true, we cannot generate
+	// additional `col is not null` condition from column equal conditions. Specifically, this value
+	// is true for LeftOuterSemiJoin and AntiLeftOuterSemiJoin.
+	nullSensitive bool
 }
 
 func (s *propOuterJoinConstSolver) setConds2ConstFalse(filterConds bool) {
@@ -338,6 +355,9 @@ func (s *propOuterJoinConstSolver) pickEQCondsOnOuterCol(retMapper map[int]*Cons
 		var ok bool
 		if col == nil {
 			if con, ok = cond.(*Constant); ok {
+				if MaybeOverOptimized4PlanCache(s.ctx, []Expression{con}) {
+					s.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+				}
 				value, _, err := EvalBool(s.ctx, []Expression{con}, chunk.Row{})
 				if err != nil {
 					terror.Log(err)
@@ -477,6 +497,17 @@ func (s *propOuterJoinConstSolver) propagateColumnEQ() {
 			innerID := s.getColID(innerCol)
 			s.unionSet.Union(outerID, innerID)
 			visited[i] = true
+			// Generate `innerCol is not null` from `outerCol = innerCol`. Note that `outerCol is not null`
+			// does not hold since we are in outer join.
+			// For AntiLeftOuterSemiJoin, this does not work, for example:
+			// `select *, t1.a not in (select t2.b from t t2) from t t1` does not imply `t2.b is not null`.
+			// For LeftOuterSemiJoin, this does not work either, for example:
+			// `select *, t1.a in (select t2.b from t t2) from t t1`
+			// rows with t2.b is null would impact whether LeftOuterSemiJoin should output 0 or null if there
+			// is no row satisfying t2.b = t1.a
+			if s.nullSensitive {
+				continue
+			}
 			childCol := s.innerSchema.RetrieveColumn(innerCol)
 			if !mysql.HasNotNullFlag(childCol.RetType.Flag) {
 				notNullExpr := BuildNotNullExpr(s.ctx, childCol)
@@ -551,10 +582,11 @@ func propagateConstantDNF(ctx sessionctx.Context, conds []Expression) []Expressi
 // conditions based on this column equal condition and `outerCol` related
 // expressions in join conditions and filter conditions;
 func PropConstOverOuterJoin(ctx sessionctx.Context, joinConds, filterConds []Expression,
-	outerSchema, innerSchema *Schema) ([]Expression, []Expression) {
+	outerSchema, innerSchema *Schema, nullSensitive bool) ([]Expression, []Expression) {
 	solver := &propOuterJoinConstSolver{
-		outerSchema: outerSchema,
-		innerSchema: innerSchema,
+		outerSchema:   outerSchema,
+		innerSchema:   innerSchema,
+		nullSensitive: nullSensitive,
 	}
 	solver.colMapper = make(map[int64]int)
 	solver.ctx = ctx
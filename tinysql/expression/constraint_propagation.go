@@ -148,6 +148,9 @@ type constraintPropagateRule func(ctx sessionctx.Context, i, j int, exprs *exprS
 func ruleConstantFalse(ctx sessionctx.Context, i, j int, exprs *exprSet) {
 	cond := exprs.data[i]
 	if cons, ok := cond.(*Constant); ok {
+		if MaybeOverOptimized4PlanCache(ctx, []Expression{cons}) {
+			ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+		}
 		v, isNull, err := cons.EvalInt(ctx, chunk.Row{})
 		if err != nil {
 			logutil.BgLogger().Warn("eval constant", zap.Error(err))
@@ -31,8 +31,13 @@ var (
 	ErrIncorrectType           = terror.ClassExpression.New(mysql.ErrIncorrectType, mysql.MySQLErrName[mysql.ErrIncorrectType])
 
 	// All the un-exported errors are defined here:
-	errFunctionNotExists = terror.ClassExpression.New(mysql.ErrSpDoesNotExist, mysql.MySQLErrName[mysql.ErrSpDoesNotExist])
-	errNonUniq           = terror.ClassExpression.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	errFunctionNotExists  = terror.ClassExpression.New(mysql.ErrSpDoesNotExist, mysql.MySQLErrName[mysql.ErrSpDoesNotExist])
+	errNonUniq            = terror.ClassExpression.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	errTooBigPrecision    = terror.ClassExpression.New(mysql.ErrTooBigPrecision, mysql.MySQLErrName[mysql.ErrTooBigPrecision])
+	errInvalidTypeForJSON = terror.ClassExpression.New(mysql.ErrInvalidTypeForJSON, mysql.MySQLErrName[mysql.ErrInvalidTypeForJSON])
+
+	// All the un-exported warnings are defined here:
+	errWarnAllowedPacketOverflowed = terror.ClassExpression.New(mysql.ErrWarnAllowedPacketOverflowed, mysql.MySQLErrName[mysql.ErrWarnAllowedPacketOverflowed])
 )
 
 func init() {
@@ -57,6 +62,8 @@ func init() {
 		mysql.ErrBadField:                          mysql.ErrBadField,
 		mysql.ErrNonUniq:                           mysql.ErrNonUniq,
 		mysql.ErrIncorrectType:                     mysql.ErrIncorrectType,
+		mysql.ErrTooBigPrecision:                   mysql.ErrTooBigPrecision,
+		mysql.ErrInvalidTypeForJSON:                mysql.ErrInvalidTypeForJSON,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExpression] = expressionMySQLErrCodes
 }
@@ -64,7 +71,7 @@ func init() {
 // handleDivisionByZeroError reports error or warning depend on the context.
 func handleDivisionByZeroError(ctx sessionctx.Context) error {
 	sc := ctx.GetSessionVars().StmtCtx
-	if sc.InInsertStmt || sc.InDeleteStmt {
+	if sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt {
 		if !ctx.GetSessionVars().SQLMode.HasErrorForDivisionByZeroMode() {
 			return nil
 		}
@@ -75,3 +82,14 @@ func handleDivisionByZeroError(ctx sessionctx.Context) error {
 	sc.AppendWarning(ErrDivisionByZero)
 	return nil
 }
+
+// handleAllowedPacketOverflowed reports error or warning depend on the context.
+func handleAllowedPacketOverflowed(ctx sessionctx.Context, exprName string, maxAllowedPacketSize uint64) error {
+	err := errWarnAllowedPacketOverflowed.GenWithStackByArgs(exprName, maxAllowedPacketSize)
+	sc := ctx.GetSessionVars().StmtCtx
+	if ctx.GetSessionVars().StrictSQLMode && (sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt) {
+		return err
+	}
+	sc.AppendWarning(err)
+	return nil
+}
@@ -88,7 +88,7 @@ func NewPBConverter(client kv.Client, sc *stmtctx.StatementContext) PbConverter
 // ExprToPB converts Expression to TiPB.
 func (pc PbConverter) ExprToPB(expr Expression) *tipb.Expr {
 	switch x := expr.(type) {
-	case *Constant:
+	case *Constant, *CorrelatedColumn:
 		return pc.conOrCorColToPBExpr(expr)
 	case *Column:
 		return pc.columnToPBExpr(x)
@@ -100,6 +100,9 @@ func (pc PbConverter) ExprToPB(expr Expression) *tipb.Expr {
 
 func (pc PbConverter) conOrCorColToPBExpr(expr Expression) *tipb.Expr {
 	ft := expr.GetType()
+	if !canTypeBePushed(ft) {
+		return nil
+	}
 	d, err := expr.Eval(chunk.Row{})
 	if err != nil {
 		logutil.BgLogger().Error("eval constant or correlated column", zap.String("expression", expr.ExplainInfo()), zap.Error(err))
@@ -196,6 +199,9 @@ func (pc PbConverter) columnToPBExpr(column *Column) *tipb.Expr {
 	case mysql.TypeBit, mysql.TypeSet, mysql.TypeEnum, mysql.TypeGeometry, mysql.TypeUnspecified:
 		return nil
 	}
+	if !canTypeBePushed(column.GetType()) {
+		return nil
+	}
 
 	if pc.client.IsRequestTypeSupported(kv.ReqTypeDAG, kv.ReqSubTypeBasic) {
 		return &tipb.Expr{
@@ -223,7 +229,7 @@ func (pc PbConverter) scalarFuncToPBExpr(expr *ScalarFunction) *tipb.Expr {
 	}
 
 	// Check whether this function can be pushed.
-	if !pc.canFuncBePushed(expr) {
+	if !pc.canFuncBePushed(expr) || !canTypeBePushed(expr.RetType) {
 		return nil
 	}
 
@@ -277,6 +283,17 @@ func SortByItemToPB(sc *stmtctx.StatementContext, client kv.Client, expr Express
 	return &tipb.ByItem{Expr: e, Desc: desc}
 }
 
+// canTypeBePushed checks whether values of this type can be decoded and
+// evaluated by the coprocessor of TinyKV, which only understands int, real
+// and string values.
+func canTypeBePushed(ft *types.FieldType) bool {
+	switch ft.EvalType() {
+	case types.ETInt, types.ETReal, types.ETString:
+		return true
+	}
+	return false
+}
+
 func (pc PbConverter) canFuncBePushed(sf *ScalarFunction) bool {
 	switch sf.FuncName.L {
 	case
@@ -19,6 +19,8 @@ import (
 
 // unFoldableFunctions stores functions which can not be folded duration constant folding stage.
 var unFoldableFunctions = map[string]struct{}{
+	ast.Sysdate: {},
+	ast.Rand:    {},
 	ast.RowFunc: {},
 	ast.SetVar:  {},
 	ast.GetVar:  {},
@@ -32,6 +34,7 @@ var inequalFunctions = map[string]struct{}{
 // mutableEffectsFunctions stores functions which are mutable or have side effects, specifically,
 // we cannot remove them from filter even if they have duplicates.
 var mutableEffectsFunctions = map[string]struct{}{
+	ast.Rand:   {},
 	ast.SetVar: {},
 	ast.GetVar: {},
 }
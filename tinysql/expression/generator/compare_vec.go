@@ -49,6 +49,7 @@ const newLine = "\n"
 
 const builtinCompareImports = `import (
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 )
 `
@@ -86,6 +87,14 @@ func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vecEvalInt(in
 		}
 {{- if eq .type.ETName "Real" }}
 		val := types.CompareFloat64(arg0[i], arg1[i])
+{{- else if eq .type.ETName "Decimal" }}
+		val := arg0[i].Compare(&arg1[i])
+{{- else if eq .type.ETName "Datetime" }}
+		val := arg0[i].Compare(arg1[i])
+{{- else if eq .type.ETName "Duration" }}
+		val := types.CompareDuration(arg0[i], arg1[i])
+{{- else if eq .type.ETName "Json" }}
+		val := json.CompareBinary(buf0.GetJSON(i), buf1.GetJSON(i))
 {{- else }}
 		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
 {{- end }}
@@ -103,6 +112,127 @@ func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vectorized()
 }
 `))
 
+var builtinCoalesceCompareVecTpl = template.Must(template.New("").Parse(`
+// NOTE: Coalesce just returns the first non-null item, but the vectorized evaluation evaluates every item,
+// which may incur additional errors or warnings. If this happens, the evaluation falls back to the scalar one.
+func (b *builtinCoalesce{{ .type.TypeName }}Sig) fallbackEval{{ .type.TypeName }}(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+{{- if .type.Fixed }}
+	result.Resize{{ .type.TypeNameInColumn }}(n, false)
+	x := result.{{ .type.TypeNameInColumn }}s()
+	for i := 0; i < n; i++ {
+		res, isNull, err := b.eval{{ .type.TypeName }}(input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		result.SetNull(i, isNull)
+		if isNull {
+			continue
+		}
+{{- if eq .type.TypeName "Decimal" }}
+		x[i] = *res
+{{- else if eq .type.TypeName "Duration" }}
+		x[i] = res.Duration
+{{- else }}
+		x[i] = res
+{{- end }}
+	}
+{{- else }}
+	result.Reserve{{ .type.TypeNameInColumn }}(n)
+	for i := 0; i < n; i++ {
+		res, isNull, err := b.eval{{ .type.TypeName }}(input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.Append{{ .type.TypeNameInColumn }}(res)
+	}
+{{- end }}
+	return nil
+}
+
+{{ if .type.Fixed }}
+func (b *builtinCoalesce{{ .type.TypeName }}Sig) vecEval{{ .type.TypeName }}(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.Resize{{ .type.TypeNameInColumn }}(n, true)
+	x := result.{{ .type.TypeNameInColumn }}s()
+	buf1, err := b.bufAllocator.get(types.ET{{ .type.ETName }}, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	beforeWarns := sc.WarningCount()
+	for j := 0; j < len(b.args); j++ {
+		err := b.args[j].VecEval{{ .type.TypeName }}(b.ctx, input, buf1)
+		afterWarns := sc.WarningCount()
+		if err != nil || afterWarns > beforeWarns {
+			if afterWarns > beforeWarns {
+				sc.TruncateWarnings(int(beforeWarns))
+			}
+			return b.fallbackEval{{ .type.TypeName }}(input, result)
+		}
+		args := buf1.{{ .type.TypeNameInColumn }}s()
+		for i := 0; i < n; i++ {
+			if !buf1.IsNull(i) && result.IsNull(i) {
+				x[i] = args[i]
+				result.SetNull(i, false)
+			}
+		}
+	}
+	return nil
+}
+{{ else }}
+func (b *builtinCoalesce{{ .type.TypeName }}Sig) vecEval{{ .type.TypeName }}(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	argLen := len(b.args)
+
+	bufs := make([]*chunk.Column, argLen)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	beforeWarns := sc.WarningCount()
+	for i := 0; i < argLen; i++ {
+		buf, err := b.bufAllocator.get(types.ET{{ .type.ETName }}, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		err = b.args[i].VecEval{{ .type.TypeName }}(b.ctx, input, buf)
+		afterWarns := sc.WarningCount()
+		if err != nil || afterWarns > beforeWarns {
+			if afterWarns > beforeWarns {
+				sc.TruncateWarnings(int(beforeWarns))
+			}
+			return b.fallbackEval{{ .type.TypeName }}(input, result)
+		}
+		bufs[i] = buf
+	}
+	result.Reserve{{ .type.TypeNameInColumn }}(n)
+
+	for i := 0; i < n; i++ {
+		isNull := true
+		for j := 0; j < argLen; j++ {
+			if !bufs[j].IsNull(i) {
+				result.Append{{ .type.TypeNameInColumn }}(bufs[j].Get{{ .type.TypeNameInColumn }}(i))
+				isNull = false
+				break
+			}
+		}
+		if isNull {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+{{ end }}
+
+func (b *builtinCoalesce{{ .type.TypeName }}Sig) vectorized() bool {
+	return true
+}
+`))
+
 const builtinCompareVecTestHeader = `import (
 	"testing"
 
@@ -123,6 +253,9 @@ var builtinCompareVecTestCase = template.Must(template.New("").Parse(`		{retEval
 var builtinCompareVecTestFuncTail = `	},
 `
 
+var builtinCoalesceCompareVecTestFunc = template.Must(template.New("").Parse(`		{retEvalType: types.ET{{ .ETName }}, childrenTypes: []types.EvalType{types.ET{{ .ETName }}, types.ET{{ .ETName }}, types.ET{{ .ETName }}}},
+`))
+
 var builtinCompareVecTestTail = `}
 
 func (s *testEvaluatorSuite) TestVectorizedGeneratedBuiltinCompareEvalOneVec(c *C) {
@@ -161,7 +294,11 @@ var comparesMap = []CompareContext{
 var typesMap = []TypeContext{
 	TypeInt,
 	TypeReal,
+	TypeDecimal,
 	TypeString,
+	TypeDatetime,
+	TypeDuration,
+	TypeJSON,
 }
 
 func generateDotGo(fileName string, compares []CompareContext, types []TypeContext) (err error) {
@@ -184,6 +321,13 @@ func generateDotGo(fileName string, compares []CompareContext, types []TypeConte
 			}
 		}
 	}
+	for _, typeCtx := range types {
+		ctx["type"] = typeCtx
+		err := builtinCoalesceCompareVecTpl.Execute(w, ctx)
+		if err != nil {
+			return err
+		}
+	}
 	data, err := format.Source(w.Bytes())
 	if err != nil {
 		log.Println("[Warn]", fileName+": gofmt failed", err)
@@ -213,6 +357,14 @@ func generateTestDotGo(fileName string, compares []CompareContext, types []TypeC
 		}
 		w.WriteString(builtinCompareVecTestFuncTail)
 	}
+	w.WriteString("	ast.Coalesce: {\n")
+	for _, typeCtx := range types {
+		err := builtinCoalesceCompareVecTestFunc.Execute(w, typeCtx)
+		if err != nil {
+			return err
+		}
+	}
+	w.WriteString(builtinCompareVecTestFuncTail)
 	w.WriteString(builtinCompareVecTestTail)
 
 	data, err := format.Source(w.Bytes())
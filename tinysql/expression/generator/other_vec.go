@@ -94,6 +94,15 @@ var builtinInTmpl = template.Must(template.New("builtinInTmpl").Parse(`
 					compareResult = 0
 				}
 		}
+	{{- else if eq .Input.TypeName "Decimal" -}}
+		compareResult = 1
+		if arg0.Compare(&arg1) == 0 {
+			compareResult = 0
+		}
+	{{- else if eq .Input.TypeName "Time" -}}
+		compareResult = arg0.Compare(arg1)
+	{{- else if eq .Input.TypeName "Duration" -}}
+		compareResult = types.CompareDuration(arg0, arg1)
 	{{- else -}}
 		compareResult = types.Compare{{ .Input.TypeNameInColumn }}(arg0, arg1)
 	{{- end -}}
@@ -185,9 +194,11 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -211,6 +222,18 @@ func (g inGener) gen() interface{} {
 			return -float64(randNum)
 		}
 		return float64(randNum)
+	case types.ETDecimal:
+		d := new(types.MyDecimal)
+		f := float64(randNum)
+		if rand.Float64() < 0.5 {
+			f = -f
+		}
+		d.FromFloat64(f)
+		return d
+	case types.ETDatetime, types.ETTimestamp:
+		return types.NewTime(types.FromDate(2019, 11, 2, 22, 00, int(randNum), rand.Intn(1000000)), mysql.TypeDatetime, 6)
+	case types.ETDuration:
+		return types.Duration{Duration: time.Duration(randNum)}
 	case types.ETString:
 		return fmt.Sprint(randNum)
 	}
@@ -269,6 +292,9 @@ var inSigsTmpl = []sig{
 	{SigName: "builtinInIntSig", Input: TypeInt, Output: TypeInt},
 	{SigName: "builtinInStringSig", Input: TypeString, Output: TypeInt},
 	{SigName: "builtinInRealSig", Input: TypeReal, Output: TypeInt},
+	{SigName: "builtinInDecimalSig", Input: TypeDecimal, Output: TypeInt},
+	{SigName: "builtinInTimeSig", Input: TypeDatetime, Output: TypeInt},
+	{SigName: "builtinInDurationSig", Input: TypeDuration, Output: TypeInt},
 }
 
 type function struct {
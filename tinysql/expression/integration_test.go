@@ -298,3 +298,90 @@ func (s *testIntegrationSuite) TestDecodetoChunkReuse(c *C) {
 	c.Assert(count, Equals, 200)
 	rs.Close()
 }
+
+func (s *testIntegrationSuite) TestStringBuiltin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a varchar(20), b varbinary(20), c int, index idx_a(a))")
+	tk.MustExec(`insert into t values ("abc", "abc", 1), ("ab_d", "AB_d", 2), ("中文字符", "中文", 3), ("  x  ", null, 4), (null, "x", 5)`)
+
+	// The functions are pushed down to the storage, the result should be the same as evaluated by TiDB.
+	result := tk.MustQuery("select c from t where a like 'ab%'")
+	result.Check(testkit.Rows("1", "2"))
+	result = tk.MustQuery(`select c from t where a like 'ab\_%'`)
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select c from t where a like 'ab|_%' escape '|'")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select c from t where a not like '%b%'")
+	result.Check(testkit.Rows("3", "4"))
+	result = tk.MustQuery("select c from t where a like '中_字%'")
+	result.Check(testkit.Rows("3"))
+	result = tk.MustQuery("select c from t where b like 'ab%'")
+	result.Check(testkit.Rows("1"))
+	result = tk.MustQuery("select c from t where a regexp '^ab.?d$'")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select c from t where b rlike 'B'")
+	result.Check(testkit.Rows("2"))
+	result = tk.MustQuery("select c from t where a like c")
+	result.Check(testkit.Rows())
+
+	result = tk.MustQuery("select concat(a, '-', c), concat_ws(',', a, b, c) from t where c < 3")
+	result.Check(testkit.Rows("abc-1 abc,abc,1", "ab_d-2 ab_d,AB_d,2"))
+	result = tk.MustQuery("select substring(a, 2), substring(a, -2, 1), substr(a from 2 for 2), mid(a, 1, 2) from t where c = 3")
+	result.Check(testkit.Rows("文字符 字 文字 中文"))
+	result = tk.MustQuery("select locate('文', a), locate('文', b), position('b' in a), locate('b', a, 3) from t where c in (1, 3)")
+	result.Check(testkit.Rows("0 0 2 0", "2 4 0 0"))
+	result = tk.MustQuery("select replace(a, 'b', 'x'), reverse(a), reverse(b) from t where c = 2")
+	result.Check(testkit.Rows("ax_d d_ba d_BA"))
+	result = tk.MustQuery("select trim(a), ltrim(a), rtrim(a), trim(leading ' ' from a), trim(trailing from a), trim(both 'x' from 'xxaxx') from t where c = 4")
+	result.Check(testkit.Rows("x x     x x     x a"))
+	result = tk.MustQuery("select upper(a), lower(b), ucase(b), lcase('ABC') from t where c = 2")
+	result.Check(testkit.Rows("AB_D AB_d AB_d abc"))
+	result = tk.MustQuery("select lpad(a, 6, '*'), rpad(a, 2, '*'), left(a, 2), right(a, 2), left(b, 3) from t where c = 3")
+	result.Check(testkit.Rows("**中文字符 中文 中文 字符 中"))
+	result = tk.MustQuery("select c from t where upper(a) = 'ABC' and reverse(b) = 'cba' and length(concat(a, b)) = 6")
+	result.Check(testkit.Rows("1"))
+}
+
+func (s *testIntegrationSuite) TestControlAndMathBuiltin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b double, c decimal(10,3), d varchar(20), e bigint unsigned)")
+	tk.MustExec(`insert into t values (1, 1.5, 2.345, "x", 1), (-7, -2.5, -3.456, null, 18446744073709551615), (null, null, null, "z", 0)`)
+
+	// The functions are pushed down to the storage, the result should be the same as evaluated by TiDB.
+	result := tk.MustQuery("select case a when 1 then 'one' when -7 then 'minus seven' else 'other' end, case when b > 0 then b when c < 0 then c end from t")
+	result.Check(testkit.Rows("one 1.5", "minus seven -3.456", "other <nil>"))
+	result = tk.MustQuery("select a from t where case when a > 0 then 1 else 0 end = 0")
+	result.Check(testkit.Rows("-7", "<nil>"))
+	result = tk.MustQuery("select coalesce(d, a, 'none'), nullif(a, 1), greatest(a, b, 0), least(a, c), greatest(d, 'y') from t")
+	result.Check(testkit.Rows("x <nil> 1.5 1 y", "-7 -7 0 -7 <nil>", "z <nil> <nil> <nil> z"))
+	result = tk.MustQuery("select a div 2, a mod 3, mod(c, 2), b % 2, e div 2, 7 div 0 from t")
+	result.Check(testkit.Rows("0 1 0.345 1.5 0 <nil>", "-3 -1 -1.456 -0.5 9223372036854775807 <nil>", "<nil> <nil> <nil> <nil> 0 <nil>"))
+	result = tk.MustQuery("select a from t where a % 2 = 1 and a div 1 = 1")
+	result.Check(testkit.Rows("1"))
+
+	result = tk.MustQuery("select abs(a), abs(c), ceil(b), floor(b), ceiling(c), floor(c), round(c), round(c, 2), truncate(c, 1), truncate(a, -1) from t")
+	result.Check(testkit.Rows("1 2.345 2 1 3 2 2 2.35 2.3 0", "7 3.456 -2 -3 -3 -4 -3 -3.46 -3.4 0", "<nil> <nil> <nil> <nil> <nil> <nil> <nil> <nil> <nil> <nil>"))
+	result = tk.MustQuery("select pow(2, 10), power(a, 2), sqrt(16), sqrt(-1), log(exp(2)), log(2, 8), log(0), crc32('MySQL'), crc32(d) from t where a = 1")
+	result.Check(testkit.Rows("1024 1 4 <nil> 2 3 <nil> 3259397556 2363233923"))
+	result = tk.MustQuery("select rand(0), rand(0) from t where a = 1")
+	result.Check(testkit.Rows("0.15522042769493574 0.15522042769493574"))
+	result = tk.MustQuery("select a from t where abs(a) > 5 and round(b) < 0 and crc32(d) is null")
+	result.Check(testkit.Rows("-7"))
+
+	result = tk.MustQuery("select a & 3, a | 8, a ^ 1, ~a, a << 2, e >> 60, 1 << 64 from t")
+	result.Check(testkit.Rows("1 9 0 18446744073709551614 4 0 0", "1 18446744073709551609 18446744073709551608 6 18446744073709551588 15 0", "<nil> <nil> <nil> <nil> <nil> 0 0"))
+	result = tk.MustQuery("select a from t where a & 1 = 1 and a >> 1 = 0")
+	result.Check(testkit.Rows("1"))
+	tk.MustExec("create table t1(a bigint)")
+	tk.MustExec("insert into t1 values (-9223372036854775808)")
+	err := tk.QueryToErr("select abs(a) from t1")
+	c.Assert(err, ErrorMatches, ".*BIGINT value is out of range in 'abs\\(-9223372036854775808\\)'")
+	err = tk.QueryToErr("select pow(10, 1000)")
+	c.Assert(err, ErrorMatches, ".*DOUBLE value is out of range in 'pow\\(10, 1000\\)'")
+}
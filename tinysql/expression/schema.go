@@ -79,7 +79,7 @@ func ExprFromSchema(expr Expression, schema *Schema) bool {
 			}
 		}
 		return true
-	case *Constant:
+	case *Constant, *CorrelatedColumn:
 		return true
 	}
 	return false
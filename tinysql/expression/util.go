@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -138,6 +139,50 @@ func extractColumnSet(expr Expression, set *intsets.Sparse) {
 	}
 }
 
+// ExtractCorColumns extracts correlated column from given expression.
+func ExtractCorColumns(expr Expression) (cols []*CorrelatedColumn) {
+	switch v := expr.(type) {
+	case *CorrelatedColumn:
+		return []*CorrelatedColumn{v}
+	case *ScalarFunction:
+		for _, arg := range v.GetArgs() {
+			cols = append(cols, ExtractCorColumns(arg)...)
+		}
+	}
+	return
+}
+
+// SetExprColumnInOperand is used to set columns in expr as InOperand.
+func SetExprColumnInOperand(expr Expression) Expression {
+	switch v := expr.(type) {
+	case *Column:
+		col := v.Clone().(*Column)
+		col.InOperand = true
+		return col
+	case *ScalarFunction:
+		args := v.GetArgs()
+		for i, arg := range args {
+			args[i] = SetExprColumnInOperand(arg)
+		}
+	}
+	return expr
+}
+
+// IsEQCondFromIn checks if an expression is equal condition converted from `[not] in (subq)`.
+func IsEQCondFromIn(expr Expression) bool {
+	sf, ok := expr.(*ScalarFunction)
+	if !ok || sf.FuncName.L != ast.EQ {
+		return false
+	}
+	cols := make([]*Column, 0, 1)
+	cols = ExtractColumnsFromExpressions(cols, sf.GetArgs(), isColumnInOperand)
+	return len(cols) > 0
+}
+
+func isColumnInOperand(c *Column) bool {
+	return c.InOperand
+}
+
 // ColumnSubstitute substitutes the columns in filter to expressions in select fields.
 // e.g. select * from (select b as a from t) k where a < 10 => select * from (select b as a from t where b < 10) k.
 func ColumnSubstitute(expr Expression, schema *Schema, newExprs []Expression) Expression {
@@ -421,6 +466,51 @@ func DatumToConstant(d types.Datum, tp byte) *Constant {
 	return &Constant{Value: d, RetType: types.NewFieldType(tp)}
 }
 
+// ParamMarkerExpression generates a Constant expression from the value bound
+// to a parameter marker of a prepared statement.
+func ParamMarkerExpression(ctx sessionctx.Context, v *driver.ParamMarkerExpr) (Expression, error) {
+	useCache := ctx.GetSessionVars().StmtCtx.UseCache
+	tp := types.NewFieldType(mysql.TypeUnspecified)
+	types.DefaultParamTypeForValue(v.GetValue(), tp)
+	value := &Constant{Value: v.Datum, RetType: tp}
+	if useCache {
+		value.ParamMarker = &ParamMarker{
+			order: v.Order,
+			ctx:   ctx,
+		}
+	}
+	return value, nil
+}
+
+// ContainMutableConst checks if the expressions contain a mutable constant,
+// whose value may change when the cached plan is reused.
+func ContainMutableConst(exprs []Expression) bool {
+	for _, expr := range exprs {
+		switch v := expr.(type) {
+		case *Constant:
+			if !v.ConstItem() {
+				return true
+			}
+		case *ScalarFunction:
+			if ContainMutableConst(v.GetArgs()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MaybeOverOptimized4PlanCache checks whether an optimization on the exprs
+// depends on the values of the parameters. If so, the plan is only valid for
+// the current parameters and the statement should set SkipPlanCache.
+func MaybeOverOptimized4PlanCache(ctx sessionctx.Context, exprs []Expression) bool {
+	// If the plan cache is not used, all the optimizations work correctly.
+	if !ctx.GetSessionVars().StmtCtx.UseCache {
+		return false
+	}
+	return ContainMutableConst(exprs)
+}
+
 // GetStringFromConstant gets a string value from the Constant expression.
 func GetStringFromConstant(ctx sessionctx.Context, value Expression) (string, bool, error) {
 	con, ok := value.(*Constant)
@@ -510,3 +600,10 @@ func GetUint64FromConstant(expr Expression) (uint64, bool, bool) {
 	}
 	return 0, false, false
 }
+
+func boolToInt64(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
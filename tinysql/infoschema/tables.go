@@ -65,6 +65,7 @@ const (
 	tableOptimizerTrace                     = "OPTIMIZER_TRACE"
 	tableTableSpaces                        = "TABLESPACES"
 	tableCollationCharacterSetApplicability = "COLLATION_CHARACTER_SET_APPLICABILITY"
+	tableProcesslist                        = "PROCESSLIST"
 )
 
 var tableIDMap = map[string]int64{
@@ -99,6 +100,7 @@ var tableIDMap = map[string]int64{
 	tableOptimizerTrace:                     autoid.InformationSchemaDBID + 30,
 	tableTableSpaces:                        autoid.InformationSchemaDBID + 31,
 	tableCollationCharacterSetApplicability: autoid.InformationSchemaDBID + 32,
+	tableProcesslist:                        autoid.InformationSchemaDBID + 33,
 }
 
 type columnInfo struct {
@@ -533,6 +535,18 @@ var tableCollationCharacterSetApplicabilityCols = []columnInfo{
 	{"CHARACTER_SET_NAME", mysql.TypeVarchar, 32, mysql.NotNullFlag, nil, nil},
 }
 
+var tableProcesslistCols = []columnInfo{
+	{"ID", mysql.TypeLonglong, 21, mysql.NotNullFlag | mysql.UnsignedFlag, 0, nil},
+	{"USER", mysql.TypeVarchar, 16, mysql.NotNullFlag, "", nil},
+	{"HOST", mysql.TypeVarchar, 64, mysql.NotNullFlag, "", nil},
+	{"DB", mysql.TypeVarchar, 64, 0, nil, nil},
+	{"COMMAND", mysql.TypeVarchar, 16, mysql.NotNullFlag, "", nil},
+	{"TIME", mysql.TypeLong, 7, mysql.NotNullFlag, 0, nil},
+	{"STATE", mysql.TypeVarchar, 7, 0, nil, nil},
+	{"INFO", mysql.TypeString, 512, 0, nil, nil},
+	{"MEM", mysql.TypeLonglong, 21, mysql.UnsignedFlag, nil, nil},
+}
+
 func dataForCharacterSets() (records [][]types.Datum) {
 
 	charsets := charset.GetSupportedCharsets()
@@ -604,6 +618,62 @@ func dataForUserPrivileges(ctx sessionctx.Context) [][]types.Datum {
 	return [][]types.Datum{}
 }
 
+func dataForSessionStatus(ctx sessionctx.Context) (records [][]types.Datum, err error) {
+	statusVars, err := variable.GetStatusVars(ctx.GetSessionVars())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(statusVars))
+	for name, v := range statusVars {
+		if v.Scope&variable.ScopeSession != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := fmt.Sprintf("%v", statusVars[name].Value)
+		records = append(records, types.MakeDatums(name, value))
+	}
+	return records, nil
+}
+
+// dataForOptimizerTrace returns the optimizer trace of the last traced
+// statement of the session.
+func dataForOptimizerTrace(ctx sessionctx.Context) (records [][]types.Datum) {
+	trace := ctx.GetSessionVars().LastOptimizerTrace
+	if trace == nil {
+		return nil
+	}
+	records = append(records,
+		types.MakeDatums(
+			trace.Query, // QUERY
+			trace.Trace, // TRACE
+			0,           // MISSING_BYTES_BEYOND_MAX_MEM_SIZE
+			0,           // INSUFFICIENT_PRIVILEGES
+		),
+	)
+	return records
+}
+
+// dataForProcesslist returns the running sessions of the server, along with
+// the memory consumed by their current statements.
+func dataForProcesslist(ctx sessionctx.Context) (records [][]types.Datum) {
+	sm := ctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	pl := sm.ShowProcessList()
+	ids := make([]uint64, 0, len(pl))
+	for id := range pl {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		records = append(records, types.MakeDatums(pl[id].ToRow()...))
+	}
+	return records
+}
+
 func dataForEngines() (records [][]types.Datum) {
 	records = append(records,
 		types.MakeDatums(
@@ -1262,6 +1332,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	tableOptimizerTrace:                     tableOptimizerTraceCols,
 	tableTableSpaces:                        tableTableSpacesCols,
 	tableCollationCharacterSetApplicability: tableCollationCharacterSetApplicabilityCols,
+	tableProcesslist:                        tableProcesslistCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocator, meta *model.TableInfo) (table.Table, error) {
@@ -1329,6 +1400,12 @@ func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 		fullRows = dataForUserPrivileges(ctx)
 	case tableEngines:
 		fullRows = dataForEngines()
+	case tableSessionStatus:
+		fullRows, err = dataForSessionStatus(ctx)
+	case tableOptimizerTrace:
+		fullRows = dataForOptimizerTrace(ctx)
+	case tableProcesslist:
+		fullRows = dataForProcesslist(ctx)
 	case tableRoutines:
 	// TODO: Fill the following tables.
 	case tableSchemaPrivileges:
@@ -1338,8 +1415,6 @@ func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 	case tableEvents:
 	case tableGlobalStatus:
 	case tableGlobalVariables:
-	case tableSessionStatus:
-	case tableOptimizerTrace:
 	case tableTableSpaces:
 	case tableCollationCharacterSetApplicability:
 		fullRows = dataForCollationCharacterSetApplicability()
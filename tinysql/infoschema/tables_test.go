@@ -14,16 +14,23 @@
 package infoschema_test
 
 import (
+	"encoding/json"
+	"strings"
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 var _ = Suite(&testTableSuite{})
@@ -72,3 +79,134 @@ func (s *testTableSuite) TestReloadDropDatabase(c *C) {
 	_, ok := is.TableByID(t2.Meta().ID)
 	c.Assert(ok, IsFalse)
 }
+
+func (s *testTableSuite) TestOptimizerTrace(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, c int, key(b))")
+	tk.MustQuery("select * from information_schema.optimizer_trace").Check(testkit.Rows())
+
+	// The statements are not traced by default.
+	tk.MustExec("select * from t where b > 1")
+	tk.MustQuery("select * from information_schema.optimizer_trace").Check(testkit.Rows())
+
+	getTrace := func() (string, *tracing.OptimizeTracer) {
+		rows := tk.MustQuery("select query, trace from information_schema.optimizer_trace").Rows()
+		c.Assert(rows, HasLen, 1)
+		trace := &tracing.OptimizeTracer{}
+		c.Assert(json.Unmarshal([]byte(rows[0][1].(string)), trace), IsNil)
+		return rows[0][0].(string), trace
+	}
+
+	tk.MustExec("set @@optimizer_trace = 'enabled=on'")
+	tk.MustQuery("select @@optimizer_trace").Check(testkit.Rows("enabled=on,one_line=off"))
+	tk.MustExec("select a from t where b > 1 order by b")
+	query, trace := getTrace()
+	c.Assert(query, Equals, "select a from t where b > 1 order by b")
+	c.Assert(trace.FinalPlan, Equals, "IndexReader(Index(t.b)[(1,+inf]])->Projection")
+	rules := make([]string, 0, len(trace.Logical))
+	for _, rule := range trace.Logical {
+		rules = append(rules, rule.Rule)
+		if rule.Rule == "predicate_push_down" {
+			c.Assert(rule.Changed, IsTrue)
+			c.Assert(rule.Before, Equals, "DataScan(t)->Sel([gt(test.t.b, 1)])->Sort->Projection")
+			c.Assert(rule.After, Equals, "DataScan(t)->Sort->Projection")
+		}
+	}
+	c.Assert(rules, DeepEquals, []string{"column_prune", "projection_eliminate", "predicate_push_down"})
+	c.Assert(trace.Cascades, HasLen, 0)
+	chosen := 0
+	for _, candidate := range trace.Physical {
+		c.Assert(candidate.Cost, Greater, 0.0)
+		if candidate.Chosen && strings.HasPrefix(candidate.LogicalPlan, "Projection") {
+			c.Assert(candidate.Plan, Equals, trace.FinalPlan)
+			chosen++
+		}
+	}
+	c.Assert(chosen, Equals, 1)
+
+	// The statement reading the trace is not traced.
+	tk.MustQuery("select query from information_schema.optimizer_trace").Check(testkit.Rows("select a from t where b > 1 order by b"))
+
+	// Both the table path and the index path are compared when the index
+	// does not cover the query.
+	tk.MustExec("select * from t where b > 1")
+	_, trace = getTrace()
+	var tableScan, indexLookUp bool
+	for _, candidate := range trace.Physical {
+		tableScan = tableScan || strings.HasPrefix(candidate.Plan, "TableReader(Table(t)")
+		indexLookUp = indexLookUp || strings.HasPrefix(candidate.Plan, "IndexLookUp(Index(t.b)")
+	}
+	c.Assert(tableScan, IsTrue)
+	c.Assert(indexLookUp, IsTrue)
+
+	tk.MustExec("set @@optimizer_trace = 'enabled=on,one_line=on'")
+	tk.MustExec("set @@tidb_enable_cascades_planner = 1")
+	tk.MustExec("select a from t where b > 1")
+	tk.MustExec("set @@tidb_enable_cascades_planner = 0")
+	rows := tk.MustQuery("select trace from information_schema.optimizer_trace").Rows()
+	c.Assert(strings.Contains(rows[0][0].(string), "\n"), IsFalse)
+	_, trace = getTrace()
+	c.Assert(trace.Cascades, Not(HasLen), 0)
+	for _, explore := range trace.Cascades {
+		c.Assert(strings.HasPrefix(explore.Group, "Group#"), IsTrue)
+		c.Assert(explore.Rule, Not(Equals), "")
+	}
+	c.Assert(trace.FinalPlan, Equals, "IndexReader(Index(t.b)[(1,+inf]])->Projection")
+
+	tk.MustExec("set @@optimizer_trace = 'enabled=off'")
+	tk.MustExec("select * from t")
+	query, _ = getTrace()
+	c.Assert(query, Equals, "select a from t where b > 1")
+	_, err := tk.Exec("set @@optimizer_trace = 'enabled=yes'")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("set @@optimizer_trace = 'unknown=on'")
+	c.Assert(err, NotNil)
+
+	// The flags not given keep their current values.
+	tk.MustQuery("select @@optimizer_trace").Check(testkit.Rows("enabled=off,one_line=on"))
+	tk.MustExec("set @@global.optimizer_trace = 'enabled=on'")
+	tk.MustExec("set @@global.optimizer_trace = 'one_line=on'")
+	tk.MustQuery("select @@global.optimizer_trace").Check(testkit.Rows("enabled=on,one_line=on"))
+	tk.MustExec("set @@global.optimizer_trace = 'enabled=off,one_line=off'")
+}
+
+type mockSessionManager struct {
+	processInfoMap map[uint64]*util.ProcessInfo
+}
+
+func (sm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
+	return sm.processInfoMap
+}
+
+func (s *testTableSuite) TestProcesslist(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("select * from information_schema.processlist").Check(testkit.Rows())
+
+	sm := &mockSessionManager{make(map[uint64]*util.ProcessInfo, 2)}
+	sm.processInfoMap[1] = &util.ProcessInfo{
+		ID:      1,
+		User:    "root",
+		Host:    "127.0.0.1",
+		DB:      "test",
+		Command: mysql.ComQuery,
+		Time:    time.Now(),
+		Info:    "select * from t",
+	}
+	sm.processInfoMap[2] = &util.ProcessInfo{
+		ID:      2,
+		User:    "root",
+		Host:    "127.0.0.1",
+		Command: mysql.ComSleep,
+		Time:    time.Now(),
+	}
+	tk.Se.SetSessionManager(sm)
+	tk.MustQuery("select id, user, host, db, command, info, mem from information_schema.processlist order by id").Check(
+		testkit.Rows("1 root 127.0.0.1 test Query select * from t 0",
+			"2 root 127.0.0.1 <nil> Sleep <nil> 0"))
+	// The rows are ordered by id like SHOW PROCESSLIST.
+	for i := 0; i < 10; i++ {
+		tk.MustQuery("select id from information_schema.processlist").Check(testkit.Rows("1", "2"))
+	}
+}
@@ -153,6 +153,7 @@ type MemBuffer interface {
 // This is not thread safe.
 type Transaction interface {
 	MemBuffer
+	BatchGetter
 	// Commit commits the transaction operations to KV store.
 	Commit(context.Context) error
 	// Rollback undoes the transaction operations to KV store.
@@ -244,6 +245,8 @@ type ResultSubset interface {
 	MemSize() int64
 	// RespTime returns the response time for the request.
 	RespTime() time.Duration
+	// CalleeAddress returns the address of the store which handled the request.
+	CalleeAddress() string
 }
 
 // Response represents the response returned from KV layer.
@@ -255,9 +258,17 @@ type Response interface {
 	Close() error
 }
 
+// BatchGetter is the interface for BatchGet.
+type BatchGetter interface {
+	// BatchGet gets a batch of values, the nonexistent keys are not in the
+	// returned map.
+	BatchGet(ctx context.Context, keys []Key) (map[string][]byte, error)
+}
+
 // Snapshot defines the interface for the snapshot fetched from KV store.
 type Snapshot interface {
 	Retriever
+	BatchGetter
 }
 
 // Driver is the interface that must be implemented by a KV storage.
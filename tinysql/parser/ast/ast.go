@@ -48,6 +48,7 @@ const (
 	FlagHasAggregateFunc
 	FlagHasVariable
 	FlagHasDefault
+	FlagHasWindowFunc
 )
 
 // ExprNode is a node that can be evaluated.
@@ -49,8 +49,11 @@ func (ts *testDMLSuite) TestDMLVisitorCover(c *C) {
 
 		// TODO: cover childrens
 		{&InsertStmt{Table: tableRefsClause}, 1, 1},
+		{&UpdateStmt{TableRefs: tableRefsClause}, 1, 1},
 		{&SelectStmt{}, 0, 0},
 		{&FieldList{}, 0, 0},
+		{&SetOprStmt{SelectList: &SetOprSelectList{Selects: []*SelectStmt{{}, {}}}}, 0, 0},
+		{&SetOprStmt{SelectList: &SetOprSelectList{}, Limit: &Limit{Count: ce}}, 1, 1},
 	}
 
 	for _, v := range stmts {
@@ -16,7 +16,7 @@ package ast
 // IsReadOnly checks whether the input ast is readOnly.
 func IsReadOnly(node Node) bool {
 	switch st := node.(type) {
-	case *SelectStmt:
+	case *SelectStmt, *SetOprStmt:
 		checker := readOnlyChecker{
 			readOnly: true,
 		}
@@ -24,7 +24,7 @@ func IsReadOnly(node Node) bool {
 		node.Accept(&checker)
 		return checker.readOnly
 	case *ExplainStmt:
-		return IsReadOnly(st.Stmt)
+		return !st.Analyze || IsReadOnly(st.Stmt)
 	default:
 		return false
 	}
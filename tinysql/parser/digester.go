@@ -0,0 +1,153 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// Normalize generates the normalized statement of the sql. The comments are
+// removed, the keywords and the identifiers are lower-cased and the tokens are
+// separated by exactly one space, the semicolons are ignored, e.g.
+//
+//	"SELECT  * FROM `T` /* comment */ WHERE a = 'A'" => "select * from `t` where a = 'A'".
+//
+// The literals are kept as they are, so two statements have the same
+// normalized statement only if they are semantically the same, which makes
+// it safe to share the plan of them.
+func Normalize(sql string) string {
+	return normalize(sql, false)
+}
+
+// NormalizeForBinding generates the normalized statement used to match the
+// SQL bindings. Besides what Normalize does, the literals are replaced with
+// '?' and the optimizer hints and the index hints are erased, so the
+// statements which only differ in them share the same binding, e.g.
+//
+//	"SELECT /*+ HASH_JOIN(t1) */ * FROM t1 USE INDEX(a) WHERE a = 1" => "select * from t1 where a = ?".
+func NormalizeForBinding(sql string) string {
+	return normalize(sql, true)
+}
+
+type digestToken struct {
+	tok int
+	lit string
+}
+
+func normalize(sql string, forBinding bool) string {
+	tokens := make([]digestToken, 0, 16)
+	s := NewScanner(sql)
+	for {
+		tok, pos, lit := s.scan()
+		if tok == invalid || pos.Offset >= len(sql) {
+			break
+		}
+		if tok == ';' {
+			continue
+		}
+		tokens = append(tokens, digestToken{tok: tok, lit: lit})
+	}
+	if forBinding {
+		tokens = eraseHints(tokens)
+	}
+
+	var buf bytes.Buffer
+	for _, token := range tokens {
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		if forBinding && isLiteral(token.tok) {
+			buf.WriteByte('?')
+			continue
+		}
+		switch token.tok {
+		case stringLit:
+			buf.WriteByte('\'')
+			buf.WriteString(strings.Replace(strings.Replace(token.lit, `\`, `\\`, -1), `'`, `''`, -1))
+			buf.WriteByte('\'')
+		case quotedIdentifier:
+			buf.WriteByte('`')
+			buf.WriteString(strings.Replace(strings.ToLower(token.lit), "`", "``", -1))
+			buf.WriteByte('`')
+		case hintBegin:
+			buf.WriteString("/*+")
+		case hintEnd:
+			buf.WriteString("*/")
+		default:
+			buf.WriteString(strings.ToLower(token.lit))
+		}
+	}
+	return buf.String()
+}
+
+func isLiteral(tok int) bool {
+	switch tok {
+	case stringLit, intLit, floatLit, decLit, hexLit, bitLit:
+		return true
+	}
+	return false
+}
+
+// eraseHints removes the optimizer hints "/*+ ... */" and the index hints
+// "USE|IGNORE|FORCE INDEX|KEY [FOR ...] (...)" from the tokens.
+func eraseHints(tokens []digestToken) []digestToken {
+	result := tokens[:0]
+	for i := 0; i < len(tokens); i++ {
+		switch {
+		case tokens[i].tok == hintBegin:
+			for i < len(tokens) && tokens[i].tok != hintEnd {
+				i++
+			}
+		case isIndexHintBegin(tokens, i):
+			for i < len(tokens) && tokens[i].tok != ')' {
+				i++
+			}
+		default:
+			result = append(result, tokens[i])
+		}
+	}
+	return result
+}
+
+func isIndexHintBegin(tokens []digestToken, i int) bool {
+	// The keywords are scanned as identifiers.
+	if tokens[i].tok != identifier || i+1 >= len(tokens) {
+		return false
+	}
+	switch strings.ToLower(tokens[i].lit) {
+	case "use", "ignore", "force":
+	default:
+		return false
+	}
+	next := strings.ToLower(tokens[i+1].lit)
+	return next == "index" || next == "key"
+}
+
+// DigestHash generates the digest of the normalized statement of the sql.
+func DigestHash(sql string) string {
+	return digest(Normalize(sql))
+}
+
+// NormalizeDigest combines Normalize and DigestHash into one method.
+func NormalizeDigest(sql string) (normalized, digestHash string) {
+	normalized = Normalize(sql)
+	return normalized, digest(normalized)
+}
+
+func digest(normalized string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(normalized)))
+}
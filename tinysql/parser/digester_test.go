@@ -0,0 +1,92 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testSQLDigestSuite{})
+
+type testSQLDigestSuite struct {
+}
+
+func (s *testSQLDigestSuite) TestNormalize(c *C) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"SELECT 1", "select 1"},
+		{"select  *  from `T` where A = 'A';", "select * from `t` where a = 'A'"},
+		{"select * from t -- comment\n where a = ? /* comment */ and b in (1, 2)", "select * from t where a = ? and b in ( 1 , 2 )"},
+		{"select /*+ HASH_JOIN(t1) */ * from t1, t2", "select /*+ hash_join ( t1 ) */ * from t1 , t2"},
+		{"select 'it''s', \"a\\\\b\"", "select 'it''s' , 'a\\\\b'"},
+		{"select 1.5e3, 0x1F, -3", "select 1.5e3 , 0x1f , - 3"},
+		{"insert into t values (?, ?)", "insert into t values ( ? , ? )"},
+	}
+	for _, test := range tests {
+		normalized := Normalize(test.input)
+		c.Assert(normalized, Equals, test.expect, Commentf("%s", test.input))
+
+		normalized2, digest := NormalizeDigest(test.input)
+		c.Assert(normalized2, Equals, normalized)
+		c.Assert(digest, Equals, DigestHash(test.input))
+		c.Assert(digest, HasLen, 64)
+	}
+}
+
+func (s *testSQLDigestSuite) TestDigestHashEqualForSimilarSQL(c *C) {
+	sqlGroups := [][]string{
+		{"select * from t where a = ?", "SELECT * FROM t WHERE a = ?", "select  *  from  t  where  a  =  ?;", "select * from t /* c */ where a = ?"},
+		{"select * from `T` where `A` = ?", "select * from `t` where `a` = ?"},
+	}
+	for _, sqlGroup := range sqlGroups {
+		digest := DigestHash(sqlGroup[0])
+		for _, sql := range sqlGroup[1:] {
+			c.Assert(DigestHash(sql), Equals, digest, Commentf("%s", sql))
+		}
+	}
+
+	sqls := []string{
+		"select * from t where a = 1",
+		"select * from t where a = 2",
+		"select * from t where a = '1'",
+		"select * from t where a = 'A'",
+		"select * from t where a = 'a'",
+	}
+	digests := make(map[string]string)
+	for _, sql := range sqls {
+		digest := DigestHash(sql)
+		other, ok := digests[digest]
+		c.Assert(ok, IsFalse, Commentf("%s and %s have the same digest", sql, other))
+		digests[digest] = sql
+	}
+}
+
+func (s *testSQLDigestSuite) TestNormalizeForBinding(c *C) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"SELECT * FROM t WHERE a = 1 AND b = 'x'", "select * from t where a = ? and b = ?"},
+		{"select /*+ HASH_JOIN(t1) */ * from t1, t2 where t1.a = t2.a", "select * from t1 , t2 where t1 . a = t2 . a"},
+		{"select * from t use index(idx_a) where a > 1.5", "select * from t where a > ?"},
+		{"select * from t IGNORE KEY (idx_a, idx_b) where a in (1, 0x1F)", "select * from t where a in ( ? , ? )"},
+		{"select * from t force index for order by(idx_a) order by a limit 10", "select * from t order by a limit ?"},
+		{"select * from t where a = ?", "select * from t where a = ?"},
+	}
+	for _, test := range tests {
+		c.Assert(NormalizeForBinding(test.input), Equals, test.expect, Commentf("%s", test.input))
+	}
+}
@@ -130,6 +130,7 @@ func init() {
 	initTokenByte('^', int('^'))
 	initTokenByte('~', int('~'))
 	initTokenByte('\\', int('\\'))
+	initTokenByte('?', paramMarker)
 	initTokenByte('=', eq)
 	initTokenByte('{', int('{'))
 	initTokenByte('}', int('}'))
@@ -273,6 +274,7 @@ var tokenMap = map[string]int{
 	"DELAY_KEY_WRITE":          delayKeyWrite,
 	"DELAYED":                  delayed,
 	"DELETE":                   deleteKwd,
+	"DENSE_RANK":               denseRank,
 	"DEPTH":                    depth,
 	"DESC":                     desc,
 	"DESCRIBE":                 describe,
@@ -320,6 +322,7 @@ var tokenMap = map[string]int{
 	"FAULTS":                   faultsSym,
 	"FIELDS":                   fields,
 	"FIRST":                    first,
+	"FIRST_VALUE":              firstValue,
 	"FIXED":                    fixed,
 	"FLOAT":                    floatType,
 	"FLUSH":                    flush,
@@ -379,6 +382,7 @@ var tokenMap = map[string]int{
 	"IO":                       io,
 	"IPC":                      ipc,
 	"INTEGER":                  integerType,
+	"INTERSECT":                intersect,
 	"INTERVAL":                 interval,
 	"INTERNAL":                 internal,
 	"INTO":                     into,
@@ -396,8 +400,11 @@ var tokenMap = map[string]int{
 	"KEYS":                     keys,
 	"KILL":                     kill,
 	"LABELS":                   labels,
+	"LAG":                      lag,
 	"LANGUAGE":                 language,
 	"LAST":                     last,
+	"LAST_VALUE":               lastValue,
+	"LEAD":                     lead,
 	"LEADING":                  leading,
 	"LEFT":                     left,
 	"LESS":                     less,
@@ -436,6 +443,7 @@ var tokenMap = map[string]int{
 	"MEMORY":                   memory,
 	"MEMORY_QUOTA":             hintMemoryQuota,
 	"MERGE":                    merge,
+	"MERGE_JOIN":               hintSMJ,
 	"MICROSECOND":              microsecond,
 	"MIN":                      min,
 	"MIN_ROWS":                 minRows,
@@ -484,6 +492,7 @@ var tokenMap = map[string]int{
 	"OR":                       or,
 	"ORDER":                    order,
 	"OUTER":                    outer,
+	"OVER":                     over,
 	"PACK_KEYS":                packKeys,
 	"PAGE":                     pageSym,
 	"PARSER":                   parser,
@@ -517,6 +526,7 @@ var tokenMap = map[string]int{
 	"SHARD_ROW_ID_BITS":        shardRowIDBits,
 	"PRE_SPLIT_REGIONS":        preSplitRegions,
 	"RANGE":                    rangeKwd,
+	"RANK":                     rank,
 	"RECOVER":                  recover,
 	"REBUILD":                  rebuild,
 	"READ":                     read,
@@ -550,6 +560,8 @@ var tokenMap = map[string]int{
 	"ROLLBACK":                 rollback,
 	"ROUTINE":                  routine,
 	"ROW":                      row,
+	"ROWS":                     rows,
+	"ROW_NUMBER":               rowNumber,
 	"ROW_COUNT":                rowCount,
 	"ROW_FORMAT":               rowFormat,
 	"RTREE":                    rtree,
@@ -773,13 +785,14 @@ var btFuncTokenMap = map[string]int{
 
 // aliases are strings directly map to another string and use the same token.
 var aliases = map[string]string{
-	"SCHEMA":    "DATABASE",
-	"SCHEMAS":   "DATABASES",
-	"DEC":       "DECIMAL",
-	"SUBSTR":    "SUBSTRING",
-	"TIDB_HJ":   "HASH_JOIN",
-	"TIDB_INLJ": "INL_JOIN",
-	"TIDB_SMJ":  "SM_JOIN",
+	"SCHEMA":     "DATABASE",
+	"SCHEMAS":    "DATABASES",
+	"DEC":        "DECIMAL",
+	"SUBSTR":     "SUBSTRING",
+	"TIDB_HJ":    "HASH_JOIN",
+	"TIDB_INLJ":  "INL_JOIN",
+	"TIDB_SMJ":   "SM_JOIN",
+	"MERGE_JOIN": "SM_JOIN",
 }
 
 func (s *Scanner) isTokenIdentifier(lit string, offset int) int {
@@ -104,6 +104,7 @@ import (
 	defaultKwd		"DEFAULT"
 	delayed			"DELAYED"
 	deleteKwd		"DELETE"
+	denseRank		"DENSE_RANK"
 	desc			"DESC"
 	describe		"DESCRIBE"
 	distinct		"DISTINCT"
@@ -119,6 +120,7 @@ import (
 	explain			"EXPLAIN"
 	except			"EXCEPT"
 	falseKwd		"FALSE"
+	firstValue		"FIRST_VALUE"
 	floatType		"FLOAT"
 	forKwd			"FOR"
 	force			"FORCE"
@@ -140,6 +142,7 @@ import (
 	infile			"INFILE"
 	inner 			"INNER"
 	integerType		"INTEGER"
+	intersect		"INTERSECT"
 	interval		"INTERVAL"
 	into			"INTO"
 	is			"IS"
@@ -154,7 +157,10 @@ import (
 	key			"KEY"
 	keys			"KEYS"
 	kill			"KILL"
+	lag			"LAG"
 	language		"LANGUAGE"
+	lastValue		"LAST_VALUE"
+	lead			"LEAD"
 	leading			"LEADING"
 	left			"LEFT"
 	like			"LIKE"
@@ -188,6 +194,7 @@ import (
 	or			"OR"
 	order			"ORDER"
 	outer			"OUTER"
+	over			"OVER"
 	packKeys		"PACK_KEYS"
 	partition		"PARTITION"
 	parser			"PARSER"
@@ -197,6 +204,7 @@ import (
 	shardRowIDBits		"SHARD_ROW_ID_BITS"
 	preSplitRegions		"PRE_SPLIT_REGIONS"
 	rangeKwd		"RANGE"
+	rank			"RANK"
 	read			"READ"
 	realType		"REAL"
 	references		"REFERENCES"
@@ -210,6 +218,8 @@ import (
 	right			"RIGHT"
 	rlike			"RLIKE"
 	row			"ROW"
+	rows			"ROWS"
+	rowNumber		"ROW_NUMBER"
 	secondMicrosecond	"SECOND_MICROSECOND"
 	selectKwd		"SELECT"
 	set			"SET"
@@ -682,6 +692,7 @@ import (
 	neq		"!="
 	neqSynonym	"<>"
 	nulleq		"<=>"
+	paramMarker	"?"
 	rsh		">>"
 
 %token not2
@@ -695,7 +706,9 @@ import (
 	BitExpr				"bit expression"
 	SimpleExpr			"simple expression"
 	SimpleIdent			"Simple Identifier expression"
+	SubSelect			"Sub Select"
 	SumExpr				"aggregate functions"
+	WindowFuncCall			"WINDOW function call"
 	FunctionCallGeneric		"Function call with Identifier"
 	FunctionCallKeyword		"Function call with keyword as function name"
 	FunctionCallNonKeyword		"Function call with nonkeyword as function name"
@@ -720,21 +733,29 @@ import (
 	CreateTableStmt			"CREATE TABLE statement"
 	CreateDatabaseStmt		"Create Database Statement"
 	CreateIndexStmt			"CREATE INDEX statement"
+	CreateBindingStmt		"CREATE BINDING statement"
+	DeallocateStmt			"Deallocate prepared statement"
 	DropDatabaseStmt		"DROP DATABASE statement"
 	DropIndexStmt			"DROP INDEX statement"
 	DropTableStmt			"DROP TABLE statement"
+	DropBindingStmt			"DROP BINDING statement"
 	DeleteFromStmt			"DELETE FROM statement"
 	EmptyStmt			"empty statement"
+	ExecuteStmt			"Execute statement"
 	ExplainStmt			"EXPLAIN statement"
 	ExplainableStmt			"explainable statement"
 	InsertIntoStmt			"INSERT INTO statement"
+	LoadDataStmt			"Load data statement"
+	PreparedStmt			"PreparedStmt"
 	SelectStmt			"SELECT statement"
 	ReplaceIntoStmt			"REPLACE INTO statement"
 	RollbackStmt			"ROLLBACK statement"
 	SetStmt				"Set variable statement"
+	SetOprStmt			"Set operation statement, such as UNION, EXCEPT and INTERSECT"
 	ShowStmt			"Show engines/databases/tables/user/columns/warnings/status statement"
 	Statement			"statement"
 	TruncateTableStmt		"TRUNCATE TABLE statement"
+	UpdateStmt			"UPDATE statement"
 	UseStmt				"USE statement"
 
 %type   <item>
@@ -772,6 +793,7 @@ import (
 	DistinctOpt			"Explicit distinct option"
 	DefaultFalseDistinctOpt		"Distinct option which defaults to false"
 	DefaultTrueDistinctOpt		"Distinct option which defaults to true"
+	ElseOpt				"Optional else clause"
 	EqOpt				"= or empty"
 	EscapedTableRef 		"escaped table reference"
 	ExplainFormatType		"explain format type"
@@ -808,7 +830,16 @@ import (
 	IndexPartSpecification		"Index column name or expression"
 	IndexPartSpecificationList	"List of index column name or expression"
 	IndexPartSpecificationListOpt   "Optional list of index column name or expression"
+	IgnoreOptional			"IGNORE or empty"
 	InsertValues			"Rest part of INSERT/REPLACE INTO statement"
+	ColumnNameListOptWithBrackets	"column name list opt with brackets"
+	DuplicateOpt			"[IGNORE|REPLACE] in LOAD DATA statement"
+	FieldItem			"Field item for load data clause"
+	FieldItemList			"Field items for load data clause"
+	Fields				"Fields clause"
+	IgnoreLines			"Ignore num(int) lines"
+	Lines				"Lines clause"
+	LocalOpt			"Local opt"
 	JoinTable 			"join table"
 	JoinType			"join type"
 	LocationLabelList		"location label name list"
@@ -817,6 +848,7 @@ import (
 	LimitClause			"LIMIT clause"
 	LimitOption			"Limit option could be integer or parameter marker."
 	NumLiteral			"Num/Int/Float/Decimal Literal"
+	OnDuplicateKeyUpdate		"ON DUPLICATE KEY UPDATE value list"
 	OptFull				"Full or empty"
 	OptTemporary			"TEMPORARY or empty"
 	Order				"ORDER BY clause optional collation specification"
@@ -826,6 +858,7 @@ import (
 	ByList				"BY list"
 	QuickOptional			"QUICK or empty"
 	QueryBlockOpt			"Query block identifier optional"
+	PrepareSQL			"Prepare statement sql string"
 	PriorityOpt			"Statement priority option"
 	OptGConcatSeparator		"optional GROUP_CONCAT SEPARATOR"
 	RowValue			"Row value"
@@ -842,6 +875,9 @@ import (
 	SelectStmtFromDualTable			"SELECT statement from dual table"
 	SelectStmtFromTable			"SELECT statement from table"
 	SelectStmtGroup			"SELECT statement optional GROUP BY clause"
+	SetOpr				"Set operator"
+	SetOprClauseList		"Set operation select list except the last one"
+	SetOprSelect			"Select statement in a set operation"
 	ShowTargetFilterable    	"Show target that can be filtered by WHERE or LIKE"
 	ShowDatabaseNameOpt		"Show tables/columns statement database name option"
 	ShowTableAliasOpt       	"Show table alias option"
@@ -863,12 +899,28 @@ import (
 	TableNameListOpt		"Table name list opt"
 	TableRef 			"table reference"
 	TableRefs 			"table references"
+	OptLeadLagInfo			"Optional LEAD/LAG info"
+	OptLLDefault			"Optional LEAD/LAG default"
+	OptPartitionClause		"Optional PARTITION BY clause"
+	OptWindowingClause		"Optional OVER clause"
+	OptWindowFrameClause		"Optional window frame clause"
+	OptWindowOrderByClause		"Optional ORDER BY clause in window"
+	WindowingClause			"OVER clause"
+	WindowSpecDetails		"WINDOW specification details"
+	WindowFrameUnits		"WINDOW frame units"
+	WindowFrameExtent		"WINDOW frame extent"
+	WindowFrameStart		"WINDOW frame start"
+	WindowFrameBetween		"WINDOW frame between"
+	WindowFrameBound		"WINDOW frame bound"
 
 	Values			"values"
 	ValuesList		"values list"
 	ValuesOpt		"values optional"
+	UserVariableList	"User defined variable name list"
 	VariableAssignment	"set variable value"
 	VariableAssignmentList	"set variable value list"
+	WhenClause		"When clause"
+	WhenClauseList		"When clause list"
 	WhereClause		"WHERE clause"
 	WhereClauseOptional	"Optional WHERE clause"
 	WithValidation		"with validation"
@@ -880,6 +932,8 @@ import (
 	BetweenOrNotOp		"Between predicate"
 	IsOrNotOp		"Is predicate"
 	InOrNotOp		"In predicate"
+	LikeOrNotOp		"Like predicate"
+	RegexpOrNotOp		"Regexp predicate"
 
 	NumericType		"Numeric types"
 	IntegerType		"Integer Types types"
@@ -891,6 +945,8 @@ import (
 	BlobType		"Blob types"
 	TextType		"Text types"
 	DateAndTimeType		"Date and Time types"
+	TimeUnit		"Time unit for 'DATE_ADD', 'DATE_SUB', 'ADDDATE', 'SUBDATE', 'EXTRACT'"
+	TrimDirection		"Trim string direction"
 
 	OptFieldLen		"Field length or empty"
 	FieldLen		"Field length"
@@ -930,7 +986,9 @@ import (
 	NowSymFunc		"CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP/NOW"
 	DefaultKwdOpt		"optional DEFAULT keyword"
 	DatabaseSym		"DATABASE or SCHEMA"
+	DeallocateSym		"Deallocate or drop"
 	ExplainSym		"EXPLAIN or DESCRIBE or DESC"
+	RegexpSym		"REGEXP or RLIKE"
 	IntoOpt			"INTO or EmptyString"
 	ValueSym		"Value or Values"
 	Char			"{CHAR|CHARACTER}"
@@ -962,6 +1020,10 @@ import (
 	FunctionNameDateArith		"Date arith function call names (date_add or date_sub)"
 	FunctionNameDateArithMultiForms	"Date arith function call names (adddate or subdate)"
 	VariableName			"A simple Identifier like xx or the xx.xx form"
+	FieldsOrColumns			"Fields or columns"
+	FieldTerminator			"Field terminator"
+	Starting			"Starting by"
+	LinesTerminated			"Lines terminated by"
 
 %precedence empty
 
@@ -2150,6 +2212,14 @@ ExplainStmt:
 			Format: $4.(string),
 		}
 	}
+|	ExplainSym "ANALYZE" ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:    $3,
+			Format:  "row",
+			Analyze: true,
+		}
+	}
 
 ExplainFormatType:
 	"TRADITIONAL"
@@ -2196,7 +2266,13 @@ Expression:
 	}
 |	"NOT" Expression %prec not
 	{
-		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
+		expr, ok := $2.(*ast.ExistsSubqueryExpr)
+		if ok {
+			expr.Not = true
+			$$ = $2
+		} else {
+			$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
+		}
 	}
 |	BoolPri IsOrNotOp "UNKNOWN" %prec is
 	{
@@ -2265,6 +2341,11 @@ BoolPri:
 		}
 		$$ = &ast.BinaryOperationExpr{Op: $2.(opcode.Op), L: $1, R: variable}
 	}
+|	BoolPri CompareOp AnyOrAll SubSelect %prec eq
+	{
+		sq := $4.(*ast.SubqueryExpr)
+		$$ = &ast.CompareSubqueryExpr{Op: $2.(opcode.Op), L: $1, R: sq, All: $3.(bool)}
+	}
 |	PredicateExpr
 
 CompareOp:
@@ -2331,6 +2412,30 @@ InOrNotOp:
 		$$ = false
 	}
 
+LikeOrNotOp:
+	"LIKE"
+	{
+		$$ = true
+	}
+|	"NOT" "LIKE"
+	{
+		$$ = false
+	}
+
+RegexpOrNotOp:
+	RegexpSym
+	{
+		$$ = true
+	}
+|	"NOT" RegexpSym
+	{
+		$$ = false
+	}
+
+RegexpSym:
+	"REGEXP"
+|	"RLIKE"
+
 AnyOrAll:
 	"ANY"
 	{
@@ -2350,6 +2455,31 @@ PredicateExpr:
 	{
 		$$ = &ast.PatternInExpr{Expr: $1, Not: !$2.(bool), List: $4.([]ast.ExprNode)}
 	}
+|	BitExpr InOrNotOp SubSelect
+	{
+		sq := $3.(*ast.SubqueryExpr)
+		$$ = &ast.PatternInExpr{Expr: $1, Not: !$2.(bool), Sel: sq}
+	}
+|	BitExpr LikeOrNotOp SimpleExpr LikeEscapeOpt
+	{
+		escape := $4.(string)
+		if len(escape) > 1 {
+			yylex.AppendError(ErrWrongArguments.GenWithStackByArgs("ESCAPE"))
+			return 1
+		} else if len(escape) == 0 {
+			escape = "\\"
+		}
+		$$ = &ast.PatternLikeExpr{
+			Expr:		$1,
+			Pattern:	$3,
+			Not:		!$2.(bool),
+			Escape:		escape[0],
+		}
+	}
+|	BitExpr RegexpOrNotOp SimpleExpr
+	{
+		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
+	}
 |	BitExpr BetweenOrNotOp BitExpr "AND" PredicateExpr
 	{
 		$$ = &ast.BetweenExpr{
@@ -2675,13 +2805,17 @@ NotKeywordToken:
  *
  **********************************************************************************/
 InsertIntoStmt:
-	"INSERT" PriorityOpt IntoOpt TableName InsertValues
+	"INSERT" PriorityOpt IgnoreOptional IntoOpt TableName InsertValues OnDuplicateKeyUpdate
 	{
-		x := $5.(*ast.InsertStmt)
+		x := $6.(*ast.InsertStmt)
 		x.Priority = $2.(mysql.PriorityEnum)
+		x.IgnoreErr = $3.(bool)
 		// Wraps many layers here so that it can be processed the same way as select statement.
-		ts := &ast.TableSource{Source: $4.(*ast.TableName)}
+		ts := &ast.TableSource{Source: $5.(*ast.TableName)}
 		x.Table = &ast.TableRefsClause{TableRefs: &ast.Join{Left: ts}}
+		if $7 != nil {
+			x.OnDuplicate = $7.([]*ast.Assignment)
+		}
 		$$ = x
 	}
 
@@ -2717,6 +2851,10 @@ InsertValues:
 	{
 		$$ = &ast.InsertStmt{Select: $1.(*ast.SelectStmt)}
 	}
+|	SetOprStmt
+	{
+		$$ = &ast.InsertStmt{Select: $1.(*ast.SetOprStmt)}
+	}
 |	"SET" ColumnSetValueList
 	{
 		$$ = &ast.InsertStmt{Setlist: $2.([]*ast.Assignment)}
@@ -2725,6 +2863,15 @@ InsertValues:
 ValueSym:
 "VALUE" | "VALUES"
 
+OnDuplicateKeyUpdate:
+	{
+		$$ = nil
+	}
+|	"ON" "DUPLICATE" "KEY" "UPDATE" AssignmentList
+	{
+		$$ = $5
+	}
+
 ValuesList:
 	RowValue
 	{
@@ -2807,6 +2954,213 @@ ReplaceIntoStmt:
 
 /***********************************Replace Statements END************************************/
 
+/*******************************************************************************************
+ * Load Data Statement
+ * See https://dev.mysql.com/doc/refman/5.7/en/load-data.html
+ *
+ * LOAD DATA [LOCAL] INFILE 'file_name'
+ *     [REPLACE | IGNORE]
+ *     INTO TABLE tbl_name
+ *     [{FIELDS | COLUMNS}
+ *         [TERMINATED BY 'string']
+ *         [[OPTIONALLY] ENCLOSED BY 'char']
+ *         [ESCAPED BY 'char']
+ *     ]
+ *     [LINES
+ *         [STARTING BY 'string']
+ *         [TERMINATED BY 'string']
+ *     ]
+ *     [IGNORE number LINES]
+ *     [(col_name,...)]
+ *******************************************************************************************/
+LoadDataStmt:
+	"LOAD" "DATA" LocalOpt "INFILE" stringLit DuplicateOpt "INTO" "TABLE" TableName Fields Lines IgnoreLines ColumnNameListOptWithBrackets
+	{
+		$$ = &ast.LoadDataStmt{
+			IsLocal:     $3.(bool),
+			Path:        $5,
+			OnDuplicate: $6.(ast.OnDuplicateKeyHandlingType),
+			Table:       $9.(*ast.TableName),
+			FieldsInfo:  $10.(*ast.FieldsClause),
+			LinesInfo:   $11.(*ast.LinesClause),
+			IgnoreLines: $12.(uint64),
+			Columns:     $13.([]*ast.ColumnName),
+		}
+	}
+
+LocalOpt:
+	{
+		$$ = false
+	}
+|	"LOCAL"
+	{
+		$$ = true
+	}
+
+DuplicateOpt:
+	{
+		$$ = ast.OnDuplicateKeyHandlingError
+	}
+|	"IGNORE"
+	{
+		$$ = ast.OnDuplicateKeyHandlingIgnore
+	}
+|	"REPLACE"
+	{
+		$$ = ast.OnDuplicateKeyHandlingReplace
+	}
+
+Fields:
+	{
+		$$ = &ast.FieldsClause{
+			Terminated: "\t",
+			Escaped:    '\\',
+		}
+	}
+|	FieldsOrColumns FieldItemList
+	{
+		fieldsClause := &ast.FieldsClause{
+			Terminated: "\t",
+			Escaped:    '\\',
+		}
+		for _, item := range $2.([]*ast.FieldItem) {
+			switch item.Type {
+			case ast.Terminated:
+				fieldsClause.Terminated = item.Value
+			case ast.Enclosed:
+				var enclosed byte
+				if len(item.Value) > 0 {
+					enclosed = item.Value[0]
+				}
+				fieldsClause.Enclosed = enclosed
+				fieldsClause.OptEnclosed = item.OptEnclosed
+			case ast.Escaped:
+				var escaped byte
+				if len(item.Value) > 0 {
+					escaped = item.Value[0]
+				}
+				fieldsClause.Escaped = escaped
+			}
+		}
+		$$ = fieldsClause
+	}
+
+FieldsOrColumns:
+	"FIELDS"
+|	"COLUMNS"
+
+FieldItemList:
+	FieldItemList FieldItem
+	{
+		$$ = append($1.([]*ast.FieldItem), $2.(*ast.FieldItem))
+	}
+|	FieldItem
+	{
+		$$ = []*ast.FieldItem{$1.(*ast.FieldItem)}
+	}
+
+FieldItem:
+	"TERMINATED" "BY" FieldTerminator
+	{
+		$$ = &ast.FieldItem{
+			Type:  ast.Terminated,
+			Value: $3,
+		}
+	}
+|	"OPTIONALLY" "ENCLOSED" "BY" FieldTerminator
+	{
+		str := $4
+		if str != "\\" && len(str) > 1 {
+			yylex.AppendError(ErrWrongFieldTerminators.GenWithStackByArgs())
+			return 1
+		}
+		$$ = &ast.FieldItem{
+			Type:        ast.Enclosed,
+			Value:       str,
+			OptEnclosed: true,
+		}
+	}
+|	"ENCLOSED" "BY" FieldTerminator
+	{
+		str := $3
+		if str != "\\" && len(str) > 1 {
+			yylex.AppendError(ErrWrongFieldTerminators.GenWithStackByArgs())
+			return 1
+		}
+		$$ = &ast.FieldItem{
+			Type:  ast.Enclosed,
+			Value: str,
+		}
+	}
+|	"ESCAPED" "BY" FieldTerminator
+	{
+		str := $3
+		if str != "\\" && len(str) > 1 {
+			yylex.AppendError(ErrWrongFieldTerminators.GenWithStackByArgs())
+			return 1
+		}
+		$$ = &ast.FieldItem{
+			Type:  ast.Escaped,
+			Value: str,
+		}
+	}
+
+FieldTerminator:
+	stringLit
+|	hexLit
+	{
+		$$ = $1.(ast.BinaryLiteral).ToString()
+	}
+|	bitLit
+	{
+		$$ = $1.(ast.BinaryLiteral).ToString()
+	}
+
+Lines:
+	{
+		$$ = &ast.LinesClause{Terminated: "\n"}
+	}
+|	"LINES" Starting LinesTerminated
+	{
+		$$ = &ast.LinesClause{Starting: $2, Terminated: $3}
+	}
+
+Starting:
+	{
+		$$ = ""
+	}
+|	"STARTING" "BY" FieldTerminator
+	{
+		$$ = $3
+	}
+
+LinesTerminated:
+	{
+		$$ = "\n"
+	}
+|	"TERMINATED" "BY" FieldTerminator
+	{
+		$$ = $3
+	}
+
+IgnoreLines:
+	{
+		$$ = uint64(0)
+	}
+|	"IGNORE" NUM "LINES"
+	{
+		$$ = getUint64FromNUM($2)
+	}
+
+ColumnNameListOptWithBrackets:
+	{
+		$$ = []*ast.ColumnName{}
+	}
+|	'(' ColumnNameListOpt ')'
+	{
+		$$ = $2.([]*ast.ColumnName)
+	}
+
 Literal:
 	"FALSE"
 	{
@@ -2952,6 +3306,28 @@ BitExpr:
 	{
 		$$ = &ast.BinaryOperationExpr{Op: opcode.Minus, L: $1, R: $3}
 	}
+|	BitExpr '+' "INTERVAL" Expression TimeUnit %prec '+'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr("DATE_ADD"),
+			Args: []ast.ExprNode{
+				$1,
+				$4,
+				&ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)},
+			},
+		}
+	}
+|	BitExpr '-' "INTERVAL" Expression TimeUnit %prec '+'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr("DATE_SUB"),
+			Args: []ast.ExprNode{
+				$1,
+				$4,
+				&ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)},
+			},
+		}
+	}
 |	BitExpr '*' BitExpr %prec '*'
 	{
 		$$ = &ast.BinaryOperationExpr{Op: opcode.Mul, L: $1, R: $3}
@@ -3019,8 +3395,13 @@ SimpleExpr:
 		$$ = $1
 	}
 |	Literal
+|	paramMarker
+	{
+		$$ = ast.NewParamMarkerExpr(yyS[yypt].offset)
+	}
 |	Variable
 |	SumExpr
+|	WindowFuncCall
 |	'!' SimpleExpr %prec neg
 	{
 		$$ = &ast.UnaryOperationExpr{Op: opcode.Not, V: $2}
@@ -3058,6 +3439,13 @@ SimpleExpr:
 		values := append($3.([]ast.ExprNode), $5)
 		$$ = &ast.RowExpr{Values: values}
 	}
+|	"EXISTS" SubSelect
+	{
+		sq := $2.(*ast.SubqueryExpr)
+		sq.Exists = true
+		$$ = &ast.ExistsSubqueryExpr{Sel: sq}
+	}
+|	SubSelect
 |	"CONVERT" '(' Expression "USING" CharsetName ')'
 	{
 		// See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_convert
@@ -3067,6 +3455,17 @@ SimpleExpr:
 			Args: []ast.ExprNode{$3, charset1},
 		}
 	}
+|	"CASE" ExpressionOpt WhenClauseList ElseOpt "END"
+	{
+		x := &ast.CaseExpr{WhenClauses: $3.([]*ast.WhenClause)}
+		if $2 != nil {
+			x.Value = $2
+		}
+		if $4 != nil {
+			x.ElseClause = $4.(ast.ExprNode)
+		}
+		$$ = x
+	}
 |	"DEFAULT" '(' SimpleIdent ')'
 	{
 		$$ = &ast.DefaultExpr{Name: $3.(*ast.ColumnNameExpr).Name}
@@ -3075,6 +3474,46 @@ SimpleExpr:
 	{
 		$$ = &ast.ValuesExpr{Column: $3.(*ast.ColumnNameExpr)}
 	}
+|	SimpleIdent jss stringLit
+	{
+		expr := ast.NewValueExpr($3)
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONExtract), Args: []ast.ExprNode{$1, expr}}
+	}
+|	SimpleIdent juss stringLit
+	{
+		expr := ast.NewValueExpr($3)
+		extract := &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONExtract), Args: []ast.ExprNode{$1, expr}}
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONUnquote), Args: []ast.ExprNode{extract}}
+	}
+
+WhenClauseList:
+	WhenClause
+	{
+		$$ = []*ast.WhenClause{$1.(*ast.WhenClause)}
+	}
+|	WhenClauseList WhenClause
+	{
+		$$ = append($1.([]*ast.WhenClause), $2.(*ast.WhenClause))
+	}
+
+WhenClause:
+	"WHEN" Expression "THEN" Expression
+	{
+		$$ = &ast.WhenClause{
+			Expr: $2,
+			Result: $4,
+		}
+	}
+
+ElseOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"ELSE" Expression
+	{
+		$$ = $2
+	}
 
 DistinctKwd:
 	"DISTINCT"
@@ -3151,138 +3590,493 @@ FunctionNameDatetimePrecision:
 |	"UTC_TIME"
 |	"UTC_TIMESTAMP"
 
-FunctionCallKeyword:
-	FunctionNameConflict '(' ExpressionListOpt ')'
+FunctionCallKeyword:
+	FunctionNameConflict '(' ExpressionListOpt ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+	}
+|	builtinUser '('	ExpressionListOpt ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+	}
+|	FunctionNameOptionalBraces OptionalBraces
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1)}
+	}
+|	builtinCurDate '(' ')'
+    {
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1)}
+    }
+|	FunctionNameDatetimePrecision FuncDatetimePrec
+	{
+		args := []ast.ExprNode{}
+		if $2 != nil {
+			args = append(args, $2.(ast.ExprNode))
+		}
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: args}
+	}
+|	"MOD" '(' BitExpr ',' BitExpr ')'
+	{
+		$$ = &ast.BinaryOperationExpr{Op: opcode.Mod, L: $3, R: $5}
+	}
+
+FunctionCallNonKeyword:
+	builtinCurTime '(' FuncDatetimePrecListOpt ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+	}
+|	builtinSysDate '(' FuncDatetimePrecListOpt ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+	}
+|	builtinPosition '(' BitExpr "IN" Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3, $5}}
+	}
+|	builtinSubstring '(' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $5},
+		}
+	}
+|	builtinSubstring '(' Expression "FROM" Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $5},
+		}
+	}
+|	builtinSubstring '(' Expression ',' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $5, $7},
+		}
+	}
+|	builtinSubstring '(' Expression "FROM" Expression "FOR" Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3, $5, $7},
+		}
+	}
+|	builtinTrim '(' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$3},
+		}
+	}
+|	builtinTrim '(' Expression "FROM" Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$5, $3},
+		}
+	}
+|	builtinTrim '(' TrimDirection "FROM" Expression ')'
+	{
+		nilVal := ast.NewValueExpr(nil)
+		direction := &ast.TrimDirectionExpr{Direction: $3.(ast.TrimDirectionType)}
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$5, nilVal, direction},
+		}
+	}
+|	builtinTrim '(' TrimDirection Expression "FROM" Expression ')'
+	{
+		direction := &ast.TrimDirectionExpr{Direction: $3.(ast.TrimDirectionType)}
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{$6, $4, direction},
+		}
+	}
+|	FunctionNameDateArithMultiForms '(' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{
+				$3,
+				$5,
+				&ast.TimeUnitExpr{Unit: ast.TimeUnitDay},
+			},
+		}
+	}
+|	FunctionNameDateArithMultiForms '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{
+				$3,
+				$6,
+				&ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)},
+			},
+		}
+	}
+|	FunctionNameDateArith '(' Expression ',' "INTERVAL" Expression TimeUnit ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{
+				$3,
+				$6,
+				&ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)},
+			},
+		}
+	}
+|	builtinExtract '(' TimeUnit "FROM" Expression ')'
+	{
+		timeUnit := &ast.TimeUnitExpr{Unit: $3.(ast.TimeUnitType)}
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args: []ast.ExprNode{timeUnit, $5},
+		}
+	}
+
+FunctionNameDateArith:
+	builtinDateAdd
+|	builtinDateSub
+
+TrimDirection:
+	"BOTH"
+	{
+		$$ = ast.TrimBoth
+	}
+|	"LEADING"
+	{
+		$$ = ast.TrimLeading
+	}
+|	"TRAILING"
+	{
+		$$ = ast.TrimTrailing
+	}
+
+TimeUnit:
+	"MICROSECOND"
+	{
+		$$ = ast.TimeUnitMicrosecond
+	}
+|	"SECOND"
+	{
+		$$ = ast.TimeUnitSecond
+	}
+|	"MINUTE"
+	{
+		$$ = ast.TimeUnitMinute
+	}
+|	"HOUR"
+	{
+		$$ = ast.TimeUnitHour
+	}
+|	"DAY"
+	{
+		$$ = ast.TimeUnitDay
+	}
+|	"WEEK"
+	{
+		$$ = ast.TimeUnitWeek
+	}
+|	"MONTH"
+	{
+		$$ = ast.TimeUnitMonth
+	}
+|	"QUARTER"
+	{
+		$$ = ast.TimeUnitQuarter
+	}
+|	"YEAR"
+	{
+		$$ = ast.TimeUnitYear
+	}
+|	"SECOND_MICROSECOND"
+	{
+		$$ = ast.TimeUnitSecondMicrosecond
+	}
+|	"MINUTE_MICROSECOND"
+	{
+		$$ = ast.TimeUnitMinuteMicrosecond
+	}
+|	"MINUTE_SECOND"
+	{
+		$$ = ast.TimeUnitMinuteSecond
+	}
+|	"HOUR_MICROSECOND"
+	{
+		$$ = ast.TimeUnitHourMicrosecond
+	}
+|	"HOUR_SECOND"
+	{
+		$$ = ast.TimeUnitHourSecond
+	}
+|	"HOUR_MINUTE"
+	{
+		$$ = ast.TimeUnitHourMinute
+	}
+|	"DAY_MICROSECOND"
+	{
+		$$ = ast.TimeUnitDayMicrosecond
+	}
+|	"DAY_SECOND"
+	{
+		$$ = ast.TimeUnitDaySecond
+	}
+|	"DAY_MINUTE"
+	{
+		$$ = ast.TimeUnitDayMinute
+	}
+|	"DAY_HOUR"
+	{
+		$$ = ast.TimeUnitDayHour
+	}
+|	"YEAR_MONTH"
+	{
+		$$ = ast.TimeUnitYearMonth
+	}
+
+
+FunctionNameDateArithMultiForms:
+	builtinAddDate
+|	builtinSubDate
+
+SumExpr:
+	"AVG" '(' Expression ')' OptWindowingClause
+	{
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *($5.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
+	}
+|	builtinCount '(' Expression ')' OptWindowingClause
+	{
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *($5.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
+	}
+|	builtinCount '(' '*' ')' OptWindowingClause
+	{
+		args := []ast.ExprNode{ast.NewValueExpr(1)}
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: *($5.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
+		}
+	}
+|	builtinMax '(' Expression ')' OptWindowingClause
+	{
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *($5.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
+	}
+|	builtinMin '(' Expression ')' OptWindowingClause
+	{
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *($5.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
+	}
+|	builtinSum '(' Expression ')' OptWindowingClause
+	{
+		if $5 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: *($5.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		}
+	}
+
+OptGConcatSeparator:
+        {
+            	$$ = ast.NewValueExpr(",")
+        }
+| "SEPARATOR" stringLit
+	{
+		$$ = ast.NewValueExpr($2)
+	}
+
+
+/************************************************************************************
+ *  Window Functions
+ *  See https://dev.mysql.com/doc/refman/8.0/en/window-functions.html
+ ***********************************************************************************/
+WindowFuncCall:
+	"ROW_NUMBER" '(' ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: $4.(ast.WindowSpec)}
+	}
+|	"RANK" '(' ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: $4.(ast.WindowSpec)}
+	}
+|	"DENSE_RANK" '(' ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Spec: $4.(ast.WindowSpec)}
+	}
+|	"LEAD" '(' Expression OptLeadLagInfo ')' WindowingClause
+	{
+		args := []ast.ExprNode{$3}
+		if $4 != nil {
+			args = append(args, $4.([]ast.ExprNode)...)
+		}
+		$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: $6.(ast.WindowSpec)}
+	}
+|	"LAG" '(' Expression OptLeadLagInfo ')' WindowingClause
+	{
+		args := []ast.ExprNode{$3}
+		if $4 != nil {
+			args = append(args, $4.([]ast.ExprNode)...)
+		}
+		$$ = &ast.WindowFuncExpr{F: $1, Args: args, Spec: $6.(ast.WindowSpec)}
+	}
+|	"FIRST_VALUE" '(' Expression ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: $5.(ast.WindowSpec)}
+	}
+|	"LAST_VALUE" '(' Expression ')' WindowingClause
+	{
+		$$ = &ast.WindowFuncExpr{F: $1, Args: []ast.ExprNode{$3}, Spec: $5.(ast.WindowSpec)}
+	}
+
+OptLeadLagInfo:
+	{
+		$$ = nil
+	}
+|	',' NumLiteral OptLLDefault
+	{
+		args := []ast.ExprNode{ast.NewValueExpr($2)}
+		if $3 != nil {
+			args = append(args, $3.(ast.ExprNode))
+		}
+		$$ = args
+	}
+
+OptLLDefault:
+	{
+		$$ = nil
+	}
+|	',' Expression
+	{
+		$$ = $2
+	}
+
+OptWindowingClause:
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+		$$ = nil
 	}
-|	builtinUser '('	ExpressionListOpt ')'
+|	WindowingClause
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+		spec := $1.(ast.WindowSpec)
+		$$ = &spec
 	}
-|	FunctionNameOptionalBraces OptionalBraces
+
+WindowingClause:
+	"OVER" '(' WindowSpecDetails ')'
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1)}
+		$$ = $3.(ast.WindowSpec)
 	}
-|	builtinCurDate '(' ')'
-    {
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1)}
-    }
-|	FunctionNameDatetimePrecision FuncDatetimePrec
+
+WindowSpecDetails:
+	OptPartitionClause OptWindowOrderByClause OptWindowFrameClause
 	{
-		args := []ast.ExprNode{}
+		spec := ast.WindowSpec{}
+		if $1 != nil {
+			spec.PartitionBy = $1.(*ast.PartitionByClause)
+		}
 		if $2 != nil {
-			args = append(args, $2.(ast.ExprNode))
+			spec.OrderBy = $2.(*ast.OrderByClause)
 		}
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: args}
-	}
-|	"MOD" '(' BitExpr ',' BitExpr ')'
-	{
-		$$ = &ast.BinaryOperationExpr{Op: opcode.Mod, L: $3, R: $5}
+		if $3 != nil {
+			spec.Frame = $3.(*ast.FrameClause)
+		}
+		$$ = spec
 	}
 
-FunctionCallNonKeyword:
-	builtinCurTime '(' FuncDatetimePrecListOpt ')'
+OptPartitionClause:
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+		$$ = nil
 	}
-|	builtinSysDate '(' FuncDatetimePrecListOpt ')'
+|	"PARTITION" "BY" ByList
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: $3.([]ast.ExprNode)}
+		$$ = &ast.PartitionByClause{Items: $3.([]*ast.ByItem)}
 	}
-|	builtinPosition '(' BitExpr "IN" Expression ')'
+
+OptWindowOrderByClause:
 	{
-		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr($1), Args: []ast.ExprNode{$3, $5}}
+		$$ = nil
 	}
-|	builtinSubstring '(' Expression ',' Expression ')'
+|	"ORDER" "BY" ByList
 	{
-		$$ = &ast.FuncCallExpr{
-			FnName: model.NewCIStr($1),
-			Args: []ast.ExprNode{$3, $5},
-		}
+		$$ = &ast.OrderByClause{Items: $3.([]*ast.ByItem)}
 	}
-|	builtinSubstring '(' Expression "FROM" Expression ')'
+
+OptWindowFrameClause:
 	{
-		$$ = &ast.FuncCallExpr{
-			FnName: model.NewCIStr($1),
-			Args: []ast.ExprNode{$3, $5},
-		}
+		$$ = nil
 	}
-|	builtinSubstring '(' Expression ',' Expression ',' Expression ')'
+|	WindowFrameUnits WindowFrameExtent
 	{
-		$$ = &ast.FuncCallExpr{
-			FnName: model.NewCIStr($1),
-			Args: []ast.ExprNode{$3, $5, $7},
+		$$ = &ast.FrameClause{
+			Type: $1.(ast.FrameType),
+			Extent: $2.(ast.FrameExtent),
 		}
 	}
-|	builtinSubstring '(' Expression "FROM" Expression "FOR" Expression ')'
+
+WindowFrameUnits:
+	"ROWS"
 	{
-		$$ = &ast.FuncCallExpr{
-			FnName: model.NewCIStr($1),
-			Args: []ast.ExprNode{$3, $5, $7},
-		}
+		$$ = ast.Rows
 	}
-|	builtinTrim '(' Expression ')'
+|	"RANGE"
 	{
-		$$ = &ast.FuncCallExpr{
-			FnName: model.NewCIStr($1),
-			Args: []ast.ExprNode{$3},
-		}
+		$$ = ast.Ranges
 	}
-|	builtinTrim '(' Expression "FROM" Expression ')'
+
+WindowFrameExtent:
+	WindowFrameStart
 	{
-		$$ = &ast.FuncCallExpr{
-			FnName: model.NewCIStr($1),
-			Args: []ast.ExprNode{$5, $3},
+		$$ = ast.FrameExtent {
+			Start: $1.(ast.FrameBound),
+			End: ast.FrameBound{Type: ast.CurrentRow},
 		}
 	}
+|	WindowFrameBetween
 
-FunctionNameDateArith:
-	builtinDateAdd
-|	builtinDateSub
-
-
-FunctionNameDateArithMultiForms:
-	builtinAddDate
-|	builtinSubDate
-
-SumExpr:
-	"AVG" '(' Expression ')'
-	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
-	}
-|	builtinCount '(' Expression ')'
+WindowFrameStart:
+	"UNBOUNDED" "PRECEDING"
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = ast.FrameBound{Type: ast.Preceding, UnBounded: true}
 	}
-|	builtinCount '(' '*' ')'
+|	NumLiteral "PRECEDING"
 	{
-		args := []ast.ExprNode{ast.NewValueExpr(1)}
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
+		$$ = ast.FrameBound{Type: ast.Preceding, Expr: ast.NewValueExpr($1)}
 	}
-|	builtinMax '(' Expression ')'
+|	"CURRENT" "ROW"
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = ast.FrameBound{Type: ast.CurrentRow}
 	}
-|	builtinMin '(' Expression ')'
+
+WindowFrameBetween:
+	"BETWEEN" WindowFrameBound "AND" WindowFrameBound
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = ast.FrameExtent{Start: $2.(ast.FrameBound), End: $4.(ast.FrameBound)}
 	}
-|	builtinSum '(' Expression ')'
+
+WindowFrameBound:
+	WindowFrameStart
+|	"UNBOUNDED" "FOLLOWING"
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = ast.FrameBound{Type: ast.Following, UnBounded: true}
 	}
-
-OptGConcatSeparator:
-        {
-            	$$ = ast.NewValueExpr(",")
-        }
-| "SEPARATOR" stringLit
+|	NumLiteral "FOLLOWING"
 	{
-		$$ = ast.NewValueExpr($2)
+		$$ = ast.FrameBound{Type: ast.Following, Expr: ast.NewValueExpr($1)}
 	}
 
-
 FunctionCallGeneric:
 	identifier '(' ExpressionListOpt ')'
 	{
@@ -3524,6 +4318,15 @@ QuickOptional:
 		$$ = true
 	}
 
+IgnoreOptional:
+	{
+		$$ = false
+	}
+|	"IGNORE"
+	{
+		$$ = true
+	}
+
 RollbackStmt:
 	"ROLLBACK"
 	{
@@ -3632,6 +4435,155 @@ SelectStmt:
 		$$ = st
 	}
 
+// See https://dev.mysql.com/doc/refman/5.7/en/union.html
+SetOprStmt:
+	SetOprClauseList SetOpr SelectStmtBasic OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		// The last field of the last select stmt ends right before ORDER BY, LIMIT or the end of the statement.
+		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
+		if lastField.Expr != nil && lastField.AsName.O == "" {
+			src := parser.src
+			var lastEnd int
+			if $4 != nil {
+				lastEnd = yyS[yypt-1].offset-1
+			} else if $5 != nil {
+				lastEnd = yyS[yypt-0].offset-1
+			} else {
+				lastEnd = len(src)
+				if src[lastEnd-1] == ';' {
+					lastEnd--
+				}
+			}
+			lastField.SetText(src[lastField.Offset:lastEnd])
+		}
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+			setOpr.OrderBy.ForUnion = true
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr SelectStmtFromDualTable OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+			setOpr.OrderBy.ForUnion = true
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr SelectStmtFromTable OrderByOptional SelectStmtLimit
+	{
+		st := $3.(*ast.SelectStmt)
+		setOpr := $1.(*ast.SetOprStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-3])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $4 != nil {
+			setOpr.OrderBy = $4.(*ast.OrderByClause)
+			setOpr.OrderBy.ForUnion = true
+		}
+		if $5 != nil {
+			setOpr.Limit = $5.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+|	SetOprClauseList SetOpr '(' SelectStmt ')' OrderByOptional SelectStmtLimit
+	{
+		setOpr := $1.(*ast.SetOprStmt)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-5])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		st := $4.(*ast.SelectStmt)
+		st.IsInBraces = true
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		endOffset = parser.endOffset(&yyS[yypt-2])
+		parser.setLastSelectFieldText(st, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		if $6 != nil {
+			setOpr.OrderBy = $6.(*ast.OrderByClause)
+			setOpr.OrderBy.ForUnion = true
+		}
+		if $7 != nil {
+			setOpr.Limit = $7.(*ast.Limit)
+		}
+		$$ = setOpr
+	}
+
+SetOprClauseList:
+	SetOprSelect
+	{
+		selectList := &ast.SetOprSelectList{Selects: []*ast.SelectStmt{$1.(*ast.SelectStmt)}}
+		$$ = &ast.SetOprStmt{
+			SelectList: selectList,
+		}
+	}
+|	SetOprClauseList SetOpr SetOprSelect
+	{
+		setOpr := $1.(*ast.SetOprStmt)
+		st := $3.(*ast.SelectStmt)
+		st.AfterSetOperator = $2.(*ast.SetOprType)
+		lastSelect := setOpr.SelectList.Selects[len(setOpr.SelectList.Selects)-1]
+		endOffset := parser.endOffset(&yyS[yypt-1])
+		parser.setLastSelectFieldText(lastSelect, endOffset)
+		setOpr.SelectList.Selects = append(setOpr.SelectList.Selects, st)
+		$$ = setOpr
+	}
+
+SetOprSelect:
+	SelectStmt
+	{
+		$$ = $1
+	}
+|	'(' SelectStmt ')'
+	{
+		st := $2.(*ast.SelectStmt)
+		st.IsInBraces = true
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(st, endOffset)
+		$$ = $2
+	}
+
+SetOpr:
+	"UNION" DefaultTrueDistinctOpt
+	{
+		tp := ast.Union
+		if !$2.(bool) {
+			tp = ast.UnionAll
+		}
+		$$ = &tp
+	}
+|	"EXCEPT"
+	{
+		tp := ast.Except
+		$$ = &tp
+	}
+|	"INTERSECT"
+	{
+		tp := ast.Intersect
+		$$ = &tp
+	}
+
 FromDual:
 	"FROM" "DUAL"
 
@@ -3695,11 +4647,35 @@ TableFactor:
 		parser.setLastSelectFieldText(st, endOffset)
 		$$ = &ast.TableSource{Source: $2.(*ast.SelectStmt), AsName: $4.(model.CIStr)}
 	}
+|	'(' SetOprStmt ')' TableAsName
+	{
+		$$ = &ast.TableSource{Source: $2.(*ast.SetOprStmt), AsName: $4.(model.CIStr)}
+	}
 |	'(' TableRefs ')'
 	{
 		$$ = $2
 	}
 
+SubSelect:
+	'(' SelectStmt ')'
+	{
+		s := $2.(*ast.SelectStmt)
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(s, endOffset)
+		src := parser.src
+		// See the implementation of yyParse function
+		s.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: s}
+	}
+|	'(' SetOprStmt ')'
+	{
+		s := $2.(*ast.SetOprStmt)
+		src := parser.src
+		// See the implementation of yyParse function
+		s.SetText(src[yyS[yypt-1].offset:yyS[yypt].offset])
+		$$ = &ast.SubqueryExpr{Query: s}
+	}
+
 TableAsNameOpt:
 	{
 		$$ = model.CIStr{}
@@ -3853,6 +4829,10 @@ LimitOption:
 	{
 		$$ = ast.NewValueExpr($1)
 	}
+|	paramMarker
+	{
+		$$ = ast.NewParamMarkerExpr(yyS[yypt].offset)
+	}
 
 SelectStmtLimit:
 	{
@@ -3996,6 +4976,10 @@ TableOptimizerHintOpt:
 	{
 		$$ = &ast.TableOptimizerHint{HintName: model.NewCIStr($1), QBName: $3.(model.CIStr), Tables: $4.([]ast.HintTable)}
 	}
+|	"LEADING" '(' QueryBlockOpt HintTableList ')'
+	{
+		$$ = &ast.TableOptimizerHint{HintName: model.NewCIStr($1), QBName: $3.(model.CIStr), Tables: $4.([]ast.HintTable)}
+	}
 |	hintUseIndexMerge '(' QueryBlockOpt HintTable IndexNameList ')'
 	{
 		$$ = &ast.TableOptimizerHint{
@@ -4400,6 +5384,39 @@ AdminStmt:
 		$$ = stmt
 	}
 
+/****************************Binding Statement*******************************/
+CreateBindingStmt:
+	"CREATE" GlobalScope "BINDING" "FOR" SelectStmt "USING" SelectStmt
+	{
+		startOffset := parser.startOffset(&yyS[yypt-2])
+		endOffset := parser.endOffset(&yyS[yypt-1])
+		originSel := $5.(*ast.SelectStmt)
+		originSel.SetText(parser.src[startOffset:endOffset])
+
+		startOffset = parser.startOffset(&yyS[yypt])
+		hintedSel := $7.(*ast.SelectStmt)
+		hintedSel.SetText(parser.stmtTextFrom(startOffset))
+
+		$$ = &ast.CreateBindingStmt{
+			GlobalScope: $2.(bool),
+			OriginSel:   originSel,
+			HintedSel:   hintedSel,
+		}
+	}
+
+DropBindingStmt:
+	"DROP" GlobalScope "BINDING" "FOR" SelectStmt
+	{
+		startOffset := parser.startOffset(&yyS[yypt])
+		originSel := $5.(*ast.SelectStmt)
+		originSel.SetText(parser.stmtTextFrom(startOffset))
+
+		$$ = &ast.DropBindingStmt{
+			GlobalScope: $2.(bool),
+			OriginSel:   originSel,
+		}
+	}
+
 /****************************Show Statement*******************************/
 ShowStmt:
 	"SHOW" ShowTargetFilterable ShowLikeOrWhereOpt
@@ -4469,6 +5486,13 @@ ShowTargetFilterable:
 			GlobalScope: $1.(bool),
 		}
 	}
+|	GlobalScope "BINDINGS"
+	{
+		$$ = &ast.ShowStmt{
+			Tp: ast.ShowBindings,
+			GlobalScope: $1.(bool),
+		}
+	}
 
 ShowLikeOrWhereOpt:
 	{
@@ -4534,26 +5558,36 @@ Statement:
 |	AnalyzeTableStmt
 |	BeginTransactionStmt
 |	CommitStmt
+|	CreateBindingStmt
+|	DeallocateStmt
 |	DeleteFromStmt
+|	ExecuteStmt
 |	ExplainStmt
 |	CreateDatabaseStmt
 |	CreateIndexStmt
 |	CreateTableStmt
+|	DropBindingStmt
 |	DropDatabaseStmt
 |	DropIndexStmt
 |	DropTableStmt
 |	InsertIntoStmt
+|	LoadDataStmt
+|	PreparedStmt
 |	RollbackStmt
 |	ReplaceIntoStmt
 |	SelectStmt
+|	SetOprStmt
 |	SetStmt
 |	ShowStmt
 |	TruncateTableStmt
+|	UpdateStmt
 |	UseStmt
 
 ExplainableStmt:
 	SelectStmt
+|	SetOprStmt
 |	DeleteFromStmt
+|	UpdateStmt
 |	InsertIntoStmt
 |	ReplaceIntoStmt
 
@@ -5281,12 +6315,133 @@ StringName:
 		$$ = $1
 	}
 
+/***********************************************************************************
+ * Update Statement
+ * See https://dev.mysql.com/doc/refman/5.7/en/update.html
+ ***********************************************************************************/
+UpdateStmt:
+	"UPDATE" TableOptimizerHints PriorityOpt IgnoreOptional TableRef "SET" AssignmentList WhereClauseOptional OrderByOptional LimitClause
+	{
+		var refs *ast.Join
+		if x, ok := $5.(*ast.Join); ok {
+			refs = x
+		} else {
+			refs = &ast.Join{Left: $5.(ast.ResultSetNode)}
+		}
+		st := &ast.UpdateStmt{
+			Priority:  $3.(mysql.PriorityEnum),
+			TableRefs: &ast.TableRefsClause{TableRefs: refs},
+			List:	   $7.([]*ast.Assignment),
+			IgnoreErr: $4.(bool),
+		}
+		if $2 != nil {
+			st.TableHints = $2.([]*ast.TableOptimizerHint)
+		}
+		if $8 != nil {
+			st.Where = $8.(ast.ExprNode)
+		}
+		if $9 != nil {
+			st.Order = $9.(*ast.OrderByClause)
+		}
+		if $10 != nil {
+			st.Limit = $10.(*ast.Limit)
+		}
+		$$ = st
+	}
+|	"UPDATE" TableOptimizerHints PriorityOpt IgnoreOptional TableRefs "SET" AssignmentList WhereClauseOptional
+	{
+		st := &ast.UpdateStmt{
+			Priority:  $3.(mysql.PriorityEnum),
+			TableRefs: &ast.TableRefsClause{TableRefs: $5.(*ast.Join)},
+			List:	   $7.([]*ast.Assignment),
+			IgnoreErr: $4.(bool),
+		}
+		if $2 != nil {
+			st.TableHints = $2.([]*ast.TableOptimizerHint)
+		}
+		if $8 != nil {
+			st.Where = $8.(ast.ExprNode)
+		}
+		st.MultipleTable = true
+		$$ = st
+	}
+
 UseStmt:
 	"USE" DBName
 	{
 		$$ = &ast.UseStmt{DBName: $2.(string)}
 	}
 
+/*******************************************************************************************/
+/* https://dev.mysql.com/doc/refman/5.7/en/prepare.html                                    */
+/*******************************************************************************************/
+PreparedStmt:
+	"PREPARE" Identifier "FROM" PrepareSQL
+	{
+		var sqlText string
+		var sqlVar *ast.VariableExpr
+		switch x := $4.(type) {
+		case string:
+			sqlText = x
+		case *ast.VariableExpr:
+			sqlVar = x
+		}
+		$$ = &ast.PrepareStmt{
+			Name:    $2,
+			SQLText: sqlText,
+			SQLVar:  sqlVar,
+		}
+	}
+
+PrepareSQL:
+	stringLit
+	{
+		$$ = $1
+	}
+|	UserVariable
+	{
+		$$ = $1
+	}
+
+/*
+ * See https://dev.mysql.com/doc/refman/5.7/en/execute.html
+ */
+ExecuteStmt:
+	"EXECUTE" Identifier
+	{
+		$$ = &ast.ExecuteStmt{Name: $2}
+	}
+|	"EXECUTE" Identifier "USING" UserVariableList
+	{
+		$$ = &ast.ExecuteStmt{
+			Name:      $2,
+			UsingVars: $4.([]ast.ExprNode),
+		}
+	}
+
+UserVariableList:
+	UserVariable
+	{
+		$$ = []ast.ExprNode{$1}
+	}
+|	UserVariableList ',' UserVariable
+	{
+		$$ = append($1.([]ast.ExprNode), $3)
+	}
+
+/*
+ * See https://dev.mysql.com/doc/refman/5.7/en/deallocate-prepare.html
+ */
+DeallocateStmt:
+	DeallocateSym "PREPARE" Identifier
+	{
+		$$ = &ast.DeallocateStmt{Name: $3}
+	}
+
+DeallocateSym:
+	"DEALLOCATE"
+|	"DROP"
+
 WhereClause:
 	"WHERE" Expression
 	{
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
@@ -49,9 +50,9 @@ func (s *testParserSuite) TestSimple(c *C) {
 		"current_timestamp", "current_user", "database", "databases", "day_hour", "day_microsecond",
 		"day_minute", "day_second", "decimal", "default", "delete", "desc", "describe",
 		"distinct", "distinctRow", "div", "double", "drop", "dual", "else", "enclosed", "escaped",
-		"exists", "explain", "false", "float", "for", "force", "foreign", "from",
+		"except", "exists", "explain", "false", "float", "for", "force", "foreign", "from",
 		"fulltext", "grant", "group", "having", "hour_microsecond", "hour_minute",
-		"hour_second", "if", "ignore", "in", "index", "infile", "inner", "insert", "int", "into", "integer",
+		"hour_second", "if", "ignore", "in", "index", "infile", "inner", "insert", "int", "into", "integer", "intersect",
 		"interval", "is", "join", "key", "keys", "kill", "leading", "left", "like", "limit", "lines", "load",
 		"localtime", "localtimestamp", "lock", "longblob", "longtext", "mediumblob", "maxvalue", "mediumint", "mediumtext",
 		"minute_microsecond", "minute_second", "mod", "not", "no_write_to_binlog", "null", "numeric",
@@ -259,6 +260,44 @@ func (s *testParserSuite) TestSpecialComments(c *C) {
 	c.Assert(sel.TableHints, HasLen, 0)
 }
 
+func (s *testParserSuite) TestOptimizerHints(c *C) {
+	parser := parser.New()
+	st, err := parser.ParseOneStmt("select /*+ HASH_JOIN(t1), merge_join(t2, t3), INL_JOIN(t4), LEADING(t3, t1, t2) */ * from t1, t2, t3, t4", "", "")
+	c.Assert(err, IsNil)
+	hints := st.(*ast.SelectStmt).TableHints
+	c.Assert(hints, HasLen, 4)
+	c.Assert(hints[0].HintName.L, Equals, "hash_join")
+	c.Assert(hints[0].Tables, HasLen, 1)
+	c.Assert(hints[0].Tables[0].TableName.L, Equals, "t1")
+	c.Assert(hints[1].HintName.L, Equals, "merge_join")
+	c.Assert(hints[1].Tables, HasLen, 2)
+	c.Assert(hints[1].Tables[1].TableName.L, Equals, "t3")
+	c.Assert(hints[2].HintName.L, Equals, "inl_join")
+	c.Assert(hints[3].HintName.L, Equals, "leading")
+	c.Assert(hints[3].Tables, HasLen, 3)
+	c.Assert(hints[3].Tables[0].TableName.L, Equals, "t3")
+	c.Assert(hints[3].Tables[1].TableName.L, Equals, "t1")
+	c.Assert(hints[3].Tables[2].TableName.L, Equals, "t2")
+
+	st, err = parser.ParseOneStmt("select /*+ USE_INDEX(t, idx1, idx2), IGNORE_INDEX(t, idx3), HASH_AGG(), STREAM_AGG() */ count(*) from t", "", "")
+	c.Assert(err, IsNil)
+	hints = st.(*ast.SelectStmt).TableHints
+	c.Assert(hints, HasLen, 4)
+	c.Assert(hints[0].HintName.L, Equals, "use_index")
+	c.Assert(hints[0].Tables[0].TableName.L, Equals, "t")
+	c.Assert(hints[0].Indexes, DeepEquals, []model.CIStr{model.NewCIStr("idx1"), model.NewCIStr("idx2")})
+	c.Assert(hints[1].HintName.L, Equals, "ignore_index")
+	c.Assert(hints[1].Indexes, DeepEquals, []model.CIStr{model.NewCIStr("idx3")})
+	c.Assert(hints[2].HintName.L, Equals, "hash_agg")
+	c.Assert(hints[3].HintName.L, Equals, "stream_agg")
+
+	// "merge_join" and "leading" are still valid identifiers.
+	_, err = parser.ParseOneStmt("select merge_join from t", "", "")
+	c.Assert(err, IsNil)
+	_, err = parser.ParseOneStmt("select trim(leading 'x' from 'xxa')", "", "")
+	c.Assert(err, IsNil)
+}
+
 type testCase struct {
 	src     string
 	ok      bool
@@ -339,6 +378,15 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"INSERT INTO tt VALUES (01000001783);", true, "INSERT INTO `tt` VALUES (1000001783)"},
 		{"INSERT INTO tt VALUES (default);", true, "INSERT INTO `tt` VALUES (DEFAULT)"},
 
+		// insert ignore and on duplicate key update
+		{"INSERT IGNORE INTO foo VALUES (1, 2)", true, "INSERT IGNORE INTO `foo` VALUES (1,2)"},
+		{"INSERT LOW_PRIORITY IGNORE foo (a) SELECT b FROM bar", true, "INSERT LOW_PRIORITY IGNORE INTO `foo` (`a`) SELECT `b` FROM `bar`"},
+		{"INSERT INTO foo (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE a = VALUES(a), b = b + 1", true, "INSERT INTO `foo` (`a`,`b`) VALUES (1,2) ON DUPLICATE KEY UPDATE `a`=VALUES(`a`), `b`=`b`+1"},
+		{"INSERT IGNORE INTO foo SET a = 1 ON DUPLICATE KEY UPDATE a = a + 1", true, "INSERT IGNORE INTO `foo` SET `a`=1 ON DUPLICATE KEY UPDATE `a`=`a`+1"},
+		{"INSERT INTO foo SELECT * FROM bar ON DUPLICATE KEY UPDATE b = VALUES(b)", true, "INSERT INTO `foo` SELECT * FROM `bar` ON DUPLICATE KEY UPDATE `b`=VALUES(`b`)"},
+		{"INSERT INTO foo VALUES (1) ON DUPLICATE KEY UPDATE", false, ""},
+		{"REPLACE INTO foo VALUES (1) ON DUPLICATE KEY UPDATE a = 1", false, ""},
+
 		{"REPLACE INTO foo VALUES (1 || 2)", true, "REPLACE INTO `foo` VALUES (1 OR 2)"},
 		{"REPLACE INTO foo VALUES (1 | 2)", true, "REPLACE INTO `foo` VALUES (1|2)"},
 		{"REPLACE INTO foo VALUES (false || true)", true, "REPLACE INTO `foo` VALUES (FALSE OR TRUE)"},
@@ -349,6 +397,22 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"REPLACE INTO foo (a,b,) VALUES (42,314,)", false, ""},
 		{"REPLACE INTO foo () VALUES ()", true, "REPLACE INTO `foo` () VALUES ()"},
 		{"REPLACE INTO foo VALUE ()", true, "REPLACE INTO `foo` VALUES ()"},
+
+		// load data
+		{"load data infile '/tmp/t.csv' into table t", true, "LOAD DATA INFILE '/tmp/t.csv' INTO TABLE `t`"},
+		{"load data local infile '/tmp/t.csv' into table t", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' INTO TABLE `t`"},
+		{"load data local infile '/tmp/t.csv' ignore into table t", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' IGNORE INTO TABLE `t`"},
+		{"load data local infile '/tmp/t.csv' replace into table test.t", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' REPLACE INTO TABLE `test`.`t`"},
+		{"load data local infile '/tmp/t.csv' into table t fields terminated by ',' optionally enclosed by '\"' escaped by '\\\\'", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' INTO TABLE `t` FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '\\\\'"},
+		{"load data local infile '/tmp/t.csv' into table t columns enclosed by '' terminated by x'2c'", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' INTO TABLE `t` FIELDS TERMINATED BY ',' ENCLOSED BY ''"},
+		{"load data local infile '/tmp/t.csv' into table t lines starting by 'xxx' terminated by '\\r\\n'", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' INTO TABLE `t` LINES STARTING BY 'xxx' TERMINATED BY '\\r\\n'"},
+		{"load data local infile '/tmp/t.csv' into table t fields terminated by ',' lines terminated by '\\n' ignore 1 lines (a, b)", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' INTO TABLE `t` FIELDS TERMINATED BY ',' LINES TERMINATED BY '\\n' IGNORE 1 LINES (`a`,`b`)"},
+		{"load data local infile '/tmp/t.csv' into table t ()", true, "LOAD DATA LOCAL INFILE '/tmp/t.csv' INTO TABLE `t`"},
+		{"load data local infile '/tmp/t.csv' into table t fields enclosed by 'xx'", false, ""},
+		{"load data local infile '/tmp/t.csv' into table t fields escaped by 'xx'", false, ""},
+		{"load data local infile '/tmp/t.csv' into table t ignore lines", false, ""},
+		{"load data infile '/tmp/t.csv' into t", false, ""},
+		{"load data local infile t.csv into table t", false, ""},
 		{"BEGIN", true, "START TRANSACTION"},
 		// 45
 		{"COMMIT", true, "COMMIT"},
@@ -392,6 +456,17 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id limit 10;", false, ""},
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id order by t1.id;", false, ""},
 
+		// update statement
+		{"UPDATE t SET id = id + 1 ORDER BY id DESC;", true, "UPDATE `t` SET `id`=`id`+1 ORDER BY `id` DESC"},
+		{"UPDATE items,month SET items.price=month.price WHERE items.id=month.id;", true, "UPDATE (`items`) JOIN `month` SET `items`.`price`=`month`.`price` WHERE `items`.`id`=`month`.`id`"},
+		{"UPDATE user T0 LEFT OUTER JOIN user_profile T1 ON T1.id = T0.profile_id SET T0.profile_id = 1 WHERE T0.profile_id IN (1);", true, "UPDATE `user` AS `T0` LEFT JOIN `user_profile` AS `T1` ON `T1`.`id`=`T0`.`profile_id` SET `T0`.`profile_id`=1 WHERE `T0`.`profile_id` IN (1)"},
+		{"UPDATE t1, t2 SET t1.a = 1 ORDER BY t1.a;", false, ""},
+		{"UPDATE t1, t2 SET t1.a = 1 LIMIT 1;", false, ""},
+		{"UPDATE LOW_PRIORITY IGNORE t SET a = 1 WHERE b = 2 LIMIT 10", true, "UPDATE LOW_PRIORITY IGNORE `t` SET `a`=1 WHERE `b`=2 LIMIT 10"},
+		{"UPDATE t SET a = DEFAULT", true, "UPDATE `t` SET `a`=DEFAULT"},
+		{"UPDATE /*+ USE_INDEX(t, idx) */ t SET a = 1", true, "UPDATE /*+ USE_INDEX(`t` `idx`)*/ `t` SET `a`=1"},
+		{"UPDATE t SET", false, ""},
+
 		// for admin
 		{"admin show ddl;", true, "ADMIN SHOW DDL"},
 		{"admin show ddl jobs;", true, "ADMIN SHOW DDL JOBS"},
@@ -510,6 +585,59 @@ func (s *testParserSuite) TestExpression(c *C) {
 		{"select {ts123 '1989-09-10 11:11:11'}", true, "SELECT '1989-09-10 11:11:11'"},
 		{"select {ts123 123}", true, "SELECT 123"},
 		{"select {ts123 1 xor 1}", true, "SELECT 1 XOR 1"},
+
+		// for subquery
+		{"select (select 1)", true, "SELECT (SELECT 1)"},
+		{"select * from t where a = (select max(b) from t1)", true, "SELECT * FROM `t` WHERE `a`=(SELECT MAX(`b`) FROM `t1`)"},
+		{"select * from t where a in (select b from t1)", true, "SELECT * FROM `t` WHERE `a` IN (SELECT `b` FROM `t1`)"},
+		{"select * from t where a not in (select b from t1 where t1.c = t.c)", true, "SELECT * FROM `t` WHERE `a` NOT IN (SELECT `b` FROM `t1` WHERE `t1`.`c`=`t`.`c`)"},
+		{"select * from t where (a, b) in (select c, d from t1)", true, "SELECT * FROM `t` WHERE (`a`,`b`) IN (SELECT `c`,`d` FROM `t1`)"},
+		{"select * from t where exists (select 1 from t1)", true, "SELECT * FROM `t` WHERE EXISTS (SELECT 1 FROM `t1`)"},
+		{"select * from t where not exists (select 1 from t1)", true, "SELECT * FROM `t` WHERE NOT EXISTS (SELECT 1 FROM `t1`)"},
+		{"select * from t where a > any (select b from t1)", true, "SELECT * FROM `t` WHERE `a`>ANY (SELECT `b` FROM `t1`)"},
+		{"select * from t where a >= some (select b from t1)", true, "SELECT * FROM `t` WHERE `a`>=ANY (SELECT `b` FROM `t1`)"},
+		{"select * from t where a != all (select b from t1)", true, "SELECT * FROM `t` WHERE `a`!=ALL (SELECT `b` FROM `t1`)"},
+		{"select * from t where a = all select b from t1", false, ""},
+		{"select exists select 1", false, ""},
+
+		// for like and regexp
+		{"select * from t where a like 'abc%'", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc%'"},
+		{"select * from t where a not like 'abc%'", true, "SELECT * FROM `t` WHERE `a` NOT LIKE 'abc%'"},
+		{"select * from t where a like 'abc|%' escape '|'", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc|%' ESCAPE '|'"},
+		{"select * from t where a like 'abc' escape ''", true, "SELECT * FROM `t` WHERE `a` LIKE 'abc'"},
+		{"select * from t where a like 'abc' escape '||'", false, ""},
+		{"select 'abc' like concat('a', '%')", true, "SELECT 'abc' LIKE CONCAT('a','%')"},
+		{"select * from t where a regexp '^a.*'", true, "SELECT * FROM `t` WHERE `a` REGEXP '^a.*'"},
+		{"select * from t where a not regexp '^a.*'", true, "SELECT * FROM `t` WHERE `a` NOT REGEXP '^a.*'"},
+		{"select * from t where a rlike '^a.*'", true, "SELECT * FROM `t` WHERE `a` REGEXP '^a.*'"},
+		{"select * from t where a not rlike '^a.*'", true, "SELECT * FROM `t` WHERE `a` NOT REGEXP '^a.*'"},
+
+		// for case
+		{"select case a when 1 then 'one' when 2 then 'two' else 'other' end from t", true, "SELECT CASE `a` WHEN 1 THEN 'one' WHEN 2 THEN 'two' ELSE 'other' END FROM `t`"},
+		{"select case when a > 1 then 'big' end from t", true, "SELECT CASE WHEN `a`>1 THEN 'big' END FROM `t`"},
+		{"select * from t where case when a is null then b else a end = 1", true, "SELECT * FROM `t` WHERE CASE WHEN `a` IS NULL THEN `b` ELSE `a` END=1"},
+		{"select case a else 1 end from t", false, ""},
+		{"select case when a then 1 from t", false, ""},
+
+		// for bit operators
+		{"select a & b, a | b, a ^ b, ~a, a << 1, a >> 1 from t", true, "SELECT `a`&`b`,`a`|`b`,`a`^`b`,~`a`,`a`<<1,`a`>>1 FROM `t`"},
+		{"select a div b, a mod b, a % b from t", true, "SELECT `a` DIV `b`,`a`%`b`,`a`%`b` FROM `t`"},
+
+		// for set operations
+		{"select c1 from t1 union select c2 from t2", true, "SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2`"},
+		{"select c1 from t1 union all select c2 from t2", true, "SELECT `c1` FROM `t1` UNION ALL SELECT `c2` FROM `t2`"},
+		{"select c1 from t1 union distinct select c2 from t2", true, "SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2`"},
+		{"select c1 from t1 union select c2 from t2 union all select c3 from t3 order by c1 limit 1", true, "SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2` UNION ALL SELECT `c3` FROM `t3` ORDER BY `c1` LIMIT 1"},
+		{"(select c1 from t1 order by c1 limit 1) union (select c2 from t2 limit 2) order by c1", true, "(SELECT `c1` FROM `t1` ORDER BY `c1` LIMIT 1) UNION (SELECT `c2` FROM `t2` LIMIT 2) ORDER BY `c1`"},
+		{"select 1 union select 2 from dual", true, "SELECT 1 UNION SELECT 2"},
+		{"select c1 from t1 except select c2 from t2", true, "SELECT `c1` FROM `t1` EXCEPT SELECT `c2` FROM `t2`"},
+		{"select c1 from t1 intersect select c2 from t2 union select c3 from t3", true, "SELECT `c1` FROM `t1` INTERSECT SELECT `c2` FROM `t2` UNION SELECT `c3` FROM `t3`"},
+		{"select * from (select c1 from t1 union select c2 from t2) as a", true, "SELECT * FROM (SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2`) AS `a`"},
+		{"select * from t where a in (select c1 from t1 union select c2 from t2)", true, "SELECT * FROM `t` WHERE `a` IN (SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2`)"},
+		{"insert into t select c1 from t1 union select c2 from t2", true, "INSERT INTO `t` SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2`"},
+		{"explain select c1 from t1 union select c2 from t2", true, "EXPLAIN FORMAT = 'row' SELECT `c1` FROM `t1` UNION SELECT `c2` FROM `t2`"},
+		{"select c1 from t1 except all select c2 from t2", false, ""},
+		{"select c1 from t1 union", false, ""},
 	}
 	s.RunTest(c, table)
 }
@@ -677,6 +805,21 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{"select curtime('1')", false, ""},
 		{"select curtime(null)", false, ""},
 
+		// for date arithmetic
+		{"select date_add('2011-11-11 10:10:10', interval 10 day)", true, ""},
+		{"select date_sub('2011-11-11 10:10:10', interval '1:1' hour_minute)", true, ""},
+		{"select date_add('2011-11-11', interval 1 year_month)", true, ""},
+		{"select adddate('2011-11-11', 10)", true, ""},
+		{"select adddate('2011-11-11', interval 10 second_microsecond)", true, ""},
+		{"select subdate('2011-11-11', interval 1 week)", true, ""},
+		{"select '2011-11-11' + interval 1 quarter, '2011-11-11' - interval 1 month", true, ""},
+		{"select date_add('2011-11-11', 10)", false, ""},
+		{"select date_add('2011-11-11', interval 10 days)", false, ""},
+		{"select extract(day from '2011-11-11')", true, ""},
+		{"select extract(day_microsecond from '2011-11-11 10:10:10.123456')", true, ""},
+		{"select extract(day '2011-11-11')", false, ""},
+		{"select date_format('2011-11-11', '%Y %m'), unix_timestamp(), from_unixtime(1)", true, ""},
+
 		// select utc_timestamp
 		{"select utc_timestamp", true, "SELECT UTC_TIMESTAMP()"},
 		{"select utc_timestamp()", true, "SELECT UTC_TIMESTAMP()"},
@@ -851,6 +994,26 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{`SELECT RELEASE_ALL_LOCKS(1);`, true, "SELECT RELEASE_ALL_LOCKS(1)"},
 		{`SELECT UUID(1);`, true, "SELECT UUID(1)"},
 		{`SELECT UUID_SHORT(1)`, true, "SELECT UUID_SHORT(1)"},
+
+		// for string functions
+		{`SELECT CONCAT('a', 'b', c), CONCAT_WS(',', 'a', c) FROM t;`, true, "SELECT CONCAT('a','b',`c`),CONCAT_WS(',','a',`c`) FROM `t`"},
+		{`SELECT SUBSTRING('Quadratically', 5), SUBSTR('Quadratically' FROM 5 FOR 6), MID('foobarbar', 4, 3);`, true, "SELECT SUBSTRING('Quadratically',5),SUBSTR('Quadratically',5,6),MID('foobarbar',4,3)"},
+		{`SELECT LOCATE('bar', 'foobarbar'), LOCATE('bar', 'foobarbar', 5), POSITION('bar' IN 'foobarbar');`, true, "SELECT LOCATE('bar','foobarbar'),LOCATE('bar','foobarbar',5),POSITION('bar','foobarbar')"},
+		{`SELECT REPLACE('www.mysql.com', 'w', 'Ww'), REVERSE('abc');`, true, "SELECT REPLACE('www.mysql.com','w','Ww'),REVERSE('abc')"},
+		{`SELECT TRIM('  bar   '), LTRIM('  bar'), RTRIM('bar  ');`, true, "SELECT TRIM('  bar   '),LTRIM('  bar'),RTRIM('bar  ')"},
+		{`SELECT TRIM('x' FROM 'xxxbarxxx');`, true, "SELECT TRIM('xxxbarxxx','x')"},
+		{`SELECT TRIM(BOTH FROM '  bar  '), TRIM(LEADING 'x' FROM 'xxxbarxxx'), TRIM(TRAILING 'xyz' FROM 'barxxyz');`, true, "SELECT TRIM('  bar  ',NULL,BOTH),TRIM('xxxbarxxx','x',LEADING),TRIM('barxxyz','xyz',TRAILING)"},
+		{`SELECT TRIM(BOTH);`, false, ""},
+		{`SELECT UPPER('a'), UCASE('a'), LOWER('A'), LCASE('A');`, true, "SELECT UPPER('a'),UCASE('a'),LOWER('A'),LCASE('A')"},
+		{`SELECT LPAD('hi', 4, '??'), RPAD('hi', 5, '?'), LEFT('foobar', 3), RIGHT('foobar', 3);`, true, "SELECT LPAD('hi',4,'??'),RPAD('hi',5,'?'),LEFT('foobar',3),RIGHT('foobar',3)"},
+
+		// for json functions
+		{`SELECT JSON_EXTRACT(a, '$.a', '$[1]') FROM t;`, true, "SELECT JSON_EXTRACT(`a`, _UTF8MB4'$.a', _UTF8MB4'$[1]') FROM `t`"},
+		{`SELECT JSON_OBJECT('a', 1, 'b', JSON_ARRAY(1, 2));`, true, "SELECT JSON_OBJECT(_UTF8MB4'a', 1, _UTF8MB4'b', JSON_ARRAY(1, 2))"},
+		{`SELECT a->'$.a', t.a->>'$.b' FROM t;`, true, "SELECT JSON_EXTRACT(`a`, _UTF8MB4'$.a'),JSON_UNQUOTE(JSON_EXTRACT(`t`.`a`, _UTF8MB4'$.b')) FROM `t`"},
+		{`SELECT * FROM t WHERE a->'$.a' = 1;`, true, "SELECT * FROM `t` WHERE JSON_EXTRACT(`a`, _UTF8MB4'$.a')=1"},
+		{`SELECT a->1 FROM t;`, false, ""},
+		{`SELECT 'a'->'$.a';`, false, ""},
 		// for aggregate functions
 		{`select avg(), avg(c1,c2) from t;`, false, "SELECT AVG(),AVG(`c1`, `c2`) FROM `t`"},
 		{`select avg(c2) from t;`, true, "SELECT AVG(`c2`) FROM `t`"},
@@ -867,6 +1030,36 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestWindowFunctions(c *C) {
+	table := []testCase{
+		{"select row_number() over () from t", true, ""},
+		{"select rank() over (partition by a order by b) from t", true, ""},
+		{"select dense_rank() over (partition by a, b order by c desc) from t", true, ""},
+		{"select lead(a) over (order by b), lag(a, 2) over (order by b), lag(a, 1, 0) over (order by b) from t", true, ""},
+		{"select first_value(a) over (order by b rows between 1 preceding and 1 following) from t", true, ""},
+		{"select last_value(a) over (order by b range between unbounded preceding and unbounded following) from t", true, ""},
+		{"select sum(a) over (partition by b order by c rows unbounded preceding) from t", true, ""},
+		{"select count(*) over (order by a range current row), avg(a) over (), max(a) over (), min(a) over () from t", true, ""},
+		{"select sum(a) over (order by b rows between current row and 2 following) from t", true, ""},
+		{"select row_number() from t", false, ""},
+		{"select row_number() over from t", false, ""},
+		{"select lead(a, b) over () from t", false, ""},
+		{"select sum(a) over (rows between 1 following) from t", false, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("select sum(a) over (partition by b order by c rows between 2 preceding and current row) from t", "", "")
+	c.Assert(err, IsNil)
+	expr := stmt.(*ast.SelectStmt).Fields.Fields[0].Expr.(*ast.WindowFuncExpr)
+	c.Assert(expr.F, Equals, ast.AggFuncSum)
+	c.Assert(expr.Spec.PartitionBy.Items, HasLen, 1)
+	c.Assert(expr.Spec.OrderBy.Items, HasLen, 1)
+	c.Assert(expr.Spec.Frame.Type, Equals, ast.Rows)
+	c.Assert(expr.Spec.Frame.Extent.Start.Type, Equals, ast.Preceding)
+	c.Assert(expr.Spec.Frame.Extent.End.Type, Equals, ast.CurrentRow)
+}
+
 func (s *testParserSuite) TestIdentifier(c *C) {
 	table := []testCase{
 		// for quote identifier
@@ -1293,6 +1486,36 @@ func (s *testParserSuite) TestType(c *C) {
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestLoadData(c *C) {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt("load data local infile '/tmp/t.csv' ignore into table t fields terminated by ',' enclosed by '\"' lines starting by 'x' ignore 2 lines (a, b)", "", "")
+	c.Assert(err, IsNil)
+	ld := stmt.(*ast.LoadDataStmt)
+	c.Assert(ld.IsLocal, IsTrue)
+	c.Assert(ld.Path, Equals, "/tmp/t.csv")
+	c.Assert(ld.OnDuplicate, Equals, ast.OnDuplicateKeyHandlingIgnore)
+	c.Assert(ld.Table.Name.O, Equals, "t")
+	c.Assert(ld.FieldsInfo, DeepEquals, &ast.FieldsClause{Terminated: ",", Enclosed: '"', Escaped: '\\'})
+	c.Assert(ld.LinesInfo, DeepEquals, &ast.LinesClause{Starting: "x", Terminated: "\n"})
+	c.Assert(ld.IgnoreLines, Equals, uint64(2))
+	c.Assert(ld.Columns, HasLen, 2)
+	c.Assert(ld.Columns[1].Name.O, Equals, "b")
+
+	// Check the default values of the clauses.
+	stmt, err = p.ParseOneStmt("load data infile '/tmp/t.csv' into table t", "", "")
+	c.Assert(err, IsNil)
+	ld = stmt.(*ast.LoadDataStmt)
+	c.Assert(ld.IsLocal, IsFalse)
+	c.Assert(ld.OnDuplicate, Equals, ast.OnDuplicateKeyHandlingError)
+	c.Assert(ld.FieldsInfo, DeepEquals, &ast.FieldsClause{Terminated: "\t", Escaped: '\\'})
+	c.Assert(ld.LinesInfo, DeepEquals, &ast.LinesClause{Terminated: "\n"})
+	c.Assert(ld.IgnoreLines, Equals, uint64(0))
+	c.Assert(ld.Columns, HasLen, 0)
+
+	_, _, err = p.Parse("load data local infile '/tmp/t.csv' into table t fields enclosed by 'xx'", "", "")
+	c.Assert(terror.ErrorEqual(err, parser.ErrWrongFieldTerminators), IsTrue, Commentf("err %v", err))
+}
+
 func (s *testParserSuite) TestCommentErrMsg(c *C) {
 	table := []testErrMsgCase{
 		{"delete from t where a = 7 or 1=1/*' and b = 'p'", false, errors.New("near '/*' and b = 'p'' at line 1")},
@@ -1358,16 +1581,116 @@ func (s *testParserSuite) TestExplain(c *C) {
 		{"explain select c1 from t1", true, "EXPLAIN FORMAT = 'row' SELECT `c1` FROM `t1`"},
 		{"explain insert into t values (1), (2), (3)", true, "EXPLAIN FORMAT = 'row' INSERT INTO `t` VALUES (1),(2),(3)"},
 		{"explain replace into foo values (1 || 2)", true, "EXPLAIN FORMAT = 'row' REPLACE INTO `foo` VALUES (1 OR 2)"},
+		{"explain update t set id = id + 1 order by id desc;", true, "EXPLAIN FORMAT = 'row' UPDATE `t` SET `id`=`id`+1 ORDER BY `id` DESC"},
 		{"EXPLAIN FORMAT = 'dot' SELECT 1", true, "EXPLAIN FORMAT = 'dot' SELECT 1"},
 		{"EXPLAIN FORMAT = 'row' SELECT 1", true, "EXPLAIN FORMAT = 'row' SELECT 1"},
 		{"EXPLAIN FORMAT = 'ROW' SELECT 1", true, "EXPLAIN FORMAT = 'ROW' SELECT 1"},
 		{"EXPLAIN SELECT 1", true, "EXPLAIN FORMAT = 'row' SELECT 1"},
 		{"EXPLAIN FORMAT = JSON SELECT 1", true, "EXPLAIN FORMAT = 'json' SELECT 1"},
 		{"EXPLAIN FORMAT = 'hint' SELECT 1", true, "EXPLAIN FORMAT = 'hint' SELECT 1"},
+		{"EXPLAIN ANALYZE SELECT 1", true, "EXPLAIN ANALYZE SELECT 1"},
+		{"DESC ANALYZE select * from t", true, "EXPLAIN ANALYZE SELECT * FROM `t`"},
+		{"EXPLAIN ANALYZE insert into t values (1)", true, "EXPLAIN ANALYZE INSERT INTO `t` VALUES (1)"},
+		{"EXPLAIN ANALYZE FORMAT = 'dot' SELECT 1", false, ""},
 	}
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestPrepare(c *C) {
+	table := []testCase{
+		{"PREPARE pname FROM 'SELECT ?'", true, "PREPARE `pname` FROM 'SELECT ?'"},
+		{"PREPARE pname FROM @test", true, "PREPARE `pname` FROM @`test`"},
+		{"PREPARE `` FROM @test", true, "PREPARE `` FROM @`test`"},
+		{"PREPARE pname FROM 1", false, ""},
+		{"EXECUTE pname", true, "EXECUTE `pname`"},
+		{"EXECUTE pname USING @a, @b", true, "EXECUTE `pname` USING @`a`,@`b`"},
+		{"EXECUTE pname USING 1", false, ""},
+		{"DEALLOCATE PREPARE pname", true, "DEALLOCATE PREPARE `pname`"},
+		{"DROP PREPARE pname", true, "DEALLOCATE PREPARE `pname`"},
+		{"DEALLOCATE pname", false, ""},
+		{"select ?", true, "SELECT ?"},
+		{"select * from t where a = ? and b > ?", true, "SELECT * FROM `t` WHERE `a`=? AND `b`>?"},
+		{"select * from t limit ?, ?", true, "SELECT * FROM `t` LIMIT ?,?"},
+		{"insert into t values (?, ?)", true, "INSERT INTO `t` VALUES (?,?)"},
+		{"update t set a = ? where b = ?", true, "UPDATE `t` SET `a`=? WHERE `b`=?"},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("select a from t where a > ? and b = ?", "", "")
+	c.Assert(err, IsNil)
+	var extractor paramMarkerExtractor
+	stmt.Accept(&extractor)
+	c.Assert(extractor.offsets, DeepEquals, []int{26, 36})
+
+	stmt, err = parser.ParseOneStmt("prepare stmt from 'select ?'", "", "")
+	c.Assert(err, IsNil)
+	prepare := stmt.(*ast.PrepareStmt)
+	c.Assert(prepare.Name, Equals, "stmt")
+	c.Assert(prepare.SQLText, Equals, "select ?")
+	c.Assert(prepare.SQLVar, IsNil)
+
+	stmt, err = parser.ParseOneStmt("execute stmt using @a, @b", "", "")
+	c.Assert(err, IsNil)
+	execute := stmt.(*ast.ExecuteStmt)
+	c.Assert(execute.Name, Equals, "stmt")
+	c.Assert(execute.UsingVars, HasLen, 2)
+	c.Assert(execute.UsingVars[1].(*ast.VariableExpr).Name, Equals, "b")
+}
+
+type paramMarkerExtractor struct {
+	offsets []int
+}
+
+func (e *paramMarkerExtractor) Enter(in ast.Node) (ast.Node, bool) {
+	return in, false
+}
+
+func (e *paramMarkerExtractor) Leave(in ast.Node) (ast.Node, bool) {
+	if x, ok := in.(*driver.ParamMarkerExpr); ok {
+		e.offsets = append(e.offsets, x.Offset)
+	}
+	return in, true
+}
+
+func (s *testParserSuite) TestBinding(c *C) {
+	table := []testCase{
+		{"create global binding for select * from t using select * from t use index(a)", true, ""},
+		{"create session binding for select * from t using select /*+ hash_join(t) */ * from t", true, ""},
+		{"create binding for select * from t where a = 1 using select * from t use index(a) where a = 1", true, ""},
+		{"create global binding for select * from t", false, ""},
+		{"create global binding for update t set a = 1 using update t set a = 1", false, ""},
+		{"drop global binding for select * from t", true, ""},
+		{"drop session binding for select * from t where a = 1", true, ""},
+		{"drop binding for select * from t using select * from t", false, ""},
+		{"show global bindings", true, ""},
+		{"show session bindings where original_sql like '%t%'", true, ""},
+		{"show bindings", true, ""},
+	}
+	s.RunTest(c, table)
+
+	parser := parser.New()
+	stmt, err := parser.ParseOneStmt("create global binding for select * from t where a > 1 using select * from t use index(idx_a) where a > 1;", "", "")
+	c.Assert(err, IsNil)
+	createBind := stmt.(*ast.CreateBindingStmt)
+	c.Assert(createBind.GlobalScope, IsTrue)
+	c.Assert(createBind.OriginSel.Text(), Equals, "select * from t where a > 1")
+	c.Assert(createBind.HintedSel.Text(), Equals, "select * from t use index(idx_a) where a > 1")
+
+	stmts, _, err := parser.Parse("drop session binding for select * from t where a > 1 ; select 1", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmts, HasLen, 2)
+	dropBind := stmts[0].(*ast.DropBindingStmt)
+	c.Assert(dropBind.GlobalScope, IsFalse)
+	c.Assert(dropBind.OriginSel.Text(), Equals, "select * from t where a > 1")
+
+	stmt, err = parser.ParseOneStmt("show global bindings", "", "")
+	c.Assert(err, IsNil)
+	show := stmt.(*ast.ShowStmt)
+	c.Assert(show.Tp, Equals, ast.ShowStmtType(ast.ShowBindings))
+	c.Assert(show.GlobalScope, IsTrue)
+}
+
 func (s *testParserSuite) TestSQLModeANSIQuotes(c *C) {
 	parser := parser.New()
 	parser.SetSQLMode(mysql.ModeANSIQuotes)
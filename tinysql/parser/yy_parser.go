@@ -18,6 +18,7 @@ import (
 	"math"
 	"regexp"
 	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/pingcap/errors"
@@ -207,6 +208,17 @@ func (parser *Parser) endOffset(v *yySymType) int {
 	return offset
 }
 
+// stmtTextFrom returns the text from startOffset to the end of the statement
+// being parsed. It's called when the last token of the statement is reduced,
+// so the last scanned token is the one following the statement.
+func (parser *Parser) stmtTextFrom(startOffset int) string {
+	endOffset := parser.lexer.lastScanOffset
+	if endOffset <= startOffset || endOffset > len(parser.src) {
+		endOffset = len(parser.src)
+	}
+	return strings.TrimSpace(parser.src[startOffset:endOffset])
+}
+
 func toInt(l yyLexer, lval *yySymType, str string) int {
 	n, err := strconv.ParseUint(str, 10, 64)
 	if err != nil {
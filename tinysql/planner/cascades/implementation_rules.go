@@ -17,6 +17,7 @@ import (
 	"math"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	impl "github.com/pingcap/tidb/planner/implementation"
 	"github.com/pingcap/tidb/planner/memo"
@@ -59,6 +60,7 @@ var defaultImplementationMap = map[memo.Operand][]ImplementationRule{
 	},
 	memo.OperandAggregation: {
 		&ImplHashAgg{},
+		&ImplStreamAgg{},
 	},
 	memo.OperandLimit: {
 		&ImplLimit{},
@@ -70,6 +72,9 @@ var defaultImplementationMap = map[memo.Operand][]ImplementationRule{
 	memo.OperandJoin: {
 		&ImplHashJoinBuildLeft{},
 		&ImplHashJoinBuildRight{},
+		&ImplMergeJoin{},
+		&ImplIndexJoinLeftInner{},
+		&ImplIndexJoinRightInner{},
 	},
 }
 
@@ -112,7 +117,8 @@ func (r *ImplProjection) OnImplement(expr *memo.GroupExpr, reqProp *property.Phy
 		return nil, nil
 	}
 	proj := plannercore.PhysicalProjection{
-		Exprs: logicProj.Exprs,
+		Exprs:                logicProj.Exprs,
+		AvoidColumnEvaluator: logicProj.AvoidColumnEvaluator,
 	}.Init(logicProj.SCtx(), logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt), childProp)
 	proj.SetSchema(logicProp.Schema)
 	return impl.NewProjectionImpl(proj), nil
@@ -272,8 +278,12 @@ type ImplHashAgg struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplHashAgg) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
-	// TODO: deal with the hints when we have implemented StreamAgg.
-	return prop.IsEmpty()
+	la := expr.ExprNode.(*plannercore.LogicalAggregation)
+	_, preferStream := la.ResetHintIfConflicted()
+	// The hash aggregation is still used for the STREAM_AGG hint if the
+	// aggregation can't be executed by a stream aggregation, e.g. the final
+	// aggregation of the pushed down one.
+	return prop.IsEmpty() && !(preferStream && la.CanUseStreamAgg())
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -295,6 +305,50 @@ func (r *ImplHashAgg) OnImplement(expr *memo.GroupExpr, reqProp *property.Physic
 	}
 }
 
+// ImplStreamAgg is the implementation rule which implements LogicalAggregation
+// to PhysicalStreamAgg.
+type ImplStreamAgg struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplStreamAgg) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	la := expr.ExprNode.(*plannercore.LogicalAggregation)
+	if preferHash, _ := la.ResetHintIfConflicted(); preferHash || !la.CanUseStreamAgg() {
+		return false
+	}
+	all, desc := prop.AllSameOrder()
+	return all && prop.IsPrefix(getStreamAggChildProp(la, desc))
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+// The child of the PhysicalStreamAgg is required to be sorted by the group by
+// columns, and the ordering is provided by the child or an enforcer.
+func (r *ImplStreamAgg) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) (memo.Implementation, error) {
+	la := expr.ExprNode.(*plannercore.LogicalAggregation)
+	_, desc := reqProp.AllSameOrder()
+	childProp := getStreamAggChildProp(la, desc)
+	childCount := expr.Children[0].Prop.Stats.RowCount
+	childProp.ExpectedCnt = math.Max(reqProp.ExpectedCnt*childCount/expr.Group.Prop.Stats.RowCount, reqProp.ExpectedCnt)
+	streamAgg := plannercore.NewPhysicalStreamAgg(
+		la,
+		expr.Group.Prop.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt),
+		childProp,
+	)
+	streamAgg.SetSchema(expr.Group.Prop.Schema.Clone())
+	switch expr.Group.EngineType {
+	case memo.EngineTiDB:
+		return impl.NewTiDBStreamAggImpl(streamAgg), nil
+	case memo.EngineTiKV:
+		return impl.NewTiKVStreamAggImpl(streamAgg), nil
+	default:
+		return nil, plannercore.ErrInternal.GenWithStack("Unsupported EngineType '%s' for StreamAggregation.", expr.Group.EngineType.String())
+	}
+}
+
+func getStreamAggChildProp(la *plannercore.LogicalAggregation, desc bool) *property.PhysicalProperty {
+	return &property.PhysicalProperty{Items: property.ItemsFromCols(la.GetGroupByCols(), desc)}
+}
+
 // ImplLimit is the implementation rule which implements LogicalLimit
 // to PhysicalLimit.
 type ImplLimit struct {
@@ -394,7 +448,7 @@ type ImplHashJoinBuildLeft struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplHashJoinBuildLeft) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
-	return prop.IsEmpty()
+	return prop.IsEmpty() && !mergeJoinForced(expr, prop) && !indexJoinForced(expr, prop)
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -415,7 +469,7 @@ type ImplHashJoinBuildRight struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplHashJoinBuildRight) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
-	return prop.IsEmpty()
+	return prop.IsEmpty() && !mergeJoinForced(expr, prop) && !indexJoinForced(expr, prop)
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -429,3 +483,152 @@ func (r *ImplHashJoinBuildRight) OnImplement(expr *memo.GroupExpr, reqProp *prop
 		return nil, nil
 	}
 }
+
+// canImplMergeJoin checks whether the LogicalJoin can be implemented as a
+// PhysicalMergeJoin under the required physical property.
+func canImplMergeJoin(join *plannercore.LogicalJoin, prop *property.PhysicalProperty) bool {
+	if join.JoinType != plannercore.InnerJoin || len(join.EqualConditions) == 0 {
+		return false
+	}
+	for _, eqCond := range join.EqualConditions {
+		if eqCond.FuncName.L == ast.NullEQ {
+			return false
+		}
+	}
+	all, desc := prop.AllSameOrder()
+	return all && !desc
+}
+
+// mergeJoinForced checks whether the merge join is specified by hints and
+// can be applied, the other join algorithms are skipped in this case.
+func mergeJoinForced(expr *memo.GroupExpr, prop *property.PhysicalProperty) bool {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	return join.PreferMergeJoin() && canImplMergeJoin(join, prop)
+}
+
+// indexJoinForced checks whether the index join is specified by hints and
+// can be applied, the other join algorithms are skipped in this case. The
+// hint is removed with a warning if the index join can't be built.
+func indexJoinForced(expr *memo.GroupExpr, prop *property.PhysicalProperty) bool {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	if !join.PreferIndexJoin() {
+		return false
+	}
+	for innerIdx := 0; innerIdx < 2; innerIdx++ {
+		if join.PreferIndexJoinInner(innerIdx) && getImplForIndexJoin(expr, prop, innerIdx) != nil {
+			return true
+		}
+	}
+	join.ResetIndexJoinHint()
+	return false
+}
+
+// ImplMergeJoin implements LogicalJoin to PhysicalMergeJoin.
+type ImplMergeJoin struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplMergeJoin) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	return canImplMergeJoin(join, prop) && !join.PreferHashJoin() && !indexJoinForced(expr, prop)
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplMergeJoin) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) (memo.Implementation, error) {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	stats := expr.Group.Prop.Stats
+	mergeJoin := plannercore.NewPhysicalMergeJoin(join, reqProp, expr.Group.Prop.Schema, stats.ScaleByExpectCnt(reqProp.ExpectedCnt))
+	if mergeJoin == nil {
+		return nil, nil
+	}
+	return impl.NewMergeJoinImpl(mergeJoin), nil
+}
+
+// getIndexJoinInnerSource finds the DataSource which can be used as the
+// inner child of an index join in the Group, along with the conditions
+// filtering it. Only the Selections and the TableScan are allowed between
+// the Group and the DataSource.
+func getIndexJoinInnerSource(g *memo.Group) (*plannercore.DataSource, []expression.Expression) {
+	var conds []expression.Expression
+	for {
+		var next *memo.Group
+		for elem := g.Equivalents.Front(); elem != nil && next == nil; elem = elem.Next() {
+			groupExpr := elem.Value.(*memo.GroupExpr)
+			switch node := groupExpr.ExprNode.(type) {
+			case *plannercore.LogicalSelection:
+				conds = append(conds, node.Conditions...)
+				next = groupExpr.Children[0]
+			case *plannercore.TiKVSingleGather:
+				if !node.IsIndexGather {
+					next = groupExpr.Children[0]
+				}
+			case *plannercore.LogicalTableScan:
+				return node.Source, append(conds, node.AccessConds...)
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+		g = next
+	}
+}
+
+func getImplForIndexJoin(expr *memo.GroupExpr, prop *property.PhysicalProperty, innerIdx int) memo.Implementation {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	if join.JoinType != plannercore.InnerJoin || len(join.EqualConditions) == 0 {
+		return nil
+	}
+	ds, conds := getIndexJoinInnerSource(expr.Children[innerIdx])
+	if ds == nil {
+		return nil
+	}
+	outerGroup := expr.Children[1-innerIdx]
+	stats := expr.Group.Prop.Stats
+	indexJoin := join.GetIndexJoin(prop, 1-innerIdx, outerGroup.Prop.Schema, outerGroup.Prop.Stats,
+		ds, conds, expr.Group.Prop.Schema, stats.ScaleByExpectCnt(prop.ExpectedCnt))
+	if indexJoin == nil {
+		return nil
+	}
+	return impl.NewIndexJoinImpl(indexJoin)
+}
+
+// matchIndexJoin checks whether the index join which uses the child of
+// innerIdx as the inner child should be considered.
+func matchIndexJoin(expr *memo.GroupExpr, prop *property.PhysicalProperty, innerIdx int) bool {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	if join.PreferHashJoin() || mergeJoinForced(expr, prop) {
+		return false
+	}
+	if !join.PreferIndexJoinInner(innerIdx) && indexJoinForced(expr, prop) {
+		return false
+	}
+	return true
+}
+
+// ImplIndexJoinLeftInner implements LogicalJoin to PhysicalIndexJoin which uses the left child as the inner child.
+type ImplIndexJoinLeftInner struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplIndexJoinLeftInner) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return matchIndexJoin(expr, prop, 0)
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplIndexJoinLeftInner) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) (memo.Implementation, error) {
+	return getImplForIndexJoin(expr, reqProp, 0), nil
+}
+
+// ImplIndexJoinRightInner implements LogicalJoin to PhysicalIndexJoin which uses the right child as the inner child.
+type ImplIndexJoinRightInner struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplIndexJoinRightInner) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return matchIndexJoin(expr, prop, 1)
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplIndexJoinRightInner) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) (memo.Implementation, error) {
+	return getImplForIndexJoin(expr, reqProp, 1), nil
+}
@@ -28,10 +28,21 @@ import (
 // transformation and implementation rules.
 var DefaultOptimizer = NewOptimizer()
 
+// defaultExplorationBudget is the default number of the alternative
+// GroupExprs which can be generated in the exploration phase.
+const defaultExplorationBudget = 10000
+
 // Optimizer is the struct for cascades optimizer.
 type Optimizer struct {
 	transformationRuleMap map[memo.Operand][]Transformation
 	implementationRuleMap map[memo.Operand][]ImplementationRule
+
+	// explorationBudget limits the number of the alternative GroupExprs,
+	// i.e. the new GroupExprs which don't replace the old ones, generated in
+	// the exploration phase. Once the budget is exhausted, the alternatives
+	// are dropped, so the search time is bounded when the search space is
+	// huge, e.g. reordering a large join graph.
+	explorationBudget int
 }
 
 // NewOptimizer returns a cascades optimizer with default transformation
@@ -40,9 +51,16 @@ func NewOptimizer() *Optimizer {
 	return &Optimizer{
 		transformationRuleMap: defaultTransformationMap,
 		implementationRuleMap: defaultImplementationMap,
+		explorationBudget:     defaultExplorationBudget,
 	}
 }
 
+// SetExplorationBudget sets the explorationBudget of the optimizer, and returns the optimizer.
+func (opt *Optimizer) SetExplorationBudget(budget int) *Optimizer {
+	opt.explorationBudget = budget
+	return opt
+}
+
 // ResetTransformationRules resets the transformationRuleMap of the optimizer, and returns the optimizer.
 func (opt *Optimizer) ResetTransformationRules(rules map[memo.Operand][]Transformation) *Optimizer {
 	opt.transformationRuleMap = rules
@@ -125,12 +143,33 @@ func (opt *Optimizer) onPhasePreprocessing(sctx sessionctx.Context, plan planner
 	if err != nil {
 		return nil, err
 	}
+	// The join order specified by the LEADING hint is applied here, the joins
+	// built for the hint are not reordered by the exploration.
+	plan, err = plannercore.ApplyLeadingHint(sctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	// The joins built from the ON clauses only have the equal conditions, the
+	// join keys are needed to estimate their row counts.
+	setAllJoinKeys(plan)
 	return plan, nil
 }
 
+// setAllJoinKeys builds the join keys for all the LogicalJoins in the plan.
+func setAllJoinKeys(p plannercore.LogicalPlan) {
+	if join, ok := p.(*plannercore.LogicalJoin); ok {
+		setJoinKeys(join)
+	}
+	for _, child := range p.Children() {
+		setAllJoinKeys(child)
+	}
+}
+
 func (opt *Optimizer) onPhaseExploration(sctx sessionctx.Context, g *memo.Group) error {
+	budget := opt.explorationBudget
+	tracer := newExploreTracer(sctx, g)
 	for !g.Explored {
-		err := opt.exploreGroup(g)
+		err := opt.exploreGroup(g, &budget, tracer)
 		if err != nil {
 			return err
 		}
@@ -138,7 +177,10 @@ func (opt *Optimizer) onPhaseExploration(sctx sessionctx.Context, g *memo.Group)
 	return nil
 }
 
-func (opt *Optimizer) exploreGroup(g *memo.Group) error {
+// exploreGroup explores the Group recursively. budget is the remained number
+// of the alternative GroupExprs which can be generated. tracer records the
+// applied transformation rules, it is nil if the optimizer trace is disabled.
+func (opt *Optimizer) exploreGroup(g *memo.Group, budget *int, tracer *exploreTracer) error {
 	if g.Explored {
 		return nil
 	}
@@ -154,13 +196,13 @@ func (opt *Optimizer) exploreGroup(g *memo.Group) error {
 		// Explore child groups firstly.
 		for _, childGroup := range curExpr.Children {
 			for !childGroup.Explored {
-				if err := opt.exploreGroup(childGroup); err != nil {
+				if err := opt.exploreGroup(childGroup, budget, tracer); err != nil {
 					return err
 				}
 			}
 		}
 
-		eraseCur, err := opt.findMoreEquiv(g, elem)
+		eraseCur, err := opt.findMoreEquiv(g, elem, budget, tracer)
 		if err != nil {
 			return err
 		}
@@ -172,7 +214,7 @@ func (opt *Optimizer) exploreGroup(g *memo.Group) error {
 }
 
 // findMoreEquiv finds and applies the matched transformation rules.
-func (opt *Optimizer) findMoreEquiv(g *memo.Group, elem *list.Element) (eraseCur bool, err error) {
+func (opt *Optimizer) findMoreEquiv(g *memo.Group, elem *list.Element, budget *int, tracer *exploreTracer) (eraseCur bool, err error) {
 	expr := elem.Value.(*memo.GroupExpr)
 	for _, rule := range opt.GetTransformationRules(expr.ExprNode) {
 		pattern := rule.GetPattern()
@@ -193,6 +235,7 @@ func (opt *Optimizer) findMoreEquiv(g *memo.Group, elem *list.Element) (eraseCur
 			}
 
 			if eraseAll {
+				tracer.record(g, rule, expr, newExprs, eraseOld, eraseAll)
 				g.DeleteAll()
 				for _, e := range newExprs {
 					g.Insert(e)
@@ -202,11 +245,19 @@ func (opt *Optimizer) findMoreEquiv(g *memo.Group, elem *list.Element) (eraseCur
 				return false, nil
 			}
 
+			if !eraseOld && *budget <= 0 {
+				// The alternatives are dropped since the budget is exhausted.
+				continue
+			}
+			tracer.record(g, rule, expr, newExprs, eraseOld, eraseAll)
 			eraseCur = eraseCur || eraseOld
 			for _, e := range newExprs {
 				if !g.Insert(e) {
 					continue
 				}
+				if !eraseOld {
+					*budget--
+				}
 				// If the new Group expression is successfully inserted into the
 				// current Group, mark the Group as unexplored to enable the exploration
 				// on the new Group expressions.
@@ -255,7 +306,27 @@ func (opt *Optimizer) onPhaseImplementation(sctx sessionctx.Context, g *memo.Gro
 	if impl == nil {
 		return nil, plannercore.ErrInternal.GenWithStackByArgs("Can't find a proper physical plan for this query")
 	}
-	return impl.GetPlan(), nil
+	return keepOutputColumnsOrder(sctx, impl.GetPlan(), g.Prop.Schema), nil
+}
+
+// keepOutputColumnsOrder adds a PhysicalProjection on the plan if its output
+// columns are not in the order of the schema. The order of the columns may be
+// changed since the children of the joins are swapped by the join reorder
+// rules.
+func keepOutputColumnsOrder(sctx sessionctx.Context, p plannercore.PhysicalPlan, schema *expression.Schema) plannercore.PhysicalPlan {
+	sameOrder := p.Schema().Len() == schema.Len()
+	for i := 0; sameOrder && i < schema.Len(); i++ {
+		sameOrder = p.Schema().Columns[i].Equal(nil, schema.Columns[i])
+	}
+	if sameOrder {
+		return p
+	}
+	proj := plannercore.PhysicalProjection{
+		Exprs: expression.Column2Exprs(schema.Columns),
+	}.Init(sctx, p.Stats())
+	proj.SetSchema(schema.Clone())
+	proj.SetChildren(p)
+	return proj
 }
 
 // implGroup finds the best Implementation which satisfies the required
@@ -291,7 +362,14 @@ func (opt *Optimizer) implGroup(g *memo.Group, reqPhysProp *property.PhysicalPro
 			cumCost = 0.0
 			childImpls = childImpls[:0]
 			for i, childGroup := range curExpr.Children {
-				childImpl, err := opt.implGroup(childGroup, impl.GetPlan().GetChildReqProps(i), impl.ScaleCostLimit(costLimit)-cumCost)
+				childReqProp := impl.GetPlan().GetChildReqProps(i)
+				if childReqProp == nil {
+					// The child is built along with the implementation, e.g.
+					// the inner child of the PhysicalIndexJoin.
+					childImpls = append(childImpls, nil)
+					continue
+				}
+				childImpl, err := opt.implGroup(childGroup, childReqProp, impl.ScaleCostLimit(costLimit)-cumCost)
 				if err != nil {
 					return nil, err
 				}
@@ -342,6 +420,14 @@ func (opt *Optimizer) implGroup(g *memo.Group, reqPhysProp *property.PhysicalPro
 }
 
 func (opt *Optimizer) implGroupExpr(cur *memo.GroupExpr, reqPhysProp *property.PhysicalProperty) (impls []memo.Implementation, err error) {
+	// The stats of the children are only filled along the first GroupExpr of
+	// each Group, but the implementation rules may need them, e.g. the join
+	// whose children Groups are created by the join reorder rules.
+	for _, childGroup := range cur.Children {
+		if err = opt.fillGroupStats(childGroup); err != nil {
+			return nil, err
+		}
+	}
 	for _, rule := range opt.GetImplementationRules(cur.ExprNode) {
 		if !rule.Match(cur, reqPhysProp) {
 			continue
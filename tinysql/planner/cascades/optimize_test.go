@@ -99,6 +99,45 @@ func (s *testCascadesSuite) TestFillGroupStats(c *C) {
 	c.Assert(rootGroup.Prop.Stats, NotNil)
 }
 
+func (s *testCascadesSuite) TestExplorationBudget(c *C) {
+	stmt, err := s.ParseOneStmt("select * from t t1, t t2, t t3 where t1.a = t2.a and t2.b = t3.b", "", "")
+	c.Assert(err, IsNil)
+	p, _, err := plannercore.BuildLogicalPlan(context.Background(), s.sctx, stmt, s.is)
+	c.Assert(err, IsNil)
+	logic, ok := p.(plannercore.LogicalPlan)
+	c.Assert(ok, IsTrue)
+	var countExprs func(g *memo.Group, visited map[*memo.Group]bool) int
+	countExprs = func(g *memo.Group, visited map[*memo.Group]bool) int {
+		if visited[g] {
+			return 0
+		}
+		visited[g] = true
+		cnt := g.Equivalents.Len()
+		for elem := g.Equivalents.Front(); elem != nil; elem = elem.Next() {
+			for _, child := range elem.Value.(*memo.GroupExpr).Children {
+				cnt += countExprs(child, visited)
+			}
+		}
+		return cnt
+	}
+	explore := func(budget int) int {
+		opt := NewOptimizer().SetExplorationBudget(budget)
+		opt.ResetTransformationRules(map[memo.Operand][]Transformation{
+			memo.OperandJoin: {
+				NewRuleJoinCommutative(),
+				NewRuleJoinAssociativity(),
+			},
+		})
+		rootGroup := memo.Convert2Group(logic)
+		c.Assert(opt.onPhaseExploration(s.sctx, rootGroup), IsNil)
+		return countExprs(rootGroup, make(map[*memo.Group]bool))
+	}
+	initial := countExprs(memo.Convert2Group(logic), make(map[*memo.Group]bool))
+	c.Assert(explore(0), Equals, initial)
+	c.Assert(explore(1), Equals, initial+1)
+	c.Assert(explore(defaultExplorationBudget) > initial+1, IsTrue)
+}
+
 func (s *testCascadesSuite) TestPreparePossibleProperties(c *C) {
 	s.optimizer.ResetTransformationRules(map[memo.Operand][]Transformation{
 		memo.OperandDataSource: {
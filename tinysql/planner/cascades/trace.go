@@ -0,0 +1,79 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascades
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pingcap/tidb/planner/memo"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/tracing"
+)
+
+// exploreTracer records the transformation rules applied in the exploration
+// phase to the optimizer trace. The Groups are numbered in the order they are
+// first seen, the root Group is Group#0.
+type exploreTracer struct {
+	tracer *tracing.OptimizeTracer
+	idMap  map[*memo.Group]int
+}
+
+// newExploreTracer returns nil if the optimizer trace is disabled.
+func newExploreTracer(sctx sessionctx.Context, root *memo.Group) *exploreTracer {
+	tracer := sctx.GetSessionVars().StmtCtx.OptimizeTracer
+	if tracer == nil {
+		return nil
+	}
+	return &exploreTracer{
+		tracer: tracer,
+		idMap:  map[*memo.Group]int{root: 0},
+	}
+}
+
+func (t *exploreTracer) groupID(g *memo.Group) int {
+	id, ok := t.idMap[g]
+	if !ok {
+		id = len(t.idMap)
+		t.idMap[g] = id
+	}
+	return id
+}
+
+func (t *exploreTracer) exprToString(expr *memo.GroupExpr) string {
+	for _, child := range expr.Children {
+		t.groupID(child)
+	}
+	return groupExprToString(expr, t.idMap)
+}
+
+// record records the new GroupExprs generated from the expr of the Group by
+// the rule. It does nothing if the tracer is nil.
+func (t *exploreTracer) record(g *memo.Group, rule Transformation, expr *memo.GroupExpr, newExprs []*memo.GroupExpr, eraseOld, eraseAll bool) {
+	if t == nil || (len(newExprs) == 0 && !eraseOld && !eraseAll) {
+		return
+	}
+	trace := &tracing.ExploreTrace{
+		Group:    fmt.Sprintf("Group#%d", t.groupID(g)),
+		Rule:     reflect.Indirect(reflect.ValueOf(rule)).Type().Name(),
+		Expr:     t.exprToString(expr),
+		NewExpr:  make([]string, 0, len(newExprs)),
+		EraseOld: eraseOld,
+		EraseAll: eraseAll,
+	}
+	for _, e := range newExprs {
+		trace.NewExpr = append(trace.NewExpr, t.exprToString(e))
+	}
+	t.tracer.AppendExplore(trace)
+}
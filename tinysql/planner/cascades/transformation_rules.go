@@ -69,6 +69,10 @@ var defaultTransformationMap = map[memo.Operand][]Transformation{
 	memo.OperandTopN: {
 		NewRulePushTopNDownProjection(),
 	},
+	memo.OperandJoin: {
+		NewRuleJoinCommutative(),
+		NewRuleJoinAssociativity(),
+	},
 }
 
 type baseRule struct {
@@ -335,10 +339,12 @@ func (r *PushAggDownGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.Gr
 	aggSchema := old.GetExpr().Group.Prop.Schema
 	gather := old.Children[0].GetExpr().ExprNode.(*plannercore.TiKVSingleGather)
 	childGroup := old.Children[0].GetExpr().Children[0]
+	copPartialAggFuncs, finalAggFuncs, finalGbyItems, partialSchema :=
+		plannercore.BuildFinalModeAggregation(agg.SCtx(), agg.AggFuncs, agg.GroupByItems, aggSchema)
 	// The old Aggregation should stay unchanged for other transformation.
 	// So we build a new LogicalAggregation for the partialAgg.
-	partialAggFuncs := make([]*aggregation.AggFuncDesc, len(agg.AggFuncs))
-	for i, aggFunc := range agg.AggFuncs {
+	partialAggFuncs := make([]*aggregation.AggFuncDesc, len(copPartialAggFuncs))
+	for i, aggFunc := range copPartialAggFuncs {
 		newAggFunc := &aggregation.AggFuncDesc{
 			Mode: aggregation.Partial1Mode,
 		}
@@ -356,9 +362,8 @@ func (r *PushAggDownGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.Gr
 		AggFuncs:     partialAggFuncs,
 		GroupByItems: partialGbyItems,
 	}.Init(agg.SCtx())
+	partialAgg.CopyAggHints(agg)
 
-	finalAggFuncs, finalGbyItems, partialSchema :=
-		plannercore.BuildFinalModeAggregation(partialAgg.SCtx(), partialAgg.AggFuncs, partialAgg.GroupByItems, aggSchema)
 	// Remove unnecessary FirstRow.
 	partialAgg.AggFuncs =
 		plannercore.RemoveUnnecessaryFirstRow(partialAgg.SCtx(), finalAggFuncs, finalGbyItems, partialAgg.AggFuncs, partialAgg.GroupByItems, partialSchema)
@@ -366,6 +371,7 @@ func (r *PushAggDownGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.Gr
 		AggFuncs:     finalAggFuncs,
 		GroupByItems: finalGbyItems,
 	}.Init(agg.SCtx())
+	finalAgg.CopyAggHints(agg)
 
 	partialAggExpr := memo.NewGroupExpr(partialAgg)
 	partialAggExpr.SetChildren(childGroup)
@@ -618,7 +624,7 @@ func buildChildSelectionGroup(
 	newSel := plannercore.LogicalSelection{Conditions: conditions}.Init(oldSel.SCtx())
 	groupExpr := memo.NewGroupExpr(newSel)
 	groupExpr.SetChildren(childGroup)
-	newChild := memo.NewGroupWithSchema(groupExpr, childGroup.Prop.Schema)
+	newChild := memo.NewSelectionGroupWithSchema(groupExpr, childGroup.Prop.Schema)
 	return newChild
 }
 
@@ -627,8 +633,8 @@ func buildChildSelectionGroup(
 func (r *PushSelDownJoin) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
 	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
 	joinExpr := old.Children[0].GetExpr()
-	// TODO: we need to create a new LogicalJoin here.
-	join := joinExpr.ExprNode.(*plannercore.LogicalJoin)
+	// The old join may be referred by other GroupExprs, so we create a new one.
+	join := joinExpr.ExprNode.(*plannercore.LogicalJoin).Shallow()
 	sctx := sel.SCtx()
 	leftGroup := old.Children[0].GetExpr().Children[0]
 	rightGroup := old.Children[0].GetExpr().Children[1]
@@ -662,10 +668,7 @@ func (r *PushSelDownJoin) OnTransform(old *memo.ExprIter) (newExprs []*memo.Grou
 	}
 	leftCond = expression.RemoveDupExprs(sctx, leftCond)
 	rightCond = expression.RemoveDupExprs(sctx, rightCond)
-	for _, eqCond := range join.EqualConditions {
-		join.LeftJoinKeys = append(join.LeftJoinKeys, eqCond.GetArgs()[0].(*expression.Column))
-		join.RightJoinKeys = append(join.RightJoinKeys, eqCond.GetArgs()[1].(*expression.Column))
-	}
+	setJoinKeys(join)
 	// TODO: Update EqualConditions like what we have done in the method join.updateEQCond() before.
 	leftGroup = buildChildSelectionGroup(sel, leftCond, joinExpr.Children[0])
 	rightGroup = buildChildSelectionGroup(sel, rightCond, joinExpr.Children[1])
@@ -879,8 +882,134 @@ func (r *MergeAggregationProjection) OnTransform(old *memo.ExprIter) (newExprs [
 		GroupByItems: groupByItems,
 		AggFuncs:     aggFuncs,
 	}.Init(oldAgg.SCtx())
+	newAgg.CopyAggHints(oldAgg)
 
 	newAggExpr := memo.NewGroupExpr(newAgg)
 	newAggExpr.SetChildren(old.Children[0].GetExpr().Children...)
 	return []*memo.GroupExpr{newAggExpr}, true, false, nil
 }
+
+// setJoinKeys builds the join keys from the equal conditions of the join.
+func setJoinKeys(join *plannercore.LogicalJoin) {
+	join.LeftJoinKeys = make([]*expression.Column, 0, len(join.EqualConditions))
+	join.RightJoinKeys = make([]*expression.Column, 0, len(join.EqualConditions))
+	for _, eqCond := range join.EqualConditions {
+		join.LeftJoinKeys = append(join.LeftJoinKeys, eqCond.GetArgs()[0].(*expression.Column))
+		join.RightJoinKeys = append(join.RightJoinKeys, eqCond.GetArgs()[1].(*expression.Column))
+	}
+}
+
+// getJoinConditions returns all the conditions of the join.
+func getJoinConditions(join *plannercore.LogicalJoin) []expression.Expression {
+	conds := make([]expression.Expression, 0,
+		len(join.EqualConditions)+len(join.LeftConditions)+len(join.RightConditions)+len(join.OtherConditions))
+	conds = append(conds, expression.ScalarFuncs2Exprs(join.EqualConditions)...)
+	conds = append(conds, join.LeftConditions...)
+	conds = append(conds, join.RightConditions...)
+	conds = append(conds, join.OtherConditions...)
+	return conds
+}
+
+// setJoinConditions replaces the conditions of the join by the conds, which
+// are split according to the schemas of the join children.
+func setJoinConditions(join *plannercore.LogicalJoin, conds []expression.Expression, leftSchema, rightSchema *expression.Schema) {
+	join.EqualConditions, join.LeftConditions, join.RightConditions, join.OtherConditions =
+		join.ExtractOnCondition(conds, leftSchema, rightSchema, false, false)
+	setJoinKeys(join)
+}
+
+// JoinCommutative swaps the children of an inner join.
+type JoinCommutative struct {
+	baseRule
+}
+
+// NewRuleJoinCommutative creates a new Transformation JoinCommutative.
+// The pattern of this rule is: `Join`.
+func NewRuleJoinCommutative() Transformation {
+	rule := &JoinCommutative{}
+	rule.pattern = memo.NewPattern(memo.OperandJoin, memo.EngineTiDBOnly)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *JoinCommutative) Match(expr *memo.ExprIter) bool {
+	join := expr.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	return join.IsReorderable()
+}
+
+// OnTransform implements Transformation interface.
+// It will transform `Join(A, B)` to `Join(B, A)`.
+func (r *JoinCommutative) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	joinExpr := old.GetExpr()
+	join := joinExpr.ExprNode.(*plannercore.LogicalJoin)
+	leftGroup, rightGroup := joinExpr.Children[0], joinExpr.Children[1]
+	newJoin := join.Shallow()
+	setJoinConditions(newJoin, getJoinConditions(join), rightGroup.Prop.Schema, leftGroup.Prop.Schema)
+	newJoinExpr := memo.NewGroupExpr(newJoin)
+	newJoinExpr.SetChildren(rightGroup, leftGroup)
+	// The old join is kept since the new one would not always be better.
+	return []*memo.GroupExpr{newJoinExpr}, false, false, nil
+}
+
+// JoinAssociativity changes the association of two adjacent inner joins.
+type JoinAssociativity struct {
+	baseRule
+}
+
+// NewRuleJoinAssociativity creates a new Transformation JoinAssociativity.
+// The pattern of this rule is: `Join -> (Join, Any)`.
+func NewRuleJoinAssociativity() Transformation {
+	rule := &JoinAssociativity{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandJoin,
+		memo.EngineTiDBOnly,
+		memo.NewPattern(memo.OperandJoin, memo.EngineTiDBOnly),
+		memo.NewPattern(memo.OperandAny, memo.EngineTiDBOnly),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *JoinAssociativity) Match(expr *memo.ExprIter) bool {
+	topJoin := expr.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	bottomJoin := expr.Children[0].GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	return topJoin.IsReorderable() && bottomJoin.IsReorderable()
+}
+
+// OnTransform implements Transformation interface.
+// It will transform `Join(Join(A, B), C)` to `Join(A, Join(B, C))`. The
+// conditions of the two joins are redistributed, the ones which only refer to
+// B and C are evaluated by the new bottom join. The transformation is skipped
+// if the new bottom join is a cartesian product.
+func (r *JoinAssociativity) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	topJoin := old.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	bottomJoinExpr := old.Children[0].GetExpr()
+	bottomJoin := bottomJoinExpr.ExprNode.(*plannercore.LogicalJoin)
+	groupA, groupB := bottomJoinExpr.Children[0], bottomJoinExpr.Children[1]
+	groupC := old.Children[1].Group
+	schemaBC := expression.MergeSchema(groupB.Prop.Schema, groupC.Prop.Schema)
+
+	var topConds, bottomConds []expression.Expression
+	for _, cond := range append(getJoinConditions(topJoin), getJoinConditions(bottomJoin)...) {
+		if expression.ExprFromSchema(cond, schemaBC) {
+			bottomConds = append(bottomConds, cond)
+		} else {
+			topConds = append(topConds, cond)
+		}
+	}
+	newBottomJoin := bottomJoin.Shallow()
+	setJoinConditions(newBottomJoin, bottomConds, groupB.Prop.Schema, groupC.Prop.Schema)
+	if len(newBottomJoin.EqualConditions) == 0 {
+		return nil, false, false, nil
+	}
+	newTopJoin := topJoin.Shallow()
+	setJoinConditions(newTopJoin, topConds, groupA.Prop.Schema, schemaBC)
+
+	newBottomJoinExpr := memo.NewGroupExpr(newBottomJoin)
+	newBottomJoinExpr.SetChildren(groupB, groupC)
+	newBottomJoinGroup := memo.NewJoinGroupWithSchema(newBottomJoinExpr, schemaBC)
+	newTopJoinExpr := memo.NewGroupExpr(newTopJoin)
+	newTopJoinExpr.SetChildren(groupA, newBottomJoinGroup)
+	// The old join is kept since the new one would not always be better.
+	return []*memo.GroupExpr{newTopJoinExpr}, false, false, nil
+}
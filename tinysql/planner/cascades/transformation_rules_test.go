@@ -198,3 +198,25 @@ func (s *testTransformationRuleSuite) TestMergeAggregationProjection(c *C) {
 	s.testData.GetTestCases(c, &input, &output)
 	testGroupToString(input, output, s, c)
 }
+
+func (s *testTransformationRuleSuite) TestJoinReorder(c *C) {
+	s.optimizer.ResetTransformationRules(map[memo.Operand][]Transformation{
+		memo.OperandSelection: {
+			NewRulePushSelDownJoin(),
+		},
+		memo.OperandJoin: {
+			NewRuleJoinCommutative(),
+			NewRuleJoinAssociativity(),
+		},
+	})
+	defer func() {
+		s.optimizer.ResetTransformationRules(defaultTransformationMap)
+	}()
+	var input []string
+	var output []struct {
+		SQL    string
+		Result []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	testGroupToString(input, output, s, c)
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/kvcache"
+)
+
+// pstmtPlanCacheKey is the key of the plan cache of the prepared statements.
+// A cached plan is only reused when the statement, the schema, the system
+// variables and the types of the parameters are all the same as they were
+// when the plan was built.
+type pstmtPlanCacheKey struct {
+	database       string
+	sqlDigest      string
+	schemaVersion  int64
+	sysVarsVersion uint64
+	// inWriteTxn indicates whether the plan reads the dirty data of the
+	// transaction through UnionScan.
+	inWriteTxn bool
+	paramTypes []*types.FieldType
+	// bindSQL is the bind sql of the sql binding applied to the plan, the
+	// cached plan is not used after the binding is changed.
+	bindSQL string
+
+	hash []byte
+}
+
+// Hash implements Key interface.
+func (key *pstmtPlanCacheKey) Hash() []byte {
+	if len(key.hash) == 0 {
+		key.hash = codec.EncodeCompactBytes(key.hash, []byte(key.database))
+		key.hash = codec.EncodeCompactBytes(key.hash, []byte(key.sqlDigest))
+		key.hash = codec.EncodeInt(key.hash, key.schemaVersion)
+		key.hash = codec.EncodeUint(key.hash, key.sysVarsVersion)
+		if key.inWriteTxn {
+			key.hash = append(key.hash, 1)
+		} else {
+			key.hash = append(key.hash, 0)
+		}
+		for _, tp := range key.paramTypes {
+			key.hash = append(key.hash, tp.Tp)
+			key.hash = codec.EncodeUint(key.hash, uint64(tp.Flag))
+			key.hash = codec.EncodeInt(key.hash, int64(tp.Decimal))
+		}
+		key.hash = codec.EncodeCompactBytes(key.hash, []byte(key.bindSQL))
+	}
+	return key.hash
+}
+
+// newPSTMTPlanCacheKey creates a new pstmtPlanCacheKey object.
+func newPSTMTPlanCacheKey(sctx sessionctx.Context, sqlDigest string, schemaVersion int64, bindSQL string) (kvcache.Key, error) {
+	vars := sctx.GetSessionVars()
+	txn, err := sctx.Txn(false)
+	if err != nil {
+		return nil, err
+	}
+	key := &pstmtPlanCacheKey{
+		database:       vars.CurrentDB,
+		sqlDigest:      sqlDigest,
+		schemaVersion:  schemaVersion,
+		sysVarsVersion: vars.SysVarsVersion,
+		inWriteTxn:     txn.Valid() && !txn.IsReadOnly(),
+		paramTypes:     make([]*types.FieldType, 0, len(vars.PreparedParams)),
+		bindSQL:        bindSQL,
+	}
+	for _, param := range vars.PreparedParams {
+		tp := types.NewFieldType(0)
+		types.DefaultParamTypeForValue(param.GetValue(), tp)
+		key.paramTypes = append(key.paramTypes, tp)
+	}
+	return key, nil
+}
+
+// PSTMTPlanCacheValue stores the cached plan of a prepared statement.
+type PSTMTPlanCacheValue struct {
+	Plan        Plan
+	OutputNames types.NameSlice
+}
+
+// NewPSTMTPlanCacheValue creates a PSTMTPlanCacheValue.
+func NewPSTMTPlanCacheValue(plan Plan, names types.NameSlice) *PSTMTPlanCacheValue {
+	return &PSTMTPlanCacheValue{
+		Plan:        plan,
+		OutputNames: names,
+	}
+}
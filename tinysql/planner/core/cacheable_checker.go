@@ -0,0 +1,102 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/parser/ast"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+)
+
+// Cacheable checks whether the plan of the statement can be put into the
+// plan cache of the prepared statements.
+func Cacheable(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.SelectStmt, *ast.UpdateStmt, *ast.InsertStmt, *ast.DeleteStmt:
+	default:
+		return false
+	}
+	checker := cacheableChecker{
+		cacheable: true,
+	}
+	node.Accept(&checker)
+	return checker.cacheable
+}
+
+// cacheableChecker checks whether a query's plan can be cached, queries that:
+//  1. have subqueries, or
+//  2. have VariableExpr, or
+//  3. have LIKE, or
+//  4. have a parameter in the LIMIT clause, or
+//  5. call a function whose result is decided when the plan is built,
+//
+// will not be cached.
+type cacheableChecker struct {
+	cacheable bool
+}
+
+// Enter implements Visitor interface.
+func (checker *cacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch node := in.(type) {
+	case *ast.VariableExpr, *ast.ExistsSubqueryExpr, *ast.SubqueryExpr:
+		checker.cacheable = false
+		return in, true
+	case *ast.FuncCallExpr:
+		if _, found := uncacheableFunctions[node.FnName.L]; found {
+			checker.cacheable = false
+			return in, true
+		}
+	case *ast.PatternLikeExpr:
+		// The pattern decides whether LIKE is rewritten to '=' and how the
+		// ranges are built.
+		checker.cacheable = false
+		return in, true
+	case *ast.Limit:
+		if node.Count != nil {
+			if _, isParamMarker := node.Count.(*driver.ParamMarkerExpr); isParamMarker {
+				checker.cacheable = false
+				return in, true
+			}
+		}
+		if node.Offset != nil {
+			if _, isParamMarker := node.Offset.(*driver.ParamMarkerExpr); isParamMarker {
+				checker.cacheable = false
+				return in, true
+			}
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (checker *cacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, checker.cacheable
+}
+
+// uncacheableFunctions are the functions which are folded to constants when
+// the plan is built, but whose results change between executions.
+var uncacheableFunctions = map[string]struct{}{
+	ast.Now:              {},
+	ast.CurrentTimestamp: {},
+	ast.CurrentDate:      {},
+	ast.CurrentTime:      {},
+	ast.Curdate:          {},
+	ast.Curtime:          {},
+	ast.LocalTime:        {},
+	ast.LocalTimestamp:   {},
+	ast.UTCDate:          {},
+	ast.UTCTime:          {},
+	ast.UTCTimestamp:     {},
+	ast.UnixTimestamp:    {},
+	ast.Sysdate:          {},
+}
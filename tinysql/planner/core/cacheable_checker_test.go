@@ -0,0 +1,53 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser"
+)
+
+var _ = Suite(&testCacheableSuite{})
+
+type testCacheableSuite struct {
+}
+
+func (s *testCacheableSuite) TestCacheable(c *C) {
+	tests := []struct {
+		sql       string
+		cacheable bool
+	}{
+		{"select a from t where a > ?", true},
+		{"select a, count(*) from t where b = ? group by a order by a", true},
+		{"update t set a = ? where b = ?", true},
+		{"delete from t where a = ?", true},
+		{"insert into t values (?, ?)", true},
+		{"select a from t where a > ? limit ?", false},
+		{"select a from t where a > ? limit 1, ?", false},
+		{"select a from t where a > ? limit 10", true},
+		{"select a from t where a in (select b from t)", false},
+		{"select a from t where exists (select b from t where b = ?)", false},
+		{"select a from t where a = @a", false},
+		{"select a from t where b like ?", false},
+		{"select a from t where b < now()", false},
+		{"delete from t where b < unix_timestamp()", false},
+		{"set @a = ?", false},
+	}
+	p := parser.New()
+	for _, tt := range tests {
+		stmt, err := p.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, Commentf("for %s", tt.sql))
+		c.Assert(Cacheable(stmt), Equals, tt.cacheable, Commentf("for %s", tt.sql))
+	}
+}
@@ -15,17 +15,26 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/ranger"
 )
 
 // ShowDDL is for showing DDL information.
@@ -33,6 +42,248 @@ type ShowDDL struct {
 	baseSchemaProducer
 }
 
+// Prepare represents prepare plan.
+type Prepare struct {
+	baseSchemaProducer
+
+	Name    string
+	SQLText string
+}
+
+// Execute represents prepare plan.
+type Execute struct {
+	baseSchemaProducer
+
+	Name          string
+	UsingVars     []expression.Expression
+	PrepareParams []types.Datum
+	ExecID        uint32
+	Stmt          ast.StmtNode
+	Plan          Plan
+}
+
+// OptimizePreparedPlan optimizes the prepared statement.
+func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema) error {
+	vars := sctx.GetSessionVars()
+	if e.Name != "" {
+		e.ExecID = vars.PreparedStmtNameToID[e.Name]
+	}
+	preparedPointer, ok := vars.PreparedStmts[e.ExecID]
+	if !ok {
+		return errors.Trace(ErrStmtNotFound)
+	}
+	prepared, ok := preparedPointer.(*ast.Prepared)
+	if !ok {
+		return errors.Errorf("invalid prepared statement type")
+	}
+
+	paramLen := len(e.PrepareParams)
+	if paramLen > 0 {
+		// for binary protocol execute, argument is placed in vars.PrepareParams
+		if len(prepared.Params) != paramLen {
+			return errors.Trace(ErrWrongParamCount)
+		}
+		vars.PreparedParams = e.PrepareParams
+		for i, val := range vars.PreparedParams {
+			param := prepared.Params[i].(*driver.ParamMarkerExpr)
+			param.Datum = val
+			param.InExecute = true
+		}
+	} else {
+		// for `execute stmt using @a, @b, @c`, using value in e.UsingVars
+		if len(prepared.Params) != len(e.UsingVars) {
+			return errors.Trace(ErrWrongParamCount)
+		}
+
+		for i, usingVar := range e.UsingVars {
+			val, err := usingVar.Eval(chunk.Row{})
+			if err != nil {
+				return err
+			}
+			param := prepared.Params[i].(*driver.ParamMarkerExpr)
+			param.Datum = val
+			param.InExecute = true
+			vars.PreparedParams = append(vars.PreparedParams, val)
+		}
+	}
+
+	if prepared.SchemaVersion != is.SchemaMetaVersion() {
+		// If the schema version has changed we need to preprocess it again,
+		// if this time it failed, the real reason for the error is schema changed.
+		err := Preprocess(sctx, prepared.Stmt, is, InPrepare)
+		if err != nil {
+			return ErrSchemaChanged.GenWithStack("Schema change caused error: %s", err.Error())
+		}
+		prepared.SchemaVersion = is.SchemaMetaVersion()
+	}
+	// The prepared statement is reused by the following executions, so the
+	// hints of the sql binding only replace its own hints during this
+	// optimization.
+	var bindSQL string
+	if selStmt, ok := prepared.Stmt.(*ast.SelectStmt); ok {
+		if record := GetBindRecord(sctx, parser.NormalizeForBinding(selStmt.Text())); record != nil {
+			origin := bindinfo.CollectHint(selStmt)
+			if bindinfo.BindHint(selStmt, record.HintsSet()) {
+				bindSQL = record.BindSQL
+				defer bindinfo.BindHint(selStmt, origin)
+			}
+		}
+	}
+	err := e.getPhysicalPlan(ctx, sctx, is, prepared, bindSQL)
+	if err != nil {
+		return err
+	}
+	e.Stmt = prepared.Stmt
+	return nil
+}
+
+// GetBindRecord gets the sql binding of the normalized sql in the current
+// database. The session bindings take precedence over the global ones.
+func GetBindRecord(sctx sessionctx.Context, normdOrigSQL string) *bindinfo.BindRecord {
+	db := sctx.GetSessionVars().CurrentDB
+	if handle, ok := sctx.Value(bindinfo.SessionBindInfoKeyType).(*bindinfo.SessionHandle); ok {
+		if record := handle.GetBindRecord(normdOrigSQL, db); record != nil {
+			return record
+		}
+	}
+	dom := domain.GetDomain(sctx)
+	if dom == nil || dom.BindHandle() == nil {
+		return nil
+	}
+	return dom.BindHandle().GetBindRecord(normdOrigSQL, db)
+}
+
+func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, prepared *ast.Prepared, bindSQL string) error {
+	vars := sctx.GetSessionVars()
+	if !prepared.UseCache || !vars.EnablePreparedPlanCache {
+		p, names, err := OptimizeAstNode(ctx, sctx, prepared.Stmt, is)
+		if err != nil {
+			return err
+		}
+		e.names = names
+		e.Plan = p
+		return nil
+	}
+	cacheKey, err := newPSTMTPlanCacheKey(sctx, prepared.SQLDigest, prepared.SchemaVersion, bindSQL)
+	if err != nil {
+		return err
+	}
+	planCache := sctx.PreparedPlanCache()
+	if cacheValue, exists := planCache.Get(cacheKey); exists {
+		cachedVal := cacheValue.(*PSTMTPlanCacheValue)
+		if err := e.rebuildRange(cachedVal.Plan); err != nil {
+			return err
+		}
+		vars.PlanCacheHits++
+		e.names = cachedVal.OutputNames
+		e.Plan = cachedVal.Plan
+		return nil
+	}
+	vars.PlanCacheMisses++
+	vars.StmtCtx.UseCache = true
+	p, names, err := OptimizeAstNode(ctx, sctx, prepared.Stmt, is)
+	if err != nil {
+		return err
+	}
+	if !vars.StmtCtx.SkipPlanCache {
+		planCache.Put(cacheKey, NewPSTMTPlanCacheValue(p, names))
+	}
+	e.names = names
+	e.Plan = p
+	return nil
+}
+
+// rebuildRange rebuilds the ranges of the scans in the cached plan, because
+// they are built from the parameters of the execution which cached the plan.
+func (e *Execute) rebuildRange(p Plan) error {
+	sctx := p.SCtx()
+	sc := sctx.GetSessionVars().StmtCtx
+	var err error
+	switch x := p.(type) {
+	case *PhysicalTableScan:
+		if len(x.AccessCondition) == 0 {
+			return nil
+		}
+		// All the access conditions of a table scan are on the handle column.
+		var pkCol *expression.Column
+		columnLen := len(x.schema.Columns)
+		isUnsigned := false
+		if x.Table.PKIsHandle {
+			if pkColInfo := x.Table.GetPkColInfo(); pkColInfo != nil {
+				isUnsigned = mysql.HasUnsignedFlag(pkColInfo.Flag)
+				pkCol = expression.ColInfo2Col(x.schema.Columns, pkColInfo)
+			}
+		} else if columnLen > 0 && x.schema.Columns[columnLen-1].ID == model.ExtraHandleID {
+			pkCol = x.schema.Columns[columnLen-1]
+		}
+		if pkCol == nil {
+			x.Ranges = ranger.FullIntRange(isUnsigned)
+			return nil
+		}
+		x.Ranges, err = ranger.BuildTableRange(x.AccessCondition, sc, pkCol.RetType)
+		if err != nil {
+			return err
+		}
+	case *PhysicalIndexScan:
+		if len(x.AccessCondition) == 0 {
+			return nil
+		}
+		res, err := ranger.DetachCondAndBuildRangeForIndex(sctx, x.AccessCondition, x.IdxCols, x.IdxColLens)
+		if err != nil {
+			return err
+		}
+		x.Ranges = res.Ranges
+	case *PhysicalTableReader:
+		for _, child := range x.TablePlans {
+			if err = e.rebuildRange(child); err != nil {
+				return err
+			}
+		}
+	case *PhysicalIndexReader:
+		for _, child := range x.IndexPlans {
+			if err = e.rebuildRange(child); err != nil {
+				return err
+			}
+		}
+	case *PhysicalIndexLookUpReader:
+		for _, child := range x.IndexPlans {
+			if err = e.rebuildRange(child); err != nil {
+				return err
+			}
+		}
+	case *PhysicalIndexJoin:
+		// The ranges of the inner side are decided by the outer rows, the
+		// plan is not cached if they depend on the parameters.
+		return e.rebuildRange(x.children[1-x.InnerChildIdx])
+	case *Insert:
+		if x.SelectPlan != nil {
+			return e.rebuildRange(x.SelectPlan)
+		}
+	case *Update:
+		if x.SelectPlan != nil {
+			return e.rebuildRange(x.SelectPlan)
+		}
+	case *Delete:
+		if x.SelectPlan != nil {
+			return e.rebuildRange(x.SelectPlan)
+		}
+	case PhysicalPlan:
+		for _, child := range x.Children() {
+			if err = e.rebuildRange(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Deallocate represents deallocate plan.
+type Deallocate struct {
+	baseSchemaProducer
+
+	Name string
+}
+
 // Set represents a plan for set stmt.
 type Set struct {
 	baseSchemaProducer
@@ -47,6 +298,27 @@ type Simple struct {
 	Statement ast.StmtNode
 }
 
+// SQLBindOpType represents the SQL bind type.
+type SQLBindOpType int
+
+const (
+	// OpSQLBindCreate represents the operation to create a SQL bind.
+	OpSQLBindCreate SQLBindOpType = iota
+	// OpSQLBindDrop represents the operation to drop a SQL bind.
+	OpSQLBindDrop
+)
+
+// SQLBindPlan represents a plan for SQL bind.
+type SQLBindPlan struct {
+	baseSchemaProducer
+
+	SQLBindOp    SQLBindOpType
+	NormdOrigSQL string
+	BindSQL      string
+	IsGlobal     bool
+	Db           string
+}
+
 // Insert represents an insert plan.
 type Insert struct {
 	baseSchemaProducer
@@ -58,6 +330,10 @@ type Insert struct {
 	Lists         [][]expression.Expression
 	SetList       []*expression.Assignment
 
+	// OnDuplicate is the assignment list of ON DUPLICATE KEY UPDATE, the
+	// expressions are evaluated on the duplicated row in the table.
+	OnDuplicate []*expression.Assignment
+
 	IsReplace bool
 
 	// NeedFillDefaultValue is true when expr in value list reference other column.
@@ -68,6 +344,33 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 }
 
+// LoadData represents a load data plan.
+type LoadData struct {
+	baseSchemaProducer
+
+	IsLocal     bool
+	OnDuplicate ast.OnDuplicateKeyHandlingType
+	Path        string
+	Table       *ast.TableName
+	Columns     []*ast.ColumnName
+	FieldsInfo  *ast.FieldsClause
+	LinesInfo   *ast.LinesClause
+	IgnoreLines uint64
+}
+
+// Update represents Update plan.
+type Update struct {
+	baseSchemaProducer
+
+	OrderedList []*expression.Assignment
+
+	AllAssignmentsAreConstant bool
+
+	SelectPlan PhysicalPlan
+
+	TblColPosInfos TblColPosInfoSlice
+}
+
 // Delete represents a delete plan.
 type Delete struct {
 	baseSchemaProducer
@@ -120,6 +423,7 @@ type Explain struct {
 
 	TargetPlan Plan
 	Format     string
+	Analyze    bool
 	ExecStmt   ast.StmtNode
 
 	Rows           [][]string
@@ -132,8 +436,10 @@ func (e *Explain) prepareSchema() error {
 	format := strings.ToLower(e.Format)
 
 	switch {
-	case format == ast.ExplainFormatROW:
+	case format == ast.ExplainFormatROW && !e.Analyze:
 		fieldNames = []string{"id", "count", "task", "operator info"}
+	case format == ast.ExplainFormatROW && e.Analyze:
+		fieldNames = []string{"id", "count", "task", "operator info", "execution info", "memory"}
 	case format == ast.ExplainFormatDOT:
 		fieldNames = []string{"dot contents"}
 	default:
@@ -205,6 +511,10 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, indent string, isLast
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
 		}
+	case *Update:
+		if x.SelectPlan != nil {
+			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
+		}
 	case *Delete:
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
@@ -289,9 +599,37 @@ func (e *Explain) prepareOperatorInfo(p Plan, taskType string, indent string, is
 	}
 	explainID := p.ExplainID().String()
 	row := []string{PrettyIdentifier(explainID, indent, isLastChild), count, taskType, operatorInfo}
+	if e.Analyze {
+		row = append(row, e.runtimeStatsInfo(explainID, taskType)...)
+	}
 	e.Rows = append(e.Rows, row)
 }
 
+// runtimeStatsInfo returns the execution info and the memory of the operator
+// collected while running the statement of EXPLAIN ANALYZE.
+func (e *Explain) runtimeStatsInfo(explainID, taskType string) []string {
+	runtimeStatsColl := e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl
+	// The operators which are not executed, e.g. the inner side of a join
+	// whose outer side is empty, have no runtime stats.
+	if taskType == "cop" {
+		if runtimeStatsColl.ExistsCopStats(explainID) {
+			return []string{runtimeStatsColl.GetCopStats(explainID).String(), "N/A"}
+		}
+		return []string{"time:0ns, loops:0, rows:0", "N/A"}
+	}
+	if !runtimeStatsColl.ExistsRootStats(explainID) {
+		return []string{"time:0ns, loops:0, rows:0", "N/A"}
+	}
+	rootStats := runtimeStatsColl.GetRootStats(explainID)
+	execInfo := rootStats.String()
+	if runtimeStatsColl.ExistsReaderStats(explainID) {
+		if readerInfo := runtimeStatsColl.GetReaderStats(explainID).String(); readerInfo != "" {
+			execInfo += ", " + readerInfo
+		}
+	}
+	return []string{execInfo, rootStats.MemoryString()}
+}
+
 func (e *Explain) prepareDotInfo(p PhysicalPlan) {
 	buffer := bytes.NewBufferString("")
 	fmt.Fprintf(buffer, "\ndigraph %s {\n", p.ExplainID())
@@ -64,6 +64,14 @@ func (s testErrorSuite) TestError(c *C) {
 		ErrCartesianProductUnsupported,
 		ErrStmtNotFound,
 		ErrAmbiguous,
+		ErrWindowInvalidWindowFuncUse,
+		ErrWindowInvalidWindowFuncAliasUse,
+		ErrWindowFrameStartIllegal,
+		ErrWindowFrameEndIllegal,
+		ErrWindowFrameIllegal,
+		ErrWindowRangeFrameOrderType,
+		ErrWindowRangeBoundNotConstant,
+		ErrWindowFunctionIgnoresFrame,
 	}
 	for _, err := range kvErrs {
 		code := err.ToSQLError().Code
@@ -14,11 +14,21 @@
 package core
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/set"
+	"go.uber.org/zap"
 )
 
 func (p *LogicalUnionScan) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
@@ -107,6 +117,12 @@ func (p *PhysicalMergeJoin) tryToGetChildReqProp(prop *property.PhysicalProperty
 }
 
 func (p *LogicalJoin) getMergeJoin(prop *property.PhysicalProperty) []PhysicalPlan {
+	// TODO: support null equal join keys for merge join.
+	for _, eqCond := range p.EqualConditions {
+		if eqCond.FuncName.L == ast.NullEQ {
+			return nil
+		}
+	}
 	joins := make([]PhysicalPlan, 0, len(p.leftProperties)+1)
 	// The leftProperties caches all the possible properties that are provided by its children.
 	for _, lhsChildProperty := range p.leftProperties {
@@ -181,6 +197,18 @@ func getNewJoinKeysByOffsets(oldJoinKeys []*expression.Column, offsets []int) []
 }
 
 func (p *LogicalJoin) getEnforcedMergeJoin(prop *property.PhysicalProperty) []PhysicalPlan {
+	mergeJoin := NewPhysicalMergeJoin(p, prop, p.schema, p.stats.ScaleByExpectCnt(prop.ExpectedCnt))
+	if mergeJoin == nil {
+		return nil
+	}
+	return []PhysicalPlan{mergeJoin}
+}
+
+// NewPhysicalMergeJoin creates a new PhysicalMergeJoin from LogicalJoin, whose
+// children are required to be sorted by the join keys. The join keys are
+// reordered to satisfy the required property, nil is returned if the property
+// can't be satisfied.
+func NewPhysicalMergeJoin(p *LogicalJoin, prop *property.PhysicalProperty, schema *expression.Schema, newStats *property.StatsInfo) *PhysicalMergeJoin {
 	// Check whether SMJ can satisfy the required property
 	offsets := make([]int, 0, len(p.LeftJoinKeys))
 	all, desc := prop.AllSameOrder()
@@ -230,11 +258,11 @@ func (p *LogicalJoin) getEnforcedMergeJoin(prop *property.PhysicalProperty) []Ph
 		RightJoinKeys:   rightKeys,
 		OtherConditions: p.OtherConditions,
 	}
-	enforcedPhysicalMergeJoin := PhysicalMergeJoin{basePhysicalJoin: baseJoin}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt))
-	enforcedPhysicalMergeJoin.SetSchema(p.schema)
+	enforcedPhysicalMergeJoin := PhysicalMergeJoin{basePhysicalJoin: baseJoin}.Init(p.ctx, newStats)
+	enforcedPhysicalMergeJoin.SetSchema(schema)
 	enforcedPhysicalMergeJoin.childrenReqProps = []*property.PhysicalProperty{lProp, rProp}
 	enforcedPhysicalMergeJoin.initCompareFuncs()
-	return []PhysicalPlan{enforcedPhysicalMergeJoin}
+	return enforcedPhysicalMergeJoin
 }
 
 func (p *PhysicalMergeJoin) initCompareFuncs() {
@@ -250,7 +278,7 @@ func (p *LogicalJoin) getHashJoins(prop *property.PhysicalProperty) []PhysicalPl
 	}
 	joins := make([]PhysicalPlan, 0, 2)
 	switch p.JoinType {
-	case LeftOuterJoin:
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin, LeftOuterJoin:
 		joins = append(joins, p.getHashJoin(prop, 1))
 	case RightOuterJoin:
 		joins = append(joins, p.getHashJoin(prop, 0))
@@ -274,6 +302,684 @@ func (p *LogicalJoin) getHashJoin(prop *property.PhysicalProperty, innerIdx int)
 	return hashJoin
 }
 
+// constructIndexJoin creates index join plans from the inner task built by
+// buildIndexJoinInner2TableScan or buildIndexJoinInner2IndexScan.
+func (p *LogicalJoin) constructIndexJoin(
+	prop *property.PhysicalProperty,
+	outerIdx int,
+	outerStats *property.StatsInfo,
+	innerTask task,
+	ranges []*ranger.Range,
+	keyOff2IdxOff []int,
+	path *util.AccessPath,
+) []PhysicalPlan {
+	var (
+		innerJoinKeys []*expression.Column
+		outerJoinKeys []*expression.Column
+	)
+	if outerIdx == 0 {
+		outerJoinKeys = p.LeftJoinKeys
+		innerJoinKeys = p.RightJoinKeys
+	} else {
+		innerJoinKeys = p.LeftJoinKeys
+		outerJoinKeys = p.RightJoinKeys
+	}
+	chReqProps := make([]*property.PhysicalProperty, 2)
+	chReqProps[outerIdx] = &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64, Items: prop.Items}
+	if prop.ExpectedCnt < p.stats.RowCount {
+		expCnt := p.stats.RowCount / outerStats.RowCount
+		chReqProps[outerIdx].ExpectedCnt = prop.ExpectedCnt / expCnt
+	}
+	newInnerKeys := make([]*expression.Column, 0, len(innerJoinKeys))
+	newOuterKeys := make([]*expression.Column, 0, len(outerJoinKeys))
+	newKeyOff := make([]int, 0, len(keyOff2IdxOff))
+	newOtherConds := make([]expression.Expression, len(p.OtherConditions), len(p.OtherConditions)+len(p.EqualConditions))
+	copy(newOtherConds, p.OtherConditions)
+	for keyOff, idxOff := range keyOff2IdxOff {
+		// The equal conditions which are not used to build the inner ranges
+		// should be evaluated as other conditions.
+		if idxOff < 0 {
+			newOtherConds = append(newOtherConds, p.EqualConditions[keyOff])
+			continue
+		}
+		newInnerKeys = append(newInnerKeys, innerJoinKeys[keyOff])
+		newOuterKeys = append(newOuterKeys, outerJoinKeys[keyOff])
+		newKeyOff = append(newKeyOff, idxOff)
+	}
+	baseJoin := basePhysicalJoin{
+		InnerChildIdx:   1 - outerIdx,
+		LeftConditions:  p.LeftConditions,
+		RightConditions: p.RightConditions,
+		OtherConditions: newOtherConds,
+		JoinType:        p.JoinType,
+		OuterJoinKeys:   newOuterKeys,
+		InnerJoinKeys:   newInnerKeys,
+		DefaultValues:   p.DefaultValues,
+	}
+	join := PhysicalIndexJoin{
+		basePhysicalJoin: baseJoin,
+		innerTask:        innerTask,
+		KeyOff2IdxOff:    newKeyOff,
+		Ranges:           ranges,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	if path != nil {
+		join.IdxColLens = path.IdxColLens
+	}
+	join.SetSchema(p.schema)
+	return []PhysicalPlan{join}
+}
+
+// getIndexJoinByOuterIdx will generate index join by outerIndex. OuterIdx points out the outer child.
+// First of all, we'll check whether the inner child is DataSource.
+// Then, we will extract the join keys of p's equal conditions. Then check whether all of them are just the primary key
+// or match some part of on index. If so we will choose the best one and construct a index join.
+func (p *LogicalJoin) getIndexJoinByOuterIdx(prop *property.PhysicalProperty, outerIdx int) []PhysicalPlan {
+	outerChild, innerChild := p.children[outerIdx], p.children[1-outerIdx]
+	ds, isDataSource := innerChild.(*DataSource)
+	us, isUnionScan := innerChild.(*LogicalUnionScan)
+	if !isDataSource && !isUnionScan {
+		return nil
+	}
+	if isUnionScan {
+		ds, isDataSource = us.Children()[0].(*DataSource)
+		if !isDataSource {
+			return nil
+		}
+	}
+	return p.getIndexJoinByInnerDS(prop, outerIdx, outerChild.Schema(), outerChild.statsInfo(), ds, us, p.equalCondOutCnt)
+}
+
+// getIndexJoinByInnerDS generates index join whose inner child is the
+// DataSource. The outer child is described by its schema and stats.
+func (p *LogicalJoin) getIndexJoinByInnerDS(prop *property.PhysicalProperty, outerIdx int, outerSchema *expression.Schema,
+	outerStats *property.StatsInfo, ds *DataSource, us *LogicalUnionScan, equalCondOutCnt float64) []PhysicalPlan {
+	all, _ := prop.AllSameOrder()
+	// If the order by columns are not all from outer child, index join cannot promise the order.
+	if !prop.AllColsFromSchema(outerSchema) || !all {
+		return nil
+	}
+	var (
+		innerJoinKeys []*expression.Column
+		outerJoinKeys []*expression.Column
+	)
+	if outerIdx == 0 {
+		outerJoinKeys = p.LeftJoinKeys
+		innerJoinKeys = p.RightJoinKeys
+	} else {
+		innerJoinKeys = p.LeftJoinKeys
+		outerJoinKeys = p.RightJoinKeys
+	}
+	var avgInnerRowCnt float64
+	if outerStats.RowCount > 0 {
+		avgInnerRowCnt = equalCondOutCnt / outerStats.RowCount
+	}
+	joins := p.buildIndexJoinInner2TableScan(prop, ds, innerJoinKeys, outerJoinKeys, outerIdx, outerStats, us, avgInnerRowCnt)
+	if joins != nil {
+		return joins
+	}
+	return p.buildIndexJoinInner2IndexScan(prop, ds, innerJoinKeys, outerJoinKeys, outerIdx, outerStats, us, avgInnerRowCnt)
+}
+
+// GetIndexJoin builds the PhysicalIndexJoin whose inner child is the
+// DataSource filtered by the innerConds, nil is returned if it can't be built.
+// It's used by the cascades planner, where the LogicalJoin doesn't record its
+// children and stats, so they are passed explicitly.
+func (p *LogicalJoin) GetIndexJoin(prop *property.PhysicalProperty, outerIdx int, outerSchema *expression.Schema, outerStats *property.StatsInfo,
+	ds *DataSource, innerConds []expression.Expression, schema *expression.Schema, stats *property.StatsInfo) *PhysicalIndexJoin {
+	innerDS := *ds
+	innerDS.pushedDownConds = innerConds
+	innerDS.initStats()
+	innerDS.stats = innerDS.deriveStatsByFilter(innerConds, nil)
+	join := *p
+	join.schema = schema
+	join.stats = stats
+	helper := &fullJoinRowCountHelper{
+		cartesian:     len(p.EqualConditions) == 0,
+		leftProfile:   outerStats,
+		rightProfile:  innerDS.stats,
+		leftJoinKeys:  p.LeftJoinKeys,
+		rightJoinKeys: p.RightJoinKeys,
+		leftSchema:    outerSchema,
+		rightSchema:   innerDS.schema,
+	}
+	if outerIdx == 1 {
+		helper.leftProfile, helper.rightProfile = helper.rightProfile, helper.leftProfile
+		helper.leftSchema, helper.rightSchema = helper.rightSchema, helper.leftSchema
+	}
+	joins := join.getIndexJoinByInnerDS(prop, outerIdx, outerSchema, outerStats, &innerDS, nil, helper.estimate())
+	if len(joins) == 0 {
+		return nil
+	}
+	return joins[0].(*PhysicalIndexJoin)
+}
+
+// buildIndexJoinInner2TableScan builds a TableScan as the inner child for an
+// IndexJoin if possible.
+// If the inner side of a index join is a TableScan, only one tuple will be
+// fetched from the inner side for every tuple from the outer side. This will be
+// promised to be no worse than building IndexScan as the inner child.
+func (p *LogicalJoin) buildIndexJoinInner2TableScan(
+	prop *property.PhysicalProperty, ds *DataSource, innerJoinKeys, outerJoinKeys []*expression.Column,
+	outerIdx int, outerStats *property.StatsInfo, us *LogicalUnionScan, avgInnerRowCnt float64) []PhysicalPlan {
+	// The table path may be pruned by the index hints.
+	var tblPath *util.AccessPath
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath {
+			tblPath = path
+			break
+		}
+	}
+	if tblPath == nil {
+		return nil
+	}
+	pkCol := ds.getPKIsHandleCol()
+	if pkCol == nil {
+		return nil
+	}
+	keyOff2IdxOff := make([]int, len(innerJoinKeys))
+	pkMatched := false
+	for i, key := range innerJoinKeys {
+		if !key.Equal(nil, pkCol) {
+			keyOff2IdxOff[i] = -1
+			continue
+		}
+		pkMatched = true
+		keyOff2IdxOff[i] = 0
+	}
+	if !pkMatched {
+		return nil
+	}
+	innerTask := p.constructInnerTableScanTask(ds, pkCol, outerJoinKeys, us, avgInnerRowCnt)
+	return p.constructIndexJoin(prop, outerIdx, outerStats, innerTask, nil, keyOff2IdxOff, nil)
+}
+
+func (p *LogicalJoin) buildIndexJoinInner2IndexScan(
+	prop *property.PhysicalProperty, ds *DataSource, innerJoinKeys, outerJoinKeys []*expression.Column,
+	outerIdx int, outerStats *property.StatsInfo, us *LogicalUnionScan, avgInnerRowCnt float64) []PhysicalPlan {
+	helper := &indexJoinBuildHelper{join: p}
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath {
+			continue
+		}
+		emptyRange, err := helper.analyzeLookUpFilters(path, ds, innerJoinKeys)
+		if emptyRange {
+			return nil
+		}
+		if err != nil {
+			logutil.BgLogger().Warn("build index join failed", zap.Error(err))
+		}
+	}
+	if helper.chosenPath == nil {
+		return nil
+	}
+	keyOff2IdxOff := make([]int, len(innerJoinKeys))
+	for i := range keyOff2IdxOff {
+		keyOff2IdxOff[i] = -1
+	}
+	for idxOff, keyOff := range helper.idxOff2KeyOff {
+		if keyOff != -1 {
+			keyOff2IdxOff[keyOff] = idxOff
+		}
+	}
+	rangeInfo := helper.buildRangeDecidedByInformation(helper.chosenPath.IdxCols, outerJoinKeys)
+	innerTask := p.constructInnerIndexScanTask(ds, helper.chosenPath, helper.chosenRemained, us, rangeInfo, avgInnerRowCnt)
+	return p.constructIndexJoin(prop, outerIdx, outerStats, innerTask, helper.chosenRanges, keyOff2IdxOff, helper.chosenPath)
+}
+
+// constructInnerTableScanTask is specially used to construct the inner plan for PhysicalIndexJoin.
+func (p *LogicalJoin) constructInnerTableScanTask(
+	ds *DataSource,
+	pk *expression.Column,
+	outerJoinKeys []*expression.Column,
+	us *LogicalUnionScan,
+	rowCount float64,
+) task {
+	ranges := ranger.FullIntRange(mysql.HasUnsignedFlag(pk.RetType.Flag))
+	ts := PhysicalTableScan{
+		Table:           ds.tableInfo,
+		Columns:         ds.Columns,
+		TableAsName:     ds.TableAsName,
+		DBName:          ds.DBName,
+		filterCondition: ds.pushedDownConds,
+		Ranges:          ranges,
+		rangeDecidedBy:  outerJoinKeys,
+	}.Init(ds.ctx)
+	ts.SetSchema(ds.schema.Clone())
+	if rowCount <= 0 {
+		rowCount = float64(1)
+	}
+	selectivity := float64(1)
+	countAfterAccess := rowCount
+	if len(ts.filterCondition) > 0 {
+		var err error
+		selectivity, err = ds.tableStats.HistColl.Selectivity(ds.ctx, ts.filterCondition, ds.possibleAccessPaths)
+		if err != nil || selectivity <= 0 {
+			logutil.BgLogger().Debug("unexpected selectivity, use selection factor", zap.Float64("selectivity", selectivity), zap.String("table", ds.tableInfo.Name.L))
+			selectivity = selectionFactor
+		}
+		// rowCount is computed from result row count of join, which has already accounted the filters on DataSource,
+		// i.e, rowCount equals to `countAfterAccess * selectivity`.
+		countAfterAccess = rowCount / selectivity
+	}
+	ts.stats = &property.StatsInfo{
+		// TableScan as inner child of IndexJoin can return at most 1 tuple for each outer row.
+		RowCount:     math.Min(1.0, countAfterAccess),
+		StatsVersion: ds.stats.StatsVersion,
+		// Cardinality would not be used in cost computation of IndexJoin, set leave it as default nil.
+	}
+	rowSize := ds.TblColHists.GetTableAvgRowSize(ds.TblCols)
+	sessVars := ds.ctx.GetSessionVars()
+	copTask := &copTask{
+		tablePlan:         ts,
+		indexPlanFinished: true,
+		cst:               sessVars.ScanFactor * rowSize * ts.stats.RowCount,
+		tblColHists:       ds.TblColHists,
+	}
+	selStats := ts.stats.Scale(selectivity)
+	ts.addPushedDownSelection(copTask, selStats)
+	t := finishCopTask(ds.ctx, copTask).(*rootTask)
+	reader := t.p
+	t.p = p.constructInnerUnionScan(us, reader)
+	return t
+}
+
+func (p *LogicalJoin) constructInnerUnionScan(us *LogicalUnionScan, reader PhysicalPlan) PhysicalPlan {
+	if us == nil {
+		return reader
+	}
+	// Use `reader.stats` instead of `us.stats` because it should be more accurate. No need to specify
+	// childrenReqProps now since we have got reader already.
+	physicalUnionScan := PhysicalUnionScan{
+		Conditions: us.conditions,
+		HandleCol:  us.handleCol,
+	}.Init(us.ctx, reader.statsInfo(), nil)
+	physicalUnionScan.SetChildren(reader)
+	return physicalUnionScan
+}
+
+// constructInnerIndexScanTask is specially used to construct the inner plan for PhysicalIndexJoin.
+func (p *LogicalJoin) constructInnerIndexScanTask(
+	ds *DataSource,
+	path *util.AccessPath,
+	filterConds []expression.Expression,
+	us *LogicalUnionScan,
+	rangeInfo string,
+	rowCount float64,
+) task {
+	is := PhysicalIndexScan{
+		Table:            ds.tableInfo,
+		TableAsName:      ds.TableAsName,
+		DBName:           ds.DBName,
+		Columns:          ds.Columns,
+		Index:            path.Index,
+		IdxCols:          path.IdxCols,
+		IdxColLens:       path.IdxColLens,
+		dataSourceSchema: ds.schema,
+		Ranges:           ranger.FullRange(),
+		rangeInfo:        rangeInfo,
+	}.Init(ds.ctx)
+	cop := &copTask{
+		indexPlan:   is,
+		tblColHists: ds.TblColHists,
+		tblCols:     ds.TblCols,
+	}
+	if !isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, is.Table.PKIsHandle) {
+		// On this way, it's double read case.
+		ts := PhysicalTableScan{
+			Columns:     ds.Columns,
+			Table:       is.Table,
+			TableAsName: ds.TableAsName,
+		}.Init(ds.ctx)
+		ts.schema = is.dataSourceSchema.Clone()
+		cop.tablePlan = ts
+	}
+	is.initSchema(path.Index, path.FullIdxCols, cop.tablePlan != nil)
+	indexConds, tblConds := splitIndexFilterConditions(filterConds, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+	// Specially handle cases when input rowCount is 0, which can only happen in 2 scenarios:
+	// - estimated row count of outer plan is 0;
+	// - estimated row count of inner "DataSource + filters" is 0;
+	// if it is the first case, it does not matter what row count we set for inner task, since the cost of index join would
+	// always be 0 then;
+	// if it is the second case, HashJoin should always be cheaper than IndexJoin then, so we set row count of inner task
+	// to table size, to simply make it more expensive.
+	if rowCount <= 0 {
+		rowCount = ds.tableStats.RowCount
+	}
+	// Assume equal conditions used by index join and other conditions are independent.
+	if len(tblConds) > 0 {
+		selectivity, err := ds.tableStats.HistColl.Selectivity(ds.ctx, tblConds, ds.possibleAccessPaths)
+		if err != nil || selectivity <= 0 {
+			logutil.BgLogger().Debug("unexpected selectivity, use selection factor", zap.Float64("selectivity", selectivity), zap.String("table", ds.tableInfo.Name.L))
+			selectivity = selectionFactor
+		}
+		// rowCount is computed from result row count of join, which has already accounted the filters on DataSource,
+		// i.e, rowCount equals to `countAfterIndex * selectivity`.
+		cnt := rowCount / selectivity
+		rowCount = math.Min(cnt, ds.tableStats.RowCount)
+	}
+	tmpPath := &util.AccessPath{
+		IndexFilters:     indexConds,
+		TableFilters:     tblConds,
+		CountAfterIndex:  rowCount,
+		CountAfterAccess: rowCount,
+	}
+	if len(indexConds) > 0 {
+		selectivity, err := ds.tableStats.HistColl.Selectivity(ds.ctx, indexConds, ds.possibleAccessPaths)
+		if err != nil || selectivity <= 0 {
+			logutil.BgLogger().Debug("unexpected selectivity, use selection factor", zap.Float64("selectivity", selectivity), zap.String("table", ds.tableInfo.Name.L))
+			selectivity = selectionFactor
+		}
+		cnt := tmpPath.CountAfterIndex / selectivity
+		tmpPath.CountAfterAccess = math.Min(cnt, ds.tableStats.RowCount)
+	}
+	is.stats = ds.tableStats.ScaleByExpectCnt(tmpPath.CountAfterAccess)
+	rowSize := is.indexScanRowSize(path.Index, ds, true)
+	sessVars := ds.ctx.GetSessionVars()
+	cop.cst = tmpPath.CountAfterAccess * rowSize * sessVars.ScanFactor
+	finalStats := ds.tableStats.ScaleByExpectCnt(rowCount)
+	is.addPushedDownSelection(cop, ds, tmpPath, finalStats)
+	t := finishCopTask(ds.ctx, cop).(*rootTask)
+	reader := t.p
+	t.p = p.constructInnerUnionScan(us, reader)
+	return t
+}
+
+type indexJoinBuildHelper struct {
+	join *LogicalJoin
+
+	maxUsedCols    int
+	chosenAccess   []expression.Expression
+	chosenRemained []expression.Expression
+	idxOff2KeyOff  []int
+	chosenRanges   []*ranger.Range
+	chosenPath     *util.AccessPath
+
+	curPossibleUsedKeys []*expression.Column
+	curNotUsedIndexCols []*expression.Column
+	curNotUsedColLens   []int
+	curIdxOff2KeyOff    []int
+}
+
+func (ijHelper *indexJoinBuildHelper) buildRangeDecidedByInformation(idxCols []*expression.Column, outerJoinKeys []*expression.Column) string {
+	buffer := bytes.NewBufferString("[")
+	isFirst := true
+	for idxOff, keyOff := range ijHelper.idxOff2KeyOff {
+		if keyOff == -1 {
+			continue
+		}
+		if !isFirst {
+			buffer.WriteString(" ")
+		} else {
+			isFirst = false
+		}
+		fmt.Fprintf(buffer, "eq(%v, %v)", idxCols[idxOff], outerJoinKeys[keyOff])
+	}
+	for _, access := range ijHelper.chosenAccess {
+		if !isFirst {
+			buffer.WriteString(" ")
+		} else {
+			isFirst = false
+		}
+		fmt.Fprintf(buffer, "%v", access)
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
+
+// findUsefulEqAndInFilters analyzes the pushedDownConds held by inner child and split them to three parts.
+// usefulEqOrInFilters is the continuous eq/in conditions on current unused index columns.
+// uselessFilters is the conditions which cannot be used for building ranges.
+// remainingRangeCandidates is the other conditions for future use.
+func (ijHelper *indexJoinBuildHelper) findUsefulEqAndInFilters(innerPlan *DataSource) (usefulEqOrInFilters, uselessFilters, remainingRangeCandidates []expression.Expression, emptyRange bool) {
+	// Extract the eq/in functions of possible join key.
+	// you can see the comment of ExtractEqAndInCondition to get the meaning of the second return value.
+	usefulEqOrInFilters, uselessFilters, remainingRangeCandidates, emptyRange = ranger.ExtractEqAndInCondition(
+		innerPlan.ctx, innerPlan.pushedDownConds,
+		ijHelper.curNotUsedIndexCols,
+		ijHelper.curNotUsedColLens,
+	)
+	return usefulEqOrInFilters, uselessFilters, remainingRangeCandidates, emptyRange
+}
+
+// removeUselessEqAndInFunc removes the useless eq/in conditions. It's designed for the following case:
+//
+//	t1 join t2 on t1.a=t2.a and t1.c=t2.c where t1.b > t2.b-10 and t1.b < t2.b+10 there's index(a, b, c) on t1.
+//	In this case the curIdxOff2KeyOff is [0 -1 1] and the notKeyEqAndIn is [].
+//	It's clearly that the column c cannot be used to access data. So we need to remove it and reset the IdxOff2KeyOff to
+//	[0 -1 -1].
+//	So that we can use t1.a=t2.a and t1.b > t2.b-10 and t1.b < t2.b+10 to build ranges then access data.
+func (ijHelper *indexJoinBuildHelper) removeUselessEqAndInFunc(
+	idxCols []*expression.Column,
+	notKeyEqAndIn []expression.Expression) (
+	usefulEqAndIn, uselessOnes []expression.Expression,
+) {
+	ijHelper.curPossibleUsedKeys = make([]*expression.Column, 0, len(idxCols))
+	for idxColPos, notKeyColPos := 0, 0; idxColPos < len(idxCols); idxColPos++ {
+		if ijHelper.curIdxOff2KeyOff[idxColPos] != -1 {
+			ijHelper.curPossibleUsedKeys = append(ijHelper.curPossibleUsedKeys, idxCols[idxColPos])
+			continue
+		}
+		if notKeyColPos < len(notKeyEqAndIn) && ijHelper.curNotUsedIndexCols[notKeyColPos].Equal(nil, idxCols[idxColPos]) {
+			notKeyColPos++
+			continue
+		}
+		for i := idxColPos + 1; i < len(idxCols); i++ {
+			ijHelper.curIdxOff2KeyOff[i] = -1
+		}
+		remained := make([]expression.Expression, 0, len(notKeyEqAndIn)-notKeyColPos)
+		remained = append(remained, notKeyEqAndIn[notKeyColPos:]...)
+		notKeyEqAndIn = notKeyEqAndIn[:notKeyColPos]
+		return notKeyEqAndIn, remained
+	}
+	return notKeyEqAndIn, nil
+}
+
+func (ijHelper *indexJoinBuildHelper) analyzeLookUpFilters(path *util.AccessPath, innerPlan *DataSource, innerJoinKeys []*expression.Column) (emptyRange bool, err error) {
+	if len(path.IdxCols) == 0 {
+		return false, nil
+	}
+	accesses := make([]expression.Expression, 0, len(path.IdxCols))
+	ijHelper.resetContextForIndex(innerJoinKeys, path.IdxCols, path.IdxColLens)
+	if expression.MaybeOverOptimized4PlanCache(ijHelper.join.ctx, innerPlan.pushedDownConds) {
+		// The ranges of the inner side are built from the parameters once for
+		// all, they are not rebuilt when the plan is reused.
+		ijHelper.join.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+	}
+	notKeyEqAndIn, remained, rangeFilterCandidates, emptyRange := ijHelper.findUsefulEqAndInFilters(innerPlan)
+	if emptyRange {
+		return true, nil
+	}
+	var remainedEqAndIn []expression.Expression
+	notKeyEqAndIn, remainedEqAndIn = ijHelper.removeUselessEqAndInFunc(path.IdxCols, notKeyEqAndIn)
+	matchedKeyCnt := len(ijHelper.curPossibleUsedKeys)
+	// If no join key is matched while join keys actually are not empty. We don't choose index join for now.
+	if matchedKeyCnt <= 0 && len(innerJoinKeys) > 0 {
+		return false, nil
+	}
+	accesses = append(accesses, notKeyEqAndIn...)
+	rangeFilterCandidates = append(rangeFilterCandidates, remainedEqAndIn...)
+	lastColPos := matchedKeyCnt + len(notKeyEqAndIn)
+	// There should be some equal conditions. But we don't need that there must be some join key in accesses here.
+	// A more strict check is applied later.
+	if lastColPos <= 0 {
+		return false, nil
+	}
+	// If all the index columns are covered by eq/in conditions, we don't need to consider other conditions anymore.
+	if lastColPos == len(path.IdxCols) {
+		remained = append(remained, rangeFilterCandidates...)
+		ranges, emptyRange, err := ijHelper.buildTemplateRange(matchedKeyCnt, notKeyEqAndIn, nil)
+		if err != nil {
+			return false, err
+		}
+		if emptyRange {
+			return true, nil
+		}
+		ijHelper.updateBestChoice(ranges, path, accesses, remained)
+		return false, nil
+	}
+	// Try to use the constant range filters on the next index column to narrow down the ranges.
+	lastPossibleCol := path.IdxCols[lastColPos]
+	colAccesses, colRemained := ranger.DetachCondsForColumn(ijHelper.join.ctx, rangeFilterCandidates, lastPossibleCol)
+	var nextColRange []*ranger.Range
+	if len(colAccesses) > 0 {
+		nextColRange, err = ranger.BuildColumnRange(colAccesses, ijHelper.join.ctx.GetSessionVars().StmtCtx, lastPossibleCol.RetType, path.IdxColLens[lastColPos])
+		if err != nil {
+			return false, err
+		}
+	}
+	ranges, emptyRange, err := ijHelper.buildTemplateRange(matchedKeyCnt, notKeyEqAndIn, nextColRange)
+	if err != nil {
+		return false, err
+	}
+	if emptyRange {
+		return true, nil
+	}
+	remained = append(remained, colRemained...)
+	if path.IdxColLens[lastColPos] != types.UnspecifiedLength {
+		remained = append(remained, colAccesses...)
+	}
+	accesses = append(accesses, colAccesses...)
+	ijHelper.updateBestChoice(ranges, path, accesses, remained)
+	return false, nil
+}
+
+func (ijHelper *indexJoinBuildHelper) updateBestChoice(ranges []*ranger.Range, path *util.AccessPath, accesses, remained []expression.Expression) {
+	// We choose the index by the number of used columns of the range, the much the better.
+	// Notice that there may be the cases like `t1.a=t2.a and b > 2 and b < 1`. So ranges can be nil though the conditions are valid.
+	// But obviously when the range is nil, we don't need index join.
+	if len(ranges) > 0 && len(ranges[0].LowVal) > ijHelper.maxUsedCols {
+		ijHelper.chosenPath = path
+		ijHelper.maxUsedCols = len(ranges[0].LowVal)
+		ijHelper.chosenRanges = ranges
+		ijHelper.chosenAccess = accesses
+		ijHelper.chosenRemained = remained
+		ijHelper.idxOff2KeyOff = ijHelper.curIdxOff2KeyOff
+	}
+}
+
+func (ijHelper *indexJoinBuildHelper) buildTemplateRange(matchedKeyCnt int, eqAndInFuncs []expression.Expression, nextColRange []*ranger.Range) (ranges []*ranger.Range, emptyRange bool, err error) {
+	pointLength := matchedKeyCnt + len(eqAndInFuncs)
+	if nextColRange != nil {
+		for _, colRan := range nextColRange {
+			// The range's exclude status is the same with last col's.
+			ran := &ranger.Range{
+				LowVal:      make([]types.Datum, pointLength, pointLength+1),
+				HighVal:     make([]types.Datum, pointLength, pointLength+1),
+				LowExclude:  colRan.LowExclude,
+				HighExclude: colRan.HighExclude,
+			}
+			ran.LowVal = append(ran.LowVal, colRan.LowVal[0])
+			ran.HighVal = append(ran.HighVal, colRan.HighVal[0])
+			ranges = append(ranges, ran)
+		}
+	} else {
+		ranges = append(ranges, &ranger.Range{
+			LowVal:  make([]types.Datum, pointLength),
+			HighVal: make([]types.Datum, pointLength),
+		})
+	}
+	sc := ijHelper.join.ctx.GetSessionVars().StmtCtx
+	for i, j := 0, 0; j < len(eqAndInFuncs); i++ {
+		// This position is occupied by join key.
+		if ijHelper.curIdxOff2KeyOff[i] != -1 {
+			continue
+		}
+		oneColumnRan, err := ranger.BuildColumnRange([]expression.Expression{eqAndInFuncs[j]}, sc, ijHelper.curNotUsedIndexCols[j].RetType, ijHelper.curNotUsedColLens[j])
+		if err != nil {
+			return nil, false, err
+		}
+		if len(oneColumnRan) == 0 {
+			return nil, true, nil
+		}
+		for _, ran := range ranges {
+			ran.LowVal[i] = oneColumnRan[0].LowVal[0]
+			ran.HighVal[i] = oneColumnRan[0].HighVal[0]
+		}
+		curRangeLen := len(ranges)
+		for ranIdx := 1; ranIdx < len(oneColumnRan); ranIdx++ {
+			newRanges := make([]*ranger.Range, 0, curRangeLen)
+			for oldRangeIdx := 0; oldRangeIdx < curRangeLen; oldRangeIdx++ {
+				newRange := ranges[oldRangeIdx].Clone()
+				newRange.LowVal[i] = oneColumnRan[ranIdx].LowVal[0]
+				newRange.HighVal[i] = oneColumnRan[ranIdx].HighVal[0]
+				newRanges = append(newRanges, newRange)
+			}
+			ranges = append(ranges, newRanges...)
+		}
+		j++
+	}
+	return ranges, false, nil
+}
+
+func (ijHelper *indexJoinBuildHelper) resetContextForIndex(innerKeys []*expression.Column, idxCols []*expression.Column, colLens []int) {
+	tmpSchema := expression.NewSchema(innerKeys...)
+	ijHelper.curIdxOff2KeyOff = make([]int, len(idxCols))
+	ijHelper.curNotUsedIndexCols = make([]*expression.Column, 0, len(idxCols))
+	ijHelper.curNotUsedColLens = make([]int, 0, len(idxCols))
+	for i, idxCol := range idxCols {
+		ijHelper.curIdxOff2KeyOff[i] = tmpSchema.ColumnIndex(idxCol)
+		// A prefix index column cannot be used as the lookup key, because the
+		// inner rows are matched with the full value of the join key.
+		if colLens[i] != types.UnspecifiedLength {
+			ijHelper.curIdxOff2KeyOff[i] = -1
+		}
+		if ijHelper.curIdxOff2KeyOff[i] >= 0 {
+			continue
+		}
+		ijHelper.curNotUsedIndexCols = append(ijHelper.curNotUsedIndexCols, idxCol)
+		ijHelper.curNotUsedColLens = append(ijHelper.curNotUsedColLens, colLens[i])
+	}
+}
+
+// getIndexJoins tries to build index joins for every possible outer child.
+func (p *LogicalJoin) getIndexJoins(prop *property.PhysicalProperty) []PhysicalPlan {
+	// The lookup keys never match NULL, so index join does not support null
+	// equal join keys.
+	for _, eqCond := range p.EqualConditions {
+		if eqCond.FuncName.L == ast.NullEQ {
+			return nil
+		}
+	}
+	switch p.JoinType {
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin, LeftOuterJoin:
+		return p.getIndexJoinByOuterIdx(prop, 0)
+	case RightOuterJoin:
+		return p.getIndexJoinByOuterIdx(prop, 1)
+	case InnerJoin:
+		joins := p.getIndexJoinByOuterIdx(prop, 0)
+		return append(joins, p.getIndexJoinByOuterIdx(prop, 1)...)
+	}
+	return nil
+}
+
+// tryToGetIndexJoin returns the candidate index joins. If the INL_JOIN hint is
+// specified and can be satisfied, forced is true and only the index joins
+// whose inner side is the hinted table are returned.
+func (p *LogicalJoin) tryToGetIndexJoin(prop *property.PhysicalProperty) (indexJoins []PhysicalPlan, forced bool) {
+	indexJoins = p.getIndexJoins(prop)
+	preferLeftAsInner := (p.preferJoinType & preferLeftAsIndexInner) > 0
+	preferRightAsInner := (p.preferJoinType & preferRightAsIndexInner) > 0
+	if !preferLeftAsInner && !preferRightAsInner {
+		return indexJoins, false
+	}
+	preferredJoins := make([]PhysicalPlan, 0, len(indexJoins))
+	for _, join := range indexJoins {
+		innerIdx := join.(*PhysicalIndexJoin).InnerChildIdx
+		if (innerIdx == 0 && preferLeftAsInner) || (innerIdx == 1 && preferRightAsInner) {
+			preferredJoins = append(preferredJoins, join)
+		}
+	}
+	if len(preferredJoins) > 0 {
+		return preferredJoins, true
+	}
+	// The index join can't be built for the required property, but the join
+	// may still be an index join when the property is enforced by a parent.
+	if prop.IsEmpty() {
+		p.ResetIndexJoinHint()
+	}
+	return indexJoins, false
+}
+
 // LogicalJoin can generates hash join, index join and sort merge join.
 // Firstly we check the hint, if hint is figured by user, we force to choose the corresponding physical plan.
 // If the hint is not matched, it will get other candidates.
@@ -286,6 +992,12 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []Ph
 	joins := make([]PhysicalPlan, 0, 3)
 	joins = append(joins, mergeJoins...)
 
+	indexJoins, forced := p.tryToGetIndexJoin(prop)
+	if forced {
+		return indexJoins
+	}
+	joins = append(joins, indexJoins...)
+
 	hashJoins := p.getHashJoins(prop)
 	if (p.preferJoinType & preferHashJoin) > 0 {
 		return hashJoins
@@ -319,7 +1031,8 @@ func (p *LogicalProjection) exhaustPhysicalPlans(prop *property.PhysicalProperty
 		return nil
 	}
 	proj := PhysicalProjection{
-		Exprs: p.Exprs,
+		Exprs:                p.Exprs,
+		AvoidColumnEvaluator: p.AvoidColumnEvaluator,
 	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), newProp)
 	proj.SetSchema(p.schema)
 	return []PhysicalPlan{proj}
@@ -382,6 +1095,49 @@ func (p *baseLogicalPlan) exhaustPhysicalPlans(_ *property.PhysicalProperty) []P
 	panic("baseLogicalPlan.exhaustPhysicalPlans() should never be called.")
 }
 
+// CanUseStreamAgg checks whether the aggregation can be executed by a stream
+// aggregation.
+func (la *LogicalAggregation) CanUseStreamAgg() bool {
+	for _, aggFunc := range la.AggFuncs {
+		if aggFunc.Mode == aggregation.FinalMode {
+			return false
+		}
+	}
+	// group by a + b is not interested in any order.
+	return len(la.GetGroupByCols()) == len(la.GroupByItems)
+}
+
+func (la *LogicalAggregation) getStreamAggs(prop *property.PhysicalProperty) []PhysicalPlan {
+	all, desc := prop.AllSameOrder()
+	if !all || !la.CanUseStreamAgg() {
+		return nil
+	}
+
+	streamAggs := make([]PhysicalPlan, 0, len(la.possibleProperties)*(len(wholeTaskTypes)-1))
+	childProp := &property.PhysicalProperty{
+		ExpectedCnt: math.Max(prop.ExpectedCnt*la.inputCount/la.stats.RowCount, prop.ExpectedCnt),
+	}
+
+	for _, possibleChildProperty := range la.possibleProperties {
+		childProp.Items = property.ItemsFromCols(possibleChildProperty[:len(la.groupByCols)], desc)
+		if !prop.IsPrefix(childProp) {
+			continue
+		}
+		// The table read of "CopDoubleReadTaskType" can't promises the sort
+		// property that the stream aggregation required, no need to consider.
+		for _, taskTp := range []property.TaskType{property.CopSingleReadTaskType, property.RootTaskType} {
+			copiedChildProperty := new(property.PhysicalProperty)
+			*copiedChildProperty = *childProp // It's ok to not deep copy the "cols" field.
+			copiedChildProperty.TaskTp = taskTp
+
+			agg := NewPhysicalStreamAgg(la, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), copiedChildProperty)
+			agg.SetSchema(la.schema.Clone())
+			streamAggs = append(streamAggs, agg)
+		}
+	}
+	return streamAggs
+}
+
 func (la *LogicalAggregation) getHashAggs(prop *property.PhysicalProperty) []PhysicalPlan {
 	if !prop.IsEmpty() {
 		return nil
@@ -396,8 +1152,49 @@ func (la *LogicalAggregation) getHashAggs(prop *property.PhysicalProperty) []Phy
 	return hashAggs
 }
 
+// getEnforcedStreamAggs generates the stream aggregation whose child is
+// enforced to be sorted by the group by columns. It's only considered when the
+// STREAM_AGG hint is specified, since the child may provide no order.
+func (la *LogicalAggregation) getEnforcedStreamAggs(prop *property.PhysicalProperty) []PhysicalPlan {
+	all, desc := prop.AllSameOrder()
+	if !all || !la.CanUseStreamAgg() {
+		return nil
+	}
+	childProp := &property.PhysicalProperty{
+		TaskTp:      property.RootTaskType,
+		ExpectedCnt: math.Max(prop.ExpectedCnt*la.inputCount/la.stats.RowCount, prop.ExpectedCnt),
+		Items:       property.ItemsFromCols(la.groupByCols, desc),
+		Enforced:    len(la.groupByCols) > 0,
+	}
+	if !prop.IsPrefix(childProp) {
+		return nil
+	}
+	agg := NewPhysicalStreamAgg(la, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	agg.SetSchema(la.schema.Clone())
+	return []PhysicalPlan{agg}
+}
+
 func (la *LogicalAggregation) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
-	return la.getHashAggs(prop)
+	preferHash, preferStream := la.ResetHintIfConflicted()
+	if preferHash {
+		// The hash aggregation can't provide any order, the required order
+		// is enforced by the parent.
+		return la.getHashAggs(prop)
+	}
+	if preferStream {
+		if la.CanUseStreamAgg() {
+			streamAggs := la.getStreamAggs(prop)
+			return append(streamAggs, la.getEnforcedStreamAggs(prop)...)
+		}
+		errMsg := "Optimizer Hint STREAM_AGG is inapplicable"
+		warning := ErrInternal.GenWithStack(errMsg)
+		la.ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
+		la.preferAggType = 0
+	}
+	aggs := make([]PhysicalPlan, 0, len(la.possibleProperties)+1)
+	aggs = append(aggs, la.getHashAggs(prop)...)
+	aggs = append(aggs, la.getStreamAggs(prop)...)
+	return aggs
 }
 
 func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
@@ -408,6 +1205,62 @@ func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty)
 	return []PhysicalPlan{sel}
 }
 
+func (la *LogicalApply) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.AllColsFromSchema(la.children[0].Schema()) { // for convenient, we don't pass through any prop
+		return nil
+	}
+	join := la.getHashJoin(prop, 1)
+	apply := PhysicalApply{
+		PhysicalHashJoin: *join,
+		OuterSchema:      la.CorCols,
+	}.Init(la.ctx,
+		la.stats.ScaleByExpectCnt(prop.ExpectedCnt),
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: prop.Items},
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	apply.SetSchema(la.schema)
+	return []PhysicalPlan{apply}
+}
+
+func (p *LogicalMaxOneRow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	mor := PhysicalMaxOneRow{}.Init(p.ctx, p.stats, &property.PhysicalProperty{ExpectedCnt: 2})
+	return []PhysicalPlan{mor}
+}
+
+func (p *LogicalUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// TODO: UnionAll can not pass any order, but we can change it to sort merge to keep order.
+	if !prop.IsEmpty() {
+		return nil
+	}
+	chReqProps := make([]*property.PhysicalProperty, 0, len(p.children))
+	for range p.children {
+		chReqProps = append(chReqProps, &property.PhysicalProperty{ExpectedCnt: prop.ExpectedCnt})
+	}
+	ua := PhysicalUnionAll{}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	ua.SetSchema(p.Schema())
+	return []PhysicalPlan{ua}
+}
+
+func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	var byItems []property.Item
+	byItems = append(byItems, p.PartitionBy...)
+	byItems = append(byItems, p.OrderBy...)
+	childProperty := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: byItems, Enforced: true}
+	if !prop.IsPrefix(childProperty) {
+		return nil
+	}
+	window := PhysicalWindow{
+		WindowFuncDescs: p.WindowFuncDescs,
+		PartitionBy:     p.PartitionBy,
+		OrderBy:         p.OrderBy,
+		Frame:           p.Frame,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProperty)
+	window.SetSchema(p.Schema())
+	return []PhysicalPlan{window}
+}
+
 func (p *LogicalLimit) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
 	if !prop.IsEmpty() {
 		return nil
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/statistics"
 )
 
@@ -44,7 +45,9 @@ func (p *PhysicalIndexScan) explainInfo(normalized bool) string {
 			}
 		}
 	}
-	if len(p.Ranges) > 0 {
+	if len(p.rangeInfo) > 0 {
+		fmt.Fprintf(buffer, ", range: decided by %v", p.rangeInfo)
+	} else if len(p.Ranges) > 0 {
 		if normalized {
 			fmt.Fprint(buffer, ", range:[?,?]")
 		} else {
@@ -92,7 +95,9 @@ func (p *PhysicalTableScan) explainInfo(normalized bool) string {
 	if p.pkCol != nil {
 		fmt.Fprintf(buffer, ", pk col:%s", p.pkCol.ExplainInfo())
 	}
-	if len(p.Ranges) > 0 {
+	if len(p.rangeDecidedBy) > 0 {
+		fmt.Fprintf(buffer, ", range: decided by %v", p.rangeDecidedBy)
+	} else if len(p.Ranges) > 0 {
 		if normalized {
 			fmt.Fprint(buffer, ", range:[?,?]")
 		} else {
@@ -212,6 +217,51 @@ func (p *basePhysicalAgg) ExplainNormalizedInfo() string {
 	return p.explainInfo(true)
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalIndexJoin) ExplainInfo() string {
+	return p.explainInfo(false)
+}
+
+func (p *PhysicalIndexJoin) explainInfo(normalized bool) string {
+	sortedExplainExpressionList := expression.SortedExplainExpressionList
+	if normalized {
+		sortedExplainExpressionList = expression.SortedExplainNormalizedExpressionList
+	}
+
+	buffer := bytes.NewBufferString(p.JoinType.String())
+	if normalized {
+		fmt.Fprintf(buffer, ", inner:%s", p.Children()[p.InnerChildIdx].TP())
+	} else {
+		fmt.Fprintf(buffer, ", inner:%s", p.Children()[p.InnerChildIdx].ExplainID())
+	}
+	if len(p.OuterJoinKeys) > 0 {
+		fmt.Fprintf(buffer, ", outer key:%s",
+			expression.ExplainColumnList(p.OuterJoinKeys))
+	}
+	if len(p.InnerJoinKeys) > 0 {
+		fmt.Fprintf(buffer, ", inner key:%s",
+			expression.ExplainColumnList(p.InnerJoinKeys))
+	}
+	if len(p.LeftConditions) > 0 {
+		fmt.Fprintf(buffer, ", left cond:%s",
+			sortedExplainExpressionList(p.LeftConditions))
+	}
+	if len(p.RightConditions) > 0 {
+		fmt.Fprintf(buffer, ", right cond:%s",
+			sortedExplainExpressionList(p.RightConditions))
+	}
+	if len(p.OtherConditions) > 0 {
+		fmt.Fprintf(buffer, ", other cond:%s",
+			sortedExplainExpressionList(p.OtherConditions))
+	}
+	return buffer.String()
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalIndexJoin) ExplainNormalizedInfo() string {
+	return p.explainInfo(true)
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalHashJoin) ExplainInfo() string {
 	return p.explainInfo(false)
@@ -311,6 +361,87 @@ func (p *PhysicalTopN) ExplainInfo() string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalWindow) ExplainInfo() string {
+	buffer := bytes.NewBufferString("")
+	formatWindowFuncDescs(buffer, p.WindowFuncDescs, p.schema)
+	buffer.WriteString(" over(")
+	isFirst := true
+	if len(p.PartitionBy) > 0 {
+		buffer.WriteString("partition by ")
+		for i, item := range p.PartitionBy {
+			buffer.WriteString(item.Col.ExplainInfo())
+			if i+1 < len(p.PartitionBy) {
+				buffer.WriteString(", ")
+			}
+		}
+		isFirst = false
+	}
+	if len(p.OrderBy) > 0 {
+		if !isFirst {
+			buffer.WriteString(" ")
+		}
+		buffer.WriteString("order by ")
+		for i, item := range p.OrderBy {
+			buffer.WriteString(item.Col.ExplainInfo())
+			if item.Desc {
+				buffer.WriteString(" desc")
+			}
+			if i+1 < len(p.OrderBy) {
+				buffer.WriteString(", ")
+			}
+		}
+		isFirst = false
+	}
+	if p.Frame != nil {
+		if !isFirst {
+			buffer.WriteString(" ")
+		}
+		if p.Frame.Type == ast.Rows {
+			buffer.WriteString("rows")
+		} else {
+			buffer.WriteString("range")
+		}
+		buffer.WriteString(" between ")
+		formatFrameBound(buffer, p.Frame.Start)
+		buffer.WriteString(" and ")
+		formatFrameBound(buffer, p.Frame.End)
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+func formatWindowFuncDescs(buffer *bytes.Buffer, descs []*aggregation.WindowFuncDesc, schema *expression.Schema) {
+	winFuncStartIdx := len(schema.Columns) - len(descs)
+	for i, desc := range descs {
+		if i != 0 {
+			buffer.WriteString(", ")
+		}
+		fmt.Fprintf(buffer, "%v->%v", desc, schema.Columns[winFuncStartIdx+i])
+	}
+}
+
+func formatFrameBound(buffer *bytes.Buffer, bound *FrameBound) {
+	if bound.Type == ast.CurrentRow {
+		buffer.WriteString("current row")
+		return
+	}
+	if bound.UnBounded {
+		buffer.WriteString("unbounded")
+	} else if len(bound.CalcFuncs) > 0 {
+		// The range frame bound is calculated by `plus(col, num)` or `minus(col, num)`.
+		sf := bound.CalcFuncs[0].(*expression.ScalarFunction)
+		buffer.WriteString(sf.GetArgs()[1].ExplainInfo())
+	} else {
+		fmt.Fprintf(buffer, "%d", bound.Num)
+	}
+	if bound.Type == ast.Preceding {
+		buffer.WriteString(" preceding")
+	} else {
+		buffer.WriteString(" following")
+	}
+}
+
 // ExplainInfo implements Plan interface.
 func (p *LogicalJoin) ExplainInfo() string {
 	buffer := bytes.NewBufferString(p.JoinType.String())
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
@@ -30,8 +31,12 @@ import (
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stringutil"
 )
 
+// EvalSubqueryFirstRow evaluates incorrelated subqueries once, and get first row.
+var EvalSubqueryFirstRow func(ctx context.Context, p PhysicalPlan, is infoschema.InfoSchema, sctx sessionctx.Context) (row []types.Datum, err error)
+
 // evalAstExpr evaluates ast expression directly.
 func evalAstExpr(sctx sessionctx.Context, expr ast.ExprNode) (types.Datum, error) {
 	if val, ok := expr.(*driver.ValueExpr); ok {
@@ -55,7 +60,7 @@ func evalAstExpr(sctx sessionctx.Context, expr ast.ExprNode) (types.Datum, error
 // asScalar means whether this expression must be treated as a scalar expression.
 // And this function returns a result expression, a new plan that may have apply or semi-join.
 func (b *PlanBuilder) rewrite(ctx context.Context, exprNode ast.ExprNode, p LogicalPlan, aggMapper map[*ast.AggregateFuncExpr]int, asScalar bool) (expression.Expression, LogicalPlan, error) {
-	expr, resultPlan, err := b.rewriteWithPreprocess(ctx, exprNode, p, aggMapper, asScalar, nil)
+	expr, resultPlan, err := b.rewriteWithPreprocess(ctx, exprNode, p, aggMapper, nil, asScalar, nil)
 	return expr, resultPlan, err
 }
 
@@ -66,6 +71,7 @@ func (b *PlanBuilder) rewriteWithPreprocess(
 	ctx context.Context,
 	exprNode ast.ExprNode,
 	p LogicalPlan, aggMapper map[*ast.AggregateFuncExpr]int,
+	windowMapper map[*ast.WindowFuncExpr]int,
 	asScalar bool,
 	preprocess func(ast.Node) ast.Node,
 ) (expression.Expression, LogicalPlan, error) {
@@ -82,6 +88,7 @@ func (b *PlanBuilder) rewriteWithPreprocess(
 	}
 
 	rewriter.aggrMap = aggMapper
+	rewriter.windowMap = windowMapper
 	rewriter.asScalar = asScalar
 	rewriter.preprocess = preprocess
 
@@ -89,6 +96,28 @@ func (b *PlanBuilder) rewriteWithPreprocess(
 	return expr, resultPlan, err
 }
 
+// rewriteInsertOnDuplicateUpdate rewrites the expressions in the assignments
+// of ON DUPLICATE KEY UPDATE, the VALUES() functions in them refer to the
+// columns of the insert plan.
+func (b *PlanBuilder) rewriteInsertOnDuplicateUpdate(ctx context.Context, exprInOnDuplicate ast.ExprNode, mockPlan LogicalPlan, insertPlan *Insert) (expression.Expression, error) {
+	b.rewriterCounter++
+	defer func() { b.rewriterCounter-- }()
+
+	rewriter := b.getExpressionRewriter(ctx, mockPlan)
+	// The rewriter maybe is obtained from "b.rewriterPool", "rewriter.err" is
+	// not nil means certain previous procedure hasn't finished. Return error
+	// directly.
+	if rewriter.err != nil {
+		return nil, errors.Trace(rewriter.err)
+	}
+
+	rewriter.insertPlan = insertPlan
+	rewriter.asScalar = true
+
+	expr, _, err := b.rewriteExprNode(rewriter, exprInOnDuplicate, true)
+	return expr, err
+}
+
 func (b *PlanBuilder) getExpressionRewriter(ctx context.Context, p LogicalPlan) (rewriter *expressionRewriter) {
 	defer func() {
 		if p != nil {
@@ -107,6 +136,7 @@ func (b *PlanBuilder) getExpressionRewriter(ctx context.Context, p LogicalPlan)
 	rewriter.p = p
 	rewriter.asScalar = false
 	rewriter.aggrMap = nil
+	rewriter.windowMap = nil
 	rewriter.preprocess = nil
 	rewriter.insertPlan = nil
 	rewriter.ctxStack = rewriter.ctxStack[:0]
@@ -141,6 +171,7 @@ type expressionRewriter struct {
 	names      []*types.FieldName
 	err        error
 	aggrMap    map[*ast.AggregateFuncExpr]int
+	windowMap  map[*ast.WindowFuncExpr]int
 	b          *PlanBuilder
 	sctx       sessionctx.Context
 	ctx        context.Context
@@ -241,12 +272,30 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 		}
 		er.ctxStackAppend(er.schema.Columns[index], er.names[index])
 		return inNode, true
+	case *ast.WindowFuncExpr:
+		index, ok := -1, false
+		if er.windowMap != nil {
+			index, ok = er.windowMap[v]
+		}
+		if !ok {
+			er.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+			return inNode, true
+		}
+		er.ctxStackAppend(er.schema.Columns[index], er.names[index])
+		return inNode, true
 	case *ast.ColumnNameExpr:
 		if index, ok := er.b.colMapper[v]; ok {
 			er.ctxStackAppend(er.schema.Columns[index], er.names[index])
 			return inNode, true
 		}
+	case *ast.CompareSubqueryExpr:
+		return er.handleCompareSubquery(er.ctx, v)
+	case *ast.ExistsSubqueryExpr:
+		return er.handleExistSubquery(er.ctx, v)
 	case *ast.PatternInExpr:
+		if v.Sel != nil {
+			return er.handleInSubquery(er.ctx, v)
+		}
 		if len(v.List) != 1 {
 			break
 		}
@@ -255,12 +304,17 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 		x := v.List[0]
 		for {
 			switch y := x.(type) {
+			case *ast.SubqueryExpr:
+				v.Sel = y
+				return er.handleInSubquery(er.ctx, v)
 			case *ast.ParenthesesExpr:
 				x = y.Expr
 			default:
 				return inNode, false
 			}
 		}
+	case *ast.SubqueryExpr:
+		return er.handleScalarSubquery(er.ctx, v)
 	case *ast.ParenthesesExpr:
 	case *ast.ValuesExpr:
 		schema, names := er.schema, er.names
@@ -290,6 +344,458 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	return inNode, false
 }
 
+func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.SubqueryExpr) (LogicalPlan, error) {
+	if er.schema != nil {
+		outerSchema := er.schema.Clone()
+		er.b.outerSchemas = append(er.b.outerSchemas, outerSchema)
+		er.b.outerNames = append(er.b.outerNames, er.names)
+		defer func() {
+			er.b.outerSchemas = er.b.outerSchemas[0 : len(er.b.outerSchemas)-1]
+			er.b.outerNames = er.b.outerNames[0 : len(er.b.outerNames)-1]
+		}()
+	}
+
+	np, err := er.b.buildResultSetNode(ctx, subq.Query)
+	if err != nil {
+		return nil, err
+	}
+	// Pop the handle map generated by the subquery.
+	er.b.handleHelper.popMap()
+	return np, nil
+}
+
+func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.CompareSubqueryExpr) (ast.Node, bool) {
+	// `a = any(subq)` will be rewritten as `a in (subq)`, and `a != all(subq)`
+	// will be rewritten as `a not in (subq)`.
+	if (v.Op == opcode.EQ && !v.All) || (v.Op == opcode.NE && v.All) {
+		return er.handleInSubquery(ctx, &ast.PatternInExpr{Expr: v.L, Sel: v.R, Not: v.All})
+	}
+	asScalar := er.asScalar
+	er.asScalar = true
+	v.L.Accept(er)
+	if er.err != nil {
+		return v, true
+	}
+	er.asScalar = asScalar
+	lexpr := er.ctxStack[len(er.ctxStack)-1]
+	subq, ok := v.R.(*ast.SubqueryExpr)
+	if !ok {
+		er.err = errors.Errorf("Unknown compare type %T.", v.R)
+		return v, true
+	}
+	np, err := er.buildSubquery(ctx, subq)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	if expression.GetRowLen(lexpr) != 1 || np.Schema().Len() != 1 {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(1)
+		return v, true
+	}
+	rexpr := np.Schema().Columns[0]
+	switch v.Op {
+	case opcode.EQ:
+		er.handleEQAll(lexpr, rexpr, np)
+	case opcode.NE:
+		er.handleNEAny(lexpr, rexpr, np)
+	case opcode.NullEQ:
+		// TODO: Support this in future.
+		er.err = errors.New("We don't support <=> all or <=> any now")
+	default:
+		// When < all or > any , the agg function should use min.
+		useMin := ((v.Op == opcode.LT || v.Op == opcode.LE) && v.All) || ((v.Op == opcode.GT || v.Op == opcode.GE) && !v.All)
+		er.handleOtherComparableSubq(lexpr, rexpr, np, useMin, v.Op.String(), v.All)
+	}
+	if er.err != nil {
+		return v, true
+	}
+	er.ctxStackPop(1)
+	if asScalar {
+		// The parent expression only use the last column in schema, which represents whether the condition is matched.
+		er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
+	}
+	return v, true
+}
+
+// handleOtherComparableSubq handles the queries like < any, < max, etc. For example, if the query is t.id < any (select s.id from s),
+// it will be rewrote to t.id < (select max(s.id) from s).
+func (er *expressionRewriter) handleOtherComparableSubq(lexpr, rexpr expression.Expression, np LogicalPlan, useMin bool, cmpFunc string, all bool) {
+	plan4Agg := LogicalAggregation{}.Init(er.sctx)
+	plan4Agg.SetChildren(np)
+
+	// Create a "max" or "min" aggregation.
+	funcName := ast.AggFuncMax
+	if useMin {
+		funcName = ast.AggFuncMin
+	}
+	funcMaxOrMin, err := aggregation.NewAggFuncDesc(er.sctx, funcName, []expression.Expression{rexpr})
+	if err != nil {
+		er.err = err
+		return
+	}
+
+	// Create a column and append it to the schema of that aggregation.
+	colMaxOrMin := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  funcMaxOrMin.RetTp,
+	}
+	schema := expression.NewSchema(colMaxOrMin)
+
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName)
+	plan4Agg.SetSchema(schema)
+	plan4Agg.AggFuncs = []*aggregation.AggFuncDesc{funcMaxOrMin}
+
+	cond := expression.NewFunctionInternal(er.sctx, cmpFunc, types.NewFieldType(mysql.TypeTiny), lexpr, colMaxOrMin)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, all)
+}
+
+// buildQuantifierPlan adds extra condition for any / all subquery.
+func (er *expressionRewriter) buildQuantifierPlan(plan4Agg *LogicalAggregation, cond, lexpr, rexpr expression.Expression, all bool) {
+	innerIsNull := expression.NewFunctionInternal(er.sctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), rexpr)
+	outerIsNull := expression.NewFunctionInternal(er.sctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), lexpr)
+
+	funcSum, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncSum, []expression.Expression{innerIsNull})
+	if err != nil {
+		er.err = err
+		return
+	}
+	colSum := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  funcSum.RetTp,
+	}
+	plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, funcSum)
+	plan4Agg.schema.Append(colSum)
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName)
+	innerHasNull := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), colSum, expression.Zero)
+
+	// Build `count(1)` aggregation to check if subquery is empty.
+	funcCount, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncCount, []expression.Expression{expression.One})
+	if err != nil {
+		er.err = err
+		return
+	}
+	colCount := &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  funcCount.RetTp,
+	}
+	plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, funcCount)
+	plan4Agg.schema.Append(colCount)
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName)
+
+	if all {
+		// All of the inner record set should not contain null value. So for t.id < all(select s.id from s), it
+		// should be rewrote to t.id < min(s.id) and if(sum(s.id is null) != 0, null, true).
+		innerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), innerHasNull, expression.Null, expression.One)
+		cond = expression.ComposeCNFCondition(er.sctx, cond, innerNullChecker)
+		// If the subquery is empty, it should always return true.
+		emptyChecker := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), colCount, expression.Zero)
+		// If outer key is null, and subquery is not empty, it should always return null, even when it is `null = all (1, 2)`.
+		outerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), outerIsNull, expression.Null, expression.Zero)
+		cond = expression.ComposeDNFCondition(er.sctx, cond, emptyChecker, outerNullChecker)
+	} else {
+		// For "any" expression, if the subquery has null and the cond returns false, the result should be NULL.
+		// Specifically, `t.id < any (select s.id from s)` would be rewrote to `t.id < max(s.id) or if(sum(s.id is null) != 0, null, false)`
+		innerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), innerHasNull, expression.Null, expression.Zero)
+		cond = expression.ComposeDNFCondition(er.sctx, cond, innerNullChecker)
+		// If the subquery is empty, it should always return false.
+		emptyChecker := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), colCount, expression.Zero)
+		// If outer key is null, and subquery is not empty, it should return null.
+		outerNullChecker := expression.NewFunctionInternal(er.sctx, ast.If, types.NewFieldType(mysql.TypeTiny), outerIsNull, expression.Null, expression.One)
+		cond = expression.ComposeCNFCondition(er.sctx, cond, emptyChecker, outerNullChecker)
+	}
+
+	if !er.asScalar {
+		// For Semi LogicalApply without aux column, the result is no matter false or null. So we can add it to join predicate.
+		er.p, er.err = er.b.buildSemiApply(er.p, plan4Agg, []expression.Expression{cond}, false, false)
+		return
+	}
+	// If we treat the result as a scalar value, we will add a projection with a extra column to output true, false or null.
+	outerSchemaLen := er.p.Schema().Len()
+	er.p = er.b.buildApplyWithJoinType(er.p, plan4Agg, InnerJoin)
+	joinSchema := er.p.Schema()
+	proj := LogicalProjection{
+		Exprs: expression.Column2Exprs(joinSchema.Clone().Columns[:outerSchemaLen]),
+	}.Init(er.sctx)
+	proj.names = make([]*types.FieldName, outerSchemaLen, outerSchemaLen+1)
+	copy(proj.names, er.p.OutputNames())
+	proj.SetSchema(expression.NewSchema(joinSchema.Clone().Columns[:outerSchemaLen]...))
+	proj.Exprs = append(proj.Exprs, cond)
+	proj.schema.Append(&expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  cond.GetType(),
+	})
+	proj.names = append(proj.names, types.EmptyName)
+	proj.SetChildren(er.p)
+	er.p = proj
+}
+
+// buildMaxMinAgg builds an aggregation which outputs both max(rexpr) and min(rexpr) over np.
+func (er *expressionRewriter) buildMaxMinAgg(rexpr expression.Expression, np LogicalPlan) (plan4Agg *LogicalAggregation, maxCol, minCol *expression.Column) {
+	maxFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMax, []expression.Expression{rexpr})
+	if err != nil {
+		er.err = err
+		return
+	}
+	minFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMin, []expression.Expression{rexpr})
+	if err != nil {
+		er.err = err
+		return
+	}
+	plan4Agg = LogicalAggregation{
+		AggFuncs: []*aggregation.AggFuncDesc{maxFunc, minFunc},
+	}.Init(er.sctx)
+	plan4Agg.SetChildren(np)
+	maxCol = &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  maxFunc.RetTp,
+	}
+	minCol = &expression.Column{
+		UniqueID: er.sctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  minFunc.RetTp,
+	}
+	plan4Agg.names = append(plan4Agg.names, types.EmptyName, types.EmptyName)
+	plan4Agg.SetSchema(expression.NewSchema(maxCol, minCol))
+	return plan4Agg, maxCol, minCol
+}
+
+// handleNEAny handles the case of != any. For example, if the query is t.id != any (select s.id from s), it will be rewrote to
+// t.id != max(s.id) or t.id != min(s.id) or [any checker]. If there are two different values in s.id,
+// there must exist a s.id that doesn't equal to t.id, and the max and min of s.id are different.
+func (er *expressionRewriter) handleNEAny(lexpr, rexpr expression.Expression, np LogicalPlan) {
+	plan4Agg, maxCol, minCol := er.buildMaxMinAgg(rexpr, np)
+	if er.err != nil {
+		return
+	}
+	neMax := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), lexpr, maxCol)
+	neMin := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), lexpr, minCol)
+	cond := expression.ComposeDNFCondition(er.sctx, neMax, neMin)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, false)
+}
+
+// handleEQAll handles the case of = all. For example, if the query is t.id = all (select s.id from s), it will be rewrote to
+// t.id = max(s.id) and t.id = min(s.id) and [all checker].
+func (er *expressionRewriter) handleEQAll(lexpr, rexpr expression.Expression, np LogicalPlan) {
+	plan4Agg, maxCol, minCol := er.buildMaxMinAgg(rexpr, np)
+	if er.err != nil {
+		return
+	}
+	eqMax := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lexpr, maxCol)
+	eqMin := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lexpr, minCol)
+	cond := expression.ComposeCNFCondition(er.sctx, eqMax, eqMin)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, true)
+}
+
+func (er *expressionRewriter) handleExistSubquery(ctx context.Context, v *ast.ExistsSubqueryExpr) (ast.Node, bool) {
+	subq, ok := v.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		er.err = errors.Errorf("Unknown exists type %T.", v.Sel)
+		return v, true
+	}
+	np, err := er.buildSubquery(ctx, subq)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	np = er.popExistsSubPlan(np)
+	if len(np.extractCorrelatedCols()) > 0 {
+		er.p, er.err = er.b.buildSemiApply(er.p, np, nil, er.asScalar, v.Not)
+		if er.err != nil || !er.asScalar {
+			return v, true
+		}
+		er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
+	} else {
+		physicalPlan, err := DoOptimize(ctx, er.b.optFlag, np)
+		if err != nil {
+			er.err = err
+			return v, true
+		}
+		row, err := EvalSubqueryFirstRow(ctx, physicalPlan, er.b.is, er.b.ctx)
+		if err != nil {
+			er.err = err
+			return v, true
+		}
+		if (row != nil && !v.Not) || (row == nil && v.Not) {
+			er.ctxStackAppend(expression.One.Clone(), types.EmptyName)
+		} else {
+			er.ctxStackAppend(expression.Zero.Clone(), types.EmptyName)
+		}
+	}
+	return v, true
+}
+
+// popExistsSubPlan will remove the useless plan in exist's child.
+// See comments inside the method for more details.
+func (er *expressionRewriter) popExistsSubPlan(p LogicalPlan) LogicalPlan {
+out:
+	for {
+		switch plan := p.(type) {
+		// This can be removed when in exists clause,
+		// e.g. exists(select count(*) from t order by a) is equal to exists t.
+		case *LogicalProjection, *LogicalSort:
+			p = p.Children()[0]
+		case *LogicalAggregation:
+			if len(plan.GroupByItems) == 0 {
+				p = LogicalTableDual{RowCount: 1}.Init(er.sctx)
+				break out
+			}
+			p = p.Children()[0]
+		default:
+			break out
+		}
+	}
+	return p
+}
+
+func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.PatternInExpr) (ast.Node, bool) {
+	asScalar := er.asScalar
+	er.asScalar = true
+	v.Expr.Accept(er)
+	if er.err != nil {
+		return v, true
+	}
+	er.asScalar = asScalar
+	lexpr := er.ctxStack[len(er.ctxStack)-1]
+	subq, ok := v.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		er.err = errors.Errorf("Unknown compare type %T.", v.Sel)
+		return v, true
+	}
+	np, err := er.buildSubquery(ctx, subq)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	lLen := expression.GetRowLen(lexpr)
+	if lLen != np.Schema().Len() {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(lLen)
+		return v, true
+	}
+	var rexpr expression.Expression
+	if np.Schema().Len() == 1 {
+		rexpr = np.Schema().Columns[0]
+	} else {
+		args := make([]expression.Expression, 0, np.Schema().Len())
+		for _, col := range np.Schema().Columns {
+			args = append(args, col)
+		}
+		rexpr, er.err = er.newFunction(ast.RowFunc, args[0].GetType(), args...)
+		if er.err != nil {
+			return v, true
+		}
+	}
+	// For AntiSemiJoin/LeftOuterSemiJoin/AntiLeftOuterSemiJoin, we cannot treat `in` expression as
+	// normal column equal condition, so we specially mark the inner operand here.
+	if v.Not || asScalar {
+		rexpr = expression.SetExprColumnInOperand(rexpr)
+	}
+	checkCondition, err := er.constructBinaryOpFunction(lexpr, rexpr, ast.EQ)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	// If it's not the form of `not in (SUBQUERY)`,
+	// and has no correlated column from the current level plan(if the correlated column is from upper level,
+	// we can treat it as constant, because the upper LogicalApply cannot be eliminated since current node is a join node),
+	// and don't need to append a scalar value, we can rewrite it to inner join.
+	if er.sctx.GetSessionVars().GetAllowInSubqToJoinAndAgg() && !v.Not && !asScalar && len(extractCorColumnsBySchema(np, er.p.Schema())) == 0 {
+		// We need to try to eliminate the agg and the projection produced by this operation.
+		er.b.optFlag |= flagEliminateAgg
+		er.b.optFlag |= flagEliminateProjection
+		er.b.optFlag |= flagJoinReOrder
+		// Build distinct for the inner query.
+		agg, err := er.b.buildDistinct(np, np.Schema().Len())
+		if err != nil {
+			er.err = err
+			return v, true
+		}
+		// Build inner join above the aggregation.
+		join := LogicalJoin{JoinType: InnerJoin}.Init(er.sctx)
+		join.SetChildren(er.p, agg)
+		join.SetSchema(expression.MergeSchema(er.p.Schema(), agg.schema))
+		join.names = make([]*types.FieldName, er.p.Schema().Len()+agg.Schema().Len())
+		copy(join.names, er.p.OutputNames())
+		// The columns of the subquery can never be referenced from outside.
+		for i := er.p.Schema().Len(); i < len(join.names); i++ {
+			join.names[i] = types.EmptyName
+		}
+		join.attachOnConds(expression.SplitCNFItems(checkCondition))
+		// Set join hint for this join.
+		if er.b.TableHints() != nil {
+			join.setPreferredJoinType(er.b.TableHints())
+		}
+		er.p = join
+	} else {
+		er.p, er.err = er.b.buildSemiApply(er.p, np, expression.SplitCNFItems(checkCondition), asScalar, v.Not)
+		if er.err != nil {
+			return v, true
+		}
+	}
+
+	er.ctxStackPop(1)
+	if asScalar {
+		col := er.p.Schema().Columns[er.p.Schema().Len()-1]
+		er.ctxStackAppend(col, er.p.OutputNames()[er.p.Schema().Len()-1])
+	}
+	return v, true
+}
+
+func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.SubqueryExpr) (ast.Node, bool) {
+	np, err := er.buildSubquery(ctx, v)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	np = er.b.buildMaxOneRow(np)
+	if len(np.extractCorrelatedCols()) > 0 {
+		er.p = er.b.buildApplyWithJoinType(er.p, np, LeftOuterJoin)
+		if np.Schema().Len() > 1 {
+			newCols := make([]expression.Expression, 0, np.Schema().Len())
+			for _, col := range np.Schema().Columns {
+				newCols = append(newCols, col)
+			}
+			expr, err1 := er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
+			if err1 != nil {
+				er.err = err1
+				return v, true
+			}
+			er.ctxStackAppend(expr, types.EmptyName)
+		} else {
+			er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
+		}
+		return v, true
+	}
+	physicalPlan, err := DoOptimize(ctx, er.b.optFlag, np)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	row, err := EvalSubqueryFirstRow(ctx, physicalPlan, er.b.is, er.b.ctx)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	if np.Schema().Len() > 1 {
+		newCols := make([]expression.Expression, 0, np.Schema().Len())
+		for i, data := range row {
+			newCols = append(newCols, &expression.Constant{
+				Value:   data,
+				RetType: np.Schema().Columns[i].GetType()})
+		}
+		expr, err1 := er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
+		if err1 != nil {
+			er.err = err1
+			return v, true
+		}
+		er.ctxStackAppend(expr, types.EmptyName)
+	} else {
+		er.ctxStackAppend(&expression.Constant{
+			Value:   row[0],
+			RetType: np.Schema().Columns[0].GetType(),
+		}, types.EmptyName)
+	}
+	return v, true
+}
+
 // Leave implements Visitor interface.
 func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok bool) {
 	if er.err != nil {
@@ -300,10 +806,18 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		inNode = er.preprocess(inNode)
 	}
 	switch v := inNode.(type) {
-	case *ast.AggregateFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ValuesExpr:
+	case *ast.AggregateFuncExpr, *ast.WindowFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ValuesExpr,
+		*ast.WhenClause, *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr:
 	case *driver.ValueExpr:
 		value := &expression.Constant{Value: v.Datum, RetType: &v.Type}
 		er.ctxStackAppend(value, types.EmptyName)
+	case *driver.ParamMarkerExpr:
+		var value expression.Expression
+		value, er.err = expression.ParamMarkerExpression(er.sctx, v)
+		if er.err != nil {
+			return retNode, false
+		}
+		er.ctxStackAppend(value, types.EmptyName)
 	case *ast.VariableExpr:
 		er.rewriteVariable(v)
 	case *ast.FuncCallExpr:
@@ -316,14 +830,30 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		er.binaryOpToExpression(v)
 	case *ast.BetweenExpr:
 		er.betweenToExpression(v)
+	case *ast.CaseExpr:
+		er.caseWhenToExpression(v)
 	case *ast.RowExpr:
 		er.rowToScalarFunc(v)
 	case *ast.PatternInExpr:
-		er.inToExpression(len(v.List), v.Not, &v.Type)
+		if v.Sel == nil {
+			er.inToExpression(len(v.List), v.Not, &v.Type)
+		}
+	case *ast.PatternLikeExpr:
+		er.patternLikeToExpression(v)
+	case *ast.PatternRegexpExpr:
+		er.regexpToScalarFunc(v)
 	case *ast.IsNullExpr:
 		er.isNullToExpression(v)
 	case *ast.DefaultExpr:
 		er.evalDefaultExpr(v)
+	case *ast.TimeUnitExpr:
+		unit := &expression.Constant{Value: types.NewStringDatum(v.Unit.String()), RetType: types.NewFieldType(mysql.TypeVarchar)}
+		er.ctxStackAppend(unit, types.EmptyName)
+	case *ast.TrimDirectionExpr:
+		er.ctxStackAppend(&expression.Constant{
+			Value:   types.NewIntDatum(int64(v.Direction)),
+			RetType: types.NewFieldType(mysql.TypeTiny),
+		}, types.EmptyName)
 	default:
 		er.err = errors.Errorf("UnknownType: %T", v)
 		return retNode, false
@@ -404,6 +934,8 @@ func (er *expressionRewriter) unaryOpToExpression(v *ast.UnaryOperationExpr) {
 		op = ast.UnaryMinus
 	case opcode.Not:
 		op = ast.UnaryNot
+	case opcode.BitNeg:
+		op = ast.BitNeg
 	default:
 		er.err = errors.Errorf("Unknown Unary Op %T", v.Op)
 		return
@@ -416,6 +948,60 @@ func (er *expressionRewriter) unaryOpToExpression(v *ast.UnaryOperationExpr) {
 	er.ctxNameStk[stkLen-1] = types.EmptyName
 }
 
+func (er *expressionRewriter) caseWhenToExpression(v *ast.CaseExpr) {
+	stkLen := len(er.ctxStack)
+	argsLen := 2 * len(v.WhenClauses)
+	if v.ElseClause != nil {
+		argsLen++
+	}
+	if v.Value != nil {
+		// The value is compared with every condition, so it is checked as well.
+		argsLen++
+	}
+	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[stkLen-argsLen:]...)
+	if er.err != nil {
+		return
+	}
+
+	// value                          -> ctxStack[stkLen-argsLen]
+	// when clause(condition, result) -> ctxStack[stkLen-argsLen+1:stkLen-1];
+	// else clause                    -> ctxStack[stkLen-1]
+	var args []expression.Expression
+	if v.Value != nil {
+		// args:  eq scalar func(args: value, condition1), result1,
+		//        eq scalar func(args: value, condition2), result2,
+		//        ...
+		//        else clause
+		value := er.ctxStack[stkLen-argsLen]
+		args = make([]expression.Expression, 0, argsLen-1)
+		for i := 0; i < len(v.WhenClauses); i++ {
+			cond := er.ctxStack[stkLen-argsLen+1+2*i]
+			arg, err := er.constructBinaryOpFunction(value, cond, ast.EQ)
+			if err != nil {
+				er.err = err
+				return
+			}
+			args = append(args, arg, er.ctxStack[stkLen-argsLen+2+2*i])
+		}
+		if v.ElseClause != nil {
+			args = append(args, er.ctxStack[stkLen-1])
+		}
+	} else {
+		// args:  condition1, result1,
+		//        condition2, result2,
+		//        ...
+		//        else clause
+		args = er.ctxStack[stkLen-argsLen:]
+	}
+	function, err := er.newFunction(ast.Case, &v.Type, args...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(argsLen)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 func (er *expressionRewriter) binaryOpToExpression(v *ast.BinaryOperationExpr) {
 	stkLen := len(er.ctxStack)
 	var function expression.Expression
@@ -469,6 +1055,71 @@ func (er *expressionRewriter) isNullToExpression(v *ast.IsNullExpr) {
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+func (er *expressionRewriter) patternLikeToExpression(v *ast.PatternLikeExpr) {
+	l := len(er.ctxStack)
+	if expression.GetRowLen(er.ctxStack[l-2]) != 1 || expression.GetRowLen(er.ctxStack[l-1]) != 1 {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(1)
+		return
+	}
+
+	var function expression.Expression
+	fieldType := &types.FieldType{}
+	isPatternExactMatch := false
+	// Treat predicate 'like' the same way as predicate '=' when it is an exact match,
+	// unless the strings are compared case-insensitively, which '=' doesn't do.
+	if patExpression, ok := er.ctxStack[l-1].(*expression.Constant); ok {
+		if expression.MaybeOverOptimized4PlanCache(er.sctx, []expression.Expression{patExpression}) {
+			// Whether the pattern is an exact match depends on the parameter.
+			er.sctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+		}
+		patString, isNull, err := patExpression.EvalString(nil, chunk.Row{})
+		if err != nil {
+			er.err = err
+			return
+		}
+		_, ci := expression.PatternMatchCollation(er.ctxStack[l-2], er.ctxStack[l-1])
+		if !isNull && !ci {
+			patValue, patTypes := stringutil.CompilePattern(patString, v.Escape)
+			if stringutil.IsExactMatch(patTypes) && er.ctxStack[l-2].GetType().EvalType() == types.ETString {
+				op := ast.EQ
+				if v.Not {
+					op = ast.NE
+				}
+				types.DefaultTypeForValue(string(patValue), fieldType)
+				function, er.err = er.constructBinaryOpFunction(er.ctxStack[l-2],
+					&expression.Constant{Value: types.NewStringDatum(string(patValue)), RetType: fieldType},
+					op)
+				isPatternExactMatch = true
+			}
+		}
+	}
+	if !isPatternExactMatch {
+		types.DefaultTypeForValue(int(v.Escape), fieldType)
+		function = er.notToExpression(v.Not, ast.Like, &v.Type,
+			er.ctxStack[l-2], er.ctxStack[l-1], &expression.Constant{Value: types.NewIntDatum(int64(v.Escape)), RetType: fieldType})
+	}
+	if er.err != nil {
+		return
+	}
+
+	er.ctxStackPop(2)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
+func (er *expressionRewriter) regexpToScalarFunc(v *ast.PatternRegexpExpr) {
+	l := len(er.ctxStack)
+	if expression.GetRowLen(er.ctxStack[l-2]) != 1 || expression.GetRowLen(er.ctxStack[l-1]) != 1 {
+		er.err = expression.ErrOperandColumns.GenWithStackByArgs(1)
+		return
+	}
+	function := er.notToExpression(v.Not, ast.Regexp, &v.Type, er.ctxStack[l-2], er.ctxStack[l-1])
+	if er.err != nil {
+		return
+	}
+	er.ctxStackPop(2)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 // inToExpression converts in expression to a scalar function. The argument lLen means the length of in list.
 // The argument not means if the expression is not in. The tp stands for the expression type, which is always bool.
 // a in (b, c, d) will be rewritten as `(a = b) or (a = c) or (a = d)`.
@@ -599,6 +1250,36 @@ func (er *expressionRewriter) rewriteFuncCall(v *ast.FuncCallExpr) bool {
 		}
 
 		return false
+	case ast.Nullif:
+		if len(v.Args) != 2 {
+			er.err = expression.ErrIncorrectParameterCount.GenWithStackByArgs(v.FnName.O)
+			return true
+		}
+		stackLen := len(er.ctxStack)
+		param1 := er.ctxStack[stackLen-2]
+		param2 := er.ctxStack[stackLen-1]
+		// param1 = param2
+		funcCompare, err := er.constructBinaryOpFunction(param1, param2, ast.EQ)
+		if err != nil {
+			er.err = err
+			return true
+		}
+		// NULL
+		nullTp := types.NewFieldType(mysql.TypeNull)
+		nullTp.Flen, nullTp.Decimal = mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeNull)
+		paramNull := &expression.Constant{
+			Value:   types.NewDatum(nil),
+			RetType: nullTp,
+		}
+		// if(param1 = param2, NULL, param1)
+		funcIf, err := er.newFunction(ast.If, &v.Type, funcCompare, paramNull, param1)
+		if err != nil {
+			er.err = err
+			return true
+		}
+		er.ctxStackPop(len(v.Args))
+		er.ctxStackAppend(funcIf, types.EmptyName)
+		return true
 	default:
 		return false
 	}
@@ -633,6 +1314,19 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
+	for i := len(er.b.outerSchemas) - 1; i >= 0; i-- {
+		outerSchema, outerName := er.b.outerSchemas[i], er.b.outerNames[i]
+		idx, err = expression.FindFieldName(outerName, v)
+		if idx >= 0 {
+			column := outerSchema.Columns[idx]
+			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
+			return
+		}
+		if err != nil {
+			er.err = ErrAmbiguous.GenWithStackByArgs(v.Name, clauseMsg[fieldList])
+			return
+		}
+	}
 	if er.b.curClause == globalOrderByClause {
 		er.b.curClause = orderByClause
 	}
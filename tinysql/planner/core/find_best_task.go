@@ -14,6 +14,7 @@
 package core
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/pingcap/tidb/expression"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/tracing"
 	"golang.org/x/tools/container/intsets"
 )
 
@@ -48,6 +50,22 @@ var wholeTaskTypes = [...]property.TaskType{property.CopSingleReadTaskType, prop
 
 var invalidTask = &rootTask{cst: math.MaxFloat64}
 
+// traceCandidate records the candidate task of the logical plan in the
+// optimizer trace, it returns nil if the optimizer trace is disabled.
+func traceCandidate(p LogicalPlan, prop *property.PhysicalProperty, t task) *tracing.CandidatePlanTrace {
+	tracer := p.SCtx().GetSessionVars().StmtCtx.OptimizeTracer
+	if tracer == nil || t.invalid() {
+		return nil
+	}
+	var plan string
+	if cop, ok := t.(*copTask); ok && cop.indexPlan != nil && cop.tablePlan != nil {
+		plan = fmt.Sprintf("IndexLookUp(%s, %s)", ToString(cop.indexPlan), ToString(cop.tablePlan))
+	} else {
+		plan = ToString(t.plan())
+	}
+	return tracer.AppendCandidatePlan(p.ExplainID().String(), prop.String(), plan, t.cost())
+}
+
 // GetPropByOrderByItems will check if this sort property can be pushed or not. In order to simplify the problem, we only
 // consider the case that all expression are columns.
 func GetPropByOrderByItems(items []*ByItems) (*property.PhysicalProperty, bool) {
@@ -93,6 +111,11 @@ func (p *LogicalShowDDLJobs) findBestTask(prop *property.PhysicalProperty) (task
 
 // findBestTask implements LogicalPlan interface.
 func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty) (bestTask task, err error) {
+	// If p is an inner plan in an IndexJoin, the IndexJoin will generate an inner plan by itself,
+	// and set inner child prop nil, so here we do nothing.
+	if prop == nil {
+		return nil, nil
+	}
 	// Look up the task with this prop in the task map.
 	// It's used to reduce double counting.
 	bestTask = p.getTask(prop)
@@ -125,6 +148,7 @@ func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty) (bestTas
 	}
 	physicalPlans := p.self.exhaustPhysicalPlans(prop)
 	prop.Items = oldPropCols
+	var bestCandidate *tracing.CandidatePlanTrace
 
 	for _, pp := range physicalPlans {
 		// find best child tasks firstly.
@@ -154,10 +178,15 @@ func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty) (bestTas
 		}
 
 		// get the most efficient one.
+		candidate := traceCandidate(p.self, prop, curTask)
 		if curTask.cost() < bestTask.cost() {
 			bestTask = curTask
+			bestCandidate = candidate
 		}
 	}
+	if bestCandidate != nil {
+		bestCandidate.Chosen = true
+	}
 
 	p.storeTask(prop, bestTask)
 	return bestTask, nil
@@ -180,6 +209,9 @@ func (p *LogicalMemTable) findBestTask(prop *property.PhysicalProperty) (t task,
 func (ds *DataSource) tryToGetDualTask() (task, error) {
 	for _, cond := range ds.pushedDownConds {
 		if _, ok := cond.(*expression.Constant); ok {
+			if expression.MaybeOverOptimized4PlanCache(ds.ctx, []expression.Expression{cond}) {
+				ds.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+			}
 			result, _, err := expression.EvalBool(ds.ctx, []expression.Expression{cond}, chunk.Row{})
 			if err != nil {
 				return nil, err
@@ -334,6 +366,11 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 // findBestTask implements the PhysicalPlan interface.
 // It will enumerate all the available indices and choose a plan with least cost.
 func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err error) {
+	// If ds is an inner plan in an IndexJoin, the IndexJoin will generate an inner plan by itself,
+	// and set inner child prop nil, so here we do nothing.
+	if prop == nil {
+		return nil, nil
+	}
 	t = ds.getTask(prop)
 	if t != nil {
 		return
@@ -374,13 +411,23 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 
 	t = invalidTask
 	candidates := ds.skylinePruning(prop)
+	var bestCandidate *tracing.CandidatePlanTrace
+	defer func() {
+		if bestCandidate != nil {
+			bestCandidate.Chosen = true
+		}
+	}()
 
 	for _, candidate := range candidates {
 		path := candidate.path
 		// if we already know the range of the scan is empty, just return a TableDual
 		if len(path.Ranges) == 0 {
+			if expression.MaybeOverOptimized4PlanCache(ds.ctx, ds.pushedDownConds) {
+				ds.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+			}
 			dual := PhysicalTableDual{}.Init(ds.ctx, ds.stats)
 			dual.SetSchema(ds.schema)
+			bestCandidate = nil
 			return &rootTask{
 				p: dual,
 			}, nil
@@ -390,8 +437,10 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 			if err != nil {
 				return nil, err
 			}
+			traced := traceCandidate(ds, prop, tblTask)
 			if tblTask.cost() < t.cost() {
 				t = tblTask
+				bestCandidate = traced
 			}
 			continue
 		}
@@ -399,8 +448,10 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 		if err != nil {
 			return nil, err
 		}
+		traced := traceCandidate(ds, prop, idxTask)
 		if idxTask.cost() < t.cost() {
 			t = idxTask
+			bestCandidate = traced
 		}
 	}
 
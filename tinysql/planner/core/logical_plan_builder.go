@@ -22,6 +22,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
@@ -31,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
@@ -46,8 +48,20 @@ const (
 	HintSMJ = "sm_join"
 	// TiDBHashJoin is hint enforce hash join.
 	TiDBHashJoin = "tidb_hj"
+	// HintMergeJoin is hint enforce merge join.
+	HintMergeJoin = "merge_join"
+	// TiDBIndexNestedLoopJoin is hint enforce index nested loop join.
+	TiDBIndexNestedLoopJoin = "tidb_inlj"
+	// HintINLJ is hint enforce index nested loop join.
+	HintINLJ = "inl_join"
 	// HintHJ is hint enforce hash join.
 	HintHJ = "hash_join"
+	// HintLeading is hint specifying the tables to be joined first and their order.
+	HintLeading = "leading"
+	// HintHashAgg is hint enforce hash aggregation.
+	HintHashAgg = "hash_agg"
+	// HintStreamAgg is hint enforce stream aggregation.
+	HintStreamAgg = "stream_agg"
 	// HintUseIndex is hint enforce using some indexes.
 	HintUseIndex = "use_index"
 	// HintIgnoreIndex is hint enforce ignoring some indexes.
@@ -130,9 +144,21 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 	plan4Agg.GroupByItems = gbyItems
 	plan4Agg.SetSchema(schema4Agg)
 	plan4Agg.collectGroupByColumns()
+	b.setPreferredAggType(plan4Agg)
 	return plan4Agg, aggIndexMap, nil
 }
 
+// setPreferredAggType sets the aggregation algorithm hints of the current
+// SELECT statement to the LogicalAggregation.
+func (b *PlanBuilder) setPreferredAggType(agg *LogicalAggregation) {
+	hintInfo := b.TableHints()
+	if hintInfo == nil || hintInfo.aggHints.preferAggType == 0 {
+		return
+	}
+	agg.preferAggType = hintInfo.aggHints.preferAggType
+	hintInfo.aggHints.matched = true
+}
+
 func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSetNode) (p LogicalPlan, err error) {
 	switch x := node.(type) {
 	case *ast.Join:
@@ -141,6 +167,8 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		switch v := x.Source.(type) {
 		case *ast.SelectStmt:
 			p, err = b.buildSelect(ctx, v)
+		case *ast.SetOprStmt:
+			p, err = b.buildSetOpr(ctx, v)
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 		default:
@@ -171,6 +199,8 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		return p, nil
 	case *ast.SelectStmt:
 		return b.buildSelect(ctx, x)
+	case *ast.SetOprStmt:
+		return b.buildSetOpr(ctx, x)
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.ResultSetNode(%T) for buildResultSetNode()", x)
 	}
@@ -182,7 +212,7 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 func (p *LogicalJoin) pushDownConstExpr(expr expression.Expression, leftCond []expression.Expression,
 	rightCond []expression.Expression, filterCond bool) ([]expression.Expression, []expression.Expression) {
 	switch p.JoinType {
-	case LeftOuterJoin:
+	case LeftOuterJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
 		if filterCond {
 			leftCond = append(leftCond, expr)
 			// Append the expr to right join condition instead of `rightCond`, to make it able to be
@@ -198,9 +228,14 @@ func (p *LogicalJoin) pushDownConstExpr(expr expression.Expression, leftCond []e
 		} else {
 			leftCond = append(leftCond, expr)
 		}
-	case InnerJoin:
+	case SemiJoin, InnerJoin:
 		leftCond = append(leftCond, expr)
 		rightCond = append(rightCond, expr)
+	case AntiSemiJoin:
+		if filterCond {
+			leftCond = append(leftCond, expr)
+		}
+		rightCond = append(rightCond, expr)
 	}
 	return leftCond, rightCond
 }
@@ -222,6 +257,15 @@ func (p *LogicalJoin) ExtractOnCondition(
 	deriveRight bool) (eqCond []*expression.ScalarFunction, leftCond []expression.Expression,
 	rightCond []expression.Expression, otherCond []expression.Expression) {
 	for _, expr := range conditions {
+		// For queries like `select a in (select a from s where s.b = t.b) from t`,
+		// if subquery is empty caused by `s.b = t.b`, the result should always be
+		// false even if t.a is null or s.a is null. To make this join "empty aware",
+		// we should differentiate `t.a = s.a` from other column equal conditions, so
+		// we put it into OtherConditions instead of EqualConditions of join.
+		if expression.IsEQCondFromIn(expr) {
+			otherCond = append(otherCond, expr)
+			continue
+		}
 		binop, ok := expr.(*expression.ScalarFunction)
 		if ok && len(binop.GetArgs()) == 2 {
 			ctx := binop.GetCtx()
@@ -248,8 +292,11 @@ func (p *LogicalJoin) ExtractOnCondition(
 							rightCond = append(rightCond, notNullExpr)
 						}
 					}
-					if binop.FuncName.L == ast.EQ {
-						cond := expression.NewFunctionInternal(ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), arg0, arg1)
+					// A null-eq condition is only used as a join key when both sides have the same type,
+					// otherwise the two sides may be hashed differently.
+					isNullEQKey := binop.FuncName.L == ast.NullEQ && leftCol.RetType.Tp == rightCol.RetType.Tp
+					if binop.FuncName.L == ast.EQ || isNullEQKey {
+						cond := expression.NewFunctionInternal(ctx, binop.FuncName.L, types.NewFieldType(mysql.TypeTiny), arg0, arg1)
 						eqCond = append(eqCond, cond.(*expression.ScalarFunction))
 						continue
 					}
@@ -327,6 +374,13 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 	if hintInfo.ifPreferHashJoin(lhsAlias, rhsAlias) {
 		p.preferJoinType |= preferHashJoin
 	}
+	// The table specified by the INL_JOIN hint is the inner side.
+	if hintInfo.ifPreferINLJ(lhsAlias) {
+		p.preferJoinType |= preferLeftAsIndexInner
+	}
+	if hintInfo.ifPreferINLJ(rhsAlias) {
+		p.preferJoinType |= preferRightAsIndexInner
+	}
 
 	// set hintInfo for further usage if this hint info can be used.
 	if p.preferJoinType != 0 {
@@ -400,6 +454,13 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 
 	// Set preferred join algorithm if some join hints is specified by user.
 	joinPlan.setPreferredJoinType(b.TableHints())
+	// The join order of the tables in the FROM clause is specified by the
+	// LEADING hint, which is applied by the join reorder rule.
+	if hintInfo := b.TableHints(); hintInfo != nil && hintInfo.leadingHint != nil {
+		hintInfo.matchTableName([]*hintTableInfo{extractTableAlias(leftPlan), extractTableAlias(rightPlan)}, hintInfo.leadingHint.tables)
+		joinPlan.leadingHint = hintInfo.leadingHint
+		b.optFlag = b.optFlag | flagJoinReOrder
+	}
 
 	if joinNode.On != nil {
 		b.curClause = onClause
@@ -442,6 +503,9 @@ func (b *PlanBuilder) buildSelection(ctx context.Context, p LogicalPlan, where a
 		cnfItems := expression.SplitCNFItems(expr)
 		for _, item := range cnfItems {
 			if con, ok := item.(*expression.Constant); ok {
+				if expression.MaybeOverOptimized4PlanCache(b.ctx, []expression.Expression{con}) {
+					b.ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+				}
 				ret, _, err := expression.EvalBool(b.ctx, expression.CNFExprs{con}, chunk.Row{})
 				if err != nil || ret {
 					continue
@@ -568,23 +632,53 @@ func (b *PlanBuilder) buildProjectionField(ctx context.Context, p LogicalPlan, f
 }
 
 // buildProjection returns a Projection plan and non-aux columns length.
-func (b *PlanBuilder) buildProjection(ctx context.Context, p LogicalPlan, fields []*ast.SelectField, mapper map[*ast.AggregateFuncExpr]int) (LogicalPlan, int, error) {
+// buildProjection returns a Projection plan and non-aux columns length.
+// Window functions are processed after the HAVING clause, so the projection is built twice when
+// the select fields contain window functions. When `considerWindow` is false, the window function
+// fields are filled with placeholders, which will be pruned later. When `considerWindow` is true,
+// the other fields have been built already, so the columns of p are reused for them.
+func (b *PlanBuilder) buildProjection(ctx context.Context, p LogicalPlan, fields []*ast.SelectField, mapper map[*ast.AggregateFuncExpr]int, windowMapper map[*ast.WindowFuncExpr]int, considerWindow bool) (LogicalPlan, int, error) {
 	b.optFlag |= flagEliminateProjection
 	b.curClause = fieldList
 	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, len(fields))}.Init(b.ctx)
 	schema := expression.NewSchema(make([]*expression.Column, 0, len(fields))...)
 	oldLen := 0
 	newNames := make([]*types.FieldName, 0, len(fields))
-	for _, field := range fields {
+	for i, field := range fields {
 		if !field.Auxiliary {
 			oldLen++
 		}
 
-		newExpr, np, err := b.rewriteWithPreprocess(ctx, field.Expr, p, mapper, true, nil)
+		isWindowFuncField := ast.HasWindowFlag(field.Expr)
+		if considerWindow && !isWindowFuncField {
+			col := p.Schema().Columns[i]
+			proj.Exprs = append(proj.Exprs, col)
+			schema.Append(col)
+			newNames = append(newNames, p.OutputNames()[i])
+			continue
+		} else if !considerWindow && isWindowFuncField {
+			expr := expression.Zero
+			proj.Exprs = append(proj.Exprs, expr)
+			col, name, err := b.buildProjectionField(ctx, p, field, expr)
+			if err != nil {
+				return nil, 0, err
+			}
+			schema.Append(col)
+			newNames = append(newNames, name)
+			continue
+		}
+
+		newExpr, np, err := b.rewriteWithPreprocess(ctx, field.Expr, p, mapper, windowMapper, true, nil)
 		if err != nil {
 			return nil, 0, err
 		}
 
+		// The window functions in the ORDER BY clause are appended as auxiliary fields,
+		// so that the Sort can refer to them by the output of this projection.
+		if windowExpr, ok := field.Expr.(*ast.WindowFuncExpr); ok && field.Auxiliary {
+			windowMapper[windowExpr] = i
+		}
+
 		p = np
 		proj.Exprs = append(proj.Exprs, newExpr)
 
@@ -619,6 +713,7 @@ func (b *PlanBuilder) buildDistinct(child LogicalPlan, length int) (*LogicalAggr
 	plan4Agg.SetChildren(child)
 	plan4Agg.SetSchema(child.Schema().Clone())
 	plan4Agg.names = child.OutputNames()
+	b.setPreferredAggType(plan4Agg)
 	// Distinct will be rewritten as first_row, we reset the type here since the return type
 	// of first_row is not always the same as the column arg of first_row.
 	for i, col := range plan4Agg.schema.Columns {
@@ -627,6 +722,259 @@ func (b *PlanBuilder) buildDistinct(child LogicalPlan, length int) (*LogicalAggr
 	return plan4Agg, nil
 }
 
+// unionJoinFieldType finds the type which can carry the given types in Union.
+func unionJoinFieldType(a, b *types.FieldType) *types.FieldType {
+	resultTp := types.NewFieldType(types.MergeFieldType(a.Tp, b.Tp))
+	// Results will be unsigned when the first SQL statement result in the union is unsigned.
+	resultTp.Flag |= a.Flag & mysql.UnsignedFlag
+	resultTp.Decimal = mathutil.Max(a.Decimal, b.Decimal)
+	// `Flen - Decimal` is the fraction before '.'
+	resultTp.Flen = mathutil.Max(a.Flen-a.Decimal, b.Flen-b.Decimal) + resultTp.Decimal
+	if resultTp.EvalType() != types.ETInt && (a.EvalType() == types.ETInt || b.EvalType() == types.ETInt) && resultTp.Flen < mysql.MaxIntWidth {
+		resultTp.Flen = mysql.MaxIntWidth
+	}
+	resultTp.Charset = a.Charset
+	resultTp.Collate = a.Collate
+	expression.SetBinFlagOrBinStr(b, resultTp)
+	return resultTp
+}
+
+func (b *PlanBuilder) buildProjection4Union(u *LogicalUnionAll) {
+	unionCols := make([]*expression.Column, 0, u.children[0].Schema().Len())
+	names := make([]*types.FieldName, 0, u.children[0].Schema().Len())
+
+	// Infer union result types by its children's schema.
+	for i, col := range u.children[0].Schema().Columns {
+		resultTp := col.RetType
+		for j := 1; j < len(u.children); j++ {
+			childTp := u.children[j].Schema().Columns[i].RetType
+			resultTp = unionJoinFieldType(resultTp, childTp)
+		}
+		names = append(names, &types.FieldName{ColName: u.children[0].OutputNames()[i].ColName})
+		unionCols = append(unionCols, &expression.Column{
+			RetType:  resultTp,
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+	}
+	u.schema = expression.NewSchema(unionCols...)
+	u.names = names
+	// Process each child and add a projection above original child.
+	// So the schema of `UnionAll` can be the same with its children's.
+	for childID, child := range u.children {
+		exprs := make([]expression.Expression, len(child.Schema().Columns))
+		for i, srcCol := range child.Schema().Columns {
+			dstType := unionCols[i].RetType
+			srcType := srcCol.RetType
+			if !srcType.Equal(dstType) {
+				exprs[i] = expression.BuildCastFunction4Union(b.ctx, srcCol, dstType)
+			} else {
+				exprs[i] = srcCol
+			}
+		}
+		b.optFlag |= flagEliminateProjection
+		proj := LogicalProjection{Exprs: exprs, AvoidColumnEvaluator: true}.Init(b.ctx)
+		proj.SetSchema(u.schema.Clone())
+		proj.SetChildren(child)
+		u.children[childID] = proj
+	}
+}
+
+func (b *PlanBuilder) buildSetOpr(ctx context.Context, setOpr *ast.SetOprStmt) (LogicalPlan, error) {
+	// Because INTERSECT has higher precedence than UNION and EXCEPT, we build it first.
+	selectPlans := make([]LogicalPlan, 0, len(setOpr.SelectList.Selects))
+	afterSetOprs := make([]*ast.SetOprType, 0, len(setOpr.SelectList.Selects))
+	selects := setOpr.SelectList.Selects
+	for i := 0; i < len(selects); i++ {
+		intersects := []*ast.SelectStmt{selects[i]}
+		for i+1 < len(selects) && *selects[i+1].AfterSetOperator == ast.Intersect {
+			intersects = append(intersects, selects[i+1])
+			i++
+		}
+		selectPlan, afterSetOpr, err := b.buildIntersect(ctx, intersects)
+		if err != nil {
+			return nil, err
+		}
+		selectPlans = append(selectPlans, selectPlan)
+		afterSetOprs = append(afterSetOprs, afterSetOpr)
+	}
+	setOprPlan, err := b.buildExcept(ctx, selectPlans, afterSetOprs)
+	if err != nil {
+		return nil, err
+	}
+
+	oldLen := setOprPlan.Schema().Len()
+
+	for i := 0; i < len(setOpr.SelectList.Selects); i++ {
+		b.handleHelper.popMap()
+	}
+	b.handleHelper.pushMap(nil)
+
+	if setOpr.OrderBy != nil {
+		setOprPlan, err = b.buildSort(ctx, setOprPlan, setOpr.OrderBy.Items, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if setOpr.Limit != nil {
+		setOprPlan, err = b.buildLimit(setOprPlan, setOpr.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// If there are extra expressions generated from `ORDER BY` clause, generate a `Projection` to remove them.
+	if oldLen != setOprPlan.Schema().Len() {
+		proj := LogicalProjection{Exprs: expression.Column2Exprs(setOprPlan.Schema().Columns[:oldLen])}.Init(b.ctx)
+		proj.SetChildren(setOprPlan)
+		schema := expression.NewSchema(setOprPlan.Schema().Clone().Columns[:oldLen]...)
+		for _, col := range schema.Columns {
+			col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+		}
+		proj.names = setOprPlan.OutputNames()[:oldLen]
+		proj.SetSchema(schema)
+		return proj, nil
+	}
+	return setOprPlan, nil
+}
+
+// buildSemiJoinForSetOperator builds a semi join or an anti semi join which matches every column of the
+// two sides by null-eq, the left side is made distinct first.
+func (b *PlanBuilder) buildSemiJoinForSetOperator(
+	leftOriginPlan LogicalPlan,
+	rightPlan LogicalPlan,
+	joinType JoinType) (leftPlan LogicalPlan, err error) {
+	b.optFlag = b.optFlag | flagPredicatePushDown
+	leftPlan, err = b.buildDistinct(leftOriginPlan, leftOriginPlan.Schema().Len())
+	if err != nil {
+		return nil, err
+	}
+	joinPlan := LogicalJoin{JoinType: joinType}.Init(b.ctx)
+	joinPlan.SetChildren(leftPlan, rightPlan)
+	joinPlan.SetSchema(leftPlan.Schema())
+	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len())
+	copy(joinPlan.names, leftPlan.OutputNames())
+	for j := 0; j < len(rightPlan.Schema().Columns); j++ {
+		leftCol, rightCol := leftPlan.Schema().Columns[j], rightPlan.Schema().Columns[j]
+		eqCond, err := expression.NewFunction(b.ctx, ast.NullEQ, types.NewFieldType(mysql.TypeTiny), leftCol, rightCol)
+		if err != nil {
+			return nil, err
+		}
+		if leftCol.RetType.Tp != rightCol.RetType.Tp {
+			joinPlan.OtherConditions = append(joinPlan.OtherConditions, eqCond)
+		} else {
+			joinPlan.EqualConditions = append(joinPlan.EqualConditions, eqCond.(*expression.ScalarFunction))
+		}
+	}
+	return joinPlan, nil
+}
+
+// buildIntersect builds the set operator for 'intersect'. It is called before buildExcept and buildUnion because of its
+// higher precedence.
+func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []*ast.SelectStmt) (LogicalPlan, *ast.SetOprType, error) {
+	leftPlan, err := b.buildSelect(ctx, selects[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	afterSetOperator := selects[0].AfterSetOperator
+	columnNums := leftPlan.Schema().Len()
+	for i := 1; i < len(selects); i++ {
+		rightPlan, err := b.buildSelect(ctx, selects[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if rightPlan.Schema().Len() != columnNums {
+			return nil, nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+		}
+		leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return leftPlan, afterSetOperator, nil
+}
+
+// buildExcept builds the set operators for 'except', and it calls buildUnion at the same time because
+// UNION and EXCEPT have the same precedence.
+func (b *PlanBuilder) buildExcept(ctx context.Context, selects []LogicalPlan, afterSetOprs []*ast.SetOprType) (LogicalPlan, error) {
+	unionPlans := []LogicalPlan{selects[0]}
+	tmpAfterSetOprs := []*ast.SetOprType{nil}
+	columnNums := selects[0].Schema().Len()
+	for i := 1; i < len(selects); i++ {
+		rightPlan := selects[i]
+		if rightPlan.Schema().Len() != columnNums {
+			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+		}
+		if *afterSetOprs[i] == ast.Except {
+			leftPlan, err := b.buildUnion(unionPlans, tmpAfterSetOprs)
+			if err != nil {
+				return nil, err
+			}
+			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			if err != nil {
+				return nil, err
+			}
+			unionPlans = []LogicalPlan{leftPlan}
+			tmpAfterSetOprs = []*ast.SetOprType{nil}
+		} else {
+			unionPlans = append(unionPlans, rightPlan)
+			tmpAfterSetOprs = append(tmpAfterSetOprs, afterSetOprs[i])
+		}
+	}
+	return b.buildUnion(unionPlans, tmpAfterSetOprs)
+}
+
+func (b *PlanBuilder) buildUnion(selects []LogicalPlan, afterSetOprs []*ast.SetOprType) (LogicalPlan, error) {
+	if len(selects) == 1 {
+		return selects[0], nil
+	}
+	distinctSelectPlans, allSelectPlans := b.divideUnionSelectPlans(selects, afterSetOprs)
+
+	unionDistinctPlan := b.buildUnionAll(distinctSelectPlans)
+	if unionDistinctPlan != nil {
+		var err error
+		unionDistinctPlan, err = b.buildDistinct(unionDistinctPlan, unionDistinctPlan.Schema().Len())
+		if err != nil {
+			return nil, err
+		}
+		if len(allSelectPlans) > 0 {
+			// Can't change the statements order in order to get the correct column info.
+			allSelectPlans = append([]LogicalPlan{unionDistinctPlan}, allSelectPlans...)
+		}
+	}
+
+	unionAllPlan := b.buildUnionAll(allSelectPlans)
+	unionPlan := unionDistinctPlan
+	if unionAllPlan != nil {
+		unionPlan = unionAllPlan
+	}
+	return unionPlan, nil
+}
+
+// divideUnionSelectPlans divides union's select plans into "union-distinct" and "union-all" parts.
+// divide rule ref: https://dev.mysql.com/doc/refman/5.7/en/union.html
+// "Mixed UNION types are treated such that a DISTINCT union overrides any ALL union to its left."
+func (b *PlanBuilder) divideUnionSelectPlans(selects []LogicalPlan, afterSetOprs []*ast.SetOprType) (distinctSelects []LogicalPlan, allSelects []LogicalPlan) {
+	firstUnionAllIdx := 0
+	for i := len(selects) - 1; i > 0; i-- {
+		if *afterSetOprs[i] != ast.UnionAll {
+			firstUnionAllIdx = i + 1
+			break
+		}
+	}
+	return selects[:firstUnionAllIdx], selects[firstUnionAllIdx:]
+}
+
+func (b *PlanBuilder) buildUnionAll(subPlan []LogicalPlan) LogicalPlan {
+	if len(subPlan) == 0 {
+		return nil
+	}
+	u := LogicalUnionAll{}.Init(b.ctx)
+	u.children = subPlan
+	b.buildProjection4Union(u)
+	return u
+}
+
 // ByItems wraps a "by" item.
 type ByItems struct {
 	Expr expression.Expression
@@ -646,12 +994,12 @@ func (by *ByItems) Clone() *ByItems {
 	return &ByItems{Expr: by.Expr.Clone(), Desc: by.Desc}
 }
 
-func (b *PlanBuilder) buildSort(ctx context.Context, p LogicalPlan, byItems []*ast.ByItem, aggMapper map[*ast.AggregateFuncExpr]int) (*LogicalSort, error) {
+func (b *PlanBuilder) buildSort(ctx context.Context, p LogicalPlan, byItems []*ast.ByItem, aggMapper map[*ast.AggregateFuncExpr]int, windowMapper map[*ast.WindowFuncExpr]int) (*LogicalSort, error) {
 	b.curClause = orderByClause
 	sort := LogicalSort{}.Init(b.ctx)
 	exprs := make([]*ByItems, 0, len(byItems))
 	for _, item := range byItems {
-		it, np, err := b.rewriteWithPreprocess(ctx, item.Expr, p, aggMapper, true, nil)
+		it, np, err := b.rewriteWithPreprocess(ctx, item.Expr, p, aggMapper, windowMapper, true, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -671,6 +1019,13 @@ func getUintFromNode(ctx sessionctx.Context, n ast.Node) (uVal uint64, isNull bo
 	switch v := n.(type) {
 	case *driver.ValueExpr:
 		val = v.GetValue()
+	case *driver.ParamMarkerExpr:
+		// The value of the parameter marker is unknown when the statement is
+		// being prepared, it is checked again when the statement is executed.
+		if !v.InExecute {
+			return 0, false, true
+		}
+		val = v.GetValue()
 	default:
 		return 0, false, false
 	}
@@ -798,6 +1153,8 @@ func resolveFromSelectFields(v *ast.ColumnNameExpr, fields []*ast.SelectField, i
 // It converts ColunmNameExpr to AggregateFuncExpr and collects AggregateFuncExpr.
 type havingAndOrderbyExprResolver struct {
 	inAggFunc    bool
+	inWindowFunc bool
+	inWindowSpec bool
 	inExpr       bool
 	orderBy      bool
 	err          error
@@ -806,6 +1163,8 @@ type havingAndOrderbyExprResolver struct {
 	aggMapper    map[*ast.AggregateFuncExpr]int
 	colMapper    map[*ast.ColumnNameExpr]int
 	gbyItems     []*ast.ByItem
+	outerSchemas []*expression.Schema
+	outerNames   [][]*types.FieldName
 	curClause    clauseCode
 }
 
@@ -814,7 +1173,15 @@ func (a *havingAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChi
 	switch n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggFunc = true
+	case *ast.WindowFuncExpr:
+		a.inWindowFunc = true
+	case *ast.WindowSpec:
+		a.inWindowSpec = true
 	case *ast.ColumnNameExpr, *ast.ColumnName:
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr:
+		// Enter a new context, skip it.
+		// For example: select sum(c) + c + exists(select c from t) from t;
+		return n, true
 	default:
 		a.inExpr = true
 	}
@@ -861,9 +1228,24 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			Expr:      v,
 			AsName:    model.NewCIStr(fmt.Sprintf("sel_agg_%d", len(a.selectFields))),
 		})
+	case *ast.WindowFuncExpr:
+		a.inWindowFunc = false
+		if a.curClause == havingClause {
+			a.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+			return node, false
+		}
+		if a.curClause == orderByClause {
+			a.selectFields = append(a.selectFields, &ast.SelectField{
+				Auxiliary: true,
+				Expr:      v,
+				AsName:    model.NewCIStr(fmt.Sprintf("sel_window_%d", len(a.selectFields))),
+			})
+		}
+	case *ast.WindowSpec:
+		a.inWindowSpec = false
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
-		if a.inAggFunc || (a.orderBy && a.inExpr) || a.curClause == fieldList {
+		if a.inAggFunc || a.inWindowFunc || a.inWindowSpec || (a.orderBy && a.inExpr) || a.curClause == fieldList {
 			resolveFieldsFirst = false
 		}
 		if !a.inAggFunc && !a.orderBy {
@@ -902,9 +1284,24 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			return node, false
 		}
 		if index == -1 {
+			// If we can't find it any where, it may be a correlated columns.
+			for _, names := range a.outerNames {
+				idx, err1 := expression.FindFieldName(names, v.Name)
+				if err1 != nil {
+					a.err = err1
+					return node, false
+				}
+				if idx >= 0 {
+					return n, true
+				}
+			}
 			a.err = ErrUnknownColumn.GenWithStackByArgs(v.Name.OrigColName(), clauseMsg[a.curClause])
 			return node, false
 		}
+		if a.curClause == havingClause && index < len(a.selectFields) && ast.HasWindowFlag(a.selectFields[index].Expr) {
+			a.err = ErrWindowInvalidWindowFuncAliasUse.GenWithStackByArgs(v.Name.Name.O)
+			return node, false
+		}
 		if a.inAggFunc {
 			return a.selectFields[index].Expr, true
 		}
@@ -923,6 +1320,8 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 		selectFields: sel.Fields.Fields,
 		aggMapper:    make(map[*ast.AggregateFuncExpr]int),
 		colMapper:    b.colMapper,
+		outerSchemas: b.outerSchemas,
+		outerNames:   b.outerNames,
 	}
 	if sel.GroupBy != nil {
 		extractor.gbyItems = sel.GroupBy.Items
@@ -944,6 +1343,9 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 	if sel.OrderBy != nil {
 		extractor.curClause = orderByClause
 		for _, item := range sel.OrderBy.Items {
+			if ast.HasWindowFlag(item.Expr) {
+				continue
+			}
 			n, ok := item.Expr.Accept(extractor)
 			if !ok {
 				return nil, nil, errors.Trace(extractor.err)
@@ -955,6 +1357,48 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 	return havingAggMapper, extractor.aggMapper, nil
 }
 
+// resolveWindowFunction resolves the columns and aggregate functions used by window functions in the select fields
+// and ORDER BY clause. The columns that don't exist in select fields are appended to select fields, and the window
+// functions in ORDER BY clause are appended as auxiliary select fields.
+func (b *PlanBuilder) resolveWindowFunction(sel *ast.SelectStmt, p LogicalPlan) (
+	map[*ast.AggregateFuncExpr]int, error) {
+	b.curClause = fieldList
+	extractor := &havingAndOrderbyExprResolver{
+		p:            p,
+		selectFields: sel.Fields.Fields,
+		aggMapper:    make(map[*ast.AggregateFuncExpr]int),
+		colMapper:    b.colMapper,
+		outerSchemas: b.outerSchemas,
+		outerNames:   b.outerNames,
+	}
+	extractor.curClause = fieldList
+	for _, field := range sel.Fields.Fields {
+		if !ast.HasWindowFlag(field.Expr) {
+			continue
+		}
+		n, ok := field.Expr.Accept(extractor)
+		if !ok {
+			return nil, extractor.err
+		}
+		field.Expr = n.(ast.ExprNode)
+	}
+	if sel.OrderBy != nil {
+		extractor.curClause = orderByClause
+		for _, item := range sel.OrderBy.Items {
+			if !ast.HasWindowFlag(item.Expr) {
+				continue
+			}
+			n, ok := item.Expr.Accept(extractor)
+			if !ok {
+				return nil, extractor.err
+			}
+			item.Expr = n.(ast.ExprNode)
+		}
+	}
+	sel.Fields.Fields = extractor.selectFields
+	return extractor.aggMapper, nil
+}
+
 func (b *PlanBuilder) extractAggFuncs(fields []*ast.SelectField) ([]*ast.AggregateFuncExpr, map[*ast.AggregateFuncExpr]int) {
 	extractor := &AggregateFuncExtractor{}
 	for _, f := range fields {
@@ -970,6 +1414,15 @@ func (b *PlanBuilder) extractAggFuncs(fields []*ast.SelectField) ([]*ast.Aggrega
 	return aggList, totalAggMapper
 }
 
+func extractWindowFuncs(fields []*ast.SelectField) []*ast.WindowFuncExpr {
+	extractor := &WindowFuncExtractor{}
+	for _, f := range fields {
+		n, _ := f.Expr.Accept(extractor)
+		f.Expr = n.(ast.ExprNode)
+	}
+	return extractor.windowFuncs
+}
+
 // gbyResolver resolves group by items from select fields.
 type gbyResolver struct {
 	ctx    sessionctx.Context
@@ -985,6 +1438,8 @@ type gbyResolver struct {
 func (g *gbyResolver) Enter(inNode ast.Node) (ast.Node, bool) {
 	g.exprDepth++
 	switch inNode.(type) {
+	case *ast.SubqueryExpr, *ast.CompareSubqueryExpr, *ast.ExistsSubqueryExpr:
+		return inNode, true
 	case *driver.ValueExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ColumnName:
 	default:
 		g.inExpr = true
@@ -1094,15 +1549,29 @@ func (b *PlanBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectFi
 
 func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
 	var (
-		sortMergeTables, hashJoinTables []hintTableInfo
-		indexHintList                   []indexHintInfo
+		sortMergeTables, hashJoinTables, indexNestedLoopJoinTables []hintTableInfo
+		indexHintList                                              []indexHintInfo
+		leadingHint                                                *leadingHintInfo
+		aggHints                                                   aggHintInfo
 	)
 	for _, hint := range hints {
 		switch hint.HintName.L {
-		case TiDBMergeJoin, HintSMJ:
+		case TiDBMergeJoin, HintSMJ, HintMergeJoin:
 			sortMergeTables = append(sortMergeTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
 		case TiDBHashJoin, HintHJ:
 			hashJoinTables = append(hashJoinTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
+		case TiDBIndexNestedLoopJoin, HintINLJ:
+			indexNestedLoopJoinTables = append(indexNestedLoopJoinTables, tableNames2HintTableInfo(b.ctx, hint.Tables)...)
+		case HintLeading:
+			if leadingHint != nil {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("We can only use one leading hint at most, the later ones are ignored"))
+				continue
+			}
+			leadingHint = &leadingHintInfo{tables: tableNames2HintTableInfo(b.ctx, hint.Tables)}
+		case HintHashAgg:
+			aggHints.preferAggType |= preferHashAgg
+		case HintStreamAgg:
+			aggHints.preferAggType |= preferStreamAgg
 		case HintUseIndex:
 			if len(hint.Tables) != 0 {
 				dbName := hint.Tables[0].DBName
@@ -1141,19 +1610,42 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
 		}
 	}
 	b.tableHintInfo = append(b.tableHintInfo, tableHintInfo{
-		sortMergeJoinTables: sortMergeTables,
-		hashJoinTables:      hashJoinTables,
-		indexHintList:       indexHintList,
+		indexNestedLoopJoinTables: indexNestedLoopJoinTables,
+		sortMergeJoinTables:       sortMergeTables,
+		hashJoinTables:            hashJoinTables,
+		indexHintList:             indexHintList,
+		leadingHint:               leadingHint,
+		aggHints:                  aggHints,
 	})
 }
 
 func (b *PlanBuilder) popTableHints() {
 	hintInfo := b.tableHintInfo[len(b.tableHintInfo)-1]
+	b.appendUnmatchedJoinHintWarning(HintINLJ, TiDBIndexNestedLoopJoin, hintInfo.indexNestedLoopJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintSMJ, TiDBMergeJoin, hintInfo.sortMergeJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintHJ, TiDBHashJoin, hintInfo.hashJoinTables)
+	if hintInfo.leadingHint != nil {
+		b.appendUnmatchedJoinHintWarning(HintLeading, "", hintInfo.leadingHint.tables)
+	}
+	b.appendUnmatchedIndexHintWarning(hintInfo.indexHintList)
+	if hintInfo.aggHints.preferAggType != 0 && !hintInfo.aggHints.matched {
+		errMsg := "Optimizer Hint HASH_AGG or STREAM_AGG is inapplicable, there is no aggregation in the query block"
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+	}
 	b.tableHintInfo = b.tableHintInfo[:len(b.tableHintInfo)-1]
 }
 
+func (b *PlanBuilder) appendUnmatchedIndexHintWarning(indexHints []indexHintInfo) {
+	for _, hint := range indexHints {
+		if hint.matched {
+			continue
+		}
+		errMsg := fmt.Sprintf("%s(%s.%s) is inapplicable, check whether the table(%s.%s) exists",
+			hint.hintName(), hint.dbName.O, hint.tblName.O, hint.dbName.O, hint.tblName.O)
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+	}
+}
+
 func (b *PlanBuilder) appendUnmatchedJoinHintWarning(joinType string, joinTypeAlias string, hintTables []hintTableInfo) {
 	unMatchedTables := extractUnmatchedTables(hintTables)
 	if len(unMatchedTables) == 0 {
@@ -1192,6 +1684,7 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	var (
 		aggFuncs                      []*ast.AggregateFuncExpr
 		havingMap, orderMap, totalMap map[*ast.AggregateFuncExpr]int
+		windowAggMap                  map[*ast.AggregateFuncExpr]int
 		gbyCols                       []expression.Expression
 	)
 
@@ -1217,6 +1710,13 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	hasWindowFuncField := b.detectSelectWindow(sel)
+	if hasWindowFuncField {
+		windowAggMap, err = b.resolveWindowFunction(sel, p)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// We must resolve having and order by clause before build projection,
 	// because when the query is "select a+1 as b from t having sum(b) < 0", we must replace sum(b) to sum(a+1),
 	// which only can be done before building projection and extracting Agg functions.
@@ -1249,7 +1749,9 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	}
 
 	var oldLen int
-	p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, totalMap)
+	// Window functions can only be processed after the HAVING clause,
+	// so `considerWindow` is false here.
+	p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, totalMap, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -1262,6 +1764,20 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 
+	var windowMapper map[*ast.WindowFuncExpr]int
+	if hasWindowFuncField {
+		windowFuncs := extractWindowFuncs(sel.Fields.Fields)
+		p, windowMapper, err = b.buildWindowFunctions(ctx, p, windowFuncs, windowAggMap)
+		if err != nil {
+			return nil, err
+		}
+		// Now we build the window function fields.
+		p, oldLen, err = b.buildProjection(ctx, p, sel.Fields.Fields, windowAggMap, windowMapper, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if sel.Distinct {
 		p, err = b.buildDistinct(p, oldLen)
 		if err != nil {
@@ -1270,7 +1786,7 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	}
 
 	if sel.OrderBy != nil {
-		p, err = b.buildSort(ctx, p, sel.OrderBy.Items, orderMap)
+		p, err = b.buildSort(ctx, p, sel.OrderBy.Items, orderMap, windowMapper)
 		if err != nil {
 			return nil, err
 		}
@@ -1299,6 +1815,80 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	return p, nil
 }
 
+func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, tp JoinType) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
+	ap := LogicalApply{LogicalJoin: LogicalJoin{JoinType: tp}}.Init(b.ctx)
+	ap.SetChildren(outerPlan, innerPlan)
+	ap.names = make([]*types.FieldName, outerPlan.Schema().Len()+innerPlan.Schema().Len())
+	copy(ap.names, outerPlan.OutputNames())
+	ap.SetSchema(expression.MergeSchema(outerPlan.Schema(), innerPlan.Schema()))
+	// Note that, tp can only be LeftOuterJoin or InnerJoin, so we don't consider other outer joins.
+	if tp == LeftOuterJoin {
+		b.optFlag = b.optFlag | flagEliminateOuterJoin
+		resetNotNullFlag(ap.schema, outerPlan.Schema().Len(), ap.schema.Len())
+	}
+	for i := outerPlan.Schema().Len(); i < ap.Schema().Len(); i++ {
+		ap.names[i] = types.EmptyName
+	}
+	return ap
+}
+
+// buildSemiApply builds apply plan with outerPlan and innerPlan, which apply semi-join for every row from outerPlan and the whole innerPlan.
+func (b *PlanBuilder) buildSemiApply(outerPlan, innerPlan LogicalPlan, condition []expression.Expression, asScalar, not bool) (LogicalPlan, error) {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
+
+	join, err := b.buildSemiJoin(outerPlan, innerPlan, condition, asScalar, not)
+	if err != nil {
+		return nil, err
+	}
+
+	ap := &LogicalApply{LogicalJoin: *join}
+	ap.tp = TypeApply
+	ap.self = ap
+	return ap, nil
+}
+
+func (b *PlanBuilder) buildMaxOneRow(p LogicalPlan) LogicalPlan {
+	maxOneRow := LogicalMaxOneRow{}.Init(b.ctx)
+	maxOneRow.SetChildren(p)
+	return maxOneRow
+}
+
+func (b *PlanBuilder) buildSemiJoin(outerPlan, innerPlan LogicalPlan, onCondition []expression.Expression, asScalar bool, not bool) (*LogicalJoin, error) {
+	joinPlan := LogicalJoin{}.Init(b.ctx)
+	for i, expr := range onCondition {
+		onCondition[i] = expr.Decorrelate(outerPlan.Schema())
+	}
+	joinPlan.SetChildren(outerPlan, innerPlan)
+	joinPlan.attachOnConds(onCondition)
+	joinPlan.names = make([]*types.FieldName, outerPlan.Schema().Len(), outerPlan.Schema().Len()+innerPlan.Schema().Len()+1)
+	copy(joinPlan.names, outerPlan.OutputNames())
+	if asScalar {
+		newSchema := outerPlan.Schema().Clone()
+		newSchema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  types.NewFieldType(mysql.TypeTiny),
+		})
+		joinPlan.names = append(joinPlan.names, types.EmptyName)
+		joinPlan.SetSchema(newSchema)
+		if not {
+			joinPlan.JoinType = AntiLeftOuterSemiJoin
+		} else {
+			joinPlan.JoinType = LeftOuterSemiJoin
+		}
+	} else {
+		joinPlan.SetSchema(outerPlan.Schema().Clone())
+		if not {
+			joinPlan.JoinType = AntiSemiJoin
+		} else {
+			joinPlan.JoinType = SemiJoin
+		}
+	}
+	// Apply forces to choose hash join currently, so don't worry the hints will take effect if the semi join is in one apply.
+	joinPlan.setPreferredJoinType(b.TableHints())
+	return joinPlan, nil
+}
+
 func (b *PlanBuilder) buildTableDual() *LogicalTableDual {
 	b.handleHelper.pushMap(nil)
 	return LogicalTableDual{RowCount: 1}.Init(b.ctx)
@@ -1584,6 +2174,151 @@ func buildColumns2Handle(
 	return cols2Handles, nil
 }
 
+func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (Plan, error) {
+	b.pushTableHints(update.TableHints)
+	defer func() {
+		// table hints are only visible in the current UPDATE statement.
+		b.popTableHints()
+	}()
+
+	// update subquery table should be forbidden
+	var asNameList []string
+	asNameList = extractTableSourceAsNames(update.TableRefs.TableRefs, asNameList, true)
+	for _, asName := range asNameList {
+		for _, assign := range update.List {
+			if assign.Column.Table.L == asName {
+				return nil, ErrNonUpdatableTable.GenWithStackByArgs(asName, "UPDATE")
+			}
+		}
+	}
+
+	p, err := b.buildResultSetNode(ctx, update.TableRefs.TableRefs)
+	if err != nil {
+		return nil, err
+	}
+	oldSchemaLen := p.Schema().Len()
+
+	if update.Where != nil {
+		p, err = b.buildSelection(ctx, p, update.Where, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Order != nil {
+		p, err = b.buildSort(ctx, p, update.Order.Items, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Limit != nil {
+		p, err = b.buildLimit(p, update.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add project to freeze the order of output columns.
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns[:oldSchemaLen])}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(make([]*expression.Column, oldSchemaLen)...))
+	proj.names = make(types.NameSlice, len(p.OutputNames()))
+	copy(proj.names, p.OutputNames())
+	copy(proj.schema.Columns, p.Schema().Columns[:oldSchemaLen])
+	proj.SetChildren(p)
+	p = proj
+
+	orderedList, np, allAssignmentsAreConstant, err := b.buildUpdateLists(ctx, update.List, p)
+	if err != nil {
+		return nil, err
+	}
+	p = np
+
+	updt := Update{OrderedList: orderedList, AllAssignmentsAreConstant: allAssignmentsAreConstant}.Init(b.ctx)
+	updt.names = p.OutputNames()
+	// We cannot apply projection elimination when building the subplan, because
+	// columns in orderedList cannot be resolved.
+	updt.SelectPlan, err = DoOptimize(ctx, b.optFlag&^flagEliminateProjection, p)
+	if err != nil {
+		return nil, err
+	}
+	err = updt.ResolveIndices()
+	if err != nil {
+		return nil, err
+	}
+	tblID2Handle, err := resolveIndicesForTblID2Handle(b.handleHelper.tailMap(), updt.SelectPlan.Schema())
+	if err != nil {
+		return nil, err
+	}
+	tblID2table := make(map[int64]table.Table)
+	for id := range tblID2Handle {
+		tblID2table[id], _ = b.is.TableByID(id)
+	}
+	updt.TblColPosInfos, err = buildColumns2Handle(updt.OutputNames(), tblID2Handle, tblID2table, true)
+	return updt, err
+}
+
+func (b *PlanBuilder) buildUpdateLists(
+	ctx context.Context,
+	list []*ast.Assignment,
+	p LogicalPlan,
+) (newList []*expression.Assignment,
+	po LogicalPlan,
+	allAssignmentsAreConstant bool,
+	e error,
+) {
+	b.curClause = fieldList
+	allAssignmentsAreConstant = true
+	newList = make([]*expression.Assignment, 0, len(list))
+	for _, assign := range list {
+		idx, err := expression.FindFieldName(p.OutputNames(), assign.Column)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if idx < 0 {
+			return nil, nil, false, ErrUnknownColumn.GenWithStackByArgs(assign.Column.Name, "field list")
+		}
+		col := p.Schema().Columns[idx]
+		name := p.OutputNames()[idx]
+		// If assign `DEFAULT` to column, fill the `defaultExpr.Name` before rewrite expression
+		if expr := extractDefaultExpr(assign.Expr); expr != nil {
+			expr.Name = assign.Column
+		}
+		newExpr, np, err := b.rewrite(ctx, assign.Expr, p, nil, false)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if _, isConst := newExpr.(*expression.Constant); !isConst {
+			allAssignmentsAreConstant = false
+		}
+		p = np
+		newList = append(newList, &expression.Assignment{Col: col, ColName: name.ColName, Expr: newExpr})
+	}
+	return newList, p, allAssignmentsAreConstant, nil
+}
+
+// extractTableSourceAsNames extracts TableSource.AsNames from node.
+// if onlySelectStmt is set to be true, only extracts AsNames when TableSource.Source.(type) == *ast.SelectStmt
+func extractTableSourceAsNames(node ast.ResultSetNode, input []string, onlySelectStmt bool) []string {
+	switch x := node.(type) {
+	case *ast.Join:
+		input = extractTableSourceAsNames(x.Left, input, onlySelectStmt)
+		input = extractTableSourceAsNames(x.Right, input, onlySelectStmt)
+	case *ast.TableSource:
+		if _, ok := x.Source.(*ast.SelectStmt); !ok && onlySelectStmt {
+			break
+		}
+		if s, ok := x.Source.(*ast.TableName); ok {
+			if x.AsName.L == "" {
+				input = append(input, s.Name.L)
+				break
+			}
+		}
+		input = append(input, x.AsName.L)
+	}
+	return input
+}
+
 // extractDefaultExpr extract a `DefaultExpr` from `ExprNode`,
 // If it is a `DEFAULT` function like `DEFAULT(a)`, return nil.
 // Only if it is `DEFAULT` keyword, it will return the `DefaultExpr`.
@@ -1610,7 +2345,7 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	}
 
 	if delete.Order != nil {
-		p, err = b.buildSort(ctx, p, delete.Order.Items, nil)
+		p, err = b.buildSort(ctx, p, delete.Order.Items, nil, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -1676,5 +2411,310 @@ func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
 // containDifferentJoinTypes checks whether `preferJoinType` contains different
 // join types.
 func containDifferentJoinTypes(preferJoinType uint) bool {
+	// The INL_JOIN hints on both sides of a join are the same join type.
+	inlMask := preferLeftAsIndexInner | preferRightAsIndexInner
+	if preferJoinType&inlMask > 0 {
+		preferJoinType = preferJoinType&^inlMask | preferLeftAsIndexInner
+	}
 	return bits.OnesCount(preferJoinType) > 1
 }
+
+// buildProjectionForWindow builds the projection for expressions in the window specification that is not an column,
+// so after the projection, window functions only needs to deal with columns.
+func (b *PlanBuilder) buildProjectionForWindow(ctx context.Context, p LogicalPlan, spec *ast.WindowSpec, args []ast.ExprNode, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, []property.Item, []property.Item, []expression.Expression, error) {
+	b.optFlag |= flagEliminateProjection
+
+	var partitionItems, orderItems []*ast.ByItem
+	if spec.PartitionBy != nil {
+		partitionItems = spec.PartitionBy.Items
+	}
+	if spec.OrderBy != nil {
+		orderItems = spec.OrderBy.Items
+	}
+
+	projLen := len(p.Schema().Columns) + len(partitionItems) + len(orderItems) + len(args)
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, projLen)}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(make([]*expression.Column, 0, projLen)...))
+	proj.names = make([]*types.FieldName, p.Schema().Len(), projLen)
+	for _, col := range p.Schema().Columns {
+		proj.Exprs = append(proj.Exprs, col)
+		proj.schema.Append(col)
+	}
+	copy(proj.names, p.OutputNames())
+
+	propertyItems := make([]property.Item, 0, len(partitionItems)+len(orderItems))
+	var err error
+	p, propertyItems, err = b.buildByItemsForWindow(ctx, p, proj, partitionItems, propertyItems, aggMap)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	lenPartition := len(propertyItems)
+	p, propertyItems, err = b.buildByItemsForWindow(ctx, p, proj, orderItems, propertyItems, aggMap)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	newArgList := make([]expression.Expression, 0, len(args))
+	for _, arg := range args {
+		newArg, np, err := b.rewrite(ctx, arg, p, aggMap, true)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		p = np
+		switch newArg.(type) {
+		case *expression.Column, *expression.Constant:
+			newArgList = append(newArgList, newArg.Clone())
+			continue
+		}
+		proj.Exprs = append(proj.Exprs, newArg)
+		proj.names = append(proj.names, types.EmptyName)
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  newArg.GetType(),
+		}
+		proj.schema.Append(col)
+		newArgList = append(newArgList, col)
+	}
+
+	proj.SetChildren(p)
+	return proj, propertyItems[:lenPartition], propertyItems[lenPartition:], newArgList, nil
+}
+
+func (b *PlanBuilder) buildByItemsForWindow(
+	ctx context.Context,
+	p LogicalPlan,
+	proj *LogicalProjection,
+	items []*ast.ByItem,
+	retItems []property.Item,
+	aggMap map[*ast.AggregateFuncExpr]int,
+) (LogicalPlan, []property.Item, error) {
+	for _, item := range items {
+		it, np, err := b.rewrite(ctx, item.Expr, p, aggMap, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = np
+		if it.GetType().Tp == mysql.TypeNull {
+			continue
+		}
+		if col, ok := it.(*expression.Column); ok {
+			retItems = append(retItems, property.Item{Col: col, Desc: item.Desc})
+			continue
+		}
+		proj.Exprs = append(proj.Exprs, it)
+		proj.names = append(proj.names, types.EmptyName)
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  it.GetType(),
+		}
+		proj.schema.Append(col)
+		retItems = append(retItems, property.Item{Col: col, Desc: item.Desc})
+	}
+	return p, retItems, nil
+}
+
+// buildWindowFunctionFrameBound builds the bounds of window function frames.
+// For type `Rows`, the bound expr must be an unsigned integer.
+// For type `Range`, the bound expr must be a numeric constant, and the
+// comparing functions are built from the ORDER BY column.
+func (b *PlanBuilder) buildWindowFunctionFrameBound(spec *ast.WindowSpec, orderByItems []property.Item, boundClause *ast.FrameBound) (*FrameBound, error) {
+	frameType := spec.Frame.Type
+	bound := &FrameBound{Type: boundClause.Type, UnBounded: boundClause.UnBounded}
+	if bound.UnBounded {
+		return bound, nil
+	}
+
+	if frameType == ast.Rows {
+		if bound.Type == ast.CurrentRow {
+			return bound, nil
+		}
+		numRows, _, _ := getUintFromNode(b.ctx, boundClause.Expr)
+		bound.Num = numRows
+		return bound, nil
+	}
+
+	bound.CalcFuncs = make([]expression.Expression, len(orderByItems))
+	bound.CmpFuncs = make([]expression.CompareFunc, len(orderByItems))
+	if bound.Type == ast.CurrentRow {
+		for i, item := range orderByItems {
+			col := item.Col
+			bound.CalcFuncs[i] = col
+			bound.CmpFuncs[i] = expression.GetCmpFunction(col, col)
+		}
+		return bound, nil
+	}
+
+	col := orderByItems[0].Col
+	val, err := evalAstExpr(b.ctx, boundClause.Expr)
+	if err != nil {
+		return nil, ErrWindowRangeBoundNotConstant.GenWithStackByArgs(unnamedWindow)
+	}
+	if val.IsNull() {
+		return nil, ErrWindowFrameIllegal.GenWithStackByArgs(unnamedWindow)
+	}
+	expr := &expression.Constant{Value: val, RetType: boundClause.Expr.GetType()}
+	num, _, err := expr.EvalReal(b.ctx, chunk.Row{})
+	if err != nil || num < 0 {
+		return nil, ErrWindowFrameIllegal.GenWithStackByArgs(unnamedWindow)
+	}
+
+	// When the order is asc:
+	//   `+` for following, and `-` for the preceding
+	// When the order is desc, `+` becomes `-` and vice-versa.
+	funcName := ast.Plus
+	desc := orderByItems[0].Desc
+	if (!desc && bound.Type == ast.Preceding) || (desc && bound.Type == ast.Following) {
+		funcName = ast.Minus
+	}
+	bound.CalcFuncs[0], err = expression.NewFunctionBase(b.ctx, funcName, col.RetType, col, expr)
+	if err != nil {
+		return nil, err
+	}
+	bound.CmpFuncs[0] = expression.GetCmpFunction(col, bound.CalcFuncs[0])
+	return bound, nil
+}
+
+// buildWindowFunctionFrame builds the window function frames.
+// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-frames.html
+func (b *PlanBuilder) buildWindowFunctionFrame(spec *ast.WindowSpec, orderByItems []property.Item) (*WindowFrame, error) {
+	frameClause := spec.Frame
+	if frameClause == nil {
+		return nil, nil
+	}
+	frame := &WindowFrame{Type: frameClause.Type}
+	var err error
+	frame.Start, err = b.buildWindowFunctionFrameBound(spec, orderByItems, &frameClause.Extent.Start)
+	if err != nil {
+		return nil, err
+	}
+	frame.End, err = b.buildWindowFunctionFrameBound(spec, orderByItems, &frameClause.Extent.End)
+	return frame, err
+}
+
+// unnamedWindow is the window name used in error messages, since only inline window specifications are supported.
+const unnamedWindow = "<unnamed window>"
+
+func (b *PlanBuilder) checkWindowFrame(spec *ast.WindowSpec, orderByItems []property.Item) error {
+	if spec.Frame == nil {
+		return nil
+	}
+	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
+	if start.Type == ast.Following && start.UnBounded {
+		return ErrWindowFrameStartIllegal.GenWithStackByArgs(unnamedWindow)
+	}
+	if end.Type == ast.Preceding && end.UnBounded {
+		return ErrWindowFrameEndIllegal.GenWithStackByArgs(unnamedWindow)
+	}
+	if start.Type == ast.Following && (end.Type == ast.Preceding || end.Type == ast.CurrentRow) {
+		return ErrWindowFrameIllegal.GenWithStackByArgs(unnamedWindow)
+	}
+	if (start.Type == ast.Following || start.Type == ast.CurrentRow) && end.Type == ast.Preceding {
+		return ErrWindowFrameIllegal.GenWithStackByArgs(unnamedWindow)
+	}
+	err := b.checkWindowFrameBound(&start, spec, orderByItems)
+	if err != nil {
+		return err
+	}
+	return b.checkWindowFrameBound(&end, spec, orderByItems)
+}
+
+func (b *PlanBuilder) checkWindowFrameBound(bound *ast.FrameBound, spec *ast.WindowSpec, orderByItems []property.Item) error {
+	if bound.Type == ast.CurrentRow || bound.UnBounded {
+		return nil
+	}
+	if spec.Frame.Type == ast.Rows {
+		_, isNull, isExpectedType := getUintFromNode(b.ctx, bound.Expr)
+		if isNull || !isExpectedType {
+			return ErrWindowFrameIllegal.GenWithStackByArgs(unnamedWindow)
+		}
+		return nil
+	}
+	if len(orderByItems) != 1 || !types.IsTypeNumeric(orderByItems[0].Col.RetType.Tp) {
+		return ErrWindowRangeFrameOrderType.GenWithStackByArgs(unnamedWindow)
+	}
+	return nil
+}
+
+// handleDefaultFrame returns the window specification with the default frame applied.
+// The origin specification is never modified since the AST may be reused.
+func (b *PlanBuilder) handleDefaultFrame(spec *ast.WindowSpec, windowFuncName string) *ast.WindowSpec {
+	needFrame := aggregation.NeedFrame(windowFuncName)
+	// According to MySQL, in the absence of a frame clause, the default frame depends on whether an ORDER BY clause is present:
+	//   (1) With order by, the default frame is equivalent to "RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW";
+	//   (2) Without order by, the default frame includes all partition rows, which is the same as an empty frame.
+	if needFrame && spec.Frame == nil && spec.OrderBy != nil {
+		newSpec := *spec
+		newSpec.Frame = &ast.FrameClause{
+			Type: ast.Ranges,
+			Extent: ast.FrameExtent{
+				Start: ast.FrameBound{Type: ast.Preceding, UnBounded: true},
+				End:   ast.FrameBound{Type: ast.CurrentRow},
+			},
+		}
+		return &newSpec
+	}
+	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame.
+	if needFrame && spec.Frame != nil &&
+		spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.End.UnBounded {
+		newSpec := *spec
+		newSpec.Frame = nil
+		return &newSpec
+	}
+	// For functions that operate on the entire partition, the frame clause will be ignored.
+	if !needFrame && spec.Frame != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendNote(ErrWindowFunctionIgnoresFrame.GenWithStackByArgs(strings.ToLower(windowFuncName), unnamedWindow))
+		newSpec := *spec
+		newSpec.Frame = nil
+		return &newSpec
+	}
+	return spec
+}
+
+// buildWindowFunctions builds a LogicalWindow for every window function, the window function
+// results are appended to the schema in order. It returns the new plan and a map from the
+// window functions to the offsets of their results in the schema.
+func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, windowFuncs []*ast.WindowFuncExpr, aggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, map[*ast.WindowFuncExpr]int, error) {
+	windowMap := make(map[*ast.WindowFuncExpr]int, len(windowFuncs))
+	for _, windowFunc := range windowFuncs {
+		np, partitionBy, orderBy, args, err := b.buildProjectionForWindow(ctx, p, &windowFunc.Spec, windowFunc.Args, aggMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = b.checkWindowFrame(&windowFunc.Spec, orderBy)
+		if err != nil {
+			return nil, nil, err
+		}
+		spec := b.handleDefaultFrame(&windowFunc.Spec, windowFunc.F)
+		frame, err := b.buildWindowFunctionFrame(spec, orderBy)
+		if err != nil {
+			return nil, nil, err
+		}
+		desc, err := aggregation.NewWindowFuncDesc(b.ctx, windowFunc.F, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		if desc == nil {
+			return nil, nil, ErrWrongArguments.GenWithStackByArgs(strings.ToLower(windowFunc.F))
+		}
+
+		window := LogicalWindow{
+			WindowFuncDescs: []*aggregation.WindowFuncDesc{desc},
+			PartitionBy:     partitionBy,
+			OrderBy:         orderBy,
+			Frame:           frame,
+		}.Init(b.ctx)
+		schema := np.Schema().Clone()
+		windowMap[windowFunc] = schema.Len()
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  desc.RetTp,
+		})
+		window.names = make([]*types.FieldName, np.Schema().Len(), schema.Len())
+		copy(window.names, np.OutputNames())
+		window.names = append(window.names, types.EmptyName)
+		window.SetChildren(np)
+		window.SetSchema(schema)
+		p = window
+	}
+	return p, windowMap, nil
+}
@@ -81,6 +81,30 @@ func (s *testPlanSuite) TestPredicatePushDown(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestSubquery(c *C) {
+	defer testleak.AfterTest(c)()
+	var input, output []string
+	s.testData.GetTestCases(c, &input, &output)
+	ctx := context.Background()
+	for ith, ca := range input {
+		comment := Commentf("for %s", ca)
+		stmt, err := s.ParseOneStmt(ca, "", "")
+		c.Assert(err, IsNil, comment)
+
+		Preprocess(s.ctx, stmt, s.is)
+		p, _, err := BuildLogicalPlan(ctx, s.ctx, stmt, s.is)
+		c.Assert(err, IsNil)
+		if lp, ok := p.(LogicalPlan); ok {
+			p, err = logicalOptimize(context.TODO(), flagBuildKeyInfo|flagDecorrelate|flagPrunColumns, lp)
+			c.Assert(err, IsNil)
+		}
+		s.testData.OnRecord(func() {
+			output[ith] = ToString(p)
+		})
+		c.Assert(ToString(p), Equals, output[ith], Commentf("for %s %d", ca, ith))
+	}
+}
+
 func (s *testPlanSuite) TestJoinPredicatePushDown(c *C) {
 	defer testleak.AfterTest(c)()
 	var (
@@ -202,6 +226,48 @@ func (s *testPlanSuite) TestSimplifyOuterJoin(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestSimplifyNestedOuterJoin(c *C) {
+	defer testleak.AfterTest(c)()
+	var (
+		input  []string
+		output []struct {
+			Best      string
+			JoinTypes []string
+		}
+	)
+	s.testData.GetTestCases(c, &input, &output)
+
+	ctx := context.Background()
+	for i, ca := range input {
+		comment := Commentf("for %s", ca)
+		stmt, err := s.ParseOneStmt(ca, "", "")
+		c.Assert(err, IsNil, comment)
+		p, _, err := BuildLogicalPlan(ctx, s.ctx, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		p, err = logicalOptimize(context.TODO(), flagPredicatePushDown|flagPrunColumns, p.(LogicalPlan))
+		c.Assert(err, IsNil, comment)
+		planString := ToString(p)
+		joinTypes := collectJoinTypes(p.(LogicalPlan), nil)
+		s.testData.OnRecord(func() {
+			output[i].Best = planString
+			output[i].JoinTypes = joinTypes
+		})
+		c.Assert(planString, Equals, output[i].Best, comment)
+		c.Assert(joinTypes, DeepEquals, output[i].JoinTypes, comment)
+	}
+}
+
+// collectJoinTypes collects the join types of the joins in the plan in pre-order.
+func collectJoinTypes(p LogicalPlan, joinTypes []string) []string {
+	if join, ok := p.(*LogicalJoin); ok {
+		joinTypes = append(joinTypes, join.JoinType.String())
+	}
+	for _, child := range p.Children() {
+		joinTypes = collectJoinTypes(child, joinTypes)
+	}
+	return joinTypes
+}
+
 func (s *testPlanSuite) TestDeriveNotNullConds(c *C) {
 	defer testleak.AfterTest(c)()
 	var (
@@ -265,6 +331,35 @@ func (s *testPlanSuite) TestPlanBuilder(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestWindowFunction(c *C) {
+	defer testleak.AfterTest(c)()
+	var input, output []string
+	s.testData.GetTestCases(c, &input, &output)
+	ctx := context.Background()
+	for i, ca := range input {
+		comment := Commentf("for %s", ca)
+		stmt, err := s.ParseOneStmt(ca, "", "")
+		c.Assert(err, IsNil, comment)
+
+		Preprocess(s.ctx, stmt, s.is)
+		builder := NewPlanBuilder(MockContext(), s.is)
+		p, err := builder.Build(ctx, stmt)
+		if err == nil {
+			p, err = DoOptimize(ctx, builder.optFlag, p.(LogicalPlan))
+		}
+		var result string
+		if err != nil {
+			result = err.Error()
+		} else {
+			result = ToString(p)
+		}
+		s.testData.OnRecord(func() {
+			output[i] = result
+		})
+		c.Assert(result, Equals, output[i], comment)
+	}
+}
+
 func (s *testPlanSuite) TestJoinReOrder(c *C) {
 	defer testleak.AfterTest(c)()
 	var input, output []string
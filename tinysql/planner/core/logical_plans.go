@@ -43,6 +43,10 @@ var (
 	_ LogicalPlan = &LogicalIndexScan{}
 	_ LogicalPlan = &LogicalSort{}
 	_ LogicalPlan = &LogicalLimit{}
+	_ LogicalPlan = &LogicalApply{}
+	_ LogicalPlan = &LogicalMaxOneRow{}
+	_ LogicalPlan = &LogicalUnionAll{}
+	_ LogicalPlan = &LogicalWindow{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -55,6 +59,14 @@ const (
 	LeftOuterJoin
 	// RightOuterJoin means right join.
 	RightOuterJoin
+	// SemiJoin means if row a in table A matches some rows in B, just output a.
+	SemiJoin
+	// AntiSemiJoin means if row a in table A does not match any row in B, then output a.
+	AntiSemiJoin
+	// LeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, true), otherwise, output (a, false).
+	LeftOuterSemiJoin
+	// AntiLeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, false), otherwise, output (a, true).
+	AntiLeftOuterSemiJoin
 )
 
 // IsOuterJoin returns if this joiner is a outer joiner
@@ -70,15 +82,30 @@ func (tp JoinType) String() string {
 		return "left outer join"
 	case RightOuterJoin:
 		return "right outer join"
+	case SemiJoin:
+		return "semi join"
+	case AntiSemiJoin:
+		return "anti semi join"
+	case LeftOuterSemiJoin:
+		return "left outer semi join"
+	case AntiLeftOuterSemiJoin:
+		return "anti left outer semi join"
 	}
 	return "unsupported join type"
 }
 
 const (
-	preferHashJoin uint = 1 << iota
+	preferLeftAsIndexInner uint = 1 << iota
+	preferRightAsIndexInner
+	preferHashJoin
 	preferMergeJoin
 )
 
+const (
+	preferHashAgg uint = 1 << iota
+	preferStreamAgg
+)
+
 // LogicalJoin is the logical join plan.
 type LogicalJoin struct {
 	logicalSchemaProducer
@@ -91,6 +118,9 @@ type LogicalJoin struct {
 	// hintInfo stores the join algorithm hint information specified by client.
 	hintInfo       *tableHintInfo
 	preferJoinType uint
+	// leadingHint stores the join order specified by the LEADING hint, it is
+	// applied by the join reorder rule.
+	leadingHint *leadingHintInfo
 
 	EqualConditions []*expression.ScalarFunction
 	LeftConditions  expression.CNFExprs
@@ -112,6 +142,52 @@ type LogicalJoin struct {
 	equalCondOutCnt float64
 }
 
+// PreferMergeJoin returns whether the merge join is specified by hints.
+func (p *LogicalJoin) PreferMergeJoin() bool {
+	return p.preferJoinType&preferMergeJoin > 0
+}
+
+// PreferIndexJoin returns whether the index join is specified by hints.
+func (p *LogicalJoin) PreferIndexJoin() bool {
+	return p.preferJoinType&(preferLeftAsIndexInner|preferRightAsIndexInner) > 0
+}
+
+// PreferIndexJoinInner returns whether the child of innerIdx is specified as
+// the inner side of the index join by hints.
+func (p *LogicalJoin) PreferIndexJoinInner(innerIdx int) bool {
+	if innerIdx == 0 {
+		return p.preferJoinType&preferLeftAsIndexInner > 0
+	}
+	return p.preferJoinType&preferRightAsIndexInner > 0
+}
+
+// PreferHashJoin returns whether the hash join is specified by hints.
+func (p *LogicalJoin) PreferHashJoin() bool {
+	return p.preferJoinType&preferHashJoin > 0
+}
+
+// ResetIndexJoinHint removes the index join hint of the join, and appends a
+// warning. It's called when the hinted index join can't be built.
+func (p *LogicalJoin) ResetIndexJoinHint() {
+	errMsg := "Optimizer Hint INL_JOIN or TIDB_INLJ is inapplicable"
+	warning := ErrInternal.GenWithStack(errMsg)
+	p.ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
+	p.preferJoinType &^= preferLeftAsIndexInner | preferRightAsIndexInner
+}
+
+// IsReorderable returns whether the join can be reordered. The join order of
+// a join is fixed if it's not an inner join, or it's specified by the
+// STRAIGHT_JOIN, LEADING or join algorithm hints.
+func (p *LogicalJoin) IsReorderable() bool {
+	return p.JoinType == InnerJoin && !p.StraightJoin && !p.reordered && p.preferJoinType == 0
+}
+
+// Shallow shallow copies a LogicalJoin struct.
+func (p *LogicalJoin) Shallow() *LogicalJoin {
+	join := *p
+	return join.Init(p.ctx)
+}
+
 func (p *LogicalJoin) attachOnConds(onConds []expression.Expression) {
 	eq, left, right, other := p.extractOnCondition(onConds, false, false)
 	p.EqualConditions = append(eq, p.EqualConditions...)
@@ -120,11 +196,90 @@ func (p *LogicalJoin) attachOnConds(onConds []expression.Expression) {
 	p.OtherConditions = append(other, p.OtherConditions...)
 }
 
+func (p *LogicalJoin) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, fun := range p.EqualConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.LeftConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.RightConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.OtherConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	return corCols
+}
+
+func (p *LogicalJoin) columnSubstitute(schema *expression.Schema, exprs []expression.Expression) {
+	for i, cond := range p.LeftConditions {
+		p.LeftConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+
+	for i, cond := range p.RightConditions {
+		p.RightConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+
+	for i, cond := range p.OtherConditions {
+		p.OtherConditions[i] = expression.ColumnSubstitute(cond, schema, exprs)
+	}
+
+	for i := len(p.EqualConditions) - 1; i >= 0; i-- {
+		newCond := expression.ColumnSubstitute(p.EqualConditions[i], schema, exprs).(*expression.ScalarFunction)
+
+		// If the columns used in the new filter all come from the left child,
+		// we can push this filter to it.
+		if expression.ExprFromSchema(newCond, p.children[0].Schema()) {
+			p.LeftConditions = append(p.LeftConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		// If the columns used in the new filter all come from the right
+		// child, we can push this filter to it.
+		if expression.ExprFromSchema(newCond, p.children[1].Schema()) {
+			p.RightConditions = append(p.RightConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		_, lhsIsCol := newCond.GetArgs()[0].(*expression.Column)
+		_, rhsIsCol := newCond.GetArgs()[1].(*expression.Column)
+
+		// If the columns used in the new filter are not all expression.Column,
+		// we can not use it as join's equal condition.
+		if !(lhsIsCol && rhsIsCol) {
+			p.OtherConditions = append(p.OtherConditions, newCond)
+			p.EqualConditions = append(p.EqualConditions[:i], p.EqualConditions[i+1:]...)
+			continue
+		}
+
+		p.EqualConditions[i] = newCond
+	}
+}
+
 // LogicalProjection represents a select fields plan.
 type LogicalProjection struct {
 	logicalSchemaProducer
 
 	Exprs []expression.Expression
+
+	// AvoidColumnEvaluator is a temporary variable which is ONLY used to avoid
+	// building columnEvaluator for the expressions of Projection which is
+	// built by buildProjection4Union.
+	// This can be removed after column pool being supported.
+	// Related issue: TiDB#8141(https://github.com/pingcap/tidb/issues/8141)
+	AvoidColumnEvaluator bool
+}
+
+func (p *LogicalProjection) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, expr := range p.Exprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
 }
 
 // LogicalAggregation represents an aggregate plan.
@@ -136,10 +291,46 @@ type LogicalAggregation struct {
 	// groupByCols stores the columns that are group-by items.
 	groupByCols []*expression.Column
 
+	// preferAggType stores the aggregation algorithm hint specified by client.
+	preferAggType uint
+
 	possibleProperties [][]*expression.Column
 	inputCount         float64 // inputCount is the input count of this plan.
 }
 
+// CopyAggHints copies the aggregation hints from another LogicalAggregation.
+func (la *LogicalAggregation) CopyAggHints(agg *LogicalAggregation) {
+	la.preferAggType = agg.preferAggType
+}
+
+// ResetHintIfConflicted resets the aggregation hints to false if they are
+// conflicted, and returns the two preferred types.
+func (la *LogicalAggregation) ResetHintIfConflicted() (preferHash bool, preferStream bool) {
+	preferHash = la.preferAggType&preferHashAgg > 0
+	preferStream = la.preferAggType&preferStreamAgg > 0
+	if preferHash && preferStream {
+		errMsg := "Optimizer aggregation hints are conflicted"
+		warning := ErrInternal.GenWithStack(errMsg)
+		la.ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
+		la.preferAggType = 0
+		preferHash, preferStream = false, false
+	}
+	return
+}
+
+func (la *LogicalAggregation) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.baseLogicalPlan.extractCorrelatedCols()
+	for _, expr := range la.GroupByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	for _, fun := range la.AggFuncs {
+		for _, arg := range fun.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+	}
+	return corCols
+}
+
 // IsPartialModeAgg returns if all of the AggFuncs are partialMode.
 func (la *LogicalAggregation) IsPartialModeAgg() bool {
 	// Since all of the AggFunc share the same AggMode, we only need to check the first one.
@@ -166,6 +357,36 @@ type LogicalSelection struct {
 	Conditions []expression.Expression
 }
 
+func (p *LogicalSelection) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, cond := range p.Conditions {
+		corCols = append(corCols, expression.ExtractCorColumns(cond)...)
+	}
+	return corCols
+}
+
+// LogicalApply gets one row from outer executor and gets one row from inner executor according to outer row.
+type LogicalApply struct {
+	LogicalJoin
+
+	CorCols []*expression.CorrelatedColumn
+}
+
+func (la *LogicalApply) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.LogicalJoin.extractCorrelatedCols()
+	for i := len(corCols) - 1; i >= 0; i-- {
+		if la.children[0].Schema().Contains(&corCols[i].Column) {
+			corCols = append(corCols[:i], corCols[i+1:]...)
+		}
+	}
+	return corCols
+}
+
+// LogicalMaxOneRow checks if a query returns no more than one row.
+type LogicalMaxOneRow struct {
+	baseLogicalPlan
+}
+
 // LogicalTableDual represents a dual table plan.
 type LogicalTableDual struct {
 	logicalSchemaProducer
@@ -190,6 +411,14 @@ type LogicalUnionScan struct {
 	handleCol *expression.Column
 }
 
+func (p *LogicalUnionScan) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, cond := range p.conditions {
+		corCols = append(corCols, expression.ExtractCorColumns(cond)...)
+	}
+	return corCols
+}
+
 // DataSource represents a tableScan without condition push down.
 type DataSource struct {
 	logicalSchemaProducer
@@ -203,8 +432,9 @@ type DataSource struct {
 	TableAsName *model.CIStr
 	// pushedDownConds are the conditions that will be pushed down to coprocessor.
 	pushedDownConds []expression.Expression
-	// allConds contains all the filters on this table. For now it's maintained
-	// in predicate push down and used only in partition pruning.
+	// allConds contains all the filters on this table. They are used to build
+	// the access paths, the ones not in pushedDownConds are kept in the
+	// Selection above the DataSource.
 	allConds []expression.Expression
 
 	statisticTable *statistics.Table
@@ -544,6 +774,81 @@ func (ds *DataSource) TableInfo() *model.TableInfo {
 	return ds.tableInfo
 }
 
+func (ds *DataSource) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(ds.pushedDownConds))
+	for _, expr := range ds.pushedDownConds {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
+// LogicalUnionAll represents LogicalUnionAll plan.
+type LogicalUnionAll struct {
+	logicalSchemaProducer
+}
+
+// WindowFrame represents a window function frame.
+type WindowFrame struct {
+	Type  ast.FrameType
+	Start *FrameBound
+	End   *FrameBound
+}
+
+// FrameBound is the boundary of a frame.
+type FrameBound struct {
+	Type      ast.BoundType
+	UnBounded bool
+	Num       uint64
+	// CalcFuncs is used for range framed windows.
+	// We will build the date_add or date_sub functions for frames like `INTERVAL '2:30' MINUTE_SECOND FOLLOWING`,
+	// and plus or minus for frames like `1 preceding`.
+	CalcFuncs []expression.Expression
+	// CmpFuncs is used to decide whether one row is included in the current frame.
+	CmpFuncs []expression.CompareFunc
+}
+
+// LogicalWindow represents a logical window function plan.
+type LogicalWindow struct {
+	logicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.Item
+	OrderBy         []property.Item
+	Frame           *WindowFrame
+}
+
+func (p *LogicalWindow) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, windowFunc := range p.WindowFuncDescs {
+		for _, arg := range windowFunc.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+	}
+	if p.Frame != nil {
+		for _, expr := range p.Frame.Start.CalcFuncs {
+			corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+		}
+		for _, expr := range p.Frame.End.CalcFuncs {
+			corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+		}
+	}
+	return corCols
+}
+
+// GetWindowResultColumns returns the columns storing the result of the window function.
+func (p *LogicalWindow) GetWindowResultColumns() []*expression.Column {
+	return p.schema.Columns[p.schema.Len()-len(p.WindowFuncDescs):]
+}
+
+// GetPartitionByCols extracts 'partition by' columns from the Window.
+func (p *LogicalWindow) GetPartitionByCols() []*expression.Column {
+	partitionCols := make([]*expression.Column, 0, len(p.PartitionBy))
+	for _, partitionItem := range p.PartitionBy {
+		partitionCols = append(partitionCols, partitionItem.Col)
+	}
+	return partitionCols
+}
+
 // LogicalSort stands for the order by plan.
 type LogicalSort struct {
 	baseLogicalPlan
@@ -551,6 +856,14 @@ type LogicalSort struct {
 	ByItems []*ByItems
 }
 
+func (p *LogicalSort) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := p.baseLogicalPlan.extractCorrelatedCols()
+	for _, item := range p.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // LogicalTopN represents a top-n plan.
 type LogicalTopN struct {
 	baseLogicalPlan
@@ -565,6 +878,14 @@ func (lt *LogicalTopN) isLimit() bool {
 	return len(lt.ByItems) == 0
 }
 
+func (lt *LogicalTopN) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := lt.baseLogicalPlan.extractCorrelatedCols()
+	for _, item := range lt.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // LogicalLimit represents offset and limit plan.
 type LogicalLimit struct {
 	baseLogicalPlan
@@ -599,3 +920,32 @@ type LogicalShowDDLJobs struct {
 
 	JobNumber int64
 }
+
+// extractCorColumnsBySchema only extracts the correlated columns that match the specified schema.
+// e.g. If the correlated columns from plan are [t1.a, t2.a, t3.a] and specified schema is [t2.a, t2.b, t2.c],
+// only [t2.a] is returned.
+func extractCorColumnsBySchema(p LogicalPlan, schema *expression.Schema) []*expression.CorrelatedColumn {
+	corCols := p.extractCorrelatedCols()
+	resultCorCols := make([]*expression.CorrelatedColumn, schema.Len())
+	for _, corCol := range corCols {
+		idx := schema.ColumnIndex(&corCol.Column)
+		if idx != -1 {
+			if resultCorCols[idx] == nil {
+				resultCorCols[idx] = &expression.CorrelatedColumn{
+					Column: *schema.Columns[idx],
+					Data:   new(types.Datum),
+				}
+			}
+			corCol.Data = resultCorCols[idx].Data
+		}
+	}
+	// Shrink slice. e.g. [col1, nil, col2, nil] will be changed to [col1, col2].
+	length := 0
+	for _, col := range resultCorCols {
+		if col != nil {
+			resultCorCols[length] = col
+			length++
+		}
+	}
+	return resultCorCols[:length]
+}
@@ -31,6 +31,7 @@ var OptimizeAstNode func(ctx context.Context, sctx sessionctx.Context, node ast.
 const (
 	flagPrunColumns uint64 = 1 << iota
 	flagBuildKeyInfo
+	flagDecorrelate
 	flagEliminateAgg
 	flagEliminateProjection
 	flagMaxMinEliminate
@@ -44,6 +45,7 @@ const (
 var optRuleList = []logicalOptRule{
 	&columnPruner{},
 	&buildKeySolver{},
+	&decorrelateSolver{},
 	&aggregationEliminator{},
 	&projectionEliminator{},
 	&maxMinEliminator{},
@@ -94,6 +96,7 @@ func postOptimize(plan PhysicalPlan) PhysicalPlan {
 
 func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (LogicalPlan, error) {
 	var err error
+	tracer := logic.SCtx().GetSessionVars().StmtCtx.OptimizeTracer
 	for i, rule := range optRuleList {
 		// The order of flags is same as the order of optRule in the list.
 		// We use a bitmask to record which opt rules should be used. If the i-th bit is 1, it means we should
@@ -101,10 +104,17 @@ func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (Logic
 		if flag&(1<<uint(i)) == 0 {
 			continue
 		}
+		var before string
+		if tracer != nil {
+			before = ToString(logic)
+		}
 		logic, err = rule.optimize(ctx, logic)
 		if err != nil {
 			return nil, err
 		}
+		if tracer != nil {
+			tracer.AppendLogicalRule(rule.name(), before, ToString(logic))
+		}
 	}
 	return logic, err
 }
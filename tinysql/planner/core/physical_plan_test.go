@@ -443,3 +443,97 @@ func (s *testPlanSuite) TestIndexHint(c *C) {
 		}
 	}
 }
+
+func (s *testPlanSuite) TestJoinHints(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	se, err := session.CreateSession4Test(store)
+	c.Assert(err, IsNil)
+	_, err = se.Execute(context.Background(), "use test")
+	c.Assert(err, IsNil)
+
+	var input []string
+	var output []struct {
+		SQL     string
+		Best    string
+		Warning []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	ctx := context.Background()
+	for i, test := range input {
+		comment := Commentf("case:%v sql:%s", i, test)
+		se.GetSessionVars().StmtCtx.SetWarnings(nil)
+
+		stmt, err := s.ParseOneStmt(test, "", "")
+		c.Assert(err, IsNil, comment)
+
+		p, _, err := planner.Optimize(ctx, se, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		warnings := se.GetSessionVars().StmtCtx.GetWarnings()
+		s.testData.OnRecord(func() {
+			output[i].SQL = test
+			output[i].Best = core.ToString(p)
+			output[i].Warning = nil
+			for _, warning := range warnings {
+				output[i].Warning = append(output[i].Warning, warning.Err.Error())
+			}
+		})
+		c.Assert(core.ToString(p), Equals, output[i].Best, comment)
+		c.Assert(warnings, HasLen, len(output[i].Warning), comment)
+		for j, warning := range warnings {
+			c.Assert(warning.Err.Error(), Equals, output[i].Warning[j], comment)
+		}
+	}
+}
+
+func (s *testPlanSuite) TestAggregationHints(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	se, err := session.CreateSession4Test(store)
+	c.Assert(err, IsNil)
+	_, err = se.Execute(context.Background(), "use test")
+	c.Assert(err, IsNil)
+
+	var input []string
+	var output []struct {
+		SQL     string
+		Best    string
+		Warning []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	ctx := context.Background()
+	for i, test := range input {
+		comment := Commentf("case:%v sql:%s", i, test)
+		se.GetSessionVars().StmtCtx.SetWarnings(nil)
+
+		stmt, err := s.ParseOneStmt(test, "", "")
+		c.Assert(err, IsNil, comment)
+
+		p, _, err := planner.Optimize(ctx, se, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		warnings := se.GetSessionVars().StmtCtx.GetWarnings()
+		s.testData.OnRecord(func() {
+			output[i].SQL = test
+			output[i].Best = core.ToString(p)
+			output[i].Warning = nil
+			for _, warning := range warnings {
+				output[i].Warning = append(output[i].Warning, warning.Err.Error())
+			}
+		})
+		c.Assert(core.ToString(p), Equals, output[i].Best, comment)
+		c.Assert(warnings, HasLen, len(output[i].Warning), comment)
+		for j, warning := range warnings {
+			c.Assert(warning.Err.Error(), Equals, output[i].Warning[j], comment)
+		}
+	}
+}
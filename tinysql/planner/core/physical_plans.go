@@ -37,9 +37,15 @@ var (
 	_ PhysicalPlan = &PhysicalIndexReader{}
 	_ PhysicalPlan = &PhysicalIndexLookUpReader{}
 	_ PhysicalPlan = &PhysicalHashAgg{}
+	_ PhysicalPlan = &PhysicalStreamAgg{}
 	_ PhysicalPlan = &PhysicalHashJoin{}
 	_ PhysicalPlan = &PhysicalMergeJoin{}
+	_ PhysicalPlan = &PhysicalIndexJoin{}
 	_ PhysicalPlan = &PhysicalUnionScan{}
+	_ PhysicalPlan = &PhysicalApply{}
+	_ PhysicalPlan = &PhysicalMaxOneRow{}
+	_ PhysicalPlan = &PhysicalUnionAll{}
+	_ PhysicalPlan = &PhysicalWindow{}
 )
 
 // PhysicalTableReader is the table reader in tidb.
@@ -92,7 +98,7 @@ func (p *PhysicalIndexReader) SetSchema(_ *expression.Schema) {
 	if p.indexPlan != nil {
 		p.IndexPlans = flattenPushDownPlan(p.indexPlan)
 		switch p.indexPlan.(type) {
-		case *PhysicalHashAgg:
+		case *PhysicalHashAgg, *PhysicalStreamAgg:
 			p.schema = p.indexPlan.Schema()
 		default:
 			is := p.IndexPlans[0].(*PhysicalIndexScan)
@@ -137,6 +143,10 @@ type PhysicalIndexScan struct {
 	Columns    []*model.ColumnInfo
 	DBName     model.CIStr
 
+	// rangeInfo describes how the ranges are decided by the outer rows of an
+	// index join, it is only used for explain.
+	rangeInfo string
+
 	TableAsName *model.CIStr
 
 	// dataSourceSchema is the original schema of DataSource. The schema of index scan in KV and index reader in TiDB
@@ -173,6 +183,10 @@ type PhysicalTableScan struct {
 	Ranges  []*ranger.Range
 	pkCol   *expression.Column
 
+	// rangeDecidedBy indicates that the ranges are decided by the outer rows
+	// of an index join, it is only used for explain.
+	rangeDecidedBy []*expression.Column
+
 	TableAsName *model.CIStr
 
 	HandleIdx int
@@ -186,7 +200,8 @@ type PhysicalTableScan struct {
 type PhysicalProjection struct {
 	physicalSchemaProducer
 
-	Exprs []expression.Expression
+	Exprs                []expression.Expression
+	AvoidColumnEvaluator bool
 }
 
 // PhysicalTopN is the physical operator of topN.
@@ -243,6 +258,33 @@ func NewPhysicalHashJoin(p *LogicalJoin, innerIdx int, newStats *property.StatsI
 	return hashJoin
 }
 
+// PhysicalIndexJoin represents the plan of index look up join.
+type PhysicalIndexJoin struct {
+	basePhysicalJoin
+
+	innerTask task
+
+	// Ranges stores the IndexRanges when the inner plan is index scan.
+	Ranges []*ranger.Range
+	// KeyOff2IdxOff maps the offsets in join key to the offsets in the index.
+	KeyOff2IdxOff []int
+	// IdxColLens stores the length of each index column.
+	IdxColLens []int
+}
+
+// GetInnerPlan returns the inner child plan built by the index join, and the
+// row count and cost of a single lookup on it.
+func (p *PhysicalIndexJoin) GetInnerPlan() (plan PhysicalPlan, count float64, cost float64) {
+	return p.innerTask.plan(), p.innerTask.count(), p.innerTask.cost()
+}
+
+// PhysicalApply represents apply plan, only used for subquery.
+type PhysicalApply struct {
+	PhysicalHashJoin
+
+	OuterSchema []*expression.CorrelatedColumn
+}
+
 // PhysicalMergeJoin represents merge join implementation of LogicalJoin.
 type PhysicalMergeJoin struct {
 	basePhysicalJoin
@@ -258,6 +300,11 @@ type PhysicalLimit struct {
 	Count  uint64
 }
 
+// PhysicalUnionAll is the physical operator of UnionAll.
+type PhysicalUnionAll struct {
+	physicalSchemaProducer
+}
+
 type basePhysicalAgg struct {
 	physicalSchemaProducer
 
@@ -294,6 +341,20 @@ func NewPhysicalHashAgg(la *LogicalAggregation, newStats *property.StatsInfo, pr
 	return agg
 }
 
+// PhysicalStreamAgg is stream operator of aggregate.
+type PhysicalStreamAgg struct {
+	basePhysicalAgg
+}
+
+// NewPhysicalStreamAgg creates a new PhysicalStreamAgg from a LogicalAggregation.
+func NewPhysicalStreamAgg(la *LogicalAggregation, newStats *property.StatsInfo, prop *property.PhysicalProperty) *PhysicalStreamAgg {
+	agg := basePhysicalAgg{
+		GroupByItems: la.GroupByItems,
+		AggFuncs:     la.AggFuncs,
+	}.initForStream(la.ctx, newStats, prop)
+	return agg
+}
+
 // PhysicalSort is the physical operator of sort, which implements a memory sort.
 type PhysicalSort struct {
 	basePhysicalPlan
@@ -307,6 +368,16 @@ type NominalSort struct {
 	basePhysicalPlan
 }
 
+// PhysicalWindow is the physical operator of window function.
+type PhysicalWindow struct {
+	physicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.Item
+	OrderBy         []property.Item
+	Frame           *WindowFrame
+}
+
 // PhysicalUnionScan represents a union scan operator.
 type PhysicalUnionScan struct {
 	basePhysicalPlan
@@ -331,6 +402,11 @@ type PhysicalSelection struct {
 	Conditions []expression.Expression
 }
 
+// PhysicalMaxOneRow is the physical operator of maxOneRow.
+type PhysicalMaxOneRow struct {
+	basePhysicalPlan
+}
+
 // PhysicalTableDual is the physical operator of dual.
 type PhysicalTableDual struct {
 	physicalSchemaProducer
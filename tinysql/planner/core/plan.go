@@ -124,6 +124,12 @@ type LogicalPlan interface {
 
 	// SetChild sets the ith child for the plan.
 	SetChild(i int, child LogicalPlan)
+
+	// extractCorrelatedCols extracts correlated columns inside the LogicalPlan.
+	extractCorrelatedCols() []*expression.CorrelatedColumn
+
+	// MaxOneRow means whether this operator only returns max one row.
+	MaxOneRow() bool
 }
 
 // PhysicalPlan is a tree of the physical operators.
@@ -165,9 +171,16 @@ type PhysicalPlan interface {
 type baseLogicalPlan struct {
 	basePlan
 
-	taskMap  map[string]task
-	self     LogicalPlan
-	children []LogicalPlan
+	taskMap map[string]task
+	// maxOneRow is set by BuildKeyInfo and means the plan outputs at most one row.
+	maxOneRow bool
+	self      LogicalPlan
+	children  []LogicalPlan
+}
+
+// MaxOneRow implements the LogicalPlan interface.
+func (p *baseLogicalPlan) MaxOneRow() bool {
+	return p.maxOneRow
 }
 
 // ExplainInfo implements Plan interface.
@@ -207,13 +220,49 @@ func (p *baseLogicalPlan) storeTask(prop *property.PhysicalProperty, task task)
 	p.taskMap[string(key)] = task
 }
 
+func (p *baseLogicalPlan) extractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.children))
+	for _, child := range p.children {
+		corCols = append(corCols, child.extractCorrelatedCols()...)
+	}
+	return corCols
+}
+
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *baseLogicalPlan) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	childMaxOneRow := make([]bool, len(p.children))
+	for i := range p.children {
+		childMaxOneRow[i] = p.children[i].MaxOneRow()
+	}
+	p.maxOneRow = HasMaxOneRow(p.self, childMaxOneRow)
+}
+
+// HasMaxOneRow returns if the LogicalPlan will output at most one row.
+func HasMaxOneRow(p LogicalPlan, childMaxOneRow []bool) bool {
+	if len(childMaxOneRow) == 0 {
+		// The reason why we use this check is that, this function
+		// is used both in planner/core and planner/cascades.
+		// In cascades planner, LogicalPlan may have no `children`.
+		return false
+	}
+	switch x := p.(type) {
+	case *LogicalLimit, *LogicalTopN, *LogicalSelection, *LogicalProjection, *LogicalSort, *LogicalMaxOneRow:
+		return childMaxOneRow[0]
+	case *LogicalJoin:
+		switch x.JoinType {
+		case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+			return childMaxOneRow[0]
+		default:
+			return childMaxOneRow[0] && childMaxOneRow[1]
+		}
+	}
+	return false
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *logicalSchemaProducer) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	selfSchema.Keys = nil
+	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
 }
 
 func newBasePlan(ctx sessionctx.Context, tp string) basePlan {
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
@@ -32,12 +33,29 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/execdetails"
 )
 
 type tableHintInfo struct {
-	sortMergeJoinTables []hintTableInfo
-	hashJoinTables      []hintTableInfo
-	indexHintList       []indexHintInfo
+	indexNestedLoopJoinTables []hintTableInfo
+	sortMergeJoinTables       []hintTableInfo
+	hashJoinTables            []hintTableInfo
+	indexHintList             []indexHintInfo
+	leadingHint               *leadingHintInfo
+	aggHints                  aggHintInfo
+}
+
+// leadingHintInfo stores the join order specified by the LEADING hint.
+type leadingHintInfo struct {
+	tables []hintTableInfo
+	// handled indicates whether the hint has been considered by the join
+	// reorder, the hint is only applied to the first join group it meets.
+	handled bool
+}
+
+type aggHintInfo struct {
+	preferAggType uint
+	matched       bool
 }
 
 type hintTableInfo struct {
@@ -50,6 +68,14 @@ type indexHintInfo struct {
 	dbName    model.CIStr
 	tblName   model.CIStr
 	indexHint *ast.IndexHint
+	matched   bool
+}
+
+func (hint *indexHintInfo) hintName() string {
+	if hint.indexHint.HintType == ast.HintIgnore {
+		return HintIgnoreIndex
+	}
+	return HintUseIndex
 }
 
 func tableNames2HintTableInfo(ctx sessionctx.Context, hintTables []ast.HintTable) []hintTableInfo {
@@ -76,6 +102,10 @@ func (info *tableHintInfo) ifPreferHashJoin(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.hashJoinTables)
 }
 
+func (info *tableHintInfo) ifPreferINLJ(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.indexNestedLoopJoinTables)
+}
+
 // matchTableName checks whether the hint hit the need.
 // Only need either side matches one on the list.
 // Even though you can put 2 tables on the list,
@@ -184,6 +214,11 @@ type PlanBuilder struct {
 	//   If it's a join, we pop its children's out then merge them and push the new map to stack.
 	//   If we meet a subquery, it's clearly that it's a independent problem so we just pop one map out when we finish building the subquery.
 	handleHelper *handleColHelper
+
+	// outerSchemas and outerNames hold the schemas and names of the outer
+	// queries, they're used to resolve correlated columns in subqueries.
+	outerSchemas []*expression.Schema
+	outerNames   [][]*types.FieldName
 }
 
 type handleColHelper struct {
@@ -245,20 +280,36 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	switch x := node.(type) {
 	case *ast.AdminStmt:
 		return b.buildAdmin(ctx, x)
+	case *ast.DeallocateStmt:
+		return &Deallocate{Name: x.Name}, nil
 	case *ast.DeleteStmt:
 		return b.buildDelete(ctx, x)
+	case *ast.ExecuteStmt:
+		return b.buildExecute(ctx, x)
 	case *ast.ExplainStmt:
 		return b.buildExplain(ctx, x)
 	case *ast.InsertStmt:
 		return b.buildInsert(ctx, x)
+	case *ast.LoadDataStmt:
+		return b.buildLoadData(ctx, x)
+	case *ast.PrepareStmt:
+		return b.buildPrepare(x), nil
 	case *ast.SelectStmt:
 		return b.buildSelect(ctx, x)
+	case *ast.SetOprStmt:
+		return b.buildSetOpr(ctx, x)
+	case *ast.UpdateStmt:
+		return b.buildUpdate(ctx, x)
 	case *ast.ShowStmt:
 		return b.buildShow(ctx, x)
 	case *ast.SetStmt:
 		return b.buildSet(ctx, x)
 	case *ast.AnalyzeTableStmt:
 		return b.buildAnalyze(x)
+	case *ast.CreateBindingStmt:
+		return b.buildCreateBindPlan(x)
+	case *ast.DropBindingStmt:
+		return b.buildDropBindPlan(x)
 	case *ast.UseStmt, *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt:
 		return b.buildSimple(node.(ast.StmtNode))
 	case ast.DDLNode:
@@ -267,6 +318,68 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	return nil, ErrUnsupportedType.GenWithStack("Unsupported type %T", node)
 }
 
+func (b *PlanBuilder) buildExecute(ctx context.Context, v *ast.ExecuteStmt) (Plan, error) {
+	vars := make([]expression.Expression, 0, len(v.UsingVars))
+	for _, expr := range v.UsingVars {
+		newExpr, _, err := b.rewrite(ctx, expr, nil, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, newExpr)
+	}
+	exe := &Execute{Name: v.Name, UsingVars: vars, ExecID: v.ExecID}
+	if v.BinaryArgs != nil {
+		exe.PrepareParams = v.BinaryArgs.([]types.Datum)
+	}
+	return exe, nil
+}
+
+func (b *PlanBuilder) buildDropBindPlan(v *ast.DropBindingStmt) (Plan, error) {
+	p := &SQLBindPlan{
+		SQLBindOp:    OpSQLBindDrop,
+		NormdOrigSQL: parser.NormalizeForBinding(v.OriginSel.Text()),
+		IsGlobal:     v.GlobalScope,
+		Db:           b.ctx.GetSessionVars().CurrentDB,
+	}
+	return p, nil
+}
+
+func (b *PlanBuilder) buildCreateBindPlan(v *ast.CreateBindingStmt) (Plan, error) {
+	originSQL := parser.NormalizeForBinding(v.OriginSel.Text())
+	hintedSQL := parser.NormalizeForBinding(v.HintedSel.Text())
+	if originSQL != hintedSQL {
+		return nil, errors.Errorf("hinted sql and original sql don't match when hinted sql erase the hint info, after erase hint info, originSQL:%s, hintedSQL:%s", originSQL, hintedSQL)
+	}
+	p := &SQLBindPlan{
+		SQLBindOp:    OpSQLBindCreate,
+		NormdOrigSQL: originSQL,
+		BindSQL:      v.HintedSel.Text(),
+		IsGlobal:     v.GlobalScope,
+		Db:           b.ctx.GetSessionVars().CurrentDB,
+	}
+	return p, nil
+}
+
+func (b *PlanBuilder) buildPrepare(x *ast.PrepareStmt) Plan {
+	p := &Prepare{
+		Name: x.Name,
+	}
+	if x.SQLVar != nil {
+		sessionVars := b.ctx.GetSessionVars()
+		sessionVars.UsersLock.RLock()
+		v, ok := sessionVars.Users[strings.ToLower(x.SQLVar.Name)]
+		sessionVars.UsersLock.RUnlock()
+		if ok {
+			p.SQLText = v
+		} else {
+			p.SQLText = "NULL"
+		}
+	} else {
+		p.SQLText = x.SQLText
+	}
+	return p
+}
+
 func (b *PlanBuilder) buildSet(ctx context.Context, v *ast.SetStmt) (Plan, error) {
 	p := &Set{}
 	for _, vars := range v.Variables {
@@ -319,6 +432,23 @@ func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	return false
 }
 
+// detectSelectWindow detects a window function in select fields or ORDER BY clause.
+func (b *PlanBuilder) detectSelectWindow(sel *ast.SelectStmt) bool {
+	for _, f := range sel.Fields.Fields {
+		if ast.HasWindowFlag(f.Expr) {
+			return true
+		}
+	}
+	if sel.OrderBy != nil {
+		for _, item := range sel.OrderBy.Items {
+			if ast.HasWindowFlag(item.Expr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func getPathByIndexName(paths []*util.AccessPath, idxName model.CIStr, tblInfo *model.TableInfo) *util.AccessPath {
 	var tablePath *util.AccessPath
 	for _, path := range paths {
@@ -357,9 +487,10 @@ func (b *PlanBuilder) getPossibleAccessPaths(indexHints []*ast.IndexHint, tbl ta
 	// Extract comment-style index hint like /*+ INDEX(t, idx1, idx2) */.
 	indexHintsLen := len(indexHints)
 	if hints := b.TableHints(); hints != nil {
-		for _, hint := range hints.indexHintList {
+		for i, hint := range hints.indexHintList {
 			if hint.dbName.L == dbName.L && hint.tblName.L == tblName.L {
 				indexHints = append(indexHints, hint.indexHint)
+				hints.indexHintList[i].matched = true
 			}
 		}
 	}
@@ -735,6 +866,14 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 	}
 
+	if len(insert.OnDuplicate) > 0 {
+		// Branch for `INSERT ... ON DUPLICATE KEY UPDATE ...`.
+		err := b.buildOnDuplicateOfInsert(ctx, insert, insertPlan, mockTablePlan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err := insertPlan.ResolveIndices()
 	return insertPlan, err
 }
@@ -783,7 +922,7 @@ func (b *PlanBuilder) buildSetValuesOfInsert(ctx context.Context, insert *ast.In
 		if defaultExpr != nil {
 			defaultExpr.Name = assign.Column
 		}
-		expr, _, err := b.rewriteWithPreprocess(ctx, assign.Expr, mockTablePlan, nil, true, checkRefColumn)
+		expr, _, err := b.rewriteWithPreprocess(ctx, assign.Expr, mockTablePlan, nil, nil, true, checkRefColumn)
 		if err != nil {
 			return err
 		}
@@ -801,6 +940,51 @@ func (b *PlanBuilder) buildSetValuesOfInsert(ctx context.Context, insert *ast.In
 	return nil
 }
 
+func (b *PlanBuilder) buildLoadData(ctx context.Context, ld *ast.LoadDataStmt) (Plan, error) {
+	p := &LoadData{
+		IsLocal:     ld.IsLocal,
+		OnDuplicate: ld.OnDuplicate,
+		Path:        ld.Path,
+		Table:       ld.Table,
+		Columns:     ld.Columns,
+		FieldsInfo:  ld.FieldsInfo,
+		LinesInfo:   ld.LinesInfo,
+		IgnoreLines: ld.IgnoreLines,
+	}
+	tableInfo := p.Table.TableInfo
+	if _, ok := b.is.TableByID(tableInfo.ID); !ok {
+		return nil, errors.Errorf("Can't get table %s.", tableInfo.Name.O)
+	}
+	return p, nil
+}
+
+func (b *PlanBuilder) buildOnDuplicateOfInsert(ctx context.Context, insert *ast.InsertStmt, insertPlan *Insert, mockTablePlan *LogicalTableDual) error {
+	for _, assign := range insert.OnDuplicate {
+		// Check whether the column to be updated exists in the table.
+		idx, err := expression.FindFieldName(insertPlan.tableColNames, assign.Column)
+		if err != nil {
+			return err
+		}
+		if idx < 0 {
+			return ErrUnknownColumn.GenWithStackByArgs(assign.Column.OrigColName(), "field list")
+		}
+		defaultExpr := extractDefaultExpr(assign.Expr)
+		if defaultExpr != nil {
+			defaultExpr.Name = assign.Column
+		}
+		expr, err := b.rewriteInsertOnDuplicateUpdate(ctx, assign.Expr, mockTablePlan, insertPlan)
+		if err != nil {
+			return err
+		}
+		insertPlan.OnDuplicate = append(insertPlan.OnDuplicate, &expression.Assignment{
+			Col:     insertPlan.tableSchema.Columns[idx],
+			ColName: insertPlan.tableColNames[idx].ColName,
+			Expr:    expr,
+		})
+	}
+	return nil
+}
+
 func (b *PlanBuilder) buildValuesListOfInsert(ctx context.Context, insert *ast.InsertStmt, insertPlan *Insert, mockTablePlan *LogicalTableDual, checkRefColumn func(n ast.Node) ast.Node) error {
 	affectedValuesCols, err := b.getAffectCols(insert, insertPlan)
 	if err != nil {
@@ -845,7 +1029,7 @@ func (b *PlanBuilder) buildValuesListOfInsert(ctx context.Context, insert *ast.I
 					RetType: &x.Type,
 				}
 			default:
-				expr, _, err = b.rewriteWithPreprocess(ctx, valueItem, mockTablePlan, nil, true, checkRefColumn)
+				expr, _, err = b.rewriteWithPreprocess(ctx, valueItem, mockTablePlan, nil, nil, true, checkRefColumn)
 			}
 			if err != nil {
 				return err
@@ -911,10 +1095,11 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 	return p, nil
 }
 
-func (b *PlanBuilder) buildExplainPlan(targetPlan Plan, format string, execStmt ast.StmtNode) (Plan, error) {
+func (b *PlanBuilder) buildExplainPlan(targetPlan Plan, format string, analyze bool, execStmt ast.StmtNode) (Plan, error) {
 	p := &Explain{
 		TargetPlan: targetPlan,
 		Format:     format,
+		Analyze:    analyze,
 		ExecStmt:   execStmt,
 	}
 	p.ctx = b.ctx
@@ -929,8 +1114,11 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 	if err != nil {
 		return nil, err
 	}
+	if explain.Analyze {
+		b.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl = execdetails.NewRuntimeStatsColl()
+	}
 
-	return b.buildExplainPlan(targetPlan, explain.Format, explain.Stmt)
+	return b.buildExplainPlan(targetPlan, explain.Format, explain.Analyze, explain.Stmt)
 }
 
 func buildShowWarningsSchema() (*expression.Schema, types.NameSlice) {
@@ -962,6 +1150,13 @@ func buildShowSchema(s *ast.ShowStmt) (schema *expression.Schema, outputNames []
 		names = []string{"Table", "Create Table"}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
+	case ast.ShowBindings:
+		names = []string{"Original_sql", "Bind_sql", "Default_db", "Status", "Create_time", "Update_time"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeDatetime}
+	case ast.ShowProcessList:
+		names = []string{"Id", "User", "Host", "db", "Command", "Time", "State", "Info"}
+		ftypes = []byte{mysql.TypeLonglong, mysql.TypeVarchar, mysql.TypeVarchar,
+			mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLong, mysql.TypeVarchar, mysql.TypeString}
 	}
 
 	schema = expression.NewSchema(make([]*expression.Column, 0, len(names))...)
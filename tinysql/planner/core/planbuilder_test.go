@@ -41,6 +41,7 @@ func (s *testPlanBuilderSuite) TestShow(c *C) {
 		ast.ShowVariables,
 		ast.ShowCreateTable,
 		ast.ShowCreateDatabase,
+		ast.ShowBindings,
 	}
 	for _, tp := range tps {
 		node.Tp = tp
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -33,6 +34,11 @@ import (
 // PreprocessOpt presents optional parameters to `Preprocess` method.
 type PreprocessOpt func(*preprocessor)
 
+// InPrepare is a PreprocessOpt that indicates preprocess is executing under prepare statement.
+func InPrepare(p *preprocessor) {
+	p.flag |= inPrepare
+}
+
 // Preprocess resolves table names of the node, and checks some statements validation.
 func Preprocess(ctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema, preprocessOpt ...PreprocessOpt) error {
 	v := preprocessor{is: is, ctx: ctx, tableAliasInJoin: make([]map[string]interface{}, 0)}
@@ -50,6 +56,9 @@ const (
 	inCreateOrDropTable preprocessorFlag = 1 << iota
 	// parentIsJoin is set when visiting node's parent is join.
 	parentIsJoin
+	// inPrepare is set when visiting the statement of a prepare statement,
+	// parameter markers are only allowed there.
+	inPrepare
 )
 
 // preprocessor is an ast.Visitor that preprocess
@@ -86,6 +95,8 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.resolveShowStmt(node)
 	case *ast.Join:
 		p.checkNonUniqTableAlias(node)
+	case *ast.SetOprSelectList:
+		p.checkSetOprSelectList(node)
 	default:
 		p.flag &= ^parentIsJoin
 	}
@@ -121,11 +132,31 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 			p.tableAliasInJoin = p.tableAliasInJoin[:len(p.tableAliasInJoin)-1]
 		}
 	case *ast.FuncCallExpr:
+	case *driver.ParamMarkerExpr:
+		if p.flag&inPrepare == 0 {
+			p.err = parser.ErrSyntax.GenWithStack("syntax error, unexpected '?'")
+		}
 	}
 
 	return in, p.err == nil
 }
 
+func (p *preprocessor) checkSetOprSelectList(stmt *ast.SetOprSelectList) {
+	for _, sel := range stmt.Selects[:len(stmt.Selects)-1] {
+		if sel.IsInBraces {
+			continue
+		}
+		if sel.Limit != nil {
+			p.err = ErrWrongUsage.GenWithStackByArgs("UNION", "LIMIT")
+			return
+		}
+		if sel.OrderBy != nil {
+			p.err = ErrWrongUsage.GenWithStackByArgs("UNION", "ORDER BY")
+			return
+		}
+	}
+}
+
 func checkAutoIncrementOp(colDef *ast.ColumnDef, num int) (bool, error) {
 	var hasAutoIncrement bool
 
@@ -113,6 +113,34 @@ func (p *PhysicalHashJoin) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalApply) ResolveIndices() (err error) {
+	err = p.PhysicalHashJoin.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for i, col := range p.OuterSchema {
+		newCol, err := col.Column.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.OuterSchema[i].Column = *newCol.(*expression.Column)
+	}
+	// Resolve index for equal conditions again, because apply is different from
+	// hash join on the fact that equal conditions are evaluated against the join result,
+	// so columns from equal conditions come from merged schema of children, instead of
+	// single child's schema.
+	joinedSchema := expression.MergeSchema(p.children[0].Schema(), p.children[1].Schema())
+	for i, cond := range p.PhysicalHashJoin.EqualConditions {
+		newSf, err := cond.ResolveIndices(joinedSchema)
+		if err != nil {
+			return err
+		}
+		p.PhysicalHashJoin.EqualConditions[i] = newSf.(*expression.ScalarFunction)
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalMergeJoin) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -156,6 +184,49 @@ func (p *PhysicalMergeJoin) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalIndexJoin) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	lSchema := p.children[0].Schema()
+	rSchema := p.children[1].Schema()
+	innerChild := p.children[p.InnerChildIdx]
+	outerChild := p.children[1-p.InnerChildIdx]
+	for i := range p.InnerJoinKeys {
+		newOuterKey, err := p.OuterJoinKeys[i].ResolveIndices(outerChild.Schema())
+		if err != nil {
+			return err
+		}
+		p.OuterJoinKeys[i] = newOuterKey.(*expression.Column)
+		newInnerKey, err := p.InnerJoinKeys[i].ResolveIndices(innerChild.Schema())
+		if err != nil {
+			return err
+		}
+		p.InnerJoinKeys[i] = newInnerKey.(*expression.Column)
+	}
+	for i, expr := range p.LeftConditions {
+		p.LeftConditions[i], err = expr.ResolveIndices(lSchema)
+		if err != nil {
+			return err
+		}
+	}
+	for i, expr := range p.RightConditions {
+		p.RightConditions[i], err = expr.ResolveIndices(rSchema)
+		if err != nil {
+			return err
+		}
+	}
+	for i, expr := range p.OtherConditions {
+		p.OtherConditions[i], err = expr.ResolveIndices(expression.MergeSchema(lSchema, rSchema))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalUnionScan) ResolveIndices() (err error) {
 	err = p.basePhysicalPlan.ResolveIndices()
@@ -289,6 +360,55 @@ func (p *PhysicalTopN) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalWindow) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(p.Schema().Columns)-len(p.WindowFuncDescs); i++ {
+		col := p.Schema().Columns[i]
+		newCol, err := col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.Schema().Columns[i] = newCol.(*expression.Column)
+	}
+	for i, item := range p.PartitionBy {
+		newCol, err := item.Col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.PartitionBy[i].Col = newCol.(*expression.Column)
+	}
+	for i, item := range p.OrderBy {
+		newCol, err := item.Col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.OrderBy[i].Col = newCol.(*expression.Column)
+	}
+	for _, desc := range p.WindowFuncDescs {
+		for i, arg := range desc.Args {
+			desc.Args[i], err = arg.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if p.Frame != nil {
+		for _, bound := range []*FrameBound{p.Frame.Start, p.Frame.End} {
+			for i, expr := range bound.CalcFuncs {
+				bound.CalcFuncs[i], err = expr.ResolveIndices(p.children[0].Schema())
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *Insert) ResolveIndices() (err error) {
 	err = p.baseSchemaProducer.ResolveIndices()
@@ -306,6 +426,38 @@ func (p *Insert) ResolveIndices() (err error) {
 			return err
 		}
 	}
+	for _, assign := range p.OnDuplicate {
+		newCol, err := assign.Col.ResolveIndices(p.tableSchema)
+		if err != nil {
+			return err
+		}
+		assign.Col = newCol.(*expression.Column)
+		assign.Expr, err = assign.Expr.ResolveIndices(p.tableSchema)
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// ResolveIndices implements Plan interface.
+func (p *Update) ResolveIndices() (err error) {
+	err = p.baseSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	schema := p.SelectPlan.Schema()
+	for _, assign := range p.OrderedList {
+		newCol, err := assign.Col.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+		assign.Col = newCol.(*expression.Column)
+		assign.Expr, err = assign.Expr.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+	}
 	return
 }
 
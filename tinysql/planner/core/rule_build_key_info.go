@@ -66,6 +66,9 @@ func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childS
 			selfSchema.Keys = append(selfSchema.Keys, newKey)
 		}
 	}
+	if len(la.GroupByItems) == 0 {
+		la.maxOneRow = true
+	}
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
@@ -76,6 +79,23 @@ func (p *LogicalSelection) BuildKeyInfo(selfSchema *expression.Schema, childSche
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalLimit) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	if p.Count == 1 {
+		p.maxOneRow = true
+	}
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalTableDual) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
+	if p.RowCount == 1 {
+		p.maxOneRow = true
+	}
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalMaxOneRow) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	p.maxOneRow = true
 }
 
 // A bijection exists between columns of a projection's schema and this projection's Exprs.
@@ -117,6 +137,8 @@ func (p *LogicalProjection) BuildKeyInfo(selfSchema *expression.Schema, childSch
 func (p *LogicalJoin) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
 	switch p.JoinType {
+	case SemiJoin, LeftOuterSemiJoin, AntiSemiJoin, AntiLeftOuterSemiJoin:
+		selfSchema.Keys = childSchema[0].Clone().Keys
 	case InnerJoin, LeftOuterJoin, RightOuterJoin:
 		// If there is no equal conditions, then cartesian product can't be prevented and unique key information will destroy.
 		if len(p.EqualConditions) == 0 {
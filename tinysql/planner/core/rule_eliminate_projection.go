@@ -53,9 +53,9 @@ func canProjectionBeEliminatedStrict(p *PhysicalProjection) bool {
 func resolveColumnAndReplace(origin *expression.Column, replace map[string]*expression.Column) {
 	dst := replace[string(origin.HashCode(nil))]
 	if dst != nil {
-		retType := origin.RetType
+		retType, inOperand := origin.RetType, origin.InOperand
 		*origin = *dst
-		origin.RetType = retType
+		origin.RetType, origin.InOperand = retType, inOperand
 	}
 }
 
@@ -112,7 +112,11 @@ func (pe *projectionEliminator) optimize(ctx context.Context, lp LogicalPlan) (L
 func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*expression.Column, canEliminate bool) LogicalPlan {
 	proj, isProj := p.(*LogicalProjection)
 	childFlag := canEliminate
-	if _, isAgg := p.(*LogicalAggregation); isAgg || isProj {
+	if _, isUnion := p.(*LogicalUnionAll); isUnion {
+		childFlag = false
+	} else if _, isAgg := p.(*LogicalAggregation); isAgg || isProj {
+		childFlag = true
+	} else if _, isWindow := p.(*LogicalWindow); isWindow {
 		childFlag = true
 	}
 	for i, child := range p.Children() {
@@ -214,6 +218,27 @@ func (lt *LogicalTopN) replaceExprColumns(replace map[string]*expression.Column)
 	}
 }
 
+func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column) {
+	for _, desc := range p.WindowFuncDescs {
+		for _, arg := range desc.Args {
+			ResolveExprAndReplace(arg, replace)
+		}
+	}
+	for _, item := range p.PartitionBy {
+		resolveColumnAndReplace(item.Col, replace)
+	}
+	for _, item := range p.OrderBy {
+		resolveColumnAndReplace(item.Col, replace)
+	}
+	if p.Frame != nil {
+		for _, bound := range []*FrameBound{p.Frame.Start, p.Frame.End} {
+			for _, expr := range bound.CalcFuncs {
+				ResolveExprAndReplace(expr, replace)
+			}
+		}
+	}
+}
+
 func (*projectionEliminator) name() string {
 	return "projection_eliminate"
 }
@@ -129,7 +129,9 @@ func (o *outerJoinEliminator) isInnerJoinKeysContainIndex(innerPlan LogicalPlan,
 		if path.IsTablePath {
 			continue
 		}
-		if !path.Index.Unique {
+		// The IdxCols only contain the index columns in the schema, the
+		// join keys can't be unique if some index columns are pruned.
+		if !path.Index.Unique || len(path.IdxCols) < len(path.Index.Columns) {
 			continue
 		}
 		joinKeysContainIndex := true
@@ -190,6 +192,9 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 		}
 	}
 
+	// childrenCols are the columns used by the parents of each child, they
+	// are the same for the operators with only one child.
+	var childrenCols [][]*expression.Column
 	switch x := p.(type) {
 	case *LogicalProjection:
 		parentCols = parentCols[:0]
@@ -206,6 +211,32 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 				parentCols = append(parentCols, expression.ExtractColumns(expr)...)
 			}
 		}
+	case *LogicalSelection:
+		// The Selection doesn't change the columns, so the columns used by
+		// its conditions are added to the ones used by its parents.
+		if len(parentCols) == 0 {
+			parentCols = append(parentCols, p.Schema().Columns...)
+		} else {
+			parentCols = expression.ExtractColumnsFromExpressions(o.copyCols(parentCols), x.Conditions, nil)
+		}
+	case *LogicalSort:
+		if len(parentCols) == 0 {
+			parentCols = append(parentCols, p.Schema().Columns...)
+		} else {
+			parentCols = o.copyCols(parentCols)
+			for _, item := range x.ByItems {
+				parentCols = append(parentCols, expression.ExtractColumns(item.Expr)...)
+			}
+		}
+	case *LogicalJoin:
+		// The columns used by the parents and the join conditions are split
+		// for the children, so the outer joins in the children may be
+		// eliminated if the columns of the inner tables are not used.
+		if len(parentCols) == 0 {
+			parentCols = append(parentCols, p.Schema().Columns...)
+		}
+		leftCols, rightCols := x.extractUsedCols(o.copyCols(parentCols))
+		childrenCols = [][]*expression.Column{leftCols, rightCols}
 	default:
 		parentCols = append(parentCols[:0], p.Schema().Columns...)
 	}
@@ -215,7 +246,11 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 	}
 
 	for i, child := range p.Children() {
-		newChild, err := o.doOptimize(child, aggCols, parentCols)
+		childCols := parentCols
+		if childrenCols != nil {
+			childCols = childrenCols[i]
+		}
+		newChild, err := o.doOptimize(child, aggCols, childCols)
 		if err != nil {
 			return nil, err
 		}
@@ -224,6 +259,12 @@ func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Co
 	return p, nil
 }
 
+// copyCols copies the columns to a new slice, so that appending columns to it
+// doesn't overwrite the columns used by the other operators.
+func (o *outerJoinEliminator) copyCols(cols []*expression.Column) []*expression.Column {
+	return append(make([]*expression.Column, 0, len(cols)), cols...)
+}
+
 func (o *outerJoinEliminator) optimize(ctx context.Context, p LogicalPlan) (LogicalPlan, error) {
 	return o.doOptimize(p, nil, nil)
 }
@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 )
 
@@ -41,6 +42,10 @@ func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression
 	eqEdges = append(eqEdges, lhsEqualConds...)
 	eqEdges = append(eqEdges, rhsEqualConds...)
 	otherConds = append(otherConds, join.OtherConditions...)
+	// The left and right conditions are only remained when the predicates
+	// haven't been pushed down, e.g. in the cascades planner.
+	otherConds = append(otherConds, join.LeftConditions...)
+	otherConds = append(otherConds, join.RightConditions...)
 	otherConds = append(otherConds, lhsOtherConds...)
 	otherConds = append(otherConds, rhsOtherConds...)
 	return group, eqEdges, otherConds
@@ -63,6 +68,9 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 	var err error
 	curJoinGroup, eqEdges, otherConds := extractJoinGroup(p)
 	if len(curJoinGroup) > 1 {
+		// The hint must be fetched before the join group nodes are handled,
+		// since they may belong to the same FROM clause.
+		leadingHint := getUnhandledLeadingHint(p)
 		for i := range curJoinGroup {
 			curJoinGroup[i], err = s.optimizeRecursive(ctx, curJoinGroup[i])
 			if err != nil {
@@ -73,6 +81,12 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 			ctx:        ctx,
 			otherConds: otherConds,
 		}
+		if leadingHint != nil {
+			curJoinGroup, eqEdges, _ = baseGroupSolver.generateLeadingJoinGroup(leadingHint, curJoinGroup, eqEdges)
+			if len(curJoinGroup) == 1 {
+				return curJoinGroup[0], nil
+			}
+		}
 		if len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold {
 			groupSolver := &joinReorderGreedySolver{
 				baseSingleGroupJoinOrderSolver: baseGroupSolver,
@@ -91,6 +105,10 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 		}
 		return p, nil
 	}
+	if hint := getUnhandledLeadingHint(p); hint != nil {
+		errMsg := "leading hint is inapplicable, check the join type or the join algorithm hint"
+		ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+	}
 	newChildren := make([]LogicalPlan, 0, len(p.Children()))
 	for _, child := range p.Children() {
 		newChild, err := s.optimizeRecursive(ctx, child)
@@ -103,6 +121,67 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 	return p, nil
 }
 
+// ApplyLeadingHint applies the LEADING hints in the plan tree without
+// reordering the other joins. The tables specified by the hint are joined
+// first in the hint order, then the remained tables of the join group are
+// joined in their original order. It's used by the cascades planner, which
+// doesn't reorder the joins.
+func ApplyLeadingHint(ctx sessionctx.Context, p LogicalPlan) (LogicalPlan, error) {
+	curJoinGroup, eqEdges, otherConds := extractJoinGroup(p)
+	if len(curJoinGroup) > 1 {
+		leadingHint := getUnhandledLeadingHint(p)
+		for i := range curJoinGroup {
+			var err error
+			curJoinGroup[i], err = ApplyLeadingHint(ctx, curJoinGroup[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		if leadingHint == nil {
+			return p, nil
+		}
+		solver := &baseSingleGroupJoinOrderSolver{
+			ctx:        ctx,
+			otherConds: otherConds,
+		}
+		newGroup, eqEdges, ok := solver.generateLeadingJoinGroup(leadingHint, curJoinGroup, eqEdges)
+		if !ok {
+			return p, nil
+		}
+		newJoin := newGroup[0]
+		for _, node := range newGroup[1:] {
+			newJoin, eqEdges = solver.makeJoinWithEdges(newJoin, node, eqEdges)
+		}
+		return newJoin, nil
+	}
+	if hint := getUnhandledLeadingHint(p); hint != nil {
+		errMsg := "leading hint is inapplicable, check the join type or the join algorithm hint"
+		ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+	}
+	newChildren := make([]LogicalPlan, 0, len(p.Children()))
+	for _, child := range p.Children() {
+		newChild, err := ApplyLeadingHint(ctx, child)
+		if err != nil {
+			return nil, err
+		}
+		newChildren = append(newChildren, newChild)
+	}
+	p.SetChildren(newChildren...)
+	return p, nil
+}
+
+// getUnhandledLeadingHint returns the LEADING hint of the join if it hasn't
+// been handled, and marks it as handled. The hint is attached to all the joins
+// of its FROM clause, it only takes effect on the top most one.
+func getUnhandledLeadingHint(p LogicalPlan) *leadingHintInfo {
+	join, isJoin := p.(*LogicalJoin)
+	if !isJoin || join.leadingHint == nil || join.leadingHint.handled {
+		return nil
+	}
+	join.leadingHint.handled = true
+	return join.leadingHint
+}
+
 type baseSingleGroupJoinOrderSolver struct {
 	ctx          sessionctx.Context
 	curJoinGroup []*jrNode
@@ -164,6 +243,65 @@ func (s *baseSingleGroupJoinOrderSolver) newJoinWithEdges(lChild, rChild Logical
 	return newJoin
 }
 
+// generateLeadingJoinGroup joins the tables specified by the LEADING hint in
+// the hint order, and replaces them in the join group by the result join. The
+// equal conditions used by the leading join are removed from the eqEdges. The
+// join group is unchanged if the hint can't be applied.
+func (s *baseSingleGroupJoinOrderSolver) generateLeadingJoinGroup(hint *leadingHintInfo, curJoinGroup []LogicalPlan,
+	eqEdges []*expression.ScalarFunction) ([]LogicalPlan, []*expression.ScalarFunction, bool) {
+	remainedGroup := make([]LogicalPlan, len(curJoinGroup))
+	copy(remainedGroup, curJoinGroup)
+	leadingNodes := make([]LogicalPlan, 0, len(hint.tables))
+	for _, table := range hint.tables {
+		idx := -1
+		for i, node := range remainedGroup {
+			alias := extractTableAlias(node)
+			if alias != nil && alias.dbName.L == table.dbName.L && alias.tblName.L == table.tblName.L {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			errMsg := "leading hint is inapplicable, check if the leading hint table is valid"
+			s.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+			return curJoinGroup, eqEdges, false
+		}
+		leadingNodes = append(leadingNodes, remainedGroup[idx])
+		remainedGroup = append(remainedGroup[:idx], remainedGroup[idx+1:]...)
+	}
+	leadingJoin := leadingNodes[0]
+	for _, node := range leadingNodes[1:] {
+		leadingJoin, eqEdges = s.makeJoinWithEdges(leadingJoin, node, eqEdges)
+	}
+	return append([]LogicalPlan{leadingJoin}, remainedGroup...), eqEdges, true
+}
+
+// makeJoinWithEdges joins the two nodes with the equal conditions and other
+// conditions connecting them, it's a cartesian join if there is no equal
+// condition. The remained equal conditions are returned.
+func (s *baseSingleGroupJoinOrderSolver) makeJoinWithEdges(lChild, rChild LogicalPlan,
+	eqEdges []*expression.ScalarFunction) (LogicalPlan, []*expression.ScalarFunction) {
+	var usedEdges, remainedEdges []*expression.ScalarFunction
+	for _, edge := range eqEdges {
+		lCol := edge.GetArgs()[0].(*expression.Column)
+		rCol := edge.GetArgs()[1].(*expression.Column)
+		if lChild.Schema().Contains(lCol) && rChild.Schema().Contains(rCol) {
+			usedEdges = append(usedEdges, edge)
+		} else if rChild.Schema().Contains(lCol) && lChild.Schema().Contains(rCol) {
+			newSf := expression.NewFunctionInternal(s.ctx, ast.EQ, edge.GetType(), rCol, lCol).(*expression.ScalarFunction)
+			usedEdges = append(usedEdges, newSf)
+		} else {
+			remainedEdges = append(remainedEdges, edge)
+		}
+	}
+	var otherConds []expression.Expression
+	mergedSchema := expression.MergeSchema(lChild.Schema(), rChild.Schema())
+	s.otherConds, otherConds = expression.FilterOutInPlace(s.otherConds, func(expr expression.Expression) bool {
+		return expression.ExprFromSchema(expr, mergedSchema)
+	})
+	return s.newJoinWithEdges(lChild, rChild, usedEdges, otherConds), remainedEdges
+}
+
 // calcJoinCumCost calculates the cumulative cost of the join node.
 func (s *baseSingleGroupJoinOrderSolver) calcJoinCumCost(join LogicalPlan, lNode, rNode *jrNode) float64 {
 	return join.statsInfo().RowCount + lNode.cumCost + rNode.cumCost
@@ -114,7 +114,7 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 	var equalCond []*expression.ScalarFunction
 	var leftPushCond, rightPushCond, otherCond, leftCond, rightCond []expression.Expression
 	switch p.JoinType {
-	case LeftOuterJoin:
+	case LeftOuterJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
 		predicates = p.outerJoinPropConst(predicates)
 		dual := Conds2TableDual(p, predicates)
 		if dual != nil {
@@ -148,7 +148,7 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 		p.LeftConditions = nil
 		ret = append(expression.ScalarFuncs2Exprs(equalCond), otherCond...)
 		ret = append(ret, leftPushCond...)
-	case InnerJoin:
+	case SemiJoin, InnerJoin:
 		tempCond := make([]expression.Expression, 0, len(p.LeftConditions)+len(p.RightConditions)+len(p.EqualConditions)+len(p.OtherConditions)+len(predicates))
 		tempCond = append(tempCond, p.LeftConditions...)
 		tempCond = append(tempCond, p.RightConditions...)
@@ -169,6 +169,23 @@ func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret
 		p.OtherConditions = otherCond
 		leftCond = leftPushCond
 		rightCond = rightPushCond
+	case AntiSemiJoin:
+		predicates = expression.PropagateConstant(p.ctx, predicates)
+		// Return table dual when filter is constant false or null.
+		dual := Conds2TableDual(p, predicates)
+		if dual != nil {
+			return ret, dual
+		}
+		// `predicates` should only contain left conditions or constant filters.
+		_, leftPushCond, rightPushCond, _ = p.extractOnCondition(predicates, true, true)
+		// Do not derive `is not null` for anti join, since it may cause wrong results.
+		// For example:
+		// `select * from t t1 where t1.a not in (select b from t t2)` does not imply `t2.b is not null`,
+		// `select * from t t1 where t1.a not in (select a from t t2 where t1.b = t2.b` does not imply `t1.b is not null`,
+		// `select * from t t1 where not exists (select * from t t2 where t2.a = t1.a)` does not imply `t1.a is not null`,
+		leftCond = leftPushCond
+		rightCond = append(p.RightConditions, rightPushCond...)
+		p.RightConditions = nil
 	}
 	leftCond = expression.RemoveDupExprs(p.ctx, leftCond)
 	rightCond = expression.RemoveDupExprs(p.ctx, rightCond)
@@ -193,6 +210,12 @@ func (p *LogicalJoin) updateEQCond() {
 	for i := len(p.OtherConditions) - 1; i >= 0; i-- {
 		need2Remove := false
 		if eqCond, ok := p.OtherConditions[i].(*expression.ScalarFunction); ok && eqCond.FuncName.L == ast.EQ {
+			// If it is a column equal condition converted from `[not] in (subq)`, do not move it
+			// to EqualConditions, and keep it in OtherConditions. Reference comments in `extractOnCondition`
+			// for detailed reasons.
+			if expression.IsEQCondFromIn(eqCond) {
+				continue
+			}
 			lExpr, rExpr := eqCond.GetArgs()[0], eqCond.GetArgs()[1]
 			if expression.ExprFromSchema(lExpr, lChild.Schema()) && expression.ExprFromSchema(rExpr, rChild.Schema()) {
 				lKeys = append(lKeys, lExpr)
@@ -281,28 +304,36 @@ func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
 		innerTable, outerTable = outerTable, innerTable
 	}
 
-	// first simplify embedded outer join.
-	if innerPlan, ok := innerTable.(*LogicalJoin); ok {
-		simplifyOuterJoin(innerPlan, predicates)
-	}
-	if outerPlan, ok := outerTable.(*LogicalJoin); ok {
-		simplifyOuterJoin(outerPlan, predicates)
+	// first simplify embedding outer join.
+	if p.JoinType != InnerJoin {
+		for _, expr := range predicates {
+			if isNullRejected(p.ctx, innerTable.Schema(), expr) {
+				p.JoinType = InnerJoin
+				break
+			}
+		}
 	}
 
+	// then simplify embedded outer join. Besides the predicates, the rows of
+	// the inner child which don't satisfy the join conditions are filtered, so
+	// the join conditions are also used to simplify the outer joins in the
+	// inner child. Both children are the inner child for an inner join.
+	innerPredicates := make([]expression.Expression, 0, len(p.EqualConditions)+len(p.LeftConditions)+
+		len(p.RightConditions)+len(p.OtherConditions)+len(predicates))
+	innerPredicates = append(innerPredicates, expression.ScalarFuncs2Exprs(p.EqualConditions)...)
+	innerPredicates = append(innerPredicates, p.LeftConditions...)
+	innerPredicates = append(innerPredicates, p.RightConditions...)
+	innerPredicates = append(innerPredicates, p.OtherConditions...)
+	innerPredicates = append(innerPredicates, predicates...)
+	outerPredicates := predicates
 	if p.JoinType == InnerJoin {
-		return
+		outerPredicates = innerPredicates
 	}
-	// then simplify embedding outer join.
-	canBeSimplified := false
-	for _, expr := range predicates {
-		isOk := isNullRejected(p.ctx, innerTable.Schema(), expr)
-		if isOk {
-			canBeSimplified = true
-			break
-		}
+	if innerPlan, ok := innerTable.(*LogicalJoin); ok {
+		simplifyOuterJoin(innerPlan, innerPredicates)
 	}
-	if canBeSimplified {
-		p.JoinType = InnerJoin
+	if outerPlan, ok := outerTable.(*LogicalJoin); ok {
+		simplifyOuterJoin(outerPlan, outerPredicates)
 	}
 }
 
@@ -321,6 +352,9 @@ func isNullRejected(ctx sessionctx.Context, schema *expression.Schema, expr expr
 	if !ok {
 		return false
 	}
+	if expression.MaybeOverOptimized4PlanCache(ctx, []expression.Expression{x}) {
+		sc.SkipPlanCache = true
+	}
 	if x.Value.IsNull() {
 		return true
 	} else if isTrue, err := x.Value.ToBool(sc); err == nil && isTrue == 0 {
@@ -351,6 +385,17 @@ func (p *LogicalProjection) PredicatePushDown(predicates []expression.Expression
 	return append(remained, canNotBePushed...), child
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalUnionAll) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
+	for i, proj := range p.children {
+		newExprs := make([]expression.Expression, 0, len(predicates))
+		newExprs = append(newExprs, predicates...)
+		retCond, newChild := proj.PredicatePushDown(newExprs)
+		addSelection(p, newChild, retCond, i)
+	}
+	return nil, p
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (la *LogicalAggregation) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
 	var condsToPush []expression.Expression
@@ -397,11 +442,30 @@ func (p *LogicalLimit) PredicatePushDown(predicates []expression.Expression) ([]
 	return predicates, p
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	canBePushed := make([]expression.Expression, 0, len(predicates))
+	canNotBePushed := make([]expression.Expression, 0, len(predicates))
+	partitionCols := expression.NewSchema(p.GetPartitionByCols()...)
+	for _, cond := range predicates {
+		// We can push predicate beneath Window, only if all of the
+		// extractedCols are part of partitionBy columns.
+		if expression.ExprFromSchema(cond, partitionCols) {
+			canBePushed = append(canBePushed, cond)
+		} else {
+			canNotBePushed = append(canNotBePushed, cond)
+		}
+	}
+	p.baseLogicalPlan.PredicatePushDown(canBePushed)
+	return canNotBePushed, p
+}
+
 // deriveOtherConditions given a LogicalJoin, check the OtherConditions to see if we can derive more
 // conditions for left/right child pushdown.
 func deriveOtherConditions(p *LogicalJoin, deriveLeft bool, deriveRight bool) (leftCond []expression.Expression,
 	rightCond []expression.Expression) {
 	leftPlan, rightPlan := p.children[0], p.children[1]
+	isOuterSemi := (p.JoinType == LeftOuterSemiJoin) || (p.JoinType == AntiLeftOuterSemiJoin)
 	for _, expr := range p.OtherConditions {
 		if deriveLeft {
 			leftRelaxedCond := expression.DeriveRelaxedFiltersFromDNF(expr, leftPlan.Schema())
@@ -418,6 +482,12 @@ func deriveOtherConditions(p *LogicalJoin, deriveLeft bool, deriveRight bool) (l
 			if rightRelaxedCond != nil {
 				rightCond = append(rightCond, rightRelaxedCond)
 			}
+			// For LeftOuterSemiJoin and AntiLeftOuterSemiJoin, the column equal condition converted
+			// from `in (subq)` is put into `OtherConditions`, and deriving `col is not null` from it
+			// would cause wrong results, so we disable this optimization for outer semi joins.
+			if isOuterSemi {
+				continue
+			}
 			notNullExpr := deriveNotNullExpr(expr, rightPlan.Schema())
 			if notNullExpr != nil {
 				rightCond = append(rightCond, notNullExpr)
@@ -461,6 +531,9 @@ func Conds2TableDual(p LogicalPlan, conds []expression.Expression) LogicalPlan {
 		return nil
 	}
 	sc := p.SCtx().GetSessionVars().StmtCtx
+	if expression.MaybeOverOptimized4PlanCache(p.SCtx(), []expression.Expression{con}) {
+		sc.SkipPlanCache = true
+	}
 	if isTrue, err := con.Value.ToBool(sc); (err == nil && isTrue == 0) || con.Value.IsNull() {
 		dual := LogicalTableDual{}.Init(p.SCtx())
 		dual.SetSchema(p.Schema())
@@ -488,11 +561,19 @@ func (p *LogicalJoin) outerJoinPropConst(predicates []expression.Expression) []e
 	p.LeftConditions = nil
 	p.RightConditions = nil
 	p.OtherConditions = nil
-	joinConds, predicates = expression.PropConstOverOuterJoin(p.ctx, joinConds, predicates, outerTable.Schema(), innerTable.Schema())
+	nullSensitive := p.JoinType == AntiLeftOuterSemiJoin || p.JoinType == LeftOuterSemiJoin
+	joinConds, predicates = expression.PropConstOverOuterJoin(p.ctx, joinConds, predicates, outerTable.Schema(), innerTable.Schema(), nullSensitive)
 	p.attachOnConds(joinConds)
 	return predicates
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalMaxOneRow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	// MaxOneRow forbids any condition to push down.
+	p.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, p
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (p *LogicalMemTable) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
 	return predicates, p.self
@@ -167,6 +167,15 @@ func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths
 	return stats
 }
 
+// removeUnpushableFilters removes the filters of the path which can't be
+// evaluated by the coprocessor, they are evaluated by the Selection above the
+// DataSource, see DataSource.PredicatePushDown.
+func (ds *DataSource) removeUnpushableFilters(path *util.AccessPath) {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	_, path.IndexFilters, _ = expression.ExpressionsToPB(sc, path.IndexFilters, ds.ctx.GetClient())
+	_, path.TableFilters, _ = expression.ExpressionsToPB(sc, path.TableFilters, ds.ctx.GetClient())
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	ds.initStats()
@@ -174,22 +183,29 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	for i, expr := range ds.pushedDownConds {
 		ds.pushedDownConds[i] = expression.PushDownNot(ds.ctx, expr)
 	}
+	for i, expr := range ds.allConds {
+		ds.allConds[i] = expression.PushDownNot(ds.ctx, expr)
+	}
+	// The ranges are built from all the conditions, the conditions which can't
+	// be pushed down to the coprocessor are still able to be converted to the
+	// ranges of the scans.
 	for _, path := range ds.possibleAccessPaths {
 		if path.IsTablePath {
 			continue
 		}
-		err := ds.fillIndexPath(path, ds.pushedDownConds)
+		err := ds.fillIndexPath(path, ds.allConds)
 		if err != nil {
 			return nil, err
 		}
 	}
-	ds.stats = ds.deriveStatsByFilter(ds.pushedDownConds, ds.possibleAccessPaths)
+	ds.stats = ds.deriveStatsByFilter(ds.allConds, ds.possibleAccessPaths)
 	for _, path := range ds.possibleAccessPaths {
 		if path.IsTablePath {
-			noIntervalRanges, err := ds.deriveTablePathStats(path, ds.pushedDownConds)
+			noIntervalRanges, err := ds.deriveTablePathStats(path, ds.allConds)
 			if err != nil {
 				return nil, err
 			}
+			ds.removeUnpushableFilters(path)
 			// If we have point or empty range, just remove other possible paths.
 			if noIntervalRanges || len(path.Ranges) == 0 {
 				ds.possibleAccessPaths[0] = path
@@ -199,6 +215,7 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 			continue
 		}
 		noIntervalRanges := ds.deriveIndexPathStats(path)
+		ds.removeUnpushableFilters(path)
 		// If we have empty range, or point range on unique index, just remove other possible paths.
 		if (noIntervalRanges && path.Index.Unique) || len(path.Ranges) == 0 {
 			ds.possibleAccessPaths[0] = path
@@ -275,6 +292,20 @@ func deriveLimitStats(childProfile *property.StatsInfo, limitCount float64) *pro
 	return stats
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalUnionAll) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = &property.StatsInfo{
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	for _, childProfile := range childStats {
+		p.stats.RowCount += childProfile.RowCount
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] += childProfile.Cardinality[i]
+		}
+	}
+	return p.stats, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalLimit) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	p.stats = deriveLimitStats(childStats[0], float64(p.Count))
@@ -347,6 +378,25 @@ func (la *LogicalAggregation) DeriveStats(childStats []*property.StatsInfo, self
 // every matched bucket.
 func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	leftProfile, rightProfile := childStats[0], childStats[1]
+	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+		p.stats = &property.StatsInfo{
+			RowCount:    leftProfile.RowCount * selectionFactor,
+			Cardinality: make([]float64, len(leftProfile.Cardinality)),
+		}
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] = leftProfile.Cardinality[i] * selectionFactor
+		}
+		return p.stats, nil
+	}
+	if p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		p.stats = &property.StatsInfo{
+			RowCount:    leftProfile.RowCount,
+			Cardinality: make([]float64, selfSchema.Len()),
+		}
+		copy(p.stats.Cardinality, leftProfile.Cardinality)
+		p.stats.Cardinality[len(p.stats.Cardinality)-1] = 2.0
+		return p.stats, nil
+	}
 	helper := &fullJoinRowCountHelper{
 		cartesian:     0 == len(p.EqualConditions),
 		leftProfile:   leftProfile,
@@ -395,3 +445,57 @@ func (h *fullJoinRowCountHelper) estimate() float64 {
 	count := h.leftProfile.RowCount * h.rightProfile.RowCount / math.Max(leftKeyCardinality, rightKeyCardinality)
 	return count
 }
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (la *LogicalApply) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	leftProfile := childStats[0]
+	la.stats = &property.StatsInfo{
+		RowCount:    leftProfile.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	copy(la.stats.Cardinality, leftProfile.Cardinality)
+	if la.JoinType == LeftOuterSemiJoin || la.JoinType == AntiLeftOuterSemiJoin {
+		la.stats.Cardinality[len(la.stats.Cardinality)-1] = 2.0
+	} else {
+		for i := childSchema[0].Len(); i < selfSchema.Len(); i++ {
+			la.stats.Cardinality[i] = leftProfile.RowCount
+		}
+	}
+	return la.stats, nil
+}
+
+// Exists and MaxOneRow produce at most one row, so we set the RowCount of stats one.
+func getSingletonStats(schema *expression.Schema) *property.StatsInfo {
+	ret := &property.StatsInfo{
+		RowCount:    1.0,
+		Cardinality: make([]float64, schema.Len()),
+	}
+	for i := range schema.Columns {
+		ret.Cardinality[i] = 1
+	}
+	return ret
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalMaxOneRow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = getSingletonStats(selfSchema)
+	return p.stats, nil
+}
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	p.stats = &property.StatsInfo{
+		RowCount:    childProfile.RowCount,
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	childLen := selfSchema.Len() - len(p.WindowFuncDescs)
+	for i := 0; i < childLen; i++ {
+		colExpr := selfSchema.Columns[i]
+		p.stats.Cardinality[i] = getCardinality([]*expression.Column{colExpr}, childSchema[0], childProfile)
+	}
+	for i := childLen; i < selfSchema.Len(); i++ {
+		p.stats.Cardinality[i] = childProfile.RowCount
+	}
+	return p.stats, nil
+}
@@ -45,3 +45,67 @@ func (impl *HashJoinImpl) AttachChildren(children ...memo.Implementation) memo.I
 func NewHashJoinImpl(hashJoin *plannercore.PhysicalHashJoin) *HashJoinImpl {
 	return &HashJoinImpl{baseImpl{plan: hashJoin}}
 }
+
+// MergeJoinImpl is the implementation for PhysicalMergeJoin.
+type MergeJoinImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (impl *MergeJoinImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	mergeJoin := impl.plan.(*plannercore.PhysicalMergeJoin)
+	// The children here are only used to calculate the cost.
+	mergeJoin.SetChildren(children[0].GetPlan(), children[1].GetPlan())
+	selfCost := mergeJoin.GetCost(children[0].GetPlan().StatsCount(), children[1].GetPlan().StatsCount())
+	impl.cost = selfCost + children[0].GetCost() + children[1].GetCost()
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *MergeJoinImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	mergeJoin := impl.plan.(*plannercore.PhysicalMergeJoin)
+	mergeJoin.SetChildren(children[0].GetPlan(), children[1].GetPlan())
+	mergeJoin.SetSchema(plannercore.BuildPhysicalJoinSchema(mergeJoin.JoinType, mergeJoin))
+	return impl
+}
+
+// NewMergeJoinImpl creates a new MergeJoinImpl.
+func NewMergeJoinImpl(mergeJoin *plannercore.PhysicalMergeJoin) *MergeJoinImpl {
+	return &MergeJoinImpl{baseImpl{plan: mergeJoin}}
+}
+
+// IndexJoinImpl is the implementation for PhysicalIndexJoin. The inner child
+// of the PhysicalIndexJoin is built along with the join, so only the outer
+// child is implemented by the optimizer, and the implementation of the inner
+// child passed to CalcCost and AttachChildren is nil.
+type IndexJoinImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (impl *IndexJoinImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	indexJoin := impl.plan.(*plannercore.PhysicalIndexJoin)
+	outer := children[1-indexJoin.InnerChildIdx]
+	_, innerCnt, innerCost := indexJoin.GetInnerPlan()
+	impl.cost = indexJoin.GetCost(outer.GetPlan().StatsCount(), innerCnt, outer.GetCost(), innerCost)
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *IndexJoinImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	indexJoin := impl.plan.(*plannercore.PhysicalIndexJoin)
+	outer := children[1-indexJoin.InnerChildIdx].GetPlan()
+	inner, _, _ := indexJoin.GetInnerPlan()
+	if indexJoin.InnerChildIdx == 1 {
+		indexJoin.SetChildren(outer, inner)
+	} else {
+		indexJoin.SetChildren(inner, outer)
+	}
+	indexJoin.SetSchema(plannercore.BuildPhysicalJoinSchema(indexJoin.JoinType, indexJoin))
+	return impl
+}
+
+// NewIndexJoinImpl creates a new IndexJoinImpl.
+func NewIndexJoinImpl(indexJoin *plannercore.PhysicalIndexJoin) *IndexJoinImpl {
+	return &IndexJoinImpl{baseImpl{plan: indexJoin}}
+}
@@ -14,6 +14,8 @@
 package implementation
 
 import (
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/planner/memo"
 )
@@ -87,6 +89,7 @@ func (agg *TiDBHashAggImpl) CalcCost(outCount float64, children ...memo.Implemen
 func (agg *TiDBHashAggImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
 	hashAgg := agg.plan.(*plannercore.PhysicalHashAgg)
 	hashAgg.SetChildren(children[0].GetPlan())
+	hashAgg.AggFuncs, hashAgg.GroupByItems = cloneAggItems(hashAgg.AggFuncs, hashAgg.GroupByItems)
 	// Inject extraProjection if the AggFuncs or GroupByItems contain ScalarFunction.
 	plannercore.InjectProjBelowAgg(hashAgg, hashAgg.AggFuncs, hashAgg.GroupByItems)
 	return agg
@@ -115,6 +118,68 @@ func NewTiKVHashAggImpl(agg *plannercore.PhysicalHashAgg) *TiKVHashAggImpl {
 	return &TiKVHashAggImpl{baseImpl{plan: agg}}
 }
 
+// TiDBStreamAggImpl is the implementation of PhysicalStreamAgg in TiDB layer.
+type TiDBStreamAggImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (agg *TiDBStreamAggImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	streamAgg := agg.plan.(*plannercore.PhysicalStreamAgg)
+	selfCost := streamAgg.GetCost(children[0].GetPlan().Stats().RowCount, true)
+	agg.cost = selfCost + children[0].GetCost()
+	return agg.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (agg *TiDBStreamAggImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	streamAgg := agg.plan.(*plannercore.PhysicalStreamAgg)
+	streamAgg.SetChildren(children[0].GetPlan())
+	streamAgg.AggFuncs, streamAgg.GroupByItems = cloneAggItems(streamAgg.AggFuncs, streamAgg.GroupByItems)
+	// Inject extraProjection if the AggFuncs or GroupByItems contain ScalarFunction.
+	plannercore.InjectProjBelowAgg(streamAgg, streamAgg.AggFuncs, streamAgg.GroupByItems)
+	return agg
+}
+
+// NewTiDBStreamAggImpl creates a new TiDBStreamAggImpl.
+func NewTiDBStreamAggImpl(agg *plannercore.PhysicalStreamAgg) *TiDBStreamAggImpl {
+	return &TiDBStreamAggImpl{baseImpl{plan: agg}}
+}
+
+// cloneAggItems copies the AggFuncs and GroupByItems of an aggregation before
+// InjectProjBelowAgg rewrites them. They are shared by the LogicalAggregation
+// and all its implementations, rewriting them in place would make the other
+// implementations refer to the columns of a projection they don't have.
+func cloneAggItems(aggFuncs []*aggregation.AggFuncDesc, groupByItems []expression.Expression) ([]*aggregation.AggFuncDesc, []expression.Expression) {
+	newAggFuncs := make([]*aggregation.AggFuncDesc, 0, len(aggFuncs))
+	for _, f := range aggFuncs {
+		newAggFuncs = append(newAggFuncs, f.Clone())
+	}
+	newGroupByItems := make([]expression.Expression, 0, len(groupByItems))
+	for _, item := range groupByItems {
+		newGroupByItems = append(newGroupByItems, item.Clone())
+	}
+	return newAggFuncs, newGroupByItems
+}
+
+// TiKVStreamAggImpl is the implementation of PhysicalStreamAgg in TiKV layer.
+type TiKVStreamAggImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (agg *TiKVStreamAggImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	streamAgg := agg.plan.(*plannercore.PhysicalStreamAgg)
+	selfCost := streamAgg.GetCost(children[0].GetPlan().Stats().RowCount, false)
+	agg.cost = selfCost + children[0].GetCost()
+	return agg.cost
+}
+
+// NewTiKVStreamAggImpl creates a new TiKVStreamAggImpl.
+func NewTiKVStreamAggImpl(agg *plannercore.PhysicalStreamAgg) *TiKVStreamAggImpl {
+	return &TiKVStreamAggImpl{baseImpl{plan: agg}}
+}
+
 // LimitImpl is the implementation of PhysicalLimit. Since PhysicalLimit on different
 // engines have the same behavior, and we don't calculate the cost of `Limit`, we only
 // have one Implementation for it.
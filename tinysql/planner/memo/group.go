@@ -16,6 +16,8 @@ package memo
 import (
 	"container/list"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pingcap/tidb/expression"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -101,6 +103,43 @@ func NewGroupWithSchema(e *GroupExpr, s *expression.Schema) *Group {
 	return g
 }
 
+// NewJoinGroupWithSchema creates a new Group with given schema for the join
+// GroupExpr generated by the join reorder rules. The fingerprint of the Group
+// is built from the join and the fingerprints of its child Groups regardless
+// of their order, instead of the address of the Group. Thus the Groups of the
+// same join generated for several times have the same fingerprint, and the
+// duplicated GroupExprs referring to them can be detected when inserted.
+func NewJoinGroupWithSchema(e *GroupExpr, s *expression.Schema) *Group {
+	g := NewGroupWithSchema(e, s)
+	childFingerprints := make([]string, 0, len(e.Children))
+	for _, child := range e.Children {
+		childFingerprints = append(childFingerprints, child.FingerPrint())
+	}
+	sort.Strings(childFingerprints)
+	join := e.ExprNode.(*plannercore.LogicalJoin)
+	g.SelfFingerprint = joinFingerPrint(join, true) + "(" + strings.Join(childFingerprints, ",") + ")"
+	return g
+}
+
+// NewSelectionGroupWithSchema creates a new Group with given schema for the
+// Selection GroupExpr pushed down by the transformation rules. Like
+// NewJoinGroupWithSchema, the fingerprint of the Group is built from the
+// conditions and the fingerprint of the child Group, so that pushing the same
+// conditions down to the same Group for several times, e.g. through the
+// reordered joins, doesn't generate the duplicated GroupExprs.
+func NewSelectionGroupWithSchema(e *GroupExpr, s *expression.Schema) *Group {
+	g := NewGroupWithSchema(e, s)
+	sel := e.ExprNode.(*plannercore.LogicalSelection)
+	sc := sel.SCtx().GetSessionVars().StmtCtx
+	conds := make([]string, 0, len(sel.Conditions))
+	for _, cond := range sel.Conditions {
+		conds = append(conds, string(cond.HashCode(sc)))
+	}
+	sort.Strings(conds)
+	g.SelfFingerprint = "selection[" + strings.Join(conds, ",") + "](" + e.Children[0].FingerPrint() + ")"
+	return g
+}
+
 // SetEngineType sets the engine type of the group.
 func (g *Group) SetEngineType(e EngineType) *Group {
 	g.EngineType = e
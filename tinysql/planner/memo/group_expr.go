@@ -15,6 +15,8 @@ package memo
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pingcap/tidb/expression"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -46,7 +48,14 @@ func NewGroupExpr(node plannercore.LogicalPlan) *GroupExpr {
 // FingerPrint gets the unique fingerprint of the Group expression.
 func (e *GroupExpr) FingerPrint() string {
 	if e.selfFingerprint == "" {
-		e.selfFingerprint = fmt.Sprintf("%v", e.ExprNode.ID())
+		if join, ok := e.ExprNode.(*plannercore.LogicalJoin); ok {
+			// The fingerprint of a join is built from its content instead of
+			// its ID, so that the same join generated by the join reorder
+			// rules for several times can be detected as duplicated.
+			e.selfFingerprint = joinFingerPrint(join, false)
+		} else {
+			e.selfFingerprint = fmt.Sprintf("%v", e.ExprNode.ID())
+		}
 		for i := range e.Children {
 			e.selfFingerprint += e.Children[i].FingerPrint()
 		}
@@ -63,3 +72,31 @@ func (e *GroupExpr) SetChildren(children ...*Group) {
 func (e *GroupExpr) Schema() *expression.Schema {
 	return e.Group.Prop.Schema
 }
+
+// joinFingerPrint builds the fingerprint from the join type and the join
+// conditions. The order of the conditions doesn't matter. If ignoreSide is
+// true, the order of the arguments of the equal conditions doesn't matter
+// either, i.e. the swapped joins have the same fingerprint.
+func joinFingerPrint(join *plannercore.LogicalJoin, ignoreSide bool) string {
+	sc := join.SCtx().GetSessionVars().StmtCtx
+	conds := make([]string, 0, len(join.EqualConditions)+len(join.LeftConditions)+len(join.RightConditions)+len(join.OtherConditions))
+	for _, cond := range join.EqualConditions {
+		args := cond.GetArgs()
+		lhs, rhs := string(args[0].HashCode(sc)), string(args[1].HashCode(sc))
+		if ignoreSide && lhs > rhs {
+			lhs, rhs = rhs, lhs
+		}
+		conds = append(conds, cond.FuncName.L+"("+lhs+","+rhs+")")
+	}
+	for _, cond := range join.LeftConditions {
+		conds = append(conds, string(cond.HashCode(sc)))
+	}
+	for _, cond := range join.RightConditions {
+		conds = append(conds, string(cond.HashCode(sc)))
+	}
+	for _, cond := range join.OtherConditions {
+		conds = append(conds, string(cond.HashCode(sc)))
+	}
+	sort.Strings(conds)
+	return fmt.Sprintf("%v[%s]", join.JoinType, strings.Join(conds, ","))
+}
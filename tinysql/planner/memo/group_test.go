@@ -210,3 +210,50 @@ func (s *testMemoSuite) TestBuildKeyInfo(c *C) {
 	newGroup2 := NewGroupWithSchema(newExpr2, group2.Prop.Schema)
 	newGroup2.BuildKeyInfo()
 }
+
+func (s *testMemoSuite) TestJoinGroupFingerPrint(c *C) {
+	stmt, err := s.ParseOneStmt("select * from t t1 join t t2 on t1.a = t2.a join t t3 on t2.b = t3.b", "", "")
+	c.Assert(err, IsNil)
+	p, _, err := plannercore.BuildLogicalPlan(context.Background(), s.sctx, stmt, s.is)
+	c.Assert(err, IsNil)
+	logic, ok := p.(plannercore.LogicalPlan)
+	c.Assert(ok, IsTrue)
+	for {
+		if _, ok := logic.(*plannercore.LogicalJoin); ok {
+			break
+		}
+		logic = logic.Children()[0]
+	}
+	group := Convert2Group(logic)
+	topExpr := group.Equivalents.Front().Value.(*GroupExpr)
+	bottomExpr := topExpr.Children[0].Equivalents.Front().Value.(*GroupExpr)
+	join := bottomExpr.ExprNode.(*plannercore.LogicalJoin)
+	schema := bottomExpr.Group.Prop.Schema
+
+	// The Groups of the same join with swapped children have the same fingerprint.
+	expr1 := NewGroupExpr(join)
+	expr1.SetChildren(bottomExpr.Children[0], bottomExpr.Children[1])
+	group1 := NewJoinGroupWithSchema(expr1, schema)
+	expr2 := NewGroupExpr(join)
+	expr2.SetChildren(bottomExpr.Children[1], bottomExpr.Children[0])
+	group2 := NewJoinGroupWithSchema(expr2, schema)
+	c.Assert(group1.FingerPrint(), Equals, group2.FingerPrint())
+
+	// The GroupExprs referring to them are detected as duplicated.
+	topJoin := topExpr.ExprNode.(*plannercore.LogicalJoin)
+	newTopExpr1 := NewGroupExpr(topJoin)
+	newTopExpr1.SetChildren(group1, topExpr.Children[1])
+	newTopExpr2 := NewGroupExpr(topJoin)
+	newTopExpr2.SetChildren(group2, topExpr.Children[1])
+	c.Assert(group.Insert(newTopExpr1), IsTrue)
+	c.Assert(group.Insert(newTopExpr2), IsFalse)
+
+	// The Selections pushed down to the same Group are also detected.
+	sel := plannercore.LogicalSelection{Conditions: expression.ScalarFuncs2Exprs(join.EqualConditions)}.Init(s.sctx)
+	selExpr1 := NewGroupExpr(sel)
+	selExpr1.SetChildren(group1)
+	selExpr2 := NewGroupExpr(sel)
+	selExpr2.SetChildren(group1)
+	c.Assert(NewSelectionGroupWithSchema(selExpr1, schema).FingerPrint(), Equals,
+		NewSelectionGroupWithSchema(selExpr2, schema).FingerPrint())
+}
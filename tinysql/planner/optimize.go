@@ -15,13 +15,16 @@ package planner
 
 import (
 	"context"
+	"strings"
 
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner/cascades"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 // Optimize does optimization and creates a Plan.
@@ -38,6 +41,12 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		return nil, nil, err
 	}
 
+	// Handle the execute statement.
+	if execPlan, ok := p.(*plannercore.Execute); ok {
+		err := execPlan.OptimizePreparedPlan(ctx, sctx, is)
+		return p, p.OutputNames(), err
+	}
+
 	names := p.OutputNames()
 
 	// Handle the non-logical plan statement.
@@ -46,13 +55,60 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		return p, names, nil
 	}
 
+	sessVars := sctx.GetSessionVars()
+	var tracer *tracing.OptimizeTracer
+	if sessVars.EnableOptimizerTrace && !readOptimizerTrace(node) {
+		tracer = &tracing.OptimizeTracer{}
+		sessVars.StmtCtx.OptimizeTracer = tracer
+		defer func() {
+			sessVars.StmtCtx.OptimizeTracer = nil
+		}()
+	}
+
 	// Handle the logical plan statement, use cascades planner if enabled.
-	if sctx.GetSessionVars().EnableCascadesPlanner {
-		finalPlan, err := cascades.DefaultOptimizer.FindBestPlan(sctx, logic)
-		return finalPlan, names, err
+	var finalPlan plannercore.PhysicalPlan
+	if sessVars.EnableCascadesPlanner {
+		finalPlan, err = cascades.DefaultOptimizer.FindBestPlan(sctx, logic)
+	} else {
+		finalPlan, err = plannercore.DoOptimize(ctx, builder.GetOptFlag(), logic)
 	}
-	finalPlan, err := plannercore.DoOptimize(ctx, builder.GetOptFlag(), logic)
-	return finalPlan, names, err
+	if err != nil {
+		return nil, nil, err
+	}
+	if tracer != nil {
+		tracer.FinalPlan = plannercore.ToString(finalPlan)
+		trace, err := tracer.JSON(sessVars.OptimizerTraceOneLine)
+		if err != nil {
+			return nil, nil, err
+		}
+		sessVars.LastOptimizerTrace = &variable.OptimizerTraceInfo{Query: node.Text(), Trace: trace}
+	}
+	return finalPlan, names, nil
+}
+
+// readOptimizerTrace checks whether the statement reads
+// INFORMATION_SCHEMA.OPTIMIZER_TRACE, such statement is not traced so the
+// trace of the previous statement can be read.
+func readOptimizerTrace(node ast.Node) bool {
+	checker := &optimizerTraceChecker{}
+	node.Accept(checker)
+	return checker.found
+}
+
+type optimizerTraceChecker struct {
+	found bool
+}
+
+func (c *optimizerTraceChecker) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok &&
+		strings.EqualFold(tn.Schema.L, infoschema.Name) && tn.Name.L == "optimizer_trace" {
+		c.found = true
+	}
+	return in, c.found
+}
+
+func (c *optimizerTraceChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
 }
 
 func init() {
@@ -508,6 +508,7 @@ func (cc *clientConn) openSessionAndDoAuth() error {
 	if err != nil {
 		return err
 	}
+	cc.ctx.SetSessionManager(cc.server)
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
@@ -675,6 +676,8 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	if cmd < mysql.ComEnd {
 		cc.ctx.SetCommandValue(cmd)
 	}
+	cc.ctx.SetProcessInfo("", time.Now(), cmd)
+	defer cc.ctx.SetProcessInfo("", time.Now(), mysql.ComSleep)
 
 	dataStr := string(hack.String(data))
 
@@ -709,6 +712,18 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		return cc.writeOK()
 	case mysql.ComFieldList:
 		return cc.handleFieldList(dataStr)
+	case mysql.ComStmtPrepare:
+		return cc.handleStmtPrepare(dataStr)
+	case mysql.ComStmtExecute:
+		return cc.handleStmtExecute(ctx, data)
+	case mysql.ComStmtFetch:
+		return cc.handleStmtFetch(ctx, data)
+	case mysql.ComStmtClose:
+		return cc.handleStmtClose(data)
+	case mysql.ComStmtSendLongData:
+		return cc.handleStmtSendLongData(data)
+	case mysql.ComStmtReset:
+		return cc.handleStmtReset(data)
 	default:
 		return mysql.NewErrf(mysql.ErrUnknown, "command %d not supported now", cmd)
 	}
@@ -847,11 +862,120 @@ func (cc *clientConn) handleQuery(ctx context.Context, sql string) (err error) {
 			err = cc.writeMultiResultset(ctx, rss, false)
 		}
 	} else {
+		loadDataInfo := cc.ctx.Value(executor.LoadDataVarKey)
+		if loadDataInfo != nil {
+			defer cc.ctx.SetValue(executor.LoadDataVarKey, nil)
+			info := loadDataInfo.(*executor.LoadDataInfo)
+			if err = cc.handleLoadData(ctx, info); err != nil {
+				return err
+			}
+			return cc.writeOkWith(info.Message(), cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), cc.ctx.Status(), cc.ctx.WarningCount())
+		}
 		err = cc.writeOK()
 	}
 	return err
 }
 
+// handleLoadData does the additional work after processing the 'load data' query.
+// It sends the file name to the client, then reads the content of the file in
+// packets until an empty packet is received. The rows are committed in batches
+// of tidb_dml_batch_size rows, each batch in its own transaction.
+func (cc *clientConn) handleLoadData(ctx context.Context, loadDataInfo *executor.LoadDataInfo) error {
+	// If the server handles the load data request, the client has to set the ClientLocalFiles capability.
+	if cc.capability&mysql.ClientLocalFiles == 0 {
+		return errNotAllowedCommand
+	}
+	if err := cc.writeReq(loadDataInfo.Path); err != nil {
+		return err
+	}
+
+	loadDataInfo.SetMaxRowsInBatch(uint64(loadDataInfo.Ctx.GetSessionVars().DMLBatchSize))
+	err := loadDataInfo.Ctx.NewTxn(ctx)
+	if err == nil {
+		err = cc.readAndInsertData(ctx, loadDataInfo)
+	}
+	if err == nil {
+		err = loadDataInfo.CheckAndInsertOneBatch(ctx)
+	}
+	if err == nil {
+		err = loadDataInfo.Ctx.StmtCommit()
+	}
+	if err != nil {
+		loadDataInfo.Ctx.StmtRollback()
+		cc.ctx.RollbackTxn()
+		return err
+	}
+	return cc.ctx.CommitTxn(ctx)
+}
+
+// readAndInsertData reads the content of the file from the client and inserts
+// the rows. After an error occurs, the remaining packets are still read so
+// that the connection is left in a consistent state.
+func (cc *clientConn) readAndInsertData(ctx context.Context, loadDataInfo *executor.LoadDataInfo) error {
+	var (
+		prevData []byte
+		err      error
+	)
+	for {
+		curData, err1 := cc.readPacket()
+		if err1 != nil {
+			return err1
+		}
+		if err == nil {
+			prevData, err = insertDataWithCommit(ctx, prevData, curData, loadDataInfo)
+		}
+		if len(curData) == 0 {
+			return err
+		}
+	}
+}
+
+// insertDataWithCommit parses curData with the rest data of the previous
+// packet. Every time the batch is full, it inserts the batch and commits the
+// transaction, then begins a new transaction for the following rows.
+func insertDataWithCommit(ctx context.Context, prevData, curData []byte, loadDataInfo *executor.LoadDataInfo) ([]byte, error) {
+	isEOF := len(curData) == 0
+	for {
+		rest, reachLimit, err := loadDataInfo.InsertData(ctx, prevData, curData)
+		if err != nil {
+			return nil, err
+		}
+		if !reachLimit {
+			return rest, nil
+		}
+		if err = loadDataInfo.CheckAndInsertOneBatch(ctx); err != nil {
+			return nil, err
+		}
+		if err = loadDataInfo.Ctx.StmtCommit(); err != nil {
+			return nil, err
+		}
+		if err = loadDataInfo.Ctx.RefreshTxnCtx(ctx); err != nil {
+			return nil, err
+		}
+		// An empty curData means the end of the file, so the rest data is
+		// passed as prevData at the end of the file.
+		if isEOF {
+			prevData, curData = rest, nil
+		} else {
+			prevData, curData = nil, rest
+		}
+	}
+}
+
+// writeReq writes the LOCAL INFILE request which asks the client to send the file.
+func (cc *clientConn) writeReq(filePath string) error {
+	data := cc.alloc.AllocWithLen(4, 5+len(filePath))
+	data = append(data, mysql.LocalInFileHeader)
+	data = append(data, filePath...)
+
+	err := cc.writePacket(data)
+	if err != nil {
+		return err
+	}
+
+	return cc.flush()
+}
+
 // handleFieldList returns the field list for a table.
 // The sql string is composed of a table name and a terminating character \x00.
 func (cc *clientConn) handleFieldList(sql string) (err error) {
@@ -1108,6 +1232,9 @@ func (cc getLastStmtInConn) String() string {
 		return "ListFields " + string(data)
 	case mysql.ComQuery, mysql.ComStmtPrepare:
 		return queryStrForLog(string(hack.String(data)))
+	case mysql.ComStmtExecute, mysql.ComStmtFetch:
+		stmtID := binary.LittleEndian.Uint32(data[0:4])
+		return queryStrForLog(cc.preparedStmt2String(stmtID))
 	case mysql.ComStmtClose, mysql.ComStmtReset:
 		stmtID := binary.LittleEndian.Uint32(data[0:4])
 		return mysql.Command2Str[cmd] + " " + strconv.Itoa(int(stmtID))
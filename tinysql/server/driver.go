@@ -17,8 +17,11 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"time"
+
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -66,6 +69,12 @@ type QueryCtx interface {
 	// FieldList returns columns of a table.
 	FieldList(tableName string) (columns []*ColumnInfo, err error)
 
+	// Prepare prepares a statement.
+	Prepare(sql string) (statement PreparedStatement, columns, params []*ColumnInfo, err error)
+
+	// GetStatement gets PreparedStatement by statement ID.
+	GetStatement(stmtID int) PreparedStatement
+
 	// Close closes the QueryCtx.
 	Close() error
 
@@ -73,6 +82,15 @@ type QueryCtx interface {
 	GetSessionVars() *variable.SessionVars
 
 	SetCommandValue(command byte)
+
+	// SetProcessInfo sets the process info of the session shown in the process list.
+	SetProcessInfo(sql string, t time.Time, command byte)
+
+	// ShowProcess returns the process info of the session.
+	ShowProcess() *util.ProcessInfo
+
+	// SetSessionManager sets the session manager.
+	SetSessionManager(util.SessionManager)
 }
 
 // PreparedStatement is the interface to use a prepared statement.
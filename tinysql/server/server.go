@@ -58,9 +58,11 @@ var (
 )
 
 var (
-	errInvalidSequence = terror.ClassServer.New(mysql.ErrInvalidSequence, mysql.MySQLErrName[mysql.ErrInvalidSequence])
-	errInvalidType     = terror.ClassServer.New(mysql.ErrInvalidType, mysql.MySQLErrName[mysql.ErrInvalidType])
-	errAccessDenied    = terror.ClassServer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDenied])
+	errInvalidSequence   = terror.ClassServer.New(mysql.ErrInvalidSequence, mysql.MySQLErrName[mysql.ErrInvalidSequence])
+	errInvalidType       = terror.ClassServer.New(mysql.ErrInvalidType, mysql.MySQLErrName[mysql.ErrInvalidType])
+	errAccessDenied      = terror.ClassServer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDenied])
+	errUnknownFieldType  = terror.ClassServer.New(mysql.ErrUnknownFieldType, mysql.MySQLErrName[mysql.ErrUnknownFieldType])
+	errNotAllowedCommand = terror.ClassServer.New(mysql.ErrNotAllowedCommand, mysql.MySQLErrName[mysql.ErrNotAllowedCommand])
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -246,6 +248,24 @@ func killConn(conn *clientConn) {
 	atomic.CompareAndSwapUint32(&sessVars.Killed, 0, 1)
 }
 
+// ShowProcessList implements the SessionManager interface.
+func (s *Server) ShowProcessList() map[uint64]*util.ProcessInfo {
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	rs := make(map[uint64]*util.ProcessInfo, len(s.clients))
+	for _, client := range s.clients {
+		if atomic.LoadInt32(&client.status) == connStatusWaitShutdown || client.ctx == nil {
+			continue
+		}
+		if pi := client.ctx.ShowProcess(); pi != nil {
+			info := *pi
+			info.User, info.Host = client.user, client.peerHost
+			rs[info.ID] = &info
+		}
+	}
+	return rs
+}
+
 // KillAllConnections kills all connections when server is not gracefully shutdown.
 func (s *Server) KillAllConnections() {
 	logutil.BgLogger().Info("[server] kill all connections.")
@@ -148,6 +148,90 @@ func runTestResultFieldTableIsNull(c *C) {
 	})
 }
 
+func runTestPreparedStatement(c *C) {
+	runTestsOnNewDB(c, nil, "PreparedStatement", func(dbt *DBTest) {
+		dbt.mustExec("create table test (id int primary key, val varchar(20), d datetime, f double)")
+		stmt, err := dbt.db.Prepare("insert into test values (?, ?, ?, ?)")
+		dbt.Assert(err, IsNil)
+		_, err = stmt.Exec(1, "a", "2020-01-01 10:00:00", 1.5)
+		dbt.Assert(err, IsNil)
+		_, err = stmt.Exec(2, nil, "2020-01-02 11:00:00", -2.25)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(stmt.Close(), IsNil)
+
+		stmt, err = dbt.db.Prepare("select val, d, f from test where id = ?")
+		dbt.Assert(err, IsNil)
+		defer stmt.Close()
+		var (
+			val sql.NullString
+			d   string
+			f   float64
+		)
+		err = stmt.QueryRow(1).Scan(&val, &d, &f)
+		dbt.Assert(err, IsNil)
+		dbt.Check(val.String, Equals, "a")
+		dbt.Check(d, Equals, "2020-01-01 10:00:00")
+		dbt.Check(f, Equals, 1.5)
+		err = stmt.QueryRow(2).Scan(&val, &d, &f)
+		dbt.Assert(err, IsNil)
+		dbt.Check(val.Valid, IsFalse)
+		dbt.Check(f, Equals, -2.25)
+		err = stmt.QueryRow(3).Scan(&val, &d, &f)
+		dbt.Check(err, Equals, sql.ErrNoRows)
+
+		// Wrong parameter count is rejected by the driver before hitting the server.
+		_, err = stmt.Query(1, 2)
+		dbt.Check(err, NotNil)
+	})
+}
+
+func runTestLoadData(c *C) {
+	fp, err := ioutil.TempFile("", "load_data_test.csv")
+	c.Assert(err, IsNil)
+	path := fp.Name()
+	defer func() {
+		c.Assert(fp.Close(), IsNil)
+		c.Assert(os.Remove(path), IsNil)
+	}()
+	for i := 1; i <= 10; i++ {
+		_, err = fp.WriteString(fmt.Sprintf("%d,v%d\n", i, i))
+		c.Assert(err, IsNil)
+	}
+	_, err = fp.WriteString("3,v30\n11")
+	c.Assert(err, IsNil)
+
+	runTestsOnNewDB(c, func(config *mysql.Config) {
+		// The duplicate row and the incomplete row produce warnings.
+		config.Strict = false
+		config.AllowAllFiles = true
+		config.Params = map[string]string{"tidb_dml_batch_size": "3"}
+	}, "LoadData", func(dbt *DBTest) {
+		dbt.mustExec("create table test (id int primary key, val varchar(20))")
+		rs := dbt.mustExec(fmt.Sprintf("load data local infile %q into table test fields terminated by ','", path))
+		affected, err := rs.RowsAffected()
+		dbt.Assert(err, IsNil)
+		dbt.Assert(affected, Equals, int64(11))
+
+		// The incomplete row takes the default value and the duplicate row is skipped.
+		rows, err := dbt.db.Query("select count(*), count(val), max(id) from test")
+		dbt.Assert(err, IsNil)
+		dbt.Assert(rows.Next(), IsTrue)
+		var cnt, cntVal, maxID int
+		dbt.Assert(rows.Scan(&cnt, &cntVal, &maxID), IsNil)
+		dbt.Assert(cnt, Equals, 10+1)
+		dbt.Assert(cntVal, Equals, 10)
+		dbt.Assert(maxID, Equals, 11)
+		dbt.Assert(rows.Close(), IsNil)
+		var val string
+		dbt.Assert(dbt.db.QueryRow("select val from test where id = 3").Scan(&val), IsNil)
+		dbt.Assert(val, Equals, "v3")
+
+		// The file on the server is not supported.
+		_, err = dbt.db.Exec(fmt.Sprintf("load data infile %q into table test", path))
+		dbt.Assert(err, NotNil)
+	})
+}
+
 const retryTime = 100
 
 func waitUntilServerOnline(statusPort uint) {
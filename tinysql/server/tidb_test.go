@@ -82,6 +82,16 @@ func (ts *TidbTestSuite) TestResultFieldTableIsNull(c *C) {
 	runTestResultFieldTableIsNull(c)
 }
 
+func (ts *TidbTestSuite) TestPreparedStatement(c *C) {
+	c.Parallel()
+	runTestPreparedStatement(c)
+}
+
+func (ts *TidbTestSuite) TestLoadData(c *C) {
+	c.Parallel()
+	runTestLoadData(c)
+}
+
 func (ts *TidbTestSuite) TestShowTablesFlen(c *C) {
 	qctx, err := ts.tidbdrv.OpenCtx(uint64(0), 0, uint8(tmysql.DefaultCollationID), "test", nil)
 	c.Assert(err, IsNil)
@@ -14,6 +14,8 @@
 package server
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
@@ -22,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -109,6 +112,12 @@ func (s *testUtilSuite) TestDumpTextValue(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "2.20")
 
+	d := types.NewDecimalDatum(types.NewDecFromFloatForTest(1.23))
+	columns[0].Type = mysql.TypeNewDecimal
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{d}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "1.23")
+
 	columns[0].Type = mysql.TypeBlob
 	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewBytesDatum([]byte("foo"))}).ToRow())
 	c.Assert(err, IsNil)
@@ -130,6 +139,52 @@ func (s *testUtilSuite) TestDumpTextValue(c *C) {
 	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{year}).ToRow())
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "1984")
+
+	dt, err := types.ParseTime(nil, "2020-01-02 10:11:12.345", mysql.TypeDatetime, 3)
+	c.Assert(err, IsNil)
+	columns[0].Type = mysql.TypeDatetime
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewTimeDatum(dt)}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "2020-01-02 10:11:12.345")
+
+	dur, err := types.ParseDuration(nil, "-10:11:12", 0)
+	c.Assert(err, IsNil)
+	columns[0].Type = mysql.TypeDuration
+	columns[0].Decimal = 0
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewDurationDatum(dur)}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, "-10:11:12")
+
+	j, err := json.ParseBinaryFromString(`{"a": [1, "b"]}`)
+	c.Assert(err, IsNil)
+	columns[0].Type = mysql.TypeJSON
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewJSONDatum(j)}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, `{"a": [1, "b"]}`)
+}
+
+func (s *testUtilSuite) TestDumpBinaryTime(c *C) {
+	t, err := types.ParseTimestamp(nil, "0000-00-00 00:00:00.000000")
+	c.Assert(err, IsNil)
+	d := dumpBinaryDateTime(nil, t)
+	c.Assert(d, DeepEquals, []byte{11, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	t, err = types.ParseDatetime(nil, "0000-00-00 00:00:00.000000")
+	c.Assert(err, IsNil)
+	d = dumpBinaryDateTime(nil, t)
+	c.Assert(d, DeepEquals, []byte{11, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+
+	t, err = types.ParseDate(nil, "0000-00-00")
+	c.Assert(err, IsNil)
+	d = dumpBinaryDateTime(nil, t)
+	c.Assert(d, DeepEquals, []byte{4, 0, 0, 0, 0})
+
+	myDuration, err := types.ParseDuration(nil, "0000-00-00 00:00:00.000000", 6)
+	c.Assert(err, IsNil)
+	d = dumpBinaryTime(myDuration.Duration)
+	c.Assert(d, DeepEquals, []byte{0})
+
+	d = dumpBinaryTime(-(25*time.Hour + 2*time.Minute + 3*time.Second + 4*time.Microsecond))
+	c.Assert(d, DeepEquals, []byte{12, 1, 1, 0, 0, 0, 1, 2, 3, 4, 0, 0, 0})
 }
 
 func mustDecodeStr(c *C, b []byte) string {
@@ -120,6 +120,18 @@ const (
 		count bigint(64) UNSIGNED NOT NULL,
 		index tbl(table_id, is_index, hist_id)
 	);`
+
+	// CreateBindInfoTable stores the sql bind info which is used to update globalBindCache.
+	CreateBindInfoTable = `CREATE TABLE IF NOT EXISTS mysql.bind_info (
+		original_sql text NOT NULL,
+		bind_sql text NOT NULL,
+		default_db text NOT NULL,
+		status text NOT NULL,
+		create_time timestamp(3) NOT NULL,
+		update_time timestamp(3) NOT NULL,
+		INDEX sql_index(original_sql(1024), default_db(1024)),
+		INDEX time_index(update_time)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -231,6 +243,8 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateGCDeleteRangeDoneTable)
 	// Create stats_topn_store table.
 	mustExecute(s, CreateStatsTopNTable)
+	// Create bind_info table.
+	mustExecute(s, CreateBindInfoTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
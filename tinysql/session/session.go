@@ -30,6 +30,7 @@ import (
 	"github.com/ngaut/pools"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/infoschema"
@@ -42,12 +43,14 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"go.uber.org/zap"
@@ -66,6 +69,9 @@ type Session interface {
 	SetClientCapability(uint32) // Set client capability flags.
 	SetConnectionID(uint64)
 	SetCommandValue(byte)
+	SetProcessInfo(string, time.Time, byte)
+	ShowProcess() *util.ProcessInfo
+	SetSessionManager(util.SessionManager)
 	SetTLSState(*tls.ConnectionState)
 	SetCollation(coID int) error
 	Close()
@@ -73,6 +79,12 @@ type Session interface {
 	PrepareTxnCtx(context.Context)
 	// FieldList returns fields list of a table.
 	FieldList(tableName string) (fields []*ast.ResultField, err error)
+	// PrepareStmt executes prepare statement in binary protocol.
+	PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error)
+	// ExecutePreparedStmt executes a prepared statement.
+	ExecutePreparedStmt(ctx context.Context, stmtID uint32, param []types.Datum) (sqlexec.RecordSet, error)
+	// DropPreparedStmt drops a prepared statement.
+	DropPreparedStmt(stmtID uint32) error
 }
 
 var (
@@ -122,6 +134,22 @@ type session struct {
 
 	// shared coprocessor client per session
 	client kv.Client
+
+	// preparedPlanCache caches the plans of the prepared statements.
+	preparedPlanCache *kvcache.SimpleLRUCache
+
+	processInfo    atomic.Value
+	sessionManager util.SessionManager
+}
+
+// PreparedPlanCache returns the plan cache of the prepared statements, it is
+// rebuilt when the capacity is changed by tidb_prepared_plan_cache_size.
+func (s *session) PreparedPlanCache() *kvcache.SimpleLRUCache {
+	capacity := uint(s.sessionVars.PreparedPlanCacheSize)
+	if s.preparedPlanCache == nil || s.preparedPlanCache.Capacity() != capacity {
+		s.preparedPlanCache = kvcache.NewSimpleLRUCache(capacity)
+	}
+	return s.preparedPlanCache
 }
 
 // DDLOwnerChecker returns s.ddlOwnerChecker.
@@ -167,6 +195,39 @@ func (s *session) SetCommandValue(command byte) {
 	atomic.StoreUint32(&s.sessionVars.CommandValue, uint32(command))
 }
 
+// SetProcessInfo sets the process info of the session shown in the process
+// list, the statement context is kept to show its memory usage.
+func (s *session) SetProcessInfo(sql string, t time.Time, command byte) {
+	pi := util.ProcessInfo{
+		ID:      s.sessionVars.ConnectionID,
+		DB:      s.sessionVars.CurrentDB,
+		Command: command,
+		Time:    t,
+		State:   s.Status(),
+		Info:    sql,
+		StmtCtx: s.sessionVars.StmtCtx,
+	}
+	s.processInfo.Store(&pi)
+}
+
+// ShowProcess returns the process info of the session.
+func (s *session) ShowProcess() *util.ProcessInfo {
+	if pi, ok := s.processInfo.Load().(*util.ProcessInfo); ok {
+		return pi
+	}
+	return nil
+}
+
+// SetSessionManager sets the session manager used by the process list.
+func (s *session) SetSessionManager(sm util.SessionManager) {
+	s.sessionManager = sm
+}
+
+// GetSessionManager implements the sessionctx.Context interface.
+func (s *session) GetSessionManager() util.SessionManager {
+	return s.sessionManager
+}
+
 func (s *session) SetCollation(coID int) error {
 	cs, co, err := charset.GetCharsetInfoByID(coID)
 	if err != nil {
@@ -612,6 +673,7 @@ func (s *session) execute(ctx context.Context, sql string) (recordSets []sqlexec
 		if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
 			return nil, err
 		}
+		s.SetProcessInfo(stmtNode.Text(), s.sessionVars.StartTime, byte(atomic.LoadUint32(&s.sessionVars.CommandValue)))
 		var stmt *executor.ExecStmt
 		// Hint: step I.3.2
 		// YOUR CODE HERE (lab4)
@@ -661,6 +723,56 @@ func (s *session) rollbackOnError(ctx context.Context) {
 	}
 }
 
+// PrepareStmt is used for executing prepare statement in binary protocol
+func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.TxnCtx.InfoSchema == nil {
+		// We don't need to create a transaction for prepare statement, just get information schema will do.
+		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
+	}
+	err = s.loadCommonGlobalVariablesIfNeeded()
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	inTxn := s.GetSessionVars().InTxn()
+	// NewPrepareExec may need startTS to build the executor, for example prepare statement has subquery in int.
+	// So we have to call PrepareTxnCtx here.
+	s.PrepareTxnCtx(ctx)
+	prepareExec := executor.NewPrepareExec(s, infoschema.GetInfoSchema(s), sql)
+	err = prepareExec.Next(ctx, nil)
+	if err != nil {
+		return
+	}
+	if !inTxn {
+		// We could start a transaction to build the prepare executor before, we should rollback it here.
+		s.RollbackTxn(ctx)
+	}
+	return prepareExec.ID, prepareExec.ParamCount, prepareExec.Fields, nil
+}
+
+// ExecutePreparedStmt executes a prepared statement.
+func (s *session) ExecutePreparedStmt(ctx context.Context, stmtID uint32, args []types.Datum) (sqlexec.RecordSet, error) {
+	s.PrepareTxnCtx(ctx)
+	s.sessionVars.StartTime = time.Now()
+	st, err := executor.CompileExecutePreparedStmt(ctx, s, stmtID, args)
+	if err != nil {
+		return nil, err
+	}
+	s.SetProcessInfo(st.OriginText(), s.sessionVars.StartTime, mysql.ComStmtExecute)
+	return runStmt(ctx, s, st)
+}
+
+// DropPreparedStmt drops a prepared statement.
+func (s *session) DropPreparedStmt(stmtID uint32) error {
+	vars := s.sessionVars
+	if _, ok := vars.PreparedStmts[stmtID]; !ok {
+		return plannercore.ErrStmtNotFound
+	}
+	vars.RemovePreparedStmt(stmtID)
+	return nil
+}
+
 func (s *session) Txn(active bool) (kv.Transaction, error) {
 	if !s.txn.validOrPending() && active {
 		return &s.txn, kv.ErrInvalidTxn
@@ -744,6 +856,9 @@ func (s *session) ClearValue(key fmt.Stringer) {
 func (s *session) Close() {
 	ctx := context.TODO()
 	s.RollbackTxn(ctx)
+	if s.sessionVars != nil {
+		s.sessionVars.WithdrawAllPreparedStmt()
+	}
 }
 
 // GetSessionVars implements the context.Context interface.
@@ -794,6 +909,19 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	if err != nil {
 		return nil, err
 	}
+	se2, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	// The stores bootstrapped before the sql bindings are supported don't
+	// have the bind_info table, create it before loading the bindings.
+	if _, err = se2.Execute(context.Background(), CreateBindInfoTable); err != nil {
+		return nil, err
+	}
+	err = dom.LoadBindInfoLoop(se2)
+	if err != nil {
+		return nil, err
+	}
 
 	return dom, err
 }
@@ -840,6 +968,7 @@ func createSession(store kv.Storage) (*session, error) {
 	domain.BindDomain(s, dom)
 	// session implements variable.GlobalVarAccessor. Bind it to ctx.
 	s.sessionVars.GlobalVarsAccessor = s
+	s.SetValue(bindinfo.SessionBindInfoKeyType, bindinfo.NewSessionBindHandle(s.parser))
 	s.txn.init()
 	return s, nil
 }
@@ -942,6 +1071,7 @@ var builtinGlobalVariable = []string{
 	/* TiDB specific global variables: */
 	variable.TiDBSkipUTF8Check,
 	variable.TiDBIndexLookupSize,
+	variable.TiDBDMLBatchSize,
 	variable.TiDBIndexLookupConcurrency,
 	variable.TiDBIndexLookupJoinConcurrency,
 	variable.TiDBIndexSerialScanConcurrency,
@@ -963,6 +1093,7 @@ var builtinGlobalVariable = []string{
 	variable.TiDBOptNetworkFactor,
 	variable.TiDBOptScanFactor,
 	variable.TiDBOptDescScanFactor,
+	variable.TiDBOptSeekFactor,
 	variable.TiDBOptMemoryFactor,
 	variable.TiDBOptDiskFactor,
 	variable.TiDBOptConcurrencyFactor,
@@ -973,6 +1104,8 @@ var builtinGlobalVariable = []string{
 	variable.TiDBEnableVectorizedExpression,
 	variable.TiDBEnableNoopFuncs,
 	variable.TiDBMaxDeltaSchemaCount,
+	variable.TiDBEnablePreparedPlanCache,
+	variable.TiDBPreparedPlanCacheSize,
 }
 
 var (
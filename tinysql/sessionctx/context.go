@@ -20,6 +20,8 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/kvcache"
 )
 
 // Context is an interface for transaction and executive args environment.
@@ -49,6 +51,9 @@ type Context interface {
 
 	GetSessionVars() *variable.SessionVars
 
+	// PreparedPlanCache returns the cache of the plans of the prepared statements.
+	PreparedPlanCache() *kvcache.SimpleLRUCache
+
 	// RefreshTxnCtx commits old transaction without retry,
 	// and creates a new transaction.
 	// now just for load data and batch insert.
@@ -61,6 +66,9 @@ type Context interface {
 	// GetStore returns the store of session.
 	GetStore() kv.Storage
 
+	// GetSessionManager gets session manager.
+	GetSessionManager() util.SessionManager
+
 	// StmtCommit flush all changes by the statement to the underlying transaction.
 	StmtCommit() error
 	// StmtRollback provides statement level rollback.
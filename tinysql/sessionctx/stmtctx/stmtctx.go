@@ -20,6 +20,9 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
 )
 
@@ -48,11 +51,14 @@ type StatementContext struct {
 	// If IsDDLJobInQueue is true, it means the DDL job is in the queue of storage, and it can be handled by the DDL worker.
 	IsDDLJobInQueue        bool
 	InInsertStmt           bool
+	InLoadDataStmt         bool
+	InUpdateStmt           bool
 	InDeleteStmt           bool
 	InSelectStmt           bool
 	InExplainStmt          bool
 	IgnoreTruncate         bool
 	IgnoreZeroInDate       bool
+	DupKeyAsWarning        bool
 	BadNullAsWarning       bool
 	DividedByZeroAsWarning bool
 	TruncateAsWarning      bool
@@ -67,6 +73,12 @@ type StatementContext struct {
 	// then cast the float string to int string. Otherwise, we cast string to integer
 	// prefix in a strict way, only extract 0-9 and (+ or - in first bit).
 	CastStrToIntStrict bool
+	// UseCache indicates the plan of the statement may be put into the plan
+	// cache, so the parameters are kept as mutable constants.
+	UseCache bool
+	// SkipPlanCache is set when the optimization depends on the values of the
+	// parameters, the plan of such statement must not be cached.
+	SkipPlanCache bool
 
 	// mu struct holds variables that change during execution.
 	mu struct {
@@ -109,13 +121,20 @@ type StatementContext struct {
 	MaxRowID  int64
 
 	// Copied from SessionVars.TimeZone.
-	TimeZone       *time.Location
-	NotFillCache   bool
-	TableIDs       []int64
-	IndexNames     []string
-	nowTs          time.Time // use this variable for now/current_timestamp calculation/cache for one stmt
-	stmtTimeCached bool
-	StmtType       string
+	TimeZone         *time.Location
+	NotFillCache     bool
+	MemTracker       *memory.Tracker
+	DiskTracker      *memory.Tracker
+	RuntimeStatsColl *execdetails.RuntimeStatsColl
+	TableIDs         []int64
+	IndexNames       []string
+	nowTs            time.Time // use this variable for now/current_timestamp calculation/cache for one stmt
+	stmtTimeCached   bool
+	StmtType         string
+
+	// OptimizeTracer records the optimization of the statement when the
+	// optimizer trace is enabled.
+	OptimizeTracer *tracing.OptimizeTracer
 }
 
 // StmtHints are SessionVars related sql hints.
@@ -277,6 +296,20 @@ func (sc *StatementContext) SetWarnings(warns []SQLWarn) {
 	sc.mu.Unlock()
 }
 
+// TruncateWarnings truncates warnings begin from start and returns the truncated warnings.
+func (sc *StatementContext) TruncateWarnings(start int) []SQLWarn {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sz := len(sc.mu.warnings) - start
+	if sz <= 0 {
+		return nil
+	}
+	ret := make([]SQLWarn, sz)
+	copy(ret, sc.mu.warnings[start:])
+	sc.mu.warnings = sc.mu.warnings[:start]
+	return ret
+}
+
 // AppendWarning appends a warning with level 'Warning'.
 func (sc *StatementContext) AppendWarning(warn error) {
 	sc.mu.Lock()
@@ -359,13 +392,13 @@ func (sc *StatementContext) ResetForRetry() {
 func (sc *StatementContext) ShouldClipToZero() bool {
 	// TODO: Currently altering column of integer to unsigned integer is not supported.
 	// If it is supported one day, that case should be added here.
-	return sc.InInsertStmt
+	return sc.InInsertStmt || sc.InLoadDataStmt || sc.InUpdateStmt
 }
 
 // ShouldIgnoreOverflowError indicates whether we should ignore the error when type conversion overflows,
 // so we can leave it for further processing like clipping values less than 0 to 0 for unsigned integer types.
 func (sc *StatementContext) ShouldIgnoreOverflowError() bool {
-	if sc.InInsertStmt && sc.TruncateAsWarning {
+	if (sc.InInsertStmt && sc.TruncateAsWarning) || sc.InLoadDataStmt {
 		return true
 	}
 	return false
@@ -376,7 +409,7 @@ func (sc *StatementContext) PushDownFlags() uint64 {
 	var flags uint64
 	if sc.InInsertStmt {
 		flags |= model.FlagInInsertStmt
-	} else if sc.InDeleteStmt {
+	} else if sc.InUpdateStmt || sc.InDeleteStmt {
 		flags |= model.FlagInUpdateOrDeleteStmt
 	} else if sc.InSelectStmt {
 		flags |= model.FlagInSelectStmt
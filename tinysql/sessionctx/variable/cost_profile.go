@@ -0,0 +1,131 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+)
+
+// CostProfile is a set of the cost factors used by the optimizer. It is
+// written by the cost calibration tool and loaded by
+// `SET GLOBAL tidb_opt_cost_profile = 'name'` from the cost-profile-dir, so
+// the optimizer can be tuned for the hardware. The json keys are the names of
+// the system variables.
+type CostProfile struct {
+	CPUFactor         float64 `json:"tidb_opt_cpu_factor"`
+	CopCPUFactor      float64 `json:"tidb_opt_copcpu_factor"`
+	NetworkFactor     float64 `json:"tidb_opt_network_factor"`
+	ScanFactor        float64 `json:"tidb_opt_scan_factor"`
+	DescScanFactor    float64 `json:"tidb_opt_desc_factor"`
+	SeekFactor        float64 `json:"tidb_opt_seek_factor"`
+	MemoryFactor      float64 `json:"tidb_opt_memory_factor"`
+	DiskFactor        float64 `json:"tidb_opt_disk_factor"`
+	ConcurrencyFactor float64 `json:"tidb_opt_concurrency_factor"`
+}
+
+// NewCostProfile creates a CostProfile with the default cost factors.
+func NewCostProfile() *CostProfile {
+	return &CostProfile{
+		CPUFactor:         DefOptCPUFactor,
+		CopCPUFactor:      DefOptCopCPUFactor,
+		NetworkFactor:     DefOptNetworkFactor,
+		ScanFactor:        DefOptScanFactor,
+		DescScanFactor:    DefOptDescScanFactor,
+		SeekFactor:        DefOptSeekFactor,
+		MemoryFactor:      DefOptMemoryFactor,
+		DiskFactor:        DefOptDiskFactor,
+		ConcurrencyFactor: DefOptConcurrencyFactor,
+	}
+}
+
+// CostProfilePath returns the path of the cost profile named name in the
+// cost-profile-dir. An error is returned if the directory is not configured.
+func CostProfilePath(name string) (string, error) {
+	dir := config.GetGlobalConfig().CostProfileDir
+	if dir == "" || !isCostProfileName(name) {
+		return "", ErrWrongValueForVar.GenWithStackByArgs(TiDBOptCostProfile, name)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// isCostProfileName checks whether name is a plain file name, so only the
+// files in the cost-profile-dir can be loaded.
+func isCostProfileName(name string) bool {
+	if name == "" || name[0] == '.' {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// LoadCostProfile loads the CostProfile from the json file. The factors
+// missing in the file keep their default values.
+func LoadCostProfile(path string) (*CostProfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p := NewCostProfile()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(p); err != nil {
+		return nil, errors.Annotatef(err, "invalid cost profile %s", path)
+	}
+	for name, value := range p.SysVars() {
+		if v, _ := strconv.ParseFloat(value, 64); v < 0 {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+	}
+	return p, nil
+}
+
+// Save writes the CostProfile to the json file.
+func (p *CostProfile) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(path, append(data, '\n'), 0644))
+}
+
+// SysVars returns the values of the cost factors as system variables.
+func (p *CostProfile) SysVars() map[string]string {
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return map[string]string{
+		TiDBOptCPUFactor:         format(p.CPUFactor),
+		TiDBOptCopCPUFactor:      format(p.CopCPUFactor),
+		TiDBOptNetworkFactor:     format(p.NetworkFactor),
+		TiDBOptScanFactor:        format(p.ScanFactor),
+		TiDBOptDescScanFactor:    format(p.DescScanFactor),
+		TiDBOptSeekFactor:        format(p.SeekFactor),
+		TiDBOptMemoryFactor:      format(p.MemoryFactor),
+		TiDBOptDiskFactor:        format(p.DiskFactor),
+		TiDBOptConcurrencyFactor: format(p.ConcurrencyFactor),
+	}
+}
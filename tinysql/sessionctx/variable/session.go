@@ -30,7 +30,9 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/stringutil"
 )
 
 // Error instances.
@@ -124,6 +126,20 @@ func (ib *WriteStmtBufs) clean() {
 	ib.IndexKeyBuf = nil
 }
 
+// PreparedStmtCount is the number of prepared statements of all the sessions,
+// it is checked against the max_prepared_stmt_count system variable.
+var PreparedStmtCount int64
+
+// PreparedParams contains the parameters of the current prepared statement when executing it.
+type PreparedParams []types.Datum
+
+func (pps PreparedParams) String() string {
+	if len(pps) == 0 {
+		return ""
+	}
+	return " [arguments: " + types.DatumsToStrNoErr(pps) + "]"
+}
+
 // SessionVars is to handle user-defined or global variables in the current session.
 type SessionVars struct {
 	Concurrency
@@ -138,6 +154,13 @@ type SessionVars struct {
 	SysWarningCount int
 	// SysErrorCount is the system variable "error_count", because it is on the hot path, so we extract it from the systems
 	SysErrorCount uint16
+	// PreparedStmts stores prepared statement.
+	PreparedStmts        map[uint32]interface{}
+	PreparedStmtNameToID map[string]uint32
+	// preparedStmtID is id of prepared statement.
+	preparedStmtID uint32
+	// PreparedParams params for prepared statements
+	PreparedParams PreparedParams
 
 	//  TxnCtx Should be reset on transaction finished.
 	TxnCtx *TransactionContext
@@ -317,6 +340,49 @@ type SessionVars struct {
 
 	// RowEncoder is reused in session for encode row data.
 	RowEncoder rowcodec.Encoder
+
+	// EnablePreparedPlanCache indicates whether the plans of the prepared statements are cached.
+	EnablePreparedPlanCache bool
+
+	// PreparedPlanCacheSize is the capacity of the plan cache of the session.
+	PreparedPlanCacheSize int
+
+	// SysVarsVersion is increased whenever a system variable is set, so the
+	// plans built under the old values are not reused from the plan cache.
+	SysVarsVersion uint64
+
+	// PlanCacheHits is the number of the executions which reuse a cached plan.
+	PlanCacheHits uint64
+
+	// PlanCacheMisses is the number of the executions of the cacheable
+	// statements which don't find a cached plan.
+	PlanCacheMisses uint64
+
+	// EnableOptimizerTrace indicates whether the optimization of the statements is traced.
+	EnableOptimizerTrace bool
+
+	// OptimizerTraceOneLine indicates whether the optimizer trace is encoded in one line.
+	OptimizerTraceOneLine bool
+
+	// LastOptimizerTrace is the optimizer trace of the last traced statement,
+	// it is shown in INFORMATION_SCHEMA.OPTIMIZER_TRACE.
+	LastOptimizerTrace *OptimizerTraceInfo
+
+	// MemQuotaQuery is the memory quota of a query in bytes.
+	MemQuotaQuery int64
+
+	// OOMAction is the action taken when a query exceeds MemQuotaQuery.
+	OOMAction string
+
+	// MemTracker tracks the memory usage of the session, the trackers of the
+	// statements are attached to it.
+	MemTracker *memory.Tracker
+}
+
+// OptimizerTraceInfo is the optimizer trace of a statement.
+type OptimizerTraceInfo struct {
+	Query string
+	Trace string
 }
 
 // ConnectionInfo present connection used by audit.
@@ -344,6 +410,9 @@ func NewSessionVars() *SessionVars {
 	vars := &SessionVars{
 		Users:                       make(map[string]string),
 		systems:                     make(map[string]string),
+		PreparedStmts:               make(map[uint32]interface{}),
+		PreparedStmtNameToID:        make(map[string]uint32),
+		PreparedParams:              make([]types.Datum, 0, 10),
 		TxnCtx:                      &TransactionContext{},
 		KVVars:                      kv.NewVariables(),
 		StrictSQLMode:               true,
@@ -372,7 +441,14 @@ func NewSessionVars() *SessionVars {
 		EnableNoopFuncs:             DefTiDBEnableNoopFuncs,
 		replicaRead:                 kv.ReplicaReadLeader,
 		AllowRemoveAutoInc:          DefTiDBAllowRemoveAutoInc,
+		EnablePreparedPlanCache:     DefTiDBEnablePreparedPlanCache,
+		PreparedPlanCacheSize:       DefTiDBPreparedPlanCacheSize,
+		MemQuotaQuery:               DefTiDBMemQuotaQuery,
+		OOMAction:                   DefTiDBMemOOMAction,
 	}
+	vars.MemTracker = memory.NewTracker(stringutil.StringerFunc(func() string {
+		return fmt.Sprintf("Session(connID=%d)", vars.ConnectionID)
+	}), -1)
 	vars.Concurrency = Concurrency{
 		IndexLookupConcurrency:     DefIndexLookupConcurrency,
 		IndexSerialScanConcurrency: DefIndexSerialScanConcurrency,
@@ -385,6 +461,7 @@ func NewSessionVars() *SessionVars {
 	}
 	vars.BatchSize = BatchSize{
 		IndexLookupSize: DefIndexLookupSize,
+		DMLBatchSize:    DefDMLBatchSize,
 		InitChunkSize:   DefInitChunkSize,
 		MaxChunkSize:    DefMaxChunkSize,
 	}
@@ -432,6 +509,49 @@ func (s *SessionVars) CleanBuffers() {
 	s.GetWriteStmtBufs().clean()
 }
 
+// GetNextPreparedStmtID generates and returns the next session scope prepared statement id.
+func (s *SessionVars) GetNextPreparedStmtID() uint32 {
+	s.preparedStmtID++
+	return s.preparedStmtID
+}
+
+// AddPreparedStmt adds prepareStmt to current session and count in global.
+func (s *SessionVars) AddPreparedStmt(stmtID uint32, stmt interface{}) error {
+	if _, exists := s.PreparedStmts[stmtID]; !exists {
+		valStr, _ := s.GetSystemVar(MaxPreparedStmtCount)
+		maxPreparedStmtCount, err := strconv.ParseInt(valStr, 10, 64)
+		if err != nil {
+			maxPreparedStmtCount = DefMaxPreparedStmtCount
+		}
+		newPreparedStmtCount := atomic.AddInt64(&PreparedStmtCount, 1)
+		if maxPreparedStmtCount >= 0 && newPreparedStmtCount > maxPreparedStmtCount {
+			atomic.AddInt64(&PreparedStmtCount, -1)
+			return ErrMaxPreparedStmtCountReached.GenWithStackByArgs(maxPreparedStmtCount)
+		}
+	}
+	s.PreparedStmts[stmtID] = stmt
+	return nil
+}
+
+// RemovePreparedStmt removes preparedStmt from current session and decrease count in global.
+func (s *SessionVars) RemovePreparedStmt(stmtID uint32) {
+	_, exists := s.PreparedStmts[stmtID]
+	if !exists {
+		return
+	}
+	delete(s.PreparedStmts, stmtID)
+	atomic.AddInt64(&PreparedStmtCount, -1)
+}
+
+// WithdrawAllPreparedStmt remove all preparedStmt in current session and decrease count in global.
+func (s *SessionVars) WithdrawAllPreparedStmt() {
+	psCount := len(s.PreparedStmts)
+	if psCount == 0 {
+		return
+	}
+	atomic.AddInt64(&PreparedStmtCount, -int64(psCount))
+}
+
 // AllocPlanColumnID allocates column id for plan.
 func (s *SessionVars) AllocPlanColumnID() int64 {
 	s.PlanColumnID++
@@ -521,6 +641,7 @@ func (s *SessionVars) setDDLReorgPriority(val string) {
 
 // SetSystemVar sets the value of a system variable.
 func (s *SessionVars) SetSystemVar(name string, val string) error {
+	s.SysVarsVersion++
 	switch name {
 	case TxnIsolationOneShot:
 		switch val {
@@ -553,6 +674,12 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.StrictSQLMode = sqlMode.HasStrictMode()
 		s.SQLMode = sqlMode
 		s.SetStatusFlag(mysql.ServerStatusNoBackslashEscaped, sqlMode.HasNoBackslashEscapesMode())
+	case TimeZone:
+		tz, err := parseTimeZone(val)
+		if err != nil {
+			return err
+		}
+		s.TimeZone = tz
 	case AutoCommit:
 		isAutocommit := TiDBOptOn(val)
 		s.SetStatusFlag(mysql.ServerStatusAutocommit, isAutocommit)
@@ -598,6 +725,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.IndexLookupJoinConcurrency = tidbOptPositiveInt32(val, DefIndexLookupJoinConcurrency)
 	case TiDBIndexLookupSize:
 		s.IndexLookupSize = tidbOptPositiveInt32(val, DefIndexLookupSize)
+	case TiDBDMLBatchSize:
+		s.DMLBatchSize = tidbOptPositiveInt32(val, DefDMLBatchSize)
 	case TiDBHashJoinConcurrency:
 		s.HashJoinConcurrency = tidbOptPositiveInt32(val, DefTiDBHashJoinConcurrency)
 	case TiDBProjectionConcurrency:
@@ -626,6 +755,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		atomic.StoreUint32(&ProcessGeneralLog, uint32(tidbOptPositiveInt32(val, DefTiDBGeneralLog)))
 	case TiDBEnableCascadesPlanner:
 		s.EnableCascadesPlanner = TiDBOptOn(val)
+	case OptimizerTrace:
+		s.EnableOptimizerTrace, s.OptimizerTraceOneLine, _ = parseOptimizerTrace(val, s.EnableOptimizerTrace, s.OptimizerTraceOneLine)
 	case TiDBDDLReorgPriority:
 		s.setDDLReorgPriority(val)
 	case TiDBEnableRadixJoin:
@@ -650,6 +781,14 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		}
 	case TiDBAllowRemoveAutoInc:
 		s.AllowRemoveAutoInc = TiDBOptOn(val)
+	case TiDBEnablePreparedPlanCache:
+		s.EnablePreparedPlanCache = TiDBOptOn(val)
+	case TiDBPreparedPlanCacheSize:
+		s.PreparedPlanCacheSize = tidbOptPositiveInt32(val, DefTiDBPreparedPlanCacheSize)
+	case TiDBMemQuotaQuery:
+		s.MemQuotaQuery = tidbOptInt64(val, DefTiDBMemQuotaQuery)
+	case TiDBMemOOMAction:
+		s.OOMAction = strings.ToUpper(val)
 	// It's a global variable, but it also wants to be cached in server.
 	case TiDBMaxDeltaSchemaCount:
 		SetMaxDeltaSchemaCount(tidbOptInt64(val, DefTiDBMaxDeltaSchemaCount))
@@ -739,6 +878,9 @@ type BatchSize struct {
 	// IndexLookupSize is the number of handles for an index lookup task in index double read executor.
 	IndexLookupSize int
 
+	// DMLBatchSize is the number of rows committed in a transaction by `LOAD DATA`.
+	DMLBatchSize int
+
 	// InitChunkSize defines init row count of a Chunk during query execution.
 	InitChunkSize int
 
@@ -14,7 +14,11 @@
 package variable_test
 
 import (
+	"sync/atomic"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/mock"
 )
 
@@ -78,3 +82,32 @@ func (*testSessionSuite) TestSession(c *C) {
 	c.Assert(ss.CopiedRows(), Equals, uint64(0))
 	c.Assert(ss.WarningCount(), Equals, uint16(0))
 }
+
+func (*testSessionSuite) TestPreparedStmtRegistry(c *C) {
+	ctx := mock.NewContext()
+	vars := ctx.GetSessionVars()
+	c.Assert(vars.SetSystemVar(variable.MaxPreparedStmtCount, "2"), IsNil)
+	base := atomic.LoadInt64(&variable.PreparedStmtCount)
+
+	id1 := vars.GetNextPreparedStmtID()
+	id2 := vars.GetNextPreparedStmtID()
+	c.Assert(id2, Equals, id1+1)
+	c.Assert(vars.AddPreparedStmt(id1, "stmt1"), IsNil)
+	c.Assert(vars.AddPreparedStmt(id2, "stmt2"), IsNil)
+	// Replacing an existing statement does not count against the limit.
+	c.Assert(vars.AddPreparedStmt(id1, "stmt1'"), IsNil)
+	c.Assert(atomic.LoadInt64(&variable.PreparedStmtCount), Equals, base+2)
+
+	id3 := vars.GetNextPreparedStmtID()
+	err := vars.AddPreparedStmt(id3, "stmt3")
+	c.Assert(terror.ErrorEqual(err, variable.ErrMaxPreparedStmtCountReached), IsTrue, Commentf("err %v", err))
+	c.Assert(atomic.LoadInt64(&variable.PreparedStmtCount), Equals, base+2)
+
+	vars.RemovePreparedStmt(id1)
+	vars.RemovePreparedStmt(id1)
+	c.Assert(atomic.LoadInt64(&variable.PreparedStmtCount), Equals, base+1)
+	c.Assert(vars.AddPreparedStmt(id3, "stmt3"), IsNil)
+
+	vars.WithdrawAllPreparedStmt()
+	c.Assert(atomic.LoadInt64(&variable.PreparedStmtCount), Equals, base)
+}
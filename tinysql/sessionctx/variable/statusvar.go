@@ -46,6 +46,18 @@ func RegisterStatistics(s Statistics) {
 	statisticsListLock.Unlock()
 }
 
+// UnregisterStatistics unregisters statistics.
+func UnregisterStatistics(s Statistics) {
+	statisticsListLock.Lock()
+	for i, stat := range statisticsList {
+		if stat == s {
+			statisticsList = append(statisticsList[:i], statisticsList[i+1:]...)
+			break
+		}
+	}
+	statisticsListLock.Unlock()
+}
+
 // GetStatusVars gets registered statistics status variables.
 // TODO: Refactor this function to avoid repeated memory allocation / dealloc
 func GetStatusVars(vars *SessionVars) (map[string]*StatusVal, error) {
@@ -135,6 +147,9 @@ var defaultStatus = map[string]*StatusVal{
 	"Ssl_cipher_list": {ScopeGlobal | ScopeSession, ""},
 	"Ssl_verify_mode": {ScopeGlobal | ScopeSession, 0},
 	"Ssl_version":     {ScopeGlobal | ScopeSession, ""},
+
+	"Plan_cache_hits":   {ScopeSession, uint64(0)},
+	"Plan_cache_misses": {ScopeSession, uint64(0)},
 }
 
 type defaultStatusStat struct {
@@ -159,6 +174,10 @@ func (s defaultStatusStat) Stats(vars *SessionVars) (map[string]interface{}, err
 		statusVars["Ssl_verify_mode"] = 0x01 | 0x04
 		statusVars["Ssl_version"] = tlsVersionString[vars.TLSConnectionState.Version]
 	}
+	if vars != nil {
+		statusVars["Plan_cache_hits"] = vars.PlanCacheHits
+		statusVars["Plan_cache_misses"] = vars.PlanCacheMisses
+	}
 
 	return statusVars, nil
 }
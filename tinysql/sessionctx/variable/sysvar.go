@@ -400,7 +400,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, "innodb_io_capacity_max", "2000"},
 	{ScopeGlobal, "innodb_autoextend_increment", "64"},
 	{ScopeGlobal | ScopeSession, "binlog_format", "STATEMENT"},
-	{ScopeGlobal | ScopeSession, "optimizer_trace", "enabled=off,one_line=off"},
+	{ScopeGlobal | ScopeSession, OptimizerTrace, "enabled=off,one_line=off"},
 	{ScopeGlobal | ScopeSession, "read_rnd_buffer_size", "262144"},
 	{ScopeNone, "version_comment", "TiDB Server (Apache License 2.0), MySQL 5.7 compatible"},
 	{ScopeGlobal | ScopeSession, NetWriteTimeout, "60"},
@@ -641,7 +641,9 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, TiDBOptMemoryFactor, strconv.FormatFloat(DefOptMemoryFactor, 'f', -1, 64)},
 	{ScopeGlobal | ScopeSession, TiDBOptDiskFactor, strconv.FormatFloat(DefOptDiskFactor, 'f', -1, 64)},
 	{ScopeGlobal | ScopeSession, TiDBOptConcurrencyFactor, strconv.FormatFloat(DefOptConcurrencyFactor, 'f', -1, 64)},
+	{ScopeGlobal, TiDBOptCostProfile, ""},
 	{ScopeGlobal | ScopeSession, TiDBIndexLookupSize, strconv.Itoa(DefIndexLookupSize)},
+	{ScopeGlobal | ScopeSession, TiDBDMLBatchSize, strconv.Itoa(DefDMLBatchSize)},
 	{ScopeGlobal | ScopeSession, TiDBIndexLookupConcurrency, strconv.Itoa(DefIndexLookupConcurrency)},
 	{ScopeGlobal | ScopeSession, TiDBIndexLookupJoinConcurrency, strconv.Itoa(DefIndexLookupJoinConcurrency)},
 	{ScopeGlobal | ScopeSession, TiDBIndexSerialScanConcurrency, strconv.Itoa(DefIndexSerialScanConcurrency)},
@@ -677,6 +679,10 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, TiDBEnableNoopFuncs, BoolToIntStr(DefTiDBEnableNoopFuncs)},
 	{ScopeSession, TiDBReplicaRead, "leader"},
 	{ScopeSession, TiDBAllowRemoveAutoInc, BoolToIntStr(DefTiDBAllowRemoveAutoInc)},
+	{ScopeGlobal | ScopeSession, TiDBEnablePreparedPlanCache, BoolToIntStr(DefTiDBEnablePreparedPlanCache)},
+	{ScopeGlobal | ScopeSession, TiDBPreparedPlanCacheSize, strconv.Itoa(DefTiDBPreparedPlanCacheSize)},
+	{ScopeGlobal | ScopeSession, TiDBMemQuotaQuery, strconv.Itoa(DefTiDBMemQuotaQuery)},
+	{ScopeGlobal | ScopeSession, TiDBMemOOMAction, DefTiDBMemOOMAction},
 }
 
 // SynonymsSysVariables is synonyms of system variables.
@@ -865,6 +871,8 @@ const (
 	SlaveAllowBatching = "slave_allow_batching"
 	// MyISAMUseMmap is the name for 'myisam_use_mmap' system variable.
 	MyISAMUseMmap = "myisam_use_mmap"
+	// OptimizerTrace is the name for 'optimizer_trace' system variable.
+	OptimizerTrace = "optimizer_trace"
 	// InnodbFilePerTable is the name for 'innodb_file_per_table' system variable.
 	InnodbFilePerTable = "innodb_file_per_table"
 	// InnodbLogCompressedPages is the name for 'innodb_log_compressed_pages' system variable.
@@ -103,6 +103,8 @@ const (
 	TiDBOptDiskFactor = "tidb_opt_disk_factor"
 	// tidb_opt_concurrency_factor is the CPU cost of additional one goroutine.
 	TiDBOptConcurrencyFactor = "tidb_opt_concurrency_factor"
+	// tidb_opt_cost_profile is the name of a cost profile file in the cost-profile-dir, setting it globally loads the cost factors in the file.
+	TiDBOptCostProfile = "tidb_opt_cost_profile"
 
 	// tidb_index_lookup_size is used for index lookup executor.
 	// The index lookup executor first scan a batch of handles from a index, then use those handles to lookup the table
@@ -127,6 +129,10 @@ const (
 	// when we need to keep the data output order the same as the order of index data.
 	TiDBIndexSerialScanConcurrency = "tidb_index_serial_scan_concurrency"
 
+	// tidb_dml_batch_size is used to split the rows of `LOAD DATA` into batches, every batch of rows is
+	// committed in its own transaction.
+	TiDBDMLBatchSize = "tidb_dml_batch_size"
+
 	// TiDBMaxChunkSize is used to control the max chunk size during query execution.
 	TiDBMaxChunkSize = "tidb_max_chunk_size"
 
@@ -211,6 +217,30 @@ const (
 
 	// TiDBEnableNoopFuncs set true will enable using fake funcs(like get_lock release_lock)
 	TiDBEnableNoopFuncs = "tidb_enable_noop_functions"
+
+	// tidb_enable_prepared_plan_cache is used to control whether to cache the plans of the prepared statements.
+	TiDBEnablePreparedPlanCache = "tidb_enable_prepared_plan_cache"
+
+	// tidb_prepared_plan_cache_size is the max number of the plans cached by a session.
+	TiDBPreparedPlanCacheSize = "tidb_prepared_plan_cache_size"
+
+	// tidb_mem_quota_query is the memory quota of a query in bytes, a non-positive value means no limit.
+	TiDBMemQuotaQuery = "tidb_mem_quota_query"
+
+	// tidb_mem_oom_action is the action taken when a query exceeds tidb_mem_quota_query,
+	// it can be "LOG", "CANCEL" or "SPILL".
+	TiDBMemOOMAction = "tidb_mem_oom_action"
+)
+
+// The actions taken when a query exceeds tidb_mem_quota_query.
+const (
+	// OOMActionLog logs a warning and keeps executing the query.
+	OOMActionLog = "LOG"
+	// OOMActionCancel cancels the query.
+	OOMActionCancel = "CANCEL"
+	// OOMActionSpill spills the intermediate data of the executors to the
+	// temporary storage, the query is canceled if nothing can be spilled.
+	OOMActionSpill = "SPILL"
 )
 
 // Default TiDB system variable values.
@@ -220,6 +250,7 @@ const (
 	DefIndexLookupJoinConcurrency    = 4
 	DefIndexSerialScanConcurrency    = 1
 	DefIndexLookupSize               = 20000
+	DefDMLBatchSize                  = 20000
 	DefDistSQLScanConcurrency        = 15
 	DefBuildStatsConcurrency         = 4
 	DefSkipUTF8Check                 = false
@@ -264,6 +295,10 @@ const (
 	DefTiDBEnableNoopFuncs           = false
 	DefTiDBAllowRemoveAutoInc        = false
 	DefInnodbLockWaitTimeout         = 50 // 50s
+	DefTiDBEnablePreparedPlanCache   = true
+	DefTiDBPreparedPlanCacheSize     = 100
+	DefTiDBMemQuotaQuery             = 1 << 30 // 1GB
+	DefTiDBMemOOMAction              = OOMActionCancel
 )
 
 // Process global variables.
@@ -251,6 +251,44 @@ const (
 	maxChunkSizeLowerBound = 32
 )
 
+// parseTimeZone parses the time_zone value, which can be 'SYSTEM', a named
+// time zone such as 'Asia/Shanghai', or an offset from UTC such as '+10:00'.
+func parseTimeZone(s string) (*time.Location, error) {
+	if strings.EqualFold(s, "SYSTEM") {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(s)
+	if err == nil {
+		return loc, nil
+	}
+
+	// The value can be given as a string indicating an offset from UTC, such as '+10:00' or '-6:00'.
+	// The time zone's value should in [-12:59,+14:00].
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		d, err := types.ParseDuration(nil, s[1:], 0)
+		if err == nil {
+			if s[0] == '-' {
+				if d.Duration > 12*time.Hour+59*time.Minute {
+					return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+				}
+			} else {
+				if d.Duration > 14*time.Hour {
+					return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+				}
+			}
+
+			ofst := int(d.Duration / time.Second)
+			if s[0] == '-' {
+				ofst = -ofst
+			}
+			return time.FixedZone("", ofst), nil
+		}
+	}
+
+	return nil, ErrUnknownTimeZone.GenWithStackByArgs(s)
+}
+
 // ValidateSetSystemVar checks if system variable satisfies specific restriction.
 func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string, error) {
 	if strings.EqualFold(value, "DEFAULT") {
@@ -363,7 +401,7 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		}
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	case TiDBSkipUTF8Check, TiDBOptAggPushDown, TiDBOptInSubqToJoinAndAgg,
-		TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs,
+		TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs, TiDBEnablePreparedPlanCache,
 		TiDBScatterRegion, TiDBGeneralLog, TiDBConstraintCheckInPlace, TiDBEnableVectorizedExpression:
 		fallthrough
 	case GeneralLog, AvoidTemporalUpgrade, BigTables, CheckProxyUsers, LogBin,
@@ -417,6 +455,7 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return checkUInt64SystemVar(name, value, uint64(0), math.MaxInt64, vars)
 	case TiDBIndexLookupConcurrency, TiDBIndexLookupJoinConcurrency,
 		TiDBIndexLookupSize,
+		TiDBDMLBatchSize,
 		TiDBHashJoinConcurrency,
 		TiDBHashAggPartialConcurrency,
 		TiDBHashAggFinalConcurrency,
@@ -466,12 +505,29 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 		}
 		return value, nil
+	case OptimizerTrace:
+		enabled, oneLine, err := parseOptimizerTrace(value, vars.EnableOptimizerTrace, vars.OptimizerTraceOneLine)
+		if err != nil {
+			return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+		return fmt.Sprintf("enabled=%s,one_line=%s", onOffStr(enabled), onOffStr(oneLine)), nil
+	case TiDBOptCostProfile:
+		if value != "" && !isCostProfileName(value) {
+			return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+		return value, nil
 	case TiDBProjectionConcurrency:
 		_, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
 			return value, ErrWrongValueForVar.GenWithStackByArgs(name)
 		}
 		return value, nil
+	case TimeZone:
+		if strings.EqualFold(value, "SYSTEM") {
+			return "SYSTEM", nil
+		}
+		_, err := parseTimeZone(value)
+		return value, err
 	case TxnIsolation, TransactionIsolation:
 		upVal := strings.ToUpper(value)
 		_, exists := TxIsolationNames[upVal]
@@ -534,6 +590,24 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		if v <= 0 {
 			return value, errors.Errorf("tidb_wait_split_region_timeout(%d) cannot be smaller than 1", v)
 		}
+	case TiDBPreparedPlanCacheSize:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return value, ErrWrongTypeForVar.GenWithStackByArgs(name)
+		}
+		if v <= 0 {
+			return value, errors.Errorf("tidb_prepared_plan_cache_size(%d) cannot be smaller than 1", v)
+		}
+	case TiDBMemQuotaQuery:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return value, ErrWrongTypeForVar.GenWithStackByArgs(name)
+		}
+	case TiDBMemOOMAction:
+		switch action := strings.ToUpper(value); action {
+		case OOMActionLog, OOMActionCancel, OOMActionSpill:
+			return action, nil
+		}
+		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
 	case TiDBReplicaRead:
 		if strings.EqualFold(value, "follower") {
 			return "follower", nil
@@ -582,6 +656,56 @@ func tidbOptFloat64(opt string, defaultVal float64) float64 {
 	return val
 }
 
+// MergeOptimizerTrace merges the flags given by value into the current value
+// of the optimizer_trace variable, like parseOptimizerTrace.
+func MergeOptimizerTrace(current, value string) (string, error) {
+	enabled, oneLine, err := parseOptimizerTrace(current, false, false)
+	if err != nil {
+		return "", err
+	}
+	enabled, oneLine, err = parseOptimizerTrace(value, enabled, oneLine)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("enabled=%s,one_line=%s", onOffStr(enabled), onOffStr(oneLine)), nil
+}
+
+// parseOptimizerTrace parses the flags of the optimizer_trace variable, the
+// format is like "enabled=on,one_line=off". Like MySQL, the flags not given
+// keep their current values, which are passed by enabled and oneLine.
+func parseOptimizerTrace(value string, enabled, oneLine bool) (bool, bool, error) {
+	for _, flag := range strings.Split(value, ",") {
+		kv := strings.Split(flag, "=")
+		if len(kv) != 2 {
+			return false, false, errors.Errorf("invalid optimizer trace flag %s", flag)
+		}
+		var on bool
+		switch strings.ToLower(strings.TrimSpace(kv[1])) {
+		case "on":
+			on = true
+		case "off":
+		default:
+			return false, false, errors.Errorf("invalid optimizer trace flag %s", flag)
+		}
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "enabled":
+			enabled = on
+		case "one_line":
+			oneLine = on
+		default:
+			return false, false, errors.Errorf("invalid optimizer trace flag %s", flag)
+		}
+	}
+	return enabled, oneLine, nil
+}
+
+func onOffStr(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
 // GoTimeToTS converts a Go time to uint64 timestamp.
 func GoTimeToTS(t time.Time) uint64 {
 	ts := (t.UnixNano() / int64(time.Millisecond)) << epochShiftBits
@@ -14,10 +14,16 @@
 package variable
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/testleak"
 )
 
@@ -53,6 +59,7 @@ func (s *testVarsutilSuite) TestNewSessionVars(c *C) {
 	vars := NewSessionVars()
 
 	c.Assert(vars.IndexLookupSize, Equals, DefIndexLookupSize)
+	c.Assert(vars.DMLBatchSize, Equals, DefDMLBatchSize)
 	c.Assert(vars.IndexLookupConcurrency, Equals, DefIndexLookupConcurrency)
 	c.Assert(vars.IndexSerialScanConcurrency, Equals, DefIndexSerialScanConcurrency)
 	c.Assert(vars.IndexLookupJoinConcurrency, Equals, DefIndexLookupJoinConcurrency)
@@ -95,6 +102,32 @@ func (s *testVarsutilSuite) TestSetOverflowBehave(c *C) {
 	c.Assert(val2, Equals, 1.2)
 }
 
+func (s *testVarsutilSuite) TestTimeZone(c *C) {
+	v := NewSessionVars()
+	tests := []struct {
+		value  string
+		offset int
+	}{
+		{"+08:00", 8 * 3600},
+		{"-08:00", -8 * 3600},
+		{"+05:30", 5*3600 + 30*60},
+		{"UTC", 0},
+	}
+	for _, t := range tests {
+		err := SetSessionSystemVar(v, TimeZone, types.NewStringDatum(t.value))
+		c.Assert(err, IsNil)
+		_, offset := time.Date(2020, 1, 1, 0, 0, 0, 0, v.Location()).Zone()
+		c.Assert(offset, Equals, t.offset, Commentf("%v", t))
+	}
+
+	err := SetSessionSystemVar(v, TimeZone, types.NewStringDatum("SYSTEM"))
+	c.Assert(err, IsNil)
+	c.Assert(v.Location(), Equals, time.Local)
+
+	err = SetSessionSystemVar(v, TimeZone, types.NewStringDatum("+15:00"))
+	c.Assert(terror.ErrorEqual(err, ErrUnknownTimeZone), IsTrue, Commentf("%v", err))
+}
+
 func (s *testVarsutilSuite) TestValidate(c *C) {
 	v := NewSessionVars()
 	v.GlobalVarsAccessor = NewMockGlobalAccessor()
@@ -136,6 +169,11 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 		{TiDBOptCopCPUFactor, "-2", true},
 		{TiDBOptNetworkFactor, "a", true},
 		{TiDBOptNetworkFactor, "-2", true},
+		{TiDBMemQuotaQuery, "a", true},
+		{TiDBMemQuotaQuery, "1024", false},
+		{TiDBMemOOMAction, "log", false},
+		{TiDBMemOOMAction, "SPILL", false},
+		{TiDBMemOOMAction, "kill", true},
 		{TiDBOptScanFactor, "a", true},
 		{TiDBOptScanFactor, "-2", true},
 		{TiDBOptDescScanFactor, "a", true},
@@ -148,6 +186,10 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 		{TiDBOptDiskFactor, "-2", true},
 		{TiDBOptConcurrencyFactor, "a", true},
 		{TiDBOptConcurrencyFactor, "-2", true},
+		{TiDBOptCostProfile, "", false},
+		{TiDBOptCostProfile, "cost_profile.json", false},
+		{TiDBOptCostProfile, "/nonexistent/cost_profile.json", true},
+		{TiDBOptCostProfile, "../cost_profile.json", true},
 		{TxnIsolation, "READ-UNCOMMITTED", true},
 		{TiDBInitChunkSize, "a", true},
 		{TiDBInitChunkSize, "-1", true},
@@ -156,6 +198,13 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 		{TiDBOptJoinReorderThreshold, "a", true},
 		{TiDBOptJoinReorderThreshold, "-1", true},
 		{TiDBReplicaRead, "invalid", true},
+		{TimeZone, "SYSTEM", false},
+		{TimeZone, "UTC", false},
+		{TimeZone, "+08:00", false},
+		{TimeZone, "-12:59", false},
+		{TimeZone, "+14:01", true},
+		{TimeZone, "-13:00", true},
+		{TimeZone, "invalid", true},
 	}
 
 	for _, t := range tests {
@@ -168,3 +217,84 @@ func (s *testVarsutilSuite) TestValidate(c *C) {
 	}
 
 }
+
+func (s *testVarsutilSuite) TestOptimizerTrace(c *C) {
+	v := NewSessionVars()
+	v.GlobalVarsAccessor = NewMockGlobalAccessor()
+
+	err := SetSessionSystemVar(v, OptimizerTrace, types.NewStringDatum("enabled=on,one_line=on"))
+	c.Assert(err, IsNil)
+	c.Assert(v.EnableOptimizerTrace, IsTrue)
+	c.Assert(v.OptimizerTraceOneLine, IsTrue)
+
+	// The flags not given keep their current values.
+	err = SetSessionSystemVar(v, OptimizerTrace, types.NewStringDatum("one_line=on"))
+	c.Assert(err, IsNil)
+	c.Assert(v.EnableOptimizerTrace, IsTrue)
+	c.Assert(v.OptimizerTraceOneLine, IsTrue)
+	val, err := GetSessionSystemVar(v, OptimizerTrace)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "enabled=on,one_line=on")
+
+	err = SetSessionSystemVar(v, OptimizerTrace, types.NewStringDatum("one_line=off"))
+	c.Assert(err, IsNil)
+	c.Assert(v.EnableOptimizerTrace, IsTrue)
+	c.Assert(v.OptimizerTraceOneLine, IsFalse)
+
+	err = SetSessionSystemVar(v, OptimizerTrace, types.NewStringDatum("enabled=off,foo=on"))
+	c.Assert(err, NotNil)
+	c.Assert(v.EnableOptimizerTrace, IsTrue)
+
+	val, err = MergeOptimizerTrace("enabled=on,one_line=off", "one_line=on")
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "enabled=on,one_line=on")
+}
+
+func (s *testVarsutilSuite) TestCostProfile(c *C) {
+	dir, err := ioutil.TempDir("", "cost_profile")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "profile.json")
+
+	profile := NewCostProfile()
+	profile.ScanFactor = 0.5
+	profile.SeekFactor = 12.25
+	c.Assert(profile.Save(path), IsNil)
+	loaded, err := LoadCostProfile(path)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, DeepEquals, profile)
+	c.Assert(loaded.SysVars()[TiDBOptSeekFactor], Equals, "12.25")
+	c.Assert(loaded.SysVars()[TiDBOptCPUFactor], Equals, "3")
+
+	// The missing factors keep the default values.
+	c.Assert(ioutil.WriteFile(path, []byte(`{"tidb_opt_scan_factor": 2}`), 0644), IsNil)
+	loaded, err = LoadCostProfile(path)
+	c.Assert(err, IsNil)
+	c.Assert(loaded.ScanFactor, Equals, 2.0)
+	c.Assert(loaded.NetworkFactor, Equals, DefOptNetworkFactor)
+
+	for _, content := range []string{
+		`{"tidb_opt_scan_factor": -1}`,
+		`{"tidb_opt_scan_factor": "a"}`,
+		`{"tidb_opt_unknown_factor": 1}`,
+		`not json`,
+	} {
+		c.Assert(ioutil.WriteFile(path, []byte(content), 0644), IsNil)
+		_, err = LoadCostProfile(path)
+		c.Assert(err, NotNil, Commentf("%s", content))
+	}
+	_, err = CostProfilePath("profile.json")
+	c.Assert(err, NotNil)
+	originCfg := config.GetGlobalConfig()
+	newCfg := *originCfg
+	newCfg.CostProfileDir = dir
+	config.StoreGlobalConfig(&newCfg)
+	defer config.StoreGlobalConfig(originCfg)
+	resolved, err := CostProfilePath("profile.json")
+	c.Assert(err, IsNil)
+	c.Assert(resolved, Equals, path)
+	for _, name := range []string{"", ".", "..", "../profile.json", path, "sub/profile.json", "a'b.json"} {
+		_, err = CostProfilePath(name)
+		c.Assert(err, NotNil, Commentf("%s", name))
+	}
+}
@@ -15,8 +15,10 @@ package statistics
 
 import (
 	"encoding/binary"
-	"github.com/pingcap/tidb/types"
 	"math"
+	"time"
+
+	"github.com/pingcap/tidb/types"
 )
 
 // calcFraction is used to calculate the fraction of the interval [lower, upper] that lies within the [lower, value]
@@ -40,12 +42,28 @@ func calcFraction(lower, upper, value float64) float64 {
 
 func convertDatumToScalar(value *types.Datum, commonPfxLen int) float64 {
 	switch value.Kind() {
+	case types.KindMysqlDecimal:
+		scalar, err := value.GetMysqlDecimal().ToFloat64()
+		if err != nil {
+			return 0
+		}
+		return scalar
 	case types.KindString, types.KindBytes:
 		bytes := value.GetBytes()
 		if len(bytes) <= commonPfxLen {
 			return 0
 		}
 		return convertBytesToScalar(bytes[commonPfxLen:])
+	case types.KindMysqlDuration:
+		return float64(value.GetMysqlDuration().Duration)
+	case types.KindMysqlTime:
+		// Times are viewed as the number of microseconds since the unix epoch. The
+		// zero time cannot be converted, and we treat it as the minimum value.
+		t, err := value.GetMysqlTime().GoTime(time.UTC)
+		if err != nil {
+			return 0
+		}
+		return float64(t.Unix())*1e6 + float64(t.Nanosecond()/1000)
 	default:
 		// do not know how to convert
 		return 0
@@ -62,6 +80,14 @@ func (hg *Histogram) PreCalculateScalar() {
 		return
 	}
 	switch hg.GetLower(0).Kind() {
+	case types.KindMysqlDecimal, types.KindMysqlTime:
+		hg.scalars = make([]scalar, len)
+		for i := 0; i < len; i++ {
+			hg.scalars[i] = scalar{
+				lower: convertDatumToScalar(hg.GetLower(i), 0),
+				upper: convertDatumToScalar(hg.GetUpper(i), 0),
+			}
+		}
 	case types.KindBytes, types.KindString:
 		hg.scalars = make([]scalar, len)
 		for i := 0; i < len; i++ {
@@ -87,6 +113,10 @@ func (hg *Histogram) calcFraction(index int, value *types.Datum) float64 {
 		return calcFraction(float64(lower.GetInt64(0)), float64(upper.GetInt64(0)), float64(value.GetInt64()))
 	case types.KindUint64:
 		return calcFraction(float64(lower.GetUint64(0)), float64(upper.GetUint64(0)), float64(value.GetUint64()))
+	case types.KindMysqlDecimal, types.KindMysqlTime:
+		return calcFraction(hg.scalars[index].lower, hg.scalars[index].upper, convertDatumToScalar(value, 0))
+	case types.KindMysqlDuration:
+		return calcFraction(float64(lower.GetDuration(0, 0).Duration), float64(upper.GetDuration(0, 0).Duration), float64(value.GetMysqlDuration().Duration))
 	case types.KindBytes, types.KindString:
 		return calcFraction(hg.scalars[index].lower, hg.scalars[index].upper, convertDatumToScalar(value, hg.scalars[index].commonPfxLen))
 	}
@@ -23,6 +23,15 @@ import (
 
 const eps = 1e-9
 
+func getDatetime(year, month, day int) types.Time {
+	return types.NewTime(types.FromDate(year, month, day, 0, 0, 0, 0), mysql.TypeDatetime, types.DefaultFsp)
+}
+
+func getDuration(value string) types.Duration {
+	dur, _ := types.ParseDuration(nil, value, 0)
+	return dur
+}
+
 func getUnsignedFieldType() *types.FieldType {
 	tp := types.NewFieldType(mysql.TypeLonglong)
 	tp.Flag |= mysql.UnsignedFlag
@@ -79,6 +88,13 @@ func (s *testStatisticsSuite) TestCalcFraction(c *C) {
 			fraction: 0.25,
 			tp:       types.NewFieldType(mysql.TypeFloat),
 		},
+		{
+			lower:    types.NewDecimalDatum(types.NewDecFromInt(0)),
+			upper:    types.NewDecimalDatum(types.NewDecFromInt(4)),
+			value:    types.NewDecimalDatum(types.NewDecFromInt(1)),
+			fraction: 0.25,
+			tp:       types.NewFieldType(mysql.TypeNewDecimal),
+		},
 		{
 			lower:    types.NewStringDatum("aasad"),
 			upper:    types.NewStringDatum("addad"),
@@ -93,6 +109,27 @@ func (s *testStatisticsSuite) TestCalcFraction(c *C) {
 			fraction: 0.0529216802217269,
 			tp:       types.NewFieldType(mysql.TypeBlob),
 		},
+		{
+			lower:    types.NewTimeDatum(getDatetime(2020, 1, 1)),
+			upper:    types.NewTimeDatum(getDatetime(2020, 1, 5)),
+			value:    types.NewTimeDatum(getDatetime(2020, 1, 2)),
+			fraction: 0.25,
+			tp:       types.NewFieldType(mysql.TypeDatetime),
+		},
+		{
+			lower:    types.NewTimeDatum(getDatetime(1000, 1, 1)),
+			upper:    types.NewTimeDatum(getDatetime(9999, 1, 1)),
+			value:    types.NewTimeDatum(getDatetime(10000, 1, 1)),
+			fraction: 1,
+			tp:       types.NewFieldType(mysql.TypeDatetime),
+		},
+		{
+			lower:    types.NewDurationDatum(getDuration("0:00:00")),
+			upper:    types.NewDurationDatum(getDuration("4:00:00")),
+			value:    types.NewDurationDatum(getDuration("1:00:00")),
+			fraction: 0.25,
+			tp:       types.NewFieldType(mysql.TypeDuration),
+		},
 	}
 	for _, test := range tests {
 		hg := NewHistogram(0, 0, 0, 0, test.tp, 1, 0)
@@ -15,6 +15,8 @@ package mocktikv
 
 import (
 	"context"
+	"time"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
@@ -42,6 +44,8 @@ type hashAggExec struct {
 	count             int64
 
 	src executor
+
+	execDetail *execDetail
 }
 
 func (e *hashAggExec) SetSrcExec(exec executor) {
@@ -60,6 +64,10 @@ func (e *hashAggExec) Counts() []int64 {
 	return e.src.Counts()
 }
 
+func (e *hashAggExec) ExecDetails() []*execDetail {
+	return append(e.src.ExecDetails(), e.execDetail)
+}
+
 func (e *hashAggExec) innerNext(ctx context.Context) (bool, error) {
 	values, err := e.src.Next(ctx)
 	if err != nil {
@@ -76,6 +84,9 @@ func (e *hashAggExec) innerNext(ctx context.Context) (bool, error) {
 }
 
 func (e *hashAggExec) Next(ctx context.Context) (value [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, value)
+	}(time.Now())
 	e.count++
 	if e.aggCtxsMap == nil {
 		e.aggCtxsMap = make(aggCtxsMapper)
@@ -180,3 +191,156 @@ func (e *hashAggExec) getContexts(groupKey []byte) []*aggregation.AggEvaluateCon
 	}
 	return aggCtxs
 }
+
+var _ executor = &streamAggExec{}
+
+type streamAggExec struct {
+	evalCtx           *evalContext
+	aggExprs          []aggregation.Aggregation
+	aggCtxs           []*aggregation.AggEvaluateContext
+	groupByExprs      []expression.Expression
+	relatedColOffsets []int
+	row               []types.Datum
+	tmpGroupByRow     []types.Datum
+	currGroupByRow    []types.Datum
+	nextGroupByRow    []types.Datum
+	currGroupByValues [][]byte
+	executed          bool
+	hasData           bool
+	count             int64
+
+	src executor
+
+	execDetail *execDetail
+}
+
+func (e *streamAggExec) SetSrcExec(exec executor) {
+	e.src = exec
+}
+
+func (e *streamAggExec) GetSrcExec() executor {
+	return e.src
+}
+
+func (e *streamAggExec) ResetCounts() {
+	e.src.ResetCounts()
+}
+
+func (e *streamAggExec) Counts() []int64 {
+	return e.src.Counts()
+}
+
+func (e *streamAggExec) ExecDetails() []*execDetail {
+	return append(e.src.ExecDetails(), e.execDetail)
+}
+
+func (e *streamAggExec) getPartialResult() ([][]byte, error) {
+	value := make([][]byte, 0, len(e.groupByExprs)+2*len(e.aggExprs))
+	for i, agg := range e.aggExprs {
+		partialResults := agg.GetPartialResult(e.aggCtxs[i])
+		for _, result := range partialResults {
+			data, err := codec.EncodeValue(e.evalCtx.sc, nil, result)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			value = append(value, data)
+		}
+		// Clear the aggregate context.
+		e.aggCtxs[i] = agg.CreateContext(e.evalCtx.sc)
+	}
+	e.currGroupByValues = e.currGroupByValues[:0]
+	for _, d := range e.currGroupByRow {
+		buf, err := codec.EncodeValue(e.evalCtx.sc, nil, d)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		e.currGroupByValues = append(e.currGroupByValues, buf)
+	}
+	e.currGroupByRow = types.CloneRow(e.nextGroupByRow)
+	return append(value, e.currGroupByValues...), nil
+}
+
+// meetNewGroup checks whether the current row starts a new group, the rows
+// from the source executor are sorted by the group by items.
+func (e *streamAggExec) meetNewGroup() (bool, error) {
+	if len(e.groupByExprs) == 0 {
+		return false, nil
+	}
+
+	e.tmpGroupByRow = e.tmpGroupByRow[:0]
+	matched, firstGroup := true, false
+	if e.nextGroupByRow == nil {
+		matched, firstGroup = false, true
+	}
+	for i, item := range e.groupByExprs {
+		d, err := item.Eval(chunk.MutRowFromDatums(e.row).ToRow())
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if matched {
+			c, err := d.CompareDatum(e.evalCtx.sc, &e.nextGroupByRow[i])
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			matched = c == 0
+		}
+		e.tmpGroupByRow = append(e.tmpGroupByRow, d)
+	}
+	if firstGroup {
+		e.currGroupByRow = types.CloneRow(e.tmpGroupByRow)
+	}
+	if matched {
+		return false, nil
+	}
+	e.nextGroupByRow = types.CloneRow(e.tmpGroupByRow)
+	return !firstGroup, nil
+}
+
+func (e *streamAggExec) Next(ctx context.Context) (retRow [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, retRow)
+	}(time.Now())
+	e.count++
+	if e.executed {
+		return nil, nil
+	}
+
+	for {
+		values, err := e.src.Next(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if values == nil {
+			e.executed = true
+			if !e.hasData && len(e.groupByExprs) > 0 {
+				return nil, nil
+			}
+			return e.getPartialResult()
+		}
+
+		e.hasData = true
+		err = e.evalCtx.decodeRelatedColumnVals(e.relatedColOffsets, values, e.row)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		newGroup, err := e.meetNewGroup()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if newGroup {
+			retRow, err = e.getPartialResult()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		for i, agg := range e.aggExprs {
+			err = agg.Update(e.aggCtxs[i], e.evalCtx.sc, chunk.MutRowFromDatums(e.row).ToRow())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if newGroup {
+			return retRow, nil
+		}
+	}
+}
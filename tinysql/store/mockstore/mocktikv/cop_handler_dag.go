@@ -76,12 +76,30 @@ func (h *rpcHandler) handleCopDAGRequest(req *coprocessor.Request) *coprocessor.
 	if err == nil {
 		err = h.fillUpData4SelectResponse(selResp, dagReq, rows)
 	}
+	if dagReq.GetCollectExecutionSummaries() {
+		selResp.ExecutionSummaries = buildExecutionSummaries(e.ExecDetails())
+	}
 	// FIXME: some err such as (overflow) will be include in Response.OtherError with calling this buildResp.
 	//  Such err should only be marshal in the data but not in OtherError.
 	//  However, we can not distinguish such err now.
 	return buildResp(selResp, err)
 }
 
+func buildExecutionSummaries(execDetails []*execDetail) []*tipb.ExecutorExecutionSummary {
+	execSummary := make([]*tipb.ExecutorExecutionSummary, 0, len(execDetails))
+	for _, d := range execDetails {
+		costNs := uint64(d.timeProcessed / time.Nanosecond)
+		rows := uint64(d.numProducedRows)
+		numIter := uint64(d.numIterations)
+		execSummary = append(execSummary, &tipb.ExecutorExecutionSummary{
+			TimeProcessedNs: &costNs,
+			NumProducedRows: &rows,
+			NumIterations:   &numIter,
+		})
+	}
+	return execSummary
+}
+
 func (h *rpcHandler) buildDAGExecutor(req *coprocessor.Request) (*dagContext, executor, *tipb.DAGRequest, error) {
 	if len(req.Ranges) == 0 {
 		return nil, nil, nil, errors.New("request range is null")
@@ -119,7 +137,10 @@ func (h *rpcHandler) buildDAGExecutor(req *coprocessor.Request) (*dagContext, ex
 // is set, the daylight saving problem must be considered. Otherwise the
 // timezone offset in seconds east of UTC is used to constructed the timezone.
 func constructTimeZone(name string, offset int) (*time.Location, error) {
-	return time.Local, nil
+	if name != "" {
+		return time.LoadLocation(name)
+	}
+	return time.FixedZone("", offset), nil
 }
 
 func (h *rpcHandler) buildExec(ctx *dagContext, curr *tipb.Executor) (executor, error) {
@@ -134,10 +155,12 @@ func (h *rpcHandler) buildExec(ctx *dagContext, curr *tipb.Executor) (executor,
 		currExec, err = h.buildSelection(ctx, curr)
 	case tipb.ExecType_TypeAggregation:
 		currExec, err = h.buildHashAgg(ctx, curr)
+	case tipb.ExecType_TypeStreamAgg:
+		currExec, err = h.buildStreamAgg(ctx, curr)
 	case tipb.ExecType_TypeTopN:
 		currExec, err = h.buildTopN(ctx, curr)
 	case tipb.ExecType_TypeLimit:
-		currExec = &limitExec{limit: curr.Limit.GetLimit()}
+		currExec = &limitExec{limit: curr.Limit.GetLimit(), execDetail: new(execDetail)}
 	default:
 		// TODO: Support other types.
 		err = errors.Errorf("this exec type %v doesn't support yet.", curr.GetTp())
@@ -194,12 +217,13 @@ func (h *rpcHandler) buildTableScan(ctx *dagContext, executor *tipb.Executor) (*
 	}
 	rd := rowcodec.NewByteDecoder(colInfos, -1, defVal, nil)
 	e := &tableScanExec{
-		TableScan: executor.TblScan,
-		kvRanges:  ranges,
-		colIDs:    ctx.evalCtx.colIDs,
-		startTS:   startTS,
-		mvccStore: h.mvccStore,
-		rd:        rd,
+		TableScan:  executor.TblScan,
+		kvRanges:   ranges,
+		colIDs:     ctx.evalCtx.colIDs,
+		startTS:    startTS,
+		mvccStore:  h.mvccStore,
+		rd:         rd,
+		execDetail: new(execDetail),
 	}
 	if ctx.dagReq.CollectRangeCounts != nil && *ctx.dagReq.CollectRangeCounts {
 		e.counts = make([]int64, len(ranges))
@@ -235,12 +259,13 @@ func (h *rpcHandler) buildIndexScan(ctx *dagContext, executor *tipb.Executor) (*
 		startTS = ctx.dagReq.GetStartTsFallback()
 	}
 	e := &indexScanExec{
-		IndexScan: executor.IdxScan,
-		kvRanges:  ranges,
-		colsLen:   len(columns),
-		startTS:   startTS,
-		mvccStore: h.mvccStore,
-		pkStatus:  pkStatus,
+		IndexScan:  executor.IdxScan,
+		kvRanges:   ranges,
+		colsLen:    len(columns),
+		startTS:    startTS,
+		mvccStore:  h.mvccStore,
+		pkStatus:   pkStatus,
+		execDetail: new(execDetail),
 	}
 	if ctx.dagReq.CollectRangeCounts != nil && *ctx.dagReq.CollectRangeCounts {
 		e.counts = make([]int64, len(ranges))
@@ -268,6 +293,7 @@ func (h *rpcHandler) buildSelection(ctx *dagContext, executor *tipb.Executor) (*
 		relatedColOffsets: relatedColOffsets,
 		conditions:        conds,
 		row:               make([]types.Datum, len(ctx.evalCtx.columnInfos)),
+		execDetail:        new(execDetail),
 	}, nil
 }
 
@@ -316,6 +342,29 @@ func (h *rpcHandler) buildHashAgg(ctx *dagContext, executor *tipb.Executor) (*ha
 		groupKeys:         make([][]byte, 0),
 		relatedColOffsets: relatedColOffsets,
 		row:               make([]types.Datum, len(ctx.evalCtx.columnInfos)),
+		execDetail:        new(execDetail),
+	}, nil
+}
+
+func (h *rpcHandler) buildStreamAgg(ctx *dagContext, executor *tipb.Executor) (*streamAggExec, error) {
+	aggs, groupBys, relatedColOffsets, err := h.getAggInfo(ctx, executor)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aggCtxs := make([]*aggregation.AggEvaluateContext, 0, len(aggs))
+	for _, agg := range aggs {
+		aggCtxs = append(aggCtxs, agg.CreateContext(ctx.evalCtx.sc))
+	}
+
+	return &streamAggExec{
+		evalCtx:           ctx.evalCtx,
+		aggExprs:          aggs,
+		aggCtxs:           aggCtxs,
+		groupByExprs:      groupBys,
+		currGroupByValues: make([][]byte, 0),
+		relatedColOffsets: relatedColOffsets,
+		row:               make([]types.Datum, len(ctx.evalCtx.columnInfos)),
+		execDetail:        new(execDetail),
 	}, nil
 }
 
@@ -350,6 +399,7 @@ func (h *rpcHandler) buildTopN(ctx *dagContext, executor *tipb.Executor) (*topNE
 		relatedColOffsets: relatedColOffsets,
 		orderByExprs:      conds,
 		row:               make([]types.Datum, len(ctx.evalCtx.columnInfos)),
+		execDetail:        new(execDetail),
 	}, nil
 }
 
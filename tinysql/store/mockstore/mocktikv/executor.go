@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"context"
 	"sort"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
@@ -43,6 +44,25 @@ type executor interface {
 	ResetCounts()
 	Counts() []int64
 	Next(ctx context.Context) ([][]byte, error)
+	// ExecDetails returns its and its children's execution details.
+	// The order is same as DAGRequest.Executors, which children are in front of parents.
+	ExecDetails() []*execDetail
+}
+
+// execDetail collects the execution details of an executor, it is returned
+// to the SQL layer as an ExecutorExecutionSummary.
+type execDetail struct {
+	timeProcessed   time.Duration
+	numProducedRows int
+	numIterations   int
+}
+
+func (e *execDetail) update(begin time.Time, row [][]byte) {
+	e.timeProcessed += time.Since(begin)
+	e.numIterations++
+	if row != nil {
+		e.numProducedRows++
+	}
 }
 
 type tableScanExec struct {
@@ -59,6 +79,8 @@ type tableScanExec struct {
 	src executor
 
 	rd *rowcodec.BytesDecoder
+
+	execDetail *execDetail
 }
 
 func (e *tableScanExec) SetSrcExec(exec executor) {
@@ -86,7 +108,14 @@ func (e *tableScanExec) Counts() []int64 {
 	return e.counts[e.start : e.cursor+1]
 }
 
+func (e *tableScanExec) ExecDetails() []*execDetail {
+	return []*execDetail{e.execDetail}
+}
+
 func (e *tableScanExec) Next(ctx context.Context) (value [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, value)
+	}(time.Now())
 	for e.cursor < len(e.kvRanges) {
 		ran := e.kvRanges[e.cursor]
 		if ran.IsPoint() {
@@ -201,6 +230,8 @@ type indexScanExec struct {
 	counts    []int64
 
 	src executor
+
+	execDetail *execDetail
 }
 
 func (e *indexScanExec) SetSrcExec(exec executor) {
@@ -228,11 +259,18 @@ func (e *indexScanExec) Counts() []int64 {
 	return e.counts[e.start : e.cursor+1]
 }
 
+func (e *indexScanExec) ExecDetails() []*execDetail {
+	return []*execDetail{e.execDetail}
+}
+
 func (e *indexScanExec) isUnique() bool {
 	return e.Unique != nil && *e.Unique
 }
 
 func (e *indexScanExec) Next(ctx context.Context) (value [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, value)
+	}(time.Now())
 	for e.cursor < len(e.kvRanges) {
 		ran := e.kvRanges[e.cursor]
 		if ran.IsPoint() && e.isUnique() {
@@ -325,6 +363,8 @@ type selectionExec struct {
 	row               []types.Datum
 	evalCtx           *evalContext
 	src               executor
+
+	execDetail *execDetail
 }
 
 func (e *selectionExec) SetSrcExec(exec executor) {
@@ -343,6 +383,10 @@ func (e *selectionExec) Counts() []int64 {
 	return e.src.Counts()
 }
 
+func (e *selectionExec) ExecDetails() []*execDetail {
+	return append(e.src.ExecDetails(), e.execDetail)
+}
+
 // evalBool evaluates expression to a boolean value.
 func evalBool(exprs []expression.Expression, row []types.Datum, ctx *stmtctx.StatementContext) (bool, error) {
 	for _, expr := range exprs {
@@ -366,6 +410,9 @@ func evalBool(exprs []expression.Expression, row []types.Datum, ctx *stmtctx.Sta
 }
 
 func (e *selectionExec) Next(ctx context.Context) (value [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, value)
+	}(time.Now())
 	for {
 		value, err = e.src.Next(ctx)
 		if err != nil {
@@ -399,6 +446,8 @@ type topNExec struct {
 	executed          bool
 
 	src executor
+
+	execDetail *execDetail
 }
 
 func (e *topNExec) SetSrcExec(src executor) {
@@ -417,6 +466,10 @@ func (e *topNExec) Counts() []int64 {
 	return e.src.Counts()
 }
 
+func (e *topNExec) ExecDetails() []*execDetail {
+	return append(e.src.ExecDetails(), e.execDetail)
+}
+
 func (e *topNExec) innerNext(ctx context.Context) (bool, error) {
 	value, err := e.src.Next(ctx)
 	if err != nil {
@@ -433,6 +486,9 @@ func (e *topNExec) innerNext(ctx context.Context) (bool, error) {
 }
 
 func (e *topNExec) Next(ctx context.Context) (value [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, value)
+	}(time.Now())
 	if !e.executed {
 		for {
 			hasMore, err := e.innerNext(ctx)
@@ -483,6 +539,8 @@ type limitExec struct {
 	cursor uint64
 
 	src executor
+
+	execDetail *execDetail
 }
 
 func (e *limitExec) SetSrcExec(src executor) {
@@ -501,7 +559,14 @@ func (e *limitExec) Counts() []int64 {
 	return e.src.Counts()
 }
 
+func (e *limitExec) ExecDetails() []*execDetail {
+	return append(e.src.ExecDetails(), e.execDetail)
+}
+
 func (e *limitExec) Next(ctx context.Context) (value [][]byte, err error) {
+	defer func(begin time.Time) {
+		e.execDetail.update(begin, value)
+	}(time.Now())
 	if e.cursor >= e.limit {
 		return nil, nil
 	}
@@ -368,10 +368,11 @@ type copIteratorTaskSender struct {
 }
 
 type copResponse struct {
-	pbResp   *coprocessor.Response
-	err      error
-	respSize int64
-	respTime time.Duration
+	pbResp        *coprocessor.Response
+	err           error
+	respSize      int64
+	respTime      time.Duration
+	calleeAddress string
 }
 
 // GetData implements the kv.ResultSubset GetData interface.
@@ -397,6 +398,11 @@ func (rs *copResponse) RespTime() time.Duration {
 	return rs.respTime
 }
 
+// CalleeAddress implements the kv.ResultSubset CalleeAddress interface.
+func (rs *copResponse) CalleeAddress() string {
+	return rs.calleeAddress
+}
+
 const minLogCopTaskTime = 300 * time.Millisecond
 
 // run is a worker function that get a copTask from channel, handle it and
@@ -621,7 +627,12 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		worker.logTimeCopTask(costTime, task, bo, resp)
 	}
 
-	return worker.handleCopResponse(bo, rpcCtx, &copResponse{pbResp: resp.Resp.(*coprocessor.Response)}, task, ch)
+	copResp := &copResponse{
+		pbResp:        resp.Resp.(*coprocessor.Response),
+		respTime:      costTime,
+		calleeAddress: storeAddr,
+	}
+	return worker.handleCopResponse(bo, rpcCtx, copResp, task, ch)
 }
 
 type minCommitTSPushed struct {
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	"github.com/pingcap/errors"
@@ -37,6 +38,10 @@ var (
 
 const (
 	scanBatchSize = 256
+	// batchGetSize is the max number of keys fetched by a worker of BatchGet.
+	batchGetSize = 64
+	// batchGetConcurrency is the max number of the concurrent workers of BatchGet.
+	batchGetConcurrency = 16
 )
 
 // tikvSnapshot implements the kv.Snapshot interface.
@@ -91,6 +96,102 @@ func (s *tikvSnapshot) Get(ctx context.Context, k kv.Key) ([]byte, error) {
 	return val, nil
 }
 
+// BatchGet gets all the keys' value from TinyKV and returns a map contains key/value pairs.
+// The map will not contain nonexistent keys. TinyKV serves a single key in a GetRequest,
+// so the keys are grouped by region and split into batches fetched concurrently. The
+// values are cached in the snapshot, the following Get requests of the keys don't need
+// to visit TinyKV.
+func (s *tikvSnapshot) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	m := make(map[string][]byte, len(keys))
+	pending := make([][]byte, 0, len(keys))
+	for _, k := range keys {
+		if value, ok := s.cached[string(k)]; ok {
+			if len(value) > 0 {
+				m[string(k)] = value
+			}
+			continue
+		}
+		pending = append(pending, k)
+	}
+	if len(pending) == 0 {
+		return m, nil
+	}
+
+	ctx = context.WithValue(ctx, txnStartKey, s.version.Ver)
+	bo := NewBackoffer(ctx, batchGetMaxBackoff).WithVars(s.vars)
+	groups, _, err := s.store.regionCache.GroupKeysByRegion(bo, pending, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var batches []batchKeys
+	for id, g := range groups {
+		batches = appendBatchBySize(batches, id, g, func([]byte) int { return 1 }, batchGetSize)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	fetched := make(map[string][]byte, len(pending))
+	batchBo, cancel := bo.Fork()
+	defer cancel()
+	tokens := make(chan struct{}, batchGetConcurrency)
+	for _, batch := range batches {
+		tokens <- struct{}{}
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		wg.Add(1)
+		go func(batch batchKeys) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			singleBatchBo, singleBatchCancel := batchBo.Fork()
+			defer singleBatchCancel()
+			values := make([][]byte, len(batch.keys))
+			for i, k := range batch.keys {
+				value, err := s.get(singleBatchBo, k)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						// Cancel the other workers.
+						cancel()
+					}
+					mu.Unlock()
+					return
+				}
+				values[i] = value
+			}
+			mu.Lock()
+			for i, k := range batch.keys {
+				fetched[string(k)] = values[i]
+			}
+			mu.Unlock()
+		}(batch)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, errors.Trace(firstErr)
+	}
+
+	if s.cached == nil {
+		s.cached = make(map[string][]byte, len(fetched))
+	}
+	for k, value := range fetched {
+		s.cached[k] = value
+		if len(value) > 0 {
+			m[k] = value
+		}
+	}
+	return m, nil
+}
+
 func (s *tikvSnapshot) get(bo *Backoffer, k kv.Key) ([]byte, error) {
 	// Check the cached values first.
 	if s.cached != nil {
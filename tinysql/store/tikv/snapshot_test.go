@@ -19,6 +19,7 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
 )
 
 type testSnapshotSuite struct {
@@ -61,6 +62,48 @@ func (s *testSnapshotSuite) beginTxn(c *C) *tikvTxn {
 	return txn.(*tikvTxn)
 }
 
+func (s *testSnapshotSuite) TestBatchGet(c *C) {
+	for _, rowNum := range s.rowNums {
+		txn := s.beginTxn(c)
+		keys := make([]kv.Key, 0, rowNum+1)
+		for i := 0; i < rowNum; i++ {
+			k := encodeKey(s.prefix, s08d("key", i))
+			c.Assert(txn.Set(k, valueBytes(i)), IsNil)
+			keys = append(keys, k)
+		}
+		c.Assert(txn.Commit(context.Background()), IsNil)
+		keys = append(keys, encodeKey(s.prefix, "nonexistent"))
+
+		txn = s.beginTxn(c)
+		m, err := txn.snapshot.BatchGet(context.Background(), keys)
+		c.Assert(err, IsNil)
+		c.Assert(m, HasLen, rowNum)
+		for i := 0; i < rowNum; i++ {
+			c.Assert(m[string(keys[i])], BytesEquals, valueBytes(i))
+		}
+		// The results including the nonexistent keys are cached.
+		c.Assert(txn.snapshot.cached, HasLen, rowNum+1)
+		c.Assert(txn.snapshot.cached[string(keys[rowNum])], HasLen, 0)
+
+		// The membuffer of the transaction overrides the snapshot.
+		c.Assert(txn.Delete(keys[0]), IsNil)
+		c.Assert(txn.Set(keys[rowNum], []byte("new")), IsNil)
+		m, err = txn.BatchGet(context.Background(), keys)
+		c.Assert(err, IsNil)
+		c.Assert(m, HasLen, rowNum)
+		_, ok := m[string(keys[0])]
+		c.Assert(ok, IsFalse)
+		c.Assert(m[string(keys[rowNum])], BytesEquals, []byte("new"))
+		c.Assert(txn.Rollback(), IsNil)
+
+		txn = s.beginTxn(c)
+		for _, k := range keys[:rowNum] {
+			c.Assert(txn.Delete(k), IsNil)
+		}
+		c.Assert(txn.Commit(context.Background()), IsNil)
+	}
+}
+
 func (s *testSnapshotSuite) TestLockNotFoundPrint(c *C) {
 	msg := "Txn(Mvcc(TxnLockNotFound { start_ts: 408090278408224772, commit_ts: 408090279311835140, " +
 		"key: [116, 128, 0, 0, 0, 0, 0, 50, 137, 95, 105, 128, 0, 0, 0, 0,0 ,0, 1, 1, 67, 49, 57, 48, 57, 50, 57, 48, 255, 48, 48, 48, 48, 48, 52, 56, 54, 255, 50, 53, 53, 50, 51, 0, 0, 0, 252] }))"
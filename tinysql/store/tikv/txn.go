@@ -109,6 +109,38 @@ func (txn *tikvTxn) Get(ctx context.Context, k kv.Key) ([]byte, error) {
 	return ret, nil
 }
 
+// BatchGet gets the values of the keys from the membuffer of the transaction
+// first, the other keys are fetched from the snapshot.
+func (txn *tikvTxn) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	m := make(map[string][]byte, len(keys))
+	shrinkKeys := make([]kv.Key, 0, len(keys))
+	for _, k := range keys {
+		val, err := txn.GetMemBuffer().Get(ctx, k)
+		if kv.IsErrNotFound(err) {
+			shrinkKeys = append(shrinkKeys, k)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// An empty value in the membuffer means the key is deleted.
+		if len(val) != 0 {
+			m[string(k)] = val
+		}
+	}
+	storageValues, err := txn.snapshot.BatchGet(ctx, shrinkKeys)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range storageValues {
+		m[k] = v
+	}
+	if err = txn.store.CheckVisibility(txn.startTS); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
 func (txn *tikvTxn) Set(k kv.Key, v []byte) error {
 	txn.setCnt++
 
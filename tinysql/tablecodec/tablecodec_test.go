@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/testleak"
 )
 
@@ -52,6 +53,84 @@ func (s *testTableCodecSuite) TestTableCodec(c *C) {
 	c.Assert(h, Equals, int64(2))
 }
 
+func (s *testTableCodecSuite) TestDecimalRowCodec(c *C) {
+	defer testleak.AfterTest(c)()
+	ft := types.NewFieldType(mysql.TypeNewDecimal)
+	ft.Flen = 10
+	ft.Decimal = 3
+	d := types.NewDecimalDatum(types.NewDecFromStringForTest("-1234.567"))
+	d.SetLength(ft.Flen)
+	d.SetFrac(ft.Decimal)
+
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	bs, err := EncodeRow(sc, []types.Datum{d}, []int64{1}, nil, nil, &rowcodec.Encoder{})
+	c.Assert(err, IsNil)
+	c.Assert(bs, NotNil)
+
+	r, err := DecodeRow(bs, map[int64]*types.FieldType{1: ft}, time.UTC)
+	c.Assert(err, IsNil)
+	v, ok := r[1]
+	c.Assert(ok, IsTrue)
+	c.Assert(v.Kind(), Equals, types.KindMysqlDecimal)
+	c.Assert(v.GetMysqlDecimal().Compare(d.GetMysqlDecimal()), Equals, 0)
+	c.Assert(v.Length(), Equals, ft.Flen)
+	c.Assert(v.Frac(), Equals, ft.Decimal)
+
+	colVal, err := EncodeValue(sc, nil, d)
+	c.Assert(err, IsNil)
+	v, err = DecodeColumnValue(colVal, ft, time.UTC)
+	c.Assert(err, IsNil)
+	c.Assert(v.GetMysqlDecimal().String(), Equals, "-1234.567")
+}
+
+func (s *testTableCodecSuite) TestTimeCodec(c *C) {
+	defer testleak.AfterTest(c)()
+	loc := time.FixedZone("UTC+8", 8*3600)
+	sc := &stmtctx.StatementContext{TimeZone: loc}
+	ts, err := types.ParseTimestamp(sc, "2020-02-02 10:00:00.123")
+	c.Assert(err, IsNil)
+	dt, err := types.ParseDatetime(sc, "2020-02-02 10:00:00")
+	c.Assert(err, IsNil)
+	dur, err := types.ParseDuration(sc, "-12:34:56.78", 2)
+	c.Assert(err, IsNil)
+	row := []types.Datum{types.NewTimeDatum(ts), types.NewTimeDatum(dt), types.NewDurationDatum(dur)}
+
+	tsFt := types.NewFieldType(mysql.TypeTimestamp)
+	tsFt.Decimal = 3
+	dtFt := types.NewFieldType(mysql.TypeDatetime)
+	dtFt.Decimal = 0
+	durFt := types.NewFieldType(mysql.TypeDuration)
+	durFt.Decimal = 2
+	cols := map[int64]*types.FieldType{1: tsFt, 2: dtFt, 3: durFt}
+
+	bs, err := EncodeRow(sc, row, []int64{1, 2, 3}, nil, nil, &rowcodec.Encoder{})
+	c.Assert(err, IsNil)
+	r, err := DecodeRow(bs, cols, loc)
+	c.Assert(err, IsNil)
+	c.Assert(len(r), Equals, 3)
+	for id, expect := range map[int64]string{1: "2020-02-02 10:00:00.123", 2: "2020-02-02 10:00:00", 3: "-12:34:56.78"} {
+		v := r[id]
+		str, err := v.ToString()
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, expect)
+	}
+
+	// The timestamp is stored in UTC, so decoding it in another time zone shifts the value,
+	// while the datetime keeps the literal value.
+	r, err = DecodeRow(bs, cols, time.UTC)
+	c.Assert(err, IsNil)
+	v := r[1]
+	c.Assert(v.GetMysqlTime().String(), Equals, "2020-02-02 02:00:00.123")
+	v = r[2]
+	c.Assert(v.GetMysqlTime().String(), Equals, "2020-02-02 10:00:00")
+
+	colVal, err := EncodeValue(sc, nil, row[0])
+	c.Assert(err, IsNil)
+	v, err = DecodeColumnValue(colVal, tsFt, loc)
+	c.Assert(err, IsNil)
+	c.Assert(v.GetMysqlTime().String(), Equals, "2020-02-02 10:00:00.123")
+}
+
 func (s *testTableCodecSuite) TestCutKeyNew(c *C) {
 	values := []types.Datum{types.NewIntDatum(1), types.NewBytesDatum([]byte("abc")), types.NewFloat64Datum(5.5)}
 	handle := types.NewIntDatum(100)
@@ -194,6 +273,7 @@ func (s *testTableCodecSuite) TestDecodeIndexKey(c *C) {
 		types.NewIntDatum(1),
 		types.NewBytesDatum([]byte("abc")),
 		types.NewFloat64Datum(123.45),
+		types.NewDecimalDatum(types.NewDecFromStringForTest("12.345")),
 		// MysqlTime is not supported.
 		// types.NewTimeDatum(types.Time{
 		// 	Time: types.FromGoTime(time.Now()),
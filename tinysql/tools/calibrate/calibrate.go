@@ -0,0 +1,446 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mock"
+	"go.uber.org/zap"
+)
+
+const (
+	// valueSize is the size of the values written for the scan and seek benchmarks.
+	valueSize = 64
+	// bigValueSize is the size of the values used to measure the network cost.
+	bigValueSize  = 256 * 1024
+	bigValueCount = 16
+	batchSize     = 1000
+	maxSeekCount  = 1000
+	// goroutineCount is the number of goroutines started in one round of the concurrency benchmark.
+	goroutineCount = 1000
+)
+
+// calibratePrefix is the key prefix of the data written by the calibrator,
+// it doesn't overlap with the table data and the meta data.
+var calibratePrefix = kv.Key("calibrate_")
+
+// calibrator measures the time of the operations the cost factors stand for,
+// the results are normalized so the CPU factor keeps its default value.
+type calibrator struct {
+	store  kv.Storage
+	rows   int
+	rounds int
+}
+
+func newCalibrator(store kv.Storage, rows, rounds int) *calibrator {
+	if rows < batchSize {
+		rows = batchSize
+	}
+	if rounds < 1 {
+		rounds = 1
+	}
+	return &calibrator{store: store, rows: rows, rounds: rounds}
+}
+
+// run writes the benchmark data, runs the benchmarks and returns the
+// calibrated cost factors. The data is removed before returning.
+func (c *calibrator) run() (profile *variable.CostProfile, err error) {
+	if err = c.prepare(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		terror.Log(c.cleanup())
+	}()
+
+	// The measurements are nanoseconds per unit of the factors.
+	benchmarks := []struct {
+		name  string
+		bench func() (float64, error)
+	}{
+		{variable.TiDBOptCPUFactor, c.measureCPU},
+		{variable.TiDBOptScanFactor, c.measureScan},
+		{variable.TiDBOptDescScanFactor, c.measureDescScan},
+		{variable.TiDBOptSeekFactor, c.measureSeek},
+		{variable.TiDBOptNetworkFactor, c.measureNetwork},
+		{variable.TiDBOptMemoryFactor, c.measureMemory},
+		{variable.TiDBOptDiskFactor, c.measureDisk},
+		{variable.TiDBOptConcurrencyFactor, c.measureConcurrency},
+	}
+	measurements := make(map[string]float64, len(benchmarks))
+	for _, b := range benchmarks {
+		results := make([]float64, 0, c.rounds)
+		for i := 0; i < c.rounds; i++ {
+			ns, err := b.bench()
+			if err != nil {
+				return nil, errors.Annotatef(err, "benchmark %s", b.name)
+			}
+			results = append(results, ns)
+		}
+		sort.Float64s(results)
+		measurements[b.name] = results[len(results)/2]
+		logutil.BgLogger().Info("calibrate cost factor", zap.String("name", b.name), zap.Float64("ns", measurements[b.name]))
+	}
+
+	unit := measurements[variable.TiDBOptCPUFactor] / variable.DefOptCPUFactor
+	factor := func(name string) float64 {
+		return roundFactor(measurements[name] / unit)
+	}
+	profile = variable.NewCostProfile()
+	// The coprocessor evaluates the expressions like TiDB does, the CPU cost
+	// on TiKV can't be measured by the calibrator.
+	profile.CopCPUFactor = profile.CPUFactor
+	profile.ScanFactor = factor(variable.TiDBOptScanFactor)
+	profile.DescScanFactor = factor(variable.TiDBOptDescScanFactor)
+	if profile.DescScanFactor == 0 {
+		logutil.BgLogger().Warn("desc scan is not supported by the store, the default ratio to the scan factor is used")
+		profile.DescScanFactor = roundFactor(profile.ScanFactor * variable.DefOptDescScanFactor / variable.DefOptScanFactor)
+	}
+	profile.SeekFactor = factor(variable.TiDBOptSeekFactor)
+	profile.NetworkFactor = factor(variable.TiDBOptNetworkFactor)
+	profile.MemoryFactor = factor(variable.TiDBOptMemoryFactor)
+	profile.DiskFactor = factor(variable.TiDBOptDiskFactor)
+	profile.ConcurrencyFactor = factor(variable.TiDBOptConcurrencyFactor)
+	return profile, nil
+}
+
+// roundFactor keeps 4 significant digits of the factor.
+func roundFactor(f float64) float64 {
+	if f <= 0 {
+		return 0
+	}
+	f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', 4, 64), 64)
+	return f
+}
+
+func rowKey(i int) kv.Key {
+	key := make([]byte, 0, len(calibratePrefix)+9)
+	key = append(key, calibratePrefix...)
+	key = append(key, 'r')
+	return codec.EncodeInt(key, int64(i))
+}
+
+func bigValueKey(i int) kv.Key {
+	key := make([]byte, 0, len(calibratePrefix)+9)
+	key = append(key, calibratePrefix...)
+	key = append(key, 'v')
+	return codec.EncodeInt(key, int64(i))
+}
+
+// rowPrefix is the prefix of the keys written by rowKey.
+func rowPrefix() kv.Key {
+	return append(append(kv.Key{}, calibratePrefix...), 'r')
+}
+
+func (c *calibrator) prepare() error {
+	value := make([]byte, valueSize)
+	for begin := 0; begin < c.rows; begin += batchSize {
+		err := kv.RunInNewTxn(c.store, false, func(txn kv.Transaction) error {
+			for i := begin; i < begin+batchSize && i < c.rows; i++ {
+				rand.Read(value)
+				if err := txn.Set(rowKey(i), value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return kv.RunInNewTxn(c.store, false, func(txn kv.Transaction) error {
+		bigValue := make([]byte, bigValueSize)
+		for i := 0; i < bigValueCount; i++ {
+			rand.Read(bigValue)
+			if err := txn.Set(bigValueKey(i), bigValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *calibrator) cleanup() error {
+	for begin := 0; begin < c.rows; begin += batchSize {
+		err := kv.RunInNewTxn(c.store, false, func(txn kv.Transaction) error {
+			for i := begin; i < begin+batchSize && i < c.rows; i++ {
+				if err := txn.Delete(rowKey(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return kv.RunInNewTxn(c.store, false, func(txn kv.Transaction) error {
+		for i := 0; i < bigValueCount; i++ {
+			if err := txn.Delete(bigValueKey(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *calibrator) snapshot() (kv.Snapshot, error) {
+	ver, err := c.store.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	return c.store.GetSnapshot(ver)
+}
+
+// measureCPU measures the time of evaluating `a + b > c` for one row.
+func (c *calibrator) measureCPU() (float64, error) {
+	ctx := mock.NewContext()
+	ft := types.NewFieldType(mysql.TypeLonglong)
+	cols := []*expression.Column{
+		{Index: 0, RetType: ft},
+		{Index: 1, RetType: ft},
+		{Index: 2, RetType: ft},
+	}
+	plus, err := expression.NewFunction(ctx, ast.Plus, ft, cols[0], cols[1])
+	if err != nil {
+		return 0, err
+	}
+	cond, err := expression.NewFunction(ctx, ast.GT, ft, plus, cols[2])
+	if err != nil {
+		return 0, err
+	}
+	chk := chunk.New([]*types.FieldType{ft, ft, ft}, batchSize, batchSize)
+	for i := 0; i < batchSize; i++ {
+		chk.AppendInt64(0, rand.Int63n(1000))
+		chk.AppendInt64(1, rand.Int63n(1000))
+		chk.AppendInt64(2, rand.Int63n(2000))
+	}
+
+	start := time.Now()
+	for evaluated := 0; evaluated < c.rows; {
+		for i := 0; i < chk.NumRows() && evaluated < c.rows; i++ {
+			if _, _, err = cond.EvalInt(ctx, chk.GetRow(i)); err != nil {
+				return 0, err
+			}
+			evaluated++
+		}
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(c.rows), nil
+}
+
+// measureScan measures the time of scanning one byte.
+func (c *calibrator) measureScan() (float64, error) {
+	snapshot, err := c.snapshot()
+	if err != nil {
+		return 0, err
+	}
+	prefix := rowPrefix()
+	start := time.Now()
+	iter, err := snapshot.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+	size := 0
+	for iter.Valid() {
+		size += len(iter.Key()) + len(iter.Value())
+		if err = iter.Next(); err != nil {
+			return 0, err
+		}
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(size), nil
+}
+
+// measureDescScan measures the time of scanning one byte in desc order, it
+// returns 0 if the store doesn't support the reverse scan.
+func (c *calibrator) measureDescScan() (float64, error) {
+	snapshot, err := c.snapshot()
+	if err != nil {
+		return 0, err
+	}
+	prefix := rowPrefix()
+	start := time.Now()
+	iter, err := snapshot.IterReverse(prefix.PrefixNext())
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+	size := 0
+	var lastKey kv.Key
+	for iter.Valid() && iter.Key().HasPrefix(prefix) {
+		if lastKey != nil && iter.Key().Cmp(lastKey) >= 0 {
+			// The store doesn't support the reverse scan.
+			return 0, nil
+		}
+		lastKey = append(lastKey[:0], iter.Key()...)
+		size += len(iter.Key()) + len(iter.Value())
+		if err = iter.Next(); err != nil {
+			return 0, err
+		}
+	}
+	if size == 0 {
+		return 0, nil
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(size), nil
+}
+
+// measureSeek measures the time of seeking the start of a range.
+func (c *calibrator) measureSeek() (float64, error) {
+	snapshot, err := c.snapshot()
+	if err != nil {
+		return 0, err
+	}
+	upperBound := rowPrefix().PrefixNext()
+	seekCount := c.rows
+	if seekCount > maxSeekCount {
+		seekCount = maxSeekCount
+	}
+	start := time.Now()
+	for i := 0; i < seekCount; i++ {
+		iter, err := snapshot.Iter(rowKey(rand.Intn(c.rows)), upperBound)
+		if err != nil {
+			return 0, err
+		}
+		if !iter.Valid() {
+			iter.Close()
+			return 0, errors.New("the benchmark data is not found")
+		}
+		iter.Close()
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(seekCount), nil
+}
+
+// measureNetwork measures the time of transferring one byte, it is the
+// difference between reading the big values and the small values.
+func (c *calibrator) measureNetwork() (float64, error) {
+	snapshot, err := c.snapshot()
+	if err != nil {
+		return 0, err
+	}
+	ctx := context.Background()
+	read := func(key func(int) kv.Key) (time.Duration, int, error) {
+		start := time.Now()
+		size := 0
+		for i := 0; i < bigValueCount; i++ {
+			value, err := snapshot.Get(ctx, key(i))
+			if err != nil {
+				return 0, 0, err
+			}
+			size += len(value)
+		}
+		return time.Since(start), size, nil
+	}
+	bigDuration, bigSize, err := read(bigValueKey)
+	if err != nil {
+		return 0, err
+	}
+	smallDuration, smallSize, err := read(rowKey)
+	if err != nil {
+		return 0, err
+	}
+	if bigDuration <= smallDuration {
+		return 0, nil
+	}
+	return float64((bigDuration - smallDuration).Nanoseconds()) / float64(bigSize-smallSize), nil
+}
+
+// measureMemory measures the time of storing one tuple in memory.
+func (c *calibrator) measureMemory() (float64, error) {
+	ft := types.NewFieldType(mysql.TypeLonglong)
+	fts := []*types.FieldType{ft, types.NewFieldType(mysql.TypeVarString)}
+	chk := chunk.New(fts, 1, 1)
+	chk.AppendInt64(0, 1)
+	chk.AppendBytes(1, make([]byte, valueSize))
+	row := chk.GetRow(0)
+
+	start := time.Now()
+	list := chunk.NewList(fts, batchSize, batchSize)
+	for i := 0; i < c.rows; i++ {
+		list.AppendRow(row)
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(list.Len()), nil
+}
+
+// measureDisk measures the time of writing and reading one byte on the
+// temporary disk.
+func (c *calibrator) measureDisk() (float64, error) {
+	f, err := ioutil.TempFile("", "calibrate")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		terror.Log(f.Close())
+		terror.Log(os.Remove(f.Name()))
+	}()
+	block := make([]byte, 64*1024)
+	rand.Read(block)
+	size := c.rows * valueSize
+
+	start := time.Now()
+	written := 0
+	for written < size {
+		n, err := f.Write(block)
+		if err != nil {
+			return 0, err
+		}
+		written += n
+	}
+	if err = f.Sync(); err != nil {
+		return 0, err
+	}
+	if _, err = f.Seek(0, 0); err != nil {
+		return 0, err
+	}
+	read := 0
+	for read < written {
+		n, err := f.Read(block)
+		if err != nil {
+			return 0, err
+		}
+		read += n
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(written+read), nil
+}
+
+// measureConcurrency measures the time of starting and waiting one goroutine.
+func (c *calibrator) measureConcurrency() (float64, error) {
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < goroutineCount; i++ {
+		wg.Add(1)
+		go func() {
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	return float64(time.Since(start).Nanoseconds()) / goroutineCount, nil
+}
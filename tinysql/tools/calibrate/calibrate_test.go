@@ -0,0 +1,73 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testCalibrateSuite{})
+
+type testCalibrateSuite struct {
+	store kv.Storage
+}
+
+func (s *testCalibrateSuite) SetUpSuite(c *C) {
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	s.store = store
+}
+
+func (s *testCalibrateSuite) TearDownSuite(c *C) {
+	c.Assert(s.store.Close(), IsNil)
+}
+
+func (s *testCalibrateSuite) TestCalibrate(c *C) {
+	defer testleak.AfterTest(c)()
+	profile, err := newCalibrator(s.store, 2000, 3).run()
+	c.Assert(err, IsNil)
+	c.Assert(profile.CPUFactor, Equals, variable.DefOptCPUFactor)
+	c.Assert(profile.CopCPUFactor, Equals, variable.DefOptCPUFactor)
+	for name, value := range profile.SysVars() {
+		_, err = variable.ValidateSetSystemVar(variable.NewSessionVars(), name, value)
+		c.Assert(err, IsNil, Commentf("%s = %s", name, value))
+	}
+	c.Assert(profile.ScanFactor, Greater, 0.0)
+	c.Assert(profile.SeekFactor, Greater, 0.0)
+
+	// The benchmark data is removed.
+	snapshot, err := newCalibrator(s.store, 0, 0).snapshot()
+	c.Assert(err, IsNil)
+	iter, err := snapshot.Iter(calibratePrefix, calibratePrefix.PrefixNext())
+	c.Assert(err, IsNil)
+	defer iter.Close()
+	c.Assert(iter.Valid(), IsFalse)
+}
+
+func (s *testCalibrateSuite) TestRoundFactor(c *C) {
+	c.Assert(roundFactor(1.234567), Equals, 1.235)
+	c.Assert(roundFactor(0.000123456), Equals, 0.0001235)
+	c.Assert(roundFactor(12345.6), Equals, 12350.0)
+	c.Assert(roundFactor(-1), Equals, 0.0)
+}
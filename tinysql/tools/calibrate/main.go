@@ -0,0 +1,74 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The calibrate command runs micro-benchmarks against a mocktikv store or a
+// local cluster, and writes the cost factors measured on the hardware to a
+// cost profile. The profile is copied to the cost-profile-dir of the server
+// and loaded by
+//
+//	SET GLOBAL tidb_opt_cost_profile = 'cost_profile.json'
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pingcap/tidb/parser/terror"
+	kvstore "github.com/pingcap/tidb/store"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+var (
+	store     = flag.String("store", "mocktikv", "registered store name, [tikv, mocktikv]")
+	storePath = flag.String("path", "/tmp/tidb", "tidb storage path")
+	output    = flag.String("output", "cost_profile.json", "the path of the cost profile to write")
+	rows      = flag.Int("rows", 100000, "the number of rows used by the benchmarks")
+	rounds    = flag.Int("rounds", 5, "the number of rounds of each benchmark, the median result is used")
+)
+
+func main() {
+	flag.Parse()
+	err := kvstore.Register("tikv", tikv.Driver{})
+	terror.MustNil(err)
+	err = kvstore.Register("mocktikv", mockstore.MockDriver{})
+	terror.MustNil(err)
+
+	storage, err := kvstore.New(fmt.Sprintf("%s://%s", *store, *storePath))
+	terror.MustNil(err)
+	defer terror.Call(storage.Close)
+
+	c := newCalibrator(storage, *rows, *rounds)
+	profile, err := c.run()
+	if err != nil {
+		logutil.BgLogger().Error("calibrate cost factors failed", zap.Error(err))
+		os.Exit(1)
+	}
+	err = profile.Save(*output)
+	terror.MustNil(err)
+
+	vars := profile.SysVars()
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s = %s\n", name, vars[name])
+	}
+	fmt.Printf("cost profile is written to %s\n", *output)
+}
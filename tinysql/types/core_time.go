@@ -0,0 +1,451 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	gotime "time"
+
+	"github.com/pingcap/errors"
+)
+
+// CoreTime is the internal struct type for Time.
+// It packs year, month, day, hour, minute, second and microsecond into a uint64,
+// the lowest 4 bits are reserved for the fsp and the type of the owning Time.
+type CoreTime uint64
+
+const (
+	yearBitFieldOffset, yearBitFieldWidth               uint64 = 50, 14
+	monthBitFieldOffset, monthBitFieldWidth             uint64 = 46, 4
+	dayBitFieldOffset, dayBitFieldWidth                 uint64 = 41, 5
+	hourBitFieldOffset, hourBitFieldWidth               uint64 = 36, 5
+	minuteBitFieldOffset, minuteBitFieldWidth           uint64 = 30, 6
+	secondBitFieldOffset, secondBitFieldWidth           uint64 = 24, 6
+	microsecondBitFieldOffset, microsecondBitFieldWidth uint64 = 4, 20
+
+	yearBitFieldMask        uint64 = ((1 << yearBitFieldWidth) - 1) << yearBitFieldOffset
+	monthBitFieldMask       uint64 = ((1 << monthBitFieldWidth) - 1) << monthBitFieldOffset
+	dayBitFieldMask         uint64 = ((1 << dayBitFieldWidth) - 1) << dayBitFieldOffset
+	hourBitFieldMask        uint64 = ((1 << hourBitFieldWidth) - 1) << hourBitFieldOffset
+	minuteBitFieldMask      uint64 = ((1 << minuteBitFieldWidth) - 1) << minuteBitFieldOffset
+	secondBitFieldMask      uint64 = ((1 << secondBitFieldWidth) - 1) << secondBitFieldOffset
+	microsecondBitFieldMask uint64 = ((1 << microsecondBitFieldWidth) - 1) << microsecondBitFieldOffset
+
+	coreTimeBitFieldMask = ^uint64((1 << microsecondBitFieldOffset) - 1)
+)
+
+// ZeroCoreTime is the zero value for CoreTime.
+var ZeroCoreTime = CoreTime(0)
+
+// FromDate makes a internal time representation from the given date.
+func FromDate(year int, month int, day int, hour int, minute int, second int, microsecond int) CoreTime {
+	v := uint64(ZeroCoreTime)
+	v |= (uint64(microsecond) << microsecondBitFieldOffset) & microsecondBitFieldMask
+	v |= (uint64(second) << secondBitFieldOffset) & secondBitFieldMask
+	v |= (uint64(minute) << minuteBitFieldOffset) & minuteBitFieldMask
+	v |= (uint64(hour) << hourBitFieldOffset) & hourBitFieldMask
+	v |= (uint64(day) << dayBitFieldOffset) & dayBitFieldMask
+	v |= (uint64(month) << monthBitFieldOffset) & monthBitFieldMask
+	v |= (uint64(year) << yearBitFieldOffset) & yearBitFieldMask
+	return CoreTime(v)
+}
+
+// FromGoTime translates time.Time to mysql time internal representation.
+func FromGoTime(t gotime.Time) CoreTime {
+	// Plus 500 nanosecond for rounding of the millisecond part.
+	t = t.Add(500 * gotime.Nanosecond)
+
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	microsecond := t.Nanosecond() / 1000
+	return FromDate(year, int(month), day, hour, minute, second, microsecond)
+}
+
+func (t CoreTime) getYear() uint16 {
+	return uint16((uint64(t) & yearBitFieldMask) >> yearBitFieldOffset)
+}
+
+func (t *CoreTime) setYear(year uint16) {
+	*(*uint64)(t) &= ^yearBitFieldMask
+	*(*uint64)(t) |= (uint64(year) << yearBitFieldOffset) & yearBitFieldMask
+}
+
+// Year returns the year value.
+func (t CoreTime) Year() int {
+	return int(t.getYear())
+}
+
+func (t CoreTime) getMonth() uint8 {
+	return uint8((uint64(t) & monthBitFieldMask) >> monthBitFieldOffset)
+}
+
+func (t *CoreTime) setMonth(month uint8) {
+	*(*uint64)(t) &= ^monthBitFieldMask
+	*(*uint64)(t) |= (uint64(month) << monthBitFieldOffset) & monthBitFieldMask
+}
+
+// Month returns the month value.
+func (t CoreTime) Month() int {
+	return int(t.getMonth())
+}
+
+func (t CoreTime) getDay() uint8 {
+	return uint8((uint64(t) & dayBitFieldMask) >> dayBitFieldOffset)
+}
+
+func (t *CoreTime) setDay(day uint8) {
+	*(*uint64)(t) &= ^dayBitFieldMask
+	*(*uint64)(t) |= (uint64(day) << dayBitFieldOffset) & dayBitFieldMask
+}
+
+// Day returns the day value.
+func (t CoreTime) Day() int {
+	return int(t.getDay())
+}
+
+func (t CoreTime) getHour() uint8 {
+	return uint8((uint64(t) & hourBitFieldMask) >> hourBitFieldOffset)
+}
+
+func (t *CoreTime) setHour(hour uint8) {
+	*(*uint64)(t) &= ^hourBitFieldMask
+	*(*uint64)(t) |= (uint64(hour) << hourBitFieldOffset) & hourBitFieldMask
+}
+
+// Hour returns the hour value.
+func (t CoreTime) Hour() int {
+	return int(t.getHour())
+}
+
+func (t CoreTime) getMinute() uint8 {
+	return uint8((uint64(t) & minuteBitFieldMask) >> minuteBitFieldOffset)
+}
+
+func (t *CoreTime) setMinute(minute uint8) {
+	*(*uint64)(t) &= ^minuteBitFieldMask
+	*(*uint64)(t) |= (uint64(minute) << minuteBitFieldOffset) & minuteBitFieldMask
+}
+
+// Minute returns the minute value.
+func (t CoreTime) Minute() int {
+	return int(t.getMinute())
+}
+
+func (t CoreTime) getSecond() uint8 {
+	return uint8((uint64(t) & secondBitFieldMask) >> secondBitFieldOffset)
+}
+
+func (t *CoreTime) setSecond(second uint8) {
+	*(*uint64)(t) &= ^secondBitFieldMask
+	*(*uint64)(t) |= (uint64(second) << secondBitFieldOffset) & secondBitFieldMask
+}
+
+// Second returns the second value.
+func (t CoreTime) Second() int {
+	return int(t.getSecond())
+}
+
+func (t CoreTime) getMicrosecond() uint32 {
+	return uint32((uint64(t) & microsecondBitFieldMask) >> microsecondBitFieldOffset)
+}
+
+func (t *CoreTime) setMicrosecond(microsecond uint32) {
+	*(*uint64)(t) &= ^microsecondBitFieldMask
+	*(*uint64)(t) |= (uint64(microsecond) << microsecondBitFieldOffset) & microsecondBitFieldMask
+}
+
+// Microsecond returns the microsecond value.
+func (t CoreTime) Microsecond() int {
+	return int(t.getMicrosecond())
+}
+
+// Weekday returns weekday value.
+func (t CoreTime) Weekday() gotime.Weekday {
+	// No need to consider BC for now, the weekday of Monday 0001-01-01 is 1.
+	weekday := calcWeekday(calcDaynr(t.Year(), t.Month(), t.Day()), false)
+	return gotime.Weekday((weekday + 1) % 7)
+}
+
+// YearDay returns day in year.
+func (t CoreTime) YearDay() int {
+	if t.Month() == 0 || t.Day() == 0 {
+		return 0
+	}
+	return calcDaynr(t.Year(), t.Month(), t.Day()) -
+		calcDaynr(t.Year(), 1, 1) + 1
+}
+
+// YearWeek returns year and week.
+func (t CoreTime) YearWeek(mode int) (int, int) {
+	behavior := weekMode(mode) | weekBehaviourYear
+	return calcWeek(t, behavior)
+}
+
+// Week returns week value.
+func (t CoreTime) Week(mode int) int {
+	if t.Month() == 0 || t.Day() == 0 {
+		return 0
+	}
+	_, week := calcWeek(t, weekMode(mode))
+	return week
+}
+
+// GoTime converts Time to GoTime.
+func (t CoreTime) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	// gotime.Time can't represent month 0 or day 0, date contains 0 would be converted to a nearest date,
+	// For example, 2006-12-00 00:00:00 would become 2015-11-30 23:59:59.
+	year, month, day, hour, minute, second, microsecond := t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond()
+	tm := gotime.Date(year, gotime.Month(month), day, hour, minute, second, microsecond*1000, loc)
+	year2, month2, day2 := tm.Date()
+	hour2, minute2, second2 := tm.Clock()
+	microsec2 := tm.Nanosecond() / 1000
+	// This function will check the result, and return an error if it's not the same with the origin input.
+	if year2 != year || int(month2) != month || day2 != day ||
+		hour2 != hour || minute2 != minute || second2 != second ||
+		microsec2 != microsecond {
+		return tm, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, t))
+	}
+	return tm, nil
+}
+
+// IsLeapYear returns if it's leap year.
+func (t CoreTime) IsLeapYear() bool {
+	return isLeapYear(t.getYear())
+}
+
+func isLeapYear(year uint16) bool {
+	return (year%4 == 0 && year%100 != 0) || year%400 == 0
+}
+
+var daysByMonth = [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// GetLastDay returns the last day of the month
+func GetLastDay(year, month int) int {
+	var day = 0
+	if month > 0 && month <= 12 {
+		day = daysByMonth[month-1]
+	}
+	if month == 2 && isLeapYear(uint16(year)) {
+		day = 29
+	}
+	return day
+}
+
+func getFixDays(year, month, day int, ot gotime.Time) int {
+	if (year != 0 || month != 0) && day == 0 {
+		od := ot.Day()
+		t := ot.AddDate(year, month, day)
+		td := t.Day()
+		if od != td {
+			return -td
+		}
+	}
+	return 0
+}
+
+// compareTime compare two CoreTime.
+func compareTime(a, b CoreTime) int {
+	ta := a.datetimeValue()
+	tb := b.datetimeValue()
+	if ta < tb {
+		return -1
+	} else if ta > tb {
+		return 1
+	}
+	return 0
+}
+
+// datetimeValue strips the fsp and type bits so that two CoreTime can be compared as integers.
+func (t CoreTime) datetimeValue() uint64 {
+	return uint64(t) & coreTimeBitFieldMask
+}
+
+// DateDiff calculates number of days between two days.
+func DateDiff(startTime, endTime CoreTime) int {
+	return calcDaynr(startTime.Year(), startTime.Month(), startTime.Day()) - calcDaynr(endTime.Year(), endTime.Month(), endTime.Day())
+}
+
+// AddDate fix gap between mysql and golang api
+// When we execute select date_add('2018-01-31',interval 1 month) in mysql we got 2018-02-28
+// but in tidb we got 2018-03-03.
+// Dig it and we found it's caused by golang api time.Date(year int, month Month, day, hour, min, sec, nsec int, loc *Location) Time ,
+// it says October 32 converts to November 1 ,it conflicts with mysql.
+// See https://github.com/mysql/mysql-server/blob/a2757a60cbb5e2ac40a3e8c8daa3d0d7cb6d7d6b/sql/sql_time.cc#L1042
+func AddDate(year, month, day int64, ot gotime.Time) (nt gotime.Time) {
+	df := getFixDays(int(year), int(month), int(day), ot)
+	if df != 0 {
+		nt = ot.AddDate(int(year), int(month), df)
+	} else {
+		nt = ot.AddDate(int(year), int(month), int(day))
+	}
+	return nt
+}
+
+func calcTimeFromSec(to *CoreTime, seconds, microseconds int) {
+	to.setHour(uint8(seconds / 3600))
+	seconds = seconds % 3600
+	to.setMinute(uint8(seconds / 60))
+	to.setSecond(uint8(seconds % 60))
+	to.setMicrosecond(uint32(microseconds))
+}
+
+const secondsIn24Hour = 86400
+
+// calcTimeDiff calculates difference between two datetime values as seconds + microseconds.
+// t1 and t2 should be TIME/DATE/DATETIME value.
+// sign can be +1 or -1, and t2 is preprocessed with sign first.
+func calcTimeDiff(t1, t2 CoreTime, sign int) (seconds, microseconds int, neg bool) {
+	days := calcDaynr(t1.Year(), t1.Month(), t1.Day())
+	days2 := calcDaynr(t2.Year(), t2.Month(), t2.Day())
+	days -= sign * days2
+
+	tmp := (int64(days)*secondsIn24Hour+
+		int64(t1.Hour())*3600+int64(t1.Minute())*60+
+		int64(t1.Second())-
+		int64(sign)*(int64(t2.Hour())*3600+int64(t2.Minute())*60+
+			int64(t2.Second())))*
+		1e6 +
+		int64(t1.Microsecond()) - int64(sign)*int64(t2.Microsecond())
+
+	if tmp < 0 {
+		tmp = -tmp
+		neg = true
+	}
+	seconds = int(tmp / 1e6)
+	microseconds = int(tmp % 1e6)
+	return
+}
+
+// datetimeToUint64 converts time value to integer in YYYYMMDDHHMMSS format.
+func datetimeToUint64(t CoreTime) uint64 {
+	return dateToUint64(t)*1e6 + timeToUint64(t)
+}
+
+// dateToUint64 converts time value to integer in YYYYMMDD format.
+func dateToUint64(t CoreTime) uint64 {
+	return uint64(t.Year())*10000 +
+		uint64(t.Month())*100 +
+		uint64(t.Day())
+}
+
+// timeToUint64 converts time value to integer in HHMMSS format.
+func timeToUint64(t CoreTime) uint64 {
+	return uint64(t.Hour())*10000 +
+		uint64(t.Minute())*100 +
+		uint64(t.Second())
+}
+
+// calcDaynr calculates days since 0000-00-00.
+func calcDaynr(year, month, day int) int {
+	if year == 0 && month == 0 {
+		return 0
+	}
+
+	delsum := 365*year + 31*(month-1) + day
+	if month <= 2 {
+		year--
+	} else {
+		delsum -= (month*4 + 23) / 10
+	}
+	temp := ((year/100 + 1) * 3) / 4
+	return delsum + year/4 - temp
+}
+
+// calcDaysInYear calculates days in one year, it works with 0 <= year <= 99.
+func calcDaysInYear(year int) int {
+	if (year&3) == 0 && (year%100 != 0 || (year%400 == 0 && (year != 0))) {
+		return 366
+	}
+	return 365
+}
+
+// calcWeekday calculates weekday from daynr, returns 0 for Monday, 1 for Tuesday ...
+func calcWeekday(daynr int, sundayFirstDayOfWeek bool) int {
+	daynr += 5
+	if sundayFirstDayOfWeek {
+		daynr++
+	}
+	return daynr % 7
+}
+
+type weekBehaviour uint
+
+const (
+	// weekBehaviourMondayFirst set Monday as first day of week; otherwise Sunday is first day of week
+	weekBehaviourMondayFirst weekBehaviour = 1 << iota
+	// If set, Week is in range 1-53, otherwise Week is in range 0-53.
+	// Note that this flag is only relevant if WEEK_JANUARY is not set.
+	weekBehaviourYear
+	// If not set, Weeks are numbered according to ISO 8601:1988.
+	// If set, the week that contains the first 'first-day-of-week' is week 1.
+	weekBehaviourFirstWeekday
+)
+
+func (v weekBehaviour) test(flag weekBehaviour) bool {
+	return (v & flag) != 0
+}
+
+func weekMode(mode int) weekBehaviour {
+	weekFormat := weekBehaviour(mode & 7)
+	if (weekFormat & weekBehaviourMondayFirst) == 0 {
+		weekFormat ^= weekBehaviourFirstWeekday
+	}
+	return weekFormat
+}
+
+// calcWeek calculates week and year for the time.
+func calcWeek(t CoreTime, wb weekBehaviour) (year int, week int) {
+	var days int
+	ty, tm, td := t.Year(), t.Month(), t.Day()
+	daynr := calcDaynr(ty, tm, td)
+	firstDaynr := calcDaynr(ty, 1, 1)
+	mondayFirst := wb.test(weekBehaviourMondayFirst)
+	weekYear := wb.test(weekBehaviourYear)
+	firstWeekday := wb.test(weekBehaviourFirstWeekday)
+
+	weekday := calcWeekday(firstDaynr, !mondayFirst)
+
+	year = ty
+
+	if tm == 1 && td <= 7-weekday {
+		if !weekYear &&
+			((firstWeekday && weekday != 0) || (!firstWeekday && weekday >= 4)) {
+			week = 0
+			return
+		}
+		weekYear = true
+		year--
+		days = calcDaysInYear(year)
+		firstDaynr -= days
+		weekday = (weekday + 53*7 - days) % 7
+	}
+
+	if (firstWeekday && weekday != 0) ||
+		(!firstWeekday && weekday >= 4) {
+		days = daynr - (firstDaynr + 7 - weekday)
+	} else {
+		days = daynr - (firstDaynr - weekday)
+	}
+
+	if weekYear && days >= 52*7 {
+		weekday = (weekday + calcDaysInYear(year)) % 7
+		if (!firstWeekday && weekday < 4) ||
+			(firstWeekday && weekday == 0) {
+			year++
+			week = 1
+			return
+		}
+	}
+	week = days/7 + 1
+	return
+}
@@ -19,6 +19,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/pingcap/errors"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/hack"
 )
 
@@ -38,7 +40,9 @@ const (
 	KindFloat64       byte = 4
 	KindString        byte = 5
 	KindBytes         byte = 6
-	KindBinaryLiteral byte = 7  // Used for BIT / HEX literals.
+	KindBinaryLiteral byte = 7 // Used for BIT / HEX literals.
+	KindMysqlDecimal  byte = 8
+	KindMysqlDuration byte = 9
 	KindMysqlBit      byte = 11 // Used for BIT table column values.
 	KindMysqlSet      byte = 12
 	KindMysqlTime     byte = 13
@@ -68,6 +72,10 @@ func (d *Datum) Copy() *Datum {
 		ret.b = make([]byte, len(d.b))
 		copy(ret.b, d.b)
 	}
+	if d.k == KindMysqlDecimal {
+		dec := *d.GetMysqlDecimal()
+		ret.SetMysqlDecimal(&dec)
+	}
 	return &ret
 }
 
@@ -233,6 +241,52 @@ func (d *Datum) SetMysqlBit(b BinaryLiteral) {
 	d.b = b
 }
 
+// GetMysqlDecimal gets Decimal value
+func (d *Datum) GetMysqlDecimal() *MyDecimal {
+	return d.x.(*MyDecimal)
+}
+
+// SetMysqlDecimal sets Decimal value
+func (d *Datum) SetMysqlDecimal(b *MyDecimal) {
+	d.k = KindMysqlDecimal
+	d.x = b
+}
+
+// GetMysqlDuration gets Duration value
+func (d *Datum) GetMysqlDuration() Duration {
+	return Duration{Duration: time.Duration(d.i), Fsp: int8(d.decimal)}
+}
+
+// SetMysqlDuration sets Duration value
+func (d *Datum) SetMysqlDuration(b Duration) {
+	d.k = KindMysqlDuration
+	d.i = int64(b.Duration)
+	d.decimal = uint16(b.Fsp)
+}
+
+// GetMysqlTime gets types.Time value
+func (d *Datum) GetMysqlTime() Time {
+	return d.x.(Time)
+}
+
+// SetMysqlTime sets types.Time value
+func (d *Datum) SetMysqlTime(b Time) {
+	d.k = KindMysqlTime
+	d.x = b
+}
+
+// GetMysqlJSON gets json.BinaryJSON value
+func (d *Datum) GetMysqlJSON() json.BinaryJSON {
+	return json.BinaryJSON{TypeCode: byte(d.i), Value: d.b}
+}
+
+// SetMysqlJSON sets json.BinaryJSON value
+func (d *Datum) SetMysqlJSON(b json.BinaryJSON) {
+	d.k = KindMysqlJSON
+	d.i = int64(b.TypeCode)
+	d.b = b.Value
+}
+
 // SetRaw sets raw value.
 func (d *Datum) SetRaw(b []byte) {
 	d.k = KindRaw
@@ -275,6 +329,10 @@ func (d Datum) String() string {
 		t = "KindBinaryLiteral"
 	case KindMysqlBit:
 		t = "KindMysqlBit"
+	case KindMysqlDecimal:
+		t = "KindMysqlDecimal"
+	case KindMysqlDuration:
+		t = "KindMysqlDuration"
 	case KindMysqlSet:
 		t = "KindMysqlSet"
 	case KindMysqlJSON:
@@ -308,6 +366,14 @@ func (d *Datum) GetValue() interface{} {
 		return d.GetBytes()
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.GetBinaryLiteral()
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal()
+	case KindMysqlDuration:
+		return d.GetMysqlDuration()
+	case KindMysqlTime:
+		return d.GetMysqlTime()
+	case KindMysqlJSON:
+		return d.GetMysqlJSON()
 	default:
 		return d.GetInterface()
 	}
@@ -344,6 +410,14 @@ func (d *Datum) SetValue(val interface{}) {
 		d.SetBinaryLiteral(BinaryLiteral(x))
 	case HexLiteral:
 		d.SetBinaryLiteral(BinaryLiteral(x))
+	case *MyDecimal:
+		d.SetMysqlDecimal(x)
+	case Duration:
+		d.SetMysqlDuration(x)
+	case Time:
+		d.SetMysqlTime(x)
+	case json.BinaryJSON:
+		d.SetMysqlJSON(x)
 	default:
 		d.SetInterface(x)
 	}
@@ -386,6 +460,14 @@ func (d *Datum) CompareDatum(sc *stmtctx.StatementContext, ad *Datum) (int, erro
 		return d.compareBytes(sc, ad.GetBytes())
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.compareBinaryLiteral(sc, ad.GetBinaryLiteral())
+	case KindMysqlDecimal:
+		return d.compareMysqlDecimal(sc, ad.GetMysqlDecimal())
+	case KindMysqlDuration:
+		return d.compareMysqlDuration(sc, ad.GetMysqlDuration())
+	case KindMysqlTime:
+		return d.compareMysqlTime(sc, ad.GetMysqlTime())
+	case KindMysqlJSON:
+		return d.compareMysqlJSON(sc, ad.GetMysqlJSON())
 	default:
 		return 0, nil
 	}
@@ -442,6 +524,15 @@ func (d *Datum) compareFloat64(sc *stmtctx.StatementContext, f float64) (int, er
 		val, err := d.GetBinaryLiteral().ToInt(sc)
 		fVal := float64(val)
 		return CompareFloat64(fVal, f), errors.Trace(err)
+	case KindMysqlDecimal:
+		fVal, err := d.GetMysqlDecimal().ToFloat64()
+		return CompareFloat64(fVal, f), errors.Trace(err)
+	case KindMysqlDuration:
+		fVal := d.GetMysqlDuration().Seconds()
+		return CompareFloat64(fVal, f), nil
+	case KindMysqlTime:
+		fVal, err := d.GetMysqlTime().ToNumber().ToFloat64()
+		return CompareFloat64(fVal, f), errors.Trace(err)
 	default:
 		return -1, nil
 	}
@@ -457,6 +548,16 @@ func (d *Datum) compareString(sc *stmtctx.StatementContext, s string) (int, erro
 		return CompareString(d.GetString(), s), nil
 	case KindBinaryLiteral, KindMysqlBit:
 		return CompareString(d.GetBinaryLiteral().ToString(), s), nil
+	case KindMysqlDecimal:
+		dec := new(MyDecimal)
+		err := sc.HandleTruncate(dec.FromString([]byte(s)))
+		return d.GetMysqlDecimal().Compare(dec), errors.Trace(err)
+	case KindMysqlTime:
+		dt, err := ParseDatetime(sc, s)
+		return d.GetMysqlTime().Compare(dt), errors.Trace(err)
+	case KindMysqlDuration:
+		dDur, err := ParseDuration(sc, s, MaxFsp)
+		return d.GetMysqlDuration().Compare(dDur), errors.Trace(err)
 	default:
 		fVal, err := StrToFloat(sc, s)
 		if err != nil {
@@ -487,6 +588,71 @@ func (d *Datum) compareBinaryLiteral(sc *stmtctx.StatementContext, b BinaryLiter
 	}
 }
 
+func (d *Datum) compareMysqlDecimal(sc *stmtctx.StatementContext, dec *MyDecimal) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().Compare(dec), nil
+	case KindString, KindBytes:
+		dDec := new(MyDecimal)
+		err := sc.HandleTruncate(dDec.FromString(d.GetBytes()))
+		return dDec.Compare(dec), errors.Trace(err)
+	default:
+		dVal, err := d.ConvertTo(sc, NewFieldType(mysql.TypeNewDecimal))
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return dVal.GetMysqlDecimal().Compare(dec), nil
+	}
+}
+
+func (d *Datum) compareMysqlDuration(sc *stmtctx.StatementContext, dur Duration) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().Compare(dur), nil
+	case KindString, KindBytes:
+		dDur, err := ParseDuration(sc, d.GetString(), MaxFsp)
+		return dDur.Compare(dur), errors.Trace(err)
+	default:
+		return d.compareFloat64(sc, dur.Seconds())
+	}
+}
+
+func (d *Datum) compareMysqlTime(sc *stmtctx.StatementContext, time Time) (int, error) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, nil
+	case KindMaxValue:
+		return 1, nil
+	case KindString, KindBytes:
+		dt, err := ParseDatetime(sc, d.GetString())
+		return dt.Compare(time), errors.Trace(err)
+	case KindMysqlTime:
+		return d.GetMysqlTime().Compare(time), nil
+	default:
+		fVal, err := time.ToNumber().ToFloat64()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return d.compareFloat64(sc, fVal)
+	}
+}
+
+func (d *Datum) compareMysqlJSON(sc *stmtctx.StatementContext, target json.BinaryJSON) (int, error) {
+	origin, err := d.ToMysqlJSON()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return json.CompareBinary(origin, target), nil
+}
+
 // ConvertTo converts a datum to the target field type.
 func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
 	if d.k == KindNull {
@@ -504,8 +670,16 @@ func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datu
 	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob,
 		mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString:
 		return d.convertToString(sc, target)
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return d.convertToMysqlTime(sc, target)
+	case mysql.TypeDuration:
+		return d.convertToMysqlDuration(sc, target)
+	case mysql.TypeNewDecimal:
+		return d.convertToMysqlDecimal(sc, target)
 	case mysql.TypeBit:
 		return d.convertToMysqlBit(sc, target)
+	case mysql.TypeJSON:
+		return d.convertToMysqlJSON(sc, target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -533,6 +707,12 @@ func (d *Datum) convertToFloat(sc *stmtctx.StatementContext, target *FieldType)
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err1 := d.GetBinaryLiteral().ToInt(sc)
 		f, err = float64(val), err1
+	case KindMysqlDecimal:
+		f, err = d.GetMysqlDecimal().ToFloat64()
+	case KindMysqlTime:
+		f, err = d.GetMysqlTime().ToNumber().ToFloat64()
+	case KindMysqlDuration:
+		f, err = d.GetMysqlDuration().ToNumber().ToFloat64()
 	default:
 		return invalidConv(d, target.Tp)
 	}
@@ -579,8 +759,16 @@ func (d *Datum) convertToString(sc *stmtctx.StatementContext, target *FieldType)
 		s = strconv.FormatFloat(d.GetFloat64(), 'f', -1, 64)
 	case KindString, KindBytes:
 		s = d.GetString()
+	case KindMysqlDecimal:
+		s = d.GetMysqlDecimal().String()
+	case KindMysqlTime:
+		s = d.GetMysqlTime().String()
+	case KindMysqlDuration:
+		s = d.GetMysqlDuration().String()
 	case KindBinaryLiteral, KindMysqlBit:
 		s = d.GetBinaryLiteral().ToString()
+	case KindMysqlJSON:
+		s = d.GetMysqlJSON().String()
 	default:
 		return invalidConv(d, target.Tp)
 	}
@@ -661,6 +849,12 @@ func (d *Datum) convertToUint(sc *stmtctx.StatementContext, target *FieldType) (
 			return ret, errors.Trace(err)
 		}
 		err = err1
+	case KindMysqlTime:
+		val, err = ConvertDecimalToUint(sc, d.GetMysqlTime().ToNumber(), upperBound, tp)
+	case KindMysqlDuration:
+		val, err = ConvertDecimalToUint(sc, d.GetMysqlDuration().ToNumber(), upperBound, tp)
+	case KindMysqlDecimal:
+		val, err = ConvertDecimalToUint(sc, d.GetMysqlDecimal(), upperBound, tp)
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err = d.GetBinaryLiteral().ToInt(sc)
 	default:
@@ -673,6 +867,181 @@ func (d *Datum) convertToUint(sc *stmtctx.StatementContext, target *FieldType) (
 	return ret, nil
 }
 
+func (d *Datum) convertToMysqlDecimal(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var ret Datum
+	ret.SetLength(target.Flen)
+	ret.SetFrac(target.Decimal)
+	var dec = &MyDecimal{}
+	var err error
+	switch d.k {
+	case KindInt64:
+		dec.FromInt(d.GetInt64())
+	case KindUint64:
+		dec.FromUint(d.GetUint64())
+	case KindFloat32, KindFloat64:
+		err = dec.FromFloat64(d.GetFloat64())
+	case KindString, KindBytes:
+		err = dec.FromString(d.GetBytes())
+		if err == ErrTruncated || err == ErrBadNumber {
+			err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", d.GetString()))
+		}
+	case KindMysqlDecimal:
+		*dec = *d.GetMysqlDecimal()
+	case KindMysqlTime:
+		dec = d.GetMysqlTime().ToNumber()
+	case KindMysqlDuration:
+		dec = d.GetMysqlDuration().ToNumber()
+	case KindBinaryLiteral, KindMysqlBit:
+		val, err1 := d.GetBinaryLiteral().ToInt(sc)
+		err = err1
+		dec.FromUint(val)
+	default:
+		return invalidConv(d, target.Tp)
+	}
+	var err1 error
+	dec, err1 = ProduceDecWithSpecifiedTp(dec, target, sc)
+	if err == nil && err1 != nil {
+		err = err1
+	}
+	if dec.negative && mysql.HasUnsignedFlag(target.Flag) {
+		*dec = zeroMyDecimal
+		if err == nil {
+			err = ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%d, %d)", target.Flen, target.Decimal))
+		}
+	}
+	ret.SetMysqlDecimal(dec)
+	return ret, err
+}
+
+// ProduceDecWithSpecifiedTp produces a new decimal according to `flen` and `decimal`.
+func ProduceDecWithSpecifiedTp(dec *MyDecimal, tp *FieldType, sc *stmtctx.StatementContext) (_ *MyDecimal, err error) {
+	flen, decimal := tp.Flen, tp.Decimal
+	if flen != UnspecifiedLength && decimal != UnspecifiedLength {
+		if flen < decimal {
+			return nil, ErrMBiggerThanD.GenWithStackByArgs("")
+		}
+		prec, frac := dec.PrecisionAndFrac()
+		if !dec.IsZero() && prec-frac > flen-decimal {
+			dec = NewMaxOrMinDec(dec.IsNegative(), flen, decimal)
+			// select (cast 111 as decimal(1)) causes a warning in MySQL.
+			err = ErrOverflow.GenWithStackByArgs("DECIMAL", fmt.Sprintf("(%d, %d)", flen, decimal))
+		} else if frac != decimal {
+			old := *dec
+			err = dec.Round(dec, decimal, ModeHalfEven)
+			if err != nil {
+				return nil, err
+			}
+			if !dec.IsZero() && frac > decimal && dec.Compare(&old) != 0 {
+				if sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt {
+					// Rounding the inserted value is not an error in MySQL.
+					sc.AppendWarning(ErrTruncated)
+					err = nil
+				} else {
+					err = sc.HandleTruncate(ErrTruncated)
+				}
+			}
+		}
+	}
+
+	if ErrOverflow.Equal(err) {
+		err = sc.HandleOverflow(err, err)
+	}
+	unsigned := mysql.HasUnsignedFlag(tp.Flag)
+	if unsigned && dec.IsNegative() {
+		dec = dec.FromUint(0)
+	}
+	return dec, err
+}
+
+func (d *Datum) convertToMysqlTime(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	tp := target.Tp
+	fsp := DefaultFsp
+	if target.Decimal != UnspecifiedLength {
+		fsp = int8(target.Decimal)
+	}
+	var (
+		ret Datum
+		t   Time
+		err error
+	)
+	switch d.k {
+	case KindMysqlTime:
+		t, err = d.GetMysqlTime().Convert(sc, tp)
+		if err != nil {
+			ret.SetMysqlTime(t)
+			return ret, errors.Trace(err)
+		}
+		t, err = t.RoundFrac(sc, fsp)
+	case KindMysqlDuration:
+		t, err = d.GetMysqlDuration().ConvertToTime(sc, tp)
+		if err != nil {
+			ret.SetMysqlTime(t)
+			return ret, errors.Trace(err)
+		}
+		t, err = t.RoundFrac(sc, fsp)
+	case KindString, KindBytes:
+		t, err = ParseTime(sc, d.GetString(), tp, fsp)
+	case KindInt64:
+		t, err = ParseTimeFromNum(sc, d.GetInt64(), tp, fsp)
+	case KindUint64:
+		t, err = ParseTimeFromNum(sc, int64(d.GetUint64()), tp, fsp)
+	case KindFloat32, KindFloat64:
+		t, err = ParseTimeFromFloatString(sc, strconv.FormatFloat(d.GetFloat64(), 'f', -1, 64), tp, fsp)
+	case KindMysqlDecimal:
+		t, err = ParseTimeFromFloatString(sc, d.GetMysqlDecimal().String(), tp, fsp)
+	default:
+		return invalidConv(d, tp)
+	}
+	ret.SetMysqlTime(t)
+	if err != nil {
+		return ret, errors.Trace(err)
+	}
+	return ret, nil
+}
+
+func (d *Datum) convertToMysqlDuration(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	fsp := DefaultFsp
+	if target.Decimal != UnspecifiedLength {
+		fsp = int8(target.Decimal)
+	}
+	var (
+		ret Datum
+		dur Duration
+		err error
+	)
+	switch d.k {
+	case KindMysqlTime:
+		dur, err = d.GetMysqlTime().ConvertToDuration()
+		if err != nil {
+			ret.SetMysqlDuration(ZeroDuration)
+			return ret, errors.Trace(err)
+		}
+		dur, err = dur.RoundFrac(fsp)
+	case KindMysqlDuration:
+		dur, err = d.GetMysqlDuration().RoundFrac(fsp)
+	case KindInt64:
+		dur, err = NumberToDuration(d.GetInt64(), fsp)
+	case KindUint64:
+		dur, err = NumberToDuration(int64(d.GetUint64()), fsp)
+	case KindFloat32, KindFloat64, KindMysqlDecimal:
+		var s string
+		s, err = d.ToString()
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+		dur, err = ParseDuration(sc, s, fsp)
+	case KindString, KindBytes:
+		dur, err = ParseDuration(sc, d.GetString(), fsp)
+	default:
+		return invalidConv(d, target.Tp)
+	}
+	ret.SetMysqlDuration(dur)
+	if err != nil {
+		return ret, errors.Trace(err)
+	}
+	return ret, nil
+}
+
 func (d *Datum) convertToMysqlBit(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
 	var ret Datum
 	var uintValue uint64
@@ -696,6 +1065,38 @@ func (d *Datum) convertToMysqlBit(sc *stmtctx.StatementContext, target *FieldTyp
 	return ret, errors.Trace(err)
 }
 
+func (d *Datum) convertToMysqlJSON(sc *stmtctx.StatementContext, target *FieldType) (ret Datum, err error) {
+	switch d.k {
+	case KindString, KindBytes:
+		var j json.BinaryJSON
+		if j, err = json.ParseBinaryFromString(d.GetString()); err == nil {
+			ret.SetMysqlJSON(j)
+		}
+	case KindInt64:
+		ret.SetMysqlJSON(json.CreateBinary(d.GetInt64()))
+	case KindUint64:
+		ret.SetMysqlJSON(json.CreateBinary(d.GetUint64()))
+	case KindFloat32, KindFloat64:
+		ret.SetMysqlJSON(json.CreateBinary(d.GetFloat64()))
+	case KindMysqlDecimal:
+		var f float64
+		if f, err = d.GetMysqlDecimal().ToFloat64(); err == nil {
+			ret.SetMysqlJSON(json.CreateBinary(f))
+		}
+	case KindMysqlJSON:
+		ret = *d
+	default:
+		var s string
+		if s, err = d.ToString(); err == nil {
+			// TODO: fix precision of MysqlTime. For example,
+			// On MySQL 5.7 CAST(NOW() AS JSON) -> "2011-11-11 11:11:11.111111",
+			// But now we can only return "2011-11-11 11:11:11".
+			ret.SetMysqlJSON(json.CreateBinary(s))
+		}
+	}
+	return ret, errors.Trace(err)
+}
+
 // ToBool converts to a bool.
 // We will use 1 for true, and 0 for false.
 func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
@@ -713,9 +1114,18 @@ func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
 	case KindString, KindBytes:
 		iVal, err1 := StrToInt(sc, d.GetString())
 		isZero, err = iVal == 0, err1
+	case KindMysqlDecimal:
+		isZero = d.GetMysqlDecimal().IsZero()
+	case KindMysqlTime:
+		isZero = d.GetMysqlTime().IsZero()
+	case KindMysqlDuration:
+		isZero = d.GetMysqlDuration().Duration == 0
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err1 := d.GetBinaryLiteral().ToInt(sc)
 		isZero, err = val == 0, err1
+	case KindMysqlJSON:
+		val, err1 := ConvertJSONToFloat(sc, d.GetMysqlJSON())
+		isZero, err = val == 0, err1
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to bool", d.GetValue(), d.GetValue())
 	}
@@ -755,9 +1165,54 @@ func (d *Datum) toSignedInteger(sc *stmtctx.StatementContext, tp byte) (int64, e
 			err = err2
 		}
 		return iVal, errors.Trace(err)
+	case KindMysqlTime:
+		// 2011-11-10 11:11:11.999999 -> 20111110111112
+		// 2011-11-10 11:59:59.999999 -> 20111110120000
+		t, err := d.GetMysqlTime().RoundFrac(sc, DefaultFsp)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		ival, err := t.ToNumber().ToInt()
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil {
+			err = err2
+		}
+		return ival, errors.Trace(err)
+	case KindMysqlDuration:
+		// 11:11:11.999999 -> 111112
+		// 11:59:59.999999 -> 120000
+		dur, err := d.GetMysqlDuration().RoundFrac(DefaultFsp)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		ival, err := dur.ToNumber().ToInt()
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil {
+			err = err2
+		}
+		return ival, errors.Trace(err)
+	case KindMysqlDecimal:
+		var to MyDecimal
+		err := d.GetMysqlDecimal().Round(&to, 0, ModeHalfEven)
+		ival, err1 := to.ToInt()
+		if err == nil {
+			err = err1
+		}
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil {
+			err = err2
+		}
+		return ival, errors.Trace(err)
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err := d.GetBinaryLiteral().ToInt(sc)
 		return int64(val), errors.Trace(err)
+	case KindMysqlJSON:
+		ival, err := ConvertJSONToInt(sc, d.GetMysqlJSON(), false)
+		ival, err2 := ConvertIntToInt(ival, lowerBound, upperBound, tp)
+		if err == nil {
+			err = err2
+		}
+		return ival, errors.Trace(err)
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to int64", d.GetValue(), d.GetValue())
 	}
@@ -778,9 +1233,21 @@ func (d *Datum) ToFloat64(sc *stmtctx.StatementContext) (float64, error) {
 		return StrToFloat(sc, d.GetString())
 	case KindBytes:
 		return StrToFloat(sc, string(d.GetBytes()))
+	case KindMysqlTime:
+		f, err := d.GetMysqlTime().ToNumber().ToFloat64()
+		return f, errors.Trace(err)
+	case KindMysqlDuration:
+		f, err := d.GetMysqlDuration().ToNumber().ToFloat64()
+		return f, errors.Trace(err)
+	case KindMysqlDecimal:
+		f, err := d.GetMysqlDecimal().ToFloat64()
+		return f, errors.Trace(err)
 	case KindBinaryLiteral, KindMysqlBit:
 		val, err := d.GetBinaryLiteral().ToInt(sc)
 		return float64(val), errors.Trace(err)
+	case KindMysqlJSON:
+		f, err := ConvertJSONToFloat(sc, d.GetMysqlJSON())
+		return f, errors.Trace(err)
 	default:
 		return 0, errors.Errorf("cannot convert %v(type %T) to float64", d.GetValue(), d.GetValue())
 	}
@@ -801,8 +1268,16 @@ func (d *Datum) ToString() (string, error) {
 		return d.GetString(), nil
 	case KindBytes:
 		return d.GetString(), nil
+	case KindMysqlTime:
+		return d.GetMysqlTime().String(), nil
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().String(), nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().String(), nil
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.GetBinaryLiteral().ToString(), nil
+	case KindMysqlJSON:
+		return d.GetMysqlJSON().String(), nil
 	default:
 		return "", errors.Errorf("cannot convert %v(type %T) to string", d.GetValue(), d.GetValue())
 	}
@@ -822,6 +1297,48 @@ func (d *Datum) ToBytes() ([]byte, error) {
 	}
 }
 
+// ToMysqlJSON is similar to convertToMysqlJSON, except the
+// latter parses from string, but the former uses it as primitive.
+func (d *Datum) ToMysqlJSON() (j json.BinaryJSON, err error) {
+	var in interface{}
+	switch d.Kind() {
+	case KindMysqlJSON:
+		j = d.GetMysqlJSON()
+		return
+	case KindInt64:
+		in = d.GetInt64()
+	case KindUint64:
+		in = d.GetUint64()
+	case KindFloat32, KindFloat64:
+		in = d.GetFloat64()
+	case KindMysqlDecimal:
+		in, err = d.GetMysqlDecimal().ToFloat64()
+	case KindString, KindBytes:
+		in = d.GetString()
+	case KindBinaryLiteral, KindMysqlBit:
+		in = d.GetBinaryLiteral().ToString()
+	case KindNull:
+		in = nil
+	default:
+		in, err = d.ToString()
+	}
+	if err != nil {
+		err = errors.Trace(err)
+		return
+	}
+	j = json.CreateBinary(in)
+	return
+}
+
+// ToDecimal converts to a decimal.
+func (d *Datum) ToDecimal(sc *stmtctx.StatementContext) (*MyDecimal, error) {
+	converted, err := d.ConvertTo(sc, NewFieldType(mysql.TypeNewDecimal))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return converted.GetMysqlDecimal(), nil
+}
+
 func invalidConv(d *Datum, tp byte) (Datum, error) {
 	return Datum{}, errors.Errorf("cannot convert datum from %s to type %s.", KindStr(d.Kind()), TypeStr(tp))
 }
@@ -873,6 +1390,30 @@ func NewFloat32Datum(f float32) (d Datum) {
 	return d
 }
 
+// NewDecimalDatum creates a new Datum from a MyDecimal value.
+func NewDecimalDatum(dec *MyDecimal) (d Datum) {
+	d.SetMysqlDecimal(dec)
+	return d
+}
+
+// NewTimeDatum creates a new Time from a Time value.
+func NewTimeDatum(t Time) (d Datum) {
+	d.SetMysqlTime(t)
+	return d
+}
+
+// NewDurationDatum creates a new Datum from a Duration value.
+func NewDurationDatum(dur Duration) (d Datum) {
+	d.SetMysqlDuration(dur)
+	return d
+}
+
+// NewJSONDatum creates a new Datum from a BinaryJSON value
+func NewJSONDatum(j json.BinaryJSON) (d Datum) {
+	d.SetMysqlJSON(j)
+	return d
+}
+
 // MakeDatums creates datum slice from interfaces.
 func MakeDatums(args ...interface{}) []Datum {
 	datums := make([]Datum, len(args))
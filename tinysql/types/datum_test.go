@@ -22,6 +22,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types/json"
 )
 
 var _ = Suite(&testDatumSuite{})
@@ -275,6 +276,62 @@ func (ts *testDatumSuite) TestCloneDatum(c *C) {
 	}
 }
 
+func (ts *testDatumSuite) TestJSONDatum(c *C) {
+	sc := new(stmtctx.StatementContext)
+	sc.IgnoreTruncate = true
+	ft := NewFieldType(mysql.TypeJSON)
+
+	d := NewStringDatum(`{"b": [1, 2], "a": null}`)
+	d, err := d.ConvertTo(sc, ft)
+	c.Assert(err, IsNil)
+	c.Assert(d.Kind(), Equals, KindMysqlJSON)
+	str, err := d.ToString()
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, `{"a": null, "b": [1, 2]}`)
+
+	d = NewStringDatum(`{"a": }`)
+	_, err = d.ConvertTo(sc, ft)
+	c.Assert(json.ErrInvalidJSONText.Equal(err), IsTrue)
+
+	d = NewIntDatum(3)
+	d, err = d.ConvertTo(sc, ft)
+	c.Assert(err, IsNil)
+	c.Assert(d.GetMysqlJSON().TypeCode, Equals, json.TypeCodeInt64)
+
+	// A non-JSON datum is compared as a JSON scalar with MySQL precedence.
+	j := NewJSONDatum(json.CreateBinary("abc"))
+	tests := []struct {
+		d   Datum
+		cmp int
+	}{
+		{NewStringDatum("abc"), 0},
+		{NewStringDatum("abd"), -1},
+		{NewIntDatum(1), 1},
+		{NewJSONDatum(json.CreateBinary(true)), -1},
+	}
+	for _, tt := range tests {
+		cmp, err := j.CompareDatum(sc, &tt.d)
+		c.Assert(err, IsNil)
+		c.Assert(cmp, Equals, tt.cmp)
+		cmp, err = tt.d.CompareDatum(sc, &j)
+		c.Assert(err, IsNil)
+		c.Assert(cmp, Equals, -tt.cmp)
+	}
+
+	d = NewJSONDatum(json.CreateBinary("12"))
+	d, err = d.ConvertTo(sc, NewFieldType(mysql.TypeLonglong))
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, int64(12))
+	d = NewJSONDatum(json.CreateBinary(true))
+	f, err := d.ToFloat64(sc)
+	c.Assert(err, IsNil)
+	c.Assert(f, Equals, float64(1))
+
+	cloned := CloneDatum(j)
+	c.Assert(cloned.GetMysqlJSON().String(), Equals, `"abc"`)
+	c.Assert(&cloned.b[0], Not(Equals), &j.b[0])
+}
+
 func prepareCompareDatums() ([]Datum, []Datum) {
 	vals := make([]Datum, 0, 5)
 	vals = append(vals, NewIntDatum(1))
@@ -52,6 +52,18 @@ func IsTypePrefixable(tp byte) bool {
 	return IsTypeBlob(tp) || IsTypeChar(tp)
 }
 
+// IsTypeTime returns a boolean indicating
+// whether the tp is time type like datetime, date or timestamp.
+func IsTypeTime(tp byte) bool {
+	return tp == mysql.TypeDatetime || tp == mysql.TypeDate || tp == mysql.TypeTimestamp
+}
+
+// IsTypeTemporal returns a boolean indicating
+// whether the tp is time type like datetime, date, timestamp or duration.
+func IsTypeTemporal(tp byte) bool {
+	return IsTypeTime(tp) || tp == mysql.TypeDuration
+}
+
 // IsTypeNumeric returns a boolean indicating whether the tp is numeric type.
 func IsTypeNumeric(tp byte) bool {
 	switch tp {
@@ -84,17 +96,21 @@ func IsString(tp byte) bool {
 }
 
 var kind2Str = map[byte]string{
-	KindNull:       "null",
-	KindInt64:      "bigint",
-	KindUint64:     "unsigned bigint",
-	KindFloat32:    "float",
-	KindFloat64:    "double",
-	KindString:     "char",
-	KindBytes:      "bytes",
-	KindInterface:  "interface",
-	KindMinNotNull: "min_not_null",
-	KindMaxValue:   "max_value",
-	KindRaw:        "raw",
+	KindNull:          "null",
+	KindInt64:         "bigint",
+	KindUint64:        "unsigned bigint",
+	KindFloat32:       "float",
+	KindFloat64:       "double",
+	KindString:        "char",
+	KindBytes:         "bytes",
+	KindMysqlDecimal:  "decimal",
+	KindMysqlDuration: "time",
+	KindMysqlTime:     "datetime",
+	KindMysqlJSON:     "json",
+	KindInterface:     "interface",
+	KindMinNotNull:    "min_not_null",
+	KindMaxValue:      "max_value",
+	KindRaw:           "raw",
 }
 
 // TypeStr converts tp to a string.
@@ -109,6 +125,7 @@ func KindStr(kind byte) (r string) {
 // It is used for converting Text to Blob,
 // or converting Char to Binary.
 // Args:
+//
 //	tp: type enum
 //	cs: charset
 var TypeToStr = ast.TypeToStr
@@ -254,6 +254,16 @@ func (s *testTypeEtcSuite) TestIsTypePrefixable(c *C) {
 	c.Assert(res, Equals, true)
 }
 
+func (s *testTypeEtcSuite) TestIsTypeTemporal(c *C) {
+	defer testleak.AfterTest(c)()
+
+	c.Assert(IsTypeTemporal(mysql.TypeDate), Equals, true)
+	c.Assert(IsTypeTemporal(mysql.TypeDatetime), Equals, true)
+	c.Assert(IsTypeTemporal(mysql.TypeTimestamp), Equals, true)
+	c.Assert(IsTypeTemporal(mysql.TypeDuration), Equals, true)
+	c.Assert(IsTypeTemporal(mysql.TypeLonglong), Equals, false)
+}
+
 func (s *testTypeEtcSuite) TestIsTypeNumeric(c *C) {
 	defer testleak.AfterTest(c)()
 
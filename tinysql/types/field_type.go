@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	ast "github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/types/json"
 	utilMath "github.com/pingcap/tidb/util/math"
 )
 
@@ -105,6 +106,8 @@ func mergeEvalType(lhs, rhs EvalType, lft, rft *FieldType, isLHSUnsigned, isRHSU
 		return ETString
 	} else if lhs == ETReal || rhs == ETReal {
 		return ETReal
+	} else if lhs == ETDecimal || rhs == ETDecimal || isLHSUnsigned != isRHSUnsigned {
+		return ETDecimal
 	}
 	return ETInt
 }
@@ -197,6 +200,36 @@ func DefaultTypeForValue(value interface{}, tp *FieldType) {
 		tp.Flen = len(x)
 		tp.Decimal = UnspecifiedLength
 		SetBinChsClnFlag(tp)
+	case *MyDecimal:
+		tp.Tp = mysql.TypeNewDecimal
+		tp.Flen = len(x.ToString())
+		tp.Decimal = int(x.digitsFrac)
+		SetBinChsClnFlag(tp)
+	case Time:
+		tp.Tp = x.Type()
+		switch x.Type() {
+		case mysql.TypeDate:
+			tp.Flen = mysql.MaxDateWidth
+			tp.Decimal = int(MinFsp)
+		case mysql.TypeDatetime, mysql.TypeTimestamp:
+			tp.Flen = mysql.MaxDatetimeWidthNoFsp
+			if x.Fsp() > DefaultFsp { // consider point('.') and the fractional part.
+				tp.Flen += int(x.Fsp()) + 1
+			}
+			tp.Decimal = int(x.Fsp())
+		}
+		SetBinChsClnFlag(tp)
+	case Duration:
+		tp.Tp = mysql.TypeDuration
+		tp.Flen = len(x.String())
+		tp.Decimal = int(x.Fsp)
+		SetBinChsClnFlag(tp)
+	case json.BinaryJSON:
+		tp.Tp = mysql.TypeJSON
+		tp.Flen = UnspecifiedLength
+		tp.Decimal = 0
+		tp.Charset = charset.CharsetUTF8MB4
+		tp.Collate = charset.CollationUTF8MB4
 	default:
 		tp.Tp = mysql.TypeUnspecified
 		tp.Flen = UnspecifiedLength
@@ -0,0 +1,652 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// Type returns type of BinaryJSON as string.
+func (bj BinaryJSON) Type() string {
+	switch bj.TypeCode {
+	case TypeCodeObject:
+		return "OBJECT"
+	case TypeCodeArray:
+		return "ARRAY"
+	case TypeCodeLiteral:
+		switch bj.Value[0] {
+		case LiteralNil:
+			return "NULL"
+		default:
+			return "BOOLEAN"
+		}
+	case TypeCodeInt64:
+		return "INTEGER"
+	case TypeCodeUint64:
+		return "UNSIGNED INTEGER"
+	case TypeCodeFloat64:
+		return "DOUBLE"
+	case TypeCodeString:
+		return "STRING"
+	default:
+		msg := fmt.Sprintf(unknownTypeCodeErrorMsg, bj.TypeCode)
+		panic(msg)
+	}
+}
+
+// Unquote is for JSON_UNQUOTE.
+func (bj BinaryJSON) Unquote() (string, error) {
+	switch bj.TypeCode {
+	case TypeCodeString:
+		return string(bj.GetString()), nil
+	default:
+		return bj.String(), nil
+	}
+}
+
+// UnquoteString remove quotes in a string,
+// including the quotes at the head and tail of string.
+func UnquoteString(str string) (string, error) {
+	strLen := len(str)
+	if strLen < 2 {
+		return str, nil
+	}
+	head, tail := str[0], str[strLen-1]
+	if head == '"' && tail == '"' {
+		// Remove prefix and suffix '"' before unquoting
+		return unquoteString(str[1 : strLen-1])
+	}
+	// if value is not double quoted, do nothing
+	return str, nil
+}
+
+// unquoteString recognizes the escape sequences shown in:
+// https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#json-unquote-character-escape-sequences
+func unquoteString(s string) (string, error) {
+	ret := new(bytes.Buffer)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			if i == len(s) {
+				return "", errors.New("Missing a closing quotation mark in string")
+			}
+			switch s[i] {
+			case '"':
+				ret.WriteByte('"')
+			case 'b':
+				ret.WriteByte('\b')
+			case 'f':
+				ret.WriteByte('\f')
+			case 'n':
+				ret.WriteByte('\n')
+			case 'r':
+				ret.WriteByte('\r')
+			case 't':
+				ret.WriteByte('\t')
+			case '\\':
+				ret.WriteByte('\\')
+			case 'u':
+				if i+4 > len(s) {
+					return "", errors.Errorf("Invalid unicode: %s", s[i+1:])
+				}
+				char, size, err := decodeEscapedUnicode(hack.Slice(s[i+1 : i+5]))
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				ret.Write(char[0:size])
+				i += 4
+			default:
+				// For all other escape sequences, backslash is ignored.
+				ret.WriteByte(s[i])
+			}
+		} else {
+			ret.WriteByte(s[i])
+		}
+	}
+	return ret.String(), nil
+}
+
+// decodeEscapedUnicode decodes unicode into utf8 bytes specified in RFC 3629.
+// According RFC 3629, the max length of utf8 characters is 4 bytes.
+// And MySQL use 4 bytes to represent the unicode which must be in [0, 65536).
+func decodeEscapedUnicode(s []byte) (char [4]byte, size int, err error) {
+	size, err = hex.Decode(char[0:2], s)
+	if err != nil || size != 2 {
+		// The unicode must can be represented in 2 bytes.
+		return char, 0, errors.Trace(err)
+	}
+	var unicode uint16
+	err = binary.Read(bytes.NewReader(char[0:2]), binary.BigEndian, &unicode)
+	if err != nil {
+		return char, 0, errors.Trace(err)
+	}
+	size = utf8.RuneLen(rune(unicode))
+	utf8.EncodeRune(char[0:size], rune(unicode))
+	return
+}
+
+// Extract receives several path expressions as arguments, matches them in bj, and returns:
+//
+//	ret: target JSON matched any path expressions. maybe autowrapped as an array.
+//	found: true if any path expressions matched.
+func (bj BinaryJSON) Extract(pathExprList []PathExpression) (ret BinaryJSON, found bool) {
+	buf := make([]BinaryJSON, 0, 1)
+	for _, pathExpr := range pathExprList {
+		buf = bj.extractTo(buf, pathExpr)
+	}
+	if len(buf) == 0 {
+		found = false
+	} else if len(pathExprList) == 1 && !pathExprList[0].ContainsAnyAsterisk() {
+		// A path expression without asterisks matches one value at most,
+		// the value is returned without being wrapped into an array.
+		found = true
+		ret = buf[0]
+	} else {
+		found = true
+		ret = buildBinaryArray(buf)
+	}
+	return
+}
+
+func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		return append(buf, bj)
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	if currentLeg.typ == pathLegIndex {
+		if bj.TypeCode != TypeCodeArray {
+			if currentLeg.arrayIndex <= 0 && currentLeg.arrayIndex != arrayIndexAsterisk {
+				buf = bj.extractTo(buf, subPathExpr)
+			}
+			return buf
+		}
+		elemCount := bj.GetElemCount()
+		if currentLeg.arrayIndex == arrayIndexAsterisk {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
+			}
+		} else if currentLeg.arrayIndex < elemCount {
+			buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
+		}
+	} else if currentLeg.typ == pathLegKey && bj.TypeCode == TypeCodeObject {
+		elemCount := bj.GetElemCount()
+		if currentLeg.dotKey == "*" {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, subPathExpr)
+			}
+		} else {
+			child, ok := bj.objectSearchKey(hack.Slice(currentLeg.dotKey))
+			if ok {
+				buf = child.extractTo(buf, subPathExpr)
+			}
+		}
+	} else if currentLeg.typ == pathLegDoubleAsterisk {
+		buf = bj.extractTo(buf, subPathExpr)
+		if bj.TypeCode == TypeCodeArray {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, pathExpr)
+			}
+		} else if bj.TypeCode == TypeCodeObject {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, pathExpr)
+			}
+		}
+	}
+	return buf
+}
+
+func (bj BinaryJSON) objectSearchKey(key []byte) (BinaryJSON, bool) {
+	elemCount := bj.GetElemCount()
+	idx := sort.Search(elemCount, func(i int) bool {
+		return bytes.Compare(bj.objectGetKey(i), key) >= 0
+	})
+	if idx < elemCount && bytes.Equal(bj.objectGetKey(idx), key) {
+		return bj.objectGetVal(idx), true
+	}
+	return BinaryJSON{}, false
+}
+
+func buildBinaryArray(elems []BinaryJSON) BinaryJSON {
+	totalSize := headerSize + len(elems)*valEntrySize
+	for _, elem := range elems {
+		if elem.TypeCode != TypeCodeLiteral {
+			totalSize += len(elem.Value)
+		}
+	}
+	buf := make([]byte, headerSize+len(elems)*valEntrySize, totalSize)
+	jsonEndian.PutUint32(buf, uint32(len(elems)))
+	jsonEndian.PutUint32(buf[dataSizeOff:], uint32(totalSize))
+	buf = buildBinaryElements(buf, headerSize, elems)
+	return BinaryJSON{TypeCode: TypeCodeArray, Value: buf}
+}
+
+func buildBinaryElements(buf []byte, entryStart int, elems []BinaryJSON) []byte {
+	for i, elem := range elems {
+		buf[entryStart+i*valEntrySize] = elem.TypeCode
+		if elem.TypeCode == TypeCodeLiteral {
+			buf[entryStart+i*valEntrySize+valTypeSize] = elem.Value[0]
+		} else {
+			jsonEndian.PutUint32(buf[entryStart+i*valEntrySize+valTypeSize:], uint32(len(buf)))
+			buf = append(buf, elem.Value...)
+		}
+	}
+	return buf
+}
+
+func buildBinaryObject(keys [][]byte, elems []BinaryJSON) BinaryJSON {
+	totalSize := headerSize + len(elems)*(keyEntrySize+valEntrySize)
+	for i, elem := range elems {
+		if elem.TypeCode != TypeCodeLiteral {
+			totalSize += len(elem.Value)
+		}
+		totalSize += len(keys[i])
+	}
+	buf := make([]byte, headerSize+len(elems)*(keyEntrySize+valEntrySize), totalSize)
+	jsonEndian.PutUint32(buf, uint32(len(elems)))
+	jsonEndian.PutUint32(buf[dataSizeOff:], uint32(totalSize))
+	for i, key := range keys {
+		jsonEndian.PutUint32(buf[headerSize+i*keyEntrySize:], uint32(len(buf)))
+		jsonEndian.PutUint16(buf[headerSize+i*keyEntrySize+keyLenOff:], uint16(len(key)))
+		buf = append(buf, key...)
+	}
+	entryStart := headerSize + len(elems)*keyEntrySize
+	buf = buildBinaryElements(buf, entryStart, elems)
+	return BinaryJSON{TypeCode: TypeCodeObject, Value: buf}
+}
+
+// Modify modifies a JSON object by insert, replace or set.
+// All path expressions cannot contain * or ** wildcard.
+// If any error occurs, the input won't be changed.
+func (bj BinaryJSON) Modify(pathExprList []PathExpression, values []BinaryJSON, mt ModifyType) (retj BinaryJSON, err error) {
+	if len(pathExprList) != len(values) {
+		return retj, errors.New("Incorrect parameter count")
+	}
+	for _, pathExpr := range pathExprList {
+		if pathExpr.flags.containsAnyAsterisk() {
+			return retj, ErrInvalidJSONPathWildcard
+		}
+	}
+	for i := 0; i < len(pathExprList); i++ {
+		pathExpr, value := pathExprList[i], values[i]
+		modifier := &binaryModifier{bj: bj}
+		switch mt {
+		case ModifyInsert:
+			bj = modifier.insert(pathExpr, value)
+		case ModifyReplace:
+			bj = modifier.replace(pathExpr, value)
+		case ModifySet:
+			bj = modifier.set(pathExpr, value)
+		}
+	}
+	return bj, nil
+}
+
+// binaryModifier rebuilds a BinaryJSON with one value modified. The value is
+// located by its offset in the original document, then every container on the
+// way to it is rebuilt.
+type binaryModifier struct {
+	bj          BinaryJSON
+	modifyPtr   *byte
+	modifyValue BinaryJSON
+}
+
+func (bm *binaryModifier) set(path PathExpression, newBj BinaryJSON) BinaryJSON {
+	result := make([]BinaryJSON, 0, 1)
+	result = bm.bj.extractTo(result, path)
+	if len(result) > 0 {
+		bm.modifyPtr = &result[0].Value[0]
+		bm.modifyValue = newBj
+		return bm.rebuild()
+	}
+	bm.doInsert(path, newBj)
+	return bm.rebuild()
+}
+
+func (bm *binaryModifier) replace(path PathExpression, newBj BinaryJSON) BinaryJSON {
+	result := make([]BinaryJSON, 0, 1)
+	result = bm.bj.extractTo(result, path)
+	if len(result) == 0 {
+		return bm.bj
+	}
+	bm.modifyPtr = &result[0].Value[0]
+	bm.modifyValue = newBj
+	return bm.rebuild()
+}
+
+func (bm *binaryModifier) insert(path PathExpression, newBj BinaryJSON) BinaryJSON {
+	result := make([]BinaryJSON, 0, 1)
+	result = bm.bj.extractTo(result, path)
+	if len(result) > 0 {
+		return bm.bj
+	}
+	bm.doInsert(path, newBj)
+	return bm.rebuild()
+}
+
+// doInsert inserts the newBj to its parent, and builds the new parent.
+func (bm *binaryModifier) doInsert(path PathExpression, newBj BinaryJSON) {
+	parentPath, lastLeg := path.popOneLastLeg()
+	result := make([]BinaryJSON, 0, 1)
+	result = bm.bj.extractTo(result, parentPath)
+	if len(result) == 0 {
+		return
+	}
+	parentBj := result[0]
+	if lastLeg.typ == pathLegIndex {
+		bm.modifyPtr = &parentBj.Value[0]
+		if parentBj.TypeCode != TypeCodeArray {
+			bm.modifyValue = buildBinaryArray([]BinaryJSON{parentBj, newBj})
+			return
+		}
+		elemCount := parentBj.GetElemCount()
+		elems := make([]BinaryJSON, 0, elemCount+1)
+		for i := 0; i < elemCount; i++ {
+			elems = append(elems, parentBj.arrayGetElem(i))
+		}
+		elems = append(elems, newBj)
+		bm.modifyValue = buildBinaryArray(elems)
+		return
+	}
+	if parentBj.TypeCode != TypeCodeObject {
+		return
+	}
+	bm.modifyPtr = &parentBj.Value[0]
+	elemCount := parentBj.GetElemCount()
+	insertKey := hack.Slice(lastLeg.dotKey)
+	insertIdx := sort.Search(elemCount, func(i int) bool {
+		return bytes.Compare(parentBj.objectGetKey(i), insertKey) >= 0
+	})
+	keys := make([][]byte, 0, elemCount+1)
+	elems := make([]BinaryJSON, 0, elemCount+1)
+	for i := 0; i < elemCount; i++ {
+		if i == insertIdx {
+			keys = append(keys, insertKey)
+			elems = append(elems, newBj)
+		}
+		keys = append(keys, parentBj.objectGetKey(i))
+		elems = append(elems, parentBj.objectGetVal(i))
+	}
+	if insertIdx == elemCount {
+		keys = append(keys, insertKey)
+		elems = append(elems, newBj)
+	}
+	bm.modifyValue = buildBinaryObject(keys, elems)
+}
+
+func (bm *binaryModifier) rebuild() BinaryJSON {
+	buf := make([]byte, 0, len(bm.bj.Value)+len(bm.modifyValue.Value))
+	value, tpCode := bm.rebuildTo(buf)
+	return BinaryJSON{TypeCode: tpCode, Value: value}
+}
+
+func (bm *binaryModifier) rebuildTo(buf []byte) ([]byte, TypeCode) {
+	if bm.modifyPtr == &bm.bj.Value[0] {
+		bm.modifyPtr = nil
+		return append(buf, bm.modifyValue.Value...), bm.modifyValue.TypeCode
+	} else if bm.modifyPtr == nil {
+		return append(buf, bm.bj.Value...), bm.bj.TypeCode
+	}
+	bj := bm.bj
+	switch bj.TypeCode {
+	case TypeCodeLiteral, TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64, TypeCodeString:
+		return append(buf, bj.Value...), bj.TypeCode
+	}
+	docOff := len(buf)
+	elemCount := bj.GetElemCount()
+	var valEntryStart int
+	if bj.TypeCode == TypeCodeArray {
+		copySize := headerSize + elemCount*valEntrySize
+		valEntryStart = headerSize
+		buf = append(buf, bj.Value[:copySize]...)
+	} else {
+		copySize := headerSize + elemCount*(keyEntrySize+valEntrySize)
+		valEntryStart = headerSize + elemCount*keyEntrySize
+		buf = append(buf, bj.Value[:copySize]...)
+		if elemCount > 0 {
+			firstKeyOff := int(jsonEndian.Uint32(bj.Value[headerSize:]))
+			lastKeyOff := int(jsonEndian.Uint32(bj.Value[headerSize+(elemCount-1)*keyEntrySize:]))
+			lastKeyLen := int(jsonEndian.Uint16(bj.Value[headerSize+(elemCount-1)*keyEntrySize+keyLenOff:]))
+			buf = append(buf, bj.Value[firstKeyOff:lastKeyOff+lastKeyLen]...)
+		}
+	}
+	for i := 0; i < elemCount; i++ {
+		valEntryOff := valEntryStart + i*valEntrySize
+		elem := bj.valEntryGet(valEntryOff)
+		bm.bj = elem
+		var tpCode TypeCode
+		valOff := len(buf) - docOff
+		buf, tpCode = bm.rebuildTo(buf)
+		buf[docOff+valEntryOff] = tpCode
+		if tpCode == TypeCodeLiteral {
+			lastIdx := len(buf) - 1
+			jsonEndian.PutUint32(buf[docOff+valEntryOff+valTypeSize:], uint32(buf[lastIdx]))
+			buf = buf[:lastIdx]
+		} else {
+			jsonEndian.PutUint32(buf[docOff+valEntryOff+valTypeSize:], uint32(valOff))
+		}
+	}
+	jsonEndian.PutUint32(buf[docOff+dataSizeOff:], uint32(len(buf)-docOff))
+	return buf, bj.TypeCode
+}
+
+// popOneLastLeg returns the parent PathExpression and the last pathLeg.
+func (pe PathExpression) popOneLastLeg() (PathExpression, pathLeg) {
+	lastLegIdx := len(pe.legs) - 1
+	lastLeg := pe.legs[lastLegIdx]
+	// It is used only in modification, it has been checked that there is no asterisks.
+	return PathExpression{legs: pe.legs[:lastLegIdx]}, lastLeg
+}
+
+// ContainsBinary checks whether JSON document target is contained in obj,
+// it follows the rules of JSON_CONTAINS in MySQL:
+//  1. A scalar is contained in a target scalar if and only if they are
+//     comparable and are equal.
+//  2. A candidate array is contained in a target array if and only if every
+//     element in the candidate is contained in some element of the target.
+//  3. A candidate nonarray is contained in a target array if and only if the
+//     candidate is contained in some element of the target.
+//  4. A candidate object is contained in a target object if and only if for
+//     each key in the candidate there is a key with the same name in the target
+//     and the value associated with the candidate key is contained in the value
+//     associated with the target key.
+func ContainsBinary(obj, target BinaryJSON) bool {
+	switch obj.TypeCode {
+	case TypeCodeObject:
+		if target.TypeCode == TypeCodeObject {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				key := target.objectGetKey(i)
+				val := target.objectGetVal(i)
+				if exp, exists := obj.objectSearchKey(key); !exists || !ContainsBinary(exp, val) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	case TypeCodeArray:
+		if target.TypeCode == TypeCodeArray {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				if !ContainsBinary(obj, target.arrayGetElem(i)) {
+					return false
+				}
+			}
+			return true
+		}
+		elemCount := obj.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			if ContainsBinary(obj.arrayGetElem(i), target) {
+				return true
+			}
+		}
+		return false
+	default:
+		return CompareBinary(obj, target) == 0
+	}
+}
+
+// jsonTypePrecedences is for comparing two JSON values of different types.
+// The precedence order follows
+// https://dev.mysql.com/doc/refman/5.7/en/json.html#json-comparison
+var jsonTypePrecedences = map[string]int{
+	"BLOB":             -1,
+	"BIT":              -2,
+	"OPAQUE":           -3,
+	"DATETIME":         -4,
+	"TIME":             -5,
+	"DATE":             -6,
+	"BOOLEAN":          -7,
+	"ARRAY":            -8,
+	"OBJECT":           -9,
+	"STRING":           -10,
+	"INTEGER":          -11,
+	"UNSIGNED INTEGER": -11,
+	"DOUBLE":           -11,
+	"NULL":             -12,
+}
+
+func getJSONTypePrecedence(bj BinaryJSON) int {
+	return jsonTypePrecedences[bj.Type()]
+}
+
+// CompareBinary compares two binary json objects. Returns -1 if left < right,
+// 0 if left == right, else returns 1.
+func CompareBinary(left, right BinaryJSON) int {
+	precedence1 := getJSONTypePrecedence(left)
+	precedence2 := getJSONTypePrecedence(right)
+	var cmp int
+	if precedence1 == precedence2 {
+		switch left.TypeCode {
+		case TypeCodeLiteral:
+			// false is less than true.
+			cmp = int(right.Value[0]) - int(left.Value[0])
+		case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+			cmp = compareBinaryNumber(left, right)
+		case TypeCodeString:
+			cmp = bytes.Compare(left.GetString(), right.GetString())
+		case TypeCodeArray:
+			leftCount := left.GetElemCount()
+			rightCount := right.GetElemCount()
+			for i := 0; i < leftCount && i < rightCount; i++ {
+				elem1 := left.arrayGetElem(i)
+				elem2 := right.arrayGetElem(i)
+				cmp = CompareBinary(elem1, elem2)
+				if cmp != 0 {
+					return cmp
+				}
+			}
+			cmp = leftCount - rightCount
+		case TypeCodeObject:
+			// only equal is defined on two json objects.
+			// larger and smaller are not defined.
+			cmp = bytes.Compare(left.Value, right.Value)
+		}
+	} else {
+		cmp = precedence1 - precedence2
+	}
+	if cmp > 0 {
+		return 1
+	} else if cmp < 0 {
+		return -1
+	}
+	return 0
+}
+
+func compareBinaryNumber(left, right BinaryJSON) int {
+	if left.TypeCode == right.TypeCode {
+		switch left.TypeCode {
+		case TypeCodeInt64:
+			return compareInt64(left.GetInt64(), right.GetInt64())
+		case TypeCodeUint64:
+			return compareUint64(left.GetUint64(), right.GetUint64())
+		}
+	}
+	return compareFloat64(getFloat64(left), getFloat64(right))
+}
+
+func getFloat64(bj BinaryJSON) float64 {
+	switch bj.TypeCode {
+	case TypeCodeInt64:
+		return float64(bj.GetInt64())
+	case TypeCodeUint64:
+		return float64(bj.GetUint64())
+	}
+	return bj.GetFloat64()
+}
+
+func compareInt64(x, y int64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareUint64(x, y uint64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareFloat64(x, y float64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+// PeekBytesAsJSON trys to peek some bytes from b, until
+// we can deserialize a JSON from those bytes.
+func PeekBytesAsJSON(b []byte) (n int, err error) {
+	if len(b) <= 0 {
+		err = errors.New("Cant peek from empty bytes")
+		return
+	}
+	switch c := TypeCode(b[0]); c {
+	case TypeCodeObject, TypeCodeArray:
+		if len(b) >= valTypeSize+headerSize {
+			size := jsonEndian.Uint32(b[valTypeSize+dataSizeOff:])
+			n = valTypeSize + int(size)
+			return
+		}
+	case TypeCodeString:
+		strLen, lenLen := binary.Uvarint(b[valTypeSize:])
+		return valTypeSize + int(strLen) + lenLen, nil
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		n = valTypeSize + 8
+		return
+	case TypeCodeLiteral:
+		n = valTypeSize + 1
+		return
+	}
+	err = errors.New("Invalid JSON bytes")
+	return
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func mustParsePaths(c *C, paths ...string) []PathExpression {
+	pathExprList := make([]PathExpression, 0, len(paths))
+	for _, path := range paths {
+		pe, err := ParseJSONPathExpr(path)
+		c.Assert(err, IsNil)
+		pathExprList = append(pathExprList, pe)
+	}
+	return pathExprList
+}
+
+func (s *testJSONSuite) TestBinaryJSONExtract(c *C) {
+	defer testleak.AfterTest(c)()
+	bj := mustParseBinaryFromString(c, `{"a": [1, "2", {"aa": "bb"}, 4, null], "b": true, "c": ["d"], "\"hello\"": "world"}`)
+	var tests = []struct {
+		paths    []string
+		expected string
+		found    bool
+	}{
+		{[]string{"$"}, bj.String(), true},
+		{[]string{"$.a"}, `[1, "2", {"aa": "bb"}, 4, null]`, true},
+		{[]string{"$.a[2].aa"}, `"bb"`, true},
+		{[]string{"$.a[4]"}, `null`, true},
+		{[]string{"$.a[5]"}, ``, false},
+		{[]string{"$.b[0]"}, `true`, true},
+		{[]string{"$.b[1]"}, ``, false},
+		{[]string{"$.a[*]"}, `[1, "2", {"aa": "bb"}, 4, null]`, true},
+		{[]string{"$.*"}, `["world", [1, "2", {"aa": "bb"}, 4, null], true, ["d"]]`, true},
+		{[]string{"$**.aa"}, `["bb"]`, true},
+		{[]string{`$."\"hello\""`}, `"world"`, true},
+		{[]string{"$.a[0]", "$.c"}, `[1, ["d"]]`, true},
+		{[]string{"$.z", "$.y"}, ``, false},
+	}
+	for _, tt := range tests {
+		ret, found := bj.Extract(mustParsePaths(c, tt.paths...))
+		c.Assert(found, Equals, tt.found, Commentf("%v", tt.paths))
+		if found {
+			c.Assert(ret.String(), Equals, tt.expected, Commentf("%v", tt.paths))
+		}
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONModify(c *C) {
+	defer testleak.AfterTest(c)()
+	var tests = []struct {
+		base     string
+		path     string
+		value    string
+		mt       ModifyType
+		expected string
+	}{
+		{`null`, "$", `{}`, ModifySet, `{}`},
+		{`{}`, "$.a", `3`, ModifySet, `{"a": 3}`},
+		{`{"a": 3}`, "$.a", `[]`, ModifyReplace, `{"a": []}`},
+		{`{"a": 3}`, "$.b", `"3"`, ModifySet, `{"a": 3, "b": "3"}`},
+		{`{"a": []}`, "$.a[0]", `3`, ModifySet, `{"a": [3]}`},
+		{`{"a": [3]}`, "$.a[1]", `4`, ModifyInsert, `{"a": [3, 4]}`},
+		{`{"a": [3]}`, "$[0]", `4`, ModifySet, `4`},
+		{`{"a": [3]}`, "$[1]", `4`, ModifySet, `[{"a": [3]}, 4]`},
+		{`{"b": true}`, "$.b", `false`, ModifySet, `{"b": false}`},
+		{`{"a": 1}`, "$.a", `2`, ModifyInsert, `{"a": 1}`},
+		{`{"a": 1}`, "$.b", `2`, ModifyReplace, `{"a": 1}`},
+		{`{"a": 1}`, "$.b.c", `2`, ModifySet, `{"a": 1}`},
+	}
+	for _, tt := range tests {
+		base := mustParseBinaryFromString(c, tt.base)
+		value := mustParseBinaryFromString(c, tt.value)
+		ret, err := base.Modify(mustParsePaths(c, tt.path), []BinaryJSON{value}, tt.mt)
+		c.Assert(err, IsNil)
+		c.Assert(ret.String(), Equals, tt.expected, Commentf("%v", tt))
+		// The result must be a valid document.
+		c.Assert(CompareBinary(ret, mustParseBinaryFromString(c, ret.String())), Equals, 0)
+	}
+
+	base := mustParseBinaryFromString(c, `{"a": [1]}`)
+	_, err := base.Modify(mustParsePaths(c, "$.a[*]"), []BinaryJSON{CreateBinary(int64(1))}, ModifySet)
+	c.Assert(ErrInvalidJSONPathWildcard.Equal(err), IsTrue)
+}
+
+func (s *testJSONSuite) TestBinaryJSONUnquote(c *C) {
+	defer testleak.AfterTest(c)()
+	var tests = []struct {
+		j        string
+		unquoted string
+	}{
+		{j: `3`, unquoted: "3"},
+		{j: `"3"`, unquoted: "3"},
+		{j: `"a\"b"`, unquoted: `a"b`},
+		{j: `"é"`, unquoted: "é"},
+		{j: `true`, unquoted: "true"},
+		{j: `null`, unquoted: "null"},
+		{j: `{"a": [1, 2]}`, unquoted: `{"a": [1, 2]}`},
+	}
+	for _, tt := range tests {
+		unquoted, err := mustParseBinaryFromString(c, tt.j).Unquote()
+		c.Assert(err, IsNil)
+		c.Assert(unquoted, Equals, tt.unquoted)
+	}
+
+	str, err := UnquoteString(`"a\tbé"`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "a\tbé")
+	str, err = UnquoteString(`abc`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "abc")
+}
+
+func (s *testJSONSuite) TestCompareBinary(c *C) {
+	defer testleak.AfterTest(c)()
+	var tests = []struct {
+		left  string
+		right string
+		cmp   int
+	}{
+		{`null`, `null`, 0},
+		{`null`, `1`, -1},
+		{`1`, `"1"`, -1},
+		{`"1"`, `{}`, -1},
+		{`{}`, `[]`, -1},
+		{`[]`, `false`, -1},
+		{`false`, `true`, -1},
+		{`true`, `true`, 0},
+		{`1`, `1.0`, 0},
+		{`1`, `1.5`, -1},
+		{`-1`, `18446744073709551615`, -1},
+		{`9223372036854775807`, `9223372036854775806`, 1},
+		{`"a"`, `"b"`, -1},
+		{`[1, 2]`, `[1, 2, 3]`, -1},
+		{`[1, 3]`, `[1, 2, 3]`, 1},
+		{`{"a": 1}`, `{"a": 1}`, 0},
+	}
+	for _, tt := range tests {
+		left := mustParseBinaryFromString(c, tt.left)
+		right := mustParseBinaryFromString(c, tt.right)
+		c.Assert(CompareBinary(left, right), Equals, tt.cmp, Commentf("%s vs %s", tt.left, tt.right))
+		c.Assert(CompareBinary(right, left), Equals, -tt.cmp, Commentf("%s vs %s", tt.right, tt.left))
+	}
+}
+
+func (s *testJSONSuite) TestContainsBinary(c *C) {
+	defer testleak.AfterTest(c)()
+	bj := mustParseBinaryFromString(c, `{"a": {"c": 1, "d": [1, 2]}, "b": [2, "x", {"e": null}]}`)
+	var tests = []struct {
+		target   string
+		expected bool
+	}{
+		{`{"a": {"c": 1}}`, true},
+		{`{"a": {"c": 2}}`, false},
+		{`{"a": {"d": 2}}`, true},
+		{`{"a": {"d": [2, 1]}}`, true},
+		{`{"b": ["x"]}`, true},
+		{`{"b": [{"e": null}]}`, true},
+		{`{"z": 1}`, false},
+		{`1`, false},
+	}
+	for _, tt := range tests {
+		c.Assert(ContainsBinary(bj, mustParseBinaryFromString(c, tt.target)), Equals, tt.expected, Commentf("%s", tt.target))
+	}
+	arr := mustParseBinaryFromString(c, `[1, 2, [3]]`)
+	c.Assert(ContainsBinary(arr, CreateBinary(int64(2))), IsTrue)
+	c.Assert(ContainsBinary(arr, CreateBinary(int64(3))), IsTrue)
+	c.Assert(ContainsBinary(arr, CreateBinary(int64(4))), IsFalse)
+	c.Assert(ContainsBinary(arr, mustParseBinaryFromString(c, `[[3], 1]`)), IsTrue)
+}
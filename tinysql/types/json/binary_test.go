@@ -0,0 +1,102 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testJSONSuite{})
+
+type testJSONSuite struct{}
+
+func mustParseBinaryFromString(c *C, s string) BinaryJSON {
+	bj, err := ParseBinaryFromString(s)
+	c.Assert(err, IsNil)
+	return bj
+}
+
+func (s *testJSONSuite) TestBinaryJSONMarshalUnmarshal(c *C) {
+	defer testleak.AfterTest(c)()
+	strs := []string{
+		`{"a": [1, "2", {"aa": "bb"}, 4, null], "b": true, "c": null}`,
+		`{"aaaaaaaaaaa": [1, "2", {"aa": "bb"}, 4.1], "bbbbbbbbbb": true, "ccccccccc": "d"}`,
+		`[{"a": 1, "b": true}, 3, 3.5, "hello, world", null, true]`,
+		`"é\n\t"`,
+		`18446744073709551615`,
+		`-9223372036854775808`,
+		`0.000001`,
+		`1e+21`,
+		`[]`,
+		`{}`,
+	}
+	for _, str := range strs {
+		parsedBJ := mustParseBinaryFromString(c, str)
+		c.Assert(parsedBJ.String(), Equals, mustParseBinaryFromString(c, parsedBJ.String()).String())
+	}
+	c.Assert(mustParseBinaryFromString(c, `{"b": 1, "a": 2}`).String(), Equals, `{"a": 2, "b": 1}`)
+	c.Assert(mustParseBinaryFromString(c, `"é\n\t"`).String(), Equals, "\"é\\n\\t\"")
+	c.Assert(mustParseBinaryFromString(c, `18446744073709551615`).TypeCode, Equals, TypeCodeUint64)
+	c.Assert(mustParseBinaryFromString(c, `-1`).TypeCode, Equals, TypeCodeInt64)
+	c.Assert(mustParseBinaryFromString(c, `1.0`).TypeCode, Equals, TypeCodeFloat64)
+	c.Assert(mustParseBinaryFromString(c, `1e+21`).String(), Equals, `1e+21`)
+}
+
+func (s *testJSONSuite) TestBinaryJSONParseError(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, str := range []string{``, `{`, `[1, 2`, `{"a": 1} 2`, `abc`} {
+		_, err := ParseBinaryFromString(str)
+		c.Assert(ErrInvalidJSONText.Equal(err), IsTrue, Commentf("%s", str))
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONType(c *C) {
+	defer testleak.AfterTest(c)()
+	var tests = []struct {
+		In  string
+		Out string
+	}{
+		{`{"a": "b"}`, "OBJECT"},
+		{`["a", "b"]`, "ARRAY"},
+		{`3`, "INTEGER"},
+		{`18446744073709551615`, "UNSIGNED INTEGER"},
+		{`3.0`, "DOUBLE"},
+		{`null`, "NULL"},
+		{`true`, "BOOLEAN"},
+		{`"abc"`, "STRING"},
+	}
+	for _, tt := range tests {
+		c.Assert(mustParseBinaryFromString(c, tt.In).Type(), Equals, tt.Out)
+	}
+}
+
+func (s *testJSONSuite) TestPeekBytesAsJSON(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, str := range []string{`{"a": [1, 2]}`, `[1, "x"]`, `"abc"`, `1`, `1.5`, `null`} {
+		bj := mustParseBinaryFromString(c, str)
+		buf := append([]byte{bj.TypeCode}, bj.Value...)
+		n, err := PeekBytesAsJSON(append(buf, 0xff, 0xff))
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, len(buf))
+	}
+	_, err := PeekBytesAsJSON(nil)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func (s *testJSONSuite) TestParseJSONPathExpr(c *C) {
+	defer testleak.AfterTest(c)()
+	var tests = []struct {
+		exprString  string
+		legs        int
+		anyAsterisk bool
+	}{
+		{"$", 0, false},
+		{"  $  ", 0, false},
+		{"$.a", 1, false},
+		{"$ . a [ 1 ]", 2, false},
+		{`$."hello world"`, 1, false},
+		{"$.a[*]", 2, true},
+		{"$.*", 1, true},
+		{"$**.a", 2, true},
+		{"$[0][1].b", 3, false},
+	}
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.exprString)
+		c.Assert(err, IsNil, Commentf("%s", tt.exprString))
+		c.Assert(len(pe.legs), Equals, tt.legs, Commentf("%s", tt.exprString))
+		c.Assert(pe.ContainsAnyAsterisk(), Equals, tt.anyAsterisk, Commentf("%s", tt.exprString))
+	}
+
+	pe, err := ParseJSONPathExpr(`$."a\"b".c`)
+	c.Assert(err, IsNil)
+	c.Assert(pe.legs[0].dotKey, Equals, `a"b`)
+	c.Assert(pe.legs[1].dotKey, Equals, "c")
+}
+
+func (s *testJSONSuite) TestParseJSONPathExprError(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, exprString := range []string{"", "a", "x$", "$.", "$.a**", "$[a]", "$.a b", "$[-1]", "$.1a"} {
+		_, err := ParseJSONPathExpr(exprString)
+		c.Assert(err, NotNil, Commentf("%s", exprString))
+	}
+}
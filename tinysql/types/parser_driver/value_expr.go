@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/hack"
 )
 
 // The purpose of driver package is to decompose the dependency of the parser and
@@ -38,9 +39,14 @@ import (
 
 func init() {
 	ast.NewValueExpr = newValueExpr
+	ast.NewParamMarkerExpr = newParamMarkerExpr
 	ast.NewDecimal = func(str string) (interface{}, error) {
-		n, err := strconv.ParseFloat(str, 64)
-		return n, err
+		dec := new(types.MyDecimal)
+		err := dec.FromString(hack.Slice(str))
+		if err == types.ErrTruncated {
+			err = nil
+		}
+		return dec, err
 	}
 	ast.NewHexLiteral = func(str string) (interface{}, error) {
 		h, err := types.NewHexLiteral(str)
@@ -53,7 +59,8 @@ func init() {
 }
 
 var (
-	_ ast.ValueExpr = &ValueExpr{}
+	_ ast.ParamMarkerExpr = &ParamMarkerExpr{}
+	_ ast.ValueExpr       = &ValueExpr{}
 )
 
 // ValueExpr is the simple value expression.
@@ -90,6 +97,8 @@ func (n *ValueExpr) Format(w io.Writer) {
 		s = strconv.FormatFloat(n.GetFloat64(), 'e', -1, 32)
 	case types.KindFloat64:
 		s = strconv.FormatFloat(n.GetFloat64(), 'e', -1, 64)
+	case types.KindMysqlDecimal:
+		s = n.GetMysqlDecimal().String()
 	case types.KindString, types.KindBytes:
 		s = strconv.Quote(n.GetString())
 	default:
@@ -129,3 +138,38 @@ func (n *ValueExpr) Accept(v ast.Visitor) (ast.Node, bool) {
 	n = newNode.(*ValueExpr)
 	return v.Leave(n)
 }
+
+// ParamMarkerExpr expression holds a place for another expression.
+// Used in parsing prepare statement.
+type ParamMarkerExpr struct {
+	ValueExpr
+	Offset    int
+	Order     int
+	InExecute bool
+}
+
+func newParamMarkerExpr(offset int) ast.ParamMarkerExpr {
+	return &ParamMarkerExpr{
+		Offset: offset,
+	}
+}
+
+// Format the ExprNode into a Writer.
+func (n *ParamMarkerExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *ParamMarkerExpr) Accept(v ast.Visitor) (ast.Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ParamMarkerExpr)
+	return v.Leave(n)
+}
+
+// SetOrder implements the ast.ParamMarkerExpr interface.
+func (n *ParamMarkerExpr) SetOrder(order int) {
+	n.Order = order
+}
@@ -0,0 +1,1780 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotime "time"
+	"unicode"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+// Time format without fractional seconds precision.
+const (
+	DateFormat = "2006-01-02"
+	TimeFormat = "2006-01-02 15:04:05"
+	// TimeFSPFormat is time format with fractional seconds precision.
+	TimeFSPFormat = "2006-01-02 15:04:05.000000"
+)
+
+const (
+	// ZeroDatetimeStr is the string representation of a zero datetime.
+	ZeroDatetimeStr = "0000-00-00 00:00:00"
+	// ZeroDateStr is the string representation of a zero date.
+	ZeroDateStr = "0000-00-00"
+
+	// TimeMaxHour is the max hour for mysql time type.
+	TimeMaxHour = 838
+	// TimeMaxMinute is the max minute for mysql time type.
+	TimeMaxMinute = 59
+	// TimeMaxSecond is the max second for mysql time type.
+	TimeMaxSecond = 59
+	// TimeMaxValue is the maximum value for mysql time type.
+	TimeMaxValue = TimeMaxHour*10000 + TimeMaxMinute*100 + TimeMaxSecond
+	// TimeMaxValueSeconds is the maximum second value for mysql time type.
+	TimeMaxValueSeconds = TimeMaxHour*3600 + TimeMaxMinute*60 + TimeMaxSecond
+)
+
+const (
+	// MaxTime is the maximum for mysql time type.
+	MaxTime = gotime.Duration(TimeMaxValueSeconds) * gotime.Second
+	// MinTime is the minimum for mysql time type.
+	MinTime = -MaxTime
+)
+
+var (
+	// MinDatetime is the minimum for mysql datetime type.
+	MinDatetime = FromDate(1000, 1, 1, 0, 0, 0, 0)
+	// MaxDatetime is the maximum for mysql datetime type.
+	MaxDatetime = FromDate(9999, 12, 31, 23, 59, 59, 999999)
+
+	// BoundTimezone is the timezone for min and max timestamp.
+	BoundTimezone = gotime.UTC
+	// MinTimestamp is the minimum for mysql timestamp type.
+	MinTimestamp = NewTime(FromDate(1970, 1, 1, 0, 0, 1, 0), mysql.TypeTimestamp, DefaultFsp)
+	// MaxTimestamp is the maximum for mysql timestamp type.
+	MaxTimestamp = NewTime(FromDate(2038, 1, 19, 3, 14, 7, 999999), mysql.TypeTimestamp, DefaultFsp)
+
+	// WeekdayNames lists names of weekdays, which are used in builtin time function `dayname`.
+	WeekdayNames = []string{
+		"Monday",
+		"Tuesday",
+		"Wednesday",
+		"Thursday",
+		"Friday",
+		"Saturday",
+		"Sunday",
+	}
+
+	// MonthNames lists names of months, which are used in builtin time function `monthname`.
+	MonthNames = []string{
+		"January", "February",
+		"March", "April",
+		"May", "June",
+		"July", "August",
+		"September", "October",
+		"November", "December",
+	}
+)
+
+var (
+	// ZeroDuration is the zero value for Duration type.
+	ZeroDuration = Duration{Duration: gotime.Duration(0), Fsp: DefaultFsp}
+
+	// ZeroTime is the zero value for TimeInternal type.
+	ZeroTime = ZeroDatetime
+
+	// ZeroDatetime is the zero value for datetime Time.
+	ZeroDatetime = NewTime(ZeroCoreTime, mysql.TypeDatetime, DefaultFsp)
+
+	// ZeroTimestamp is the zero value for timestamp Time.
+	ZeroTimestamp = NewTime(ZeroCoreTime, mysql.TypeTimestamp, DefaultFsp)
+
+	// ZeroDate is the zero value for date Time.
+	ZeroDate = NewTime(ZeroCoreTime, mysql.TypeDate, DefaultFsp)
+)
+
+// The lowest 4 bits of a Time hold its fsp and type:
+// the lowest bit tells datetime (0) from timestamp (1), the next 3 bits are the fsp.
+// As 7 is not a valid fsp, 0b1110 is used to mark a date.
+const (
+	fspTtBitFieldMask uint64 = 0b1111
+	fspBitFieldMask   uint64 = 0b1110
+	fspBitFieldOffset uint64 = 1
+	ttBitFieldMask    uint64 = 0b1
+
+	ttForDatetime  uint64 = 0
+	ttForTimestamp uint64 = 1
+	fspTtForDate   uint64 = 0b1110
+)
+
+// Time is the struct for handling datetime, timestamp and date.
+type Time struct {
+	coreTime CoreTime
+}
+
+// NewTime constructs time from core time, type and fsp.
+func NewTime(coreTime CoreTime, tp uint8, fsp int8) Time {
+	t := Time{coreTime: CoreTime(uint64(coreTime) & coreTimeBitFieldMask)}
+	t.setFspTt(tp, fsp)
+	return t
+}
+
+func (t *Time) setFspTt(tp uint8, fsp int8) {
+	v := uint64(t.coreTime) & ^fspTtBitFieldMask
+	switch tp {
+	case mysql.TypeDate:
+		v |= fspTtForDate
+	default:
+		if fsp == UnspecifiedFsp {
+			fsp = DefaultFsp
+		}
+		v |= (uint64(fsp) << fspBitFieldOffset) & fspBitFieldMask
+		if tp == mysql.TypeTimestamp {
+			v |= ttForTimestamp
+		}
+	}
+	t.coreTime = CoreTime(v)
+}
+
+// Type returns type value.
+func (t Time) Type() uint8 {
+	fspTt := uint64(t.coreTime) & fspTtBitFieldMask
+	if fspTt == fspTtForDate {
+		return mysql.TypeDate
+	}
+	if fspTt&ttBitFieldMask == ttForTimestamp {
+		return mysql.TypeTimestamp
+	}
+	return mysql.TypeDatetime
+}
+
+// Fsp returns fsp value.
+func (t Time) Fsp() int8 {
+	fspTt := uint64(t.coreTime) & fspTtBitFieldMask
+	if fspTt == fspTtForDate {
+		return 0
+	}
+	return int8((fspTt & fspBitFieldMask) >> fspBitFieldOffset)
+}
+
+// SetType updates the type in Time.
+// Setting the type to date drops the clock part of the time.
+func (t *Time) SetType(tp uint8) {
+	if tp == mysql.TypeDate {
+		t.coreTime = FromDate(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0)
+	}
+	t.setFspTt(tp, t.Fsp())
+}
+
+// SetFsp updates the fsp in Time, it is a no-op for date.
+func (t *Time) SetFsp(fsp int8) {
+	if t.Type() == mysql.TypeDate {
+		return
+	}
+	t.setFspTt(t.Type(), fsp)
+}
+
+// CoreTime returns core time.
+func (t Time) CoreTime() CoreTime {
+	return CoreTime(uint64(t.coreTime) & coreTimeBitFieldMask)
+}
+
+// SetCoreTime updates core time, the type and fsp are kept.
+func (t *Time) SetCoreTime(ct CoreTime) {
+	fspTt := uint64(t.coreTime) & fspTtBitFieldMask
+	t.coreTime = CoreTime(uint64(ct)&coreTimeBitFieldMask | fspTt)
+}
+
+// Year returns the year value.
+func (t Time) Year() int {
+	return t.coreTime.Year()
+}
+
+// Month returns the month value.
+func (t Time) Month() int {
+	return t.coreTime.Month()
+}
+
+// Day returns the day value.
+func (t Time) Day() int {
+	return t.coreTime.Day()
+}
+
+// Hour returns the hour value.
+func (t Time) Hour() int {
+	return t.coreTime.Hour()
+}
+
+// Minute returns the minute value.
+func (t Time) Minute() int {
+	return t.coreTime.Minute()
+}
+
+// Second returns the second value.
+func (t Time) Second() int {
+	return t.coreTime.Second()
+}
+
+// Microsecond returns the microsecond value.
+func (t Time) Microsecond() int {
+	return t.coreTime.Microsecond()
+}
+
+// Clock returns the hour, minute, and second within the day specified by t.
+func (t Time) Clock() (hour int, minute int, second int) {
+	return t.Hour(), t.Minute(), t.Second()
+}
+
+// Weekday returns weekday value.
+func (t Time) Weekday() gotime.Weekday {
+	return t.coreTime.Weekday()
+}
+
+// YearDay returns day in year.
+func (t Time) YearDay() int {
+	return t.coreTime.YearDay()
+}
+
+// YearWeek returns year and week.
+func (t Time) YearWeek(mode int) (int, int) {
+	return t.coreTime.YearWeek(mode)
+}
+
+// Week returns week value.
+func (t Time) Week(mode int) int {
+	return t.coreTime.Week(mode)
+}
+
+// GoTime converts Time to GoTime.
+func (t Time) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	return t.coreTime.GoTime(loc)
+}
+
+// IsZero returns a boolean indicating whether the time is equal to ZeroTime.
+func (t Time) IsZero() bool {
+	return compareTime(t.coreTime, ZeroCoreTime) == 0
+}
+
+// InvalidZero returns a boolean indicating whether the month or day is zero.
+func (t Time) InvalidZero() bool {
+	return t.Month() == 0 || t.Day() == 0
+}
+
+// String returns the formatted string of the time, the clock part is omitted for date.
+func (t Time) String() string {
+	if t.Type() == mysql.TypeDate {
+		return fmt.Sprintf("%04d-%02d-%02d", t.Year(), t.Month(), t.Day())
+	}
+
+	str := fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	if fsp := t.Fsp(); fsp > 0 {
+		tmp := fmt.Sprintf(".%06d", t.Microsecond())
+		str = str + tmp[:1+fsp]
+	}
+	return str
+}
+
+// ToNumber returns a formatted number, YYYYMMDD for date and YYYYMMDDHHMMSS[.ffffff] for the others.
+func (t Time) ToNumber() *MyDecimal {
+	dec := new(MyDecimal)
+	if t.Type() == mysql.TypeDate {
+		dec.FromUint(dateToUint64(t.coreTime))
+		return dec
+	}
+
+	s := strconv.FormatUint(datetimeToUint64(t.coreTime), 10)
+	if fsp := t.Fsp(); fsp > 0 {
+		s += fmt.Sprintf(".%06d", t.Microsecond())[:1+fsp]
+	}
+	// We skip checking error here because time formatted string can be parsed certainly.
+	err := dec.FromString([]byte(s))
+	terror.Log(errors.Trace(err))
+	return dec
+}
+
+// Convert converts t with type tp.
+func (t Time) Convert(sc *stmtctx.StatementContext, tp uint8) (Time, error) {
+	t1 := t
+	if t.Type() == tp || t.IsZero() {
+		t1.SetType(tp)
+		return t1, nil
+	}
+
+	t1.SetType(tp)
+	err := t1.check(sc)
+	return t1, errors.Trace(err)
+}
+
+// ConvertToDuration converts mysql datetime, timestamp and date to mysql time type.
+// e.g,
+// 2012-12-12T10:10:10 -> 10:10:10
+// 2012-12-12 -> 0
+func (t Time) ConvertToDuration() (Duration, error) {
+	if t.IsZero() {
+		return ZeroDuration, nil
+	}
+
+	hour, minute, second := t.Clock()
+	frac := t.Microsecond() * 1000
+
+	d := gotime.Duration(hour*3600+minute*60+second)*gotime.Second + gotime.Duration(frac)
+	return Duration{Duration: d, Fsp: t.Fsp()}, nil
+}
+
+// Compare returns an integer comparing the time instant t to o.
+// If t is after o, returns 1, equal o, returns 0, before o, returns -1.
+func (t Time) Compare(o Time) int {
+	return compareTime(t.coreTime, o.coreTime)
+}
+
+// CompareString is like Compare,
+// but parses string to Time then compares.
+func (t Time) CompareString(sc *stmtctx.StatementContext, str string) (int, error) {
+	// use MaxFsp to parse the string
+	o, err := ParseTime(sc, str, t.Type(), MaxFsp)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	return t.Compare(o), nil
+}
+
+// roundTime rounds the time value according to digits count specified by fsp.
+func roundTime(t gotime.Time, fsp int8) gotime.Time {
+	d := gotime.Duration(math.Pow10(9 - int(fsp)))
+	return t.Round(d)
+}
+
+// RoundFrac rounds the fraction part of a time-type value according to `fsp`.
+func (t Time) RoundFrac(sc *stmtctx.StatementContext, fsp int8) (Time, error) {
+	if t.Type() == mysql.TypeDate || t.IsZero() {
+		// date type has no fsp
+		return t, nil
+	}
+
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+
+	if fsp == t.Fsp() {
+		// have same fsp
+		return t, nil
+	}
+
+	var nt CoreTime
+	if t1, err := t.GoTime(gotime.UTC); err == nil {
+		nt = FromGoTime(roundTime(t1, fsp))
+	} else {
+		// Take the hh:mm:ss part out to avoid handle month or day = 0.
+		hour, minute, second, microsecond := t.Hour(), t.Minute(), t.Second(), t.Microsecond()
+		t1 := gotime.Date(1, 1, 1, hour, minute, second, microsecond*1000, gotime.UTC)
+		t2 := roundTime(t1, fsp)
+		hour, minute, second = t2.Clock()
+		microsecond = t2.Nanosecond() / 1000
+
+		// TODO: when hh:mm:ss overflow one day after rounding, it should be add to yy:mm:dd part,
+		// but mm:dd may contain 0, it makes the code complex, so we ignore it here.
+		if t2.Day()-1 > 0 {
+			return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, t.String()))
+		}
+		nt = FromDate(t.Year(), t.Month(), t.Day(), hour, minute, second, microsecond)
+	}
+
+	return NewTime(nt, t.Type(), fsp), nil
+}
+
+// ConvertTimeZone converts the time value from one timezone to another.
+// The input time should be a valid timestamp.
+func (t *Time) ConvertTimeZone(from, to *gotime.Location) error {
+	if !t.IsZero() {
+		raw, err := t.GoTime(from)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		converted := raw.In(to)
+		t.SetCoreTime(FromGoTime(converted))
+	}
+	return nil
+}
+
+// ToPackedUint encodes Time to a packed uint64 value.
+//
+//	 1 bit  0
+//	17 bits year*13+month   (year 0-9999, month 0-12)
+//	 5 bits day             (0-31)
+//	 5 bits hour            (0-23)
+//	 6 bits minute          (0-59)
+//	 6 bits second          (0-59)
+//	24 bits microseconds    (0-999999)
+//
+//	Total: 64 bits = 8 bytes
+//
+//	0YYYYYYY.YYYYYYYY.YYdddddh.hhhhmmmm.mmssssss.ffffffff.ffffffff.ffffffff
+//
+// The packed value keeps the order of the time, so it can be used in memcomparable encoding.
+func (t Time) ToPackedUint() (uint64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+	year, month, day := t.Year(), t.Month(), t.Day()
+	hour, minute, sec := t.Clock()
+	ymd := uint64(((year*13 + month) << 5) | day)
+	hms := uint64(hour<<12 | minute<<6 | sec)
+	micro := uint64(t.Microsecond())
+	return ((ymd<<17 | hms) << 24) | micro, nil
+}
+
+// FromPackedUint decodes Time from a packed uint64 value.
+func (t *Time) FromPackedUint(packed uint64) error {
+	if packed == 0 {
+		t.SetCoreTime(ZeroCoreTime)
+		return nil
+	}
+	ymdhms := packed >> 24
+	ymd := ymdhms >> 17
+	day := int(ymd & (1<<5 - 1))
+	ym := ymd >> 5
+	month := int(ym % 13)
+	year := int(ym / 13)
+
+	hms := ymdhms & (1<<17 - 1)
+	second := int(hms & (1<<6 - 1))
+	minute := int((hms >> 6) & (1<<6 - 1))
+	hour := int(hms >> 12)
+	microsec := int(packed % (1 << 24))
+
+	t.SetCoreTime(FromDate(year, month, day, hour, minute, second, microsec))
+	return nil
+}
+
+// Check if 't' is valid
+func (t *Time) Check(sc *stmtctx.StatementContext) error {
+	return t.check(sc)
+}
+
+// check whether t matches valid Time format.
+// If allowZeroInDate is false, it returns ErrWrongValue when month or day is zero.
+// FIXME: See https://dev.mysql.com/doc/refman/5.7/en/sql-mode.html#sqlmode_no_zero_in_date
+func (t Time) check(sc *stmtctx.StatementContext) error {
+	allowZeroInDate := false
+	allowInvalidDate := false
+	// We should avoid passing sc as nil here as far as possible.
+	if sc != nil {
+		allowZeroInDate = sc.IgnoreZeroInDate
+		allowInvalidDate = sc.AllowInvalidDate
+	}
+	var err error
+	switch t.Type() {
+	case mysql.TypeTimestamp:
+		err = checkTimestampType(sc, t.coreTime)
+	case mysql.TypeDatetime, mysql.TypeDate:
+		err = checkDatetimeType(t.coreTime, allowZeroInDate, allowInvalidDate)
+	}
+	return errors.Trace(err)
+}
+
+func checkDateType(t CoreTime, allowZeroInDate, allowInvalidDate bool) error {
+	year, month, day := t.Year(), t.Month(), t.Day()
+	if year == 0 && month == 0 && day == 0 {
+		return nil
+	}
+
+	if !allowZeroInDate && (month == 0 || day == 0) {
+		return ErrWrongValue.GenWithStackByArgs(DateTimeStr, fmt.Sprintf("%04d-%02d-%02d", year, month, day))
+	}
+
+	if err := checkDateRange(t); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := checkMonthDay(year, month, day, allowInvalidDate); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+func checkDateRange(t CoreTime) error {
+	// Oddly enough, MySQL document says date range should larger than '1000-01-01',
+	// but we can insert '0001-01-01' actually.
+	if compareTime(t, MaxDatetime) > 0 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, t))
+	}
+	return nil
+}
+
+func checkMonthDay(year, month, day int, allowInvalidDate bool) error {
+	if month < 0 || month > 12 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, fmt.Sprintf("%d-%d-%d", year, month, day)))
+	}
+
+	maxDay := 31
+	if !allowInvalidDate && month > 0 {
+		maxDay = GetLastDay(year, month)
+	}
+
+	if day < 0 || day > maxDay {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, fmt.Sprintf("%d-%d-%d", year, month, day)))
+	}
+	return nil
+}
+
+func checkTimestampType(sc *stmtctx.StatementContext, t CoreTime) error {
+	if compareTime(t, ZeroCoreTime) == 0 {
+		return nil
+	}
+
+	checkTime := t
+	if sc != nil && sc.TimeZone != nil && sc.TimeZone != BoundTimezone {
+		convertTime := NewTime(t, mysql.TypeTimestamp, DefaultFsp)
+		err := convertTime.ConvertTimeZone(sc.TimeZone, BoundTimezone)
+		if err != nil {
+			return err
+		}
+		checkTime = convertTime.coreTime
+	}
+	if compareTime(checkTime, MaxTimestamp.coreTime) > 0 || compareTime(checkTime, MinTimestamp.coreTime) < 0 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, NewTime(t, mysql.TypeTimestamp, MaxFsp)))
+	}
+
+	if _, err := t.GoTime(gotime.Local); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+func checkDatetimeType(t CoreTime, allowZeroInDate, allowInvalidDate bool) error {
+	if err := checkDateType(t, allowZeroInDate, allowInvalidDate); err != nil {
+		return errors.Trace(err)
+	}
+
+	hour, minute, second := t.Hour(), t.Minute(), t.Second()
+	if hour < 0 || hour >= 24 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(hour)))
+	}
+	if minute < 0 || minute >= 60 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(minute)))
+	}
+	if second < 0 || second >= 60 {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(second)))
+	}
+
+	return nil
+}
+
+// fromDateChecked makes a CoreTime, it returns false if any field is too large for its bit field.
+func fromDateChecked(year, month, day, hour, minute, second, microsecond int) (CoreTime, bool) {
+	if year < 0 || uint64(year) >= 1<<yearBitFieldWidth ||
+		month < 0 || uint64(month) >= 1<<monthBitFieldWidth ||
+		day < 0 || uint64(day) >= 1<<dayBitFieldWidth ||
+		hour < 0 || uint64(hour) >= 1<<hourBitFieldWidth ||
+		minute < 0 || uint64(minute) >= 1<<minuteBitFieldWidth ||
+		second < 0 || uint64(second) >= 1<<secondBitFieldWidth ||
+		microsecond < 0 || uint64(microsecond) >= 1<<microsecondBitFieldWidth {
+		return ZeroCoreTime, false
+	}
+	return FromDate(year, month, day, hour, minute, second, microsecond), true
+}
+
+// GetFracIndex finds the last '.' for get fracStr, index = -1 means fracStr not found.
+// but for format like '2019.01.01 00:00:00', the index should be -1.
+func GetFracIndex(s string) (index int) {
+	index = -1
+	for i := len(s) - 1; i >= 0; i-- {
+		if isPunctuation(s[i]) {
+			if s[i] == '.' {
+				index = i
+			}
+			break
+		}
+	}
+	return index
+}
+
+// GetFsp gets the fsp of a string.
+func GetFsp(s string) int8 {
+	index := GetFracIndex(s)
+	var fsp int
+	if index < 0 {
+		fsp = 0
+	} else {
+		fsp = len(s) - index - 1
+	}
+
+	if fsp == len(s) {
+		fsp = 0
+	} else if fsp > int(MaxFsp) {
+		fsp = int(MaxFsp)
+	}
+	return int8(fsp)
+}
+
+// isPunctuation tells whether c is an ASCII punctuation character, which can be used as a delimiter.
+func isPunctuation(c byte) bool {
+	return (c >= 0x21 && c <= 0x2F) || (c >= 0x3A && c <= 0x40) || (c >= 0x5B && c <= 0x60) || (c >= 0x7B && c <= 0x7E)
+}
+
+func isValidSeparator(c byte, prevParts int) bool {
+	if isPunctuation(c) {
+		return true
+	}
+
+	// Space and 'T' are only valid between the date part and the time part.
+	return prevParts == 2 && (c == ' ' || c == 'T')
+}
+
+// ParseDateFormat parses a formatted date string and returns separated components.
+func ParseDateFormat(format string) []string {
+	format = strings.TrimSpace(format)
+
+	if len(format) == 0 {
+		return nil
+	}
+
+	// Date format must start and end with number.
+	if !isDigit(format[0]) || !isDigit(format[len(format)-1]) {
+		return nil
+	}
+
+	start := 0
+	// Initialize `seps` with capacity of 6. The input `format` is typically
+	// a date time of the form "2006-01-02 15:04:05", which has 6 numeric parts
+	// (the fractional second part is usually removed by `splitDateTime`).
+	// Setting `seps`'s capacity to 6 avoids reallocation in this common case.
+	seps := make([]string, 0, 6)
+	for i := 1; i < len(format)-1; i++ {
+		if isValidSeparator(format[i], len(seps)) {
+			prevParts := len(seps)
+			seps = append(seps, format[start:i])
+			start = i + 1
+
+			// consume further consecutive separators
+			for j := i + 1; j < len(format); j++ {
+				if !isValidSeparator(format[j], prevParts) {
+					break
+				}
+
+				start++
+				i++
+			}
+
+			continue
+		}
+
+		if !isDigit(format[i]) {
+			return nil
+		}
+	}
+
+	seps = append(seps, format[start:])
+	return seps
+}
+
+// splitDateTime splits the date time string into the separated parts and the fraction part.
+func splitDateTime(format string) (seps []string, fracStr string) {
+	index := GetFracIndex(format)
+	if index > 0 {
+		fracStr = format[index+1:]
+		format = format[:index]
+	}
+
+	seps = ParseDateFormat(format)
+	return
+}
+
+// adjustYear adjusts year according to y.
+// See https://dev.mysql.com/doc/refman/5.7/en/two-digit-years.html
+func adjustYear(y int) int {
+	if y >= 0 && y <= 69 {
+		y = 2000 + y
+	} else if y >= 70 && y <= 99 {
+		y = 1900 + y
+	}
+	return y
+}
+
+func scanTimeArgs(seps []string, args ...*int) error {
+	if len(seps) != len(args) {
+		return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, seps))
+	}
+
+	var err error
+	for i, s := range seps {
+		*args[i], err = strconv.Atoi(s)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-literals.html.
+func parseDatetime(sc *stmtctx.StatementContext, str string, fsp int8, isFloat bool) (Time, error) {
+	var (
+		year, month, day, hour, minute, second int
+		fracStr                                string
+		hhmmss                                 bool
+		err                                    error
+	)
+
+	seps, fracStr := splitDateTime(str)
+	var truncatedOrIncorrect bool
+	switch len(seps) {
+	case 1:
+		l := len(seps[0])
+		switch l {
+		case 14: // No delimiter. YYYYMMDDHHMMSS
+			_, err = fmt.Sscanf(seps[0], "%4d%2d%2d%2d%2d%2d", &year, &month, &day, &hour, &minute, &second)
+			hhmmss = true
+		case 12: // YYMMDDHHMMSS
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%2d%2d", &year, &month, &day, &hour, &minute, &second)
+			year = adjustYear(year)
+			hhmmss = true
+		case 11: // YYMMDDHHMMS
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%2d%1d", &year, &month, &day, &hour, &minute, &second)
+			year = adjustYear(year)
+			hhmmss = true
+		case 10: // YYMMDDHHMM
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%2d", &year, &month, &day, &hour, &minute)
+			year = adjustYear(year)
+		case 9: // YYMMDDHHM
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%2d%1d", &year, &month, &day, &hour, &minute)
+			year = adjustYear(year)
+		case 8: // YYYYMMDD
+			_, err = fmt.Sscanf(seps[0], "%4d%2d%2d", &year, &month, &day)
+		case 7: // YYMMDDH
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%2d%1d", &year, &month, &day, &hour)
+			year = adjustYear(year)
+		case 6, 5:
+			// YYMMDD && YYMMD
+			_, err = fmt.Sscanf(seps[0], "%2d%2d%d", &year, &month, &day)
+			year = adjustYear(year)
+		default:
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+		if l == 5 || l == 6 || l == 8 {
+			// YYMMDD or YYYYMMDD
+			// We must handle float => string => datetime, the difference is that fractional
+			// part of float type is discarded directly, while fractional part of string type
+			// is parsed to HH:MM:SS.
+			if !isFloat {
+				// '20170118.123423' => 2017-01-18 12:34:23
+				switch len(fracStr) {
+				case 0:
+				case 1, 2:
+					_, err = fmt.Sscanf(fracStr, "%2d ", &hour)
+				case 3, 4:
+					_, err = fmt.Sscanf(fracStr, "%2d%2d ", &hour, &minute)
+				default:
+					_, err = fmt.Sscanf(fracStr, "%2d%2d%2d ", &hour, &minute, &second)
+				}
+				truncatedOrIncorrect = err != nil
+			}
+		}
+		if l == 9 || l == 10 {
+			if len(fracStr) == 0 {
+				second = 0
+			} else {
+				_, err = fmt.Sscanf(fracStr, "%2d ", &second)
+			}
+			truncatedOrIncorrect = err != nil
+		}
+		if truncatedOrIncorrect && sc != nil {
+			sc.AppendWarning(ErrTruncatedWrongVal.GenWithStackByArgs("datetime", str))
+			err = nil
+		}
+	case 2:
+		// YYYY-MM is not valid
+		if len(fracStr) == 0 {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+
+		// YYYY-MM.DD, DD is treat as fracStr
+		err = scanTimeArgs(append(seps, fracStr), &year, &month, &day)
+		fracStr = ""
+	case 3:
+		// YYYY-MM-DD
+		err = scanTimeArgs(seps, &year, &month, &day)
+	case 4:
+		// YYYY-MM-DD HH
+		err = scanTimeArgs(seps, &year, &month, &day, &hour)
+	case 5:
+		// YYYY-MM-DD HH-MM
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute)
+	case 6:
+		// We don't have fractional seconds part.
+		// YYYY-MM-DD HH-MM-SS
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute, &second)
+		hhmmss = true
+	default:
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+	if err != nil {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+
+	// If str is sepereated by delimiters, the first one is year, and if the year is 1 or 2 digit,
+	// we should adjust it.
+	// TODO: adjust year is very complex, now we only consider the simplest way.
+	if len(seps[0]) <= 2 {
+		if !(year == 0 && month == 0 && day == 0 && hour == 0 && minute == 0 && second == 0 && fracStr == "") {
+			// Skip a special case "00-00-00".
+			year = adjustYear(year)
+		}
+	}
+
+	var microsecond int
+	var overflow bool
+	if hhmmss {
+		// If input string is "20170118.999", without hhmmss, fsp is meaningless.
+		microsecond, overflow, err = ParseFrac(fracStr, fsp)
+		if err != nil {
+			return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+		}
+	}
+
+	tmp, ok := fromDateChecked(year, month, day, hour, minute, second, microsecond)
+	if !ok {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(DateTimeStr, str))
+	}
+	if overflow {
+		// Convert to Go time and add 1 second, to handle input like 2017-01-05 08:40:59.575601
+		t1, err := tmp.GoTime(gotime.UTC)
+		if err != nil {
+			return ZeroDatetime, errors.Trace(err)
+		}
+		tmp = FromGoTime(t1.Add(gotime.Second))
+	}
+
+	return NewTime(tmp, mysql.TypeDatetime, fsp), nil
+}
+
+// ParseTime parses a formatted string with type tp and specific fsp.
+// Type is TypeDatetime, TypeTimestamp and TypeDate.
+// Fsp is in range [0, 6].
+// MySQL supports many valid datetime format, but still has some limitation.
+// If delimiter exists, the date part and time part is separated by a space or T,
+// other punctuation character can be used as the delimiter between date parts or time parts.
+// If no delimiter, the format must be YYYYMMDDHHMMSS or YYMMDDHHMMSS
+// If we have fractional seconds part, we must use decimal points as the delimiter.
+// The valid datetime range is from '1000-01-01 00:00:00.000000' to '9999-12-31 23:59:59.999999'.
+// The valid timestamp range is from '1970-01-01 00:00:01.000000' to '2038-01-19 03:14:07.999999'.
+// The valid date range is from '1000-01-01' to '9999-12-31'
+func ParseTime(sc *stmtctx.StatementContext, str string, tp byte, fsp int8) (Time, error) {
+	return parseTime(sc, str, tp, fsp, false)
+}
+
+// ParseTimeFromFloatString is similar to ParseTime, except that it's used to parse a float converted string.
+func ParseTimeFromFloatString(sc *stmtctx.StatementContext, str string, tp byte, fsp int8) (Time, error) {
+	// MySQL compatibility: 0.0 should not be converted to null, see #11203
+	if len(str) >= 3 && str[:3] == "0.0" {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), nil
+	}
+	return parseTime(sc, str, tp, fsp, true)
+}
+
+func parseTime(sc *stmtctx.StatementContext, str string, tp byte, fsp int8, isFloat bool) (Time, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t, err := parseDatetime(sc, str, fsp, isFloat)
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t.SetType(tp)
+	if err = t.check(sc); err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+	return t, nil
+}
+
+// ParseDatetime is a helper function wrapping ParseTime with datetime type and default fsp.
+func ParseDatetime(sc *stmtctx.StatementContext, str string) (Time, error) {
+	return ParseTime(sc, str, mysql.TypeDatetime, GetFsp(str))
+}
+
+// ParseTimestamp is a helper function wrapping ParseTime with timestamp type and default fsp.
+func ParseTimestamp(sc *stmtctx.StatementContext, str string) (Time, error) {
+	return ParseTime(sc, str, mysql.TypeTimestamp, GetFsp(str))
+}
+
+// ParseDate is a helper function wrapping ParseTime with date type.
+func ParseDate(sc *stmtctx.StatementContext, str string) (Time, error) {
+	// date has no fractional seconds precision
+	return ParseTime(sc, str, mysql.TypeDate, MinFsp)
+}
+
+// ParseTimeFromNum parses a formatted int64,
+// returns the value which type is tp.
+func ParseTimeFromNum(sc *stmtctx.StatementContext, num int64, tp byte, fsp int8) (Time, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t, err := parseDateTimeFromNum(sc, num)
+	if err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+
+	t.SetType(tp)
+	t.SetFsp(fsp)
+	if err := t.check(sc); err != nil {
+		return NewTime(ZeroCoreTime, tp, DefaultFsp), errors.Trace(err)
+	}
+	return t, nil
+}
+
+// ParseDatetimeFromNum is a helper function wrapping ParseTimeFromNum with datetime type and default fsp.
+func ParseDatetimeFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	return ParseTimeFromNum(sc, num, mysql.TypeDatetime, DefaultFsp)
+}
+
+// ParseTimestampFromNum is a helper function wrapping ParseTimeFromNum with timestamp type and default fsp.
+func ParseTimestampFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	return ParseTimeFromNum(sc, num, mysql.TypeTimestamp, DefaultFsp)
+}
+
+// ParseDateFromNum is a helper function wrapping ParseTimeFromNum with date type.
+func ParseDateFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	// date has no fractional seconds precision
+	return ParseTimeFromNum(sc, num, mysql.TypeDate, MinFsp)
+}
+
+func getTime(sc *stmtctx.StatementContext, num int64, tp byte) (Time, error) {
+	s1 := num / 1000000
+	s2 := num - s1*1000000
+
+	year := int(s1 / 10000)
+	s1 %= 10000
+	month := int(s1 / 100)
+	day := int(s1 % 100)
+
+	hour := int(s2 / 10000)
+	s2 %= 10000
+	minute := int(s2 / 100)
+	second := int(s2 % 100)
+
+	ct, ok := fromDateChecked(year, month, day, hour, minute, second, 0)
+	if !ok {
+		return ZeroDatetime, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+	t := NewTime(ct, tp, DefaultFsp)
+	err := t.check(sc)
+	return t, errors.Trace(err)
+}
+
+// parseDateTimeFromNum parses date time from num.
+// See number_to_datetime function.
+// https://github.com/mysql/mysql-server/blob/5.7/sql-common/my_time.c
+func parseDateTimeFromNum(sc *stmtctx.StatementContext, num int64) (Time, error) {
+	t := ZeroDate
+	// Check zero.
+	if num == 0 {
+		return t, nil
+	}
+
+	// Check datetime type.
+	if num >= 10000101000000 {
+		return getTime(sc, num, mysql.TypeDatetime)
+	}
+
+	// Check MMDD.
+	if num < 101 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDD, year: 2000-2069
+	if num <= (70-1)*10000+1231 {
+		num = (num + 20000000) * 1000000
+		return getTime(sc, num, mysql.TypeDate)
+	}
+
+	// Check YYMMDD.
+	if num < 70*10000+101 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDD, year: 1970-1999
+	if num <= 991231 {
+		num = (num + 19000000) * 1000000
+		return getTime(sc, num, mysql.TypeDate)
+	}
+
+	// Adjust hour/min/second.
+	if num <= 99991231 {
+		num = num * 1000000
+		return getTime(sc, num, mysql.TypeDate)
+	}
+
+	// Check MMDDHHMMSS.
+	if num < 101000000 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDDHHMMSS, 2000-2069
+	if num <= 69*10000000000+1231235959 {
+		num = num + 20000000000000
+		return getTime(sc, num, mysql.TypeDatetime)
+	}
+
+	// Check YYYYMMDDHHMMSS.
+	if num < 70*10000000000+101000000 {
+		return t, errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.FormatInt(num, 10)))
+	}
+
+	// Adjust year
+	// YYMMDDHHMMSS, 1970-1999
+	if num <= 991231235959 {
+		num = num + 19000000000000
+		return getTime(sc, num, mysql.TypeDatetime)
+	}
+
+	return getTime(sc, num, mysql.TypeDatetime)
+}
+
+// CurrentTime returns current time with type tp.
+func CurrentTime(tp uint8) Time {
+	return NewTime(FromGoTime(gotime.Now()), tp, DefaultFsp)
+}
+
+// DateFormat returns a textual representation of the time value formatted
+// according to layout.
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (t Time) DateFormat(layout string) (string, error) {
+	var buf bytes.Buffer
+	inPatternMatch := false
+	for _, b := range layout {
+		if inPatternMatch {
+			if err := t.convertDateFormat(b, &buf); err != nil {
+				return "", errors.Trace(err)
+			}
+			inPatternMatch = false
+			continue
+		}
+
+		// It's not in pattern match now.
+		if b == '%' {
+			inPatternMatch = true
+		} else {
+			buf.WriteRune(b)
+		}
+	}
+	return buf.String(), nil
+}
+
+var abbrevWeekdayName = []string{
+	"Sun", "Mon", "Tue",
+	"Wed", "Thu", "Fri", "Sat",
+}
+
+func (t Time) convertDateFormat(b rune, buf *bytes.Buffer) error {
+	switch b {
+	case 'b':
+		m := t.Month()
+		if m == 0 || m > 12 {
+			return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(m)))
+		}
+		buf.WriteString(MonthNames[m-1][:3])
+	case 'M':
+		m := t.Month()
+		if m == 0 || m > 12 {
+			return errors.Trace(ErrWrongValue.GenWithStackByArgs(TimeStr, strconv.Itoa(m)))
+		}
+		buf.WriteString(MonthNames[m-1])
+	case 'm':
+		buf.WriteString(FormatIntWidthN(t.Month(), 2))
+	case 'c':
+		buf.WriteString(strconv.FormatInt(int64(t.Month()), 10))
+	case 'D':
+		buf.WriteString(strconv.FormatInt(int64(t.Day()), 10))
+		buf.WriteString(abbrDayOfMonth(t.Day()))
+	case 'd':
+		buf.WriteString(FormatIntWidthN(t.Day(), 2))
+	case 'e':
+		buf.WriteString(strconv.FormatInt(int64(t.Day()), 10))
+	case 'j':
+		fmt.Fprintf(buf, "%03d", t.YearDay())
+	case 'H':
+		buf.WriteString(FormatIntWidthN(t.Hour(), 2))
+	case 'k':
+		buf.WriteString(strconv.FormatInt(int64(t.Hour()), 10))
+	case 'h', 'I':
+		h := t.Hour()
+		if h%12 == 0 {
+			buf.WriteString("12")
+		} else {
+			buf.WriteString(FormatIntWidthN(h%12, 2))
+		}
+	case 'l':
+		h := t.Hour()
+		if h%12 == 0 {
+			buf.WriteString("12")
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(h%12), 10))
+		}
+	case 'i':
+		buf.WriteString(FormatIntWidthN(t.Minute(), 2))
+	case 'p':
+		hour := t.Hour()
+		if hour/12%2 == 0 {
+			buf.WriteString("AM")
+		} else {
+			buf.WriteString("PM")
+		}
+	case 'r':
+		h := t.Hour()
+		switch {
+		case h == 0:
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", 12, t.Minute(), t.Second())
+		case h == 12:
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", 12, t.Minute(), t.Second())
+		case h < 12:
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", h, t.Minute(), t.Second())
+		default:
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", h-12, t.Minute(), t.Second())
+		}
+	case 'T':
+		fmt.Fprintf(buf, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	case 'S', 's':
+		buf.WriteString(FormatIntWidthN(t.Second(), 2))
+	case 'f':
+		fmt.Fprintf(buf, "%06d", t.Microsecond())
+	case 'U':
+		buf.WriteString(FormatIntWidthN(t.Week(0), 2))
+	case 'u':
+		buf.WriteString(FormatIntWidthN(t.Week(1), 2))
+	case 'V':
+		buf.WriteString(FormatIntWidthN(t.Week(2), 2))
+	case 'v':
+		_, w := t.YearWeek(3)
+		buf.WriteString(FormatIntWidthN(w, 2))
+	case 'a':
+		buf.WriteString(abbrevWeekdayName[t.Weekday()])
+	case 'W':
+		buf.WriteString(t.Weekday().String())
+	case 'w':
+		buf.WriteString(strconv.FormatInt(int64(t.Weekday()), 10))
+	case 'X':
+		year, _ := t.YearWeek(2)
+		buf.WriteString(FormatIntWidthN(year, 4))
+	case 'x':
+		year, _ := t.YearWeek(3)
+		buf.WriteString(FormatIntWidthN(year, 4))
+	case 'Y':
+		buf.WriteString(FormatIntWidthN(t.Year(), 4))
+	case 'y':
+		str := FormatIntWidthN(t.Year(), 4)
+		buf.WriteString(str[2:])
+	default:
+		buf.WriteRune(b)
+	}
+
+	return nil
+}
+
+// FormatIntWidthN uses to format int with width. Insufficient digits are filled by 0.
+func FormatIntWidthN(num, n int) string {
+	numString := strconv.FormatInt(int64(num), 10)
+	if len(numString) >= n {
+		return numString
+	}
+	return strings.Repeat("0", n-len(numString)) + numString
+}
+
+func abbrDayOfMonth(day int) string {
+	var str string
+	switch day {
+	case 1, 21, 31:
+		str = "st"
+	case 2, 22:
+		str = "nd"
+	case 3, 23:
+		str = "rd"
+	default:
+		str = "th"
+	}
+	return str
+}
+
+// Duration is the type for MySQL TIME type.
+type Duration struct {
+	gotime.Duration
+	// Fsp is short for Fractional Seconds Precision.
+	// See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
+	Fsp int8
+}
+
+// NewDuration constructs a Duration from the clock fields.
+func NewDuration(hour, minute, second, microsecond int, fsp int8) Duration {
+	return Duration{
+		Duration: gotime.Duration(hour)*gotime.Hour + gotime.Duration(minute)*gotime.Minute +
+			gotime.Duration(second)*gotime.Second + gotime.Duration(microsecond)*gotime.Microsecond,
+		Fsp: fsp,
+	}
+}
+
+// Add adds d to d, returns a duration value.
+func (d Duration) Add(v Duration) (Duration, error) {
+	dsum, err := AddInt64(int64(d.Duration), int64(v.Duration))
+	if err != nil {
+		return Duration{}, errors.Trace(err)
+	}
+	return Duration{Duration: gotime.Duration(dsum), Fsp: myMaxInt8(d.Fsp, v.Fsp)}, nil
+}
+
+// Sub subtracts d to d, returns a duration value.
+func (d Duration) Sub(v Duration) (Duration, error) {
+	dsum, err := SubInt64(int64(d.Duration), int64(v.Duration))
+	if err != nil {
+		return Duration{}, errors.Trace(err)
+	}
+	return Duration{Duration: gotime.Duration(dsum), Fsp: myMaxInt8(d.Fsp, v.Fsp)}, nil
+}
+
+// String returns the time formatted using default TimeFormat and fsp.
+func (d Duration) String() string {
+	var buf bytes.Buffer
+
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	if sign < 0 {
+		buf.WriteByte('-')
+	}
+
+	fmt.Fprintf(&buf, "%02d:%02d:%02d", hours, minutes, seconds)
+	if d.Fsp > 0 {
+		buf.WriteString(".")
+		buf.WriteString(d.formatFrac(fraction))
+	}
+
+	return buf.String()
+}
+
+func (d Duration) formatFrac(frac int) string {
+	s := fmt.Sprintf("%06d", frac)
+	return s[0:d.Fsp]
+}
+
+// ToNumber changes duration to number format.
+// e.g,
+// 10:10:10 -> 101010
+func (d Duration) ToNumber() *MyDecimal {
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	var (
+		s       string
+		signStr string
+	)
+
+	if sign < 0 {
+		signStr = "-"
+	}
+
+	if d.Fsp == 0 {
+		s = fmt.Sprintf("%s%02d%02d%02d", signStr, hours, minutes, seconds)
+	} else {
+		s = fmt.Sprintf("%s%02d%02d%02d.%s", signStr, hours, minutes, seconds, d.formatFrac(fraction))
+	}
+
+	// We skip checking error here because time formatted string can be parsed certainly.
+	dec := new(MyDecimal)
+	err := dec.FromString([]byte(s))
+	terror.Log(errors.Trace(err))
+	return dec
+}
+
+// ConvertToTime converts duration to Time.
+// Tp is TypeDatetime, TypeTimestamp and TypeDate.
+func (d Duration) ConvertToTime(sc *stmtctx.StatementContext, tp uint8) (Time, error) {
+	loc := gotime.Local
+	if sc != nil && sc.TimeZone != nil {
+		loc = sc.TimeZone
+	}
+	year, month, day := gotime.Now().In(loc).Date()
+	datePart := gotime.Date(year, month, day, 0, 0, 0, 0, gotime.UTC)
+	t := NewTime(FromGoTime(datePart.Add(d.Duration)), mysql.TypeDatetime, d.Fsp)
+	return t.Convert(sc, tp)
+}
+
+// RoundFrac rounds fractional seconds precision with new fsp and returns a new one.
+// We will use the “round half up” rule, e.g, >= 0.5 -> 1, < 0.5 -> 0,
+// so 10:10:10.999999 round 0 -> 10:10:11
+// and 10:10:10.000000 round 0 -> 10:10:10
+func (d Duration) RoundFrac(fsp int8) (Duration, error) {
+	fsp, err := CheckFsp(int(fsp))
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+
+	if fsp == d.Fsp {
+		return d, nil
+	}
+
+	nd := d.Duration.Round(gotime.Duration(math.Pow10(9 - int(fsp))))
+	return Duration{Duration: nd, Fsp: fsp}, nil
+}
+
+// Compare returns an integer comparing the Duration instant t to o.
+// If d is after o, returns 1, equal o, returns 0, before o, returns -1.
+func (d Duration) Compare(o Duration) int {
+	if d.Duration > o.Duration {
+		return 1
+	} else if d.Duration == o.Duration {
+		return 0
+	}
+	return -1
+}
+
+// CompareString is like Compare,
+// but parses str to Duration then compares.
+func (d Duration) CompareString(sc *stmtctx.StatementContext, str string) (int, error) {
+	// use MaxFsp to parse the string
+	o, err := ParseDuration(sc, str, MaxFsp)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Compare(o), nil
+}
+
+// Hour returns current hour.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Hour() int {
+	_, hour, _, _, _ := splitDuration(d.Duration)
+	return hour
+}
+
+// Minute returns current minute.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Minute() int {
+	_, _, minute, _, _ := splitDuration(d.Duration)
+	return minute
+}
+
+// Second returns current second.
+// e.g, hour("11:11:11") -> 11
+func (d Duration) Second() int {
+	_, _, _, second, _ := splitDuration(d.Duration)
+	return second
+}
+
+// MicroSecond returns current microsecond.
+// e.g, hour("11:11:11.11") -> 110000
+func (d Duration) MicroSecond() int {
+	_, _, _, _, frac := splitDuration(d.Duration)
+	return frac
+}
+
+func splitDuration(t gotime.Duration) (int, int, int, int, int) {
+	sign := 1
+	if t < 0 {
+		t = -t
+		sign = -1
+	}
+
+	hours := t / gotime.Hour
+	t -= hours * gotime.Hour
+	minutes := t / gotime.Minute
+	t -= minutes * gotime.Minute
+	seconds := t / gotime.Second
+	t -= seconds * gotime.Second
+	fraction := t / gotime.Microsecond
+
+	return sign, int(hours), int(minutes), int(seconds), int(fraction)
+}
+
+// TruncateOverflowMySQLTime truncates d when it overflows, and return ErrTruncatedWrongVal.
+func TruncateOverflowMySQLTime(d gotime.Duration) (gotime.Duration, error) {
+	if d > MaxTime {
+		return MaxTime, ErrTruncatedWrongVal.GenWithStackByArgs("time", d)
+	} else if d < MinTime {
+		return MinTime, ErrTruncatedWrongVal.GenWithStackByArgs("time", d)
+	}
+
+	return d, nil
+}
+
+// ParseDuration parses the time form a formatted string with a fractional seconds part,
+// returns the duration type Time value.
+// See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
+func ParseDuration(sc *stmtctx.StatementContext, str string, fsp int8) (Duration, error) {
+	var (
+		day, hour, minute, second int
+		err                       error
+		sign                      = 0
+		dayExists                 = false
+		origStr                   = str
+	)
+
+	fsp, err = CheckFsp(int(fsp))
+	if err != nil {
+		return ZeroDuration, errors.Trace(err)
+	}
+
+	str = strings.TrimSpace(str)
+	if len(str) == 0 {
+		return ZeroDuration, nil
+	} else if str[0] == '-' {
+		str = str[1:]
+		sign = -1
+	}
+
+	// Time format may has day.
+	if n := strings.IndexByte(str, ' '); n >= 0 {
+		if day, err = strconv.Atoi(str[:n]); err == nil {
+			dayExists = true
+		}
+		str = str[n+1:]
+	}
+
+	var (
+		integeralPart = str
+		fracPart      int
+		overflow      bool
+	)
+	if n := strings.IndexByte(str, '.'); n >= 0 {
+		// It has fractional precision parts.
+		fracStr := str[n+1:]
+		fracPart, overflow, err = ParseFrac(fracStr, fsp)
+		if err != nil {
+			return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+		}
+		integeralPart = str[0:n]
+	}
+
+	// It tries to split integeralPart with delimiter, time delimiter must be :
+	seps := strings.Split(integeralPart, ":")
+
+	switch len(seps) {
+	case 1:
+		if dayExists {
+			hour, err = strconv.Atoi(seps[0])
+		} else {
+			// No delimiter.
+			switch len(integeralPart) {
+			case 7: // HHHMMSS
+				_, err = fmt.Sscanf(integeralPart, "%3d%2d%2d", &hour, &minute, &second)
+			case 6: // HHMMSS
+				_, err = fmt.Sscanf(integeralPart, "%2d%2d%2d", &hour, &minute, &second)
+			case 5: // HMMSS
+				_, err = fmt.Sscanf(integeralPart, "%1d%2d%2d", &hour, &minute, &second)
+			case 4: // MMSS
+				_, err = fmt.Sscanf(integeralPart, "%2d%2d", &minute, &second)
+			case 3: // MSS
+				_, err = fmt.Sscanf(integeralPart, "%1d%2d", &minute, &second)
+			case 2: // SS
+				_, err = fmt.Sscanf(integeralPart, "%2d", &second)
+			case 1: // 0S
+				_, err = fmt.Sscanf(integeralPart, "%1d", &second)
+			default: // Maybe contains date.
+				t, err1 := ParseDatetime(sc, str)
+				if err1 != nil {
+					return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+				}
+				dur, err1 := t.ConvertToDuration()
+				if err1 != nil {
+					return ZeroDuration, errors.Trace(err1)
+				}
+				return dur.RoundFrac(fsp)
+			}
+		}
+	case 2:
+		// HH:MM
+		_, err = fmt.Sscanf(integeralPart, "%2d:%2d", &hour, &minute)
+	case 3:
+		// Time format maybe HH:MM:SS or HHH:MM:SS.
+		// See https://dev.mysql.com/doc/refman/5.7/en/time.html
+		if len(seps[0]) == 3 {
+			_, err = fmt.Sscanf(integeralPart, "%3d:%2d:%2d", &hour, &minute, &second)
+		} else {
+			_, err = fmt.Sscanf(integeralPart, "%2d:%2d:%2d", &hour, &minute, &second)
+		}
+	default:
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+	}
+
+	if err != nil {
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+	}
+
+	if overflow {
+		second++
+		fracPart = 0
+	}
+	// Invalid TIME values are converted to '00:00:00'.
+	// See https://dev.mysql.com/doc/refman/5.7/en/time.html
+	if minute >= 60 || second > 60 || (!overflow && second == 60) {
+		return ZeroDuration, ErrTruncatedWrongVal.GenWithStackByArgs("time", origStr)
+	}
+	d := gotime.Duration(day*24*3600+hour*3600+minute*60+second)*gotime.Second + gotime.Duration(fracPart)*gotime.Microsecond
+	if sign == -1 {
+		d = -d
+	}
+
+	d, err = TruncateOverflowMySQLTime(d)
+	return Duration{Duration: d, Fsp: fsp}, errors.Trace(err)
+}
+
+// NumberToDuration converts number to Duration.
+func NumberToDuration(number int64, fsp int8) (Duration, error) {
+	if number > TimeMaxValue {
+		// Try to parse DATETIME.
+		if number >= 10000000000 { // '2001-00-00 00-00-00'
+			if t, err := ParseDatetimeFromNum(nil, number); err == nil {
+				dur, err1 := t.ConvertToDuration()
+				return dur, errors.Trace(err1)
+			}
+		}
+		return Duration{Duration: MaxTime, Fsp: fsp}, ErrOverflow.GenWithStackByArgs("Duration", strconv.FormatInt(number, 10))
+	} else if number < -TimeMaxValue {
+		return Duration{Duration: MinTime, Fsp: fsp}, ErrOverflow.GenWithStackByArgs("Duration", strconv.FormatInt(number, 10))
+	}
+	var neg bool
+	if neg = number < 0; neg {
+		number = -number
+	}
+
+	if number/10000 > TimeMaxHour || number%100 >= 60 || (number/100)%100 >= 60 {
+		return ZeroDuration, errors.Trace(ErrInvalidTimeFormat.GenWithStackByArgs(strconv.FormatInt(number, 10)))
+	}
+	dur := NewDuration(int(number/10000), int((number/100)%100), int(number%100), 0, fsp)
+	if neg {
+		dur.Duration = -dur.Duration
+	}
+	return dur, nil
+}
+
+// IsClockUnit returns true when unit is interval unit with hour, minute or second.
+func IsClockUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND", "SECOND", "MINUTE", "HOUR",
+		"SECOND_MICROSECOND", "MINUTE_MICROSECOND", "MINUTE_SECOND",
+		"HOUR_MICROSECOND", "HOUR_SECOND", "HOUR_MINUTE",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDateUnit returns true when unit is interval unit with year, quarter, month, week or day.
+func IsDateUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "DAY", "WEEK", "MONTH", "QUARTER", "YEAR",
+		"DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR",
+		"YEAR_MONTH":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsMicrosecondUnit returns true when unit is interval unit with microsecond.
+func IsMicrosecondUnit(unit string) bool {
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND", "SECOND_MICROSECOND", "MINUTE_MICROSECOND", "HOUR_MICROSECOND", "DAY_MICROSECOND":
+		return true
+	}
+	return false
+}
+
+// ExtractDatetimeNum extracts time value number from datetime unit and format.
+func ExtractDatetimeNum(t *Time, unit string) (int64, error) {
+	switch strings.ToUpper(unit) {
+	case "DAY":
+		return int64(t.Day()), nil
+	case "WEEK":
+		return int64(t.Week(0)), nil
+	case "MONTH":
+		return int64(t.Month()), nil
+	case "QUARTER":
+		m := int64(t.Month())
+		// 1 - 3 -> 1
+		// 4 - 6 -> 2
+		// 7 - 9 -> 3
+		// 10 - 12 -> 4
+		return (m + 2) / 3, nil
+	case "YEAR":
+		return int64(t.Year()), nil
+	case "DAY_MICROSECOND":
+		h, m, s := t.Clock()
+		d := t.Day()
+		return int64(d*1000000+h*10000+m*100+s)*1000000 + int64(t.Microsecond()), nil
+	case "DAY_SECOND":
+		h, m, s := t.Clock()
+		d := t.Day()
+		return int64(d)*1000000 + int64(h)*10000 + int64(m)*100 + int64(s), nil
+	case "DAY_MINUTE":
+		h, m, _ := t.Clock()
+		d := t.Day()
+		return int64(d)*10000 + int64(h)*100 + int64(m), nil
+	case "DAY_HOUR":
+		h, _, _ := t.Clock()
+		d := t.Day()
+		return int64(d)*100 + int64(h), nil
+	case "YEAR_MONTH":
+		y, m := t.Year(), t.Month()
+		return int64(y)*100 + int64(m), nil
+	default:
+		return 0, errors.Errorf("invalid unit %s", unit)
+	}
+}
+
+// ExtractDurationNum extracts duration value number from duration unit and format.
+func ExtractDurationNum(d *Duration, unit string) (int64, error) {
+	sign, hour, minute, second, microsecond := splitDuration(d.Duration)
+	var res int64
+	switch strings.ToUpper(unit) {
+	case "MICROSECOND":
+		res = int64(microsecond)
+	case "SECOND":
+		res = int64(second)
+	case "MINUTE":
+		res = int64(minute)
+	case "HOUR":
+		res = int64(hour)
+	case "SECOND_MICROSECOND":
+		res = int64(second)*1000000 + int64(microsecond)
+	case "MINUTE_MICROSECOND":
+		res = int64(minute)*100000000 + int64(second)*1000000 + int64(microsecond)
+	case "MINUTE_SECOND":
+		res = int64(minute*100 + second)
+	case "HOUR_MICROSECOND":
+		res = int64(hour)*10000000000 + int64(minute)*100000000 + int64(second)*1000000 + int64(microsecond)
+	case "HOUR_SECOND":
+		res = int64(hour)*10000 + int64(minute)*100 + int64(second)
+	case "HOUR_MINUTE":
+		res = int64(hour)*100 + int64(minute)
+	case "DAY_MICROSECOND":
+		res = int64(hour*10000+minute*100+second)*1000000 + int64(microsecond)
+	case "DAY_SECOND":
+		res = int64(hour)*10000 + int64(minute)*100 + int64(second)
+	case "DAY_MINUTE":
+		res = int64(hour)*100 + int64(minute)
+	case "DAY_HOUR":
+		res = int64(hour)
+	default:
+		return 0, errors.Errorf("invalid unit %s", unit)
+	}
+	return int64(sign) * res, nil
+}
+
+// intervalUnitParts lists the parts of the composite interval units from the most significant one.
+var intervalUnitParts = map[string][]string{
+	"SECOND_MICROSECOND": {"SECOND", "MICROSECOND"},
+	"MINUTE_MICROSECOND": {"MINUTE", "SECOND", "MICROSECOND"},
+	"MINUTE_SECOND":      {"MINUTE", "SECOND"},
+	"HOUR_MICROSECOND":   {"HOUR", "MINUTE", "SECOND", "MICROSECOND"},
+	"HOUR_SECOND":        {"HOUR", "MINUTE", "SECOND"},
+	"HOUR_MINUTE":        {"HOUR", "MINUTE"},
+	"DAY_MICROSECOND":    {"DAY", "HOUR", "MINUTE", "SECOND", "MICROSECOND"},
+	"DAY_SECOND":         {"DAY", "HOUR", "MINUTE", "SECOND"},
+	"DAY_MINUTE":         {"DAY", "HOUR", "MINUTE"},
+	"DAY_HOUR":           {"DAY", "HOUR"},
+	"YEAR_MONTH":         {"YEAR", "MONTH"},
+}
+
+// ParseDurationValue parses the interval value of the given unit, it returns
+// the years, months, days and nanoseconds the interval represents.
+// See https://dev.mysql.com/doc/refman/5.7/en/expressions.html#temporal-intervals
+func ParseDurationValue(unit string, format string) (y int64, m int64, d int64, n int64, _ error) {
+	format = strings.TrimSpace(format)
+	unit = strings.ToUpper(unit)
+	neg := false
+	if len(format) > 0 && format[0] == '-' {
+		neg = true
+		format = format[1:]
+	}
+
+	var parts []string
+	var values []int64
+	if composite, ok := intervalUnitParts[unit]; ok {
+		parts = composite
+		fields := strings.FieldsFunc(format, func(c rune) bool { return !unicode.IsDigit(c) })
+		if len(fields) == 0 || len(fields) > len(parts) {
+			return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+		}
+		// If the value is too short, the leftmost parts are assumed to be left out.
+		parts = parts[len(parts)-len(fields):]
+		values = make([]int64, len(fields))
+		for i, field := range fields {
+			v, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+			}
+			values[i] = v
+		}
+	} else {
+		fv, err := strconv.ParseFloat(format, 64)
+		if err != nil {
+			return 0, 0, 0, 0, ErrWrongValue.GenWithStackByArgs(DateTimeStr, format)
+		}
+		if unit == "SECOND" {
+			// The fractional part of a second is kept as microseconds.
+			n = int64(math.Round(fv*1e6)) * int64(gotime.Microsecond)
+			if neg {
+				n = -n
+			}
+			return 0, 0, 0, n, nil
+		}
+		parts = []string{unit}
+		values = []int64{int64(math.Round(fv))}
+	}
+
+	for i, part := range parts {
+		v := values[i]
+		switch part {
+		case "YEAR":
+			y += v
+		case "QUARTER":
+			m += v * 3
+		case "MONTH":
+			m += v
+		case "WEEK":
+			d += v * 7
+		case "DAY":
+			d += v
+		case "HOUR":
+			n += v * int64(gotime.Hour)
+		case "MINUTE":
+			n += v * int64(gotime.Minute)
+		case "SECOND":
+			n += v * int64(gotime.Second)
+		case "MICROSECOND":
+			n += v * int64(gotime.Microsecond)
+		default:
+			return 0, 0, 0, 0, errors.Errorf("invalid single timeunit - %s", unit)
+		}
+	}
+	if neg {
+		y, m, d, n = -y, -m, -d, -n
+	}
+	return y, m, d, n, nil
+}
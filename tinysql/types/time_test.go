@@ -0,0 +1,803 @@
+// Copyright 2015 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = Suite(&testTimeSuite{})
+
+type testTimeSuite struct {
+}
+
+func (s *testTimeSuite) TestDateTime(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00"},
+		{"0001-01-01 00:00:00", "0001-01-01 00:00:00"},
+		{"00-12-31 11:30:45", "2000-12-31 11:30:45"},
+		{"12-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"2012-12-31", "2012-12-31 00:00:00"},
+		{"20121231", "2012-12-31 00:00:00"},
+		{"121231", "2012-12-31 00:00:00"},
+		{"2012^12^31 11+30+45", "2012-12-31 11:30:45"},
+		{"2012^12^31T11+30+45", "2012-12-31 11:30:45"},
+		{"2012-2-1 11:30:45", "2012-02-01 11:30:45"},
+		{"12-2-1 11:30:45", "2012-02-01 11:30:45"},
+		{"20121231113045", "2012-12-31 11:30:45"},
+		{"121231113045", "2012-12-31 11:30:45"},
+		{"2012-02-29", "2012-02-29 00:00:00"},
+		{"00-00-00", "0000-00-00 00:00:00"},
+		{"1701020301", "2017-01-02 03:01:00"},
+		{"170102036", "2017-01-02 03:06:00"},
+		{"2018.01.01", "2018-01-01 00:00:00.00"},
+		{"2018.01.01 00:00:00", "2018-01-01 00:00:00"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDatetime(sc, test.Input)
+		c.Assert(err, IsNil, Commentf("%s", test.Input))
+		c.Assert(t.String(), Equals, test.Expect, Commentf("%s", test.Input))
+	}
+
+	fspTbl := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"20170118.123", 6, "2017-01-18 12:03:00.000000"},
+		{"121231113045.123345", 6, "2012-12-31 11:30:45.123345"},
+		{"20121231113045.123345", 6, "2012-12-31 11:30:45.123345"},
+		{"121231113045.9999999", 6, "2012-12-31 11:30:46.000000"},
+		{"2012-12-31 11:30:45.123456", 4, "2012-12-31 11:30:45.1235"},
+		{"2012-12-31 11:30:45.123456", 0, "2012-12-31 11:30:45"},
+		{"2017-01-05 08:40:59.575601", 0, "2017-01-05 08:41:00"},
+		{"2017-01-05 23:59:59.575601", 0, "2017-01-06 00:00:00"},
+	}
+
+	for _, test := range fspTbl {
+		t, err := ParseTime(sc, test.Input, mysql.TypeDatetime, test.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"1000-01-01 00:00:70",
+		"1000-13-00 00:00:00",
+		"10000-01-01 00:00:00",
+		"1000-09-31 00:00:00",
+		"1001-02-29 00:00:00",
+		"20170118.999",
+		"2012-12",
+		"abc",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDatetime(sc, test)
+		c.Assert(err, NotNil, Commentf("%s", test))
+	}
+}
+
+func (s *testTimeSuite) TestTimestamp(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31 11:30:45", "2012-12-31 11:30:45"},
+		{"1970-01-01 00:00:01", "1970-01-01 00:00:01"},
+		{"2038-01-19 03:14:07", "2038-01-19 03:14:07"},
+	}
+
+	for _, test := range table {
+		t, err := ParseTimestamp(sc, test.Input)
+		c.Assert(err, IsNil)
+		c.Assert(t.Type(), Equals, mysql.TypeTimestamp)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"2048-12-31 11:30:45",
+		"1969-12-31 23:59:59",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseTimestamp(sc, test)
+		c.Assert(err, NotNil)
+	}
+
+	// The range of timestamp depends on the time zone.
+	sc.TimeZone = time.FixedZone("UTC+8", 8*3600)
+	t, err := ParseTimestamp(sc, "1970-01-01 08:00:01")
+	c.Assert(err, IsNil)
+	c.Assert(t.String(), Equals, "1970-01-01 08:00:01")
+	_, err = ParseTimestamp(sc, "1970-01-01 00:00:01")
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestDate(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"2012-12-31", "2012-12-31"},
+		{"00-12-31", "2000-12-31"},
+		{"20121231", "2012-12-31"},
+		{"121231", "2012-12-31"},
+		{"2015-06-01 12:12:12", "2015-06-01"},
+		{"0001-01-01 00:00:00", "0001-01-01"},
+		{"0001-01-01", "0001-01-01"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDate(sc, test.Input)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+		c.Assert(t.Fsp(), Equals, int8(0))
+		c.Assert(t.Hour(), Equals, 0)
+	}
+
+	errTable := []string{
+		"0121231",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDate(sc, test)
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *testTimeSuite) TestTime(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"10:11:12", "10:11:12"},
+		{"101112", "10:11:12"},
+		{"10:11", "10:11:00"},
+		{"101112.123456", "10:11:12"},
+		{"1112", "00:11:12"},
+		{"12", "00:00:12"},
+		{"1 12", "36:00:00"},
+		{"1 10:11:12", "34:11:12"},
+		{"1 10:11:12.123456", "34:11:12"},
+		{"10:11:12.123456", "10:11:12"},
+		{"-10:11:12", "-10:11:12"},
+		{"-101112", "-10:11:12"},
+		{"2011-11-11 00:00:01", "00:00:01"},
+		{"838:59:59", "838:59:59"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDuration(sc, test.Input, MinFsp)
+		c.Assert(err, IsNil, Commentf("%s", test.Input))
+		c.Assert(t.String(), Equals, test.Expect, Commentf("%s", test.Input))
+	}
+
+	table = []struct {
+		Input  string
+		Expect string
+	}{
+		{"101112.123456", "10:11:12.123456"},
+		{"1 10:11:12.123456", "34:11:12.123456"},
+		{"10:11:12.9999999", "10:11:13.000000"},
+	}
+
+	for _, test := range table {
+		t, err := ParseDuration(sc, test.Input, MaxFsp)
+		c.Assert(err, IsNil)
+		c.Assert(t.String(), Equals, test.Expect)
+	}
+
+	errTable := []string{
+		"236023",
+		"-236023",
+		"10:60:11",
+		"10:11:61",
+	}
+
+	for _, test := range errTable {
+		_, err := ParseDuration(sc, test, DefaultFsp)
+		c.Assert(err, NotNil, Commentf("%s", test))
+	}
+
+	// Values over the limit are truncated.
+	t, err := ParseDuration(sc, "900:00:00", DefaultFsp)
+	c.Assert(ErrTruncatedWrongVal.Equal(err), IsTrue)
+	c.Assert(t.String(), Equals, "838:59:59")
+
+	t, err = ParseDuration(sc, "00:00:00.000000", DefaultFsp)
+	c.Assert(err, IsNil)
+	c.Assert(t.Compare(ZeroDuration), Equals, 0)
+}
+
+func (s *testTimeSuite) TestParseTimeFromNum(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Input                int64
+		ExpectDateTimeError  bool
+		ExpectDateTimeValue  string
+		ExpectTimeStampError bool
+		ExpectTimeStampValue string
+		ExpectDateError      bool
+		ExpectDateValue      string
+	}{
+		{20101010111111, false, "2010-10-10 11:11:11", false, "2010-10-10 11:11:11", false, "2010-10-10"},
+		{2010101011111, false, "0201-01-01 01:11:11", true, ZeroDatetimeStr, false, "0201-01-01"},
+		{201010101111, false, "2020-10-10 10:11:11", false, "2020-10-10 10:11:11", false, "2020-10-10"},
+		{20101010111, false, "2002-01-01 01:01:11", false, "2002-01-01 01:01:11", false, "2002-01-01"},
+		{2010101011, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{201010101, false, "2000-02-01 01:01:01", false, "2000-02-01 01:01:01", false, "2000-02-01"},
+		{20101010, false, "2010-10-10 00:00:00", false, "2010-10-10 00:00:00", false, "2010-10-10"},
+		{2010101, false, "0201-01-01 00:00:00", true, ZeroDatetimeStr, false, "0201-01-01"},
+		{201010, false, "2020-10-10 00:00:00", false, "2020-10-10 00:00:00", false, "2020-10-10"},
+		{20101, false, "2002-01-01 00:00:00", false, "2002-01-01 00:00:00", false, "2002-01-01"},
+		{2010, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{201, false, "2000-02-01 00:00:00", false, "2000-02-01 00:00:00", false, "2000-02-01"},
+		{20, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{2, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{0, false, ZeroDatetimeStr, false, ZeroDatetimeStr, false, ZeroDateStr},
+		{-1, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{99999999999999, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{100000000000000, true, ZeroDatetimeStr, true, ZeroDatetimeStr, true, ZeroDateStr},
+		{10000102000000, false, "1000-01-02 00:00:00", true, ZeroDatetimeStr, false, "1000-01-02"},
+		{19690101000000, false, "1969-01-01 00:00:00", true, ZeroDatetimeStr, false, "1969-01-01"},
+		{991231235959, false, "1999-12-31 23:59:59", false, "1999-12-31 23:59:59", false, "1999-12-31"},
+		{691231235959, false, "2069-12-31 23:59:59", true, ZeroDatetimeStr, false, "2069-12-31"},
+		{370119031407, false, "2037-01-19 03:14:07", false, "2037-01-19 03:14:07", false, "2037-01-19"},
+		{380120031407, false, "2038-01-20 03:14:07", true, ZeroDatetimeStr, false, "2038-01-20"},
+		{11111111111, false, "2001-11-11 11:11:11", false, "2001-11-11 11:11:11", false, "2001-11-11"},
+	}
+
+	for ith, test := range table {
+		// testtypes.ParseDatetimeFromNum
+		t, err := ParseDatetimeFromNum(sc, test.Input)
+		if test.ExpectDateTimeError {
+			c.Assert(err, NotNil, Commentf("%d", ith))
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeDatetime)
+		}
+		c.Assert(t.String(), Equals, test.ExpectDateTimeValue)
+
+		// testtypes.ParseTimestampFromNum
+		t, err = ParseTimestampFromNum(sc, test.Input)
+		if test.ExpectTimeStampError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil, Commentf("%d", ith))
+			c.Assert(t.Type(), Equals, mysql.TypeTimestamp)
+		}
+		c.Assert(t.String(), Equals, test.ExpectTimeStampValue)
+
+		// testtypes.ParseDateFromNum
+		t, err = ParseDateFromNum(sc, test.Input)
+		if test.ExpectDateError {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			c.Assert(t.Type(), Equals, mysql.TypeDate)
+		}
+		c.Assert(t.String(), Equals, test.ExpectDateValue)
+	}
+}
+
+func (s *testTimeSuite) TestNumberToDuration(c *C) {
+	var testCases = []struct {
+		number int64
+		fsp    int
+		hasErr bool
+		year   int
+		month  int
+		day    int
+		hour   int
+		minute int
+		second int
+	}{
+		{20171222, 0, true, 0, 0, 0, 0, 0, 0},
+		{171222, 0, false, 0, 0, 0, 17, 12, 22},
+		{20171222020005, 0, false, 2017, 12, 22, 02, 00, 05},
+		{10000000000, 0, true, 0, 0, 0, 0, 0, 0},
+		{171222, 1, false, 0, 0, 0, 17, 12, 22},
+		{176022, 1, true, 0, 0, 0, 0, 0, 0},
+		{8391222, 1, true, 0, 0, 0, 0, 0, 0},
+		{8381222, 0, false, 0, 0, 0, 838, 12, 22},
+		{1001222, 0, false, 0, 0, 0, 100, 12, 22},
+		{171260, 1, true, 0, 0, 0, 0, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		dur, err := NumberToDuration(tc.number, int8(tc.fsp))
+		if tc.hasErr {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(dur.Hour(), Equals, tc.hour)
+		c.Assert(dur.Minute(), Equals, tc.minute)
+		c.Assert(dur.Second(), Equals, tc.second)
+	}
+
+	var testCases1 = []struct {
+		number int64
+		dur    time.Duration
+	}{
+		{171222, 17*time.Hour + 12*time.Minute + 22*time.Second},
+		{-171222, -(17*time.Hour + 12*time.Minute + 22*time.Second)},
+	}
+
+	for _, tc := range testCases1 {
+		dur, err := NumberToDuration(tc.number, 0)
+		c.Assert(err, IsNil)
+		c.Assert(dur.Duration, Equals, tc.dur)
+	}
+}
+
+func (s *testTimeSuite) TestCompare(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	table := []struct {
+		Arg1 string
+		Arg2 string
+		Ret  int
+	}{
+		{"2011-10-10 11:11:11", "2011-10-10 11:11:11", 0},
+		{"2011-10-10 11:11:11.123456", "2011-10-10 11:11:11.1", 1},
+		{"2011-10-10 11:11:11", "2011-10-10 11:11:11.123", -1},
+		{"0000-00-00 00:00:00", "2011-10-10 11:11:11", -1},
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00", 0},
+	}
+
+	for _, t := range table {
+		v1, err := ParseTime(sc, t.Arg1, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+
+		ret, err := v1.CompareString(nil, t.Arg2)
+		c.Assert(err, IsNil)
+		c.Assert(ret, Equals, t.Ret)
+	}
+
+	v1, err := ParseTime(sc, "2011-10-10 11:11:11", mysql.TypeDatetime, MaxFsp)
+	c.Assert(err, IsNil)
+	res, err := v1.CompareString(nil, "Test should error")
+	c.Assert(err, NotNil)
+	c.Assert(res, Equals, 0)
+
+	// A date compares equal to the datetime at the start of that day.
+	d, err := ParseDate(sc, "2011-10-10")
+	c.Assert(err, IsNil)
+	dt, err := ParseDatetime(sc, "2011-10-10 00:00:00")
+	c.Assert(err, IsNil)
+	c.Assert(d.Compare(dt), Equals, 0)
+
+	table = []struct {
+		Arg1 string
+		Arg2 string
+		Ret  int
+	}{
+		{"11:11:11", "11:11:11", 0},
+		{"11:11:11.123456", "11:11:11.1", 1},
+		{"11:11:11", "11:11:11.123", -1},
+		{"-11:11:11", "11:11:11", -1},
+	}
+
+	for _, t := range table {
+		v1, err := ParseDuration(nil, t.Arg1, MaxFsp)
+		c.Assert(err, IsNil)
+
+		ret, err := v1.CompareString(nil, t.Arg2)
+		c.Assert(err, IsNil)
+		c.Assert(ret, Equals, t.Ret)
+	}
+}
+
+func (s *testTimeSuite) TestRoundFrac(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC, IgnoreZeroInDate: true}
+	tbl := []struct {
+		Input  string
+		Fsp    int8
+		Except string
+	}{
+		{"2012-12-31 11:30:45.123456", 4, "2012-12-31 11:30:45.1235"},
+		{"2012-12-31 11:30:45.123456", 6, "2012-12-31 11:30:45.123456"},
+		{"2012-12-31 11:30:45.123456", 0, "2012-12-31 11:30:45"},
+		{"2012-12-31 11:30:45.123456", 1, "2012-12-31 11:30:45.1"},
+		{"2012-12-31 11:30:45.999999", 4, "2012-12-31 11:30:46.0000"},
+		{"2012-12-31 11:30:45.999999", 0, "2012-12-31 11:30:46"},
+		{"2012-00-00 11:30:45.999999", 3, "2012-00-00 11:30:46.000"},
+		{"2011-11-11 10:10:10.888888", 0, "2011-11-11 10:10:11"},
+		{"0000-00-00 00:00:00.000000", 3, "0000-00-00 00:00:00.000000"},
+	}
+
+	for _, t := range tbl {
+		v, err := ParseTime(sc, t.Input, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+		nv, err := v.RoundFrac(sc, t.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(nv.String(), Equals, t.Except)
+	}
+
+	tbl = []struct {
+		Input  string
+		Fsp    int8
+		Except string
+	}{
+		{"11:30:45.123456", 4, "11:30:45.1235"},
+		{"11:30:45.123456", 6, "11:30:45.123456"},
+		{"11:30:45.123456", 0, "11:30:45"},
+		{"11:59:59.999999", 3, "12:00:00.000"},
+		{"1 11:30:45.123456", 1, "35:30:45.1"},
+		{"1 11:30:45.999999", 4, "35:30:46.0000"},
+		{"-1 11:30:45.999999", 0, "-35:30:46"},
+	}
+
+	for _, t := range tbl {
+		v, err := ParseDuration(sc, t.Input, MaxFsp)
+		c.Assert(err, IsNil)
+		nv, err := v.RoundFrac(t.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(nv.String(), Equals, t.Except)
+	}
+}
+
+func (s *testTimeSuite) TestConvert(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tbl := []struct {
+		Input  string
+		Fsp    int8
+		Except string
+	}{
+		{"2012-12-31 11:30:45.123456", 4, "11:30:45.1235"},
+		{"2012-12-31 11:30:45.123456", 6, "11:30:45.123456"},
+		{"2012-12-31 11:30:45.123456", 0, "11:30:45"},
+		{"2012-12-31 11:30:45.999999", 0, "11:30:46"},
+		{"2017-01-05 08:40:59.575601", 0, "08:41:00"},
+		{"2017-01-05 23:59:59.575601", 0, "00:00:00"},
+		{"0000-00-00 00:00:00", 6, "00:00:00"},
+	}
+
+	for _, t := range tbl {
+		v, err := ParseTime(sc, t.Input, mysql.TypeDatetime, t.Fsp)
+		c.Assert(err, IsNil)
+		nv, err := v.ConvertToDuration()
+		c.Assert(err, IsNil)
+		c.Assert(nv.String(), Equals, t.Except)
+	}
+
+	v, err := ParseTime(sc, "2012-12-31 11:30:45", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	d, err := v.Convert(sc, mysql.TypeDate)
+	c.Assert(err, IsNil)
+	c.Assert(d.String(), Equals, "2012-12-31")
+	dt, err := d.Convert(sc, mysql.TypeDatetime)
+	c.Assert(err, IsNil)
+	c.Assert(dt.String(), Equals, "2012-12-31 00:00:00")
+
+	// Converting a datetime out of the timestamp range fails.
+	v, err = ParseTime(sc, "1000-01-01 00:00:00", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	_, err = v.Convert(sc, mysql.TypeTimestamp)
+	c.Assert(err, NotNil)
+
+	// Durations are converted with the current date.
+	dur, err := ParseDuration(sc, "11:30:45", 0)
+	c.Assert(err, IsNil)
+	t, err := dur.ConvertToTime(sc, mysql.TypeDatetime)
+	c.Assert(err, IsNil)
+	year, month, day := time.Now().In(sc.TimeZone).Date()
+	c.Assert(t.Year(), Equals, year)
+	c.Assert(t.Month(), Equals, int(month))
+	c.Assert(t.Day(), Equals, day)
+	c.Assert(t.Hour(), Equals, 11)
+	c.Assert(t.Minute(), Equals, 30)
+	c.Assert(t.Second(), Equals, 45)
+}
+
+func (s *testTimeSuite) TestToNumber(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tblDateTime := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"12-12-31 11:30:45", 0, "20121231113045"},
+		{"12-12-31 11:30:45", 6, "20121231113045.000000"},
+		{"12-12-31 11:30:45.123", 6, "20121231113045.123000"},
+		{"12-12-31 11:30:45.123345", 0, "20121231113045"},
+		{"12-12-31 11:30:45.123345", 3, "20121231113045.123"},
+		{"12-12-31 11:30:45.123345", 5, "20121231113045.12335"},
+		{"1-1-1 1:1:1.123345", 5, "20010101010101.12335"},
+	}
+
+	for _, t := range tblDateTime {
+		v, err := ParseTime(sc, t.Input, mysql.TypeDatetime, t.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(v.ToNumber().String(), Equals, t.Expect)
+	}
+
+	// Fix issue #1046
+	tblDate := []struct {
+		Input  string
+		Expect string
+	}{
+		{"12-12-31 11:30:45", "20121231"},
+		{"2012-12-31", "20121231"},
+	}
+
+	for _, t := range tblDate {
+		v, err := ParseTime(sc, t.Input, mysql.TypeDate, 0)
+		c.Assert(err, IsNil)
+		c.Assert(v.ToNumber().String(), Equals, t.Expect)
+	}
+
+	tblDuration := []struct {
+		Input  string
+		Fsp    int8
+		Expect string
+	}{
+		{"11:30:45", 0, "113045"},
+		{"11:30:45", 6, "113045.000000"},
+		{"11:30:45.123", 6, "113045.123000"},
+		{"11:30:45.123345", 3, "113045.123"},
+		{"-11:30:45.123345", 5, "-113045.12335"},
+		{"1 11:30:45", 0, "353045"},
+	}
+
+	for _, t := range tblDuration {
+		v, err := ParseDuration(sc, t.Input, t.Fsp)
+		c.Assert(err, IsNil)
+		c.Assert(v.ToNumber().String(), Equals, t.Expect)
+	}
+}
+
+func (s *testTimeSuite) TestConvertTimeZone(c *C) {
+	defer testleak.AfterTest(c)()
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	tests := []struct {
+		input  Time
+		from   *time.Location
+		to     *time.Location
+		expect Time
+	}{
+		{NewTime(FromDate(2017, 1, 1, 0, 0, 0, 0), mysql.TypeDatetime, 0), time.UTC, loc, NewTime(FromDate(2017, 1, 1, 8, 0, 0, 0), mysql.TypeDatetime, 0)},
+		{NewTime(FromDate(2017, 1, 1, 8, 0, 0, 0), mysql.TypeDatetime, 0), loc, time.UTC, NewTime(FromDate(2017, 1, 1, 0, 0, 0, 0), mysql.TypeDatetime, 0)},
+		{NewTime(FromDate(0, 0, 0, 0, 0, 0, 0), mysql.TypeDatetime, 0), loc, time.UTC, NewTime(FromDate(0, 0, 0, 0, 0, 0, 0), mysql.TypeDatetime, 0)},
+		{NewTime(FromDate(2017, 12, 31, 20, 0, 0, 123), mysql.TypeTimestamp, 6), time.UTC, loc, NewTime(FromDate(2018, 1, 1, 4, 0, 0, 123), mysql.TypeTimestamp, 6)},
+	}
+
+	for ith, test := range tests {
+		t := test.input
+		err := t.ConvertTimeZone(test.from, test.to)
+		c.Assert(err, IsNil)
+		c.Assert(t.Compare(test.expect), Equals, 0, Commentf("%d", ith))
+		c.Assert(t.Type(), Equals, test.input.Type())
+		c.Assert(t.Fsp(), Equals, test.input.Fsp())
+	}
+}
+
+func (s *testTimeSuite) TestTimeFspTp(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		tp  uint8
+		fsp int8
+	}{
+		{mysql.TypeDatetime, 0},
+		{mysql.TypeDatetime, 6},
+		{mysql.TypeTimestamp, 0},
+		{mysql.TypeTimestamp, 3},
+		{mysql.TypeTimestamp, 6},
+		{mysql.TypeDate, 0},
+	}
+	ct := FromDate(2020, 2, 29, 23, 59, 59, 999999)
+	for _, t := range tbl {
+		tm := NewTime(ct, t.tp, t.fsp)
+		c.Assert(tm.Type(), Equals, t.tp)
+		c.Assert(tm.Fsp(), Equals, t.fsp)
+		c.Assert(tm.Year(), Equals, 2020)
+		c.Assert(tm.Month(), Equals, 2)
+		c.Assert(tm.Day(), Equals, 29)
+		c.Assert(tm.Microsecond(), Equals, 999999)
+	}
+
+	tm := NewTime(ct, mysql.TypeDatetime, 3)
+	tm.SetType(mysql.TypeTimestamp)
+	c.Assert(tm.Type(), Equals, mysql.TypeTimestamp)
+	c.Assert(tm.Fsp(), Equals, int8(3))
+	tm.SetFsp(6)
+	c.Assert(tm.Fsp(), Equals, int8(6))
+	tm.SetType(mysql.TypeDate)
+	c.Assert(tm.Type(), Equals, mysql.TypeDate)
+	c.Assert(tm.String(), Equals, "2020-02-29")
+	c.Assert(tm.Hour(), Equals, 0)
+}
+
+func (s *testTimeSuite) TestToPackedUint(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tbl := []string{
+		"0000-00-00 00:00:00",
+		"0001-01-01 00:00:00",
+		"1000-01-01 00:00:00.000001",
+		"2012-12-31 11:30:45.123456",
+		"2012-12-31 11:30:45.999999",
+		"2012-12-31 11:30:46",
+		"9999-12-31 23:59:59.999999",
+	}
+
+	var prev uint64
+	for i, str := range tbl {
+		t, err := ParseTime(sc, str, mysql.TypeDatetime, MaxFsp)
+		c.Assert(err, IsNil)
+		packed, err := t.ToPackedUint()
+		c.Assert(err, IsNil)
+		if i > 0 {
+			c.Assert(packed > prev, IsTrue)
+		}
+		prev = packed
+
+		v := NewTime(ZeroCoreTime, mysql.TypeDatetime, MaxFsp)
+		err = v.FromPackedUint(packed)
+		c.Assert(err, IsNil)
+		c.Assert(v.String(), Equals, t.String())
+	}
+}
+
+func (s *testTimeSuite) TestDateFormat(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTime(sc, "2010-01-07 23:12:34.12345", mysql.TypeDatetime, MaxFsp)
+	c.Assert(err, IsNil)
+	str, err := t.DateFormat(`%b %M %m %c %D %d %e %j %k %h %i %p %r %T %s %f %U %u %V %v %a %W %w %X %x %Y %y %%`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, `Jan January 01 1 7th 07 7 007 23 11 12 PM 11:12:34 PM 23:12:34 34 123450 01 01 01 01 Thu Thursday 4 2010 2010 2010 10 %`)
+
+	t, err = ParseTime(sc, "2012-12-21 00:00:00", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	str, err = t.DateFormat(`%H %I %l %p %r %U %W %y %j`)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, `00 12 12 AM 12:00:00 AM 51 Friday 12 356`)
+
+	t, err = ParseTime(sc, "0000-00-00 00:00:00", mysql.TypeDatetime, 0)
+	c.Assert(err, IsNil)
+	_, err = t.DateFormat(`%M`)
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestParseDurationValue(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		unit   string
+		format string
+		res    [4]int64
+		hasErr bool
+	}{
+		{"MICROSECOND", "50", [4]int64{0, 0, 0, int64(50 * time.Microsecond)}, false},
+		{"SECOND", "1.5", [4]int64{0, 0, 0, int64(1500 * time.Millisecond)}, false},
+		{"SECOND", "-10", [4]int64{0, 0, 0, int64(-10 * time.Second)}, false},
+		{"MINUTE", "10", [4]int64{0, 0, 0, int64(10 * time.Minute)}, false},
+		{"HOUR", "10", [4]int64{0, 0, 0, int64(10 * time.Hour)}, false},
+		{"DAY", "10", [4]int64{0, 0, 10, 0}, false},
+		{"DAY", "1.5", [4]int64{0, 0, 2, 0}, false},
+		{"WEEK", "2", [4]int64{0, 0, 14, 0}, false},
+		{"MONTH", "3", [4]int64{0, 3, 0, 0}, false},
+		{"QUARTER", "2", [4]int64{0, 6, 0, 0}, false},
+		{"YEAR", "-1", [4]int64{-1, 0, 0, 0}, false},
+		{"SECOND_MICROSECOND", "10.5", [4]int64{0, 0, 0, int64(10*time.Second + 5*time.Microsecond)}, false},
+		{"MINUTE_SECOND", "10:20", [4]int64{0, 0, 0, int64(10*time.Minute + 20*time.Second)}, false},
+		{"HOUR_SECOND", "20", [4]int64{0, 0, 0, int64(20 * time.Second)}, false},
+		{"HOUR_MINUTE", "-1:30", [4]int64{0, 0, 0, int64(-90 * time.Minute)}, false},
+		{"DAY_HOUR", "1 2", [4]int64{0, 0, 1, int64(2 * time.Hour)}, false},
+		{"DAY_SECOND", "1 1:1:1", [4]int64{0, 0, 1, int64(time.Hour + time.Minute + time.Second)}, false},
+		{"YEAR_MONTH", "1-2", [4]int64{1, 2, 0, 0}, false},
+		{"YEAR_MONTH", "1-2-3", [4]int64{}, true},
+		{"DAY", "abc", [4]int64{}, true},
+	}
+	for _, t := range tbl {
+		y, m, d, n, err := ParseDurationValue(t.unit, t.format)
+		if t.hasErr {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert([4]int64{y, m, d, n}, Equals, t.res, Commentf("%s %s", t.unit, t.format))
+	}
+}
+
+func (s *testTimeSuite) TestExtractNum(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	t, err := ParseTime(sc, "2011-11-11 10:10:10.123456", mysql.TypeDatetime, MaxFsp)
+	c.Assert(err, IsNil)
+	tbl := []struct {
+		unit   string
+		expect int64
+	}{
+		{"DAY", 11},
+		{"WEEK", 45},
+		{"MONTH", 11},
+		{"QUARTER", 4},
+		{"YEAR", 2011},
+		{"DAY_MICROSECOND", 11101010123456},
+		{"DAY_SECOND", 11101010},
+		{"DAY_MINUTE", 111010},
+		{"DAY_HOUR", 1110},
+		{"YEAR_MONTH", 201111},
+	}
+	for _, tt := range tbl {
+		res, err := ExtractDatetimeNum(&t, tt.unit)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, tt.expect, Commentf("%s", tt.unit))
+	}
+	_, err = ExtractDatetimeNum(&t, "TEST_ERROR")
+	c.Assert(err, NotNil)
+
+	dur, err := ParseDuration(sc, "-10:10:10.123456", MaxFsp)
+	c.Assert(err, IsNil)
+	durTbl := []struct {
+		unit   string
+		expect int64
+	}{
+		{"MICROSECOND", -123456},
+		{"SECOND", -10},
+		{"MINUTE", -10},
+		{"HOUR", -10},
+		{"SECOND_MICROSECOND", -10123456},
+		{"MINUTE_SECOND", -1010},
+		{"HOUR_MINUTE", -1010},
+		{"HOUR_SECOND", -101010},
+	}
+	for _, tt := range durTbl {
+		res, err := ExtractDurationNum(&dur, tt.unit)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, tt.expect, Commentf("%s", tt.unit))
+	}
+}
+
+func (s *testTimeSuite) TestWeekdayAndYearDay(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		ct      CoreTime
+		weekday time.Weekday
+		yearDay int
+	}{
+		{FromDate(2000, 1, 1, 0, 0, 0, 0), time.Saturday, 1},
+		{FromDate(2019, 12, 31, 0, 0, 0, 0), time.Tuesday, 365},
+		{FromDate(2020, 12, 31, 0, 0, 0, 0), time.Thursday, 366},
+		{FromDate(1, 1, 1, 0, 0, 0, 0), time.Monday, 1},
+	}
+	for _, t := range tbl {
+		c.Assert(t.ct.Weekday(), Equals, t.weekday)
+		c.Assert(t.ct.YearDay(), Equals, t.yearDay)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 )
 
 var msgErrSelNotNil = "The selection vector of Chunk is not nil. Please file a bug to the TiDB Team"
@@ -355,6 +356,27 @@ func (c *Chunk) AppendPartialRow(colIdx int, row Row) {
 	}
 }
 
+// AppendPartialRowByColIdxs appends the columns of a row specified by colIdxs to the chunk,
+// the columns are appended from the colOff-th column of the chunk.
+func (c *Chunk) AppendPartialRowByColIdxs(colOff int, row Row, colIdxs []int) {
+	c.appendSel(colOff)
+	for i, colIdx := range colIdxs {
+		rowCol := row.c.columns[colIdx]
+		chkCol := c.columns[colOff+i]
+		chkCol.appendNullBitmap(!rowCol.IsNull(row.idx))
+		if rowCol.isFixed() {
+			elemLen := len(rowCol.elemBuf)
+			offset := row.idx * elemLen
+			chkCol.data = append(chkCol.data, rowCol.data[offset:offset+elemLen]...)
+		} else {
+			start, end := rowCol.offsets[row.idx], rowCol.offsets[row.idx+1]
+			chkCol.data = append(chkCol.data, rowCol.data[start:end]...)
+			chkCol.offsets = append(chkCol.offsets, int64(len(chkCol.data)))
+		}
+		chkCol.length++
+	}
+}
+
 // preAlloc pre-allocates the memory space in a Chunk to store the Row.
 // NOTE: only used in test.
 // 1. The Chunk must be empty or holds no useful data.
@@ -518,6 +540,24 @@ func (c *Chunk) AppendFloat64(colIdx int, f float64) {
 	c.columns[colIdx].AppendFloat64(f)
 }
 
+// AppendMyDecimal appends a MyDecimal value to the chunk.
+func (c *Chunk) AppendMyDecimal(colIdx int, dec *types.MyDecimal) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendMyDecimal(dec)
+}
+
+// AppendTime appends a Time value to the chunk.
+func (c *Chunk) AppendTime(colIdx int, t types.Time) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendTime(t)
+}
+
+// AppendDuration appends a Duration value to the chunk.
+func (c *Chunk) AppendDuration(colIdx int, dur types.Duration) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendDuration(dur)
+}
+
 // AppendString appends a string value to the chunk.
 func (c *Chunk) AppendString(colIdx int, str string) {
 	c.appendSel(colIdx)
@@ -530,6 +570,12 @@ func (c *Chunk) AppendBytes(colIdx int, b []byte) {
 	c.columns[colIdx].AppendBytes(b)
 }
 
+// AppendJSON appends a JSON value to the chunk.
+func (c *Chunk) AppendJSON(colIdx int, j json.BinaryJSON) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendJSON(j)
+}
+
 func (c *Chunk) appendSel(colIdx int) {
 	if colIdx == 0 && c.sel != nil { // use column 0 as standard
 		c.sel = append(c.sel, c.columns[0].length)
@@ -551,6 +597,14 @@ func (c *Chunk) AppendDatum(colIdx int, d *types.Datum) {
 		c.AppendFloat64(colIdx, d.GetFloat64())
 	case types.KindString, types.KindBytes:
 		c.AppendBytes(colIdx, d.GetBytes())
+	case types.KindMysqlDecimal:
+		c.AppendMyDecimal(colIdx, d.GetMysqlDecimal())
+	case types.KindMysqlDuration:
+		c.AppendDuration(colIdx, d.GetMysqlDuration())
+	case types.KindMysqlTime:
+		c.AppendTime(colIdx, d.GetMysqlTime())
+	case types.KindMysqlJSON:
+		c.AppendJSON(colIdx, d.GetMysqlJSON())
 	}
 }
 
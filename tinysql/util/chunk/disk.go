@@ -0,0 +1,156 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+const writeBufSize = 128 * 1024
+
+var chunkListInDiskLabel fmt.Stringer = stringutil.StringerStr("chunk.ListInDisk")
+
+// ListInDisk represents a slice of chunks storing in a temporary file on disk.
+// The chunks are encoded by Codec and can only be appended, they are read
+// back by their index in the list.
+type ListInDisk struct {
+	fieldTypes []*types.FieldType
+	codec      *Codec
+	length     int
+	// offsets stores the offset of each chunk in the file, the last element
+	// is the offset of the end of the file.
+	offsets []int64
+	numRows []int
+
+	disk        *os.File
+	bufWriter   *bufio.Writer
+	diskTracker *memory.Tracker // track disk usage.
+}
+
+// NewListInDisk creates a new ListInDisk with field types. The temporary
+// file is created lazily when the first chunk is added.
+func NewListInDisk(fieldTypes []*types.FieldType) *ListInDisk {
+	return &ListInDisk{
+		fieldTypes:  fieldTypes,
+		codec:       NewCodec(fieldTypes),
+		offsets:     []int64{0},
+		diskTracker: memory.NewTracker(chunkListInDiskLabel, -1),
+	}
+}
+
+func (l *ListInDisk) initDiskFile() (err error) {
+	dir := config.GetGlobalConfig().TempStoragePath
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	l.disk, err = ioutil.TempFile(dir, "chunk.ListInDisk")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	l.bufWriter = bufio.NewWriterSize(l.disk, writeBufSize)
+	return nil
+}
+
+// GetDiskTracker returns the disk usage tracker of this ListInDisk.
+func (l *ListInDisk) GetDiskTracker() *memory.Tracker {
+	return l.diskTracker
+}
+
+// Len returns the number of rows in the ListInDisk.
+func (l *ListInDisk) Len() int {
+	return l.length
+}
+
+// NumChunks returns the number of chunks in the ListInDisk.
+func (l *ListInDisk) NumChunks() int {
+	return len(l.numRows)
+}
+
+// NumRowsOfChunk returns the number of rows of the chunk specified by chkIdx.
+func (l *ListInDisk) NumRowsOfChunk(chkIdx int) int {
+	return l.numRows[chkIdx]
+}
+
+// Add encodes the chunk and appends it to the end of the file, the chunk can
+// be reused by the caller after Add returns.
+func (l *ListInDisk) Add(chk *Chunk) (err error) {
+	if chk.NumRows() == 0 {
+		return errors.New("chunk appended to ListInDisk should have at least 1 row")
+	}
+	if l.disk == nil {
+		if err = l.initDiskFile(); err != nil {
+			return
+		}
+	}
+	data := l.codec.Encode(chk)
+	n, err := l.bufWriter.Write(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	l.offsets = append(l.offsets, l.offsets[len(l.offsets)-1]+int64(n))
+	l.numRows = append(l.numRows, chk.NumRows())
+	l.length += chk.NumRows()
+	l.diskTracker.Consume(int64(n))
+	return nil
+}
+
+// GetChunk reads the chunk specified by chkIdx from the file.
+func (l *ListInDisk) GetChunk(chkIdx int) (*Chunk, error) {
+	if l.bufWriter.Buffered() > 0 {
+		if err := l.bufWriter.Flush(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	off := l.offsets[chkIdx]
+	data := make([]byte, l.offsets[chkIdx+1]-off)
+	if _, err := l.disk.ReadAt(data, off); err != nil {
+		return nil, errors.Trace(err)
+	}
+	chk, _ := l.codec.Decode(data)
+	return chk, nil
+}
+
+// GetRow gets a Row from the ListInDisk by RowPtr. It reads the whole chunk
+// containing the row, callers reading rows in order should use GetChunk.
+func (l *ListInDisk) GetRow(ptr RowPtr) (row Row, err error) {
+	chk, err := l.GetChunk(int(ptr.ChkIdx))
+	if err != nil {
+		return row, err
+	}
+	return chk.GetRow(int(ptr.RowIdx)), nil
+}
+
+// Close releases the disk resource.
+func (l *ListInDisk) Close() error {
+	if l.disk == nil {
+		return nil
+	}
+	l.diskTracker.Consume(-l.diskTracker.BytesConsumed())
+	closeErr := l.disk.Close()
+	removeErr := os.Remove(l.disk.Name())
+	l.disk = nil
+	if closeErr != nil {
+		return errors.Trace(closeErr)
+	}
+	return errors.Trace(removeErr)
+}
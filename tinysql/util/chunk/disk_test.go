@@ -0,0 +1,74 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+func (s *testChunkSuite) TestListInDisk(c *check.C) {
+	fields := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+		types.NewFieldType(mysql.TypeVarchar),
+	}
+	l := NewListInDisk(fields)
+	c.Assert(l.Close(), check.IsNil)
+
+	numChk, numRow := 5, 10
+	for i := 0; i < numChk; i++ {
+		chk := NewChunkWithCapacity(fields, numRow)
+		for j := 0; j < numRow; j++ {
+			if j%3 == 0 {
+				chk.AppendNull(0)
+			} else {
+				chk.AppendInt64(0, int64(i*numRow+j))
+			}
+			chk.AppendString(1, strconv.Itoa(i*numRow+j))
+		}
+		c.Assert(l.Add(chk), check.IsNil)
+	}
+	c.Assert(l.Add(NewChunkWithCapacity(fields, 1)), check.NotNil)
+	c.Assert(l.NumChunks(), check.Equals, numChk)
+	c.Assert(l.Len(), check.Equals, numChk*numRow)
+	c.Assert(l.GetDiskTracker().BytesConsumed() > 0, check.IsTrue)
+	name := l.disk.Name()
+
+	for i := numChk - 1; i >= 0; i-- {
+		c.Assert(l.NumRowsOfChunk(i), check.Equals, numRow)
+		chk, err := l.GetChunk(i)
+		c.Assert(err, check.IsNil)
+		c.Assert(chk.NumRows(), check.Equals, numRow)
+		for j := 0; j < numRow; j++ {
+			row := chk.GetRow(j)
+			c.Assert(row.IsNull(0), check.Equals, j%3 == 0)
+			if j%3 != 0 {
+				c.Assert(row.GetInt64(0), check.Equals, int64(i*numRow+j))
+			}
+			c.Assert(row.GetString(1), check.Equals, strconv.Itoa(i*numRow+j))
+		}
+	}
+	row, err := l.GetRow(RowPtr{ChkIdx: 2, RowIdx: 4})
+	c.Assert(err, check.IsNil)
+	c.Assert(row.GetString(1), check.Equals, "24")
+
+	c.Assert(l.Close(), check.IsNil)
+	c.Assert(l.GetDiskTracker().BytesConsumed(), check.Equals, int64(0))
+	_, err = os.Stat(name)
+	c.Assert(os.IsNotExist(err), check.IsTrue)
+}
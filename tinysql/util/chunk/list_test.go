@@ -77,6 +77,39 @@ func (s *testChunkSuite) TestList(c *check.C) {
 	c.Assert(results, check.DeepEquals, expected)
 }
 
+func (s *testChunkSuite) TestListMemoryUsage(c *check.C) {
+	fields := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+	}
+	l := NewList(fields, 2, 2)
+	srcChunk := NewChunkWithCapacity(fields, 32)
+	srcChunk.AppendInt64(0, 1)
+	srcRow := srcChunk.GetRow(0)
+
+	// The chunk being appended is consumed when it is full.
+	for i := 0; i < 5; i++ {
+		l.AppendRow(srcRow)
+	}
+	expected := l.GetChunk(0).MemoryUsage() + l.GetChunk(1).MemoryUsage()
+	c.Assert(l.GetMemTracker().BytesConsumed(), check.Equals, expected)
+
+	// The chunks in the freelist are still consumed.
+	l.Reset()
+	expected += l.freelist[2].MemoryUsage()
+	c.Assert(l.GetMemTracker().BytesConsumed(), check.Equals, expected)
+
+	// Reusing a chunk in the freelist releases its memory.
+	reused := l.freelist[2].MemoryUsage()
+	l.AppendRow(srcRow)
+	c.Assert(l.GetMemTracker().BytesConsumed(), check.Equals, expected-reused)
+
+	nChunk := NewChunkWithCapacity(fields, 32)
+	nChunk.AppendInt64(0, 1)
+	l.Add(nChunk)
+	expected += l.GetChunk(0).MemoryUsage() - reused + nChunk.MemoryUsage()
+	c.Assert(l.GetMemTracker().BytesConsumed(), check.Equals, expected)
+}
+
 func (s *testChunkSuite) TestListPrePreAlloc4RowAndInsert(c *check.C) {
 	fieldTypes := make([]*types.FieldType, 0, 3)
 	fieldTypes = append(fieldTypes, &types.FieldType{Tp: mysql.TypeFloat})
@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"testing"
 )
 
@@ -63,6 +64,7 @@ var allTypes = []*types.FieldType{
 		Elems:   []string{"a", "b"},
 	},
 	types.NewFieldType(mysql.TypeBit),
+	types.NewFieldType(mysql.TypeJSON),
 }
 
 func (s *testChunkSuite) TestMutRow(c *check.C) {
@@ -107,6 +109,18 @@ func (s *testChunkSuite) TestMutRow(c *check.C) {
 	row = mutRow.ToRow()
 	c.Assert(row.IsNull(0), check.IsTrue)
 	c.Assert(row.IsNull(1), check.IsFalse)
+
+	j, err := json.ParseBinaryFromString(`{"a": [1, "b"]}`)
+	c.Assert(err, check.IsNil)
+	mutRow = MutRowFromTypes([]*types.FieldType{types.NewFieldType(mysql.TypeJSON)})
+	mutRow.SetValue(0, j)
+	row = mutRow.ToRow()
+	c.Assert(row.GetJSON(0).String(), check.Equals, j.String())
+	mutRow.SetDatum(0, types.NewJSONDatum(json.CreateBinary("x")))
+	c.Assert(row.GetJSON(0).String(), check.Equals, `"x"`)
+	d := row.GetDatum(0, types.NewFieldType(mysql.TypeJSON))
+	c.Assert(d.GetMysqlJSON().String(), check.Equals, `"x"`)
+	c.Assert(MutRowFromValues(j).ToRow().GetJSON(0).String(), check.Equals, j.String())
 }
 
 func BenchmarkMutRowSetDatums(b *testing.B) {
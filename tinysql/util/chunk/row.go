@@ -16,6 +16,7 @@ package chunk
 import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 )
 
 // Row represents a row of data, can be used to access values.
@@ -64,6 +65,21 @@ func (r Row) GetFloat64(colIdx int) float64 {
 	return r.c.columns[colIdx].GetFloat64(r.idx)
 }
 
+// GetMyDecimal returns the MyDecimal value with the colIdx.
+func (r Row) GetMyDecimal(colIdx int) *types.MyDecimal {
+	return r.c.columns[colIdx].GetDecimal(r.idx)
+}
+
+// GetTime returns the Time value with the colIdx.
+func (r Row) GetTime(colIdx int) types.Time {
+	return r.c.columns[colIdx].GetTime(r.idx)
+}
+
+// GetDuration returns the Duration value with the colIdx.
+func (r Row) GetDuration(colIdx int, fillFsp int) types.Duration {
+	return r.c.columns[colIdx].GetDuration(r.idx, fillFsp)
+}
+
 // GetString returns the string value with the colIdx.
 func (r Row) GetString(colIdx int) string {
 	return r.c.columns[colIdx].GetString(r.idx)
@@ -116,10 +132,41 @@ func (r Row) GetDatum(colIdx int, tp *types.FieldType) types.Datum {
 		if !r.IsNull(colIdx) {
 			d.SetBytes(r.GetBytes(colIdx))
 		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		if !r.IsNull(colIdx) {
+			d.SetMysqlTime(r.GetTime(colIdx))
+		}
+	case mysql.TypeDuration:
+		if !r.IsNull(colIdx) {
+			duration := r.GetDuration(colIdx, tp.Decimal)
+			d.SetMysqlDuration(duration)
+		}
+	case mysql.TypeNewDecimal:
+		if !r.IsNull(colIdx) {
+			d.SetMysqlDecimal(r.GetMyDecimal(colIdx))
+			d.SetLength(tp.Flen)
+			// If tp.Decimal is unspecified(-1), we should set it to the real
+			// fraction length of the decimal value, otherwise the frac of the
+			// datum would overflow and break the encoding.
+			if tp.Decimal == types.UnspecifiedLength {
+				d.SetFrac(int(d.GetMysqlDecimal().GetDigitsFrac()))
+			} else {
+				d.SetFrac(tp.Decimal)
+			}
+		}
+	case mysql.TypeJSON:
+		if !r.IsNull(colIdx) {
+			d.SetMysqlJSON(r.GetJSON(colIdx))
+		}
 	}
 	return d
 }
 
+// GetJSON returns the JSON value with the colIdx.
+func (r Row) GetJSON(colIdx int) json.BinaryJSON {
+	return r.c.columns[colIdx].GetJSON(r.idx)
+}
+
 // GetRaw returns the underlying raw bytes with the colIdx.
 func (r Row) GetRaw(colIdx int) []byte {
 	return r.c.columns[colIdx].GetRaw(r.idx)
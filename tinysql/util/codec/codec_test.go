@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -441,6 +442,266 @@ func (s *testCodecSuite) TestFloatCodec(c *C) {
 	}
 }
 
+func (s *testCodecSuite) TestDecimal(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []string{
+		"1234.00",
+		"1234",
+		"12.34",
+		"12.340",
+		"0.1234",
+		"0.0",
+		"0",
+		"-0.0",
+		"-0.0000",
+		"-1234.00",
+		"-1234",
+		"-12.34",
+		"-12.340",
+		"-0.1234",
+	}
+
+	for _, t := range tbl {
+		dec := new(types.MyDecimal)
+		err := dec.FromString([]byte(t))
+		c.Assert(err, IsNil)
+		b, err := EncodeDecimal(nil, dec, 0, 0)
+		c.Assert(err, IsNil)
+		_, v, _, _, err := DecodeDecimal(b)
+		c.Assert(err, IsNil)
+		c.Assert(v.Compare(dec), Equals, 0)
+	}
+
+	tblCmp := []struct {
+		Arg1 interface{}
+		Arg2 interface{}
+		Ret  int
+	}{
+		// Test for float type decimal.
+		{"1234", "123400", -1},
+		{"12340", "123400", -1},
+		{"1234", "1234.5", -1},
+		{"1234", "1234.0000", 0},
+		{"1234", "12.34", 1},
+		{"12.34", "12.35", -1},
+		{"0.12", "0.1234", -1},
+		{"0.1234", "12.3400", -1},
+		{"0.1234", "0.1235", -1},
+		{"0.123400", "12.34", -1},
+		{"12.34000", "12.34", 0},
+		{"0.01234", "0.01235", -1},
+		{"0.1234", "0", 1},
+		{"0.0000", "0", 0},
+		{"0.0001", "0", 1},
+		{"0.0001", "0.0000", 1},
+		{"0", "-0.0000", 0},
+		{"-0.0001", "0", -1},
+		{"-0.1234", "0", -1},
+		{"-0.1234", "-0.12", -1},
+		{"-0.12", "-0.1234", 1},
+		{"-0.12", "-0.1200", 0},
+		{"-0.1234", "0.1234", -1},
+		{"-1.234", "-12.34", 1},
+		{"-0.1234", "-12.34", 1},
+		{"-12.34", "1234", -1},
+		{"-12.34", "-12.35", 1},
+		{"-0.01234", "-0.01235", 1},
+		{"-1234", "-123400", 1},
+		{"-12340", "-123400", 1},
+
+		// Test for int type decimal.
+		{int64(-1), int64(1), -1},
+		{int64(math.MaxInt64), int64(math.MinInt64), 1},
+		{int64(math.MaxInt64), int64(math.MaxInt32), 1},
+		{int64(math.MinInt32), int64(math.MaxInt16), -1},
+		{int64(math.MinInt64), int64(math.MaxInt8), -1},
+		{int64(0), int64(math.MaxInt8), -1},
+		{int64(math.MinInt8), int64(0), -1},
+		{int64(math.MinInt16), int64(math.MaxInt16), -1},
+		{int64(1), int64(-1), 1},
+		{int64(1), int64(0), 1},
+		{int64(-1), int64(0), -1},
+		{int64(0), int64(0), 0},
+		{int64(math.MaxInt16), int64(math.MaxInt16), 0},
+
+		// Test for uint type decimal.
+		{uint64(0), uint64(0), 0},
+		{uint64(1), uint64(0), 1},
+		{uint64(0), uint64(1), -1},
+		{uint64(math.MaxInt8), uint64(math.MaxInt16), -1},
+		{uint64(math.MaxUint32), uint64(math.MaxInt32), 1},
+		{uint64(math.MaxUint8), uint64(math.MaxInt8), 1},
+		{uint64(math.MaxUint16), uint64(math.MaxInt32), -1},
+		{uint64(math.MaxUint64), uint64(math.MaxInt64), 1},
+		{uint64(math.MaxInt64), uint64(math.MaxUint32), 1},
+		{uint64(math.MaxUint64), uint64(0), 1},
+		{uint64(0), uint64(math.MaxUint64), -1},
+	}
+	sc := new(stmtctx.StatementContext)
+	for _, t := range tblCmp {
+		d1 := types.NewDatum(t.Arg1)
+		dec1, err := d1.ToDecimal(sc)
+		c.Assert(err, IsNil)
+		d1.SetMysqlDecimal(dec1)
+		d2 := types.NewDatum(t.Arg2)
+		dec2, err := d2.ToDecimal(sc)
+		c.Assert(err, IsNil)
+		d2.SetMysqlDecimal(dec2)
+
+		d1.SetLength(30)
+		d1.SetFrac(6)
+		d2.SetLength(30)
+		d2.SetFrac(6)
+		b1, err := EncodeKey(sc, nil, d1)
+		c.Assert(err, IsNil)
+		b2, err := EncodeKey(sc, nil, d2)
+		c.Assert(err, IsNil)
+
+		ret := bytes.Compare(b1, b2)
+		c.Assert(ret, Equals, t.Ret, Commentf("%v %x %x", t, b1, b2))
+
+		_, v1, err := DecodeOne(b1)
+		c.Assert(err, IsNil)
+		c.Assert(v1.GetMysqlDecimal().Compare(dec1), Equals, 0)
+		c.Assert(v1.Length(), Equals, 30)
+		c.Assert(v1.Frac(), Equals, 6)
+	}
+
+	_, _, _, _, err := DecodeDecimal(nil)
+	c.Assert(err, NotNil)
+}
+
+func parseTime(c *C, s string) types.Time {
+	m, err := types.ParseTime(nil, s, mysql.TypeDatetime, types.DefaultFsp)
+	c.Assert(err, IsNil)
+	return m
+}
+
+func parseDuration(c *C, s string) types.Duration {
+	m, err := types.ParseDuration(nil, s, types.DefaultFsp)
+	c.Assert(err, IsNil)
+	return m
+}
+
+func (s *testCodecSuite) TestTime(c *C) {
+	defer testleak.AfterTest(c)()
+	table := []string{
+		"2011-01-01 00:00:00",
+		"2011-01-01 00:00:00",
+		"0001-01-01 00:00:00",
+	}
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	for _, t := range table {
+		m := types.NewDatum(parseTime(c, t))
+
+		b, err := EncodeKey(sc, nil, m)
+		c.Assert(err, IsNil)
+		v, err := Decode(b, 1)
+		c.Assert(err, IsNil)
+		var t types.Time
+		t.SetType(mysql.TypeDatetime)
+		err = t.FromPackedUint(v[0].GetUint64())
+		c.Assert(err, IsNil)
+		c.Assert(t.Compare(m.GetMysqlTime()), Equals, 0)
+	}
+
+	tblCmp := []struct {
+		Arg1 string
+		Arg2 string
+		Ret  int
+	}{
+		{"2011-10-10 00:00:00", "2000-12-12 11:11:11", 1},
+		{"2000-10-10 00:00:00", "2001-10-10 00:00:00", -1},
+		{"2000-10-10 00:00:00", "2000-10-10 00:00:00", 0},
+		{"2000-10-10 00:00:00.999", "2000-10-10 00:00:01", 0},
+	}
+
+	for _, t := range tblCmp {
+		m1 := types.NewDatum(parseTime(c, t.Arg1))
+		m2 := types.NewDatum(parseTime(c, t.Arg2))
+
+		b1, err := EncodeKey(sc, nil, m1)
+		c.Assert(err, IsNil)
+		b2, err := EncodeKey(sc, nil, m2)
+		c.Assert(err, IsNil)
+
+		ret := bytes.Compare(b1, b2)
+		c.Assert(ret, Equals, t.Ret)
+	}
+}
+
+func (s *testCodecSuite) TestTimestampTimeZone(c *C) {
+	defer testleak.AfterTest(c)()
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		loc = time.FixedZone("UTC+8", 8*3600)
+	}
+	sc := &stmtctx.StatementContext{TimeZone: loc}
+	t, err := types.ParseTimestamp(sc, "2020-01-01 08:00:00")
+	c.Assert(err, IsNil)
+	b, err := EncodeValue(sc, nil, types.NewDatum(t))
+	c.Assert(err, IsNil)
+
+	// The stored value is in UTC.
+	v, err := Decode(b, 1)
+	c.Assert(err, IsNil)
+	stored := types.NewTime(types.ZeroCoreTime, mysql.TypeTimestamp, 0)
+	c.Assert(stored.FromPackedUint(v[0].GetUint64()), IsNil)
+	c.Assert(stored.String(), Equals, "2020-01-01 00:00:00")
+
+	// Decoding to chunk converts it back to the session time zone.
+	ft := types.NewFieldType(mysql.TypeTimestamp)
+	ft.Decimal = 0
+	decoder := NewDecoder(chunk.New([]*types.FieldType{ft}, 1, 1), sc.TimeZone)
+	_, err = decoder.DecodeOne(b, 0, ft)
+	c.Assert(err, IsNil)
+	c.Assert(decoder.chk.GetRow(0).GetTime(0).String(), Equals, "2020-01-01 08:00:00")
+}
+
+func (s *testCodecSuite) TestDuration(c *C) {
+	defer testleak.AfterTest(c)()
+	table := []string{
+		"00:00:00",
+		"1 11:11:11",
+		"00:00:00.777777",
+	}
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	for _, t := range table {
+		m := parseDuration(c, t)
+
+		b, err := EncodeKey(sc, nil, types.NewDatum(m))
+		c.Assert(err, IsNil)
+		v, err := Decode(b, 1)
+		c.Assert(err, IsNil)
+		m.Fsp = types.MaxFsp
+		c.Assert(v, DeepEquals, types.MakeDatums(m))
+	}
+
+	tblCmp := []struct {
+		Arg1 string
+		Arg2 string
+		Ret  int
+	}{
+		{"20:00:00", "11:11:11", 1},
+		{"00:00:00", "00:00:01", -1},
+		{"00:00:00", "00:00:00", 0},
+		{"-00:00:01", "00:00:00", -1},
+	}
+
+	for _, t := range tblCmp {
+		m1 := parseDuration(c, t.Arg1)
+		m2 := parseDuration(c, t.Arg2)
+
+		b1, err := EncodeKey(sc, nil, types.NewDatum(m1))
+		c.Assert(err, IsNil)
+		b2, err := EncodeKey(sc, nil, types.NewDatum(m2))
+		c.Assert(err, IsNil)
+
+		ret := bytes.Compare(b1, b2)
+		c.Assert(ret, Equals, t.Ret)
+	}
+}
+
 func (s *testCodecSuite) TestBytes(c *C) {
 	defer testleak.AfterTest(c)()
 	tblBytes := [][]byte{
@@ -540,6 +801,10 @@ func (s *testCodecSuite) TestCut(c *C) {
 			types.MakeDatums(float32(1), float64(3.15), []byte("123456789012345")),
 			types.MakeDatums(float64(1), float64(3.15), []byte("123456789012345")),
 		},
+		{
+			types.MakeDatums(json.CreateBinary("abc"), int64(1), json.CreateBinary([]interface{}{int64(1), nil})),
+			types.MakeDatums(json.CreateBinary("abc"), int64(1), json.CreateBinary([]interface{}{int64(1), nil})),
+		},
 	}
 	sc := &stmtctx.StatementContext{TimeZone: time.Local}
 	for i, t := range table {
@@ -641,6 +906,12 @@ func datumsForTest(sc *stmtctx.StatementContext) ([]types.Datum, []*types.FieldT
 		{[]byte("abc"), types.NewFieldType(mysql.TypeMediumBlob)},
 		{[]byte("abc"), types.NewFieldType(mysql.TypeLongBlob)},
 		{int64(1), types.NewFieldType(mysql.TypeYear)},
+		{types.NewDecFromInt(1), types.NewFieldType(mysql.TypeNewDecimal)},
+		{types.Duration{Duration: time.Second, Fsp: 1}, types.NewFieldType(mysql.TypeDuration)},
+		{types.CurrentTime(mysql.TypeDatetime), types.NewFieldType(mysql.TypeDatetime)},
+		{types.CurrentTime(mysql.TypeDate), types.NewFieldType(mysql.TypeDate)},
+		{types.CurrentTime(mysql.TypeTimestamp), types.NewFieldType(mysql.TypeTimestamp)},
+		{json.CreateBinary(map[string]interface{}{"a": []interface{}{int64(1), "b"}}), types.NewFieldType(mysql.TypeJSON)},
 	}
 
 	datums := make([]types.Datum, 0, len(table)+2)
@@ -764,8 +1035,14 @@ func (s *testCodecSuite) TestHashChunkRow(c *C) {
 	testHashChunkRowEqual(c, float32(1.0), float64(1.0), true)
 	testHashChunkRowEqual(c, float32(1.0), float64(1.1), false)
 
+	testHashChunkRowEqual(c, types.NewDecFromStringForTest("1.1"), types.NewDecFromStringForTest("1.10"), true)
+	testHashChunkRowEqual(c, types.NewDecFromStringForTest("1.1"), types.NewDecFromStringForTest("1.2"), false)
+
 	testHashChunkRowEqual(c, "x", []byte("x"), true)
 	testHashChunkRowEqual(c, "x", []byte("y"), false)
+
+	testHashChunkRowEqual(c, json.CreateBinary("x"), json.CreateBinary("x"), true)
+	testHashChunkRowEqual(c, json.CreateBinary("x"), json.CreateBinary("y"), false)
 }
 
 func (s *testCodecSuite) TestValueSizeOfSignedInt(c *C) {
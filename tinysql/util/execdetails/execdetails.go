@@ -0,0 +1,251 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execdetails
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// RuntimeStatsColl collects executors's execution info.
+type RuntimeStatsColl struct {
+	mu          sync.Mutex
+	rootStats   map[string]*RuntimeStats
+	copStats    map[string]*CopRuntimeStats
+	readerStats map[string]*ReaderRuntimeStats
+}
+
+// CopRuntimeStats collects cop tasks' execution info.
+type CopRuntimeStats struct {
+	sync.Mutex
+
+	// stats stores the runtime statistics of coprocessor tasks.
+	// The key of the map is the store address. Because a store can have many
+	// region leaders, several coprocessor tasks can be sent to the same store.
+	// We have to use a list to maintain all tasks executed on each store.
+	stats map[string][]*RuntimeStats
+}
+
+// RecordOneCopTask records a specific cop tasks's execution detail.
+func (crs *CopRuntimeStats) RecordOneCopTask(address string, summary *tipb.ExecutorExecutionSummary) {
+	crs.Lock()
+	defer crs.Unlock()
+	crs.stats[address] = append(crs.stats[address],
+		&RuntimeStats{loop: int32(summary.GetNumIterations()),
+			consume: int64(summary.GetTimeProcessedNs()),
+			rows:    int64(summary.GetNumProducedRows())})
+}
+
+func (crs *CopRuntimeStats) String() string {
+	crs.Lock()
+	defer crs.Unlock()
+	if len(crs.stats) == 0 {
+		return ""
+	}
+
+	var totalRows, totalTasks int64
+	var totalIters int32
+	procTimes := make([]time.Duration, 0, 32)
+	for _, instanceStats := range crs.stats {
+		for _, stat := range instanceStats {
+			procTimes = append(procTimes, time.Duration(stat.consume)*time.Nanosecond)
+			totalRows += stat.rows
+			totalIters += stat.loop
+			totalTasks++
+		}
+	}
+
+	if totalTasks == 1 {
+		return fmt.Sprintf("time:%v, loops:%d, rows:%d", procTimes[0], totalIters, totalRows)
+	}
+
+	n := len(procTimes)
+	sort.Slice(procTimes, func(i, j int) bool { return procTimes[i] < procTimes[j] })
+	return fmt.Sprintf("proc max:%v, min:%v, p80:%v, p95:%v, rows:%v, iters:%v, tasks:%v",
+		procTimes[n-1], procTimes[0], procTimes[n*4/5], procTimes[n*19/20], totalRows, totalIters, totalTasks)
+}
+
+// ReaderRuntimeStats collects the coprocessor responses of TableReader,
+// IndexReader and IndexLookUpReader.
+type ReaderRuntimeStats struct {
+	sync.Mutex
+
+	copRespTime []time.Duration
+}
+
+// recordOneCopTask records the response time of one cop task.
+func (rrs *ReaderRuntimeStats) recordOneCopTask(t time.Duration) {
+	rrs.Lock()
+	defer rrs.Unlock()
+	rrs.copRespTime = append(rrs.copRespTime, t)
+}
+
+func (rrs *ReaderRuntimeStats) String() string {
+	rrs.Lock()
+	defer rrs.Unlock()
+	size := len(rrs.copRespTime)
+	if size == 0 {
+		return ""
+	}
+	if size == 1 {
+		return fmt.Sprintf("cop_task: {num: 1, time: %v}", rrs.copRespTime[0])
+	}
+	respTimes := make([]time.Duration, size)
+	copy(respTimes, rrs.copRespTime)
+	sort.Slice(respTimes, func(i, j int) bool { return respTimes[i] < respTimes[j] })
+	var sum time.Duration
+	for _, t := range respTimes {
+		sum += t
+	}
+	return fmt.Sprintf("cop_task: {num: %v, max: %v, min: %v, avg: %v, p95: %v}",
+		size, respTimes[size-1], respTimes[0], sum/time.Duration(size), respTimes[size*19/20])
+}
+
+// RuntimeStats collects one executor's execution info.
+type RuntimeStats struct {
+	// executor's Next() called times.
+	loop int32
+	// executor consume time.
+	consume int64
+	// executor return row count.
+	rows int64
+	// the peak memory of the chunks returned by the executor.
+	maxMemory int64
+}
+
+// NewRuntimeStatsColl creates new executor collector.
+func NewRuntimeStatsColl() *RuntimeStatsColl {
+	return &RuntimeStatsColl{rootStats: make(map[string]*RuntimeStats),
+		copStats: make(map[string]*CopRuntimeStats), readerStats: make(map[string]*ReaderRuntimeStats)}
+}
+
+// GetRootStats gets execStat for a executor.
+func (e *RuntimeStatsColl) GetRootStats(planID string) *RuntimeStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	runtimeStats, exists := e.rootStats[planID]
+	if !exists {
+		runtimeStats = &RuntimeStats{}
+		e.rootStats[planID] = runtimeStats
+	}
+	return runtimeStats
+}
+
+// GetCopStats gets the CopRuntimeStats specified by planID.
+func (e *RuntimeStatsColl) GetCopStats(planID string) *CopRuntimeStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	copStats, ok := e.copStats[planID]
+	if !ok {
+		copStats = &CopRuntimeStats{stats: make(map[string][]*RuntimeStats)}
+		e.copStats[planID] = copStats
+	}
+	return copStats
+}
+
+// GetReaderStats gets the ReaderRuntimeStats specified by planID.
+func (e *RuntimeStatsColl) GetReaderStats(planID string) *ReaderRuntimeStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats, exists := e.readerStats[planID]
+	if !exists {
+		stats = &ReaderRuntimeStats{copRespTime: make([]time.Duration, 0, 20)}
+		e.readerStats[planID] = stats
+	}
+	return stats
+}
+
+// RecordOneCopTask records a specific cop tasks's execution detail.
+func (e *RuntimeStatsColl) RecordOneCopTask(planID, address string, summary *tipb.ExecutorExecutionSummary) {
+	copStats := e.GetCopStats(planID)
+	copStats.RecordOneCopTask(address, summary)
+}
+
+// RecordOneReaderStats records a specific stats for TableReader, IndexReader and IndexLookupReader.
+func (e *RuntimeStatsColl) RecordOneReaderStats(planID string, copRespTime time.Duration) {
+	readerStats := e.GetReaderStats(planID)
+	readerStats.recordOneCopTask(copRespTime)
+}
+
+// ExistsRootStats checks if the planID exists in the rootStats collection.
+func (e *RuntimeStatsColl) ExistsRootStats(planID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, exists := e.rootStats[planID]
+	return exists
+}
+
+// ExistsCopStats checks if the planID exists in the copStats collection.
+func (e *RuntimeStatsColl) ExistsCopStats(planID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, exists := e.copStats[planID]
+	return exists
+}
+
+// ExistsReaderStats checks if the planID exists in the readerStats collection.
+func (e *RuntimeStatsColl) ExistsReaderStats(planID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, exists := e.readerStats[planID]
+	return exists
+}
+
+// Record records executor's execution.
+func (e *RuntimeStats) Record(d time.Duration, rowNum int) {
+	atomic.AddInt32(&e.loop, 1)
+	atomic.AddInt64(&e.consume, int64(d))
+	atomic.AddInt64(&e.rows, int64(rowNum))
+}
+
+// RecordMemory records the memory of a chunk returned by the executor, only
+// the peak one is kept.
+func (e *RuntimeStats) RecordMemory(bytes int64) {
+	for {
+		old := atomic.LoadInt64(&e.maxMemory)
+		if bytes <= old || atomic.CompareAndSwapInt64(&e.maxMemory, old, bytes) {
+			return
+		}
+	}
+}
+
+// SetRowNum sets the row num.
+func (e *RuntimeStats) SetRowNum(rowNum int64) {
+	atomic.StoreInt64(&e.rows, rowNum)
+}
+
+func (e *RuntimeStats) String() string {
+	return fmt.Sprintf("time:%v, loops:%d, rows:%d", time.Duration(atomic.LoadInt64(&e.consume)),
+		atomic.LoadInt32(&e.loop), atomic.LoadInt64(&e.rows))
+}
+
+// MemoryString returns the peak memory recorded by RecordMemory in a human
+// readable format.
+func (e *RuntimeStats) MemoryString() string {
+	bytes := atomic.LoadInt64(&e.maxMemory)
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.2f GB", float64(bytes)/float64(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.2f MB", float64(bytes)/float64(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.2f KB", float64(bytes)/float64(1<<10))
+	}
+	return fmt.Sprintf("%d Bytes", bytes)
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execdetails
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+func mockExecutorExecutionSummary(TimeProcessedNs, NumProducedRows, NumIterations uint64) *tipb.ExecutorExecutionSummary {
+	return &tipb.ExecutorExecutionSummary{TimeProcessedNs: &TimeProcessedNs, NumProducedRows: &NumProducedRows,
+		NumIterations: &NumIterations}
+}
+
+func TestCopRuntimeStats(t *testing.T) {
+	stats := NewRuntimeStatsColl()
+	stats.RecordOneCopTask("table_scan", "8.8.8.8", mockExecutorExecutionSummary(1, 1, 1))
+	stats.RecordOneCopTask("table_scan", "8.8.8.9", mockExecutorExecutionSummary(2, 2, 2))
+	stats.RecordOneCopTask("agg", "8.8.8.8", mockExecutorExecutionSummary(3, 3, 3))
+	stats.RecordOneCopTask("agg", "8.8.8.9", mockExecutorExecutionSummary(4, 4, 4))
+	if stats.ExistsCopStats("table_scan") != true {
+		t.Fatal("exist")
+	}
+	if stats.ExistsRootStats("table_scan") != false {
+		t.Fatal("not exist")
+	}
+	cop := stats.GetCopStats("table_scan")
+	if cop.String() != "proc max:2ns, min:1ns, p80:2ns, p95:2ns, rows:3, iters:3, tasks:2" {
+		t.Fatal(cop.String())
+	}
+	if stats.GetCopStats("agg").String() != "proc max:4ns, min:3ns, p80:4ns, p95:4ns, rows:7, iters:7, tasks:2" {
+		t.Fatal("agg")
+	}
+
+	stats.RecordOneCopTask("limit", "8.8.8.8", mockExecutorExecutionSummary(5, 5, 6))
+	if stats.GetCopStats("limit").String() != "time:5ns, loops:6, rows:5" {
+		t.Fatal(stats.GetCopStats("limit").String())
+	}
+}
+
+func TestReaderRuntimeStats(t *testing.T) {
+	stats := NewRuntimeStatsColl()
+	if stats.GetReaderStats("table_reader").String() != "" {
+		t.Fatal("empty")
+	}
+	stats.RecordOneReaderStats("table_reader", time.Second)
+	if stats.GetReaderStats("table_reader").String() != "cop_task: {num: 1, time: 1s}" {
+		t.Fatal(stats.GetReaderStats("table_reader").String())
+	}
+	stats.RecordOneReaderStats("table_reader", 3*time.Second)
+	stats.RecordOneReaderStats("table_reader", 2*time.Second)
+	if stats.GetReaderStats("table_reader").String() != "cop_task: {num: 3, max: 3s, min: 1s, avg: 2s, p95: 3s}" {
+		t.Fatal(stats.GetReaderStats("table_reader").String())
+	}
+}
+
+func TestRootRuntimeStats(t *testing.T) {
+	stats := NewRuntimeStatsColl()
+	root := stats.GetRootStats("projection")
+	root.Record(time.Millisecond, 10)
+	root.Record(2*time.Millisecond, 0)
+	root.RecordMemory(3 << 10)
+	root.RecordMemory(1 << 10)
+	if !stats.ExistsRootStats("projection") {
+		t.Fatal("exist")
+	}
+	if root.String() != "time:3ms, loops:2, rows:10" {
+		t.Fatal(root.String())
+	}
+	if root.MemoryString() != "3.00 KB" {
+		t.Fatal(root.MemoryString())
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package math
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testMath) TestRandWithSeed(c *C) {
+	// The expected values are the results of `SELECT RAND(seed)` in MySQL.
+	tests := [4]struct {
+		seed int64
+		once float64
+		exp  float64
+	}{{0, 0.15522042769493574, 0.620881741513388},
+		{1, 0.40540353712197724, 0.8716141803857071},
+		{-1, 0.9050373219931845, 0.37014932126752037},
+		{9223372036854775807, 0.9050373219931845, 0.37014932126752037}}
+	for _, test := range tests {
+		rng := NewWithSeed(test.seed)
+		c.Assert(rng.Gen(), Equals, test.once)
+		c.Assert(rng.Gen(), Equals, test.exp)
+	}
+}
+
+func (s *testMath) TestRandWithTime(c *C) {
+	rng := NewWithTime()
+	for i := 0; i < 100; i++ {
+		v := rng.Gen()
+		c.Assert(v >= 0 && v < 1, IsTrue)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// ActionOnExceed is the action taken when memory usage exceeds memory quota.
+// NOTE: All the implementors should be thread-safe.
+type ActionOnExceed interface {
+	// Action will be called when memory usage exceeds memory quota by the
+	// corresponding Tracker.
+	Action(t *Tracker)
+	// SetFallback sets a fallback action which will be triggered if itself has
+	// already been triggered.
+	SetFallback(a ActionOnExceed)
+}
+
+// LogOnExceed logs a warning only once when memory usage exceeds memory quota.
+type LogOnExceed struct {
+	mutex  sync.Mutex // For synchronization.
+	acted  bool
+	ConnID uint64
+}
+
+// Action logs a warning only once when memory usage exceeds memory quota.
+func (a *LogOnExceed) Action(t *Tracker) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.acted {
+		a.acted = true
+		logutil.BgLogger().Warn("memory exceeds quota",
+			zap.Uint64("connID", a.ConnID),
+			zap.Error(errMemExceedThreshold.GenWithStackByArgs(t.label, t.BytesConsumed(), t.bytesLimit, t.String())))
+	}
+}
+
+// SetFallback sets a fallback action.
+func (a *LogOnExceed) SetFallback(ActionOnExceed) {}
+
+// PanicOnExceed panics when memory usage exceeds memory quota, the panic is
+// recovered by the statement executor and returned as the error of the
+// statement, so the statement is canceled.
+type PanicOnExceed struct {
+	mutex  sync.Mutex // For synchronization.
+	acted  bool
+	ConnID uint64
+}
+
+// Action panics when memory usage exceeds memory quota.
+func (a *PanicOnExceed) Action(t *Tracker) {
+	a.mutex.Lock()
+	if a.acted {
+		a.mutex.Unlock()
+		return
+	}
+	a.acted = true
+	a.mutex.Unlock()
+	panic(PanicMemoryExceed + fmt.Sprintf("[conn_id=%d]", a.ConnID))
+}
+
+// SetFallback sets a fallback action.
+func (a *PanicOnExceed) SetFallback(ActionOnExceed) {}
+
+var (
+	errMemExceedThreshold = terror.ClassUtil.New(mysql.ErrMemExceedThreshold, mysql.MySQLErrName[mysql.ErrMemExceedThreshold])
+)
+
+const (
+	// PanicMemoryExceed represents the panic message when out of memory quota.
+	PanicMemoryExceed string = "Out Of Memory Quota!"
+)
@@ -0,0 +1,180 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testSuite{})
+
+type testSuite struct{}
+
+func (s *testSuite) TestSetLabel(c *C) {
+	tracker := NewTracker(stringutil.StringerStr("old label"), -1)
+	c.Assert(tracker.Label().String(), Equals, "old label")
+	c.Assert(tracker.BytesConsumed(), Equals, int64(0))
+	c.Assert(tracker.GetBytesLimit(), Equals, int64(-1))
+	c.Assert(tracker.parent, IsNil)
+	c.Assert(len(tracker.mu.children), Equals, 0)
+	tracker.SetLabel(stringutil.StringerStr("new label"))
+	c.Assert(tracker.Label().String(), Equals, "new label")
+}
+
+func (s *testSuite) TestConsume(c *C) {
+	tracker := NewTracker(stringutil.StringerStr("tracker"), -1)
+	c.Assert(tracker.BytesConsumed(), Equals, int64(0))
+
+	tracker.Consume(100)
+	c.Assert(tracker.BytesConsumed(), Equals, int64(100))
+
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer waitGroup.Done()
+			tracker.Consume(10)
+		}()
+	}
+	waitGroup.Add(10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer waitGroup.Done()
+			tracker.Consume(-10)
+		}()
+	}
+	waitGroup.Wait()
+	c.Assert(tracker.BytesConsumed(), Equals, int64(100))
+	c.Assert(tracker.MaxConsumed() >= 100, IsTrue)
+}
+
+func (s *testSuite) TestAttachTo(c *C) {
+	oldParent := NewTracker(stringutil.StringerStr("old parent"), -1)
+	newParent := NewTracker(stringutil.StringerStr("new parent"), -1)
+	child := NewTracker(stringutil.StringerStr("child"), -1)
+	child.Consume(100)
+	child.AttachTo(oldParent)
+	c.Assert(child.BytesConsumed(), Equals, int64(100))
+	c.Assert(oldParent.BytesConsumed(), Equals, int64(100))
+	c.Assert(child.parent, DeepEquals, oldParent)
+	c.Assert(len(oldParent.mu.children), Equals, 1)
+
+	child.AttachTo(newParent)
+	c.Assert(child.BytesConsumed(), Equals, int64(100))
+	c.Assert(oldParent.BytesConsumed(), Equals, int64(0))
+	c.Assert(newParent.BytesConsumed(), Equals, int64(100))
+	c.Assert(child.parent, DeepEquals, newParent)
+	c.Assert(len(newParent.mu.children), Equals, 1)
+	c.Assert(len(oldParent.mu.children), Equals, 0)
+
+	// Attaching to a nil parent does nothing.
+	child.AttachTo(nil)
+	c.Assert(child.parent, DeepEquals, newParent)
+
+	child.Detach()
+	c.Assert(child.parent, IsNil)
+	c.Assert(newParent.BytesConsumed(), Equals, int64(0))
+	c.Assert(len(newParent.mu.children), Equals, 0)
+}
+
+func (s *testSuite) TestReplaceChild(c *C) {
+	oldChild := NewTracker(stringutil.StringerStr("old child"), -1)
+	oldChild.Consume(100)
+	newChild := NewTracker(stringutil.StringerStr("new child"), -1)
+	newChild.Consume(500)
+	parent := NewTracker(stringutil.StringerStr("parent"), -1)
+
+	oldChild.AttachTo(parent)
+	c.Assert(parent.BytesConsumed(), Equals, int64(100))
+
+	parent.ReplaceChild(oldChild, newChild)
+	c.Assert(parent.BytesConsumed(), Equals, int64(500))
+	c.Assert(len(parent.mu.children), Equals, 1)
+	c.Assert(parent.mu.children[0], DeepEquals, newChild)
+	c.Assert(newChild.parent, DeepEquals, parent)
+	c.Assert(oldChild.parent, IsNil)
+
+	parent.ReplaceChild(newChild, nil)
+	c.Assert(parent.BytesConsumed(), Equals, int64(0))
+	c.Assert(len(parent.mu.children), Equals, 0)
+	c.Assert(newChild.parent, IsNil)
+}
+
+type mockAction struct {
+	called   bool
+	fallback ActionOnExceed
+}
+
+func (a *mockAction) SetFallback(fallback ActionOnExceed) {
+	a.fallback = fallback
+}
+
+func (a *mockAction) Action(t *Tracker) {
+	if a.called && a.fallback != nil {
+		a.fallback.Action(t)
+		return
+	}
+	a.called = true
+}
+
+func (s *testSuite) TestActionOnExceed(c *C) {
+	parent := NewTracker(stringutil.StringerStr("parent"), 1000)
+	child := NewTracker(stringutil.StringerStr("child"), -1)
+	child.AttachTo(parent)
+	first := &mockAction{}
+	parent.SetActionOnExceed(first)
+
+	child.Consume(999)
+	c.Assert(first.called, IsFalse)
+	child.Consume(1)
+	c.Assert(first.called, IsTrue)
+
+	// The new action runs first and falls back to the old one.
+	second := &mockAction{}
+	parent.FallbackOldAndSetNewAction(second)
+	c.Assert(second.fallback, Equals, first)
+	child.Consume(1)
+	c.Assert(second.called, IsTrue)
+
+	parent.SetActionOnExceed(&PanicOnExceed{ConnID: 1})
+	c.Assert(func() { child.Consume(1) }, PanicMatches, PanicMemoryExceed+`\[conn_id=1\]`)
+	// A release never triggers the action.
+	child.Consume(-1)
+}
+
+func (s *testSuite) TestString(c *C) {
+	parent := NewTracker(stringutil.StringerStr("parent"), -1)
+	child := NewTracker(stringutil.StringerStr("child"), 2<<20)
+	child.Consume(10)
+	child.AttachTo(parent)
+	str := parent.String()
+	c.Assert(strings.Contains(str, `"parent"{`), IsTrue)
+	c.Assert(strings.Contains(str, `"child"{`), IsTrue)
+	c.Assert(strings.Contains(str, `"quota": 2.00 MB`), IsTrue)
+	c.Assert(strings.Contains(str, `"consumed": 10 Bytes`), IsTrue)
+	c.Assert(parent.SearchTracker("child"), Equals, child)
+	c.Assert(parent.SearchTracker("unknown"), IsNil)
+
+	c.Assert(BytesToString(3<<30), Equals, "3.00 GB")
+	c.Assert(BytesToString(1536), Equals, "1.50 KB")
+}
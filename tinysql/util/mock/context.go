@@ -25,6 +25,8 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/sqlexec"
 )
 
@@ -39,6 +41,7 @@ type Context struct {
 	sessionVars *variable.SessionVars
 	ctx         context.Context
 	cancel      context.CancelFunc
+	pcache      *kvcache.SimpleLRUCache
 }
 
 type wrapTxn struct {
@@ -84,6 +87,11 @@ func (c *Context) GetSessionVars() *variable.SessionVars {
 	return c.sessionVars
 }
 
+// PreparedPlanCache implements the sessionctx.Context interface.
+func (c *Context) PreparedPlanCache() *kvcache.SimpleLRUCache {
+	return c.pcache
+}
+
 // Txn implements sessionctx.Context Txn interface.
 func (c *Context) Txn(bool) (kv.Transaction, error) {
 	return &c.txn, nil
@@ -162,6 +170,11 @@ func (c *Context) GetStore() kv.Storage {
 	return c.Store
 }
 
+// GetSessionManager implements the sessionctx.Context interface.
+func (c *Context) GetSessionManager() util.SessionManager {
+	return nil
+}
+
 // Cancel implements the Session interface.
 func (c *Context) Cancel() {
 	c.cancel()
@@ -232,6 +245,7 @@ func NewContext() *Context {
 		sessionVars: variable.NewSessionVars(),
 		ctx:         ctx,
 		cancel:      cancel,
+		pcache:      kvcache.NewSimpleLRUCache(variable.DefTiDBPreparedPlanCacheSize),
 	}
 	sctx.sessionVars.InitChunkSize = 2
 	sctx.sessionVars.MaxChunkSize = 32
@@ -17,6 +17,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 // conditionChecker checks if this condition can be pushed to index planner.
@@ -56,7 +57,11 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 	case ast.IsNull:
 		return c.checkColumn(scalar.GetArgs()[0])
 	case ast.UnaryNot:
-		if _, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+		if s, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+			// TODO: support "not like" convert to access conditions.
+			if s.FuncName.L == ast.Like {
+				return false
+			}
 			return c.check(scalar.GetArgs()[0])
 		}
 		// "not column" or "not constant" can't lead to a range.
@@ -71,10 +76,68 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 			}
 		}
 		return true
+	case ast.Like:
+		return c.checkLikeFunc(scalar)
 	}
 	return false
 }
 
+func (c *conditionChecker) checkLikeFunc(scalar *expression.ScalarFunction) bool {
+	args := scalar.GetArgs()
+	if !c.checkColumn(args[0]) || args[0].GetType().EvalType() != types.ETString {
+		return false
+	}
+	// The index keys are compared byte-wise, so the case-insensitive match
+	// can't be converted to a range.
+	if _, ci := expression.PatternMatchCollation(args[0], args[1]); ci {
+		return false
+	}
+	pattern, ok := args[1].(*expression.Constant)
+	if !ok {
+		return false
+	}
+	patternStr, isNull, err := pattern.EvalString(nil, chunk.Row{})
+	if err != nil || isNull {
+		return false
+	}
+	escapeCon, ok := args[2].(*expression.Constant)
+	if !ok {
+		return false
+	}
+	escape := byte(escapeCon.Value.GetInt64())
+	for i := 0; i < len(patternStr); i++ {
+		if patternStr[i] == escape {
+			// Skip the escaped character.
+			if i < len(patternStr)-1 && isLikeEscapable(patternStr[i+1], escape) {
+				i++
+			}
+			continue
+		}
+		// The pattern without a constant prefix can't lead to a range.
+		if i == 0 && (patternStr[i] == '%' || patternStr[i] == '_') {
+			return false
+		}
+		if patternStr[i] == '%' {
+			// "abc%" is fully covered by the range, "abc%d" is not.
+			if i != len(patternStr)-1 {
+				c.shouldReserve = true
+			}
+			break
+		}
+		if patternStr[i] == '_' {
+			c.shouldReserve = true
+			break
+		}
+	}
+	return true
+}
+
+// isLikeEscapable checks whether the character after the escape character is
+// escaped in a LIKE pattern, otherwise the escape character is a literal.
+func isLikeEscapable(c, escape byte) bool {
+	return c == escape || c == '_' || c == '%'
+}
+
 func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
 	col, ok := expr.(*expression.Column)
 	if !ok {
@@ -407,6 +407,84 @@ func (r *builder) buildFromIn(expr *expression.ScalarFunction) ([]point, bool) {
 	return rangePoints[:curPos], hasNull
 }
 
+// buildFromPatternLike builds the range of the constant prefix of a LIKE
+// pattern, e.g., "abc%" leads to the range ["abc", "abd").
+func (r *builder) buildFromPatternLike(expr *expression.ScalarFunction) []point {
+	pdt, err := expr.GetArgs()[1].(*expression.Constant).Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	pattern, err := pdt.ToString()
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	if pattern == "" {
+		startPoint := point{value: types.NewStringDatum(""), start: true}
+		endPoint := point{value: types.NewStringDatum("")}
+		return []point{startPoint, endPoint}
+	}
+	edt, err := expr.GetArgs()[2].(*expression.Constant).Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	escape := byte(edt.GetInt64())
+	lowValue := make([]byte, 0, len(pattern))
+	var exclude bool
+	isExactMatch := true
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == escape {
+			if i < len(pattern)-1 && isLikeEscapable(pattern[i+1], escape) {
+				i++
+			}
+			lowValue = append(lowValue, pattern[i])
+			continue
+		}
+		if pattern[i] == '%' {
+			// Get the prefix.
+			isExactMatch = false
+			break
+		} else if pattern[i] == '_' {
+			// Get the prefix, but exclude the prefix.
+			// e.g., "abc_x", the start point exclude "abc",
+			// because the string length is more than 3.
+			exclude = true
+			isExactMatch = false
+			break
+		}
+		lowValue = append(lowValue, pattern[i])
+	}
+	if len(lowValue) == 0 {
+		return []point{{value: types.MinNotNullDatum(), start: true}, {value: types.MaxValueDatum()}}
+	}
+	if isExactMatch {
+		val := types.NewStringDatum(string(lowValue))
+		return []point{{value: val, start: true}, {value: val}}
+	}
+	startPoint := point{start: true, excl: exclude}
+	startPoint.value.SetBytesAsString(lowValue)
+	highValue := make([]byte, len(lowValue))
+	copy(highValue, lowValue)
+	endPoint := point{excl: true}
+	for i := len(highValue) - 1; i >= 0; i-- {
+		// Make the end point value more than the start point value,
+		// and the length of the end point value is the same as the length of the start point value.
+		// e.g., the start point value is "abc", so the end point value is "abd".
+		highValue[i]++
+		if highValue[i] != 0 {
+			endPoint.value.SetBytesAsString(highValue[:i+1])
+			break
+		}
+		// If highValue[i] is 255 and highValue[i]++ is 0, then the end point value is max value.
+		if i == 0 {
+			endPoint.value = types.MaxValueDatum()
+		}
+	}
+	return []point{startPoint, endPoint}
+}
+
 func (r *builder) buildFromNot(expr *expression.ScalarFunction) []point {
 	switch n := expr.FuncName.L; n {
 	case ast.In:
@@ -464,6 +542,8 @@ func (r *builder) buildFromScalarFunc(expr *expression.ScalarFunction) []point {
 		startPoint := point{start: true}
 		endPoint := point{}
 		return []point{startPoint, endPoint}
+	case ast.Like:
+		return r.buildFromPatternLike(expr)
 	case ast.UnaryNot:
 		return r.buildFromNot(expr.GetArgs()[0].(*expression.ScalarFunction))
 	}
@@ -460,6 +460,69 @@ func (s *testRangerSuite) TestIndexRange(c *C) {
 			filterConds: "[or(lt(test.t.d, 你好), gt(test.t.d, 你好))]",
 			resultStr:   "[[-inf,\"你好\") (\"你好\",+inf]]",
 		},
+		{
+			indexPos:    0,
+			exprStr:     `a LIKE 'abc%'`,
+			accessConds: "[like(test.t.a, abc%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc_'",
+			accessConds: "[like(test.t.a, abc_, 92)]",
+			filterConds: "[like(test.t.a, abc_, 92)]",
+			resultStr:   "[(\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc%de'",
+			accessConds: "[like(test.t.a, abc%de, 92)]",
+			filterConds: "[like(test.t.a, abc%de, 92)]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     `a LIKE 'ab\\_c%'`,
+			accessConds: "[like(test.t.a, ab\\_c%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab_c\",\"ab_d\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     `a LIKE 'ab|%c%' ESCAPE '|'`,
+			accessConds: "[like(test.t.a, ab|%c%, 124)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab%c\",\"ab%d\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE '%abc'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.a, %abc, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a NOT LIKE 'abc%'",
+			accessConds: "[]",
+			filterConds: "[not(like(test.t.a, abc%, 92))]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc'",
+			accessConds: "[eq(test.t.a, abc)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abc\"]]",
+		},
+		{
+			indexPos:    2,
+			exprStr:     "d LIKE 'abc%'",
+			accessConds: "[like(test.t.d, abc%, 92)]",
+			filterConds: "[like(test.t.d, abc%, 92)]",
+			resultStr:   "[[\"ab\",\"ab\"]]",
+		},
 	}
 
 	ctx := context.Background()
@@ -613,6 +676,93 @@ func (s *testRangerSuite) TestIndexRangeForUnsignedInt(c *C) {
 	}
 }
 
+func (s *testRangerSuite) TestIndexRangeForTime(c *C) {
+	defer testleak.AfterTest(c)()
+	dom, store, err := newDomainStoreWithBootstrap(c)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	c.Assert(err, IsNil)
+	testKit := testkit.NewTestKit(c, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("drop table if exists t")
+	testKit.MustExec("create table t (a datetime(3), b date, c time, key (a), key (b), key (c))")
+
+	tests := []struct {
+		indexPos    int
+		exprStr     string
+		accessConds string
+		filterConds string
+		resultStr   string
+	}{
+		{
+			indexPos:    0,
+			exprStr:     `a = '2020-01-02 10:00:00.1234'`,
+			accessConds: "[eq(test.t.a, 2020-01-02 10:00:00.123400)]",
+			filterConds: "[]",
+			resultStr:   `[]`,
+		},
+		{
+			indexPos:    0,
+			exprStr:     `a > '2020-01-02' and a <= '2020-01-03 12:00:00'`,
+			accessConds: "[gt(test.t.a, 2020-01-02 00:00:00.000000) le(test.t.a, 2020-01-03 12:00:00.000000)]",
+			filterConds: "[]",
+			resultStr:   `[(2020-01-02 00:00:00.000,2020-01-03 12:00:00.000]]`,
+		},
+		{
+			indexPos:    1,
+			exprStr:     `b >= '2020-01-02 10:00:00'`,
+			accessConds: "[ge(test.t.b, 2020-01-02 10:00:00.000000)]",
+			filterConds: "[]",
+			resultStr:   `[(2020-01-02,+inf]]`,
+		},
+		{
+			indexPos:    1,
+			exprStr:     `b in ('2020-01-02', '2019-12-31')`,
+			accessConds: "[in(test.t.b, 2020-01-02 00:00:00.000000, 2019-12-31 00:00:00.000000)]",
+			filterConds: "[]",
+			resultStr:   `[[2019-12-31,2019-12-31] [2020-01-02,2020-01-02]]`,
+		},
+		{
+			indexPos:    2,
+			exprStr:     `c < '10:00:00.5'`,
+			accessConds: "[lt(test.t.c, 10:00:00.500000)]",
+			filterConds: "[]",
+			resultStr:   `[[-inf,10:00:01)]`,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		sql := "select * from t where " + tt.exprStr
+		sctx := testKit.Se.(sessionctx.Context)
+		stmts, err := session.Parse(sctx, sql)
+		c.Assert(err, IsNil, Commentf("error %v, for expr %s", err, tt.exprStr))
+		c.Assert(stmts, HasLen, 1)
+		is := domain.GetDomain(sctx).InfoSchema()
+		err = plannercore.Preprocess(sctx, stmts[0], is)
+		c.Assert(err, IsNil, Commentf("error %v, for resolve name, expr %s", err, tt.exprStr))
+		p, _, err := plannercore.BuildLogicalPlan(ctx, sctx, stmts[0], is)
+		c.Assert(err, IsNil, Commentf("error %v, for build plan, expr %s", err, tt.exprStr))
+		selection := p.(plannercore.LogicalPlan).Children()[0].(*plannercore.LogicalSelection)
+		tbl := selection.Children()[0].(*plannercore.DataSource).TableInfo()
+		c.Assert(selection, NotNil, Commentf("expr:%v", tt.exprStr))
+		conds := make([]expression.Expression, len(selection.Conditions))
+		for i, cond := range selection.Conditions {
+			conds[i] = expression.PushDownNot(sctx, cond)
+		}
+		cols, lengths := expression.IndexInfo2PrefixCols(tbl.Columns, selection.Schema().Columns, tbl.Indices[tt.indexPos])
+		c.Assert(cols, NotNil)
+		res, err := ranger.DetachCondAndBuildRangeForIndex(sctx, conds, cols, lengths)
+		c.Assert(err, IsNil)
+		c.Assert(fmt.Sprintf("%s", res.AccessConds), Equals, tt.accessConds, Commentf("wrong access conditions for expr: %s", tt.exprStr))
+		c.Assert(fmt.Sprintf("%s", res.RemainedConds), Equals, tt.filterConds, Commentf("wrong filter conditions for expr: %s", tt.exprStr))
+		got := fmt.Sprintf("%v", res.Ranges)
+		c.Assert(got, Equals, tt.resultStr, Commentf("different for expr %s", tt.exprStr))
+	}
+}
+
 func (s *testRangerSuite) TestColumnRange(c *C) {
 	defer testleak.AfterTest(c)()
 	dom, store, err := newDomainStoreWithBootstrap(c)
@@ -16,8 +16,11 @@ package rowcodec
 import (
 	"math"
 	"sort"
+	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/codec"
@@ -183,6 +186,37 @@ func EncodeValueDatum(sc *stmtctx.StatementContext, d types.Datum, buffer []byte
 		buffer = encodeUint(buffer, val)
 	case types.KindFloat32, types.KindFloat64:
 		buffer = codec.EncodeFloat(buffer, d.GetFloat64())
+	case types.KindMysqlTime:
+		// Encoding timestamp need to consider timezone.
+		// If it's not in UTC, transform to UTC first.
+		t := d.GetMysqlTime()
+		if t.Type() == mysql.TypeTimestamp && sc != nil && sc.TimeZone != nil && sc.TimeZone != time.UTC {
+			err = t.ConvertTimeZone(sc.TimeZone, time.UTC)
+			if err != nil {
+				return
+			}
+		}
+		var v uint64
+		v, err = t.ToPackedUint()
+		if err != nil {
+			return
+		}
+		buffer = encodeUint(buffer, v)
+	case types.KindMysqlDuration:
+		buffer = encodeInt(buffer, int64(d.GetMysqlDuration().Duration))
+	case types.KindMysqlDecimal:
+		buffer, err = codec.EncodeDecimal(buffer, d.GetMysqlDecimal(), d.Length(), d.Frac())
+		if err != nil && sc != nil {
+			if terror.ErrorEqual(err, types.ErrTruncated) {
+				err = sc.HandleTruncate(err)
+			} else if terror.ErrorEqual(err, types.ErrOverflow) {
+				err = sc.HandleOverflow(err, err)
+			}
+		}
+	case types.KindMysqlJSON:
+		j := d.GetMysqlJSON()
+		buffer = append(buffer, j.TypeCode)
+		buffer = append(buffer, j.Value...)
 	case types.KindNull:
 	case types.KindMinNotNull:
 	case types.KindMaxValue:
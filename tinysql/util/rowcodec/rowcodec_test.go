@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/rowcodec"
@@ -263,6 +264,14 @@ func (s *testSuite) TestTypesNewRowCodec(c *C) {
 			nil,
 			false,
 		},
+		{
+			4,
+			withFrac(4)(withLen(6)(types.NewFieldType(mysql.TypeNewDecimal))),
+			getDecimalDatum("11.9900", 6, 4),
+			getDecimalDatum("11.9900", 6, 4),
+			nil,
+			false,
+		},
 		{
 			12,
 			types.NewFieldType(mysql.TypeYear),
@@ -311,6 +320,38 @@ func (s *testSuite) TestTypesNewRowCodec(c *C) {
 			nil,
 			false,
 		},
+		{
+			5,
+			withFrac(6)(types.NewFieldType(mysql.TypeDatetime)),
+			types.NewTimeDatum(getTime("2011-11-10 11:11:11.999999", mysql.TypeDatetime)),
+			types.NewUintDatum(getPackedTime(getTime("2011-11-10 11:11:11.999999", mysql.TypeDatetime))),
+			nil,
+			false,
+		},
+		{
+			16,
+			withFrac(6)(types.NewFieldType(mysql.TypeTimestamp)),
+			types.NewTimeDatum(getTime("2011-11-10 11:11:11", mysql.TypeTimestamp)),
+			types.NewUintDatum(getPackedTime(getTime("2011-11-10 11:11:11", mysql.TypeTimestamp))),
+			nil,
+			false,
+		},
+		{
+			8,
+			withFrac(0)(types.NewFieldType(mysql.TypeDuration)),
+			types.NewDurationDatum(getDuration("4:00:00")),
+			types.NewIntDatum(getDuration("4:00:00").Nanoseconds()),
+			nil,
+			false,
+		},
+		{
+			9,
+			types.NewFieldType(mysql.TypeJSON),
+			types.NewJSONDatum(getJSON(`{"a": [1, "b"], "c": null}`)),
+			types.NewJSONDatum(getJSON(`{"a": [1, "b"], "c": null}`)),
+			nil,
+			false,
+		},
 	}
 
 	// test small
@@ -560,6 +601,52 @@ var (
 		ft.Flag = ft.Flag | mysql.UnsignedFlag
 		return ft
 	}
+	withFrac = func(f int) func(ft *types.FieldType) *types.FieldType {
+		return func(ft *types.FieldType) *types.FieldType {
+			ft.Decimal = f
+			return ft
+		}
+	}
+	withLen = func(l int) func(ft *types.FieldType) *types.FieldType {
+		return func(ft *types.FieldType) *types.FieldType {
+			ft.Flen = l
+			return ft
+		}
+	}
+	getDecimalDatum = func(s string, l, f int) types.Datum {
+		d := types.NewDecimalDatum(types.NewDecFromStringForTest(s))
+		d.SetLength(l)
+		d.SetFrac(f)
+		return d
+	}
+	getTime = func(value string, tp byte) types.Time {
+		t, err := types.ParseTime(&stmtctx.StatementContext{TimeZone: time.UTC}, value, tp, 6)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	getPackedTime = func(t types.Time) uint64 {
+		v, err := t.ToPackedUint()
+		if err != nil {
+			panic(err)
+		}
+		return v
+	}
+	getDuration = func(value string) types.Duration {
+		dur, err := types.ParseDuration(nil, value, 0)
+		if err != nil {
+			panic(err)
+		}
+		return dur
+	}
+	getJSON = func(value string) json.BinaryJSON {
+		j, err := json.ParseBinaryFromString(value)
+		if err != nil {
+			panic(err)
+		}
+		return j
+	}
 	getOldDatumByte = func(d types.Datum) []byte {
 		b, err := tablecodec.EncodeValue(nil, nil, d)
 		if err != nil {
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/pingcap/errors"
@@ -272,6 +273,102 @@ func DoMatch(str string, patChars, patTypes []byte) bool {
 	return true
 }
 
+// CompilePatternRunes is like CompilePattern, but works on the characters
+// instead of the bytes of pattern, so that a '_' matches exactly one
+// character of a multi-byte string.
+func CompilePatternRunes(pattern string, escape rune) (patChars []rune, patTypes []byte) {
+	var lastAny bool
+	runes := []rune(pattern)
+	patChars = make([]rune, len(runes))
+	patTypes = make([]byte, len(runes))
+	patLen := 0
+	for i := 0; i < len(runes); i++ {
+		var tp byte
+		var c = runes[i]
+		switch c {
+		case escape:
+			lastAny = false
+			tp = patMatch
+			if i < len(runes)-1 {
+				i++
+				c = runes[i]
+				if c == escape || c == '_' || c == '%' {
+					// Valid escape.
+				} else {
+					// Invalid escape, fall back to escape character, see CompilePattern.
+					i--
+					c = escape
+				}
+			}
+		case '_':
+			if lastAny {
+				continue
+			}
+			tp = patOne
+		case '%':
+			if lastAny {
+				continue
+			}
+			lastAny = true
+			tp = patAny
+		default:
+			lastAny = false
+			tp = patMatch
+		}
+		patChars[patLen] = c
+		patTypes[patLen] = tp
+		patLen++
+	}
+	patChars = patChars[:patLen]
+	patTypes = patTypes[:patLen]
+	return
+}
+
+// DoMatchRunes is like DoMatch, but matches the characters of str with the
+// pattern compiled by CompilePatternRunes. The characters are compared
+// case-insensitively if ci is true.
+func DoMatchRunes(str string, patChars []rune, patTypes []byte, ci bool) bool {
+	runes := []rune(str)
+	var sIdx, pIdx, nextSIdx, nextPIdx int
+	for pIdx < len(patChars) || sIdx < len(runes) {
+		if pIdx < len(patChars) {
+			switch patTypes[pIdx] {
+			case patMatch:
+				if sIdx < len(runes) && matchRune(runes[sIdx], patChars[pIdx], ci) {
+					pIdx++
+					sIdx++
+					continue
+				}
+			case patOne:
+				if sIdx < len(runes) {
+					pIdx++
+					sIdx++
+					continue
+				}
+			case patAny:
+				nextPIdx = pIdx
+				nextSIdx = sIdx + 1
+				pIdx++
+				continue
+			}
+		}
+		if 0 < nextSIdx && nextSIdx <= len(runes) {
+			pIdx = nextPIdx
+			sIdx = nextSIdx
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func matchRune(a, b rune, ci bool) bool {
+	if a == b {
+		return true
+	}
+	return ci && unicode.ToLower(a) == unicode.ToLower(b)
+}
+
 // IsExactMatch return true if no wildcard character
 func IsExactMatch(patTypes []byte) bool {
 	for _, pt := range patTypes {
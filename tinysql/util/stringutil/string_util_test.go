@@ -125,6 +125,33 @@ func (s *testStringUtilSuite) TestPatternMatch(c *C) {
 	}
 }
 
+func (s *testStringUtilSuite) TestPatternMatchRunes(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		pattern string
+		input   string
+		escape  rune
+		ci      bool
+		match   bool
+	}{
+		{`_`, `啊`, '\\', false, true},
+		{`__`, `啊`, '\\', false, false},
+		{`啊_`, `啊啊`, '\\', false, true},
+		{`%啊`, `a啊`, '\\', false, true},
+		{`\_a`, `_a`, '\\', false, true},
+		{`啊%a`, `%a`, '啊', false, true},
+		{`啊%a`, `xa`, '啊', false, false},
+		{`aB%`, `Abc`, '\\', false, false},
+		{`aB%`, `Abc`, '\\', true, true},
+		{`%ä_`, `xÄy`, '\\', true, true},
+	}
+	for _, v := range tbl {
+		patChars, patTypes := CompilePatternRunes(v.pattern, v.escape)
+		match := DoMatchRunes(v.input, patChars, patTypes, v.ci)
+		c.Assert(match, Equals, v.match, Commentf("%v", v))
+	}
+}
+
 func (s *testStringUtilSuite) TestCompileLike2Regexp(c *C) {
 	defer testleak.AfterTest(c)()
 	tbl := []struct {
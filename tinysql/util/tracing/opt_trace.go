@@ -0,0 +1,108 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+)
+
+// OptimizeTracer records the optimization steps of a statement, it is
+// exposed as json in INFORMATION_SCHEMA.OPTIMIZER_TRACE.
+type OptimizeTracer struct {
+	// Logical records the logical optimization rules applied in order.
+	Logical []*LogicalRuleTrace `json:"logical_optimize,omitempty"`
+	// Physical records the candidate physical plans compared by the optimizer.
+	Physical []*CandidatePlanTrace `json:"physical_optimize,omitempty"`
+	// Cascades records the transformation rules applied on the memo groups
+	// by the cascades planner.
+	Cascades []*ExploreTrace `json:"cascades_explore,omitempty"`
+	// FinalPlan is the plan chosen by the optimizer.
+	FinalPlan string `json:"final_plan"`
+}
+
+// LogicalRuleTrace is the trace of a logical optimization rule.
+type LogicalRuleTrace struct {
+	Rule    string `json:"rule"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	Changed bool   `json:"changed"`
+}
+
+// CandidatePlanTrace is the trace of a candidate physical plan of a logical
+// plan under the required physical property.
+type CandidatePlanTrace struct {
+	LogicalPlan string  `json:"logical_plan"`
+	Property    string  `json:"required_property"`
+	Plan        string  `json:"plan"`
+	Cost        float64 `json:"cost"`
+	Chosen      bool    `json:"chosen"`
+}
+
+// ExploreTrace is the trace of a transformation rule applied on an
+// expression of a memo group.
+type ExploreTrace struct {
+	Group   string   `json:"group"`
+	Rule    string   `json:"rule"`
+	Expr    string   `json:"expr"`
+	NewExpr []string `json:"new_exprs"`
+	// EraseOld and EraseAll are set when the rule removes the old
+	// expression or all the other expressions of the group.
+	EraseOld bool `json:"erase_old,omitempty"`
+	EraseAll bool `json:"erase_all,omitempty"`
+}
+
+// AppendLogicalRule appends the trace of a logical optimization rule.
+func (t *OptimizeTracer) AppendLogicalRule(rule, before, after string) {
+	t.Logical = append(t.Logical, &LogicalRuleTrace{
+		Rule:    rule,
+		Before:  before,
+		After:   after,
+		Changed: before != after,
+	})
+}
+
+// AppendCandidatePlan appends the trace of a candidate physical plan and
+// returns it, so the caller can mark it as chosen later.
+func (t *OptimizeTracer) AppendCandidatePlan(logicalPlan, prop, plan string, cost float64) *CandidatePlanTrace {
+	candidate := &CandidatePlanTrace{
+		LogicalPlan: logicalPlan,
+		Property:    prop,
+		Plan:        plan,
+		Cost:        cost,
+	}
+	t.Physical = append(t.Physical, candidate)
+	return candidate
+}
+
+// AppendExplore appends the trace of a transformation rule.
+func (t *OptimizeTracer) AppendExplore(trace *ExploreTrace) {
+	t.Cascades = append(t.Cascades, trace)
+}
+
+// JSON encodes the trace to json, the result is indented unless oneLine is set.
+func (t *OptimizeTracer) JSON(oneLine bool) (string, error) {
+	var data []byte
+	var err error
+	if oneLine {
+		data, err = json.Marshal(t)
+	} else {
+		data, err = json.MarshalIndent(t, "", "  ")
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}